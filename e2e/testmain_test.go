@@ -18,6 +18,7 @@ package e2e
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
@@ -108,8 +109,8 @@ func TestMain(m *testing.M) {
 func checkErrCode(t *testing.T, err error, wantCode int, fmtStr string, args ...interface{}) {
 	t.Helper()
 
-	gerr, ok := err.(*googleapi.Error)
-	if !ok {
+	var gerr *googleapi.Error
+	if !errors.As(err, &gerr) {
 		t.Fatalf("%s: invalid error type, want *googleapi.Error, got %T", fmt.Sprintf(fmtStr, args...), err)
 	}
 	if gerr.Code != wantCode {
@@ -76,7 +76,7 @@ func TestCopyBasic(t *testing.T) {
 		{name: "float64", dest: v(&ve).Elem(), src: v(float64(13)), want: float64(13)},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			err := testCopier(t).doBasic(Path{}, tc.dest, tc.src)
+			err := testCopier(t).do(tc.dest, tc.src)
 			if err != nil {
 				t.Fatalf("copyBasic() = %v, want nil", err)
 			}
@@ -158,7 +158,7 @@ func TestCopyPointer(t *testing.T) {
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			err := testCopier(t).doPointer(Path{}, tc.dest, tc.src)
+			err := testCopier(t).do(tc.dest, tc.src)
 			gotErr := err != nil
 			if gotErr != tc.wantErr {
 				t.Fatalf("copy() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
@@ -235,7 +235,7 @@ func TestCopySlice(t *testing.T) {
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			err := testCopier(t).doSlice(Path{}, tc.dest, tc.src)
+			err := testCopier(t).do(tc.dest, tc.src)
 			gotErr := err != nil
 			if gotErr != tc.wantErr {
 				t.Fatalf("copy() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
@@ -347,7 +347,7 @@ func TestCopyStruct(t *testing.T) {
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			cc := testCopier(t)
-			err := cc.doStruct(Path{}, tc.dest, tc.src)
+			err := cc.do(tc.dest, tc.src)
 			gotErr := err != nil
 			if gotErr != tc.wantErr {
 				t.Fatalf("copy() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
@@ -447,7 +447,7 @@ func TestCopyMap(t *testing.T) {
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			cc := testCopier(t)
-			err := cc.doMap(Path{}, tc.dest, tc.src)
+			err := cc.do(tc.dest, tc.src)
 			gotErr := err != nil
 			if gotErr != tc.wantErr {
 				t.Fatalf("copyMap() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
@@ -536,11 +536,9 @@ func TestCopyMetaFields(t *testing.T) {
 		t.Run(tc.name, func(*testing.T) {
 			srcV := reflect.ValueOf(&tc.src).Elem()
 			destV := reflect.ValueOf(&tc.dest).Elem()
-			srcFieldV := srcV.FieldByName(tc.fn)
-			destFieldV := destV.FieldByName(tc.fn)
 
 			cc := testCopier(t)
-			err := cc.doMetaFields(Path{}, destFieldV, srcFieldV, destV, srcV)
+			err := cc.do(destV, srcV)
 			gotErr := err != nil
 			if gotErr != tc.wantErr {
 				t.Fatalf("copyMetaFields() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"reflect"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/internal/objutil"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 )
 
@@ -156,7 +157,7 @@ const (
 // given type.
 func (dt *FieldTraits) CheckSchema(t reflect.Type) error {
 	for _, f := range dt.fields {
-		if f.path[len(f.path)-1][0] != pathField {
+		if f.path[len(f.path)-1][0] != objutil.PathField {
 			return fmt.Errorf("CheckSchema: path %s is not a field reference", f.path)
 		}
 		_, err := f.path.ResolveType(t)
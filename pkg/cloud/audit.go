@@ -0,0 +1,181 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// Caller identifies the component on whose behalf calls are made.
+type Caller struct {
+	// Name of the component, e.g. the controller name.
+	Name string
+	// Key of the unit of work, e.g. the reconcile key "namespace/name".
+	Key string
+	// ReconcileID identifies a single pass of the unit of work, e.g. a
+	// random ID generated at the start of each reconcile. This may be
+	// empty.
+	ReconcileID string
+}
+
+var callerContextKey = contextKey("caller")
+
+// WithCaller returns a context that attributes the calls made with it to
+// caller in the AuditRecords.
+//
+//	ctx = cloud.WithCaller(ctx, cloud.Caller{Name: "l4-controller", Key: req.NamespacedName.String()})
+//	g.ForwardingRules().Delete(ctx, key)
+func WithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, callerContextKey, caller)
+}
+
+// CallerFromContext returns the Caller set with WithCaller.
+func CallerFromContext(ctx context.Context) (Caller, bool) {
+	c, ok := ctx.Value(callerContextKey).(Caller)
+	return c, ok
+}
+
+// AuditRecord is the record of a call that mutates a resource.
+type AuditRecord struct {
+	// ResourceID of the resource that was mutated. Key is nil if the
+	// call is not on a single resource.
+	ResourceID *ResourceID
+	// Operation is the verb, e.g. "Delete".
+	Operation string
+	// Version of the API.
+	Version meta.Version
+	// RequestID sent with the call. This is empty if the method does not
+	// take a requestId.
+	RequestID string
+	// Caller from the context of the call. This is the zero value if the
+	// context has no Caller.
+	Caller Caller
+	// Time the call started.
+	Time time.Time
+	// Err is the outcome of the call. For calls that return a long
+	// running operation, this is the result of the operation.
+	Err error
+}
+
+// Auditor is called for each mutation (Insert, Update, Patch, Delete and the
+// other methods that change a resource).
+type Auditor interface {
+	Audit(ctx context.Context, r *AuditRecord)
+}
+
+// IsMutation returns true if the operation op (e.g. "Insert") changes the
+// resource. The read-only methods are Get*, List*, Test*, Preview and
+// AggregatedList.
+func IsMutation(op string) bool {
+	for _, prefix := range []string{"Get", "List", "Test"} {
+		if strings.HasPrefix(op, prefix) {
+			return false
+		}
+	}
+	return op != "Preview" && op != "AggregatedList"
+}
+
+var (
+	resourceNamesOnce sync.Once
+	resourceNames     map[string]string
+)
+
+// resourceName returns the resource in the URL (e.g. "forwardingRules") of
+// the service (e.g. "GlobalForwardingRules").
+func resourceName(service string) string {
+	resourceNamesOnce.Do(func() {
+		resourceNames = map[string]string{}
+		for _, s := range meta.AllServices {
+			resourceNames[s.Service] = s.Resource
+		}
+	})
+	if r, ok := resourceNames[service]; ok {
+		return r
+	}
+	if service == "" {
+		return ""
+	}
+	return strings.ToLower(service[:1]) + service[1:]
+}
+
+// serviceName returns the service (e.g. "RegionBackendServices") of the
+// resource in the URL (e.g. "backendServices") with the scope of key. It
+// returns "" if there is none.
+func serviceName(resource string, key *meta.Key) string {
+	for _, s := range meta.AllServices {
+		if s.Resource != resource {
+			continue
+		}
+		if (key.Type() == meta.Global && s.KeyIsGlobal()) ||
+			(key.Type() == meta.Regional && s.KeyIsRegional()) ||
+			(key.Type() == meta.Zonal && s.KeyIsZonal()) {
+			return s.Service
+		}
+	}
+	return ""
+}
+
+// audit the call in state and publish its Event if it has not been done
+// yet.
+func (state *callState) audit(ctx context.Context, err error) {
+	if state.audited || !IsMutation(state.ck.Operation) {
+		return
+	}
+	state.audited = true
+	id := &ResourceID{
+		ProjectID: state.ck.ProjectID,
+		Resource:  resourceName(state.ck.Service),
+		Key:       state.key,
+	}
+	if state.auditor != nil {
+		caller, _ := CallerFromContext(ctx)
+		state.auditor.Audit(ctx, &AuditRecord{
+			ResourceID: id,
+			Operation:  state.ck.Operation,
+			Version:    state.ck.Version,
+			RequestID:  state.requestID,
+			Caller:     caller,
+			Time:       state.start,
+			Err:        err,
+		})
+	}
+	if state.events != nil && err == nil {
+		state.events.Publish(ctx, &Event{
+			Type:       eventType(state.ck.Operation),
+			ResourceID: id,
+			Service:    state.ck.Service,
+			Operation:  state.ck.Operation,
+			Version:    state.ck.Version,
+			Time:       time.Now(),
+		})
+	}
+}
+
+// callOperationDone stops the Watchdog, audits the call in ctx and reports
+// its diagnostics with the result of its long running operation.
+func callOperationDone(ctx context.Context, err error) {
+	if state, ok := ctx.Value(callStateContextKey).(*callState); ok {
+		state.watch.stop()
+		state.audit(ctx, err)
+		state.diag.end(ctx, state, err)
+	}
+}
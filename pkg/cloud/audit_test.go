@@ -0,0 +1,154 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+)
+
+type fakeAuditor struct {
+	lock    sync.Mutex
+	records []*AuditRecord
+}
+
+func (a *fakeAuditor) Audit(ctx context.Context, r *AuditRecord) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.records = append(a.records, r)
+}
+
+func TestIsMutation(t *testing.T) {
+	t.Parallel()
+
+	for op, want := range map[string]bool{
+		"Insert":             true,
+		"Delete":             true,
+		"Patch":              true,
+		"Update":             true,
+		"SetLabels":          true,
+		"SetIamPolicy":       true,
+		"Get":                false,
+		"GetHealth":          false,
+		"List":               false,
+		"ListUsable":         false,
+		"AggregatedList":     false,
+		"TestIamPermissions": false,
+		"Preview":            false,
+	} {
+		if got := IsMutation(op); got != want {
+			t.Errorf("IsMutation(%q) = %t, want %t", op, got, want)
+		}
+	}
+}
+
+func TestResourceName(t *testing.T) {
+	t.Parallel()
+
+	for service, want := range map[string]string{
+		"GlobalForwardingRules": "forwardingRules",
+		"RegionBackendServices": "backendServices",
+		"Meshes":                "meshes",
+	} {
+		if got := resourceName(service); got != want {
+			t.Errorf("resourceName(%q) = %q, want %q", service, got, want)
+		}
+	}
+}
+
+func TestAuditor(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/proj/global/forwardingRules/fr", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "fr"})
+	})
+	mux.HandleFunc("/projects/proj/global/forwardingRules", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":     "op-1",
+			"status":   "RUNNING",
+			"selfLink": "https://www.googleapis.com/compute/v1/projects/proj/global/operations/op-1",
+		})
+	})
+	mux.HandleFunc("/projects/proj/global/operations/op-1/wait", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":   "op-1",
+			"status": "DONE",
+			"error":  map[string]interface{}{"errors": []interface{}{map[string]interface{}{"code": "QUOTA_EXCEEDED"}}},
+		})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+	auditor := &fakeAuditor{}
+	c := NewGCE(&Service{
+		GA:            client,
+		ProjectRouter: &SingleProjectRouter{"proj"},
+		RateLimiter:   &NopRateLimiter{},
+		Auditor:       auditor,
+	})
+	key := meta.GlobalKey("fr")
+	caller := Caller{Name: "l7-controller", Key: "default/ing"}
+	ctx = WithCaller(ctx, caller)
+
+	if _, err := c.GlobalForwardingRules().Get(ctx, key); err != nil {
+		t.Fatalf("Get(%v) = _, %v, want nil", key, err)
+	}
+	if len(auditor.records) != 0 {
+		t.Fatalf("got %d records after Get(), want 0", len(auditor.records))
+	}
+
+	// The outcome of Insert is the error of the operation.
+	if err := c.GlobalForwardingRules().Insert(ctx, key, &ga.ForwardingRule{}); err == nil {
+		t.Fatalf("Insert(%v) = nil, want error", key)
+	}
+	var gerr *googleapi.Error
+	if err := c.GlobalForwardingRules().Delete(ctx, key); !errors.As(err, &gerr) || gerr.Code != http.StatusNotFound {
+		t.Fatalf("Delete(%v) = %v, want 404", key, err)
+	}
+
+	if len(auditor.records) != 2 {
+		t.Fatalf("got %d records, want 2", len(auditor.records))
+	}
+	for i, want := range []string{"Insert", "Delete"} {
+		r := auditor.records[i]
+		wantID := &ResourceID{ProjectID: "proj", Resource: "forwardingRules", Key: key}
+		if r.Operation != want || !r.ResourceID.Equal(wantID) || r.Caller != caller || r.Version != meta.VersionGA || r.Err == nil || r.Time.IsZero() {
+			t.Errorf("records[%d] = %+v, want %s of %v by %v with an error", i, r, want, wantID, caller)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// KeyError is the error of the call on one resource of a batch.
+type KeyError struct {
+	// Key of the resource.
+	Key *meta.Key
+	// Err returned by the call.
+	Err error
+}
+
+// Error returns the key followed by Err.
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Key, e.Err)
+}
+
+// Unwrap returns Err.
+func (e *KeyError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError is the error of a batch of calls of which some failed, e.g.
+// the calls of ForEachKey. It has the error of each resource, so that the
+// caller can retry the ones that failed only:
+//
+//	err := cloud.ForEachKey(ctx, keys, 10, func(ctx context.Context, key *meta.Key) error {
+//		return gce.NetworkEndpointGroups().Delete(ctx, key)
+//	})
+//	var berr *cloud.BatchError
+//	if errors.As(err, &berr) {
+//		retry := berr.Keys(gcerrors.IsServerError)
+//		...
+//	}
+//
+// errors.Is and errors.As (and the predicates of package gcerrors) are
+// true for a BatchError if they are for the error of one of the
+// resources.
+type BatchError struct {
+	// Errors of the resources that failed, in the order of the batch.
+	Errors []*KeyError
+	// Total is the number of calls of the batch.
+	Total int
+}
+
+// Error returns the number of failed calls and their errors, e.g. "2 of 5
+// calls failed: Key{"a"}: ...; Key{"b"}: ...".
+func (e *BatchError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, ke := range e.Errors {
+		msgs[i] = ke.Error()
+	}
+	return fmt.Sprintf("%d of %d calls failed: %s", len(e.Errors), e.Total, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors of the resources.
+func (e *BatchError) Unwrap() []error {
+	ret := make([]error, len(e.Errors))
+	for i, ke := range e.Errors {
+		ret[i] = ke
+	}
+	return ret
+}
+
+// Keys returns the keys of the resources with an error for which match is
+// true, e.g. gcerrors.IsServerError. A nil match returns the keys of all
+// the resources that failed.
+func (e *BatchError) Keys(match func(error) bool) []*meta.Key {
+	var ret []*meta.Key
+	for _, ke := range e.Errors {
+		if match == nil || match(ke.Err) {
+			ret = append(ret, ke.Key)
+		}
+	}
+	return ret
+}
+
+// Err returns the error of the resource key, or nil if its call did not
+// fail. Keys are compared with Key.Equal, so the location may be given in
+// any form (e.g. a zone URL).
+func (e *BatchError) Err(key *meta.Key) error {
+	for _, ke := range e.Errors {
+		if ke.Key.Equal(key) {
+			return ke.Err
+		}
+	}
+	return nil
+}
+
+// ForEachKey calls f for each of keys, with at most parallelism calls at a
+// time (no limit if parallelism is not positive). It returns a *BatchError
+// with the errors of the keys for which f failed, or nil if none did.
+func ForEachKey(ctx context.Context, keys []*meta.Key, parallelism int, f func(context.Context, *meta.Key) error) error {
+	errs := make([]error, len(keys))
+	fanOut(len(keys), parallelism, func(i int) {
+		errs[i] = f(ctx, keys[i])
+	})
+
+	berr := &BatchError{Total: len(keys)}
+	for i, err := range errs {
+		if err != nil {
+			berr.Errors = append(berr.Errors, &KeyError{Key: keys[i], Err: err})
+		}
+	}
+	if len(berr.Errors) == 0 {
+		return nil
+	}
+	return berr
+}
+
+// ListScopes calls list for each of scopes (e.g. the zones of the
+// Instances), with at most parallelism calls at a time (no limit if
+// parallelism is not positive), and returns the objects of all the scopes
+// in the order of scopes. This is faster than listing the scopes one after
+// the other for the resources without an AggregatedList, or to list a
+// subset of the scopes:
+//
+//	instances, err := cloud.ListScopes(ctx, zones, 8, func(ctx context.Context, zone string) ([]*ga.Instance, error) {
+//		return gce.Instances().List(ctx, zone, filter.None)
+//	})
+//
+// The error has the error of each scope that failed, joined with
+// errors.Join; the objects of the other scopes are returned.
+func ListScopes[T any](ctx context.Context, scopes []string, parallelism int, list func(context.Context, string) ([]T, error)) ([]T, error) {
+	results := make([][]T, len(scopes))
+	errs := make([]error, len(scopes))
+	fanOut(len(scopes), parallelism, func(i int) {
+		results[i], errs[i] = list(ctx, scopes[i])
+	})
+
+	var ret []T
+	for i, objs := range results {
+		if errs[i] != nil {
+			errs[i] = fmt.Errorf("%s: %w", scopes[i], errs[i])
+		}
+		ret = append(ret, objs...)
+	}
+	return ret, errors.Join(errs...)
+}
+
+// fanOut calls f for 0 to n-1, with at most parallelism calls at a time
+// (no limit if parallelism is not positive), and waits for them to return.
+func fanOut(n, parallelism int, f func(i int)) {
+	if parallelism <= 0 || parallelism > n {
+		parallelism = n
+	}
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		i := i
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			f(i)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/gcerrors"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+func TestForEachKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"proj"})
+	var keys []*meta.Key
+	for _, name := range []string{"a", "b", "c", "d"} {
+		keys = append(keys, meta.GlobalKey(name))
+	}
+	for _, key := range []*meta.Key{keys[0], keys[2]} {
+		if err := mock.HealthChecks().Insert(ctx, key, &ga.HealthCheck{}); err != nil {
+			t.Fatalf("Insert(%v) = %v, want nil", key, err)
+		}
+	}
+
+	var (
+		lock          sync.Mutex
+		running, peak int
+	)
+	err := ForEachKey(ctx, keys, 2, func(ctx context.Context, key *meta.Key) error {
+		lock.Lock()
+		running++
+		if running > peak {
+			peak = running
+		}
+		lock.Unlock()
+		defer func() {
+			lock.Lock()
+			running--
+			lock.Unlock()
+		}()
+		return mock.HealthChecks().Delete(ctx, key)
+	})
+	if peak > 2 {
+		t.Errorf("got %d calls at a time, want at most 2", peak)
+	}
+
+	var berr *BatchError
+	if !errors.As(err, &berr) {
+		t.Fatalf("ForEachKey() = %v (%T), want a *BatchError", err, err)
+	}
+	if berr.Total != 4 || len(berr.Errors) != 2 {
+		t.Errorf("BatchError = %v, want 2 of 4 calls failed", berr)
+	}
+	if diff := cmp.Diff([]*meta.Key{keys[1], keys[3]}, berr.Keys(gcerrors.IsNotFound)); diff != "" {
+		t.Errorf("Keys(IsNotFound): -want +got: %s", diff)
+	}
+	if got := berr.Keys(gcerrors.IsServerError); len(got) != 0 {
+		t.Errorf("Keys(IsServerError) = %v, want none", got)
+	}
+	if !gcerrors.IsNotFound(berr.Err(meta.GlobalKey("b"))) || berr.Err(meta.GlobalKey("a")) != nil {
+		t.Errorf("Err(b), Err(a) = %v, %v, want a 404, nil", berr.Err(meta.GlobalKey("b")), berr.Err(meta.GlobalKey("a")))
+	}
+	if !gcerrors.IsNotFound(err) {
+		t.Errorf("gcerrors.IsNotFound(%v) = false, want true", err)
+	}
+
+	// A batch without errors returns nil.
+	if err := ForEachKey(ctx, []*meta.Key{keys[0], keys[2]}, 0, func(ctx context.Context, key *meta.Key) error {
+		return mock.HealthChecks().Insert(ctx, key, &ga.HealthCheck{})
+	}); err != nil {
+		t.Errorf("ForEachKey() = %v, want nil", err)
+	}
+	if err := ForEachKey(ctx, nil, 2, nil); err != nil {
+		t.Errorf("ForEachKey(nil) = %v, want nil", err)
+	}
+}
+
+func TestBatchErrorErrNormalizesKeys(t *testing.T) {
+	t.Parallel()
+
+	errNotFound := &googleapi.Error{Code: http.StatusNotFound}
+	berr := &BatchError{
+		Total:  2,
+		Errors: []*KeyError{{Key: meta.ZonalKey("neg", "us-central1-a"), Err: errNotFound}},
+	}
+	for _, key := range []*meta.Key{
+		meta.ZonalKey("neg", "us-central1-a"),
+		meta.ZonalKey("neg", "https://www.googleapis.com/compute/v1/projects/p/zones/us-central1-a"),
+		meta.ZonalKey("neg", "zones/US-Central1-A"),
+	} {
+		if got := berr.Err(key); got != errNotFound {
+			t.Errorf("Err(%v) = %v, want %v", key, got, errNotFound)
+		}
+	}
+	if got := berr.Err(meta.ZonalKey("neg", "us-central1-b")); got != nil {
+		t.Errorf("Err(other zone) = %v, want nil", got)
+	}
+}
+
+func TestListScopes(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"proj"})
+	zones := []string{"us-central1-a", "us-central1-b", "us-central1-c", "europe-west1-b"}
+	for _, zone := range zones {
+		if err := mock.Instances().Insert(ctx, meta.ZonalKey("vm-"+zone, zone), &ga.Instance{}); err != nil {
+			t.Fatalf("Insert() = %v, want nil", err)
+		}
+	}
+
+	var (
+		lock          sync.Mutex
+		running, peak int
+	)
+	list := func(ctx context.Context, zone string) ([]*ga.Instance, error) {
+		lock.Lock()
+		running++
+		if running > peak {
+			peak = running
+		}
+		lock.Unlock()
+		defer func() {
+			lock.Lock()
+			running--
+			lock.Unlock()
+		}()
+		if zone == "europe-west1-b" {
+			return nil, errors.New("injected error")
+		}
+		return mock.Instances().List(ctx, zone, filter.None)
+	}
+	got, err := ListScopes(ctx, zones, 2, list)
+	if peak > 2 {
+		t.Errorf("got %d calls at a time, want at most 2", peak)
+	}
+	if err == nil || !strings.Contains(err.Error(), "europe-west1-b: injected error") {
+		t.Errorf("ListScopes() = _, %v, want the error of europe-west1-b", err)
+	}
+	var names []string
+	for _, vm := range got {
+		names = append(names, vm.Name)
+	}
+	want := []string{"vm-us-central1-a", "vm-us-central1-b", "vm-us-central1-c"}
+	if diff := cmp.Diff(want, names); diff != "" {
+		t.Errorf("ListScopes(): -want +got: %s", diff)
+	}
+
+	got, err = ListScopes(ctx, zones[:3], 0, list)
+	if err != nil || len(got) != 3 {
+		t.Errorf("ListScopes() = %d objects, %v, want 3, nil", len(got), err)
+	}
+}
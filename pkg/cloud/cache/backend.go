@@ -0,0 +1,172 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// Key of an Item in a Backend.
+type Key struct {
+	Project string
+	Version meta.Version
+	// Resource is the Service of the cloud.CallContextKey, e.g.
+	// "BackendServices".
+	Resource string
+	// Object is the key of the object of a Get.
+	Object meta.Key
+	// List is true for the results of List and AggregatedList in Scope
+	// with Filter. Scope is the region or zone of the List, "" for
+	// global resources and "*" for AggregatedList.
+	List   bool
+	Scope  string
+	Filter string
+}
+
+// String returns a unique representation of k, for Backends that need a
+// string key.
+func (k Key) String() string {
+	if k.List {
+		return fmt.Sprintf("%s/%s/%s/list/%s/%q", k.Project, k.Version, k.Resource, k.Scope, k.Filter)
+	}
+	return fmt.Sprintf("%s/%s/%s/%s", k.Project, k.Version, k.Resource, k.Object.String())
+}
+
+// Item is a value in a Backend.
+type Item struct {
+	// Value is an API object (e.g. *ga.BackendService) for a Get, a slice
+	// of objects for a List and a map of slices for an AggregatedList.
+	// The Cache copies the Values it puts and gets, so a Backend does not
+	// need to.
+	Value interface{}
+	// Err is the not found error of a Get. Value is nil if Err is set.
+	Err     error
+	Created time.Time
+	Expires time.Time
+}
+
+// Backend stores the Items of a Cache. It must be safe for concurrent
+// use.
+//
+// The default is an in-memory Backend per Cache. A Backend shared by a
+// fleet of controllers (e.g. on Redis) lets them share the reads of a
+// project. Such a Backend must encode the Values, e.g. with encoding/gob
+// after registering the types of the cached resources.
+type Backend interface {
+	// Get the Item of k. A Backend may return expired Items, which are
+	// not served by the Cache.
+	Get(ctx context.Context, k Key) (*Item, bool)
+	// Put item at k. The Item may be dropped after ttl.
+	Put(ctx context.Context, k Key, item *Item, ttl time.Duration)
+	// Invalidate removes the Items of k.Project and k.Resource in all
+	// API versions: the object k.Object if k.List is false, the Lists
+	// of k.Scope with any Filter otherwise. It returns the number of
+	// Items removed, 0 if not known.
+	Invalidate(ctx context.Context, k Key) int
+}
+
+// Enumerator is implemented by the Backends that can enumerate their
+// Items. This is needed for Dump, Clear and the number of entries in the
+// Stats of the Cache.
+type Enumerator interface {
+	// Range calls f for each Item until f returns false.
+	Range(f func(k Key, item *Item) bool)
+	// Clear removes all the Items.
+	Clear()
+}
+
+// Memory is the in-memory Backend.
+type Memory struct {
+	lock  sync.Mutex
+	items map[Key]*Item
+}
+
+// Memory implements Backend and Enumerator.
+var (
+	_ Backend    = (*Memory)(nil)
+	_ Enumerator = (*Memory)(nil)
+)
+
+// NewMemory returns an empty Memory Backend.
+func NewMemory() *Memory {
+	return &Memory{items: map[Key]*Item{}}
+}
+
+// Get implements Backend.
+func (m *Memory) Get(ctx context.Context, k Key) (*Item, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	item, ok := m.items[k]
+	return item, ok
+}
+
+// Put implements Backend. Expired Items are kept until they are
+// replaced or invalidated.
+func (m *Memory) Put(ctx context.Context, k Key, item *Item, ttl time.Duration) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.items[k] = item
+}
+
+// Invalidate implements Backend.
+func (m *Memory) Invalidate(ctx context.Context, k Key) int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	var n int
+	for ik := range m.items {
+		if ik.Project != k.Project || ik.Resource != k.Resource || ik.List != k.List {
+			continue
+		}
+		if (!k.List && ik.Object == k.Object) || (k.List && ik.Scope == k.Scope) {
+			delete(m.items, ik)
+			n++
+		}
+	}
+	return n
+}
+
+// Range implements Enumerator.
+func (m *Memory) Range(f func(k Key, item *Item) bool) {
+	m.lock.Lock()
+	items := make(map[Key]*Item, len(m.items))
+	for k, item := range m.items {
+		items[k] = item
+	}
+	m.lock.Unlock()
+
+	for k, item := range items {
+		if !f(k, item) {
+			return
+		}
+	}
+}
+
+// Clear implements Enumerator.
+func (m *Memory) Clear() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.items = map[Key]*Item{}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+)
+
+// sharedBackend is a Backend with string keys that is not an Enumerator,
+// like a remote store.
+type sharedBackend struct {
+	lock  sync.Mutex
+	items map[string]*Item
+	keys  map[string]Key
+}
+
+func (b *sharedBackend) Get(ctx context.Context, k Key) (*Item, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	item, ok := b.items[k.String()]
+	return item, ok
+}
+
+func (b *sharedBackend) Put(ctx context.Context, k Key, item *Item, ttl time.Duration) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.items[k.String()] = item
+	b.keys[k.String()] = k
+}
+
+func (b *sharedBackend) Invalidate(ctx context.Context, k Key) int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for s, ik := range b.keys {
+		if ik.Project == k.Project && ik.Resource == k.Resource && ik.List == k.List &&
+			((!k.List && ik.Object == k.Object) || (k.List && ik.Scope == k.Scope)) {
+			delete(b.items, s)
+			delete(b.keys, s)
+		}
+	}
+	return 0
+}
+
+func TestCacheSharedBackend(t *testing.T) {
+	t.Parallel()
+
+	backend := &sharedBackend{items: map[string]*Item{}, keys: map[string]Key{}}
+	config := Config{Default: Policy{TTL: time.Minute}, ListMaxStaleness: time.Minute, Backend: backend}
+	c1, c2 := New(config), New(config)
+
+	ctx := context.Background()
+	key := meta.GlobalKey("bs")
+	api := &fakeAPI{obj: &ga.BackendService{Name: "bs"}}
+	get := func(c *Cache) {
+		t.Helper()
+		if _, err := c.Intercept(ctx, callInfo("BackendServices", "Get", meta.VersionGA, key), api.handler); err != nil {
+			t.Fatalf("Get() = _, %v, want nil", err)
+		}
+	}
+
+	// The object read by c1 is served to c2.
+	get(c1)
+	get(c2)
+	if api.calls != 1 {
+		t.Fatalf("calls = %d, want 1", api.calls)
+	}
+	// A mutation made through c2 invalidates the object for c1.
+	c2.Intercept(ctx, callInfo("BackendServices", "Update", meta.VersionGA, key), api.handler)
+	get(c1)
+	if api.calls != 3 {
+		t.Fatalf("calls = %d, want 3", api.calls)
+	}
+
+	// Lists are invalidated in the scope of the mutated resource.
+	info := callInfo("BackendServices", "List", meta.VersionGA, nil)
+	info.Args = []interface{}{filter.None}
+	c1.Intercept(ctx, info, api.handler)
+	c2.Intercept(ctx, info, api.handler)
+	c1.Invalidate("proj", "BackendServices", key)
+	c2.Intercept(ctx, info, api.handler)
+	if api.calls != 5 {
+		t.Fatalf("calls = %d, want 5", api.calls)
+	}
+
+	// The entries are not known.
+	if got := c1.Dump(); got != nil {
+		t.Errorf("Dump() = %v, want nil", got)
+	}
+	if got := c2.Stats(); got.Objects["BackendServices"].Hits != 1 || got.ObjectEntries != 0 {
+		t.Errorf("Stats() = %+v, want 1 hit and no entries", got)
+	}
+}
+
+func TestKeyString(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		k    Key
+		want string
+	}{
+		{
+			k:    Key{Project: "proj", Version: meta.VersionGA, Resource: "BackendServices", Object: *meta.RegionalKey("bs", "us-central1")},
+			want: "proj/ga/BackendServices/Key{\"bs\", region: \"us-central1\"}",
+		},
+		{
+			k:    Key{Project: "proj", Version: meta.VersionBeta, Resource: "Addresses", List: true, Scope: "*", Filter: "name eq a"},
+			want: "proj/beta/Addresses/list/*/\"name eq a\"",
+		},
+	} {
+		if got := tc.k.String(); got != tc.want {
+			t.Errorf("%+v.String() = %q, want %q", tc.k, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,346 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cache serves reads made through a cloud.Service from a cache.
+//
+// The Cache is a cloud.Interceptor, so it applies to every call made with
+// the Service without changing the callers:
+//
+//	c := cache.New(cache.Config{
+//		Default: cache.Policy{TTL: 30 * time.Second},
+//		Policies: map[string]cache.Policy{
+//			"Zones":     {TTL: cache.Forever},
+//			"Instances": {TTL: 30 * time.Second, MaxEntries: 1000},
+//			"Disks":     {Disabled: true},
+//		},
+//	})
+//	svc := &cloud.Service{
+//		...
+//		Interceptors: []cloud.Interceptor{c.Intercept},
+//	}
+//	gce := cloud.NewGCE(svc)
+//
+// Objects are cached by project, API version, resource and key, and List
+// results by project, API version, resource, scope and filter. The objects
+// returned are copies, so callers may modify them. A mutation made through
+// the Service invalidates the resource in all versions and the Lists that
+// may contain it. With Config.Events, the Cache also invalidates the
+// resources of the Events of the cloud.EventBus, e.g. the mutations made
+// through other Services sharing the EventBus:
+//
+//	bus := cloud.NewEventBus()
+//	c := cache.New(cache.Config{..., Events: bus})
+//	defer c.Close()
+//	svc.Events = bus
+//
+// Mutations made by other clients are seen when the entry expires. Expired
+// objects with an ETag are revalidated with If-None-Match. With
+// Config.StaleWhileRevalidate, expired objects are served while they are
+// refreshed in the background. An offline Cache serves all the calls from
+// the cached objects (see SetOffline).
+//
+// The objects are kept in memory by default. Config.Backend plugs in
+// another store, e.g. one shared by the controllers of a project.
+//
+// Stats returns the hit ratio and the age of the entries served by
+// resource, for tuning the TTLs. The Cache is also an http.Handler serving
+// the Stats and, with ?entries, the cached entries:
+//
+//	http.Handle("/debug/cache", c)
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/gcerrors"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// Config of a Cache.
+type Config struct {
+	// Default is the Policy of the resources not in Policies.
+	Default Policy
+	// Policies by resource, e.g. "BackendServices". This is the Service
+	// of the cloud.CallContextKey and is the same in all API versions.
+	Policies map[string]Policy
+	// ListMaxStaleness is how long the results of List and
+	// AggregatedList are cached. A mutation of a resource invalidates
+	// the results of the Lists of its type in its scope. Zero disables
+	// caching of Lists.
+	ListMaxStaleness time.Duration
+	// NotFoundTTL is how long a Get that returned a not found error is
+	// cached, for all resources. An Insert made through the Service
+	// invalidates the entry. Zero disables the caching of not found
+	// errors.
+	NotFoundTTL time.Duration
+	// Backend stores the cached objects. The default is a new Memory
+	// Backend.
+	Backend Backend
+	// StaleWhileRevalidate is how long an expired object is still served
+	// by Get. The object is then refreshed in the background, so the
+	// latency of Get is bounded while the object is at most TTL +
+	// StaleWhileRevalidate old. Zero disables serving stale objects.
+	StaleWhileRevalidate time.Duration
+	// OnRefresh is called when the refresh of a stale object served by
+	// Get returns a different object, with the stale and the fresh
+	// object. fresh is nil if the object was deleted. The objects must
+	// not be modified.
+	OnRefresh func(k Key, stale, fresh interface{})
+	// OnRejectedMutation is called with the mutations rejected by an
+	// offline Cache, e.g. to report what would have changed. See
+	// SetOffline.
+	OnRejectedMutation func(ctx context.Context, info *cloud.CallInfo)
+	// WarmParallelism is the number of scopes listed at a time by Warm for
+	// the resources without an AggregatedList. The default is
+	// DefaultWarmParallelism.
+	WarmParallelism int
+	// Events, if set, is subscribed to by New: the resources of its
+	// Events are invalidated, e.g. the mutations made through other
+	// Services and the operations that complete outside of a call. Close
+	// removes the subscription.
+	Events *cloud.EventBus
+}
+
+// Cache of the objects returned by Get.
+type Cache struct {
+	config  Config
+	backend Backend
+	// now is replaced in unit tests.
+	now func() time.Time
+
+	offline atomic.Bool
+
+	lock  sync.Mutex
+	stats Stats
+	// fifos of the resources with a MaxEntries.
+	fifos map[string]*fifo
+	// refreshing are the objects refreshed in the background.
+	refreshing map[Key]bool
+	// refreshes is waited on by unit tests.
+	refreshes sync.WaitGroup
+	// unsubscribe removes the subscription to Config.Events.
+	unsubscribe func()
+}
+
+// New returns an empty Cache.
+func New(config Config) *Cache {
+	c := &Cache{
+		config:  config,
+		backend: config.Backend,
+		now:     time.Now,
+		stats:   newStats(),
+
+		fifos:      map[string]*fifo{},
+		refreshing: map[Key]bool{},
+	}
+	if c.backend == nil {
+		c.backend = NewMemory()
+	}
+	if config.Events != nil {
+		c.unsubscribe = config.Events.Subscribe(c.OnEvent)
+	}
+	return c
+}
+
+// Close removes the subscription to Config.Events. The Cache is still
+// usable, but is no longer invalidated by the Events.
+func (c *Cache) Close() {
+	if c.unsubscribe != nil {
+		c.unsubscribe()
+	}
+}
+
+// Intercept is the cloud.Interceptor of the Cache.
+func (c *Cache) Intercept(ctx context.Context, info *cloud.CallInfo, next cloud.CallHandler) (interface{}, error) {
+	if c.offline.Load() {
+		return c.offlineCall(ctx, info)
+	}
+	switch {
+	case info.Operation == "Get" && info.Key != nil:
+		return c.get(ctx, info, next)
+	case info.Operation == "List" || info.Operation == "AggregatedList":
+		return c.list(ctx, info, next)
+	case cloud.IsMutation(info.Operation) && info.Key != nil:
+		// Invalidate after the call, as a Get racing with the mutation
+		// may store the old object.
+		defer c.Invalidate(info.ProjectID, info.Service, info.Key)
+		return next(ctx)
+	}
+	return next(ctx)
+}
+
+func (c *Cache) get(ctx context.Context, info *cloud.CallInfo, next cloud.CallHandler) (interface{}, error) {
+	ttl := c.config.objectTTL(info.Service)
+	if c.config.policy(info.Service).Disabled || (ttl <= 0 && c.config.NotFoundTTL <= 0) {
+		return next(ctx)
+	}
+	k := Key{
+		Project:  info.ProjectID,
+		Version:  info.Version,
+		Resource: info.Service,
+		Object:   *info.Key,
+	}
+
+	item, _ := c.backend.Get(ctx, k)
+	var staleFor time.Duration
+	if ttl > 0 {
+		staleFor = c.config.StaleWhileRevalidate
+	}
+	if ok, stale := c.lookup(c.stats.Objects, info.Service, item, staleFor); ok {
+		if item.Err != nil {
+			return nil, item.Err
+		}
+		if stale {
+			c.startRefresh(ctx, k, item, ttl, next)
+		}
+		return cloud.DeepCopy(item.Value), nil
+	}
+	return c.fetch(ctx, k, item, ttl, next)
+}
+
+// fetch the object k with next and store it. item is the expired Item of
+// k, nil if there is none.
+func (c *Cache) fetch(ctx context.Context, k Key, item *Item, ttl time.Duration, next cloud.CallHandler) (interface{}, error) {
+	// An expired object with an ETag is revalidated: the server does not
+	// send it again if it has not changed.
+	var etag string
+	if item != nil && item.Err == nil && ttl > 0 {
+		etag = cloud.ETag(item.Value)
+	}
+	if etag != "" {
+		ctx = cloud.WithIfNoneMatch(ctx, etag)
+	}
+	obj, err := next(ctx)
+	switch {
+	case etag != "" && cloud.IsNotModified(err):
+		c.revalidated(c.stats.Objects, k.Resource)
+		c.putObject(ctx, k, &Item{Value: item.Value}, ttl)
+		return cloud.DeepCopy(item.Value), nil
+	case err == nil && ttl > 0:
+		c.putObject(ctx, k, &Item{Value: cloud.DeepCopy(obj)}, ttl)
+	case gcerrors.IsNotFound(err) && c.config.NotFoundTTL > 0:
+		c.putObject(ctx, k, &Item{Err: err}, c.config.NotFoundTTL)
+	case gcerrors.IsNotFound(err) && item != nil:
+		// The expired object must not be served stale.
+		c.backend.Invalidate(ctx, k)
+	}
+	return obj, err
+}
+
+// startRefresh fetches the object k in the background after the stale
+// item was served, unless it is already being refreshed.
+func (c *Cache) startRefresh(ctx context.Context, k Key, item *Item, ttl time.Duration, next cloud.CallHandler) {
+	c.lock.Lock()
+	if c.refreshing[k] {
+		c.lock.Unlock()
+		return
+	}
+	c.refreshing[k] = true
+	c.refreshes.Add(1)
+	c.lock.Unlock()
+
+	go func() {
+		defer func() {
+			c.lock.Lock()
+			delete(c.refreshing, k)
+			c.lock.Unlock()
+			c.refreshes.Done()
+		}()
+		// The refresh outlives the call that served the stale object.
+		obj, err := c.fetch(detachedContext{ctx}, k, item, ttl, next)
+		if c.config.OnRefresh == nil {
+			return
+		}
+		switch {
+		case err == nil && changed(item.Value, obj):
+			c.config.OnRefresh(k, item.Value, obj)
+		case gcerrors.IsNotFound(err):
+			c.config.OnRefresh(k, item.Value, nil)
+		}
+	}()
+}
+
+// changed is true if the objects a and b have different fields.
+func changed(a, b interface{}) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA != nil || errB != nil || !bytes.Equal(ja, jb)
+}
+
+// detachedContext has the values of its parent but is never canceled.
+type detachedContext struct{ context.Context }
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+func (c *Cache) put(ctx context.Context, k Key, item *Item, ttl time.Duration) {
+	item.Created = c.now()
+	item.Expires = item.Created.Add(ttl)
+	c.backend.Put(ctx, k, item, ttl)
+}
+
+// Invalidate the objects of the resource in all API versions and the List
+// results that may contain it. This is done by the Cache for the mutations
+// made through the Service; Invalidate is for the changes made by other
+// means.
+func (c *Cache) Invalidate(project, service string, key *meta.Key) {
+	ctx := context.Background()
+	c.lock.Lock()
+	c.untrack(project, service, key)
+	c.lock.Unlock()
+	n := c.backend.Invalidate(ctx, Key{Project: project, Resource: service, Object: *key})
+	c.evict(c.stats.Objects, service, n)
+	c.invalidateLists(ctx, project, service, key)
+}
+
+// OnEvent invalidates the resource of the Event. It is a
+// cloud.EventHandler for the mutations made through other Services and the
+// operations that complete outside of a call, e.g. a WaitForCompletion
+// resumed after a restart. It is subscribed to Config.Events by New.
+func (c *Cache) OnEvent(ctx context.Context, e *cloud.Event) {
+	if e.ResourceID == nil || e.ResourceID.Key == nil || e.Service == "" {
+		return
+	}
+	c.Invalidate(e.ResourceID.ProjectID, e.Service, e.ResourceID.Key)
+}
+
+// Clear removes all the objects from the Cache. It does nothing if the
+// Backend is not an Enumerator.
+func (c *Cache) Clear() {
+	e, ok := c.backend.(Enumerator)
+	if !ok {
+		return
+	}
+	e.Range(func(k Key, _ *Item) bool {
+		if k.List {
+			c.evict(c.stats.Lists, k.Resource, 1)
+		} else {
+			c.evict(c.stats.Objects, k.Resource, 1)
+		}
+		return true
+	})
+	e.Clear()
+	c.lock.Lock()
+	c.fifos = map[string]*fifo{}
+	c.lock.Unlock()
+}
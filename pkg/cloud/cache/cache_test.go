@@ -0,0 +1,419 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/gcerrors"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+)
+
+// fakeAPI is a next cloud.CallHandler that counts the calls.
+type fakeAPI struct {
+	calls int
+	obj   interface{}
+	err   error
+}
+
+func (f *fakeAPI) handler(ctx context.Context) (interface{}, error) {
+	f.calls++
+	return cloud.DeepCopy(f.obj), f.err
+}
+
+func callInfo(service, op string, version meta.Version, key *meta.Key) *cloud.CallInfo {
+	return &cloud.CallInfo{
+		CallContextKey: cloud.CallContextKey{ProjectID: "proj", Service: service, Operation: op, Version: version},
+		Key:            key,
+	}
+}
+
+func TestCacheGet(t *testing.T) {
+	t.Parallel()
+
+	c := New(Config{
+		Default:  Policy{TTL: time.Minute},
+		Policies: map[string]Policy{"Firewalls": {}},
+	})
+	now := time.Unix(0, 0)
+	c.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	key := meta.GlobalKey("bs")
+	api := &fakeAPI{obj: &ga.BackendService{Name: "bs", Description: "v1"}}
+	get := func() *ga.BackendService {
+		t.Helper()
+		obj, err := c.Intercept(ctx, callInfo("BackendServices", "Get", meta.VersionGA, key), api.handler)
+		if err != nil {
+			t.Fatalf("Get() = _, %v, want nil", err)
+		}
+		return obj.(*ga.BackendService)
+	}
+
+	get().Description = "modified by the caller"
+	if got := get(); got.Description != "v1" || api.calls != 1 {
+		t.Fatalf("Get() = %q after %d calls, want %q after 1 call", got.Description, api.calls, "v1")
+	}
+
+	// A mutation invalidates the object.
+	api.obj = &ga.BackendService{Name: "bs", Description: "v2"}
+	if _, err := c.Intercept(ctx, callInfo("BackendServices", "Patch", meta.VersionBeta, key), api.handler); err != nil {
+		t.Fatalf("Patch() = _, %v, want nil", err)
+	}
+	if got := get(); got.Description != "v2" || api.calls != 3 {
+		t.Fatalf("Get() = %q after %d calls, want %q after 3 calls", got.Description, api.calls, "v2")
+	}
+
+	// The object expires.
+	api.obj = &ga.BackendService{Name: "bs", Description: "v3"}
+	now = now.Add(time.Minute)
+	if got := get(); got.Description != "v3" || api.calls != 4 {
+		t.Fatalf("Get() = %q after %d calls, want %q after 4 calls", got.Description, api.calls, "v3")
+	}
+
+	// Resources with a TTL of 0 are not cached.
+	fw := &fakeAPI{obj: &ga.Firewall{Name: "fw"}}
+	for i := 0; i < 2; i++ {
+		c.Intercept(ctx, callInfo("Firewalls", "Get", meta.VersionGA, meta.GlobalKey("fw")), fw.handler)
+	}
+	if fw.calls != 2 {
+		t.Errorf("Firewalls calls = %d, want 2", fw.calls)
+	}
+}
+
+func TestCacheStaleWhileRevalidate(t *testing.T) {
+	t.Parallel()
+
+	type refresh struct{ stale, fresh interface{} }
+	var refreshes []refresh
+	c := New(Config{
+		Default:              Policy{TTL: time.Minute},
+		StaleWhileRevalidate: time.Minute,
+		OnRefresh: func(k Key, stale, fresh interface{}) {
+			refreshes = append(refreshes, refresh{stale, fresh})
+		},
+	})
+	now := time.Unix(0, 0)
+	c.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	key := meta.GlobalKey("bs")
+	api := &fakeAPI{obj: &ga.BackendService{Name: "bs", Description: "v1"}}
+	get := func() (*ga.BackendService, error) {
+		obj, err := c.Intercept(ctx, callInfo("BackendServices", "Get", meta.VersionGA, key), api.handler)
+		c.refreshes.Wait()
+		if err != nil {
+			return nil, err
+		}
+		return obj.(*ga.BackendService), nil
+	}
+
+	get()
+	api.obj = &ga.BackendService{Name: "bs", Description: "v2"}
+	// The stale object is served and refreshed.
+	now = now.Add(90 * time.Second)
+	if got, _ := get(); got.Description != "v1" || api.calls != 2 {
+		t.Fatalf("Get() = %q after %d calls, want stale %q after 2 calls", got.Description, api.calls, "v1")
+	}
+	if len(refreshes) != 1 || refreshes[0].stale.(*ga.BackendService).Description != "v1" || refreshes[0].fresh.(*ga.BackendService).Description != "v2" {
+		t.Fatalf("OnRefresh calls = %+v, want v1 -> v2", refreshes)
+	}
+	if got, _ := get(); got.Description != "v2" || api.calls != 2 {
+		t.Fatalf("Get() = %q after %d calls, want %q after 2 calls", got.Description, api.calls, "v2")
+	}
+	// A refresh without changes is not notified.
+	now = now.Add(90 * time.Second)
+	get()
+	if len(refreshes) != 1 || api.calls != 3 {
+		t.Fatalf("OnRefresh calls = %d after %d calls, want 1 after 3", len(refreshes), api.calls)
+	}
+	// Objects older than TTL + StaleWhileRevalidate are fetched.
+	now = now.Add(3 * time.Minute)
+	if got, _ := get(); got.Description != "v2" || api.calls != 4 {
+		t.Fatalf("Get() = %q after %d calls, want %q after 4 calls", got.Description, api.calls, "v2")
+	}
+	// The deletion of the object is notified and it is not served anymore.
+	api.obj, api.err = nil, &googleapi.Error{Code: http.StatusNotFound}
+	now = now.Add(90 * time.Second)
+	get()
+	if len(refreshes) != 2 || refreshes[1].fresh != nil {
+		t.Fatalf("OnRefresh calls = %+v, want a deletion", refreshes)
+	}
+	if _, err := get(); !gcerrors.IsNotFound(err) {
+		t.Fatalf("Get() = %v, want not found", err)
+	}
+	if got := c.Stats().Objects["BackendServices"].Stale; got != 3 {
+		t.Errorf("Stale = %d, want 3", got)
+	}
+}
+
+func TestCacheOnEvent(t *testing.T) {
+	t.Parallel()
+
+	bus := cloud.NewEventBus()
+	c := New(Config{Default: Policy{TTL: time.Minute}, Events: bus})
+
+	ctx := context.Background()
+	key := meta.RegionalKey("bs", "us-central1")
+	api := &fakeAPI{obj: &ga.BackendService{Name: "bs"}}
+	get := func() {
+		c.Intercept(ctx, callInfo("RegionBackendServices", "Get", meta.VersionGA, key), api.handler)
+	}
+	get()
+	bus.Publish(ctx, &cloud.Event{
+		Type:       cloud.EventUpdated,
+		ResourceID: &cloud.ResourceID{ProjectID: "proj", Resource: "backendServices", Key: key},
+		Service:    "RegionBackendServices",
+	})
+	get()
+	if api.calls != 2 {
+		t.Errorf("calls = %d, want 2", api.calls)
+	}
+
+	// After Close, the Events no longer invalidate the Cache.
+	c.Close()
+	bus.Publish(ctx, &cloud.Event{
+		Type:       cloud.EventUpdated,
+		ResourceID: &cloud.ResourceID{ProjectID: "proj", Resource: "backendServices", Key: key},
+		Service:    "RegionBackendServices",
+	})
+	get()
+	if api.calls != 2 {
+		t.Errorf("calls = %d after Close, want 2", api.calls)
+	}
+}
+
+func TestCacheService(t *testing.T) {
+	t.Parallel()
+
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "bs"})
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+	c := New(Config{Default: Policy{TTL: time.Minute}})
+	gce := cloud.NewGCE(&cloud.Service{
+		GA:            client,
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj"},
+		RateLimiter:   &cloud.NopRateLimiter{},
+		Interceptors:  []cloud.Interceptor{c.Intercept},
+	})
+
+	key := meta.GlobalKey("bs")
+	for i := 0; i < 3; i++ {
+		bs, err := gce.BackendServices().Get(ctx, key)
+		if err != nil || bs.Name != "bs" {
+			t.Fatalf("Get(%v) = %v, %v; want bs, nil", key, bs, err)
+		}
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("requests = %d, want 1", n)
+	}
+}
+
+func TestCacheRevalidate(t *testing.T) {
+	t.Parallel()
+
+	var requests, notModified int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "bs", "description": "v1"})
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+	c := New(Config{Default: Policy{TTL: time.Minute}})
+	now := time.Unix(0, 0)
+	c.now = func() time.Time { return now }
+	gce := cloud.NewGCE(&cloud.Service{
+		GA:            client,
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj"},
+		RateLimiter:   &cloud.NopRateLimiter{},
+		Interceptors:  []cloud.Interceptor{c.Intercept},
+	})
+
+	key := meta.GlobalKey("bs")
+	for i := 0; i < 3; i++ {
+		bs, err := gce.BackendServices().Get(ctx, key)
+		if err != nil || bs.Description != "v1" {
+			t.Fatalf("Get(%v) = %+v, %v; want v1, nil", key, bs, err)
+		}
+		// Each Get after the first finds the object expired.
+		now = now.Add(time.Minute)
+	}
+	if n, nm := atomic.LoadInt32(&requests), atomic.LoadInt32(&notModified); n != 3 || nm != 2 {
+		t.Errorf("requests = %d with %d not modified, want 3 with 2", n, nm)
+	}
+	if got := c.Stats().Objects["BackendServices"].Revalidated; got != 2 {
+		t.Errorf("Revalidated = %d, want 2", got)
+	}
+}
+
+func TestCacheList(t *testing.T) {
+	t.Parallel()
+
+	c := New(Config{ListMaxStaleness: time.Minute})
+	now := time.Unix(0, 0)
+	c.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	api := &fakeAPI{}
+	var items []*ga.Address
+	list := func(region string, fl *filter.F) []*ga.Address {
+		t.Helper()
+		info := callInfo("Addresses", "List", meta.VersionGA, nil)
+		info.Args = []interface{}{region, fl}
+		res, err := c.Intercept(ctx, info, func(ctx context.Context) (interface{}, error) {
+			api.calls++
+			return copyResult(items), nil
+		})
+		if err != nil {
+			t.Fatalf("List() = _, %v, want nil", err)
+		}
+		return res.([]*ga.Address)
+	}
+	mutate := func(key *meta.Key) {
+		t.Helper()
+		if _, err := c.Intercept(ctx, callInfo("Addresses", "Delete", meta.VersionGA, key), api.handler); err != nil {
+			t.Fatalf("Delete() = _, %v, want nil", err)
+		}
+	}
+
+	items = []*ga.Address{{Name: "a1"}}
+	list("us-central1", filter.None)[0].Name = "modified by the caller"
+	if got := list("us-central1", filter.None); got[0].Name != "a1" || api.calls != 1 {
+		t.Fatalf("List() = %v after %d calls, want [a1] after 1 call", got, api.calls)
+	}
+	// Each filter and scope is cached separately.
+	list("us-central1", filter.Regexp("name", "a.*"))
+	list("europe-west1", filter.None)
+	if api.calls != 3 {
+		t.Fatalf("calls = %d, want 3", api.calls)
+	}
+
+	// A mutation in another scope does not invalidate the List.
+	mutate(meta.RegionalKey("a2", "europe-west1"))
+	list("us-central1", filter.None)
+	if api.calls != 4 {
+		t.Fatalf("calls = %d, want 4", api.calls)
+	}
+	// A mutation in the scope does.
+	items = nil
+	mutate(meta.RegionalKey("a1", "us-central1"))
+	if got := list("us-central1", filter.None); len(got) != 0 || api.calls != 6 {
+		t.Fatalf("List() = %v after %d calls, want [] after 6 calls", got, api.calls)
+	}
+	// List results expire after the max staleness.
+	items = []*ga.Address{{Name: "a3"}}
+	now = now.Add(time.Minute)
+	if got := list("us-central1", filter.None); len(got) != 1 || api.calls != 7 {
+		t.Fatalf("List() = %v after %d calls, want [a3] after 7 calls", got, api.calls)
+	}
+}
+
+func TestCopyResult(t *testing.T) {
+	t.Parallel()
+
+	src := map[string][]*ga.Address{"regions/us-central1": {{Name: "a1"}}}
+	got := copyResult(src).(map[string][]*ga.Address)
+	got["regions/us-central1"][0].Name = "changed"
+	if src["regions/us-central1"][0].Name != "a1" {
+		t.Errorf("modifying the copy changed the source: %v", src)
+	}
+}
+
+func TestCacheNotFound(t *testing.T) {
+	t.Parallel()
+
+	c := New(Config{NotFoundTTL: 5 * time.Second})
+	now := time.Unix(0, 0)
+	c.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	key := meta.GlobalKey("fw")
+	api := &fakeAPI{err: &googleapi.Error{Code: http.StatusNotFound}}
+	get := func() error {
+		_, err := c.Intercept(ctx, callInfo("Firewalls", "Get", meta.VersionGA, key), api.handler)
+		return err
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := get(); !gcerrors.IsNotFound(err) {
+			t.Fatalf("Get() = %v, want not found", err)
+		}
+	}
+	if api.calls != 1 {
+		t.Fatalf("calls = %d, want 1", api.calls)
+	}
+
+	// The not found error expires.
+	now = now.Add(5 * time.Second)
+	get()
+	if api.calls != 2 {
+		t.Fatalf("calls = %d, want 2", api.calls)
+	}
+
+	// Insert invalidates the not found error. Found objects are not
+	// cached with a TTL of 0.
+	api.err = nil
+	api.obj = &ga.Firewall{Name: "fw"}
+	c.Intercept(ctx, callInfo("Firewalls", "Insert", meta.VersionGA, key), api.handler)
+	for i := 0; i < 2; i++ {
+		if err := get(); err != nil {
+			t.Fatalf("Get() = %v, want nil", err)
+		}
+	}
+	if api.calls != 5 {
+		t.Fatalf("calls = %d, want 5", api.calls)
+	}
+
+	// Other errors are not cached.
+	api.err = &googleapi.Error{Code: http.StatusServiceUnavailable}
+	get()
+	get()
+	if api.calls != 7 {
+		t.Fatalf("calls = %d, want 7", api.calls)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// aggregatedScope is the scope of the AggregatedList entries.
+const aggregatedScope = "*"
+
+// newListKey returns the Key of the List or AggregatedList call. ok is
+// false if the arguments are not known.
+func newListKey(info *cloud.CallInfo) (k Key, ok bool) {
+	k = Key{Project: info.ProjectID, Version: info.Version, Resource: info.Service, List: true}
+	args := info.Args
+	if info.Operation == "AggregatedList" {
+		k.Scope = aggregatedScope
+	} else if len(args) == 2 {
+		// List(ctx, region or zone, fl)
+		if k.Scope, ok = args[0].(string); !ok {
+			return k, false
+		}
+		args = args[1:]
+	}
+	if len(args) != 1 {
+		return k, false
+	}
+	fl, ok := args[0].(*filter.F)
+	if !ok {
+		return k, false
+	}
+	if fl != filter.None {
+		k.Filter = fl.String()
+	}
+	return k, true
+}
+
+func (c *Cache) list(ctx context.Context, info *cloud.CallInfo, next cloud.CallHandler) (interface{}, error) {
+	ttl := c.config.ListMaxStaleness
+	if ttl <= 0 || c.config.policy(info.Service).Disabled {
+		return next(ctx)
+	}
+	k, ok := newListKey(info)
+	if !ok {
+		return next(ctx)
+	}
+
+	item, _ := c.backend.Get(ctx, k)
+	if ok, _ := c.lookup(c.stats.Lists, info.Service, item, 0); ok {
+		return copyResult(item.Value), nil
+	}
+
+	res, err := next(ctx)
+	if err != nil {
+		return res, err
+	}
+	c.put(ctx, k, &Item{Value: copyResult(res)}, ttl)
+
+	return res, nil
+}
+
+// invalidateLists removes the List results that may contain the resource
+// key.
+func (c *Cache) invalidateLists(ctx context.Context, project, service string, key *meta.Key) {
+	var scope string
+	switch key.Type() {
+	case meta.Regional:
+		scope = key.Region
+	case meta.Zonal:
+		scope = key.Zone
+	}
+	for _, s := range []string{scope, aggregatedScope} {
+		n := c.backend.Invalidate(ctx, Key{Project: project, Resource: service, List: true, Scope: s})
+		c.evict(c.stats.Lists, service, n)
+	}
+}
+
+// copyResult returns a deep copy of the result of a List (a slice of
+// objects) or of an AggregatedList (a map of slices).
+func copyResult(res interface{}) interface{} {
+	return copyValue(reflect.ValueOf(res)).Interface()
+}
+
+func copyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		return reflect.ValueOf(cloud.DeepCopy(v.Interface()))
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		ret := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			ret.Index(i).Set(copyValue(v.Index(i)))
+		}
+		return ret
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		ret := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			ret.SetMapIndex(iter.Key(), copyValue(iter.Value()))
+		}
+		return ret
+	}
+	return v
+}
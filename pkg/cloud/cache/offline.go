@@ -0,0 +1,138 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/googleapi"
+)
+
+// ErrOffline is returned by the calls that an offline Cache cannot serve.
+var ErrOffline = errors.New("cache is offline")
+
+// SetOffline switches the Cache to serving all the calls made through the
+// Service from the cached objects, without calling the API. This is for
+// dry runs and reports of what a controller would change on a snapshot,
+// usually filled with Warm.
+//
+// When offline:
+//   - Get returns the cached object, even if it expired, and a not found
+//     error if there is none.
+//   - List and AggregatedList return the cached result of the same call
+//     or, if the Backend is an Enumerator, the cached objects of the
+//     resource in the scope. Filters are applied as in the mocks.
+//   - Mutations fail with ErrOffline after being passed to
+//     Config.OnRejectedMutation.
+//   - Other calls fail with ErrOffline.
+func (c *Cache) SetOffline(offline bool) {
+	c.offline.Store(offline)
+}
+
+func (c *Cache) offlineCall(ctx context.Context, info *cloud.CallInfo) (interface{}, error) {
+	switch {
+	case info.Operation == "Get" && info.Key != nil:
+		k := Key{Project: info.ProjectID, Version: info.Version, Resource: info.Service, Object: *info.Key}
+		if item, ok := c.backend.Get(ctx, k); ok {
+			if item.Err != nil {
+				return nil, item.Err
+			}
+			return cloud.DeepCopy(item.Value), nil
+		}
+		return nil, &googleapi.Error{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("%s %v is not in the cache (offline)", info.Service, info.Key),
+		}
+	case info.Operation == "List" || info.Operation == "AggregatedList":
+		return c.offlineList(ctx, info)
+	case cloud.IsMutation(info.Operation):
+		if c.config.OnRejectedMutation != nil {
+			c.config.OnRejectedMutation(ctx, info)
+		}
+	}
+	return nil, fmt.Errorf("%s.%s(%v): %w", info.Service, info.Operation, info.Key, ErrOffline)
+}
+
+func (c *Cache) offlineList(ctx context.Context, info *cloud.CallInfo) (interface{}, error) {
+	k, ok := newListKey(info)
+	if !ok {
+		return nil, fmt.Errorf("%s.%s: %w", info.Service, info.Operation, ErrOffline)
+	}
+	if item, ok := c.backend.Get(ctx, k); ok {
+		return copyResult(item.Value), nil
+	}
+	e, ok := c.backend.(Enumerator)
+	if !ok {
+		return nil, fmt.Errorf("%s.%s: %w", info.Service, info.Operation, ErrOffline)
+	}
+	fl, _ := info.Args[len(info.Args)-1].(*filter.F)
+
+	// Objects by aggregated list scope, e.g. "regions/us-central1".
+	objs := map[string][]reflect.Value{}
+	var elemType reflect.Type
+	e.Range(func(ik Key, item *Item) bool {
+		if ik.List || item.Err != nil || ik.Project != k.Project || ik.Version != k.Version || ik.Resource != k.Resource {
+			return true
+		}
+		if k.Scope != aggregatedScope && k.Scope != ik.Object.Region+ik.Object.Zone {
+			return true
+		}
+		if !fl.Match(item.Value) {
+			return true
+		}
+		v := reflect.ValueOf(cloud.DeepCopy(item.Value))
+		elemType = v.Type()
+		objs[aggregatedListScope(ik.Object)] = append(objs[aggregatedListScope(ik.Object)], v)
+		return true
+	})
+	if elemType == nil {
+		return nil, nil
+	}
+
+	sliceType := reflect.SliceOf(elemType)
+	if k.Scope != aggregatedScope {
+		ret := reflect.MakeSlice(sliceType, 0, 0)
+		for _, vs := range objs {
+			ret = reflect.Append(ret, vs...)
+		}
+		return ret.Interface(), nil
+	}
+	ret := reflect.MakeMap(reflect.MapOf(reflect.TypeOf(""), sliceType))
+	for scope, vs := range objs {
+		ret.SetMapIndex(reflect.ValueOf(scope), reflect.Append(reflect.MakeSlice(sliceType, 0, len(vs)), vs...))
+	}
+	return ret.Interface(), nil
+}
+
+// aggregatedListScope is the key of the object key in the result of an
+// AggregatedList.
+func aggregatedListScope(key meta.Key) string {
+	switch key.Type() {
+	case meta.Regional:
+		return "regions/" + key.Region
+	case meta.Zonal:
+		return "zones/" + key.Zone
+	}
+	return "global"
+}
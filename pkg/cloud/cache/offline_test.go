@@ -0,0 +1,104 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/gcerrors"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+)
+
+func TestCacheOffline(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	// The snapshot is taken from the mock.
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	for _, key := range []*meta.Key{
+		meta.RegionalKey("a1", "us-central1"),
+		meta.RegionalKey("a2", "us-central1"),
+		meta.RegionalKey("b1", "europe-west1"),
+	} {
+		if err := mock.Addresses().Insert(ctx, key, &ga.Address{}); err != nil {
+			t.Fatalf("Insert(%v) = %v", key, err)
+		}
+	}
+	var rejected []string
+	c := New(Config{
+		Default: Policy{TTL: time.Minute},
+		OnRejectedMutation: func(ctx context.Context, info *cloud.CallInfo) {
+			rejected = append(rejected, info.Operation+" "+info.Key.Name)
+		},
+	})
+	if err := c.Warm(ctx, mock, []string{"Addresses"}, nil); err != nil {
+		t.Fatalf("Warm() = %v", err)
+	}
+	c.SetOffline(true)
+
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+	gce := cloud.NewGCE(&cloud.Service{
+		GA:            client,
+		ProjectRouter: &cloud.SingleProjectRouter{ID: "proj"},
+		RateLimiter:   &cloud.NopRateLimiter{},
+		Interceptors:  []cloud.Interceptor{c.Intercept},
+	})
+
+	if a, err := gce.Addresses().Get(ctx, meta.RegionalKey("a1", "us-central1")); err != nil || a.Name != "a1" {
+		t.Errorf("Get(a1) = %v, %v; want a1, nil", a, err)
+	}
+	if _, err := gce.Addresses().Get(ctx, meta.RegionalKey("c1", "us-central1")); !gcerrors.IsNotFound(err) {
+		t.Errorf("Get(c1) = %v, want not found", err)
+	}
+	if got, err := gce.Addresses().List(ctx, "us-central1", filter.None); err != nil || len(got) != 2 {
+		t.Errorf("List(us-central1) = %v, %v; want 2 addresses", got, err)
+	}
+	if got, err := gce.Addresses().List(ctx, "us-central1", filter.Regexp("name", "a2")); err != nil || len(got) != 1 || got[0].Name != "a2" {
+		t.Errorf("List(us-central1, name=a2) = %v, %v; want [a2]", got, err)
+	}
+	got, err := gce.Addresses().AggregatedList(ctx, filter.None)
+	if err != nil || len(got["regions/us-central1"]) != 2 || len(got["regions/europe-west1"]) != 1 {
+		t.Errorf("AggregatedList() = %v, %v; want 2 addresses in us-central1 and 1 in europe-west1", got, err)
+	}
+	if err := gce.Addresses().Delete(ctx, meta.RegionalKey("a1", "us-central1")); !errors.Is(err, ErrOffline) {
+		t.Errorf("Delete(a1) = %v, want ErrOffline", err)
+	}
+	if len(rejected) != 1 || rejected[0] != "Delete a1" {
+		t.Errorf("rejected mutations = %v, want [Delete a1]", rejected)
+	}
+	if _, err := gce.Addresses().Get(ctx, meta.RegionalKey("a1", "us-central1")); err != nil {
+		t.Errorf("Get(a1) after the rejected Delete = %v, want nil", err)
+	}
+}
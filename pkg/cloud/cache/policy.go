@@ -0,0 +1,127 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// Forever is the TTL of objects that are only removed from the Cache by
+// invalidations, e.g. for Zones.
+const Forever = time.Duration(math.MaxInt64)
+
+// Policy of the caching of a resource.
+type Policy struct {
+	// TTL of the objects returned by Get. Zero disables caching the
+	// objects.
+	TTL time.Duration
+	// MaxEntries is the maximum number of objects of the resource put
+	// in the Backend by the Cache. The oldest objects are evicted first.
+	// Zero is no limit.
+	MaxEntries int
+	// Disabled disables all caching of the resource, including Lists
+	// and not found errors.
+	Disabled bool
+}
+
+// policy returns the Policy of the resource service.
+func (c *Config) policy(service string) Policy {
+	if p, ok := c.Policies[service]; ok {
+		return p
+	}
+	return c.Default
+}
+
+// objectTTL returns the TTL of the objects of service, 0 if they are not
+// cached.
+func (c *Config) objectTTL(service string) time.Duration {
+	p := c.policy(service)
+	if p.Disabled {
+		return 0
+	}
+	return p.TTL
+}
+
+// fifo is the order in which the objects of a resource with MaxEntries
+// were put.
+type fifo struct {
+	order *list.List
+	elems map[Key]*list.Element
+}
+
+// track that k was put and returns the Keys to evict if there are more
+// than max.
+func (c *Cache) track(k Key, max int) []Key {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	f, ok := c.fifos[k.Resource]
+	if !ok {
+		f = &fifo{order: list.New(), elems: map[Key]*list.Element{}}
+		c.fifos[k.Resource] = f
+	}
+	if e, ok := f.elems[k]; ok {
+		f.order.MoveToBack(e)
+	} else {
+		f.elems[k] = f.order.PushBack(k)
+	}
+	var evict []Key
+	for f.order.Len() > max {
+		e := f.order.Front()
+		ek := e.Value.(Key)
+		f.order.Remove(e)
+		delete(f.elems, ek)
+		evict = append(evict, ek)
+	}
+	return evict
+}
+
+// untrack the object key in all API versions. c.lock must be held.
+func (c *Cache) untrack(project, service string, key *meta.Key) {
+	f, ok := c.fifos[service]
+	if !ok {
+		return
+	}
+	for _, v := range meta.AllVersions {
+		k := Key{Project: project, Version: v, Resource: service, Object: *key}
+		if e, ok := f.elems[k]; ok {
+			f.order.Remove(e)
+			delete(f.elems, k)
+		}
+	}
+}
+
+// putObject puts the object k with the TTL and MaxEntries of its Policy.
+func (c *Cache) putObject(ctx context.Context, k Key, item *Item, ttl time.Duration) {
+	c.put(ctx, k, item, ttl)
+	max := c.config.policy(k.Resource).MaxEntries
+	if max <= 0 {
+		return
+	}
+	for _, ek := range c.track(k, max) {
+		n := c.backend.Invalidate(ctx, ek)
+		c.lock.Lock()
+		c.untrack(ek.Project, ek.Resource, &ek.Object)
+		c.lock.Unlock()
+		c.evict(c.stats.Objects, ek.Resource, n)
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+func TestCachePolicies(t *testing.T) {
+	t.Parallel()
+
+	c := New(Config{
+		Default: Policy{TTL: time.Minute},
+		Policies: map[string]Policy{
+			"Zones":     {TTL: Forever},
+			"Instances": {TTL: time.Minute, MaxEntries: 2},
+			"Disks":     {TTL: time.Minute, Disabled: true},
+		},
+		ListMaxStaleness: time.Minute,
+		NotFoundTTL:      time.Minute,
+	})
+	now := time.Unix(0, 0)
+	c.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	get := func(service string, key *meta.Key, api *fakeAPI) {
+		t.Helper()
+		c.Intercept(ctx, callInfo(service, "Get", meta.VersionGA, key), api.handler)
+	}
+
+	// Zones are cached forever.
+	zones := &fakeAPI{obj: &ga.Zone{Name: "us-central1-a"}}
+	get("Zones", meta.GlobalKey("us-central1-a"), zones)
+	now = now.Add(100 * 365 * 24 * time.Hour)
+	get("Zones", meta.GlobalKey("us-central1-a"), zones)
+	if zones.calls != 1 {
+		t.Errorf("Zones calls = %d, want 1", zones.calls)
+	}
+
+	// The oldest Instance is evicted.
+	instances := &fakeAPI{obj: &ga.Instance{}}
+	keys := []*meta.Key{
+		meta.ZonalKey("i1", "us-central1-a"),
+		meta.ZonalKey("i2", "us-central1-a"),
+		meta.ZonalKey("i3", "us-central1-a"),
+	}
+	for _, key := range keys {
+		get("Instances", key, instances)
+	}
+	get("Instances", keys[2], instances)
+	get("Instances", keys[1], instances)
+	if instances.calls != 3 {
+		t.Errorf("Instances calls = %d, want 3", instances.calls)
+	}
+	get("Instances", keys[0], instances)
+	if instances.calls != 4 {
+		t.Errorf("Instances calls = %d, want 4", instances.calls)
+	}
+	if got := c.Stats().Objects["Instances"].Evictions; got != 2 {
+		t.Errorf("Instances Evictions = %d, want 2", got)
+	}
+	// An invalidated Instance does not count.
+	c.Invalidate("proj", "Instances", keys[0])
+	get("Instances", keys[2], instances)
+	get("Instances", keys[0], instances)
+	get("Instances", keys[2], instances)
+	if instances.calls != 5 {
+		t.Errorf("Instances calls = %d, want 5", instances.calls)
+	}
+
+	// Nothing is cached for Disks.
+	disks := &fakeAPI{err: &googleapi.Error{Code: http.StatusNotFound}}
+	get("Disks", meta.ZonalKey("d1", "us-central1-a"), disks)
+	get("Disks", meta.ZonalKey("d1", "us-central1-a"), disks)
+	info := callInfo("Disks", "List", meta.VersionGA, nil)
+	info.Args = []interface{}{"us-central1-a", filter.None}
+	disks.err, disks.obj = nil, []*ga.Disk{}
+	c.Intercept(ctx, info, disks.handler)
+	c.Intercept(ctx, info, disks.handler)
+	if disks.calls != 4 {
+		t.Errorf("Disks calls = %d, want 4", disks.calls)
+	}
+}
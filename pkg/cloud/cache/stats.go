@@ -0,0 +1,243 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// Counters of the lookups of a resource in the Cache.
+type Counters struct {
+	// Hits is the number of calls served from the Cache.
+	Hits int64
+	// Misses is the number of calls sent to the API, including Expired.
+	Misses int64
+	// Stale is the number of Hits that served an expired object while
+	// it was refreshed.
+	Stale int64
+	// Expired is the number of Misses that found an expired entry.
+	Expired int64
+	// Revalidated is the number of Expired objects that had not changed
+	// according to their ETag.
+	Revalidated int64
+	// Evictions is the number of entries removed before they expired,
+	// by mutations, Invalidate and Clear.
+	Evictions int64
+	// HitAge is the total age of the entries served by the Hits.
+	HitAge time.Duration
+}
+
+// HitRatio is the fraction of the calls served from the Cache.
+func (c Counters) HitRatio() float64 {
+	if c.Hits+c.Misses == 0 {
+		return 0
+	}
+	return float64(c.Hits) / float64(c.Hits+c.Misses)
+}
+
+// MeanHitAge is the mean staleness of the entries served from the Cache.
+func (c Counters) MeanHitAge() time.Duration {
+	if c.Hits == 0 {
+		return 0
+	}
+	return c.HitAge / time.Duration(c.Hits)
+}
+
+// ResourceCounters are the Counters by resource, e.g. "BackendServices".
+type ResourceCounters map[string]*Counters
+
+func (rc ResourceCounters) get(service string) *Counters {
+	c, ok := rc[service]
+	if !ok {
+		c = &Counters{}
+		rc[service] = c
+	}
+	return c
+}
+
+// lookup counts the lookup in rc of the item (nil if there is none). ok
+// is true if the item can be served; stale is true if it is served for up
+// to staleFor after it expired.
+func (c *Cache) lookup(rc ResourceCounters, service string, item *Item, staleFor time.Duration) (ok, stale bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	rs := rc.get(service)
+	switch {
+	case item == nil:
+		rs.Misses++
+		return false, false
+	case !now.Before(item.Expires) && item.Err == nil && now.Before(item.Expires.Add(staleFor)):
+		rs.Stale++
+		stale = true
+	case !now.Before(item.Expires):
+		rs.Misses++
+		rs.Expired++
+		return false, false
+	}
+	rs.Hits++
+	rs.HitAge += now.Sub(item.Created)
+	return true, stale
+}
+
+// revalidated counts a revalidation in rc.
+func (c *Cache) revalidated(rc ResourceCounters, service string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	rc.get(service).Revalidated++
+}
+
+// evict counts n evictions in rc.
+func (c *Cache) evict(rc ResourceCounters, service string, n int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	rc.get(service).Evictions += int64(n)
+}
+
+func (rc ResourceCounters) copy() ResourceCounters {
+	ret := ResourceCounters{}
+	for k, c := range rc {
+		c := *c
+		ret[k] = &c
+	}
+	return ret
+}
+
+// Stats of a Cache.
+type Stats struct {
+	// Objects are the Counters of Get.
+	Objects ResourceCounters
+	// Lists are the Counters of List and AggregatedList.
+	Lists ResourceCounters
+	// ObjectEntries is the number of objects in the Cache, including
+	// the expired ones. The entries are only counted if the Backend is
+	// an Enumerator.
+	ObjectEntries int
+	// ListEntries is the number of List results in the Cache.
+	ListEntries int
+}
+
+func newStats() Stats {
+	return Stats{Objects: ResourceCounters{}, Lists: ResourceCounters{}}
+}
+
+// Stats returns a snapshot of the Counters of the Cache since it was
+// created.
+func (c *Cache) Stats() *Stats {
+	c.lock.Lock()
+	ret := &Stats{
+		Objects: c.stats.Objects.copy(),
+		Lists:   c.stats.Lists.copy(),
+	}
+	c.lock.Unlock()
+
+	if e, ok := c.backend.(Enumerator); ok {
+		e.Range(func(k Key, _ *Item) bool {
+			if k.List {
+				ret.ListEntries++
+			} else {
+				ret.ObjectEntries++
+			}
+			return true
+		})
+	}
+	return ret
+}
+
+// Entry is a cached object or List result returned by Dump.
+type Entry struct {
+	Project  string
+	Version  meta.Version
+	Resource string
+	// Key of an object. It is empty for a List.
+	Key string `json:",omitempty"`
+	// Scope and Filter of a List. Scope is "*" for AggregatedList.
+	Scope  string `json:",omitempty"`
+	Filter string `json:",omitempty"`
+	// NotFound is true for the not found errors of Get.
+	NotFound bool `json:",omitempty"`
+	// Age of the entry and the time until it expires, which is negative
+	// for expired entries.
+	Age       time.Duration
+	ExpiresIn time.Duration
+}
+
+// Dump the entries of the Cache, objects first. It returns nil if the
+// Backend is not an Enumerator.
+func (c *Cache) Dump() []Entry {
+	e, ok := c.backend.(Enumerator)
+	if !ok {
+		return nil
+	}
+	now := c.now()
+	var objects, lists []Entry
+	e.Range(func(k Key, item *Item) bool {
+		entry := Entry{
+			Project:   k.Project,
+			Version:   k.Version,
+			Resource:  k.Resource,
+			Age:       now.Sub(item.Created),
+			ExpiresIn: item.Expires.Sub(now),
+		}
+		if k.List {
+			entry.Scope = k.Scope
+			entry.Filter = k.Filter
+			lists = append(lists, entry)
+		} else {
+			entry.Key = k.Object.String()
+			entry.NotFound = item.Err != nil
+			objects = append(objects, entry)
+		}
+		return true
+	})
+	sortEntries(objects)
+	sortEntries(lists)
+	return append(objects, lists...)
+}
+
+func sortEntries(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		return fmt.Sprint(a.Project, a.Resource, a.Key, a.Scope, a.Filter, a.Version) <
+			fmt.Sprint(b.Project, b.Resource, b.Key, b.Scope, b.Filter, b.Version)
+	})
+}
+
+// ServeHTTP implements http.Handler. It serves the Stats as JSON, with the
+// entries of Dump if the query has the "entries" parameter.
+func (c *Cache) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	out := struct {
+		Stats   *Stats
+		Entries []Entry `json:",omitempty"`
+	}{Stats: c.Stats()}
+	if r.URL.Query().Has("entries") {
+		out.Entries = c.Dump()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(out)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestCacheStats(t *testing.T) {
+	t.Parallel()
+
+	c := New(Config{Default: Policy{TTL: time.Minute}, ListMaxStaleness: time.Minute})
+	now := time.Unix(0, 0)
+	c.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	key := meta.GlobalKey("bs")
+	api := &fakeAPI{obj: &ga.BackendService{Name: "bs"}}
+	get := func() {
+		c.Intercept(ctx, callInfo("BackendServices", "Get", meta.VersionGA, key), api.handler)
+	}
+
+	// A miss, two hits 10s and 20s after the miss and an expired entry.
+	get()
+	for i := 0; i < 2; i++ {
+		now = now.Add(10 * time.Second)
+		get()
+	}
+	now = now.Add(time.Minute)
+	get()
+	c.Invalidate("proj", "BackendServices", key)
+
+	info := callInfo("Addresses", "List", meta.VersionGA, nil)
+	info.Args = []interface{}{"us-central1", filter.None}
+	list := &fakeAPI{obj: []*ga.Address{}}
+	c.Intercept(ctx, info, list.handler)
+
+	got := c.Stats()
+	want := Counters{Hits: 2, Misses: 2, Expired: 1, Evictions: 1, HitAge: 30 * time.Second}
+	if bs := got.Objects["BackendServices"]; bs == nil || *bs != want {
+		t.Errorf("Stats().Objects[BackendServices] = %+v, want %+v", bs, want)
+	}
+	if r := got.Objects["BackendServices"].HitRatio(); r != 0.5 {
+		t.Errorf("HitRatio() = %v, want 0.5", r)
+	}
+	if a := got.Objects["BackendServices"].MeanHitAge(); a != 15*time.Second {
+		t.Errorf("MeanHitAge() = %v, want 15s", a)
+	}
+	if a := got.Lists["Addresses"]; a == nil || a.Misses != 1 {
+		t.Errorf("Stats().Lists[Addresses] = %+v, want 1 miss", a)
+	}
+	if got.ObjectEntries != 0 || got.ListEntries != 1 {
+		t.Errorf("Stats() entries = %d, %d, want 0, 1", got.ObjectEntries, got.ListEntries)
+	}
+
+	get()
+	now = now.Add(5 * time.Second)
+	entries := c.Dump()
+	wantEntries := []Entry{
+		{Project: "proj", Version: meta.VersionGA, Resource: "BackendServices", Key: key.String(), Age: 5 * time.Second, ExpiresIn: 55 * time.Second},
+		{Project: "proj", Version: meta.VersionGA, Resource: "Addresses", Scope: "us-central1", Age: 5 * time.Second, ExpiresIn: 55 * time.Second},
+	}
+	if len(entries) != len(wantEntries) {
+		t.Fatalf("Dump() = %+v, want %+v", entries, wantEntries)
+	}
+	for i := range entries {
+		if entries[i] != wantEntries[i] {
+			t.Errorf("Dump()[%d] = %+v, want %+v", i, entries[i], wantEntries[i])
+		}
+	}
+
+	w := httptest.NewRecorder()
+	c.ServeHTTP(w, httptest.NewRequest("GET", "/debug/cache?entries", nil))
+	var out struct {
+		Stats   Stats
+		Entries []Entry
+	}
+	if err := json.NewDecoder(w.Body).Decode(&out); err != nil {
+		t.Fatalf("ServeHTTP() returned invalid JSON: %v", err)
+	}
+	if out.Stats.Objects["BackendServices"].Hits != 2 || len(out.Entries) != 2 {
+		t.Errorf("ServeHTTP() = %+v, want 2 hits and 2 entries", out)
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"k8s.io/klog/v2"
+)
+
+// DefaultWarmParallelism is the default of Config.WarmParallelism.
+const DefaultWarmParallelism = 8
+
+// Warm fills the Cache with the objects of resources, so the Gets made
+// after a restart are served by the Cache. resources are the names of the
+// methods of cloud.Cloud, e.g. "BackendServices" or "BetaAddresses".
+//
+// The objects are listed with AggregatedList if the resource has one and
+// with a List of each scope otherwise, listing up to
+// Config.WarmParallelism scopes at a time. scopes are regions and zones (e.g.
+// "us-central1"); an AggregatedList only keeps the objects in scopes,
+// unless scopes is empty. Global objects are always kept.
+//
+//	err := c.Warm(ctx, gce, []string{"BackendServices", "ForwardingRules"}, []string{"us-central1"})
+func (c *Cache) Warm(ctx context.Context, gce cloud.Cloud, resources, scopes []string) error {
+	var errs []error
+	for _, r := range resources {
+		if err := c.warm(ctx, gce, r, scopes); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Cache) warm(ctx context.Context, gce cloud.Cloud, resource string, scopes []string) error {
+	var info *meta.ServiceInfo
+	for _, s := range meta.AllServices {
+		if s.WrapType() == resource {
+			info = s
+		}
+	}
+	m := reflect.ValueOf(gce).MethodByName(resource)
+	if info == nil || !m.IsValid() {
+		return fmt.Errorf("unknown resource")
+	}
+	svc := m.Call(nil)[0]
+
+	var objs []reflect.Value
+	fl := reflect.ValueOf(filter.None)
+	if agg := svc.MethodByName("AggregatedList"); agg.IsValid() {
+		res := agg.Call([]reflect.Value{reflect.ValueOf(ctx), fl})
+		if err, _ := res[1].Interface().(error); err != nil {
+			return err
+		}
+		iter := res[0].MapRange()
+		for iter.Next() {
+			for i := 0; i < iter.Value().Len(); i++ {
+				objs = append(objs, iter.Value().Index(i))
+			}
+		}
+	} else if list := svc.MethodByName("List"); list.IsValid() {
+		values := func(res []reflect.Value) ([]reflect.Value, error) {
+			if err, _ := res[1].Interface().(error); err != nil {
+				return nil, err
+			}
+			var ret []reflect.Value
+			for i := 0; i < res[0].Len(); i++ {
+				ret = append(ret, res[0].Index(i))
+			}
+			return ret, nil
+		}
+		var err error
+		if list.Type().NumIn() == 2 {
+			objs, err = values(list.Call([]reflect.Value{reflect.ValueOf(ctx), fl}))
+		} else {
+			parallelism := c.config.WarmParallelism
+			if parallelism <= 0 {
+				parallelism = DefaultWarmParallelism
+			}
+			objs, err = cloud.ListScopes(ctx, scopes, parallelism, func(ctx context.Context, scope string) ([]reflect.Value, error) {
+				return values(list.Call([]reflect.Value{reflect.ValueOf(ctx), reflect.ValueOf(scope), fl}))
+			})
+		}
+		if err != nil {
+			return err
+		}
+	} else {
+		return fmt.Errorf("resource cannot be listed")
+	}
+
+	inScope := map[string]bool{}
+	for _, s := range scopes {
+		inScope[s] = true
+	}
+	var n int
+	for _, obj := range objs {
+		k, ok := warmKey(info.Version(), obj.Interface())
+		if !ok {
+			continue
+		}
+		scope := k.Object.Region + k.Object.Zone
+		if scope != "" && len(scopes) > 0 && !inScope[scope] {
+			continue
+		}
+		ttl := c.config.objectTTL(k.Resource)
+		if ttl <= 0 {
+			continue
+		}
+		c.putObject(ctx, k, &Item{Value: cloud.DeepCopy(obj.Interface())}, ttl)
+		n++
+	}
+	klog.V(2).Infof("Cache.Warm(%s): cached %d of %d objects", resource, n, len(objs))
+	return nil
+}
+
+// warmKey returns the Key of the Get of obj in version. The resource of the
+// Key is the service that has the scope of obj, e.g. "RegionBackendServices"
+// for a regional BackendService returned by the AggregatedList of
+// "BackendServices".
+func warmKey(version meta.Version, obj interface{}) (Key, bool) {
+	v := reflect.Indirect(reflect.ValueOf(obj))
+	if v.Kind() != reflect.Struct {
+		return Key{}, false
+	}
+	f := v.FieldByName("SelfLink")
+	if !f.IsValid() || f.Kind() != reflect.String {
+		return Key{}, false
+	}
+	id, err := cloud.ParseResourceURL(f.String())
+	if err != nil || id.Key == nil {
+		return Key{}, false
+	}
+	for _, s := range meta.AllServices {
+		if s.Version() != version || s.Resource != id.Resource || !s.GenerateGet() {
+			continue
+		}
+		if (id.Key.Type() == meta.Global && s.KeyIsGlobal()) ||
+			(id.Key.Type() == meta.Regional && s.KeyIsRegional()) ||
+			(id.Key.Type() == meta.Zonal && s.KeyIsZonal()) {
+			return Key{Project: id.ProjectID, Version: version, Resource: s.Service, Object: *id.Key}, true
+		}
+	}
+	return Key{}, false
+}
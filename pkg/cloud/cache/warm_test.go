@@ -0,0 +1,103 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestCacheWarm(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	keys := map[string]*meta.Key{
+		"regional":     meta.RegionalKey("a1", "us-central1"),
+		"other region": meta.RegionalKey("a2", "europe-west1"),
+		"global":       meta.GlobalKey("ga1"),
+		"other global": meta.GlobalKey("ga2"),
+	}
+	for name, key := range keys {
+		var err error
+		if key.Type() == meta.Global {
+			err = mock.GlobalAddresses().Insert(ctx, key, &ga.Address{})
+		} else {
+			err = mock.Addresses().Insert(ctx, key, &ga.Address{})
+		}
+		if err != nil {
+			t.Fatalf("Insert(%s) = %v", name, err)
+		}
+	}
+
+	c := New(Config{Default: Policy{TTL: time.Minute}})
+	if err := c.Warm(ctx, mock, []string{"Addresses", "GlobalAddresses"}, []string{"us-central1"}); err != nil {
+		t.Fatalf("Warm() = %v, want nil", err)
+	}
+	if err := c.Warm(ctx, mock, []string{"Unknown"}, nil); err == nil {
+		t.Errorf("Warm(Unknown) = nil, want error")
+	}
+
+	for _, tc := range []struct {
+		name    string
+		service string
+		want    bool
+	}{
+		{name: "regional", service: "Addresses", want: true},
+		{name: "other region", service: "Addresses", want: false},
+		{name: "global", service: "GlobalAddresses", want: true},
+		{name: "other global", service: "GlobalAddresses", want: true},
+	} {
+		api := &fakeAPI{obj: &ga.Address{}}
+		c.Intercept(ctx, callInfo(tc.service, "Get", meta.VersionGA, keys[tc.name]), api.handler)
+		if got := api.calls == 0; got != tc.want {
+			t.Errorf("%s: Get() served from the Cache = %t, want %t", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestCacheWarmScopes(t *testing.T) {
+	t.Parallel()
+
+	// RegionBackendServices has no AggregatedList, so each scope is
+	// listed.
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	regions := []string{"us-central1", "us-east1", "europe-west1"}
+	for _, region := range regions {
+		if err := mock.RegionBackendServices().Insert(ctx, meta.RegionalKey("bs", region), &ga.BackendService{}); err != nil {
+			t.Fatalf("Insert() = %v", err)
+		}
+	}
+
+	c := New(Config{Default: Policy{TTL: time.Minute}, WarmParallelism: 2})
+	if err := c.Warm(ctx, mock, []string{"RegionBackendServices"}, regions[:2]); err != nil {
+		t.Fatalf("Warm() = %v, want nil", err)
+	}
+	for i, region := range regions {
+		api := &fakeAPI{obj: &ga.BackendService{}}
+		c.Intercept(ctx, callInfo("RegionBackendServices", "Get", meta.VersionGA, meta.RegionalKey("bs", region)), api.handler)
+		if got, want := api.calls == 0, i < 2; got != want {
+			t.Errorf("%s: Get() served from the Cache = %t, want %t", region, got, want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/gcerrors"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"k8s.io/klog/v2"
+)
+
+// isOperationConflict is true if err is a 409 that is not for a resource
+// that already exists, i.e. another operation on the resource is running.
+func isOperationConflict(err error) bool {
+	return gcerrors.IsConflict(err) && !gcerrors.IsAlreadyExists(err)
+}
+
+// waitForConflictingOperation waits for the operations that are running on
+// the resource of the call. It does not return an error if there is none
+// (e.g. it is already done).
+func (s *Service) waitForConflictingOperation(ctx context.Context, info *CallInfo) error {
+	if s.GA == nil {
+		return fmt.Errorf("no GA client to list the operations")
+	}
+	projectID := info.ProjectID
+	if projectID == "" {
+		projectID = s.ProjectRouter.ProjectID(ctx, info.Version, info.Service)
+	}
+	target := RelativeResourceName(projectID, resourceName(info.Service), info.Key)
+	fl := filter.Regexp("targetLink", ".*/"+regexp.QuoteMeta(target)).AndNotRegexp("status", "DONE")
+
+	var ops []*ga.Operation
+	var err error
+	switch info.Key.Type() {
+	case meta.Global:
+		var l *ga.OperationList
+		l, err = s.GA.GlobalOperations.List(projectID).Filter(fl.String()).Context(ctx).Do()
+		if l != nil {
+			ops = l.Items
+		}
+	case meta.Regional:
+		var l *ga.OperationList
+		l, err = s.GA.RegionOperations.List(projectID, info.Key.Region).Filter(fl.String()).Context(ctx).Do()
+		if l != nil {
+			ops = l.Items
+		}
+	case meta.Zonal:
+		var l *ga.OperationList
+		l, err = s.GA.ZoneOperations.List(projectID, info.Key.Zone).Filter(fl.String()).Context(ctx).Do()
+		if l != nil {
+			ops = l.Items
+		}
+	default:
+		return fmt.Errorf("invalid key type: %#v", info.Key)
+	}
+	if err != nil {
+		return err
+	}
+	for _, op := range ops {
+		klog.V(2).Infof("%s.%s(%v): waiting for the conflicting operation %s (%s)", info.Service, info.Operation, info.Key, op.Name, op.OperationType)
+		// The result of the other operation does not matter, only that it
+		// is done.
+		if err := s.WaitForCompletion(ctx, op); err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			klog.V(2).Infof("%s.%s(%v): conflicting operation %s: %v", info.Service, info.Operation, info.Key, op.Name, err)
+		}
+	}
+	return nil
+}
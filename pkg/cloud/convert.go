@@ -0,0 +1,166 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/internal/objutil"
+)
+
+// ConversionLoss describes the fields that are not preserved when an object
+// is converted between API versions (e.g. from alpha to ga). Fields are
+// named by their dot-separated JSON path (e.g. "iap.oauth2ClientInfo").
+type ConversionLoss struct {
+	// Dropped are the fields that are set in the source object but do not
+	// exist in the destination version. Their values are lost.
+	Dropped []string
+	// Defaulted are the fields of the destination version that do not exist
+	// in the source version. They are left at their zero value.
+	Defaulted []string
+}
+
+// Empty returns true if the conversion did not drop any fields. Defaulted
+// fields are not considered, as converting to a version with additional
+// fields is always lossless.
+func (l *ConversionLoss) Empty() bool {
+	return l == nil || len(l.Dropped) == 0
+}
+
+// String implements fmt.Stringer.
+func (l *ConversionLoss) String() string {
+	if l == nil {
+		return "{}"
+	}
+	return fmt.Sprintf("{Dropped: %v, Defaulted: %v}", l.Dropped, l.Defaulted)
+}
+
+func (l *ConversionLoss) addDropped(path string) {
+	for _, p := range l.Dropped {
+		if p == path {
+			return
+		}
+	}
+	l.Dropped = append(l.Dropped, path)
+}
+
+func (l *ConversionLoss) addDefaulted(path string) {
+	for _, p := range l.Defaulted {
+		if p == path {
+			return
+		}
+	}
+	l.Defaulted = append(l.Defaulted, path)
+}
+
+// joinPath returns the JSON path of the field name of the struct at path.
+// The generated converters use it to name the fields of the ConversionLoss.
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// metaFields returns a copy of the ForceSendFields or NullFields of an object
+// without duplicates and without the entries naming the dropped fields. An
+// entry may name a map key (e.g. "Labels.app"), in which case the field is
+// the part before the ".". The generated converters use it for the fields
+// that do not exist in the destination version.
+func metaFields(fields []string, dropped ...string) []string {
+	var ret []string
+	added := map[string]bool{}
+	for _, fn := range fields {
+		field := strings.SplitN(fn, ".", 2)[0]
+		keep := !added[fn]
+		for _, d := range dropped {
+			if field == d {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			ret = append(ret, fn)
+			added[fn] = true
+		}
+	}
+	return ret
+}
+
+// Convert copies src into dest, matching the fields of the structs by name
+// like the conversions between API versions (e.g. BackendServiceToGA).
+// dest is a pointer to a struct or a slice, and src is a pointer to a
+// struct or a slice, e.g. two versions of []*NetworkEndpoint or an object
+// and its Usable variant. The returned ConversionLoss lists the fields
+// that could not be copied. The result does not share any memory with src.
+func Convert(dest, src interface{}) (*ConversionLoss, error) {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return nil, fmt.Errorf("Convert: dest %T is not a non-nil pointer", dest)
+	}
+	sv := reflect.ValueOf(src)
+	if sv.Kind() == reflect.Ptr && dv.Elem().Kind() == reflect.Struct {
+		if sv.IsNil() {
+			return &ConversionLoss{}, nil
+		}
+		sv = sv.Elem()
+	}
+	if sv.Kind() != dv.Elem().Kind() {
+		return nil, fmt.Errorf("Convert: cannot convert %T to %T", src, dest)
+	}
+	loss := &ConversionLoss{}
+	convertValue(dv.Elem(), sv, loss)
+	return loss, nil
+}
+
+// DeepCopy returns a deep copy of obj, a pointer to an API object (e.g.
+// *ga.BackendService), with the same type. It is the untyped version of the
+// DeepCopy functions, e.g. DeepCopyBackendService.
+func DeepCopy(obj interface{}) interface{} {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return obj
+	}
+	ret := reflect.New(v.Elem().Type())
+	convertValue(ret.Elem(), v.Elem(), &ConversionLoss{})
+	return ret.Interface()
+}
+
+// convertValue copies src into dest with the fields matched by name, adding
+// the fields that could not be copied to loss.
+func convertValue(dest, src reflect.Value, loss *ConversionLoss) {
+	c := &objutil.Copier{
+		Lax: true,
+		Missing: func(p objutil.Path, _ any) {
+			// Metafield entries for fields that don't exist in dest are
+			// dropped with the field.
+			for _, x := range p {
+				if x == ".ForceSendFields" || x == ".NullFields" {
+					return
+				}
+			}
+			loss.addDropped(p.JSONFields(src.Type()))
+		},
+		Defaulted: func(p objutil.Path) {
+			loss.addDefaulted(p.JSONFields(dest.Type()))
+		},
+	}
+	// Lax copies drop the values that cannot be copied instead of failing.
+	_ = c.Copy(dest, src)
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+func TestBackendServiceConversion(t *testing.T) {
+	t.Parallel()
+
+	src := &alpha.BackendService{
+		Name:            "bs",
+		TimeoutSec:      30,
+		ServiceLbPolicy: "policy",
+		Backends:        []*alpha.Backend{{Group: "ig", MaxUtilization: 0.5}},
+		Iap:             &alpha.BackendServiceIAP{Enabled: true},
+		ForceSendFields: []string{"EnableCDN", "ServiceLbPolicy"},
+	}
+	got, loss, err := BackendServiceToGA(src)
+	if err != nil {
+		t.Fatalf("BackendServiceToGA() = _, _, %v; want nil", err)
+	}
+	want := &ga.BackendService{
+		Name:            "bs",
+		TimeoutSec:      30,
+		Backends:        []*ga.Backend{{Group: "ig", MaxUtilization: 0.5}},
+		Iap:             &ga.BackendServiceIAP{Enabled: true},
+		ForceSendFields: []string{"EnableCDN"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("BackendServiceToGA() diff -want +got: %s", diff)
+	}
+	if diff := cmp.Diff([]string{"serviceLbPolicy"}, loss.Dropped); diff != "" || loss.Empty() {
+		t.Errorf("BackendServiceToGA() loss = %v; diff -want +got: %s", loss, diff)
+	}
+
+	// The result must not share memory with the source.
+	got.Backends[0].Group = "changed"
+	if src.Backends[0].Group != "ig" {
+		t.Errorf("src.Backends[0].Group = %q, want ig", src.Backends[0].Group)
+	}
+
+	// Converting up to alpha is lossless, alpha only fields are defaulted.
+	back, loss, err := BackendServiceToAlpha(got)
+	if err != nil || !loss.Empty() {
+		t.Fatalf("BackendServiceToAlpha() = _, %v, %v; want empty loss, nil", loss, err)
+	}
+	if back.TimeoutSec != 30 || back.ServiceLbPolicy != "" {
+		t.Errorf("BackendServiceToAlpha() = %+v", back)
+	}
+	found := false
+	for _, f := range loss.Defaulted {
+		found = found || f == "serviceLbPolicy"
+	}
+	if !found {
+		t.Errorf("BackendServiceToAlpha() loss.Defaulted = %v, want serviceLbPolicy", loss.Defaulted)
+	}
+
+	// Same version is returned as-is.
+	if same, _, _ := BackendServiceToAlpha(src); same != src {
+		t.Errorf("BackendServiceToAlpha(alpha) = %p, want %p", same, src)
+	}
+	if _, _, err := BackendServiceToGA(&ga.Address{}); err == nil {
+		t.Errorf("BackendServiceToGA(*ga.Address) = _, _, nil; want error")
+	}
+	if obj, _, err := BackendServiceToGA((*alpha.BackendService)(nil)); obj != nil || err != nil {
+		t.Errorf("BackendServiceToGA(nil) = %v, _, %v; want nil, _, nil", obj, err)
+	}
+}
+
+func TestConversionMatchesConvert(t *testing.T) {
+	t.Parallel()
+
+	src := &alpha.ForwardingRule{
+		Name:                          "fr",
+		AllowPscGlobalAccess:          true,
+		Ports:                         []string{},
+		Labels:                        map[string]string{"k": "v"},
+		ServiceDirectoryRegistrations: []*alpha.ForwardingRuleServiceDirectoryRegistration{{Namespace: "ns"}},
+		ForceSendFields:               []string{"Name", "Name", "AllowPscGlobalAccess"},
+		NullFields:                    []string{"Labels.k"},
+	}
+	got, gotLoss, err := ForwardingRuleToGA(src)
+	if err != nil {
+		t.Fatalf("ForwardingRuleToGA() = _, _, %v; want nil", err)
+	}
+	want := &ga.ForwardingRule{}
+	wantLoss, err := Convert(want, src)
+	if err != nil {
+		t.Fatalf("Convert() = _, %v; want nil", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ForwardingRuleToGA() diff -Convert() +got: %s", diff)
+	}
+	sortStrings := cmp.Transformer("sort", func(in []string) []string {
+		out := append([]string(nil), in...)
+		sort.Strings(out)
+		return out
+	})
+	if diff := cmp.Diff(wantLoss, gotLoss, sortStrings); diff != "" {
+		t.Errorf("ForwardingRuleToGA() loss diff -Convert() +got: %s", diff)
+	}
+}
+
+func TestDeepCopy(t *testing.T) {
+	t.Parallel()
+
+	src := &ga.BackendService{
+		Name:                 "bs",
+		Backends:             []*ga.Backend{{Group: "ig"}},
+		CustomRequestHeaders: []string{"h1"},
+		Iap:                  &ga.BackendServiceIAP{Enabled: true},
+		ConsistentHash:       &ga.ConsistentHashLoadBalancerSettings{HttpCookie: &ga.ConsistentHashLoadBalancerSettingsHttpCookie{Name: "c"}},
+		NullFields:           []string{"Description"},
+		ServerResponse:       googleapi.ServerResponse{HTTPStatusCode: 200},
+	}
+	got := DeepCopyBackendService(src)
+	if diff := cmp.Diff(src, got); diff != "" {
+		t.Fatalf("DeepCopyBackendService() diff -want +got: %s", diff)
+	}
+	got.Backends[0].Group = "changed"
+	got.CustomRequestHeaders[0] = "changed"
+	got.Iap.Enabled = false
+	got.ConsistentHash.HttpCookie.Name = "changed"
+	if src.Backends[0].Group != "ig" || src.CustomRequestHeaders[0] != "h1" || !src.Iap.Enabled || src.ConsistentHash.HttpCookie.Name != "c" {
+		t.Errorf("modifying the copy changed the source: %+v", src)
+	}
+	if DeepCopyAlphaBackendService(nil) != nil {
+		t.Errorf("DeepCopyAlphaBackendService(nil) != nil")
+	}
+
+	untyped, ok := DeepCopy(src).(*ga.BackendService)
+	if !ok || untyped == src {
+		t.Fatalf("DeepCopy() = %T %p, want a new *ga.BackendService", untyped, untyped)
+	}
+	if diff := cmp.Diff(src, untyped); diff != "" {
+		t.Errorf("DeepCopy() diff -want +got: %s", diff)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	type A struct {
+		A, B, C string
+	}
+	type B struct {
+		A, B, D string
+	}
+	var b B
+	loss, err := Convert(&b, &A{"aa", "bb", "cc"})
+	if err != nil {
+		t.Fatalf("Convert() = _, %v, want nil", err)
+	}
+	if want := (B{A: "aa", B: "bb"}); b != want {
+		t.Errorf("Convert() = %+v, want %+v", b, want)
+	}
+	if diff := cmp.Diff(&ConversionLoss{Dropped: []string{"C"}, Defaulted: []string{"D"}}, loss); diff != "" {
+		t.Errorf("Convert() loss: -want +got: %s", diff)
+	}
+
+	src := []*ga.NetworkEndpoint{{IpAddress: "10.0.0.1", Port: 80}, {Instance: "vm"}}
+	var eps []*alpha.NetworkEndpoint
+	if _, err := Convert(&eps, src); err != nil {
+		t.Fatalf("Convert() = _, %v, want nil", err)
+	}
+	want := []*alpha.NetworkEndpoint{{IpAddress: "10.0.0.1", Port: 80}, {Instance: "vm"}}
+	if diff := cmp.Diff(want, eps); diff != "" {
+		t.Errorf("Convert(): -want +got: %s", diff)
+	}
+	src[0].Port = 8080
+	if eps[0].Port != 80 {
+		t.Errorf("eps[0].Port = %d, want 80", eps[0].Port)
+	}
+
+	if _, err := Convert(&eps, &A{}); err == nil {
+		t.Errorf("Convert(slice, struct) = _, nil, want error")
+	}
+	if _, err := Convert(b, &A{}); err == nil {
+		t.Errorf("Convert(not a pointer, _) = _, nil, want error")
+	}
+}
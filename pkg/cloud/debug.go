@@ -0,0 +1,230 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/go-logr/logr"
+)
+
+const redacted = "REDACTED"
+
+var (
+	// redactedHeaders are not logged by the DebugTransport.
+	redactedHeaders = []string{
+		"Authorization",
+		"Proxy-Authorization",
+		"Cookie",
+		"Set-Cookie",
+		"X-Goog-Api-Key",
+	}
+	// redactedParams are URL query parameters that are not logged by the
+	// DebugTransport.
+	redactedParams = []string{"key", "access_token"}
+	// redactedFields are JSON fields of the request and response bodies
+	// that are not logged by the DebugTransport.
+	redactedFields = map[string]bool{
+		"privateKey":       true,
+		"sharedSecret":     true,
+		"sharedSecretHash": true,
+		"password":         true,
+		"accessToken":      true,
+		"rawKey":           true,
+		"rsaEncryptedKey":  true,
+	}
+)
+
+// CallSelector selects calls by resource and verb. An empty field matches
+// any value.
+type CallSelector struct {
+	// Service is the resource, e.g. "BackendServices".
+	Service string
+	// Operation is the verb, e.g. "Insert".
+	Operation string
+}
+
+func (sel *CallSelector) match(ck *CallContextKey) bool {
+	if ck == nil {
+		// Calls not made through Cloud (e.g. polling operations) only
+		// match the selector for everything.
+		return sel.Service == "" && sel.Operation == ""
+	}
+	return (sel.Service == "" || sel.Service == ck.Service) &&
+		(sel.Operation == "" || sel.Operation == ck.Operation)
+}
+
+// DebugTransport is an http.RoundTripper that logs the full HTTP requests
+// and responses of selected calls. Credentials in the headers and URL and
+// sensitive fields of the bodies (private keys, shared secrets, metadata
+// item values) are redacted. Dumping is off until Enable is called and can
+// be toggled at runtime.
+//
+//	dt := cloud.NewDebugTransport(http.DefaultTransport, logger)
+//	client := &http.Client{Transport: dt}
+//	// Build the compute client with option.WithHTTPClient(client).
+//	...
+//	dt.Enable(cloud.CallSelector{Service: "BackendServices", Operation: "Patch"})
+type DebugTransport struct {
+	base http.RoundTripper
+	log  logr.Logger
+
+	lock      sync.RWMutex
+	selectors []CallSelector
+}
+
+// NewDebugTransport returns a DebugTransport that sends requests with base
+// and logs to log.
+func NewDebugTransport(base http.RoundTripper, log logr.Logger) *DebugTransport {
+	return &DebugTransport{base: base, log: log}
+}
+
+// Enable dumping of the calls matching any of selectors. This replaces the
+// previously enabled selectors. Enable() with no selectors is equivalent to
+// Disable().
+func (t *DebugTransport) Enable(selectors ...CallSelector) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.selectors = append([]CallSelector(nil), selectors...)
+}
+
+// Disable dumping.
+func (t *DebugTransport) Disable() {
+	t.Enable()
+}
+
+func (t *DebugTransport) enabled(ctx context.Context) bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if len(t.selectors) == 0 {
+		return false
+	}
+	var ck *CallContextKey
+	if state, ok := ctx.Value(callStateContextKey).(*callState); ok {
+		ck = state.ck
+	}
+	for i := range t.selectors {
+		if t.selectors[i].match(ck) {
+			return true
+		}
+	}
+	return false
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *DebugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.enabled(req.Context()) {
+		return t.base.RoundTrip(req)
+	}
+
+	var err error
+	var reqBody []byte
+	if req.Body != nil {
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	u := *req.URL
+	q := u.Query()
+	for _, p := range redactedParams {
+		if q.Has(p) {
+			q.Set(p, redacted)
+		}
+	}
+	u.RawQuery = q.Encode()
+	t.log.Info("HTTP request",
+		"method", req.Method,
+		"url", u.String(),
+		"header", redactHeader(req.Header),
+		"body", redactBody(reqBody))
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.log.Info("HTTP response", "url", u.String(), "err", err)
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	t.log.Info("HTTP response",
+		"url", u.String(),
+		"status", resp.StatusCode,
+		"header", redactHeader(resp.Header),
+		"body", redactBody(respBody))
+
+	return resp, nil
+}
+
+func redactHeader(h http.Header) http.Header {
+	h = h.Clone()
+	for _, k := range redactedHeaders {
+		if h.Get(k) != "" {
+			h.Set(k, redacted)
+		}
+	}
+	return h
+}
+
+// redactBody returns the body with the redactedFields and the values of
+// key/value items (e.g. metadata) redacted. Bodies that are not JSON are
+// not logged.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return redacted
+	}
+	out, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return redacted
+	}
+	return string(out)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		_, hasKey := v["key"]
+		for k, fv := range v {
+			if redactedFields[k] || (hasKey && k == "value") {
+				v[k] = redacted
+				continue
+			}
+			v[k] = redactValue(fv)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+	}
+	return v
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+)
+
+func TestRedactBody(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		body string
+		want string
+	}{
+		{body: "", want: ""},
+		{body: "not json", want: redacted},
+		{
+			body: `{"name":"c","privateKey":"secret","certificate":"cert"}`,
+			want: `{"certificate":"cert","name":"c","privateKey":"REDACTED"}`,
+		},
+		{
+			body: `{"metadata":{"items":[{"key":"ssh-keys","value":"user:ssh-rsa AAAA"}]}}`,
+			want: `{"metadata":{"items":[{"key":"ssh-keys","value":"REDACTED"}]}}`,
+		},
+		{
+			body: `{"items":[{"sharedSecret":"s","labels":{"value":"v"}}]}`,
+			want: `{"items":[{"labels":{"value":"v"},"sharedSecret":"REDACTED"}]}`,
+		},
+	} {
+		if got := redactBody([]byte(tc.body)); got != tc.want {
+			t.Errorf("redactBody(%q) = %q, want %q", tc.body, got, tc.want)
+		}
+	}
+}
+
+func TestDebugTransport(t *testing.T) {
+	t.Parallel()
+
+	var gotBody map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			json.NewDecoder(r.Body).Decode(&gotBody)
+		}
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.Write([]byte(`{"name":"c1","privateKey":"response-secret"}`))
+	}))
+	defer srv.Close()
+
+	log := newFakeLogger()
+	dt := NewDebugTransport(http.DefaultTransport, log.logger())
+	ctx := context.Background()
+	client, err := ga.NewService(ctx,
+		option.WithEndpoint(srv.URL),
+		option.WithHTTPClient(&http.Client{Transport: dt}))
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+	c := NewGCE(&Service{
+		GA:            client,
+		ProjectRouter: &SingleProjectRouter{"proj"},
+		RateLimiter:   &NopRateLimiter{},
+	})
+	key := meta.GlobalKey("c1")
+
+	// Disabled by default.
+	if _, err := c.SslCertificates().Get(ctx, key); err != nil {
+		t.Fatalf("Get(%v) = _, %v, want nil", key, err)
+	}
+	if n := len(log.find("HTTP request")); n != 0 {
+		t.Errorf("got %d requests logged while disabled, want 0", n)
+	}
+
+	dt.Enable(CallSelector{Service: "SslCertificates", Operation: "Get"})
+	if _, err := c.SslCertificates().Get(ctx, key); err != nil {
+		t.Fatalf("Get(%v) = _, %v, want nil", key, err)
+	}
+	// Not selected.
+	if _, err := c.SslCertificates().List(ctx, nil); err != nil {
+		t.Fatalf("List() = _, %v, want nil", err)
+	}
+	reqs := log.find("HTTP request")
+	resps := log.find("HTTP response")
+	if len(reqs) != 1 || len(resps) != 1 {
+		t.Fatalf("got %d requests, %d responses logged, want 1, 1", len(reqs), len(resps))
+	}
+	if got := resps[0].kv["header"].(http.Header).Get("Set-Cookie"); got != redacted {
+		t.Errorf("response Set-Cookie = %q, want %q", got, redacted)
+	}
+	if got := resps[0].kv["body"].(string); strings.Contains(got, "response-secret") || !strings.Contains(got, `"name":"c1"`) {
+		t.Errorf("response body = %q, want the privateKey redacted", got)
+	}
+
+	// The body sent to the server is not redacted.
+	dt.Enable(CallSelector{Operation: "Insert"})
+	// The fake server does not return an Operation, so the error from
+	// waiting for it is ignored.
+	c.SslCertificates().Insert(ctx, key, &ga.SslCertificate{PrivateKey: "request-secret"})
+	if gotBody["privateKey"] != "request-secret" {
+		t.Errorf("server got privateKey %v, want %q", gotBody["privateKey"], "request-secret")
+	}
+	reqs = log.find("HTTP request")
+	if len(reqs) != 2 {
+		t.Fatalf("got %d requests logged, want 2", len(reqs))
+	}
+	if got := reqs[1].kv["body"].(string); strings.Contains(got, "request-secret") {
+		t.Errorf("request body = %q, want the privateKey redacted", got)
+	}
+
+	dt.Disable()
+	if _, err := c.SslCertificates().Get(ctx, key); err != nil {
+		t.Fatalf("Get(%v) = _, %v, want nil", key, err)
+	}
+	if n := len(log.find("HTTP request")); n != 2 {
+		t.Errorf("got %d requests logged after Disable(), want 2", n)
+	}
+}
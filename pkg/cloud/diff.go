@@ -0,0 +1,87 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/internal/objutil"
+)
+
+// FieldDiff is a difference in a field between two objects.
+type FieldDiff struct {
+	// Path is the JSON path of the field (e.g. "pathMatchers[0].defaultService"
+	// or "labels.app"). Lists that are compared as sets are reported as a
+	// whole (e.g. "backends").
+	Path string
+	// A and B are the values of the field in each object.
+	A, B interface{}
+}
+
+// String implements fmt.Stringer.
+func (d FieldDiff) String() string {
+	return fmt.Sprintf("%s: %+v != %+v", d.Path, d.A, d.B)
+}
+
+// diffOptions configures diffObjects for an object type. The paths do not
+// contain list indices (e.g. "pathMatchers.pathRules").
+type diffOptions struct {
+	// ignore are fields that are not compared, i.e. output only and server
+	// populated fields.
+	ignore map[string]bool
+	// unordered are lists that are compared as sets.
+	unordered map[string]bool
+}
+
+// diffIgnoreFields are never compared, at any level of the object.
+var diffIgnoreFields = map[string]bool{
+	"fingerprint":      true,
+	"labelFingerprint": true,
+	"ForceSendFields":  true,
+	"NullFields":       true,
+	"ServerResponse":   true,
+}
+
+// diffObjects returns the differences between a and b, which must be
+// pointers to the same struct type. A nil pointer is equivalent to the zero
+// value, as are nil and empty lists and maps.
+func diffObjects(a, b interface{}, opts *diffOptions) []FieldDiff {
+	t := reflect.TypeOf(a)
+	d := &objutil.Differ{
+		Ignore: func(p objutil.Path) bool {
+			key := p.JSONFields(t)
+			name := key[strings.LastIndex(key, ".")+1:]
+			return diffIgnoreFields[p[len(p)-1][1:]] || diffIgnoreFields[name] || opts.ignore[key]
+		},
+		Unordered: func(p objutil.Path) bool {
+			return opts.unordered[p.JSONFields(t)]
+		},
+		NilIsZero: true,
+	}
+	items, err := d.Diff(reflect.ValueOf(a), reflect.ValueOf(b))
+	if err != nil {
+		// The API objects only contain types supported by the differ.
+		return []FieldDiff{{A: a, B: b}}
+	}
+	var ret []FieldDiff
+	for _, item := range items {
+		ret = append(ret, FieldDiff{Path: item.Path.JSONPath(t), A: item.A, B: item.B})
+	}
+	return ret
+}
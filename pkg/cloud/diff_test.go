@@ -0,0 +1,148 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"testing"
+
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestDiffBackendService(t *testing.T) {
+	t.Parallel()
+
+	base := func() *ga.BackendService {
+		return &ga.BackendService{
+			Name:       "bs",
+			TimeoutSec: 30,
+			Backends: []*ga.Backend{
+				{Group: "ig-a", BalancingMode: "UTILIZATION"},
+				{Group: "ig-b", BalancingMode: "UTILIZATION"},
+			},
+			HealthChecks: []string{"hc"},
+		}
+	}
+
+	for _, tc := range []struct {
+		desc   string
+		modify func(*ga.BackendService)
+		want   []string
+	}{
+		{
+			desc:   "equal",
+			modify: func(*ga.BackendService) {},
+		},
+		{
+			desc: "server populated fields",
+			modify: func(bs *ga.BackendService) {
+				bs.SelfLink = "https://www.googleapis.com/compute/v1/projects/p/global/backendServices/bs"
+				bs.Fingerprint = "abc"
+				bs.CreationTimestamp = "2023-01-01T00:00:00Z"
+				bs.Id = 123
+				bs.Kind = "compute#backendService"
+			},
+		},
+		{
+			desc: "reordered backends",
+			modify: func(bs *ga.BackendService) {
+				bs.Backends[0], bs.Backends[1] = bs.Backends[1], bs.Backends[0]
+			},
+		},
+		{
+			desc: "nil and empty are equal",
+			modify: func(bs *ga.BackendService) {
+				bs.CustomRequestHeaders = []string{}
+				bs.Iap = &ga.BackendServiceIAP{}
+			},
+		},
+		{
+			desc: "changed backend",
+			modify: func(bs *ga.BackendService) {
+				bs.Backends[1].BalancingMode = "RATE"
+			},
+			want: []string{"backends"},
+		},
+		{
+			desc: "changed fields",
+			modify: func(bs *ga.BackendService) {
+				bs.TimeoutSec = 60
+				bs.Iap = &ga.BackendServiceIAP{Enabled: true}
+			},
+			want: []string{"iap.enabled", "timeoutSec"},
+		},
+	} {
+		b := base()
+		tc.modify(b)
+		diffs := DiffBackendService(base(), b)
+		var got []string
+		for _, d := range diffs {
+			got = append(got, d.Path)
+		}
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: DiffBackendService() = %v, want paths %v", tc.desc, diffs, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%s: DiffBackendService() = %v, want paths %v", tc.desc, diffs, tc.want)
+			}
+		}
+	}
+}
+
+func TestDiffURLMapPathMatchers(t *testing.T) {
+	t.Parallel()
+
+	a := &ga.UrlMap{PathMatchers: []*ga.PathMatcher{{Name: "pm", PathRules: []*ga.PathRule{{Paths: []string{"/a", "/b"}, Service: "s"}}}}}
+	b := &ga.UrlMap{PathMatchers: []*ga.PathMatcher{{Name: "pm", PathRules: []*ga.PathRule{{Paths: []string{"/b", "/a"}, Service: "s"}}}}}
+	if d := DiffUrlMap(a, b); len(d) != 0 {
+		t.Errorf("DiffUrlMap() = %v, want no differences", d)
+	}
+	b.PathMatchers[0].PathRules[0].Service = "other"
+	if d := DiffUrlMap(a, b); len(d) != 1 || d[0].Path != "pathMatchers" {
+		t.Errorf("DiffUrlMap() = %v, want a difference in pathMatchers", d)
+	}
+}
+
+func TestNewBackendService(t *testing.T) {
+	t.Parallel()
+
+	got := NewBackendService("bs", func(bs *ga.BackendService) {
+		bs.LoadBalancingScheme = "INTERNAL_MANAGED"
+	}, func(bs *ga.BackendService) {
+		bs.HealthChecks = []string{"hc"}
+	})
+	if got.Name != "bs" || got.Kind != "compute#backendService" {
+		t.Errorf("NewBackendService() = %+v, want Name bs and Kind compute#backendService", got)
+	}
+	// The object as read back from the API, with the defaults filled in.
+	want := &ga.BackendService{
+		Name:                "bs",
+		LoadBalancingScheme: "INTERNAL_MANAGED",
+		Protocol:            "HTTP",
+		SessionAffinity:     "NONE",
+		TimeoutSec:          30,
+		HealthChecks:        []string{"hc"},
+	}
+	if d := DiffBackendService(got, want); len(d) != 0 {
+		t.Errorf("DiffBackendService(NewBackendService(), want) = %v, want none", d)
+	}
+
+	if hc := NewAlphaHealthCheck("hc"); hc.Kind != "compute#healthCheck" || hc.CheckIntervalSec != 5 || hc.UnhealthyThreshold != 2 {
+		t.Errorf("NewAlphaHealthCheck() = %+v, want Kind compute#healthCheck and defaults set", hc)
+	}
+}
@@ -35,6 +35,15 @@ limitations under the License.
 //  // Run foo with a mock.
 //  foo(NewMockGCE())
 //
+// Objects can be created with the generated New<Object> functions (e.g.
+// NewBackendService, NewAlphaHealthCheck). They set Kind and the values that
+// the API uses for unset fields so that the desired state of an object
+// compares equal to what is read back:
+//
+//  bs := NewBackendService("bs", func(bs *ga.BackendService) {
+//    bs.HealthChecks = []string{hcURL}
+//  })
+//
 // Rate limiting and routing
 //
 // The generated code allows for custom policies for operation rate limiting
@@ -70,6 +79,23 @@ limitations under the License.
 //    options: <options>              // Or'd ("|") together.
 //  }
 //
+// Custom verbs
+//
+// Methods can also be declared in "verbs" together with their request and
+// response types. The generator checks the types against the API, so changes
+// in the API are caught when the code is generated:
+//
+//  verbs: []Verb{
+//    {
+//      Name:     "AttachDisk",
+//      Request:  reflect.TypeOf(&ga.AttachedDisk{}),
+//      Response: reflect.TypeOf(&ga.Operation{}),
+//    },
+//  },
+//
+// The mock of each additional method has a "<Method>Hook" and a
+// "<Method>Error" map that returns an error for the given key.
+//
 // Read-only objects
 //
 // Services such as Regions and Zones do not allow for mutations. Specify
@@ -110,6 +136,20 @@ limitations under the License.
 //    // Custom implementation.
 //  }
 //
+// Generating a subset of the services
+//
+// The Cloud interface, GCE and GRPCGCE reference the wrappers of all of the
+// services (gen_cloud.go), so every binary using them links all of the
+// services. Binaries that only need a few resources can generate a Cloud
+// with only these services with "make gen-subset SERVICES=<services>", which
+// takes a comma separated list of service names (e.g.
+// "BackendServices,NetworkEndpointGroups") and writes gen_cloud_subset.go.
+// The binary is then built with the "gce_subset" build tag, which replaces
+// gen_cloud.go by gen_cloud_subset.go. Zones (used by CachedTopology) and the
+// services with hand written Ops (e.g. Projects) are always part of the
+// subset. MockGCE and the rest of the package are not affected by the tag;
+// the tests of the package require the full Cloud and are built without it.
+//
 // Update generated codes
 //
 // Run hack/update-cloudprovider-gce.sh to update the generated codes.
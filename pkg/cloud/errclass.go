@@ -0,0 +1,229 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/gcerrors"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+)
+
+// ErrorClass is the class of the error returned by a call.
+type ErrorClass string
+
+// Error classes returned by ClassifyError.
+const (
+	// ErrorClassNone is no error.
+	ErrorClassNone ErrorClass = ""
+	// ErrorClassClient is a 4xx error other than a quota error, e.g. the
+	// resource was not found or the request is invalid.
+	ErrorClassClient ErrorClass = "client"
+	// ErrorClassQuota is a quota or rate limit error from the API.
+	ErrorClassQuota ErrorClass = "quota"
+	// ErrorClassServer is a 5xx error other than a timeout.
+	ErrorClassServer ErrorClass = "server"
+	// ErrorClassTimeout is the API timing out before the caller gave up,
+	// e.g. 504 Gateway Timeout, see gcerrors.IsServerTimeout.
+	ErrorClassTimeout ErrorClass = "timeout"
+	// ErrorClassNetwork is an error connecting to the API.
+	ErrorClassNetwork ErrorClass = "network"
+	// ErrorClassContext is a call that ended because its context was
+	// canceled or its deadline exceeded, i.e. the caller gave up.
+	ErrorClassContext ErrorClass = "context"
+	// ErrorClassOther is any other error, e.g. an invalid key.
+	ErrorClassOther ErrorClass = "other"
+)
+
+// ClassifyError returns the class of err. Errors from the REST API
+// (*googleapi.Error), from the gRPC API and from failed operations are
+// classified by their status.
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ErrorClassNone
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return ErrorClassContext
+	}
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		return classifyHTTP(gerr)
+	}
+	if s, ok := gcerrors.Status(err); ok {
+		return classifyGRPC(s.Code())
+	}
+	var nerr net.Error
+	if errors.As(err, &nerr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrorClassNetwork
+	}
+	return ErrorClassOther
+}
+
+func classifyHTTP(gerr *googleapi.Error) ErrorClass {
+	switch {
+	case gerr.Code == http.StatusTooManyRequests:
+		return ErrorClassQuota
+	case gerr.Code == http.StatusGatewayTimeout:
+		return ErrorClassTimeout
+	case gerr.Code >= 500:
+		return ErrorClassServer
+	case gerr.Code >= 400:
+		if gcerrors.IsQuotaExceeded(gerr) {
+			return ErrorClassQuota
+		}
+		return ErrorClassClient
+	}
+	return ErrorClassOther
+}
+
+func classifyGRPC(code codes.Code) ErrorClass {
+	switch code {
+	case codes.OK:
+		return ErrorClassNone
+	case codes.Canceled:
+		return ErrorClassContext
+	case codes.DeadlineExceeded:
+		// The calls made through Cloud wrap the error of the context if
+		// the deadline of the caller passed, see callError.
+		return ErrorClassTimeout
+	case codes.ResourceExhausted:
+		return ErrorClassQuota
+	case codes.Unavailable:
+		return ErrorClassNetwork
+	case codes.Internal, codes.DataLoss, codes.Unknown:
+		return ErrorClassServer
+	case codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied,
+		codes.FailedPrecondition, codes.Aborted, codes.OutOfRange, codes.Unimplemented, codes.Unauthenticated:
+		return ErrorClassClient
+	}
+	return ErrorClassOther
+}
+
+// CallError describes a call that returned an error. It is passed to
+// Service.OnError and is the error returned by the calls made through
+// Cloud, so callers can tell which resource failed without adding it to
+// the error themselves:
+//
+//	var cerr *cloud.CallError
+//	if errors.As(err, &cerr) {
+//		log.Printf("%s of %s failed: %v", cerr.CallContextKey.Operation, cerr.ResourceID().RelativeResourceName(), cerr.Err)
+//	}
+//
+// The error returned by the API is wrapped, so errors.As and errors.Is (and
+// the predicates of package gcerrors) see it; code that type asserts the
+// error (e.g. err.(*googleapi.Error)) must use errors.As instead.
+type CallError struct {
+	// CallContextKey of the call.
+	CallContextKey *CallContextKey
+	// Key of the resource. This is nil if the call is not on a single
+	// resource.
+	Key *meta.Key
+	// Class of Err.
+	Class ErrorClass
+	// RequestID is the requestId sent with the last attempt of the call,
+	// to reference it when asking for support. It is "" if the call does
+	// not send one (e.g. Get).
+	RequestID string
+	// Err returned by the call.
+	Err error
+}
+
+// Error returns the verb and the resource of the call followed by Err, e.g.
+// "BackendServices.Get projects/p/global/backendServices/bs: ...".
+func (e *CallError) Error() string {
+	if e.CallContextKey == nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s.%s %s: %v", e.CallContextKey.Service, e.CallContextKey.Operation, e.resource(), e.Err)
+}
+
+// Unwrap returns Err.
+func (e *CallError) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode returns the HTTP status code of Err, or 0 if it is not an
+// error from the API. See gcerrors.Code.
+func (e *CallError) StatusCode() int {
+	return gcerrors.Code(e.Err)
+}
+
+// Header returns the header of the response of the call, or nil if Err is
+// not the error of a REST call.
+func (e *CallError) Header() http.Header {
+	if ge, ok := gcerrors.From(e.Err); ok {
+		return ge.Header
+	}
+	return nil
+}
+
+// RetryAfter returns the wait asked for by the Retry-After header of the
+// response. It returns false if there is none.
+func (e *CallError) RetryAfter() (time.Duration, bool) {
+	if ge, ok := gcerrors.From(e.Err); ok {
+		return ge.RetryAfter()
+	}
+	return 0, false
+}
+
+// ResourceID of the resource of the call. This is nil if the call is not
+// on a single resource.
+func (e *CallError) ResourceID() *ResourceID {
+	if e.Key == nil || e.CallContextKey == nil {
+		return nil
+	}
+	return &ResourceID{ProjectID: e.CallContextKey.ProjectID, Resource: resourceName(e.CallContextKey.Service), Key: e.Key}
+}
+
+func (e *CallError) resource() string {
+	if id := e.ResourceID(); id != nil {
+		return id.RelativeResourceName()
+	}
+	return "projects/" + e.CallContextKey.ProjectID
+}
+
+// callError returns err of the call as a CallError. err is returned as is
+// if it is already one. If ctx is done, err also wraps the error of ctx, so
+// that a call the caller gave up on (e.g. a gRPC DeadlineExceeded) is not
+// taken for a server timeout.
+func (s *Service) callError(ctx context.Context, info *CallInfo, err error) error {
+	var cerr *CallError
+	if errors.As(err, &cerr) {
+		return err
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil && !errors.Is(err, ctxErr) {
+		err = fmt.Errorf("%w: %w", ctxErr, err)
+	}
+	ck := info.CallContextKey
+	if ck.ProjectID == "" {
+		ck.ProjectID = s.ProjectRouter.ProjectID(ctx, ck.Version, ck.Service)
+	}
+	requestID, _ := ctx.Value(sentRequestIDContextKey).(*string)
+	cerr = &CallError{CallContextKey: &ck, Key: info.Key, Class: ClassifyError(err), Err: err}
+	if requestID != nil {
+		cerr.RequestID = *requestID
+	}
+	return cerr
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/gcerrors"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClassifyError(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		desc string
+		err  error
+		want ErrorClass
+	}{
+		{desc: "nil", want: ErrorClassNone},
+		{desc: "not found", err: &googleapi.Error{Code: http.StatusNotFound}, want: ErrorClassClient},
+		{desc: "wrapped bad request", err: fmt.Errorf("x: %w", &googleapi.Error{Code: http.StatusBadRequest}), want: ErrorClassClient},
+		{desc: "429", err: &googleapi.Error{Code: http.StatusTooManyRequests}, want: ErrorClassQuota},
+		{
+			desc: "403 rate limit",
+			err:  &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}}},
+			want: ErrorClassQuota,
+		},
+		{
+			desc: "403 forbidden",
+			err:  &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "forbidden"}}},
+			want: ErrorClassClient,
+		},
+		{
+			desc: "operation quota",
+			err:  &googleapi.Error{Code: http.StatusForbidden, Message: "QUOTA_EXCEEDED - Quota 'CPUS' exceeded"},
+			want: ErrorClassQuota,
+		},
+		{desc: "503", err: &googleapi.Error{Code: http.StatusServiceUnavailable}, want: ErrorClassServer},
+		{desc: "504", err: &googleapi.Error{Code: http.StatusGatewayTimeout}, want: ErrorClassTimeout},
+		{desc: "canceled", err: context.Canceled, want: ErrorClassContext},
+		{desc: "deadline", err: fmt.Errorf("x: %w", context.DeadlineExceeded), want: ErrorClassContext},
+		{desc: "network", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, want: ErrorClassNetwork},
+		{desc: "eof", err: io.ErrUnexpectedEOF, want: ErrorClassNetwork},
+		{desc: "grpc not found", err: status.Error(codes.NotFound, "x"), want: ErrorClassClient},
+		{desc: "wrapped grpc not found", err: fmt.Errorf("x: %w", status.Error(codes.NotFound, "x")), want: ErrorClassClient},
+		{desc: "grpc exhausted", err: status.Error(codes.ResourceExhausted, "x"), want: ErrorClassQuota},
+		{desc: "grpc internal", err: status.Error(codes.Internal, "x"), want: ErrorClassServer},
+		{desc: "grpc deadline", err: status.Error(codes.DeadlineExceeded, "x"), want: ErrorClassTimeout},
+		{desc: "grpc canceled", err: status.Error(codes.Canceled, "x"), want: ErrorClassContext},
+		{desc: "other", err: errors.New("invalid GCE key"), want: ErrorClassOther},
+	} {
+		if got := ClassifyError(tc.err); got != tc.want {
+			t.Errorf("%s: ClassifyError(%v) = %q, want %q", tc.desc, tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestServiceOnError(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+
+	var lock sync.Mutex
+	var got []*CallError
+	c := NewGCE(&Service{
+		GA:            client,
+		ProjectRouter: &SingleProjectRouter{"proj"},
+		RateLimiter:   &NopRateLimiter{},
+		OnError: func(ctx context.Context, e *CallError) {
+			lock.Lock()
+			defer lock.Unlock()
+			got = append(got, e)
+		},
+	})
+	key := meta.GlobalKey("fw")
+	if _, err := c.Firewalls().Get(ctx, key); err == nil {
+		t.Fatalf("Get() = _, nil, want error")
+	}
+
+	if len(got) != 1 {
+		t.Fatalf("OnError called %d times, want 1", len(got))
+	}
+	if got[0].Class != ErrorClassClient || got[0].Key != key || got[0].CallContextKey.Operation != "Get" {
+		t.Errorf("OnError(%+v), want a client error for Get(%v)", got[0], key)
+	}
+}
+
+func TestCallErrorContext(t *testing.T) {
+	t.Parallel()
+
+	s := &Service{ProjectRouter: &SingleProjectRouter{"proj"}}
+	info := newCallInfo("BackendServices", "Get", meta.VersionGA, meta.GlobalKey("bs"))
+	grpcErr := status.Error(codes.DeadlineExceeded, "deadline exceeded")
+
+	// The API timed out while the caller was still waiting.
+	err := s.callError(context.Background(), info, grpcErr)
+	if got := ClassifyError(err); got != ErrorClassTimeout {
+		t.Errorf("ClassifyError(%v) = %q, want %q", err, got, ErrorClassTimeout)
+	}
+	if !gcerrors.IsServerTimeout(err) || gcerrors.IsDeadlineExceeded(err) {
+		t.Errorf("IsServerTimeout(%v), IsDeadlineExceeded() = %t, %t, want true, false", err, gcerrors.IsServerTimeout(err), gcerrors.IsDeadlineExceeded(err))
+	}
+
+	// The deadline of the caller passed.
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now())
+	defer cancel()
+	<-ctx.Done()
+	err = s.callError(ctx, info, grpcErr)
+	if got := ClassifyError(err); got != ErrorClassContext {
+		t.Errorf("ClassifyError(%v) = %q, want %q", err, got, ErrorClassContext)
+	}
+	if gcerrors.IsServerTimeout(err) || !gcerrors.IsDeadlineExceeded(err) {
+		t.Errorf("IsServerTimeout(%v), IsDeadlineExceeded() = %t, %t, want false, true", err, gcerrors.IsServerTimeout(err), gcerrors.IsDeadlineExceeded(err))
+	}
+	if s, ok := gcerrors.Status(err); !ok || s.Code() != codes.DeadlineExceeded {
+		t.Errorf("Status(%v) = %v, %t, want the status of the call", err, s, ok)
+	}
+}
+
+func TestCallErrorReturned(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+	c := NewGCE(&Service{
+		GA:            client,
+		ProjectRouter: &SingleProjectRouter{"proj"},
+		RateLimiter:   &NopRateLimiter{},
+	})
+
+	key := meta.RegionalKey("fr", "us-central1")
+	_, err = c.ForwardingRules().Get(ctx, key)
+	var cerr *CallError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("Get() = _, %v (%T), want a *CallError", err, err)
+	}
+	wantID := &ResourceID{ProjectID: "proj", Resource: "forwardingRules", Key: key}
+	if cerr.CallContextKey.Operation != "Get" || cerr.Class != ErrorClassClient || !cerr.ResourceID().Equal(wantID) {
+		t.Errorf("CallError = %+v, ResourceID() = %+v; want a client error of Get(%+v)", cerr, cerr.ResourceID(), wantID)
+	}
+	if !gcerrors.IsNotFound(err) {
+		t.Errorf("gcerrors.IsNotFound(%v) = false, want true", err)
+	}
+	const wantPrefix = "ForwardingRules.Get projects/proj/regions/us-central1/forwardingRules/fr: googleapi: got HTTP response code 404"
+	if got := err.Error(); !strings.HasPrefix(got, wantPrefix) {
+		t.Errorf("Error() = %q, want prefix %q", got, wantPrefix)
+	}
+
+	_, err = c.ForwardingRules().List(ctx, "us-central1", nil)
+	if !errors.As(err, &cerr) || cerr.ResourceID() != nil || cerr.CallContextKey.ProjectID != "proj" {
+		t.Errorf("List() = _, %v, want a *CallError without a ResourceID in project proj", err)
+	}
+}
+
+func TestCallErrorResponse(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		http.Error(w, `{"error": {"code": 503, "message": "unavailable"}}`, http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+	c := NewGCE(&Service{
+		GA:            client,
+		ProjectRouter: &SingleProjectRouter{"proj"},
+		RateLimiter:   &NopRateLimiter{},
+	})
+
+	err = c.Addresses().Insert(WithRequestID(ctx, "req-1"), meta.RegionalKey("addr", "us-central1"), &ga.Address{})
+	var cerr *CallError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("Insert() = %v (%T), want a *CallError", err, err)
+	}
+	if cerr.RequestID != "req-1" {
+		t.Errorf("RequestID = %q, want %q", cerr.RequestID, "req-1")
+	}
+	if got := cerr.StatusCode(); got != http.StatusServiceUnavailable {
+		t.Errorf("StatusCode() = %d, want %d", got, http.StatusServiceUnavailable)
+	}
+	if got := cerr.Header().Get("Retry-After"); got != "7" {
+		t.Errorf("Header().Get(Retry-After) = %q, want %q", got, "7")
+	}
+	if got, ok := cerr.RetryAfter(); !ok || got != 7*time.Second {
+		t.Errorf("RetryAfter() = %v, %t, want %v, true", got, ok, 7*time.Second)
+	}
+
+	// Calls that do not send a requestId.
+	_, err = c.Addresses().Get(ctx, meta.RegionalKey("addr", "us-central1"))
+	if !errors.As(err, &cerr) || cerr.RequestID != "" {
+		t.Errorf("Get() = %v, want a *CallError without a RequestID", err)
+	}
+}
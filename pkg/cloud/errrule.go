@@ -0,0 +1,136 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/gcerrors"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// ErrorRule classifies the errors it matches as retriable or terminal. The
+// rules are consulted by the RetryPolicy and by IsRetriableError before
+// the built-in classification, so that the quirks of an API (e.g. an
+// alpha API that returns 400 while a resource is being created) can be
+// handled without changing this package:
+//
+//	cloud.RegisterErrorRule(cloud.ErrorRule{
+//		Service:   "NetworkEndpointGroups",
+//		Version:   meta.VersionAlpha,
+//		Reason:    "resourceNotReady",
+//		Retriable: true,
+//	})
+//
+// The zero value of a field matches any value.
+type ErrorRule struct {
+	// Code, Reason and Domain of the error, see gcerrors.Error.
+	Code   int
+	Reason string
+	Domain string
+	// Service (e.g. "BackendServices"), Version and Operation of the call.
+	Service   string
+	Version   meta.Version
+	Operation string
+	// Match is called for the errors matched by the other fields, for
+	// conditions they cannot express (e.g. on the message).
+	Match func(error) bool
+	// Retriable is true if the errors matched are transient, false if
+	// retrying the call will not help. Retriable errors are only retried
+	// for the calls that are safe to repeat, see RetryPolicy.
+	Retriable bool
+}
+
+var errorRules struct {
+	lock  sync.RWMutex
+	rules []ErrorRule
+}
+
+// RegisterErrorRule adds r to the rules. The rules registered last are
+// consulted first.
+func RegisterErrorRule(r ErrorRule) {
+	errorRules.lock.Lock()
+	defer errorRules.lock.Unlock()
+
+	errorRules.rules = append(errorRules.rules, r)
+}
+
+// matches is true if r applies to err of the call ck.
+func (r *ErrorRule) matches(err error, e *gcerrors.Error, ck *CallContextKey) bool {
+	if ck == nil && (r.Service != "" || r.Version != "" || r.Operation != "") {
+		return false
+	}
+	if ck != nil && ((r.Service != "" && r.Service != ck.Service) ||
+		(r.Version != "" && r.Version != ck.Version) ||
+		(r.Operation != "" && r.Operation != ck.Operation)) {
+		return false
+	}
+	if r.Code != 0 || r.Reason != "" || r.Domain != "" {
+		if e == nil || (r.Code != 0 && r.Code != e.Code) ||
+			(r.Reason != "" && r.Reason != e.Reason) ||
+			(r.Domain != "" && r.Domain != e.Domain) {
+			return false
+		}
+	}
+	return r.Match == nil || r.Match(err)
+}
+
+// errorRule returns whether err of the call ck is retriable according to
+// the registered rules. It returns false if no rule matches.
+func errorRule(err error, ck *CallContextKey) (retriable bool, ok bool) {
+	if err == nil {
+		return false, false
+	}
+	errorRules.lock.RLock()
+	defer errorRules.lock.RUnlock()
+
+	if len(errorRules.rules) == 0 {
+		return false, false
+	}
+	e, _ := gcerrors.From(err)
+	for i := len(errorRules.rules) - 1; i >= 0; i-- {
+		r := &errorRules.rules[i]
+		if r.matches(err, e, ck) {
+			return r.Retriable, true
+		}
+	}
+	return false, false
+}
+
+// IsRetriableError is true if the call that returned err may succeed if it
+// is made again: the error is classified as retriable by a registered
+// ErrorRule or else is transient (5xx, 429, network error) or a resource
+// that is not ready or in use. The rules on the call (e.g. on the Service)
+// apply if err is a *CallError.
+func IsRetriableError(err error) bool {
+	var ck *CallContextKey
+	var cerr *CallError
+	if errors.As(err, &cerr) {
+		ck = cerr.CallContextKey
+	}
+	if retriable, ok := errorRule(err, ck); ok {
+		return retriable
+	}
+	return isRetriable(err) || isNotReady(err)
+}
+
+// IsTerminalError is true if err is an error that retrying the call will
+// not fix, see IsRetriableError.
+func IsTerminalError(err error) bool {
+	return err != nil && !IsRetriableError(err)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+)
+
+func TestErrorRules(t *testing.T) {
+	t.Parallel()
+
+	// The rules match reasons that only this test uses, as they are
+	// global.
+	RegisterErrorRule(ErrorRule{Reason: "testRuleRetriable", Service: "HealthChecks", Retriable: true})
+	RegisterErrorRule(ErrorRule{Code: http.StatusServiceUnavailable, Reason: "testRuleTerminal"})
+
+	callErr := func(service string, err error) error {
+		return &CallError{CallContextKey: &CallContextKey{Service: service, Operation: "Get", Version: meta.VersionGA}, Err: err}
+	}
+	apiErr := func(code int, reason string) error {
+		return &googleapi.Error{Code: code, Errors: []googleapi.ErrorItem{{Reason: reason}}}
+	}
+	for _, tc := range []struct {
+		desc string
+		err  error
+		want bool
+	}{
+		{desc: "built-in 503", err: apiErr(http.StatusServiceUnavailable, "backendError"), want: true},
+		{desc: "built-in 400", err: apiErr(http.StatusBadRequest, "invalid")},
+		{desc: "rule on the service", err: callErr("HealthChecks", apiErr(http.StatusBadRequest, "testRuleRetriable")), want: true},
+		{desc: "rule on another service", err: callErr("Firewalls", apiErr(http.StatusBadRequest, "testRuleRetriable"))},
+		{desc: "rule on the service without a call", err: apiErr(http.StatusBadRequest, "testRuleRetriable")},
+		{desc: "terminal rule", err: apiErr(http.StatusServiceUnavailable, "testRuleTerminal")},
+		{desc: "terminal rule on another code", err: apiErr(http.StatusInternalServerError, "testRuleTerminal"), want: true},
+	} {
+		if got := IsRetriableError(tc.err); got != tc.want {
+			t.Errorf("%s: IsRetriableError(%v) = %t, want %t", tc.desc, tc.err, got, tc.want)
+		}
+		if got := IsTerminalError(tc.err); got != !tc.want {
+			t.Errorf("%s: IsTerminalError(%v) = %t, want %t", tc.desc, tc.err, got, !tc.want)
+		}
+	}
+	if IsTerminalError(nil) {
+		t.Errorf("IsTerminalError(nil) = true, want false")
+	}
+
+	sentinel := errors.New("sentinel")
+	RegisterErrorRule(ErrorRule{Match: func(err error) bool { return errors.Is(err, sentinel) }, Retriable: true})
+	if !IsRetriableError(sentinel) {
+		t.Errorf("IsRetriableError(%v) = false, want true", sentinel)
+	}
+}
+
+func TestRetryErrorRules(t *testing.T) {
+	t.Parallel()
+
+	RegisterErrorRule(ErrorRule{Reason: "testRetryRuleRetriable", Service: "HealthChecks", Operation: "Get", Retriable: true})
+	RegisterErrorRule(ErrorRule{Reason: "testRetryRuleTerminal", Service: "HealthChecks"})
+
+	var (
+		lock     sync.Mutex
+		requests int
+	)
+	mux := http.NewServeMux()
+	reply := func(code int, reason string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			lock.Lock()
+			requests++
+			lock.Unlock()
+			http.Error(w, `{"error": {"code": 400, "message": "m", "errors": [{"reason": "`+reason+`"}]}}`, code)
+		}
+	}
+	mux.HandleFunc("/projects/proj/global/healthChecks/retriable", reply(http.StatusBadRequest, "testRetryRuleRetriable"))
+	mux.HandleFunc("/projects/proj/global/healthChecks/terminal", reply(http.StatusServiceUnavailable, "testRetryRuleTerminal"))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+	c := NewGCE(&Service{
+		GA:            client,
+		ProjectRouter: &SingleProjectRouter{"proj"},
+		RateLimiter:   &NopRateLimiter{},
+		Retry:         &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+	})
+
+	for _, tc := range []struct {
+		name     string
+		wantReqs int
+	}{
+		{name: "retriable", wantReqs: 3},
+		{name: "terminal", wantReqs: 1},
+	} {
+		lock.Lock()
+		requests = 0
+		lock.Unlock()
+		if _, err := c.HealthChecks().Get(ctx, meta.GlobalKey(tc.name)); err == nil {
+			t.Errorf("%s: Get() = _, nil, want error", tc.name)
+		}
+		lock.Lock()
+		if requests != tc.wantReqs {
+			t.Errorf("%s: got %d requests, want %d", tc.name, requests, tc.wantReqs)
+		}
+		lock.Unlock()
+	}
+}
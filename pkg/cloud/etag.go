@@ -0,0 +1,104 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/gcerrors"
+	"google.golang.org/api/googleapi"
+)
+
+var preconditionsContextKey = contextKey("preconditions")
+
+// preconditions are the conditional headers sent with the calls made with
+// a context.
+type preconditions struct {
+	ifMatch     string
+	ifNoneMatch string
+}
+
+func withPreconditions(ctx context.Context, f func(p *preconditions)) context.Context {
+	var p preconditions
+	if old, ok := ctx.Value(preconditionsContextKey).(*preconditions); ok {
+		p = *old
+	}
+	f(&p)
+	return context.WithValue(ctx, preconditionsContextKey, &p)
+}
+
+// WithIfMatch sends If-Match: etag with the calls made with ctx, e.g. an
+// Update. The server rejects the call with 412 Precondition Failed if the
+// ETag of the resource is not etag, which gives optimistic concurrency for
+// the resources without a fingerprint. Calls made with gRPC ignore it.
+func WithIfMatch(ctx context.Context, etag string) context.Context {
+	return withPreconditions(ctx, func(p *preconditions) { p.ifMatch = etag })
+}
+
+// WithIfNoneMatch sends If-None-Match: etag with the calls made with ctx.
+// A Get of a resource that still has the ETag etag returns an error for
+// which IsNotModified is true, without the object. Calls made with gRPC
+// ignore it.
+//
+//	bs, err := gce.BackendServices().Get(cloud.WithIfNoneMatch(ctx, cloud.ETag(old)), key)
+//	if cloud.IsNotModified(err) {
+//		bs, err = old, nil
+//	}
+func WithIfNoneMatch(ctx context.Context, etag string) context.Context {
+	return withPreconditions(ctx, func(p *preconditions) { p.ifNoneMatch = etag })
+}
+
+// ETag returns the ETag of the response that returned obj (e.g. the
+// result of a Get). It returns "" if the server did not send one or obj
+// is not an API object.
+func ETag(obj interface{}) string {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return ""
+	}
+	f := v.Elem().FieldByName("ServerResponse")
+	if !f.IsValid() {
+		return ""
+	}
+	sr, ok := f.Interface().(googleapi.ServerResponse)
+	if !ok {
+		return ""
+	}
+	return sr.Header.Get("Etag")
+}
+
+// IsNotModified is true if err is the result of a call with an
+// If-None-Match that matched.
+func IsNotModified(err error) bool {
+	return gcerrors.IsNotModified(err)
+}
+
+// setPreconditions sets the conditional headers of ctx on h.
+func setPreconditions(ctx context.Context, h http.Header) {
+	p, ok := ctx.Value(preconditionsContextKey).(*preconditions)
+	if !ok {
+		return
+	}
+	if p.ifMatch != "" {
+		h.Set("If-Match", p.ifMatch)
+	}
+	if p.ifNoneMatch != "" {
+		h.Set("If-None-Match", p.ifNoneMatch)
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+)
+
+func TestPreconditions(t *testing.T) {
+	t.Parallel()
+
+	const etag = `"v1"`
+	var gotIfMatch string
+	op := map[string]interface{}{
+		"name":     "op-1",
+		"status":   "DONE",
+		"selfLink": "https://www.googleapis.com/compute/v1/projects/proj/global/operations/op-1",
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "/operations/"):
+			json.NewEncoder(w).Encode(op)
+		case r.Method == http.MethodPut:
+			gotIfMatch = r.Header.Get("If-Match")
+			json.NewEncoder(w).Encode(op)
+		case r.Header.Get("If-None-Match") == etag:
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			w.Header().Set("ETag", etag)
+			json.NewEncoder(w).Encode(map[string]interface{}{"name": "bs"})
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+	c := NewGCE(&Service{GA: client, ProjectRouter: &SingleProjectRouter{"proj"}, RateLimiter: &NopRateLimiter{}})
+	key := meta.GlobalKey("bs")
+
+	bs, err := c.BackendServices().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get(%v) = _, %v, want nil", key, err)
+	}
+	if got := ETag(bs); got != etag {
+		t.Fatalf("ETag(%+v) = %q, want %q", bs, got, etag)
+	}
+	if _, err := c.BackendServices().Get(WithIfNoneMatch(ctx, etag), key); !IsNotModified(err) {
+		t.Errorf("Get(WithIfNoneMatch(%q)) = %v, want not modified", etag, err)
+	}
+	if _, err := c.BackendServices().Get(WithIfNoneMatch(ctx, `"v0"`), key); err != nil {
+		t.Errorf("Get(WithIfNoneMatch(v0)) = %v, want nil", err)
+	}
+	if err := c.BackendServices().Update(WithIfMatch(ctx, etag), key, bs); err != nil {
+		t.Fatalf("Update() = %v, want nil", err)
+	}
+	if gotIfMatch != etag {
+		t.Errorf("If-Match = %q, want %q", gotIfMatch, etag)
+	}
+
+	for _, obj := range []interface{}{nil, &ga.BackendService{}, "not an object"} {
+		if got := ETag(obj); got != "" {
+			t.Errorf("ETag(%v) = %q, want \"\"", obj, got)
+		}
+	}
+}
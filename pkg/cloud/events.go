@@ -0,0 +1,192 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	ga "google.golang.org/api/compute/v1"
+)
+
+// EventType is the change to a resource.
+type EventType string
+
+// Event types.
+const (
+	// EventCreated is sent for a successful Insert.
+	EventCreated EventType = "created"
+	// EventUpdated is sent for the other successful mutations (e.g.
+	// Patch, Update, SetLabels).
+	EventUpdated EventType = "updated"
+	// EventDeleted is sent for a successful Delete.
+	EventDeleted EventType = "deleted"
+)
+
+// Event is a successful mutation of a resource made through Cloud. The
+// long running operations that complete outside of a call (e.g. a
+// WaitForCompletion resumed after a restart) also send an Event for the
+// target of the operation.
+type Event struct {
+	Type EventType
+	// ResourceID of the resource. Key is nil if the call is not on a
+	// single resource.
+	ResourceID *ResourceID
+	// Service of the call, e.g. "GlobalForwardingRules".
+	Service string
+	// Operation is the verb, e.g. "SetTarget". For an operation that
+	// completed outside of a call, this is its operationType.
+	Operation string
+	// Version of the API.
+	Version meta.Version
+	// Time the call ended. For calls that return a long running
+	// operation, this is when the operation is done.
+	Time time.Time
+}
+
+// eventType returns the EventType of the mutation op.
+func eventType(op string) EventType {
+	switch op {
+	case "Insert":
+		return EventCreated
+	case "Delete":
+		return EventDeleted
+	}
+	return EventUpdated
+}
+
+// EventHandler is called for each Event. It is called synchronously
+// before the call returns, so it should not block.
+type EventHandler func(ctx context.Context, e *Event)
+
+// EventBus sends an Event to its subscribers for each successful mutation
+// made through a Cloud with Service.Events set to the EventBus.
+//
+//	bus := cloud.NewEventBus()
+//	svc.Events = bus
+//	cancel := bus.Subscribe(func(ctx context.Context, e *cloud.Event) {
+//		cache.Invalidate(e.ResourceID)
+//	})
+//	defer cancel()
+type EventBus struct {
+	lock     sync.RWMutex
+	next     int
+	handlers map[int]EventHandler
+}
+
+// NewEventBus returns a new EventBus with no subscribers.
+func NewEventBus() *EventBus {
+	return &EventBus{handlers: map[int]EventHandler{}}
+}
+
+// Subscribe h to the Events. Handlers are called in the order they were
+// subscribed. The returned function removes the subscription.
+func (b *EventBus) Subscribe(h EventHandler) func() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	id := b.next
+	b.next++
+	b.handlers[id] = h
+
+	return func() {
+		b.lock.Lock()
+		defer b.lock.Unlock()
+
+		delete(b.handlers, id)
+	}
+}
+
+// Publish e to the subscribers. Cloud publishes the Events of the calls
+// made through it; Publish is for the Events of changes made by other
+// means, e.g. by a mock.
+func (b *EventBus) Publish(ctx context.Context, e *Event) {
+	b.lock.RLock()
+	ids := make([]int, 0, len(b.handlers))
+	for id := range b.handlers {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	handlers := make([]EventHandler, 0, len(ids))
+	for _, id := range ids {
+		handlers = append(handlers, b.handlers[id])
+	}
+	b.lock.RUnlock()
+
+	for _, h := range handlers {
+		h(ctx, e)
+	}
+}
+
+// publishOperation publishes the Event of the long running operation op
+// that completed successfully outside of a call. The calls publish the
+// Events of their operations themselves.
+func (s *Service) publishOperation(ctx context.Context, op interface{}) {
+	if s.Events == nil {
+		return
+	}
+	if _, ok := ctx.Value(callStateContextKey).(*callState); ok {
+		return
+	}
+	target, opType, version := operationTarget(op)
+	if target == "" || opType == "" {
+		return
+	}
+	id, err := ParseResourceURL(target)
+	if err != nil || id.Key == nil {
+		return
+	}
+	// The operationType is the method, e.g. "insert" or
+	// "compute.instances.insert".
+	opType = opType[strings.LastIndex(opType, ".")+1:]
+	operation := strings.ToUpper(opType[:1]) + opType[1:]
+	s.Events.Publish(ctx, &Event{
+		Type:       eventType(operation),
+		ResourceID: id,
+		Service:    serviceName(id.Resource, id.Key),
+		Operation:  operation,
+		Version:    version,
+		Time:       time.Now(),
+	})
+}
+
+// operationTarget returns the URL of the resource changed by the long
+// running operation op, its operationType and its API version. target is
+// empty if it is not known.
+func operationTarget(op interface{}) (target, opType string, version meta.Version) {
+	switch o := op.(type) {
+	case *ga.Operation:
+		if o != nil {
+			return o.TargetLink, o.OperationType, meta.VersionGA
+		}
+	case *alpha.Operation:
+		if o != nil {
+			return o.TargetLink, o.OperationType, meta.VersionAlpha
+		}
+	case *beta.Operation:
+		if o != nil {
+			return o.TargetLink, o.OperationType, meta.VersionBeta
+		}
+	}
+	return "", "", ""
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+)
+
+func TestEventBus(t *testing.T) {
+	t.Parallel()
+
+	bus := NewEventBus()
+	var got []string
+	cancel1 := bus.Subscribe(func(ctx context.Context, e *Event) { got = append(got, "1 "+e.Operation) })
+	bus.Subscribe(func(ctx context.Context, e *Event) { got = append(got, "2 "+e.Operation) })
+
+	ctx := context.Background()
+	bus.Publish(ctx, &Event{Operation: "Insert"})
+	cancel1()
+	bus.Publish(ctx, &Event{Operation: "Delete"})
+
+	want := []string{"1 Insert", "2 Insert", "2 Delete"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestServiceEvents(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/proj/global/firewalls/fw", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(map[string]interface{}{"name": "fw"})
+		case http.MethodPatch:
+			http.Error(w, "bad request", http.StatusBadRequest)
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"name":     "op-2",
+				"status":   "DONE",
+				"selfLink": "https://www.googleapis.com/compute/v1/projects/proj/global/operations/op-2",
+			})
+		}
+	})
+	mux.HandleFunc("/projects/proj/global/firewalls", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":     "op-1",
+			"status":   "RUNNING",
+			"selfLink": "https://www.googleapis.com/compute/v1/projects/proj/global/operations/op-1",
+		})
+	})
+	mux.HandleFunc("/projects/proj/global/operations/", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "DONE"})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+	bus := NewEventBus()
+	var events []*Event
+	bus.Subscribe(func(ctx context.Context, e *Event) { events = append(events, e) })
+	c := NewGCE(&Service{
+		GA:            client,
+		ProjectRouter: &SingleProjectRouter{"proj"},
+		RateLimiter:   &NopRateLimiter{},
+		Events:        bus,
+	})
+	key := meta.GlobalKey("fw")
+
+	if _, err := c.Firewalls().Get(ctx, key); err != nil {
+		t.Fatalf("Get(%v) = _, %v, want nil", key, err)
+	}
+	if err := c.Firewalls().Insert(ctx, key, &ga.Firewall{}); err != nil {
+		t.Fatalf("Insert(%v) = %v, want nil", key, err)
+	}
+	// Failed mutations are not published.
+	if err := c.Firewalls().Patch(ctx, key, &ga.Firewall{}); err == nil {
+		t.Fatalf("Patch(%v) = nil, want error", key)
+	}
+	if err := c.Firewalls().Delete(ctx, key); err != nil {
+		t.Fatalf("Delete(%v) = %v, want nil", key, err)
+	}
+
+	wantID := &ResourceID{ProjectID: "proj", Resource: "firewalls", Key: key}
+	wantTypes := []EventType{EventCreated, EventDeleted}
+	if len(events) != len(wantTypes) {
+		t.Fatalf("got %d events, want %d", len(events), len(wantTypes))
+	}
+	for i, e := range events {
+		if e.Type != wantTypes[i] || !e.ResourceID.Equal(wantID) || e.Service != "Firewalls" || e.Version != meta.VersionGA || e.Time.IsZero() {
+			t.Errorf("events[%d] = %+v, want %s of %v", i, e, wantTypes[i], wantID)
+		}
+	}
+}
+
+func TestEventResumedOperation(t *testing.T) {
+	t.Parallel()
+
+	const selfLink = "https://www.googleapis.com/compute/v1/projects/proj/global/operations/op-1"
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/proj/global/operations/op-1/wait", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "op-1", "status": "DONE", "selfLink": selfLink})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+	bus := NewEventBus()
+	var got []*Event
+	bus.Subscribe(func(ctx context.Context, e *Event) { got = append(got, e) })
+	svc := &Service{GA: client, ProjectRouter: &SingleProjectRouter{"proj"}, RateLimiter: &NopRateLimiter{}, Events: bus}
+
+	// The operation of a call made before a restart.
+	op := &ga.Operation{
+		Name:          "op-1",
+		SelfLink:      selfLink,
+		TargetLink:    "https://www.googleapis.com/compute/v1/projects/proj/regions/us-central1/backendServices/bs",
+		OperationType: "setSecurityPolicy",
+	}
+	if err := svc.WaitForCompletion(ctx, op); err != nil {
+		t.Fatalf("WaitForCompletion() = %v, want nil", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d Events, want 1", len(got))
+	}
+	e := got[0]
+	wantID := &ResourceID{ProjectID: "proj", Resource: "backendServices", Key: meta.RegionalKey("bs", "us-central1")}
+	if e.Type != EventUpdated || !e.ResourceID.Equal(wantID) || e.Service != "RegionBackendServices" || e.Operation != "SetSecurityPolicy" || e.Version != meta.VersionGA {
+		t.Errorf("Event = %+v, want an update of %v by RegionBackendServices.SetSecurityPolicy", e, wantID)
+	}
+}
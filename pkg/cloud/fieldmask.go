@@ -0,0 +1,180 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+)
+
+// applyFieldMask returns a copy of obj that contains only the fields named in
+// fieldMask. This is used to send a PATCH that only touches the given fields,
+// leaving fields that are managed by other clients untouched.
+//
+// Each element of fieldMask is a dot-separated path of field names. The names
+// may either be the JSON name ("logConfig.enable") or the Go name
+// ("LogConfig.Enable"). Masked fields that have the zero value are added to
+// ForceSendFields (or NullFields for pointer, slice and map types) so that
+// the field is cleared on the server. A nested field whose parent is nil in
+// obj (e.g. "logConfig.enable" with a nil LogConfig) is an error, as sending
+// the parent as null would clear all of its fields; mask the parent instead
+// to clear it.
+//
+// The Fingerprint field is always copied if set, as it is required by the
+// API for optimistic concurrency control.
+//
+// If fieldMask is empty, obj is returned unchanged.
+func applyFieldMask[T any](obj *T, fieldMask []string) (*T, error) {
+	if len(fieldMask) == 0 || obj == nil {
+		return obj, nil
+	}
+	src := reflect.ValueOf(obj).Elem()
+	if src.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("applyFieldMask: %T is not a pointer to a struct", obj)
+	}
+	ret := new(T)
+	dest := reflect.ValueOf(ret).Elem()
+
+	for _, path := range fieldMask {
+		if err := maskField(dest, src, strings.Split(path, ".")); err != nil {
+			return nil, fmt.Errorf("applyFieldMask: field mask %q: %w", path, err)
+		}
+	}
+	if f := src.FieldByName("Fingerprint"); f.IsValid() && f.Kind() == reflect.String && f.String() != "" {
+		dest.FieldByName("Fingerprint").Set(f)
+	}
+
+	return ret, nil
+}
+
+// maskField copies the field named by path from src to dest. dest and src
+// must be struct values of the same type.
+func maskField(dest, src reflect.Value, path []string) error {
+	sf, ok := findField(src.Type(), path[0])
+	if !ok {
+		return fmt.Errorf("no field %q in %s", path[0], src.Type())
+	}
+	srcField := src.FieldByIndex(sf.Index)
+	destField := dest.FieldByIndex(sf.Index)
+
+	if len(path) == 1 {
+		destField.Set(srcField)
+		if srcField.IsZero() {
+			switch srcField.Kind() {
+			case reflect.Ptr, reflect.Slice, reflect.Map:
+				appendFieldName(dest, "NullFields", sf.Name)
+			default:
+				appendFieldName(dest, "ForceSendFields", sf.Name)
+			}
+		}
+		return nil
+	}
+
+	if sf.Type.Kind() != reflect.Ptr || sf.Type.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("field %q in %s is not a struct", path[0], src.Type())
+	}
+	if srcField.IsNil() {
+		return fmt.Errorf("field %q in %s is nil", path[0], src.Type())
+	}
+	if destField.IsNil() {
+		destField.Set(reflect.New(sf.Type.Elem()))
+	}
+	return maskField(destField.Elem(), srcField.Elem(), path[1:])
+}
+
+// findField looks up the field by JSON or Go name.
+func findField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		jsonName := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if sf.Name == name || (jsonName != "" && jsonName != "-" && jsonName == name) {
+			return sf, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func appendFieldName(v reflect.Value, field, name string) {
+	f := v.FieldByName(field)
+	if !f.IsValid() || f.Kind() != reflect.Slice || f.Type().Elem().Kind() != reflect.String {
+		return
+	}
+	for i := 0; i < f.Len(); i++ {
+		if f.Index(i).String() == name {
+			return
+		}
+	}
+	f.Set(reflect.Append(f, reflect.ValueOf(name)))
+}
+
+// MockPatch applies patch to dest with the semantics of a PATCH to the API:
+// the fields present in the JSON encoding of patch (non-zero fields,
+// ForceSendFields and NullFields) replace the corresponding fields in dest.
+// This is the default behavior of the Patch() method of the mocks and is
+// exported for use by custom mock hooks.
+//
+// If patch has a non-empty Fingerprint that does not match dest, a 412 error
+// is returned. On success, the Fingerprint of dest is updated.
+func MockPatch(dest, patch interface{}) error {
+	destFP := reflect.ValueOf(dest).Elem().FieldByName("Fingerprint")
+	patchFP := reflect.ValueOf(patch).Elem().FieldByName("Fingerprint")
+	if destFP.IsValid() && patchFP.IsValid() && patchFP.String() != "" && patchFP.String() != destFP.String() {
+		return &googleapi.Error{
+			Code:    http.StatusPreconditionFailed,
+			Message: fmt.Sprintf("fingerprint %q does not match %q", patchFP.String(), destFP.String()),
+		}
+	}
+	b, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	// Lists in the patch replace the list in dest. json.Unmarshal would
+	// otherwise merge into the existing elements.
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	destVal := reflect.ValueOf(dest).Elem()
+	for name := range fields {
+		if sf, ok := findField(destVal.Type(), name); ok && sf.Type.Kind() == reflect.Slice {
+			destVal.FieldByIndex(sf.Index).Set(reflect.Zero(sf.Type))
+		}
+	}
+	if err := json.Unmarshal(b, dest); err != nil {
+		return err
+	}
+	if destFP.IsValid() && destFP.Kind() == reflect.String {
+		destFP.SetString("")
+		b, err := json.Marshal(dest)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(b)
+		destFP.SetString(base64.StdEncoding.EncodeToString(sum[:8]))
+	}
+	return nil
+}
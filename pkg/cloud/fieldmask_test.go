@@ -0,0 +1,153 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+func TestApplyFieldMask(t *testing.T) {
+	t.Parallel()
+
+	bs := &ga.BackendService{
+		Name:        "bs",
+		Description: "desc",
+		Fingerprint: "abc",
+		TimeoutSec:  30,
+		Iap:         &ga.BackendServiceIAP{Enabled: true, Oauth2ClientId: "client"},
+		LogConfig:   &ga.BackendServiceLogConfig{Enable: false, SampleRate: 0.5},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		mask    []string
+		want    *ga.BackendService
+		wantErr bool
+	}{
+		{
+			name: "empty mask",
+			want: bs,
+		},
+		{
+			name: "json name",
+			mask: []string{"description"},
+			want: &ga.BackendService{Description: "desc", Fingerprint: "abc"},
+		},
+		{
+			name: "go name",
+			mask: []string{"TimeoutSec"},
+			want: &ga.BackendService{TimeoutSec: 30, Fingerprint: "abc"},
+		},
+		{
+			name: "nested field",
+			mask: []string{"logConfig.enable", "logConfig.sampleRate"},
+			want: &ga.BackendService{
+				Fingerprint: "abc",
+				LogConfig: &ga.BackendServiceLogConfig{
+					SampleRate:      0.5,
+					ForceSendFields: []string{"Enable"},
+				},
+			},
+		},
+		{
+			name: "zero valued scalar",
+			mask: []string{"affinityCookieTtlSec"},
+			want: &ga.BackendService{Fingerprint: "abc", ForceSendFields: []string{"AffinityCookieTtlSec"}},
+		},
+		{
+			name: "nil pointer",
+			mask: []string{"cdnPolicy"},
+			want: &ga.BackendService{Fingerprint: "abc", NullFields: []string{"CdnPolicy"}},
+		},
+		{
+			// Sending the parent as null would clear its other fields.
+			name:    "nil parent",
+			mask:    []string{"cdnPolicy.defaultTtl"},
+			wantErr: true,
+		},
+		{
+			name:    "nil parent of a masked sibling",
+			mask:    []string{"logConfig.enable", "cdnPolicy.defaultTtl"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			mask:    []string{"noSuchField"},
+			wantErr: true,
+		},
+		{
+			name:    "not a struct",
+			mask:    []string{"name.foo"},
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := applyFieldMask(bs, tc.mask)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("applyFieldMask(_, %v) = _, %v; gotErr = %t, want %t", tc.mask, err, gotErr, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("applyFieldMask(_, %v): -got,+want: %s", tc.mask, diff)
+			}
+		})
+	}
+}
+
+func TestMockPatchFieldMask(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"mock-project"})
+
+	var got *ga.BackendService
+	mock.MockBackendServices.PatchHook = func(_ context.Context, _ *meta.Key, bs *ga.BackendService, _ *MockBackendServices) error {
+		got = bs
+		return nil
+	}
+
+	key := meta.GlobalKey("bs")
+	bs := &ga.BackendService{Name: "bs", Description: "desc", TimeoutSec: 30}
+	if err := mock.BackendServices().Patch(ctx, key, bs, "timeoutSec"); err != nil {
+		t.Fatalf("Patch() = %v, want nil", err)
+	}
+	if want := (&ga.BackendService{TimeoutSec: 30}); !cmp.Equal(got, want) {
+		t.Errorf("PatchHook got %+v, want %+v", got, want)
+	}
+
+	if err := mock.BackendServices().Patch(ctx, key, bs); err != nil {
+		t.Fatalf("Patch() = %v, want nil", err)
+	}
+	if got != bs {
+		t.Errorf("PatchHook got %+v, want %+v", got, bs)
+	}
+
+	if err := mock.BackendServices().Patch(ctx, key, bs, "invalid"); err == nil {
+		t.Errorf("Patch(_, _, _, invalid) = nil, want error")
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	networkconnectivity "google.golang.org/api/networkconnectivity/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// NetworkConnectivity is an interface for the Network Connectivity Center
+// resources in the networkconnectivity.googleapis.com API.
+//
+// Resources are addressed with a meta.Key in the same way as NetworkServices.
+// Hubs only exist in the "global" location, so only global keys are valid
+// for Hubs().
+//
+// The GCE implementation is generated from meta.AllLocationServices.
+type NetworkConnectivity interface {
+	Hubs() NetworkConnectivityOps[networkconnectivity.Hub]
+	Spokes() NetworkConnectivityOps[networkconnectivity.Spoke]
+}
+
+// NetworkConnectivityProvider is the subset of Cloud that provides
+// NetworkConnectivity.
+type NetworkConnectivityProvider interface {
+	NetworkConnectivity() NetworkConnectivity
+}
+
+// NetworkConnectivityOps are the operations supported by the
+// networkconnectivity resources. Mutating methods wait for the long running
+// operation to complete.
+type NetworkConnectivityOps[T any] interface {
+	Get(ctx context.Context, key *meta.Key) (*T, error)
+	// List the resources in location. location is either "global" or a
+	// region.
+	List(ctx context.Context, location string) ([]*T, error)
+	Insert(ctx context.Context, key *meta.Key, obj *T) error
+	Delete(ctx context.Context, key *meta.Key) error
+	// Patch the resource. Only the fields in updateMask are changed. If
+	// updateMask is empty, all fields in obj are updated.
+	Patch(ctx context.Context, key *meta.Key, obj *T, updateMask ...string) error
+}
+
+var errNetworkConnectivityNotConfigured = errors.New("Service.NetworkConnectivity is not set")
+
+// networkConnectivityOperation is a google.longrunning.Operation returned by
+// the networkconnectivity API. The operations are polled with the
+// Operations service of the networkconnectivity API, not the compute one.
+type networkConnectivityOperation struct {
+	s         *Service
+	projectID string
+	name      string
+	done      bool
+	err       error
+}
+
+func (o *networkConnectivityOperation) String() string {
+	return fmt.Sprintf("networkConnectivityOperation{%q}", o.name)
+}
+
+func (o *networkConnectivityOperation) setOp(op *networkconnectivity.GoogleLongrunningOperation) {
+	o.name = op.Name
+	o.done = op.Done
+	if op.Done && op.Error != nil {
+		o.err = &NetworkConnectivityOperationError{Name: op.Name, Code: op.Error.Code, Message: op.Error.Message}
+	}
+}
+
+func (o *networkConnectivityOperation) isDone(ctx context.Context) (bool, error) {
+	if o.done {
+		return true, nil
+	}
+	op, err := o.s.NetworkConnectivity.Projects.Locations.Operations.Get(o.name).Context(ctx).Do()
+	klog.V(5).Infof("NetworkConnectivity.Operations.Get(%v) = %+v, %v; ctx = %v", o.name, op, err, ctx)
+	if err != nil {
+		return false, err
+	}
+	o.setOp(op)
+	return o.done, nil
+}
+
+func (o *networkConnectivityOperation) error() error {
+	return o.err
+}
+
+func (o *networkConnectivityOperation) rateLimitKey() *RateLimitKey {
+	return &RateLimitKey{
+		ProjectID: o.projectID,
+		Operation: "Get",
+		Service:   "NetworkConnectivityOperations",
+		Version:   meta.VersionGA,
+	}
+}
+
+// NetworkConnectivityOperationError is returned when a networkconnectivity
+// long running operation completes with an error. Code is the
+// google.rpc.Code of the failure.
+type NetworkConnectivityOperationError struct {
+	Name    string
+	Code    int64
+	Message string
+}
+
+func (e *NetworkConnectivityOperationError) Error() string {
+	return fmt.Sprintf("operation %s failed: %s (code %d)", e.Name, e.Message, e.Code)
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	networkservices "google.golang.org/api/networkservices/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// NetworkServices is an interface for the resources in the
+// networkservices.googleapis.com API.
+//
+// Resources are addressed with a meta.Key. A global key refers to the
+// "global" location, a regional key refers to the region of the key. Zonal
+// keys are not valid.
+//
+// The GCE implementation is generated from meta.AllLocationServices.
+type NetworkServices interface {
+	Gateways() NetworkServicesOps[networkservices.Gateway]
+	HTTPRoutes() NetworkServicesOps[networkservices.HttpRoute]
+	TCPRoutes() NetworkServicesOps[networkservices.TcpRoute]
+	TLSRoutes() NetworkServicesOps[networkservices.TlsRoute]
+	EndpointPolicies() NetworkServicesOps[networkservices.EndpointPolicy]
+}
+
+// NetworkServicesProvider is the subset of Cloud that provides
+// NetworkServices.
+type NetworkServicesProvider interface {
+	NetworkServices() NetworkServices
+}
+
+// NetworkServicesOps are the operations supported by the networkservices
+// resources. Mutating methods wait for the long running operation to
+// complete.
+type NetworkServicesOps[T any] interface {
+	Get(ctx context.Context, key *meta.Key) (*T, error)
+	// List the resources in location. location is either "global" or a
+	// region.
+	List(ctx context.Context, location string) ([]*T, error)
+	Insert(ctx context.Context, key *meta.Key, obj *T) error
+	Delete(ctx context.Context, key *meta.Key) error
+	// Patch the resource. Only the fields in updateMask are changed. If
+	// updateMask is empty, all fields in obj are updated.
+	Patch(ctx context.Context, key *meta.Key, obj *T, updateMask ...string) error
+}
+
+// NetworkServicesLocation returns the location for the key as used by the
+// networkservices API.
+func NetworkServicesLocation(key *meta.Key) (string, error) {
+	switch key.Type() {
+	case meta.Global:
+		return "global", nil
+	case meta.Regional:
+		return key.Region, nil
+	}
+	return "", fmt.Errorf("invalid networkservices key (%+v)", key)
+}
+
+// locationResourceName returns the parent and the relative resource name
+// for the key, e.g. "projects/p/locations/global" and
+// "projects/p/locations/global/gateways/gw". If global is true, only global
+// keys are valid.
+func locationResourceName(projectID, resource string, global bool, key *meta.Key) (string, string, error) {
+	key = normalizeKey(key)
+	if !key.Valid() {
+		return "", "", fmt.Errorf("invalid GCE key (%+v)", key)
+	}
+	if global && key.Type() != meta.Global {
+		return "", "", fmt.Errorf("invalid key for %s (%+v), must be global", resource, key)
+	}
+	loc, err := NetworkServicesLocation(key)
+	if err != nil {
+		return "", "", err
+	}
+	parent := fmt.Sprintf("projects/%s/locations/%s", projectID, loc)
+	return parent, fmt.Sprintf("%s/%s/%s", parent, resource, key.Name), nil
+}
+
+// locationProjectID returns the project of the relative resource name, e.g.
+// "p" for "projects/p/locations/global/operations/op".
+func locationProjectID(name string) string {
+	parts := strings.Split(name, "/")
+	if len(parts) < 2 || parts[0] != "projects" {
+		return ""
+	}
+	return parts[1]
+}
+
+var errNetworkServicesNotConfigured = errors.New("Service.NetworkServices is not set")
+
+// networkServicesOperation is a google.longrunning.Operation returned by the
+// networkservices API.
+type networkServicesOperation struct {
+	s         *Service
+	projectID string
+	name      string
+	done      bool
+	err       error
+}
+
+func (o *networkServicesOperation) String() string {
+	return fmt.Sprintf("networkServicesOperation{%q}", o.name)
+}
+
+func (o *networkServicesOperation) setOp(op *networkservices.Operation) {
+	o.name = op.Name
+	o.done = op.Done
+	if op.Done && op.Error != nil {
+		o.err = &NetworkServicesOperationError{Name: op.Name, Code: op.Error.Code, Message: op.Error.Message}
+	}
+}
+
+func (o *networkServicesOperation) isDone(ctx context.Context) (bool, error) {
+	if o.done {
+		return true, nil
+	}
+	op, err := o.s.NetworkServices.Projects.Locations.Operations.Get(o.name).Context(ctx).Do()
+	klog.V(5).Infof("NetworkServices.Operations.Get(%v) = %+v, %v; ctx = %v", o.name, op, err, ctx)
+	if err != nil {
+		return false, err
+	}
+	o.setOp(op)
+	return o.done, nil
+}
+
+func (o *networkServicesOperation) error() error {
+	return o.err
+}
+
+func (o *networkServicesOperation) rateLimitKey() *RateLimitKey {
+	return &RateLimitKey{
+		ProjectID: o.projectID,
+		Operation: "Get",
+		Service:   "Operations",
+		Version:   meta.VersionGA,
+	}
+}
+
+// NetworkServicesOperationError is returned when a networkservices long
+// running operation completes with an error. Code is the
+// google.rpc.Code of the failure.
+type NetworkServicesOperationError struct {
+	Name    string
+	Code    int64
+	Message string
+}
+
+func (e *NetworkServicesOperationError) Error() string {
+	return fmt.Sprintf("operation %s failed: %s (code %d)", e.Name, e.Message, e.Code)
+}
@@ -0,0 +1,453 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gcerrors has predicates for the errors returned by the API. The
+// errors of REST calls (*googleapi.Error), of gRPC calls (status errors),
+// of failed operations and of the mocks in package cloud are handled the
+// same way, so callers do not need to check the status code or match the
+// message themselves:
+//
+//	bs, err := gce.BackendServices().Get(ctx, key)
+//	switch {
+//	case gcerrors.IsNotFound(err):
+//		// Create it.
+//	case err != nil:
+//		return err
+//	}
+package gcerrors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Error is the status of an error returned by the API.
+type Error struct {
+	// Code is the HTTP status code. Codes of gRPC errors are mapped to the
+	// equivalent HTTP status code.
+	Code int
+	// Reason is the reason of the first error item (e.g. "notFound"), the
+	// reason of the ErrorInfo detail or the error code of a failed
+	// operation (e.g. "QUOTA_EXCEEDED"). It may be empty.
+	Reason string
+	// Domain of the first error item (e.g. "global") or of the ErrorInfo
+	// detail (e.g. "compute.googleapis.com").
+	Domain string
+	// Location and LocationType of the first error item, e.g. the
+	// parameter that is invalid.
+	Location     string
+	LocationType string
+	// Message of the error.
+	Message string
+	// Items are all the error items of the response.
+	Items []Item
+	// Metadata of the ErrorInfo detail.
+	Metadata map[string]string
+	// Help links of the error.
+	Help []HelpLink
+	// Header of the response of a REST call, e.g. with the Retry-After.
+	// It is nil for gRPC errors and for the errors of failed operations.
+	Header http.Header
+	// Err is the error this was parsed from.
+	Err error
+
+	// quota is the quota in the details, see Quota.
+	quota *quotaInfo
+}
+
+// Item is an error item of the response of a REST call.
+type Item struct {
+	Reason       string `json:"reason"`
+	Domain       string `json:"domain"`
+	Message      string `json:"message"`
+	Location     string `json:"location"`
+	LocationType string `json:"locationType"`
+}
+
+// HelpLink is a link to the documentation of an error.
+type HelpLink struct {
+	Description string
+	URL         string
+}
+
+// Error returns the code, the reason, the message and the first help link
+// of the error, e.g. "400 resourceNotReady: The resource is not ready
+// (see https://...)".
+func (e *Error) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d", e.Code)
+	if e.Reason != "" {
+		fmt.Fprintf(&b, " %s", e.Reason)
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	if len(e.Help) > 0 {
+		fmt.Fprintf(&b, " (see %s)", e.Help[0].URL)
+	}
+	return b.String()
+}
+
+// Unwrap returns the error this was parsed from.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// RetryAfter returns the wait asked for by the Retry-After header of the
+// response, in seconds or as a date. It returns false if there is none.
+func (e *Error) RetryAfter() (time.Duration, bool) {
+	v := e.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// opReason matches the error code at the start of the message of the error
+// of a failed operation, see op.go in package cloud.
+var opReason = regexp.MustCompile(`^([A-Z][A-Z0-9_]+) - `)
+
+// From returns the Error for err. It returns false if err (or an error it
+// wraps) is not an error from the API. The details of the error are parsed
+// from the response, so the Error has more than the *googleapi.Error it
+// wraps, e.g. the domain and the help links.
+func From(err error) (*Error, bool) {
+	if err == nil {
+		return nil, false
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		e := &Error{Code: gerr.Code, Message: gerr.Message, Header: gerr.Header, Err: err}
+		e.Items = bodyItems(gerr.Body)
+		if len(e.Items) == 0 {
+			for _, item := range gerr.Errors {
+				e.Items = append(e.Items, Item{Reason: item.Reason, Message: item.Message})
+			}
+		}
+		if len(e.Items) > 0 {
+			first := e.Items[0]
+			e.Reason, e.Domain, e.Location, e.LocationType = first.Reason, first.Domain, first.Location, first.LocationType
+		} else if m := opReason.FindStringSubmatch(gerr.Message); m != nil {
+			e.Reason = m[1]
+		}
+		e.setHTTPDetails(gerr.Details)
+		return e, true
+	}
+	if s, ok := Status(err); ok && s.Code() != codes.OK && s.Code() != codes.Unknown {
+		e := &Error{Code: grpcToHTTP[s.Code()], Message: s.Message(), Err: err}
+		e.setGRPCDetails(s.Details())
+		if e.Reason == "" {
+			e.Reason = s.Code().String()
+		}
+		return e, true
+	}
+	return nil, false
+}
+
+// Status returns the status of the gRPC error in err. Unlike
+// status.FromError, it finds the status of a wrapped error.
+func Status(err error) (*status.Status, bool) {
+	var se interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &se) {
+		return se.GRPCStatus(), true
+	}
+	return nil, false
+}
+
+// bodyItems returns the error items of the JSON body of a REST response.
+// googleapi.ErrorItem does not have the domain and the location.
+func bodyItems(body string) []Item {
+	if body == "" {
+		return nil
+	}
+	var reply struct {
+		Error struct {
+			Errors []Item `json:"errors"`
+		} `json:"error"`
+	}
+	if json.Unmarshal([]byte(body), &reply) != nil {
+		return nil
+	}
+	return reply.Error.Errors
+}
+
+// detail is the JSON of the details of the REST errors used by Error. The
+// details of the errors of operations have the ErrorInfo, Help or
+// QuotaInfo in a field instead of the @type.
+type detail struct {
+	Type     string            `json:"@type"`
+	Reason   string            `json:"reason"`
+	Domain   string            `json:"domain"`
+	Metadata map[string]string `json:"metadata"`
+	Links    []struct {
+		Description string `json:"description"`
+		URL         string `json:"url"`
+	} `json:"links"`
+	// Violations of a QuotaFailure.
+	Violations []struct {
+		Subject     string `json:"subject"`
+		Description string `json:"description"`
+	} `json:"violations"`
+
+	ErrorInfo *detail    `json:"errorInfo"`
+	Help      *detail    `json:"help"`
+	QuotaInfo *quotaInfo `json:"quotaInfo"`
+}
+
+// quotaInfo is the QuotaExceededInfo of the error of an operation.
+type quotaInfo struct {
+	MetricName string            `json:"metricName"`
+	LimitName  string            `json:"limitName"`
+	Limit      float64           `json:"limit"`
+	Dimensions map[string]string `json:"dimensions"`
+}
+
+// setHTTPDetails sets the fields of e from the details of a REST error or
+// of the error of an operation (ErrorInfo, Help and the quota).
+func (e *Error) setHTTPDetails(details []interface{}) {
+	for _, raw := range details {
+		b, err := json.Marshal(raw)
+		if err != nil {
+			continue
+		}
+		var d detail
+		if json.Unmarshal(b, &d) != nil {
+			continue
+		}
+		switch d.Type {
+		case "type.googleapis.com/google.rpc.ErrorInfo":
+			e.setErrorInfo(d.Reason, d.Domain, d.Metadata)
+		case "type.googleapis.com/google.rpc.Help":
+			e.addHelp(&d)
+		case "type.googleapis.com/google.rpc.QuotaFailure":
+			if len(d.Violations) > 0 && e.quota == nil {
+				e.quota = &quotaInfo{MetricName: d.Violations[0].Subject}
+			}
+		}
+		if d.ErrorInfo != nil {
+			e.setErrorInfo(d.ErrorInfo.Reason, d.ErrorInfo.Domain, d.ErrorInfo.Metadata)
+		}
+		if d.Help != nil {
+			e.addHelp(d.Help)
+		}
+		if d.QuotaInfo != nil {
+			e.quota = d.QuotaInfo
+		}
+	}
+}
+
+func (e *Error) addHelp(d *detail) {
+	for _, link := range d.Links {
+		e.Help = append(e.Help, HelpLink{Description: link.Description, URL: link.URL})
+	}
+}
+
+// setGRPCDetails sets the fields of e from the details of a gRPC status.
+func (e *Error) setGRPCDetails(details []interface{}) {
+	for _, d := range details {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			e.setErrorInfo(d.GetReason(), d.GetDomain(), d.GetMetadata())
+		case *errdetails.Help:
+			for _, link := range d.GetLinks() {
+				e.Help = append(e.Help, HelpLink{Description: link.GetDescription(), URL: link.GetUrl()})
+			}
+		case *errdetails.QuotaFailure:
+			if v := d.GetViolations(); len(v) > 0 && e.quota == nil {
+				e.quota = &quotaInfo{MetricName: v[0].GetSubject()}
+			}
+		}
+	}
+}
+
+func (e *Error) setErrorInfo(reason, domain string, metadata map[string]string) {
+	if e.Reason == "" {
+		e.Reason = reason
+	}
+	if e.Domain == "" {
+		e.Domain = domain
+	}
+	e.Metadata = metadata
+}
+
+// grpcToHTTP maps the gRPC codes to the HTTP status codes, following
+// google.rpc.Code.
+var grpcToHTTP = map[codes.Code]int{
+	codes.Canceled:           499,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+}
+
+// Code returns the HTTP status code of err or 0 if err is not an error
+// from the API.
+func Code(err error) int {
+	if e, ok := From(err); ok {
+		return e.Code
+	}
+	return 0
+}
+
+// Reason returns the reason of err or "" if it has none.
+func Reason(err error) string {
+	if e, ok := From(err); ok {
+		return e.Reason
+	}
+	return ""
+}
+
+// quotaReasons are the reasons of the quota and rate limit errors.
+var quotaReasons = map[string]bool{
+	"quotaExceeded":         true,
+	"rateLimitExceeded":     true,
+	"userRateLimitExceeded": true,
+	"dailyLimitExceeded":    true,
+	"limitExceeded":         true,
+	"QUOTA_EXCEEDED":        true,
+	"RATE_LIMIT_EXCEEDED":   true,
+	"ResourceExhausted":     true,
+}
+
+// IsNotFound is true if the resource does not exist.
+func IsNotFound(err error) bool {
+	return Code(err) == http.StatusNotFound
+}
+
+// IsConflict is true if the call conflicts with the state of the resource,
+// e.g. the resource already exists or another operation on it is running.
+func IsConflict(err error) bool {
+	return Code(err) == http.StatusConflict
+}
+
+// IsAlreadyExists is true if the resource to create already exists.
+func IsAlreadyExists(err error) bool {
+	e, ok := From(err)
+	return ok && e.Code == http.StatusConflict && (e.Reason == "alreadyExists" || e.Reason == "RESOURCE_ALREADY_EXISTS" || e.Reason == codes.AlreadyExists.String())
+}
+
+// IsPreconditionFailed is true if the fingerprint or the ETag sent with the
+// call is not the current one.
+func IsPreconditionFailed(err error) bool {
+	return Code(err) == http.StatusPreconditionFailed
+}
+
+// IsNotModified is true if the resource still has the ETag sent with
+// If-None-Match.
+func IsNotModified(err error) bool {
+	return Code(err) == http.StatusNotModified
+}
+
+// IsBadRequest is true if the request is invalid.
+func IsBadRequest(err error) bool {
+	return Code(err) == http.StatusBadRequest
+}
+
+// IsForbidden is true if the caller is not allowed to make the call.
+func IsForbidden(err error) bool {
+	return Code(err) == http.StatusForbidden
+}
+
+// IsQuotaExceeded is true if the call was rejected by a quota or a rate
+// limit.
+func IsQuotaExceeded(err error) bool {
+	e, ok := From(err)
+	return ok && (e.Code == http.StatusTooManyRequests || quotaReasons[e.Reason])
+}
+
+// IsServerError is true if the call failed with a 5xx error.
+func IsServerError(err error) bool {
+	return Code(err) >= 500
+}
+
+// IsResourceNotReady is true if the call was rejected because a resource it
+// uses is not ready yet, e.g. it is being created. The call usually
+// succeeds if it is made again a few seconds later.
+func IsResourceNotReady(err error) bool {
+	e, ok := From(err)
+	return ok && (e.Reason == "resourceNotReady" || e.Reason == "RESOURCE_NOT_READY" || strings.Contains(e.Message, "is not ready"))
+}
+
+// IsInUse is true if the call was rejected because the resource is used by
+// another resource, e.g. the deletion of a HealthCheck that is used by a
+// BackendService. The call succeeds once the other resource stops using
+// it.
+func IsInUse(err error) bool {
+	e, ok := From(err)
+	return ok && (e.Reason == "resourceInUseByAnotherResource" || e.Reason == "RESOURCE_IN_USE_BY_ANOTHER_RESOURCE" || strings.Contains(e.Message, "being used by"))
+}
+
+// IsCanceled is true if the call ended because the caller canceled its
+// context.
+func IsCanceled(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return true
+	}
+	s, ok := Status(err)
+	return ok && s.Code() == codes.Canceled
+}
+
+// IsDeadlineExceeded is true if the call ended because the deadline of the
+// context of the caller passed, i.e. the caller gave up waiting. See
+// IsServerTimeout for the API timing out.
+func IsDeadlineExceeded(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+// IsServerTimeout is true if the API timed out before the caller gave up,
+// e.g. 504 Gateway Timeout. The call may succeed if it is made again.
+//
+// gRPC DeadlineExceeded errors do not tell whose deadline passed. The
+// calls made through package cloud wrap the error of the context of the
+// caller if it is done, so only the other ones are server timeouts.
+func IsServerTimeout(err error) bool {
+	return Code(err) == http.StatusGatewayTimeout && !IsDeadlineExceeded(err) && !IsCanceled(err)
+}
@@ -0,0 +1,370 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcerrors_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/gcerrors"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPredicates(t *testing.T) {
+	t.Parallel()
+
+	gce := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj"})
+	_, mockErr := gce.BackendServices().Get(context.Background(), meta.GlobalKey("missing"))
+
+	type predicate struct {
+		name string
+		f    func(error) bool
+	}
+	var (
+		notFound      = predicate{"IsNotFound", gcerrors.IsNotFound}
+		conflict      = predicate{"IsConflict", gcerrors.IsConflict}
+		alreadyExists = predicate{"IsAlreadyExists", gcerrors.IsAlreadyExists}
+		precondition  = predicate{"IsPreconditionFailed", gcerrors.IsPreconditionFailed}
+		quota         = predicate{"IsQuotaExceeded", gcerrors.IsQuotaExceeded}
+		server        = predicate{"IsServerError", gcerrors.IsServerError}
+		notReady      = predicate{"IsResourceNotReady", gcerrors.IsResourceNotReady}
+		inUse         = predicate{"IsInUse", gcerrors.IsInUse}
+		canceled      = predicate{"IsCanceled", gcerrors.IsCanceled}
+		deadline      = predicate{"IsDeadlineExceeded", gcerrors.IsDeadlineExceeded}
+		timeout       = predicate{"IsServerTimeout", gcerrors.IsServerTimeout}
+		all           = []predicate{notFound, conflict, alreadyExists, precondition, quota, server, notReady, inUse, canceled, deadline, timeout}
+	)
+	for _, tc := range []struct {
+		desc string
+		err  error
+		want []predicate
+	}{
+		{desc: "nil"},
+		{desc: "not an API error", err: errors.New("invalid GCE key")},
+		{desc: "REST not found", err: &googleapi.Error{Code: http.StatusNotFound}, want: []predicate{notFound}},
+		{desc: "wrapped not found", err: fmt.Errorf("x: %w", &googleapi.Error{Code: http.StatusNotFound}), want: []predicate{notFound}},
+		{desc: "mock not found", err: mockErr, want: []predicate{notFound}},
+		{desc: "grpc not found", err: status.Error(codes.NotFound, "x"), want: []predicate{notFound}},
+		{
+			desc: "REST already exists",
+			err:  &googleapi.Error{Code: http.StatusConflict, Errors: []googleapi.ErrorItem{{Reason: "alreadyExists"}}},
+			want: []predicate{conflict, alreadyExists},
+		},
+		{
+			desc: "operation in progress",
+			err:  &googleapi.Error{Code: http.StatusConflict, Errors: []googleapi.ErrorItem{{Reason: "conflict"}}},
+			want: []predicate{conflict},
+		},
+		{desc: "grpc already exists", err: status.Error(codes.AlreadyExists, "x"), want: []predicate{conflict, alreadyExists}},
+		{desc: "fingerprint", err: &googleapi.Error{Code: http.StatusPreconditionFailed}, want: []predicate{precondition}},
+		{desc: "429", err: &googleapi.Error{Code: http.StatusTooManyRequests}, want: []predicate{quota}},
+		{
+			desc: "403 rate limit",
+			err:  &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}}},
+			want: []predicate{quota},
+		},
+		{
+			desc: "operation quota",
+			err:  &googleapi.Error{Code: http.StatusForbidden, Message: "QUOTA_EXCEEDED - Quota 'CPUS' exceeded"},
+			want: []predicate{quota},
+		},
+		{desc: "grpc exhausted", err: status.Error(codes.ResourceExhausted, "x"), want: []predicate{quota}},
+		{desc: "503", err: &googleapi.Error{Code: http.StatusServiceUnavailable}, want: []predicate{server}},
+		{desc: "grpc internal", err: status.Error(codes.Internal, "x"), want: []predicate{server}},
+		{
+			desc: "REST not ready",
+			err:  &googleapi.Error{Code: http.StatusBadRequest, Errors: []googleapi.ErrorItem{{Reason: "resourceNotReady"}}},
+			want: []predicate{notReady},
+		},
+		{
+			desc: "operation not ready",
+			err:  &googleapi.Error{Code: http.StatusBadRequest, Message: "RESOURCE_NOT_READY - The resource 'hc' is not ready"},
+			want: []predicate{notReady},
+		},
+		{
+			desc: "REST in use",
+			err:  &googleapi.Error{Code: http.StatusBadRequest, Errors: []googleapi.ErrorItem{{Reason: "resourceInUseByAnotherResource"}}},
+			want: []predicate{inUse},
+		},
+		{desc: "mock in use", err: mock.InUseError, want: []predicate{inUse}},
+		{desc: "canceled", err: fmt.Errorf("x: %w", context.Canceled), want: []predicate{canceled}},
+		{desc: "grpc canceled", err: status.Error(codes.Canceled, "x"), want: []predicate{canceled}},
+		{desc: "deadline", err: fmt.Errorf("x: %w", context.DeadlineExceeded), want: []predicate{deadline}},
+		{desc: "504", err: &googleapi.Error{Code: http.StatusGatewayTimeout}, want: []predicate{server, timeout}},
+		{desc: "grpc deadline", err: status.Error(codes.DeadlineExceeded, "x"), want: []predicate{server, timeout}},
+		{
+			desc: "grpc deadline of the caller",
+			err:  fmt.Errorf("%w: %w", context.DeadlineExceeded, status.Error(codes.DeadlineExceeded, "x")),
+			want: []predicate{server, deadline},
+		},
+	} {
+		want := map[string]bool{}
+		for _, p := range tc.want {
+			want[p.name] = true
+		}
+		for _, p := range all {
+			if got := p.f(tc.err); got != want[p.name] {
+				t.Errorf("%s: %s(%v) = %t, want %t", tc.desc, p.name, tc.err, got, want[p.name])
+			}
+		}
+	}
+}
+
+func TestFrom(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		desc   string
+		err    error
+		wantOK bool
+		want   gcerrors.Error
+	}{
+		{desc: "nil"},
+		{desc: "not an API error", err: errors.New("x")},
+		{
+			desc:   "error item",
+			err:    &googleapi.Error{Code: http.StatusNotFound, Message: "m", Errors: []googleapi.ErrorItem{{Reason: "notFound"}}},
+			wantOK: true,
+			want:   gcerrors.Error{Code: http.StatusNotFound, Reason: "notFound", Message: "m"},
+		},
+		{
+			desc:   "operation error",
+			err:    &googleapi.Error{Code: http.StatusBadRequest, Message: "RESOURCE_NOT_READY - The resource is not ready"},
+			wantOK: true,
+			want:   gcerrors.Error{Code: http.StatusBadRequest, Reason: "RESOURCE_NOT_READY", Message: "RESOURCE_NOT_READY - The resource is not ready"},
+		},
+		{
+			desc:   "grpc",
+			err:    status.Error(codes.Unavailable, "m"),
+			wantOK: true,
+			want:   gcerrors.Error{Code: http.StatusServiceUnavailable, Reason: "Unavailable", Message: "m"},
+		},
+	} {
+		got, ok := gcerrors.From(tc.err)
+		if ok != tc.wantOK {
+			t.Errorf("%s: From(%v) = _, %t, want %t", tc.desc, tc.err, ok, tc.wantOK)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if got.Code != tc.want.Code || got.Reason != tc.want.Reason || got.Message != tc.want.Message {
+			t.Errorf("%s: From(%v) = %+v, want %+v", tc.desc, tc.err, *got, tc.want)
+		}
+		if !errors.Is(got, tc.err) {
+			t.Errorf("%s: errors.Is(From(err), err) = false, want true", tc.desc)
+		}
+	}
+}
+
+func TestFromDetails(t *testing.T) {
+	t.Parallel()
+
+	const body = `{"error": {
+		"code": 400,
+		"message": "Invalid value for field 'resource.port'",
+		"errors": [{"message": "Invalid value for field 'resource.port'", "domain": "global", "reason": "invalid", "location": "resource.port", "locationType": "other"}]
+	}}`
+	gerr := &googleapi.Error{
+		Code:    http.StatusBadRequest,
+		Message: "Invalid value for field 'resource.port'",
+		Body:    body,
+		Errors:  []googleapi.ErrorItem{{Reason: "invalid", Message: "Invalid value for field 'resource.port'"}},
+		Details: []interface{}{
+			map[string]interface{}{
+				"@type":    "type.googleapis.com/google.rpc.ErrorInfo",
+				"reason":   "INVALID_PORT",
+				"domain":   "compute.googleapis.com",
+				"metadata": map[string]interface{}{"port": "0"},
+			},
+			map[string]interface{}{
+				"@type": "type.googleapis.com/google.rpc.Help",
+				"links": []interface{}{map[string]interface{}{"description": "Ports", "url": "https://cloud.google.com/ports"}},
+			},
+		},
+	}
+	err := fmt.Errorf("sync: %w", gerr)
+
+	e, ok := gcerrors.From(err)
+	if !ok {
+		t.Fatalf("From(%v) = _, false, want true", err)
+	}
+	if e.Reason != "invalid" || e.Domain != "global" || e.Location != "resource.port" || e.LocationType != "other" {
+		t.Errorf("From() = %+v, want the reason, domain and location of the first error item", *e)
+	}
+	if got := e.Metadata["port"]; got != "0" {
+		t.Errorf("Metadata[port] = %q, want %q", got, "0")
+	}
+	if len(e.Help) != 1 || e.Help[0].URL != "https://cloud.google.com/ports" {
+		t.Errorf("Help = %+v, want the link of the Help detail", e.Help)
+	}
+	const wantMsg = "400 invalid: Invalid value for field 'resource.port' (see https://cloud.google.com/ports)"
+	if got := e.Error(); got != wantMsg {
+		t.Errorf("Error() = %q, want %q", got, wantMsg)
+	}
+
+	// An Error wrapped again is found with errors.As and From.
+	wrapped := fmt.Errorf("reconcile: %w", e)
+	var got *gcerrors.Error
+	if !errors.As(wrapped, &got) || got != e {
+		t.Errorf("errors.As(%v) = %v, want %v", wrapped, got, e)
+	}
+	if got, _ := gcerrors.From(wrapped); got != e {
+		t.Errorf("From(%v) = %v, want %v", wrapped, got, e)
+	}
+
+	// Without error items, the reason and domain are the ones of the
+	// ErrorInfo.
+	gerr.Body, gerr.Errors = "", nil
+	e, _ = gcerrors.From(gerr)
+	if e.Reason != "INVALID_PORT" || e.Domain != "compute.googleapis.com" {
+		t.Errorf("From() = %+v, want the reason and domain of the ErrorInfo", *e)
+	}
+
+	// gRPC errors have the details in the status.
+	st, err := status.New(codes.InvalidArgument, "invalid port").WithDetails(
+		&errdetails.ErrorInfo{Reason: "INVALID_PORT", Domain: "compute.googleapis.com"},
+		&errdetails.Help{Links: []*errdetails.Help_Link{{Url: "https://cloud.google.com/ports"}}},
+	)
+	if err != nil {
+		t.Fatalf("WithDetails() = _, %v, want nil", err)
+	}
+	e, _ = gcerrors.From(st.Err())
+	if e.Reason != "INVALID_PORT" || e.Domain != "compute.googleapis.com" || len(e.Help) != 1 {
+		t.Errorf("From() = %+v, want the details of the status", *e)
+	}
+}
+
+func TestQuota(t *testing.T) {
+	t.Parallel()
+
+	const opMsg = "QUOTA_EXCEEDED - Quota 'CPUS' exceeded.  Limit: 24.0 in region us-central1."
+	grpcErr, err := status.New(codes.ResourceExhausted, "quota exceeded").WithDetails(
+		&errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{{Subject: "compute.googleapis.com/cpus"}}},
+	)
+	if err != nil {
+		t.Fatalf("WithDetails() = _, %v, want nil", err)
+	}
+	for _, tc := range []struct {
+		desc    string
+		err     error
+		wantOK  bool
+		want    gcerrors.QuotaError
+		wantMsg string
+	}{
+		{desc: "nil"},
+		{desc: "not a quota error", err: &googleapi.Error{Code: http.StatusNotFound}},
+		{
+			desc:    "operation message",
+			err:     &googleapi.Error{Code: http.StatusForbidden, Message: opMsg},
+			wantOK:  true,
+			want:    gcerrors.QuotaError{Metric: "CPUS", Limit: 24, Dimensions: map[string]string{"region": "us-central1"}},
+			wantMsg: "quota CPUS exceeded (limit 24, region=us-central1): 403 QUOTA_EXCEEDED: " + opMsg,
+		},
+		{
+			desc: "operation details",
+			err: &googleapi.Error{
+				Code:    http.StatusForbidden,
+				Message: opMsg,
+				Details: []interface{}{&ga.OperationErrorErrorsErrorDetails{
+					QuotaInfo: &ga.QuotaExceededInfo{
+						MetricName: "compute.googleapis.com/cpus",
+						LimitName:  "CPUS-per-project-region",
+						Limit:      24,
+						Dimensions: map[string]string{"region": "us-central1"},
+					},
+				}},
+			},
+			wantOK: true,
+			want: gcerrors.QuotaError{
+				Metric:     "compute.googleapis.com/cpus",
+				LimitName:  "CPUS-per-project-region",
+				Limit:      24,
+				Dimensions: map[string]string{"region": "us-central1"},
+			},
+		},
+		{
+			desc:   "usage in the message",
+			err:    &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "quotaExceeded"}}, Message: "Quota 'FORWARDING_RULES' exceeded.  Limit: 75.0 globally. Usage: 75.0."},
+			wantOK: true,
+			want:   gcerrors.QuotaError{Metric: "FORWARDING_RULES", Limit: 75, Usage: 75},
+		},
+		{
+			desc:   "grpc",
+			err:    grpcErr.Err(),
+			wantOK: true,
+			want:   gcerrors.QuotaError{Metric: "compute.googleapis.com/cpus"},
+		},
+	} {
+		got, ok := gcerrors.Quota(tc.err)
+		if ok != tc.wantOK {
+			t.Errorf("%s: Quota(%v) = _, %t, want %t", tc.desc, tc.err, ok, tc.wantOK)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if diff := cmp.Diff(tc.want, *got, cmpopts.IgnoreFields(gcerrors.QuotaError{}, "Err")); diff != "" {
+			t.Errorf("%s: Quota(%v): -want +got: %s", tc.desc, tc.err, diff)
+		}
+		if !errors.Is(got, tc.err) {
+			t.Errorf("%s: errors.Is(Quota(err), err) = false, want true", tc.desc)
+		}
+		if tc.wantMsg != "" && got.Error() != tc.wantMsg {
+			t.Errorf("%s: Error() = %q, want %q", tc.desc, got.Error(), tc.wantMsg)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		desc   string
+		header string
+		want   time.Duration
+		wantOK bool
+	}{
+		{desc: "none"},
+		{desc: "seconds", header: "30", want: 30 * time.Second, wantOK: true},
+		{desc: "past date", header: "Mon, 02 Jan 2006 15:04:05 GMT", wantOK: true},
+		{desc: "invalid", header: "soon"},
+	} {
+		gerr := &googleapi.Error{Code: http.StatusTooManyRequests, Header: http.Header{}}
+		if tc.header != "" {
+			gerr.Header.Set("Retry-After", tc.header)
+		}
+		e, _ := gcerrors.From(gerr)
+		got, ok := e.RetryAfter()
+		if got != tc.want || ok != tc.wantOK {
+			t.Errorf("%s: RetryAfter() = %v, %t, want %v, %t", tc.desc, got, ok, tc.want, tc.wantOK)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcerrors
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// QuotaError is a quota error with the quota that was exceeded, so that
+// callers can report which quota to raise instead of the raw message.
+//
+//	if q, ok := gcerrors.Quota(err); ok {
+//		log.Printf("raise %s (limit %v in %v)", q.Metric, q.Limit, q.Dimensions)
+//	}
+type QuotaError struct {
+	// Metric is the quota metric, e.g. "compute.googleapis.com/cpus". If
+	// the error only has a message, it is the name of the quota in the
+	// message, e.g. "CPUS".
+	Metric string
+	// LimitName is the name of the limit, e.g. "CPUS-per-project-region".
+	// It is empty if the error does not have it.
+	LimitName string
+	// Limit of the quota. It is 0 if the error does not have it.
+	Limit float64
+	// Usage of the quota when the call was made. It is 0 if the error does
+	// not have it: the API does not always report it.
+	Usage float64
+	// Dimensions of the quota, e.g. {"region": "us-central1"}.
+	Dimensions map[string]string
+	// Err is the error this was parsed from.
+	Err *Error
+}
+
+// Error returns the quota that was exceeded, e.g. "quota CPUS exceeded
+// (limit 24, region=us-central1): ...".
+func (q *QuotaError) Error() string {
+	var attrs []string
+	if q.Limit != 0 {
+		attrs = append(attrs, "limit "+strconv.FormatFloat(q.Limit, 'f', -1, 64))
+	}
+	if q.Usage != 0 {
+		attrs = append(attrs, "usage "+strconv.FormatFloat(q.Usage, 'f', -1, 64))
+	}
+	if q.LimitName != "" {
+		attrs = append(attrs, "limit name "+q.LimitName)
+	}
+	var dims []string
+	for k, v := range q.Dimensions {
+		dims = append(dims, k+"="+v)
+	}
+	sort.Strings(dims)
+	attrs = append(attrs, dims...)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "quota %s exceeded", q.Metric)
+	if len(attrs) > 0 {
+		fmt.Fprintf(&b, " (%s)", strings.Join(attrs, ", "))
+	}
+	fmt.Fprintf(&b, ": %v", q.Err)
+	return b.String()
+}
+
+// Unwrap returns the Error this was parsed from.
+func (q *QuotaError) Unwrap() error {
+	return q.Err
+}
+
+var (
+	// quotaMessage matches the messages of the quota errors of operations,
+	// e.g. "Quota 'CPUS' exceeded.  Limit: 24.0 in region us-central1.".
+	quotaMessage = regexp.MustCompile(`Quota '([^']+)' exceeded\.\s*Limit: ([0-9.]+)(?: in (region|zone) ([^ ]+?)\.?(?:\s|$))?`)
+	// quotaUsage matches the usage in the message, if any.
+	quotaUsage = regexp.MustCompile(`Usage: ([0-9.]+)`)
+)
+
+// Quota returns the QuotaError for err. It returns false if err is not a
+// quota error (see IsQuotaExceeded). The quota is parsed from the details
+// of the error (QuotaExceededInfo, QuotaFailure), and from the message if
+// there are none.
+func Quota(err error) (*QuotaError, bool) {
+	var q *QuotaError
+	if errors.As(err, &q) {
+		return q, true
+	}
+	if !IsQuotaExceeded(err) {
+		return nil, false
+	}
+	e, _ := From(err)
+	q = &QuotaError{Err: e}
+	if e.quota != nil {
+		q.Metric, q.LimitName, q.Limit, q.Dimensions = e.quota.MetricName, e.quota.LimitName, e.quota.Limit, e.quota.Dimensions
+	}
+	if m := quotaMessage.FindStringSubmatch(e.Message); m != nil {
+		if q.Metric == "" {
+			q.Metric = m[1]
+		}
+		if q.Limit == 0 {
+			q.Limit, _ = strconv.ParseFloat(strings.TrimSuffix(m[2], "."), 64)
+		}
+		if q.Dimensions == nil && m[3] != "" {
+			q.Dimensions = map[string]string{m[3]: m[4]}
+		}
+	}
+	if m := quotaUsage.FindStringSubmatch(e.Message); m != nil {
+		q.Usage, _ = strconv.ParseFloat(strings.TrimSuffix(m[1], "."), 64)
+	}
+	return q, true
+}
@@ -22,15 +22,26 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"go/build"
 	"io"
 	"log"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"text/template"
 	"time"
 
+	_ "cloud.google.com/go/compute/apiv1/computepb"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 )
 
@@ -39,10 +50,12 @@ const (
 	packageRoot         = "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	googleAPIPackage    = "google.golang.org/api/googleapi"
 	kLogPackage         = "k8s.io/klog/v2"
+	logrPackage         = "github.com/go-logr/logr"
 	alphaComputePackage = "google.golang.org/api/compute/v0.alpha"
 	betaComputePackage  = "google.golang.org/api/compute/v0.beta"
 	gaComputePackage    = "google.golang.org/api/compute/v1"
 	kLogEnabled         = ".Enabled()"
+	computeProtoPackage = "google.cloud.compute.v1"
 
 	filterPackage = packageRoot + "/filter"
 	metaPackage   = packageRoot + "/meta"
@@ -53,13 +66,49 @@ const (
 )
 
 var flags = struct {
-	gofmt bool
-	mode  string
+	gofmt    bool
+	mode     string
+	services string
 }{}
 
 func init() {
 	flag.BoolVar(&flags.gofmt, "gofmt", true, "run output through gofmt")
-	flag.StringVar(&flags.mode, "mode", "src", "content to generate: src, test, dummy")
+	flag.StringVar(&flags.mode, "mode", "src", "content to generate: src, test, grpc, cloud")
+	flag.StringVar(&flags.services, "services", "", "with -mode cloud, comma separated list of services of the gce_subset build (e.g. \"BackendServices,NetworkEndpointGroups\"), all services if empty")
+}
+
+// requiredServices are used by the hand written code in the package and are
+// always part of the gce_subset Cloud.
+var requiredServices = []string{
+	"Zones", // CachedTopology.
+}
+
+// selectServices restricts the services of the Cloud interface to the
+// services named in the -services flag. The result is built with the
+// gce_subset tag instead of gen_cloud.go, so that the binary only links the
+// resources that it uses. Services with CustomOps are always kept, as their
+// Ops are implemented by hand written code in the package.
+func selectServices() {
+	if flags.services == "" {
+		return
+	}
+	if flags.mode != "cloud" {
+		log.Fatalf("Invalid -services: only supported with -mode cloud")
+	}
+	names := map[string]bool{}
+	for _, name := range requiredServices {
+		names[name] = true
+	}
+	for _, name := range strings.Split(flags.services, ",") {
+		name = strings.TrimSpace(name)
+		if _, ok := meta.AllServicesByGroup[name]; !ok {
+			log.Fatalf("Invalid -services: unknown service %q", name)
+		}
+		names[name] = true
+	}
+	meta.SelectServices(func(s *meta.ServiceInfo) bool {
+		return names[s.Service] || s.GenerateCustomOps()
+	})
 }
 
 // gofmtContent runs "gofmt" on the given contents.
@@ -105,8 +154,10 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 
+	"{{.LogrPackage}}"
 	"{{.GoogleAPIPackage}}"
 	"{{.KLogPackage}}"
 
@@ -119,6 +170,7 @@ import (
 		"Year":             fmt.Sprintf("%v", time.Now().Year()),
 		"GoogleAPIPackage": googleAPIPackage,
 		"KLogPackage":      kLogPackage,
+		"LogrPackage":      logrPackage,
 		"FilterPackage":    filterPackage,
 		"MetaPackage":      metaPackage,
 	}
@@ -146,6 +198,13 @@ import (
 	if hasGA {
 		fmt.Fprintf(wr, "	ga \"%s\"\n", gaComputePackage)
 	}
+	packages := map[string]bool{}
+	for _, s := range meta.AllLocationServices {
+		if !packages[s.Package()] {
+			packages[s.Package()] = true
+			fmt.Fprintf(wr, "	%s \"%s\"\n", s.Package(), s.GoPackage())
+		}
+	}
 
 	fmt.Fprintf(wr, ")\n\n")
 
@@ -166,43 +225,9 @@ func kLogEnabled(level klog.Level) bool {
 	}
 }
 
-// genStubs generates the interface and wrapper stubs.
+// genStubs generates the MockGCE stubs.
 func genStubs(wr io.Writer) {
-	const text = `// Cloud is an interface for the GCE compute API.
-type Cloud interface {
-{{- range .All}}
-	{{.WrapType}}() {{.WrapType}}
-{{- end}}
-}
-
-// NewGCE returns a GCE.
-func NewGCE(s *Service) *GCE {
-	g := &GCE{
-	{{- range .All}}
-		{{.Field}}: &{{.GCEWrapType}}{s},
-	{{- end}}
-	}
-	return g
-}
-
-// GCE implements Cloud.
-var _ Cloud = (*GCE)(nil)
-
-// GCE is the golang adapter for the compute APIs.
-type GCE struct {
-{{- range .All}}
-	{{.Field}} *{{.GCEWrapType}}
-{{- end}}
-}
-
-{{range .All}}
-// {{.WrapType}} returns the interface for the {{.Version}} {{.Service}}.
-func (gce *GCE) {{.WrapType}}() {{.WrapType}} {
-	return gce.{{.Field}}
-}
-{{- end}}
-
-// NewMockGCE returns a new mock for GCE.
+	const text = `// NewMockGCE returns a new mock for GCE.
 func NewMockGCE(projectRouter ProjectRouter) *MockGCE {
 	{{- range .Groups}}
 	mock{{.Service}}Objs := map[meta.Key]*Mock{{.Service}}Obj{}
@@ -212,6 +237,8 @@ func NewMockGCE(projectRouter ProjectRouter) *MockGCE {
 	{{- range .All}}
 		{{.MockField}}: New{{.MockWrapType}}(projectRouter, mock{{.Service}}Objs),
 	{{- end}}
+		MockNetworkServices: NewMockNetworkServices(projectRouter),
+		MockNetworkConnectivity: NewMockNetworkConnectivity(projectRouter),
 	}
 	return mock
 }
@@ -219,11 +246,20 @@ func NewMockGCE(projectRouter ProjectRouter) *MockGCE {
 // MockGCE implements Cloud.
 var _ Cloud = (*MockGCE)(nil)
 
+// SetLogger sets the Logger of all of the mocks.
+func (mock *MockGCE) SetLogger(log logr.Logger) {
+{{- range .All}}
+	mock.{{.MockField}}.Logger = log
+{{- end}}
+}
+
 // MockGCE is the mock for the compute API.
 type MockGCE struct {
 {{- range .All}}
 	{{.MockField}} *{{.MockWrapType}}
 {{- end}}
+	MockNetworkServices *MockNetworkServices
+	MockNetworkConnectivity *MockNetworkConnectivity
 }
 {{range .All}}
 // {{.WrapType}} returns the interface for the {{.Version}} {{.Service}}.
@@ -231,52 +267,75 @@ func (mock *MockGCE) {{.WrapType}}() {{.WrapType}} {
 	return mock.{{.MockField}}
 }
 {{end}}
+// NetworkServices returns the interface for the networkservices API.
+func (mock *MockGCE) NetworkServices() NetworkServices {
+	return mock.MockNetworkServices
+}
+
+// NetworkConnectivity returns the interface for the networkconnectivity API.
+func (mock *MockGCE) NetworkConnectivity() NetworkConnectivity {
+	return mock.MockNetworkConnectivity
+}
 
 {{range .Groups}}
 // Mock{{.Service}}Obj is used to store the various object versions in the shared
 // map of mocked objects. This allows for multiple API versions to co-exist and
-// share the same "view" of the objects in the backend.
+// share the same "view" of the objects in the backend. The To* methods return
+// copies of Obj; to change an object of the mock, replace its entry in the
+// Objects of the mock.
 type Mock{{.Service}}Obj struct {
 	Obj interface{}
 }
 {{- if .HasAlpha}}
-// ToAlpha retrieves the given version of the object.
+// ToAlpha retrieves the given version of the object. The object returned is a
+// copy: changing it does not change the mock.
 func (m *Mock{{.Service}}Obj) ToAlpha() *{{.Alpha.FQObjectType}} {
 	if ret, ok := m.Obj.(*{{.Alpha.FQObjectType}}); ok {
-		return ret
+		return DeepCopy{{.Alpha.VersionPrefix}}{{.Alpha.Object}}(ret)
+	}
+	ret, loss, err := {{.Alpha.Object}}ToAlpha(m.Obj)
+	if err != nil {
+		klog.Errorf("Could not convert %T to *{{.Alpha.FQObjectType}}: %v", m.Obj, err)
+		return &{{.Alpha.FQObjectType}}{}
 	}
-	// Convert the object via JSON copying to the type that was requested.
-	ret := &{{.Alpha.FQObjectType}}{}
-	if err := copyViaJSON(ret, m.Obj); err != nil {
-		klog.Errorf("Could not convert %T to *{{.Alpha.FQObjectType}} via JSON: %v", m.Obj, err)
+	if !loss.Empty() {
+		klog.V(4).Infof("Converting %T to *{{.Alpha.FQObjectType}} dropped fields %v", m.Obj, loss.Dropped)
 	}
 	return ret
 }
 {{- end}}
 {{- if .HasBeta}}
-// ToBeta retrieves the given version of the object.
+// ToBeta retrieves the given version of the object. The object returned is a
+// copy: changing it does not change the mock.
 func (m *Mock{{.Service}}Obj) ToBeta() *{{.Beta.FQObjectType}} {
 	if ret, ok := m.Obj.(*{{.Beta.FQObjectType}}); ok {
-		return ret
+		return DeepCopy{{.Beta.VersionPrefix}}{{.Beta.Object}}(ret)
+	}
+	ret, loss, err := {{.Beta.Object}}ToBeta(m.Obj)
+	if err != nil {
+		klog.Errorf("Could not convert %T to *{{.Beta.FQObjectType}}: %v", m.Obj, err)
+		return &{{.Beta.FQObjectType}}{}
 	}
-	// Convert the object via JSON copying to the type that was requested.
-	ret := &{{.Beta.FQObjectType}}{}
-	if err := copyViaJSON(ret, m.Obj); err != nil {
-		klog.Errorf("Could not convert %T to *{{.Beta.FQObjectType}} via JSON: %v", m.Obj, err)
+	if !loss.Empty() {
+		klog.V(4).Infof("Converting %T to *{{.Beta.FQObjectType}} dropped fields %v", m.Obj, loss.Dropped)
 	}
 	return ret
 }
 {{- end}}
 {{- if .HasGA}}
-// ToGA retrieves the given version of the object.
+// ToGA retrieves the given version of the object. The object returned is a
+// copy: changing it does not change the mock.
 func (m *Mock{{.Service}}Obj) ToGA() *{{.GA.FQObjectType}} {
 	if ret, ok := m.Obj.(*{{.GA.FQObjectType}}); ok {
-		return ret
+		return DeepCopy{{.GA.VersionPrefix}}{{.GA.Object}}(ret)
 	}
-		// Convert the object via JSON copying to the type that was requested.
-	ret := &{{.GA.FQObjectType}}{}
-	if err := copyViaJSON(ret, m.Obj); err != nil {
-		klog.Errorf("Could not convert %T to *{{.GA.FQObjectType}} via JSON: %v", m.Obj, err)
+	ret, loss, err := {{.GA.Object}}ToGA(m.Obj)
+	if err != nil {
+		klog.Errorf("Could not convert %T to *{{.GA.FQObjectType}}: %v", m.Obj, err)
+		return &{{.GA.FQObjectType}}{}
+	}
+	if !loss.Empty() {
+		klog.V(4).Infof("Converting %T to *{{.GA.FQObjectType}} dropped fields %v", m.Obj, loss.Dropped)
 	}
 	return ret
 }
@@ -294,8 +353,170 @@ func (m *Mock{{.Service}}Obj) ToGA() *{{.GA.FQObjectType}} {
 	}
 }
 
+// genCloud generates the Cloud interface and the GCE and GRPCGCE types
+// implementing it. These are the only references to the wrappers of the
+// services, so a binary built with the gce_subset tag (see selectServices)
+// links the services of the subset only.
+func genCloud(wr io.Writer) {
+	const text = `{{if .Subset}}//go:build gce_subset{{else}}//go:build !gce_subset{{end}}
+
+/*
+Copyright {{.Year}} Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+{{if .Subset -}}
+// This file was generated by "go run gen/main.go -mode cloud -services {{.Subset}} > gen_cloud_subset.go".
+// Do not edit directly.
+{{- else -}}
+// This file was generated by "go run gen/main.go -mode cloud > gen_cloud.go". Do not edit
+// directly.
+{{- end}}
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"google.golang.org/api/option"
+)
+
+// Cloud is an interface for the GCE compute API.
+type Cloud interface {
+{{- range .All}}
+	{{.WrapType}}() {{.WrapType}}
+{{- end}}
+	NetworkServices() NetworkServices
+	NetworkConnectivity() NetworkConnectivity
+}
+
+// NewGCE returns a GCE.
+func NewGCE(s *Service) *GCE {
+	g := &GCE{
+	{{- range .All}}
+		{{.Field}}: &{{.GCEWrapType}}{s},
+	{{- end}}
+		gceNetworkServices: NewGCENetworkServices(s),
+		gceNetworkConnectivity: NewGCENetworkConnectivity(s),
+	}
+	return g
+}
+
+// GCE implements Cloud.
+var _ Cloud = (*GCE)(nil)
+
+// GCE is the golang adapter for the compute APIs.
+type GCE struct {
+{{- range .All}}
+	{{.Field}} *{{.GCEWrapType}}
+{{- end}}
+	gceNetworkServices *GCENetworkServices
+	gceNetworkConnectivity *GCENetworkConnectivity
+}
+
+{{range .All}}
+// {{.WrapType}} returns the interface for the {{.Version}} {{.Service}}.
+func (gce *GCE) {{.WrapType}}() {{.WrapType}} {
+	return gce.{{.Field}}
+}
+{{- end}}
+
+// NetworkServices returns the interface for the networkservices API.
+func (gce *GCE) NetworkServices() NetworkServices {
+	return gce.gceNetworkServices
+}
+
+// NetworkConnectivity returns the interface for the networkconnectivity API.
+func (gce *GCE) NetworkConnectivity() NetworkConnectivity {
+	return gce.gceNetworkConnectivity
+}
+
+
+// GRPCGCE implements Cloud using the protobuf based compute clients
+// (cloud.google.com/go/compute/apiv1) for the GA services that have one.
+// Objects are converted field by field between the compute/v1 structs and
+// the computepb messages. The methods of these services that have no
+// equivalent in apiv1 return an error wrapping ErrGRPCUnsupported. The
+// services without an apiv1 client (and all alpha and beta services) are
+// served by the Discovery based client of the embedded GCE.
+type GRPCGCE struct {
+	*GCE
+{{- range .Services}}
+	grpc{{.Service}} *{{.GRPCWrapType}}
+{{- end}}
+}
+
+// NewGRPCGCE returns a new GRPCGCE. opts are passed to each of the compute
+// clients. Close() must be called to release the clients.
+func NewGRPCGCE(ctx context.Context, s *Service, opts ...option.ClientOption) (*GRPCGCE, error) {
+	g := &GRPCGCE{GCE: NewGCE(s)}
+{{- range .Services}}
+	{
+		c, err := compute.New{{.Service}}RESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.New{{.Service}}RESTClient: %w", err)
+		}
+		g.grpc{{.Service}} = &{{.GRPCWrapType}}{s: s, c: c}
+	}
+{{- end}}
+	return g, nil
+}
+
+// Close releases the compute clients.
+func (g *GRPCGCE) Close() error {
+	var errs []error
+{{- range .Services}}
+	if g.grpc{{.Service}} != nil {
+		if err := g.grpc{{.Service}}.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+{{- end}}
+	if len(errs) > 0 {
+		return fmt.Errorf("GRPCGCE.Close(): %v", errs)
+	}
+	return nil
+}
+{{range .Services}}
+// {{.Service}} returns the interface for the ga {{.Service}}.
+func (g *GRPCGCE) {{.Service}}() {{.Service}} {
+	return g.grpc{{.Service}}
+}
+{{end}}
+`
+	data := struct {
+		Year     string
+		Subset   string
+		All      []*meta.ServiceInfo
+		Services []*grpcServiceInfo
+	}{
+		Year:     fmt.Sprintf("%v", time.Now().Year()),
+		Subset:   flags.services,
+		All:      meta.AllServices,
+		Services: grpcServices(newProtoConverters()),
+	}
+	tmpl := template.Must(template.New("cloud").Parse(text))
+	if err := tmpl.Execute(wr, data); err != nil {
+		panic(err)
+	}
+}
+
 // genTypes generates the type wrappers.
-func genTypes(wr io.Writer) {
+func genTypes(wr io.Writer, conv *versionConverters) {
 	const text = `// {{.WrapType}} is an interface that allows for mocking of {{.Service}}.
 type {{.WrapType}} interface {
 {{- if .GenerateCustomOps}}
@@ -336,6 +557,18 @@ type {{.WrapType}} interface {
 {{- end}}
 }
 
+// {{.WrapType}}Provider is the subset of Cloud that provides {{.WrapType}}.
+// Code that only uses {{.WrapType}} can depend on this instead of Cloud.
+type {{.WrapType}}Provider interface {
+	{{.WrapType}}() {{.WrapType}}
+}
+
+// New{{.WrapType}} returns the {{.WrapType}} of the Cloud (or any other
+// {{.WrapType}}Provider).
+func New{{.WrapType}}(c {{.WrapType}}Provider) {{.WrapType}} {
+	return c.{{.WrapType}}()
+}
+
 // New{{.MockWrapType}} returns a new mock for {{.Service}}.
 func New{{.MockWrapType}}(pr ProjectRouter, objs map[meta.Key]*Mock{{.Service}}Obj) *{{.MockWrapType}} {
 	mock := &{{.MockWrapType}}{
@@ -351,6 +584,9 @@ func New{{.MockWrapType}}(pr ProjectRouter, objs map[meta.Key]*Mock{{.Service}}O
 		{{- if .GenerateDelete}}
 		DeleteError: map[meta.Key]error{},
 		{{- end}}
+		{{- range .Methods}}
+		{{.MockErrorName}}: map[meta.Key]error{},
+		{{- end}}
 	}
 	return mock
 }
@@ -361,6 +597,10 @@ type {{.MockWrapType}} struct {
 
 	ProjectRouter ProjectRouter
 
+	// Logger receives structured logs for Insert and Delete calls. The
+	// zero value discards the logs.
+	Logger logr.Logger
+
 	// Objects maintained by the mock.
 	Objects map[meta.Key]*Mock{{.Service}}Obj
 
@@ -384,6 +624,9 @@ type {{.MockWrapType}} struct {
 	{{- if .ListUsable}}
 	ListUsableError *error
 	{{- end}}
+	{{- range .Methods}}
+	{{.MockErrorName}} map[meta.Key]error
+	{{- end}}
 
 	// xxxHook allow you to intercept the standard processing of the mock in
 	// order to add your own logic. Return (true, _, _) to prevent the normal
@@ -436,6 +679,7 @@ func (m *{{.MockWrapType}}) Get(ctx context.Context, key *meta.Key) (*{{.FQObjec
 			return obj, err
 		}
 	}
+	key = key.Normalize()
 	if !key.Valid() {
 		return nil, fmt.Errorf("invalid GCE key (%+v)", key)
 	}
@@ -491,6 +735,12 @@ func (m *{{.MockWrapType}}) List(ctx context.Context, zone string, fl *filter.F)
 			return objs, err
 		}
 	}
+{{- if .KeyIsRegional}}
+	region = meta.NormalizeLocation(region)
+{{- end -}}
+{{- if .KeyIsZonal}}
+	zone = meta.NormalizeLocation(zone)
+{{- end}}
 
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
@@ -526,10 +776,11 @@ func (m *{{.MockWrapType}}) List(ctx context.Context, zone string, fl *filter.F)
 			continue
 		}
 {{- end}}
-		if !fl.Match(obj.To{{.VersionTitle}}()) {
+		typedObj := obj.To{{.VersionTitle}}()
+		if !fl.Match(typedObj) {
 			continue
 		}
-		objs = append(objs, obj.To{{.VersionTitle}}())
+		objs = append(objs, typedObj)
 	}
 
 	{{if .KeyIsGlobal -}}
@@ -551,9 +802,11 @@ func (m *{{.MockWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.F
 	if m.InsertHook != nil {
 		if intercept, err := m.InsertHook(ctx, key, obj, m);  intercept {
 			klog.V(5).Infof("{{.MockWrapType}}.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
+			mockLog(m.Logger, "{{.Service}}", "Insert", key, err)
 			return err
 		}
 	}
+	key = key.Normalize()
 	if !key.Valid() {
 		return fmt.Errorf("invalid GCE key (%+v)", key)
 	}
@@ -563,6 +816,7 @@ func (m *{{.MockWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.F
 
 	if err, ok := m.InsertError[*key]; ok {
 		klog.V(5).Infof("{{.MockWrapType}}.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
+		mockLog(m.Logger, "{{.Service}}", "Insert", key, err)
 		return err
 	}
 	if _, ok := m.Objects[*key]; ok {
@@ -571,6 +825,7 @@ func (m *{{.MockWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.F
 			Message: fmt.Sprintf("{{.MockWrapType}} %v exists", key),
 		}
 		klog.V(5).Infof("{{.MockWrapType}}.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
+		mockLog(m.Logger, "{{.Service}}", "Insert", key, err)
 		return err
 	}
 
@@ -580,6 +835,7 @@ func (m *{{.MockWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.F
 
 	m.Objects[*key] = &Mock{{.Service}}Obj{obj}
 	klog.V(5).Infof("{{.MockWrapType}}.Insert(%v, %v, %+v) = nil", ctx, key, obj)
+	mockLog(m.Logger, "{{.Service}}", "Insert", key, nil)
 	return nil
 }
 {{- end}}
@@ -590,9 +846,11 @@ func (m *{{.MockWrapType}}) Delete(ctx context.Context, key *meta.Key) error {
 	if m.DeleteHook != nil {
 		if intercept, err := m.DeleteHook(ctx, key, m);  intercept {
 			klog.V(5).Infof("{{.MockWrapType}}.Delete(%v, %v) = %v", ctx, key, err)
+			mockLog(m.Logger, "{{.Service}}", "Delete", key, err)
 			return err
 		}
 	}
+	key = key.Normalize()
 	if !key.Valid() {
 		return fmt.Errorf("invalid GCE key (%+v)", key)
 	}
@@ -602,6 +860,7 @@ func (m *{{.MockWrapType}}) Delete(ctx context.Context, key *meta.Key) error {
 
 	if err, ok := m.DeleteError[*key]; ok {
 		klog.V(5).Infof("{{.MockWrapType}}.Delete(%v, %v) = %v", ctx, key, err)
+		mockLog(m.Logger, "{{.Service}}", "Delete", key, err)
 		return err
 	}
 	if _, ok := m.Objects[*key]; !ok {
@@ -610,11 +869,13 @@ func (m *{{.MockWrapType}}) Delete(ctx context.Context, key *meta.Key) error {
 			Message: fmt.Sprintf("{{.MockWrapType}} %v not found", key),
 		}
 		klog.V(5).Infof("{{.MockWrapType}}.Delete(%v, %v) = %v", ctx, key, err)
+		mockLog(m.Logger, "{{.Service}}", "Delete", key, err)
 		return err
 	}
 
 	delete(m.Objects, *key)
 	klog.V(5).Infof("{{.MockWrapType}}.Delete(%v, %v) = nil", ctx, key)
+	mockLog(m.Logger, "{{.Service}}", "Delete", key, nil)
 	return nil
 }
 {{- end}}
@@ -640,16 +901,17 @@ func (m *{{.MockWrapType}}) AggregatedList(ctx context.Context, fl *filter.F) (m
 
 	objs := map[string][]*{{.FQObjectType}}{}
 	for _, obj := range m.Objects {
-		res, err := ParseResourceURL(obj.To{{.VersionTitle}}().SelfLink)
+		typedObj := obj.To{{.VersionTitle}}()
+		res, err := ParseResourceURL(typedObj.SelfLink)
 		if err != nil {
 			klog.V(5).Infof("{{.MockWrapType}}.AggregatedList(%v, %v) = nil, %v", ctx, fl, err)
 			return nil, err
 		}
-		if !fl.Match(obj.To{{.VersionTitle}}()) {
+		if !fl.Match(typedObj) {
 			continue
 		}
-        location := aggregatedListKey(res.Key)
-		objs[location] = append(objs[location], obj.To{{.VersionTitle}}())
+		location := aggregatedListKey(res.Key)
+		objs[location] = append(objs[location], typedObj)
 	}
 	klog.V(5).Infof("{{.MockWrapType}}.AggregatedList(%v, %v) = [%v items], nil", ctx, fl, len(objs))
 	return objs, nil
@@ -678,15 +940,13 @@ func (m *{{.MockWrapType}}) ListUsable(ctx context.Context, fl *filter.F) ([]*{{
 	var objs []*{{.FQListUsableObjectType}}
 
 	for _, obj := range m.Objects {
-		if !fl.Match(obj.To{{.VersionTitle}}()) {
+		typedObj := obj.To{{.VersionTitle}}()
+		if !fl.Match(typedObj) {
 			continue
 		}
-		{{.Version}}Obj := obj.To{{.VersionTitle}}()
 		dest := &{{.FQListUsableObjectType}}{}
 		// Convert to Usable type to avoid separate Usable struct
-		if err := copyViaJSON(dest, {{.Version}}Obj); err != nil {
-			klog.Errorf("Could not convert %T to *{{.FQListUsableObjectType}} via JSON: %v", {{.Version}}Obj, err)
-		}
+		{{converter .ListUsableGoType .ObjectGoType}}(dest, typedObj, &ConversionLoss{}, "")
 		objs = append(objs, dest)
 	}
   klog.V(5).Infof("{{.MockWrapType}}.ListUsable(%v, %v) = [%v items], nil", ctx, fl, len(objs))
@@ -703,23 +963,132 @@ func (m *{{.MockWrapType}}) Obj(o *{{.FQObjectType}}) *Mock{{.Service}}Obj {
 {{- range .}}
 // {{.Name}} is a mock for the corresponding method.
 func (m *{{.MockWrapType}}) {{.FcnArgs}} {
+{{- if .IsPatch }}
+	arg0, err := applyFieldMask(arg0, fieldMask)
+	if err != nil {
+		return err
+	}
+{{- end}}
 {{- if .IsOperation }}
 	if m.{{.MockHookName}} != nil {
 		return m.{{.MockHookName}}(ctx, key {{.CallArgs}}, m)
 	}
+	if err := m.mock{{.Name}}Error(key); err != nil {
+		klog.V(5).Infof("{{.MockWrapType}}.{{.Name}}(%v, %v, ...) = %v", ctx, key, err)
+		return err
+	}
+{{- if .IsUpdate}}
+	key = key.Normalize()
+	if !key.Valid() {
+		return fmt.Errorf("invalid GCE key (%+v)", key)
+	}
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	if _, ok := m.Objects[*key]; !ok {
+		err := &googleapi.Error{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("{{.MockWrapType}} %v not found", key),
+		}
+		klog.V(5).Infof("{{.MockWrapType}}.{{.Name}}(%v, %v, %+v) = %v", ctx, key, arg0, err)
+		return err
+	}
+
+	arg0.Name = key.Name
+	projectID := m.ProjectRouter.ProjectID(ctx, "{{.Version}}", "{{.Resource}}")
+	arg0.SelfLink = SelfLink(meta.Version{{.VersionTitle}}, projectID, "{{.Resource}}", key)
+
+	m.Objects[*key] = &Mock{{.Service}}Obj{arg0}
+	klog.V(5).Infof("{{.MockWrapType}}.{{.Name}}(%v, %v, %+v) = nil", ctx, key, arg0)
+{{- end}}
+{{- if .IsPatch}}
+	key = key.Normalize()
+	if !key.Valid() {
+		return fmt.Errorf("invalid GCE key (%+v)", key)
+	}
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	o, ok := m.Objects[*key]
+	if !ok {
+		err := &googleapi.Error{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("{{.MockWrapType}} %v not found", key),
+		}
+		klog.V(5).Infof("{{.MockWrapType}}.{{.Name}}(%v, %v, %+v) = %v", ctx, key, arg0, err)
+		return err
+	}
+	obj := o.To{{.VersionTitle}}()
+	if err := MockPatch(obj, arg0); err != nil {
+		klog.V(5).Infof("{{.MockWrapType}}.{{.Name}}(%v, %v, %+v) = %v", ctx, key, arg0, err)
+		return err
+	}
+	m.Objects[*key] = &Mock{{.Service}}Obj{obj}
+	klog.V(5).Infof("{{.MockWrapType}}.{{.Name}}(%v, %v, %+v) = nil", ctx, key, arg0)
+{{- end}}
+{{- if .IsSetLabels}}
+	key = key.Normalize()
+	if !key.Valid() {
+		return fmt.Errorf("invalid GCE key (%+v)", key)
+	}
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	o, ok := m.Objects[*key]
+	if !ok {
+		err := &googleapi.Error{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("{{.MockWrapType}} %v not found", key),
+		}
+		klog.V(5).Infof("{{.MockWrapType}}.{{.Name}}(%v, %v, %+v) = %v", ctx, key, arg0, err)
+		return err
+	}
+	obj := o.To{{.VersionTitle}}()
+	if obj.LabelFingerprint != arg0.LabelFingerprint {
+		err := &googleapi.Error{
+			Code:    http.StatusPreconditionFailed,
+			Message: fmt.Sprintf("{{.MockWrapType}} %v: labelFingerprint %q does not match %q", key, arg0.LabelFingerprint, obj.LabelFingerprint),
+		}
+		klog.V(5).Infof("{{.MockWrapType}}.{{.Name}}(%v, %v, %+v) = %v", ctx, key, arg0, err)
+		return err
+	}
+	obj.Labels = arg0.Labels
+	obj.LabelFingerprint = mockLabelFingerprint(arg0.Labels)
+	m.Objects[*key] = &Mock{{.Service}}Obj{obj}
+	klog.V(5).Infof("{{.MockWrapType}}.{{.Name}}(%v, %v, %+v) = nil", ctx, key, arg0)
+{{- end}}
 	return nil
 {{- else if .IsGet}}
 	if m.{{.MockHookName}} != nil {
 		return m.{{.MockHookName}}(ctx, key {{.CallArgs}}, m)
 	}
+	if err := m.mock{{.Name}}Error(key); err != nil {
+		klog.V(5).Infof("{{.MockWrapType}}.{{.Name}}(%v, %v, ...) = nil, %v", ctx, key, err)
+		return nil, err
+	}
 	return nil, fmt.Errorf("{{.MockHookName}} must be set")
 {{- else if .IsPaged}}
 	if m.{{.MockHookName}} != nil {
 		return m.{{.MockHookName}}(ctx, key {{.CallArgs}}, fl, m)
 	}
+	if err := m.mock{{.Name}}Error(key); err != nil {
+		klog.V(5).Infof("{{.MockWrapType}}.{{.Name}}(%v, %v, ...) = nil, %v", ctx, key, err)
+		return nil, err
+	}
 	return nil, nil
 {{- end}}
 }
+
+// mock{{.Name}}Error returns the error in {{.MockErrorName}} for key, if any.
+func (m *{{.MockWrapType}}) mock{{.Name}}Error(key *meta.Key) error {
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	return m.{{.MockErrorName}}[*key]
+}
 {{end -}}
 {{- end}}
 // {{.GCEWrapType}} is a simplifying adapter for the GCE {{.Service}}.
@@ -730,6 +1099,8 @@ type {{.GCEWrapType}} struct {
 {{- if .GenerateGet}}
 // Get the {{.Object}} named by key.
 func (g *{{.GCEWrapType}}) Get(ctx context.Context, key *meta.Key) (*{{.FQObjectType}}, error) {
+	key = normalizeKey(key)
+	return intercept(ctx, g.s, newCallInfo("{{.Service}}", "Get", meta.Version("{{.Version}}"), key), func(ctx context.Context) (*{{.FQObjectType}}, error) {
 	klog.V(5).Infof("{{.GCEWrapType}}.Get(%v, %v): called", ctx, key)
 
 	if !key.Valid() {
@@ -745,29 +1116,34 @@ func (g *{{.GCEWrapType}}) Get(ctx context.Context, key *meta.Key) (*{{.FQObject
 	}
 
 	klog.V(5).Infof("{{.GCEWrapType}}.Get(%v, %v): projectID = %v, ck = %+v", ctx, key, projectID, ck)
-	callObserverStart(ctx, ck)
+	ctx = g.s.callStart(ctx, ck, key)
 	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
 		klog.V(4).Infof("{{.GCEWrapType}}.Get(%v, %v): RateLimiter error: %v", ctx, key, err)
+		callEnd(ctx, ck, err)
 		return nil, err
 	}
 
 {{- if .KeyIsGlobal}}
 	call := g.s.{{.VersionTitle}}.{{.Service}}.Get(projectID, key.Name)
+	callSend(ctx, call.Header())
 {{- end -}}
 {{- if .KeyIsRegional}}
 	call := g.s.{{.VersionTitle}}.{{.Service}}.Get(projectID, key.Region, key.Name)
+	callSend(ctx, call.Header())
 {{- end -}}
 {{- if .KeyIsZonal}}
 	call := g.s.{{.VersionTitle}}.{{.Service}}.Get(projectID, key.Zone, key.Name)
+	callSend(ctx, call.Header())
 {{- end}}
 	call.Context(ctx)
 	v, err := call.Do()
 	klog.V(4).Infof("{{.GCEWrapType}}.Get(%v, %v) = %+v, %v", ctx, key, v, err)
 
-	callObserverEnd(ctx, ck, err)
+	callEnd(ctx, ck, err)
 	g.s.RateLimiter.Observe(ctx, err, ck)
 
 	return v, err
+	})
 }
 {{- end}}
 
@@ -775,14 +1151,19 @@ func (g *{{.GCEWrapType}}) Get(ctx context.Context, key *meta.Key) (*{{.FQObject
 // List all {{.Object}} objects.
 {{- if .KeyIsGlobal}}
 func (g *{{.GCEWrapType}}) List(ctx context.Context, fl *filter.F) ([]*{{.FQObjectType}}, error) {
+	return intercept(ctx, g.s, newCallInfo("{{.Service}}", "List", meta.Version("{{.Version}}"), nil, fl), func(ctx context.Context) ([]*{{.FQObjectType}}, error) {
 	klog.V(5).Infof("{{.GCEWrapType}}.List(%v, %v) called", ctx, fl)
 {{- end -}}
 {{- if .KeyIsRegional}}
 func (g *{{.GCEWrapType}}) List(ctx context.Context, region string, fl *filter.F) ([]*{{.FQObjectType}}, error) {
+	region = meta.NormalizeLocation(region)
+	return intercept(ctx, g.s, newCallInfo("{{.Service}}", "List", meta.Version("{{.Version}}"), nil, region, fl), func(ctx context.Context) ([]*{{.FQObjectType}}, error) {
 	klog.V(5).Infof("{{.GCEWrapType}}.List(%v, %v, %v) called", ctx, region, fl)
 {{- end -}}
 {{- if .KeyIsZonal}}
 func (g *{{.GCEWrapType}}) List(ctx context.Context, zone string, fl *filter.F) ([]*{{.FQObjectType}}, error) {
+	zone = meta.NormalizeLocation(zone)
+	return intercept(ctx, g.s, newCallInfo("{{.Service}}", "List", meta.Version("{{.Version}}"), nil, zone, fl), func(ctx context.Context) ([]*{{.FQObjectType}}, error) {
 	klog.V(5).Infof("{{.GCEWrapType}}.List(%v, %v, %v) called", ctx, zone, fl)
 {{- end}}
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "{{.Version}}", "{{.Service}}")
@@ -793,22 +1174,26 @@ func (g *{{.GCEWrapType}}) List(ctx context.Context, zone string, fl *filter.F)
 		Service: "{{.Service}}",
 	}
 
-	callObserverStart(ctx, ck)
+	ctx = g.s.callStart(ctx, ck, nil)
 	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		callEnd(ctx, ck, err)
 		return nil, err
 	}
 
 {{- if .KeyIsGlobal}}
 	klog.V(5).Infof("{{.GCEWrapType}}.List(%v, %v): projectID = %v, ck = %+v", ctx, fl, projectID, ck)
 	call := g.s.{{.VersionTitle}}.{{.Service}}.List(projectID)
+	callSend(ctx, call.Header())
 {{- end -}}
 {{- if .KeyIsRegional}}
 	klog.V(5).Infof("{{.GCEWrapType}}.List(%v, %v, %v): projectID = %v, ck = %+v", ctx, region, fl, projectID, ck)
 	call := g.s.{{.VersionTitle}}.{{.Service}}.List(projectID, region)
+	callSend(ctx, call.Header())
 {{- end -}}
 {{- if .KeyIsZonal}}
 	klog.V(5).Infof("{{.GCEWrapType}}.List(%v, %v, %v): projectID = %v, ck = %+v", ctx, zone, fl, projectID, ck)
 	call := g.s.{{.VersionTitle}}.{{.Service}}.List(projectID, zone)
+	callSend(ctx, call.Header())
 {{- end}}
 	if fl != filter.None {
 		call.Filter(fl.String())
@@ -820,14 +1205,14 @@ func (g *{{.GCEWrapType}}) List(ctx context.Context, zone string, fl *filter.F)
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
-		callObserverEnd(ctx, ck, err)
+		callEnd(ctx, ck, err)
 		g.s.RateLimiter.Observe(ctx, err, ck)
 
 		klog.V(4).Infof("{{.GCEWrapType}}.List(%v, ..., %v) = %v, %v", ctx, fl, nil, err)
 		return nil, err
 	}
 
-    callObserverEnd(ctx, ck, nil)
+    callEnd(ctx, ck, nil)
 	g.s.RateLimiter.Observe(ctx, nil, ck)
 
 	if kLogEnabled(4) {
@@ -841,12 +1226,15 @@ func (g *{{.GCEWrapType}}) List(ctx context.Context, zone string, fl *filter.F)
 	}
 
 	return all, nil
+	})
 }
 {{- end}}
 
 {{- if .GenerateInsert}}
 // Insert {{.Object}} with key of value obj.
 func (g *{{.GCEWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.FQObjectType}}) error {
+	key = normalizeKey(key)
+	return interceptErr(ctx, g.s, newCallInfo("{{.Service}}", "Insert", meta.Version("{{.Version}}"), key, obj), func(ctx context.Context) error {
 	klog.V(5).Infof("{{.GCEWrapType}}.Insert(%v, %v, %+v): called", ctx, key, obj)
 	if !key.Valid() {
 		klog.V(2).Infof("{{.GCEWrapType}}.Insert(%v, %v, ...): key is invalid (%#v)", ctx, key, key)
@@ -861,27 +1249,35 @@ func (g *{{.GCEWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.FQ
 	}
 
 	klog.V(5).Infof("{{.GCEWrapType}}.Insert(%v, %v, ...): projectID = %v, ck = %+v", ctx, key, projectID, ck)
-	callObserverStart(ctx, ck)
+	ctx = g.s.callStart(ctx, ck, key)
 	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
 		klog.V(4).Infof("{{.GCEWrapType}}.Insert(%v, %v, ...): RateLimiter error: %v", ctx, key, err)
+		callEnd(ctx, ck, err)
 		return err
 	}
 	obj.Name = key.Name
 
 {{- if .KeyIsGlobal}}
 	call := g.s.{{.VersionTitle}}.{{.Service}}.Insert(projectID, obj)
+	callSend(ctx, call.Header())
 {{- end -}}
 {{- if .KeyIsRegional}}
 	call := g.s.{{.VersionTitle}}.{{.Service}}.Insert(projectID, key.Region, obj)
+	callSend(ctx, call.Header())
 {{- end -}}
 {{- if .KeyIsZonal}}
 	call := g.s.{{.VersionTitle}}.{{.Service}}.Insert(projectID, key.Zone, obj)
+	callSend(ctx, call.Header())
+{{- end}}
+{{- if .SupportsRequestID "Insert"}}
+	call.RequestId(callRequestID(ctx))
 {{- end}}
 	call.Context(ctx)
 
 	op, err := call.Do()
+	callSetOperation(ctx, op)
 
-	callObserverEnd(ctx, ck, err)
+	callEnd(ctx, ck, err)
 	g.s.RateLimiter.Observe(ctx, err, ck)
 
 	if err != nil {
@@ -892,12 +1288,15 @@ func (g *{{.GCEWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.FQ
 	err = g.s.WaitForCompletion(ctx, op)
 	klog.V(4).Infof("{{.GCEWrapType}}.Insert(%v, %v, %+v) = %+v", ctx, key, obj, err)
 	return err
+	})
 }
 {{- end}}
 
 {{- if .GenerateDelete}}
 // Delete the {{.Object}} referenced by key.
 func (g *{{.GCEWrapType}}) Delete(ctx context.Context, key *meta.Key) error {
+	key = normalizeKey(key)
+	return interceptErr(ctx, g.s, newCallInfo("{{.Service}}", "Delete", meta.Version("{{.Version}}"), key), func(ctx context.Context) error {
 	klog.V(5).Infof("{{.GCEWrapType}}.Delete(%v, %v): called", ctx, key)
 	if !key.Valid() {
 		klog.V(2).Infof("{{.GCEWrapType}}.Delete(%v, %v): key is invalid (%#v)", ctx, key, key)
@@ -911,26 +1310,34 @@ func (g *{{.GCEWrapType}}) Delete(ctx context.Context, key *meta.Key) error {
 		Service: "{{.Service}}",
 	}
 	klog.V(5).Infof("{{.GCEWrapType}}.Delete(%v, %v): projectID = %v, ck = %+v", ctx, key, projectID, ck)
-	callObserverStart(ctx, ck)
+	ctx = g.s.callStart(ctx, ck, key)
 	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
 		klog.V(4).Infof("{{.GCEWrapType}}.Delete(%v, %v): RateLimiter error: %v", ctx, key, err)
+		callEnd(ctx, ck, err)
 		return err
 	}
 
 {{- if .KeyIsGlobal}}
 	call := g.s.{{.VersionTitle}}.{{.Service}}.Delete(projectID, key.Name)
+	callSend(ctx, call.Header())
 {{end -}}
 {{- if .KeyIsRegional}}
 	call := g.s.{{.VersionTitle}}.{{.Service}}.Delete(projectID, key.Region, key.Name)
+	callSend(ctx, call.Header())
 {{- end -}}
 {{- if .KeyIsZonal}}
 	call := g.s.{{.VersionTitle}}.{{.Service}}.Delete(projectID, key.Zone, key.Name)
+	callSend(ctx, call.Header())
+{{- end}}
+{{- if .SupportsRequestID "Delete"}}
+	call.RequestId(callRequestID(ctx))
 {{- end}}
 	call.Context(ctx)
 
 	op, err := call.Do()
+	callSetOperation(ctx, op)
 
-	callObserverEnd(ctx, ck, err)
+	callEnd(ctx, ck, err)
 	g.s.RateLimiter.Observe(ctx, err, ck)
 
 	if err != nil {
@@ -941,12 +1348,14 @@ func (g *{{.GCEWrapType}}) Delete(ctx context.Context, key *meta.Key) error {
 	err = g.s.WaitForCompletion(ctx, op)
 	klog.V(4).Infof("{{.GCEWrapType}}.Delete(%v, %v) = %v", ctx, key, err)
 	return err
+	})
 }
 {{end -}}
 
 {{- if .AggregatedList}}
 // AggregatedList lists all resources of the given type across all locations.
 func (g *{{.GCEWrapType}}) AggregatedList(ctx context.Context, fl *filter.F) (map[string][]*{{.FQObjectType}}, error) {
+	return intercept(ctx, g.s, newCallInfo("{{.Service}}", "AggregatedList", meta.Version("{{.Version}}"), nil, fl), func(ctx context.Context) (map[string][]*{{.FQObjectType}}, error) {
 	klog.V(5).Infof("{{.GCEWrapType}}.AggregatedList(%v, %v) called", ctx, fl)
 
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "{{.Version}}", "{{.Service}}")
@@ -958,13 +1367,15 @@ func (g *{{.GCEWrapType}}) AggregatedList(ctx context.Context, fl *filter.F) (ma
 	}
 
 	klog.V(5).Infof("{{.GCEWrapType}}.AggregatedList(%v, %v): projectID = %v, ck = %+v", ctx, fl, projectID, ck)
-	callObserverStart(ctx, ck)
+	ctx = g.s.callStart(ctx, ck, nil)
 	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
 		klog.V(5).Infof("{{.GCEWrapType}}.AggregatedList(%v, %v): RateLimiter error: %v", ctx, fl, err)
+		callEnd(ctx, ck, err)
 		return nil, err
 	}
 
 	call := g.s.{{.VersionTitle}}.{{.Service}}.AggregatedList(projectID)
+	callSend(ctx, call.Header())
 	call.Context(ctx)
 	if fl != filter.None {
 		call.Filter(fl.String())
@@ -974,12 +1385,18 @@ func (g *{{.GCEWrapType}}) AggregatedList(ctx context.Context, fl *filter.F) (ma
 	f := func(l *{{.ObjectAggregatedListType}}) error {
 		for k, v := range l.Items {
 			klog.V(5).Infof("{{.GCEWrapType}}.AggregatedList(%v, %v): page[%v]%+v", ctx, fl, k, v)
+			// Scopes without any objects have a NO_RESULTS_ON_PAGE warning.
+			// Other warnings (e.g. an unreachable scope) mean that the
+			// result may be incomplete.
+			if v.Warning != nil && v.Warning.Code != "NO_RESULTS_ON_PAGE" {
+				klog.V(2).Infof("{{.GCEWrapType}}.AggregatedList(%v, %v): warning for scope %v: %v: %v", ctx, fl, k, v.Warning.Code, v.Warning.Message)
+			}
 			all[k] = append(all[k], v.{{.AggregatedListField}}...)
 		}
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
-		callObserverEnd(ctx, ck, err)
+		callEnd(ctx, ck, err)
 		g.s.RateLimiter.Observe(ctx, err, ck)
 
 		klog.V(4).Infof("{{.GCEWrapType}}.AggregatedList(%v, %v) = %v, %v", ctx, fl, nil, err)
@@ -995,12 +1412,14 @@ func (g *{{.GCEWrapType}}) AggregatedList(ctx context.Context, fl *filter.F) (ma
 		klog.V(5).Infof("{{.GCEWrapType}}.AggregatedList(%v, %v) = %v, %v", ctx, fl, asStr, nil)
 	}
 	return all, nil
+	})
 }
 {{- end}}
 
 {{- if .ListUsable}}
 // List all Usable {{.Object}} objects.
 func (g *{{.GCEWrapType}}) ListUsable(ctx context.Context, fl *filter.F) ([]*{{.FQListUsableObjectType}}, error) {
+	return intercept(ctx, g.s, newCallInfo("{{.Service}}", "ListUsable", meta.Version("{{.Version}}"), nil, fl), func(ctx context.Context) ([]*{{.FQListUsableObjectType}}, error) {
 	klog.V(5).Infof("{{.GCEWrapType}}.ListUsable(%v, %v) called", ctx, fl)
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "{{.Version}}", "{{.Service}}")
 	ck:= &CallContextKey{
@@ -1009,13 +1428,15 @@ func (g *{{.GCEWrapType}}) ListUsable(ctx context.Context, fl *filter.F) ([]*{{.
 		Version: meta.Version("{{.Version}}"),
 		Service: "{{.Service}}",
 	}
-    callObserverStart(ctx, ck)
+    ctx = g.s.callStart(ctx, ck, nil)
 	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		callEnd(ctx, ck, err)
 		return nil, err
 	}
 
 	klog.V(5).Infof("{{.GCEWrapType}}.ListUsable(%v, %v): projectID = %v, ck = %+v", ctx, fl, projectID, ck)
 	call := g.s.{{.VersionTitle}}.{{.Service}}.ListUsable(projectID)
+	callSend(ctx, call.Header())
 	if fl != filter.None {
 		call.Filter(fl.String())
 	}
@@ -1026,7 +1447,7 @@ func (g *{{.GCEWrapType}}) ListUsable(ctx context.Context, fl *filter.F) ([]*{{.
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
-		callObserverEnd(ctx, ck, err)
+		callEnd(ctx, ck, err)
 		g.s.RateLimiter.Observe(ctx, err, ck)
 
 		klog.V(4).Infof("{{.GCEWrapType}}.ListUsable(%v, ..., %v) = %v, %v", ctx, fl, nil, err)
@@ -1044,6 +1465,7 @@ func (g *{{.GCEWrapType}}) ListUsable(ctx context.Context, fl *filter.F) ([]*{{.
 	}
 
 	return all, nil
+	})
 }
 {{- end}}
 
@@ -1051,6 +1473,14 @@ func (g *{{.GCEWrapType}}) ListUsable(ctx context.Context, fl *filter.F) ([]*{{.
 {{- range .}}
 // {{.Name}} is a method on {{.GCEWrapType}}.
 func (g *{{.GCEWrapType}}) {{.FcnArgs}} {
+	key = normalizeKey(key)
+{{- if .IsOperation}}
+	return interceptErr(ctx, g.s, newCallInfo("{{.Service}}", "{{.Name}}", meta.Version("{{.Version}}"), key{{.CallArgs}}{{if .IsPaged}}, fl{{end}}{{if .IsPatch}}, fieldMask{{end}}), func(ctx context.Context) error {
+{{- else if .IsGet}}
+	return intercept(ctx, g.s, newCallInfo("{{.Service}}", "{{.Name}}", meta.Version("{{.Version}}"), key{{.CallArgs}}{{if .IsPaged}}, fl{{end}}{{if .IsPatch}}, fieldMask{{end}}), func(ctx context.Context) (*{{.Version}}.{{.ReturnType}}, error) {
+{{- else if .IsPaged}}
+	return intercept(ctx, g.s, newCallInfo("{{.Service}}", "{{.Name}}", meta.Version("{{.Version}}"), key{{.CallArgs}}{{if .IsPaged}}, fl{{end}}{{if .IsPatch}}, fieldMask{{end}}), func(ctx context.Context) ([]*{{.Version}}.{{.ItemType}}, error) {
+{{- end}}
 	klog.V(5).Infof("{{.GCEWrapType}}.{{.Name}}(%v, %v, ...): called", ctx, key)
 
 	if !key.Valid() {
@@ -1063,6 +1493,13 @@ func (g *{{.GCEWrapType}}) {{.FcnArgs}} {
 		return nil, fmt.Errorf("invalid GCE key (%+v)", key)
 {{- end}}
 	}
+{{- if .IsPatch}}
+	arg0, err := applyFieldMask(arg0, fieldMask)
+	if err != nil {
+		klog.V(2).Infof("{{.GCEWrapType}}.{{.Name}}(%v, %v, ...): invalid field mask %v: %v", ctx, key, fieldMask, err)
+		return err
+	}
+{{- end}}
 	projectID := g.s.ProjectRouter.ProjectID(ctx, "{{.Version}}", "{{.Service}}")
 	ck:= &CallContextKey{
 		ProjectID: projectID,
@@ -1071,31 +1508,40 @@ func (g *{{.GCEWrapType}}) {{.FcnArgs}} {
 		Service: "{{.Service}}",
 	}
 	klog.V(5).Infof("{{.GCEWrapType}}.{{.Name}}(%v, %v, ...): projectID = %v, ck = %+v", ctx, key, projectID, ck)
-	callObserverStart(ctx, ck)
+	ctx = g.s.callStart(ctx, ck, key)
 	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
 		klog.V(4).Infof("{{.GCEWrapType}}.{{.Name}}(%v, %v, ...): RateLimiter error: %v", ctx, key, err)
 	{{- if .IsOperation}}
+		callEnd(ctx, ck, err)
 		return err
 	{{- else}}
+		callEnd(ctx, ck, err)
 		return nil, err
 	{{- end}}
 	}
 
 {{- if .KeyIsGlobal}}
 	call := g.s.{{.VersionTitle}}.{{.Service}}.{{.Name}}(projectID, key.Name {{.CallArgs}})
+	callSend(ctx, call.Header())
 {{- end -}}
 {{- if .KeyIsRegional}}
 	call := g.s.{{.VersionTitle}}.{{.Service}}.{{.Name}}(projectID, key.Region, key.Name {{.CallArgs}})
+	callSend(ctx, call.Header())
 {{- end -}}
 {{- if .KeyIsZonal}}
 	call := g.s.{{.VersionTitle}}.{{.Service}}.{{.Name}}(projectID, key.Zone, key.Name {{.CallArgs}})
+	callSend(ctx, call.Header())
 {{- end}}
 {{- if .IsOperation}}
+{{- if .SupportsRequestID .Name}}
+	call.RequestId(callRequestID(ctx))
+{{- end}}
 	call.Context(ctx)
 	op, err := call.Do()
+	callSetOperation(ctx, op)
 
 	if err != nil {
-		callObserverEnd(ctx, ck, err)
+		callEnd(ctx, ck, err)
 		g.s.RateLimiter.Observe(ctx, err, ck)
 
 		klog.V(4).Infof("{{.GCEWrapType}}.{{.Name}}(%v, %v, ...) = %+v", ctx, key, err)
@@ -1104,7 +1550,7 @@ func (g *{{.GCEWrapType}}) {{.FcnArgs}} {
 
 	err = g.s.WaitForCompletion(ctx, op)
 
-    callObserverEnd(ctx, ck, err)
+    callEnd(ctx, ck, err)
 	g.s.RateLimiter.Observe(ctx, err, ck) // XXX
 
 	klog.V(4).Infof("{{.GCEWrapType}}.{{.Name}}(%v, %v, ...) = %+v", ctx, key, err)
@@ -1113,7 +1559,7 @@ func (g *{{.GCEWrapType}}) {{.FcnArgs}} {
 	call.Context(ctx)
 	v, err := call.Do()
 
-    callObserverEnd(ctx, ck, err)
+    callEnd(ctx, ck, err)
 	g.s.RateLimiter.Observe(ctx, err, ck)
 
 	klog.V(4).Infof("{{.GCEWrapType}}.{{.Name}}(%v, %v, ...) = %+v, %v", ctx, key, v, err)
@@ -1122,18 +1568,18 @@ func (g *{{.GCEWrapType}}) {{.FcnArgs}} {
 	var all []*{{.Version}}.{{.ItemType}}
 	f := func(l *{{.Version}}.{{.ReturnType}}) error {
 		klog.V(5).Infof("{{.GCEWrapType}}.{{.Name}}(%v, %v, ...): page %+v", ctx, key, l)
-		all = append(all, l.Items...)
+		all = append(all, l.{{.ItemsField}}...)
 		return nil
 	}
 	if err := call.Pages(ctx, f); err != nil {
-		callObserverEnd(ctx, ck, err)
+		callEnd(ctx, ck, err)
 		g.s.RateLimiter.Observe(ctx, err, ck)
 
 		klog.V(4).Infof("{{.GCEWrapType}}.{{.Name}}(%v, %v, ...) = %v, %v", ctx, key, nil, err)
 		return nil, err
 	}
 
-    callObserverEnd(ctx, ck, nil)
+    callEnd(ctx, ck, nil)
 	g.s.RateLimiter.Observe(ctx, nil, ck)
 
 	if kLogEnabled(4) {
@@ -1147,11 +1593,12 @@ func (g *{{.GCEWrapType}}) {{.FcnArgs}} {
 	}
 	return all, nil
 {{- end}}
+	})
 }
 {{end -}}
 {{- end}}
 `
-	tmpl := template.Must(template.New("interface").Parse(text))
+	tmpl := template.Must(template.New("interface").Funcs(template.FuncMap{"converter": conv.converter}).Parse(text))
 	for _, s := range meta.AllServices {
 		if err := tmpl.Execute(wr, s); err != nil {
 			panic(err)
@@ -1159,350 +1606,1267 @@ func (g *{{.GCEWrapType}}) {{.FcnArgs}} {
 	}
 }
 
-// genTypes generates the type wrappers.
-func genResourceIDs(wr io.Writer) {
-	const text = `
-// New{{.Service}}ResourceID creates a ResourceID for the {{.Service}} resource.
-{{- if .KeyIsProject}}
-func New{{.Service}}ResourceID(project string) *ResourceID {
-	var key *meta.Key
-{{- else}}
-{{- if .KeyIsGlobal}}
-func New{{.Service}}ResourceID(project, name string) *ResourceID {
-	key := meta.GlobalKey(name)
-{{- end}}
-{{- if .KeyIsRegional}}
-func New{{.Service}}ResourceID(project, region, name string) *ResourceID {
-	key := meta.RegionalKey(name, region)
-{{- end}}
-{{- if .KeyIsZonal}}
-func New{{.Service}}ResourceID(project, zone, name string) *ResourceID {
-	key := meta.ZonalKey(name, zone)
-{{- end -}}
-{{end}}
-	return &ResourceID{project, "{{.Resource}}", key}
+// locationAPI is an API of meta.AllLocationServices with its resources.
+type locationAPI struct {
+	API      string
+	Services []*meta.LocationServiceInfo
 }
-`
-	tmpl := template.Must(template.New("resourceIDs").Parse(text))
-	for _, sg := range meta.SortedServicesGroups {
-		if err := tmpl.Execute(wr, sg.ServiceInfo()); err != nil {
-			panic(err)
+
+// locationAPIs groups meta.AllLocationServices by API, in order.
+func locationAPIs() []*locationAPI {
+	var ret []*locationAPI
+	for _, s := range meta.AllLocationServices {
+		if len(ret) == 0 || ret[len(ret)-1].API != s.API {
+			ret = append(ret, &locationAPI{API: s.API})
 		}
+		ret[len(ret)-1].Services = append(ret[len(ret)-1].Services, s)
 	}
+	return ret
 }
 
-func genUnitTestHeader(wr io.Writer) {
-	const text = `/*
-Copyright {{.Year}} The Kubernetes Authors.
+// genLocationServices generates the GCE implementation of the APIs of
+// meta.AllLocationServices. The interfaces, the operations and the mocks
+// are hand written.
+func genLocationServices(wr io.Writer) {
+	const apiText = `
+// NewGCE{{.API}} returns the GCE implementation of {{.API}}. Calls use
+// the s.{{.API}} client.
+func NewGCE{{.API}}(s *Service) *GCE{{.API}} {
+	return &GCE{{.API}}{
+	{{- range .Services}}
+		{{.Field}}: &{{.GCEWrapType}}{s},
+	{{- end}}
+	}
+}
 
-Licensed under the Apache License, Version 2.0 (the "License");
-you may not use this file except in compliance with the License.
-You may obtain a copy of the License at
+// GCE{{.API}} implements {{.API}}.
+type GCE{{.API}} struct {
+{{- range .Services}}
+	{{.Field}} *{{.GCEWrapType}}
+{{- end}}
+}
 
-    http://www.apache.org/licenses/LICENSE-2.0
+// GCE{{.API}} implements {{.API}}.
+var _ {{.API}} = (*GCE{{.API}})(nil)
+{{range .Services}}
+// {{.Service}} implements {{.API}}.
+func (g *GCE{{.API}}) {{.Service}}() {{.API}}Ops[{{.FQObjectType}}] {
+	return g.{{.Field}}
+}
+{{end}}
+`
+	const text = `
+// {{.GCEWrapType}} is a simplifying adapter for the {{.API}} {{.Service}}.
+type {{.GCEWrapType}} struct {
+	s *Service
+}
 
-Unless required by applicable law or agreed to in writing, software
-distributed under the License is distributed on an "AS IS" BASIS,
-WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-See the License for the specific language governing permissions and
-limitations under the License.
-*/
+// {{.GCEWrapType}} implements {{.API}}Ops.
+var _ {{.API}}Ops[{{.FQObjectType}}] = (*{{.GCEWrapType}})(nil)
 
-// This file was generated by "go run gen/main.go -mode test > gen_test.go". Do not edit
-// directly.
+// Get the {{.Object}} named by key.
+func (g *{{.GCEWrapType}}) Get(ctx context.Context, key *meta.Key) (*{{.FQObjectType}}, error) {
+	if g.s.{{.API}} == nil {
+		return nil, err{{.API}}NotConfigured
+	}
+	key = normalizeKey(key)
+	return intercept(ctx, g.s, newCallInfo("{{.Service}}", "Get", meta.VersionGA, key), func(ctx context.Context) (*{{.FQObjectType}}, error) {
+	klog.V(5).Infof("{{.GCEWrapType}}.Get(%v, %v): called", ctx, key)
+	projectID := g.s.ProjectRouter.ProjectID(ctx, meta.VersionGA, "{{.Service}}")
+	_, name, err := locationResourceName(projectID, "{{.Resource}}", {{.GlobalOnly}}, key)
+	if err != nil {
+		klog.V(2).Infof("{{.GCEWrapType}}.Get(%v, %v): key is invalid (%#v)", ctx, key, key)
+		return nil, err
+	}
+	ck := &CallContextKey{
+		ProjectID: projectID,
+		Operation: "Get",
+		Version:   meta.VersionGA,
+		Service:   "{{.Service}}",
+	}
 
-package cloud
+	klog.V(5).Infof("{{.GCEWrapType}}.Get(%v, %v): projectID = %v, ck = %+v", ctx, key, projectID, ck)
+	ctx = g.s.callStart(ctx, ck, key)
+	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		klog.V(4).Infof("{{.GCEWrapType}}.Get(%v, %v): RateLimiter error: %v", ctx, key, err)
+		callEnd(ctx, ck, err)
+		return nil, err
+	}
+	call := g.s.{{.API}}.Projects.Locations.{{.Collection}}.Get(name)
+	callSend(ctx, call.Header())
+	call.Context(ctx)
+	v, err := call.Do()
+	klog.V(4).Infof("{{.GCEWrapType}}.Get(%v, %v) = %+v, %v", ctx, key, v, err)
 
-import (
-	"context"
-	"reflect"
-	"testing"
+	callEnd(ctx, ck, err)
+	g.s.RateLimiter.Observe(ctx, err, ck)
 
-	alpha "{{.AlphaComputePackage}}"
-	beta "{{.BetaComputePackage}}"
-	ga "{{.GaComputePackage}}"
+	return v, err
+	})
+}
 
-	"{{.FilterPackage}}"
-	"{{.MetaPackage}}"
-)
+// List all {{.Object}} objects in location. location is either "global" or
+// a region.
+func (g *{{.GCEWrapType}}) List(ctx context.Context, location string) ([]*{{.FQObjectType}}, error) {
+	if g.s.{{.API}} == nil {
+		return nil, err{{.API}}NotConfigured
+	}
+	location = meta.NormalizeLocation(location)
+	return intercept(ctx, g.s, newCallInfo("{{.Service}}", "List", meta.VersionGA, nil, location), func(ctx context.Context) ([]*{{.FQObjectType}}, error) {
+	klog.V(5).Infof("{{.GCEWrapType}}.List(%v, %v) called", ctx, location)
+{{- if .GlobalOnly}}
+	if location != "global" {
+		return nil, fmt.Errorf("invalid location for {{.Resource}} (%q), must be global", location)
+	}
+{{- end}}
+	projectID := g.s.ProjectRouter.ProjectID(ctx, meta.VersionGA, "{{.Service}}")
+	ck := &CallContextKey{
+		ProjectID: projectID,
+		Operation: "List",
+		Version:   meta.VersionGA,
+		Service:   "{{.Service}}",
+	}
 
-const location = "location"
-`
-	tmpl := template.Must(template.New("header").Parse(text))
-	values := map[string]string{
-		"Year":                fmt.Sprintf("%v", time.Now().Year()),
-		"FilterPackage":       filterPackage,
-		"MetaPackage":         metaPackage,
-		"AlphaComputePackage": alphaComputePackage,
-		"BetaComputePackage":  betaComputePackage,
-		"GaComputePackage":    gaComputePackage,
+	ctx = g.s.callStart(ctx, ck, nil)
+	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		callEnd(ctx, ck, err)
+		return nil, err
 	}
-	if err := tmpl.Execute(wr, values); err != nil {
-		panic(err)
+	klog.V(5).Infof("{{.GCEWrapType}}.List(%v, %v): projectID = %v, ck = %+v", ctx, location, projectID, ck)
+	call := g.s.{{.API}}.Projects.Locations.{{.Collection}}.List(fmt.Sprintf("projects/%s/locations/%s", projectID, location))
+	callSend(ctx, call.Header())
+	var all []*{{.FQObjectType}}
+	f := func(l *{{.ListResponseType}}) error {
+		klog.V(5).Infof("{{.GCEWrapType}}.List(%v, %v): page %+v", ctx, location, l)
+		all = append(all, l.{{.ListItemsField}}...)
+		return nil
 	}
-}
+	if err := call.Pages(ctx, f); err != nil {
+		callEnd(ctx, ck, err)
+		g.s.RateLimiter.Observe(ctx, err, ck)
 
-func genUnitTestServices(wr io.Writer) {
-	const text = `
-func Test{{.Service}}Group(t *testing.T) {
-	t.Parallel()
+		klog.V(4).Infof("{{.GCEWrapType}}.List(%v, %v) = %v, %v", ctx, location, nil, err)
+		return nil, err
+	}
 
-	ctx := context.Background()
-	pr := &SingleProjectRouter{"mock-project"}
-	mock := NewMockGCE(pr)
+	callEnd(ctx, ck, nil)
+	g.s.RateLimiter.Observe(ctx, nil, ck)
 
-	var key *meta.Key
-{{- if .HasAlpha}}
-	keyAlpha := meta.{{.Alpha.MakeKey "key-alpha" "location"}}
-	key = keyAlpha
-{{- end}}
-{{- if .HasBeta}}
-	keyBeta := meta.{{.Beta.MakeKey "key-beta" "location"}}
-	key = keyBeta
-{{- end}}
-{{- if .HasGA}}
-	keyGA := meta.{{.GA.MakeKey "key-ga" "location"}}
-	key = keyGA
-{{- end}}
-	// Ignore unused variables.
-	_, _, _ = ctx, mock, key
+	klog.V(4).Infof("{{.GCEWrapType}}.List(%v, %v) = [%v items], %v", ctx, location, len(all), nil)
+	return all, nil
+	})
+}
 
-	// Get not found.
-{{- if .HasAlpha}}{{- if .Alpha.GenerateGet}}
-	if _, err := mock.Alpha{{.Service}}().Get(ctx, key); err == nil {
-		t.Errorf("Alpha{{.Service}}().Get(%v, %v) = _, nil; want error", ctx, key)
+// Insert {{.Object}} with key of value obj.
+func (g *{{.GCEWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.FQObjectType}}) error {
+	if g.s.{{.API}} == nil {
+		return err{{.API}}NotConfigured
 	}
-{{- end}}{{- end}}
-{{- if .HasBeta}}{{- if .Beta.GenerateGet}}
-	if _, err := mock.Beta{{.Service}}().Get(ctx, key); err == nil {
-		t.Errorf("Beta{{.Service}}().Get(%v, %v) = _, nil; want error", ctx, key)
+	key = normalizeKey(key)
+	return interceptErr(ctx, g.s, newCallInfo("{{.Service}}", "Insert", meta.VersionGA, key, obj), func(ctx context.Context) error {
+	klog.V(5).Infof("{{.GCEWrapType}}.Insert(%v, %v, %+v): called", ctx, key, obj)
+	projectID := g.s.ProjectRouter.ProjectID(ctx, meta.VersionGA, "{{.Service}}")
+	parent, _, err := locationResourceName(projectID, "{{.Resource}}", {{.GlobalOnly}}, key)
+	if err != nil {
+		klog.V(2).Infof("{{.GCEWrapType}}.Insert(%v, %v): key is invalid (%#v)", ctx, key, key)
+		return err
 	}
-{{- end}}{{- end}}
-{{- if .HasGA}}{{- if .GA.GenerateGet}}
-	if _, err := mock.{{.Service}}().Get(ctx, key); err == nil {
-		t.Errorf("{{.Service}}().Get(%v, %v) = _, nil; want error", ctx, key)
+	ck := &CallContextKey{
+		ProjectID: projectID,
+		Operation: "Insert",
+		Version:   meta.VersionGA,
+		Service:   "{{.Service}}",
 	}
-{{- end}}{{- end}}
 
-	// Insert.
-{{- if .HasAlpha}}{{- if .Alpha.GenerateInsert}}
-	{
-		obj := &alpha.{{.Alpha.Object}}{}
-		if err := mock.Alpha{{.Service}}().Insert(ctx, keyAlpha, obj); err != nil {
-			t.Errorf("Alpha{{.Service}}().Insert(%v, %v, %v) = %v; want nil", ctx, keyAlpha, obj, err)
-		}
-	}
-{{- end}}{{- end}}
-{{- if .HasBeta}}{{- if .Beta.GenerateInsert}}
-	{
-		obj := &beta.{{.Beta.Object}}{}
-		if err := mock.Beta{{.Service}}().Insert(ctx, keyBeta, obj); err != nil {
-			t.Errorf("Beta{{.Service}}().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
-		}
+	klog.V(5).Infof("{{.GCEWrapType}}.Insert(%v, %v): projectID = %v, ck = %+v", ctx, key, projectID, ck)
+	ctx = g.s.callStart(ctx, ck, key)
+	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		klog.V(4).Infof("{{.GCEWrapType}}.Insert(%v, %v): RateLimiter error: %v", ctx, key, err)
+		callEnd(ctx, ck, err)
+		return err
 	}
-{{- end}}{{- end}}
-{{- if .HasGA}}{{- if .GA.GenerateInsert}}
-	{
-		obj := &ga.{{.GA.Object}}{}
-		if err := mock.{{.Service}}().Insert(ctx, keyGA, obj); err != nil {
-			t.Errorf("{{.Service}}().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
-		}
+	call := g.s.{{.API}}.Projects.Locations.{{.Collection}}.Create(parent, obj).{{.IDMethod}}(key.Name)
+	callSend(ctx, call.Header())
+{{- if .SupportsRequestID "Insert"}}
+	call.RequestId(callRequestID(ctx))
+{{- end}}
+	call.Context(ctx)
+
+	op, err := call.Do()
+	callSetOperation(ctx, op)
+
+	callEnd(ctx, ck, err)
+	g.s.RateLimiter.Observe(ctx, err, ck)
+
+	if err != nil {
+		klog.V(4).Infof("{{.GCEWrapType}}.Insert(%v, %v) = %+v", ctx, key, err)
+		return err
 	}
-{{- end}}{{- end}}
 
-	// Get across versions.
-{{- if .HasAlpha}}{{- if .Alpha.GenerateInsert}}
-	if obj, err := mock.Alpha{{.Service}}().Get(ctx, key); err != nil {
-		t.Errorf("Alpha{{.Service}}().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	err = g.s.WaitForCompletion(ctx, op)
+	klog.V(4).Infof("{{.GCEWrapType}}.Insert(%v, %v) = %+v", ctx, key, err)
+	return err
+	})
+}
+
+// Delete the {{.Object}} referenced by key.
+func (g *{{.GCEWrapType}}) Delete(ctx context.Context, key *meta.Key) error {
+	if g.s.{{.API}} == nil {
+		return err{{.API}}NotConfigured
 	}
-{{- end}}{{- end}}
-{{- if .HasBeta}}{{- if .Beta.GenerateInsert}}
-	if obj, err := mock.Beta{{.Service}}().Get(ctx, key); err != nil {
-		t.Errorf("Beta{{.Service}}().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	key = normalizeKey(key)
+	return interceptErr(ctx, g.s, newCallInfo("{{.Service}}", "Delete", meta.VersionGA, key), func(ctx context.Context) error {
+	klog.V(5).Infof("{{.GCEWrapType}}.Delete(%v, %v): called", ctx, key)
+	projectID := g.s.ProjectRouter.ProjectID(ctx, meta.VersionGA, "{{.Service}}")
+	_, name, err := locationResourceName(projectID, "{{.Resource}}", {{.GlobalOnly}}, key)
+	if err != nil {
+		klog.V(2).Infof("{{.GCEWrapType}}.Delete(%v, %v): key is invalid (%#v)", ctx, key, key)
+		return err
 	}
-{{- end}}{{- end}}
-{{- if .HasGA}}{{- if .GA.GenerateInsert}}
-	if obj, err := mock.{{.Service}}().Get(ctx, key); err != nil {
-		t.Errorf("{{.Service}}().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	ck := &CallContextKey{
+		ProjectID: projectID,
+		Operation: "Delete",
+		Version:   meta.VersionGA,
+		Service:   "{{.Service}}",
 	}
-{{- end}}{{- end}}
 
-	// List.
-{{- if .HasAlpha}}
-	mock.MockAlpha{{.Service}}.Objects[*keyAlpha] =  mock.MockAlpha{{.Service}}.Obj(&alpha.{{.Alpha.Object}}{Name: keyAlpha.Name})
-{{- end}}
-{{- if .HasBeta}}
-	mock.MockBeta{{.Service}}.Objects[*keyBeta] =  mock.MockBeta{{.Service}}.Obj(&beta.{{.Beta.Object}}{Name: keyBeta.Name})
-{{- end}}
-{{- if .HasGA}}
-	mock.Mock{{.Service}}.Objects[*keyGA] =  mock.Mock{{.Service}}.Obj(&ga.{{.GA.Object}}{Name: keyGA.Name})
-{{- end}}
-	want := map[string]bool{
-{{- if .HasAlpha}}
-		"key-alpha": true,
-{{- end}}
-{{- if .HasBeta}}
-		"key-beta": true,
-{{- end}}
-{{- if .HasGA}}
-		"key-ga": true,
+	klog.V(5).Infof("{{.GCEWrapType}}.Delete(%v, %v): projectID = %v, ck = %+v", ctx, key, projectID, ck)
+	ctx = g.s.callStart(ctx, ck, key)
+	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		klog.V(4).Infof("{{.GCEWrapType}}.Delete(%v, %v): RateLimiter error: %v", ctx, key, err)
+		callEnd(ctx, ck, err)
+		return err
+	}
+	call := g.s.{{.API}}.Projects.Locations.{{.Collection}}.Delete(name)
+	callSend(ctx, call.Header())
+{{- if .SupportsRequestID "Delete"}}
+	call.RequestId(callRequestID(ctx))
 {{- end}}
+	call.Context(ctx)
+
+	op, err := call.Do()
+	callSetOperation(ctx, op)
+
+	callEnd(ctx, ck, err)
+	g.s.RateLimiter.Observe(ctx, err, ck)
+
+	if err != nil {
+		klog.V(4).Infof("{{.GCEWrapType}}.Delete(%v, %v) = %+v", ctx, key, err)
+		return err
 	}
-	_ = want // ignore unused variables.
 
-{{- if .HasAlpha}}{{- if .Alpha.GenerateList}}
-	{
-	{{- if .Alpha.KeyIsGlobal }}
-		objs, err := mock.Alpha{{.Service}}().List(ctx, filter.None)
-	{{- else}}
-		objs, err := mock.Alpha{{.Service}}().List(ctx, location, filter.None)
-	{{- end}}
-		if err != nil {
-			t.Errorf("Alpha{{.Service}}().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
-		} else {
-			got := map[string]bool{}
-			for _, obj := range objs {
-				got[obj.Name] = true
-			}
-			if !reflect.DeepEqual(got, want) {
-				t.Errorf("Alpha{{.Service}}().List(); got %+v, want %+v", got, want)
+	err = g.s.WaitForCompletion(ctx, op)
+	klog.V(4).Infof("{{.GCEWrapType}}.Delete(%v, %v) = %+v", ctx, key, err)
+	return err
+	})
+}
+
+// Patch the {{.Object}} referenced by key with obj. Only the fields in
+// updateMask are changed. If updateMask is empty, all fields in obj are
+// updated.
+func (g *{{.GCEWrapType}}) Patch(ctx context.Context, key *meta.Key, obj *{{.FQObjectType}}, updateMask ...string) error {
+	if g.s.{{.API}} == nil {
+		return err{{.API}}NotConfigured
+	}
+	key = normalizeKey(key)
+	return interceptErr(ctx, g.s, newCallInfo("{{.Service}}", "Patch", meta.VersionGA, key, obj, updateMask), func(ctx context.Context) error {
+	klog.V(5).Infof("{{.GCEWrapType}}.Patch(%v, %v, %+v, %v): called", ctx, key, obj, updateMask)
+	projectID := g.s.ProjectRouter.ProjectID(ctx, meta.VersionGA, "{{.Service}}")
+	_, name, err := locationResourceName(projectID, "{{.Resource}}", {{.GlobalOnly}}, key)
+	if err != nil {
+		klog.V(2).Infof("{{.GCEWrapType}}.Patch(%v, %v): key is invalid (%#v)", ctx, key, key)
+		return err
+	}
+	ck := &CallContextKey{
+		ProjectID: projectID,
+		Operation: "Patch",
+		Version:   meta.VersionGA,
+		Service:   "{{.Service}}",
+	}
+
+	klog.V(5).Infof("{{.GCEWrapType}}.Patch(%v, %v): projectID = %v, ck = %+v", ctx, key, projectID, ck)
+	ctx = g.s.callStart(ctx, ck, key)
+	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		klog.V(4).Infof("{{.GCEWrapType}}.Patch(%v, %v): RateLimiter error: %v", ctx, key, err)
+		callEnd(ctx, ck, err)
+		return err
+	}
+	call := g.s.{{.API}}.Projects.Locations.{{.Collection}}.Patch(name, obj)
+	if len(updateMask) > 0 {
+		call.UpdateMask(strings.Join(updateMask, ","))
+	}
+	callSend(ctx, call.Header())
+{{- if .SupportsRequestID "Patch"}}
+	call.RequestId(callRequestID(ctx))
+{{- end}}
+	call.Context(ctx)
+
+	op, err := call.Do()
+	callSetOperation(ctx, op)
+
+	callEnd(ctx, ck, err)
+	g.s.RateLimiter.Observe(ctx, err, ck)
+
+	if err != nil {
+		klog.V(4).Infof("{{.GCEWrapType}}.Patch(%v, %v) = %+v", ctx, key, err)
+		return err
+	}
+
+	err = g.s.WaitForCompletion(ctx, op)
+	klog.V(4).Infof("{{.GCEWrapType}}.Patch(%v, %v) = %+v", ctx, key, err)
+	return err
+	})
+}
+`
+	apiTmpl := template.Must(template.New("locationAPI").Parse(apiText))
+	tmpl := template.Must(template.New("locationService").Parse(text))
+	for _, api := range locationAPIs() {
+		if err := apiTmpl.Execute(wr, api); err != nil {
+			panic(err)
+		}
+		for _, s := range api.Services {
+			if err := tmpl.Execute(wr, s); err != nil {
+				panic(err)
 			}
 		}
 	}
-{{- end}}{{- end}}
-{{- if .HasBeta}}{{- if .Beta.GenerateList}}
-	{
-	{{- if .Beta.KeyIsGlobal }}
-		objs, err := mock.Beta{{.Service}}().List(ctx, filter.None)
-	{{- else}}
-		objs, err := mock.Beta{{.Service}}().List(ctx, location, filter.None)
-	{{- end}}
-		if err != nil {
-			t.Errorf("Beta{{.Service}}().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
-		} else {
-			got := map[string]bool{}
-			for _, obj := range objs {
-				got[obj.Name] = true
+}
+
+// genUpdateWithRetry generates the read-modify-write helpers.
+func genUpdateWithRetry(wr io.Writer) {
+	const text = `
+{{- if .UpdateMethod}}
+// Update{{.WrapType}}WithRetry gets the {{.Object}} key, applies mutate to it
+// and writes it back with {{.UpdateMethod}}. This is retried with the latest
+// object if the fingerprint is stale. See UpdateWithRetry.
+func Update{{.WrapType}}WithRetry(ctx context.Context, c {{.WrapType}}, key *meta.Key, mutate func(*{{.FQObjectType}}) error) error {
+	return UpdateWithRetry(ctx, key, c.Get, func(ctx context.Context, key *meta.Key, obj *{{.FQObjectType}}) error {
+		return c.{{.UpdateMethod}}(ctx, key, obj)
+	}, mutate)
+}
+{{end -}}
+`
+	tmpl := template.Must(template.New("updateWithRetry").Parse(text))
+	for _, s := range meta.AllServices {
+		if err := tmpl.Execute(wr, s); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// genTypes generates the type wrappers.
+func genResourceIDs(wr io.Writer) {
+	const text = `
+// New{{.Service}}ResourceID creates a ResourceID for the {{.Service}} resource.
+{{- if .KeyIsProject}}
+func New{{.Service}}ResourceID(project string) *ResourceID {
+	var key *meta.Key
+{{- else}}
+{{- if .KeyIsGlobal}}
+func New{{.Service}}ResourceID(project, name string) *ResourceID {
+	key := meta.GlobalKey(name)
+{{- end}}
+{{- if .KeyIsRegional}}
+func New{{.Service}}ResourceID(project, region, name string) *ResourceID {
+	key := meta.RegionalKey(name, region)
+{{- end}}
+{{- if .KeyIsZonal}}
+func New{{.Service}}ResourceID(project, zone, name string) *ResourceID {
+	key := meta.ZonalKey(name, zone)
+{{- end -}}
+{{end}}
+	return &ResourceID{project, "{{.Resource}}", key}
+}
+`
+	tmpl := template.Must(template.New("resourceIDs").Parse(text))
+	for _, sg := range meta.SortedServicesGroups {
+		if err := tmpl.Execute(wr, sg.ServiceInfo()); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// objectVersions returns the ServiceInfo for each version of each object
+// type, sorted by object name and in alpha, beta, ga order.
+func objectVersions() [][]*meta.ServiceInfo {
+	byObject := map[string]map[meta.Version]*meta.ServiceInfo{}
+	for _, s := range meta.AllServices {
+		if byObject[s.Object] == nil {
+			byObject[s.Object] = map[meta.Version]*meta.ServiceInfo{}
+		}
+		if _, ok := byObject[s.Object][s.Version()]; !ok {
+			byObject[s.Object][s.Version()] = s
+		}
+	}
+	var objects []string
+	for o := range byObject {
+		objects = append(objects, o)
+	}
+	sort.Strings(objects)
+
+	var ret [][]*meta.ServiceInfo
+	for _, o := range objects {
+		var versions []*meta.ServiceInfo
+		for _, v := range []meta.Version{meta.VersionAlpha, meta.VersionBeta, meta.VersionGA} {
+			if s, ok := byObject[o][v]; ok {
+				versions = append(versions, s)
 			}
-			if !reflect.DeepEqual(got, want) {
-				t.Errorf("Beta{{.Service}}().List(); got %+v, want %+v", got, want)
+		}
+		ret = append(ret, versions)
+	}
+	return ret
+}
+
+// genDeepCopy generates the DeepCopy functions for the objects.
+func genDeepCopy(wr io.Writer, conv *versionConverters) {
+	const text = `
+// DeepCopy{{.VersionPrefix}}{{.Object}} returns a deep copy of obj.
+func DeepCopy{{.VersionPrefix}}{{.Object}}(obj *{{.FQObjectType}}) *{{.FQObjectType}} {
+	if obj == nil {
+		return nil
+	}
+	ret := &{{.FQObjectType}}{}
+	{{converter .ObjectGoType .ObjectGoType}}(ret, obj)
+	return ret
+}
+`
+	tmpl := template.Must(template.New("deepcopy").Funcs(template.FuncMap{"converter": conv.converter}).Parse(text))
+	for _, versions := range objectVersions() {
+		for _, s := range versions {
+			if err := tmpl.Execute(wr, s); err != nil {
+				panic(err)
 			}
 		}
 	}
-{{- end}}{{- end}}
-{{- if .HasGA}}{{- if .GA.GenerateList}}
-	{
-	{{- if .GA.KeyIsGlobal }}
-		objs, err := mock.{{.Service}}().List(ctx, filter.None)
-	{{- else}}
-		objs, err := mock.{{.Service}}().List(ctx, location, filter.None)
-	{{- end}}
-		if err != nil {
-			t.Errorf("{{.Service}}().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
-		} else {
-			got := map[string]bool{}
-			for _, obj := range objs {
-				got[obj.Name] = true
+}
+
+// genConverters generates the functions that convert objects between API
+// versions.
+func genConverters(wr io.Writer, conv *versionConverters) {
+	const text = `
+// {{.Object}}To{{.To.VersionTitle}} converts obj, a {{.Object}} of any API
+// version, to the {{.To.Version}} version. Fields that are not preserved by
+// the conversion are returned in the ConversionLoss. If obj is already the
+// {{.To.Version}} version, it is returned as-is.
+func {{.Object}}To{{.To.VersionTitle}}(obj interface{}) (*{{.To.FQObjectType}}, *ConversionLoss, error) {
+	switch o := obj.(type) {
+	case *{{.To.FQObjectType}}:
+		return o, &ConversionLoss{}, nil
+{{- range .From}}
+	case *{{.FQObjectType}}:
+		if o == nil {
+			return nil, &ConversionLoss{}, nil
+		}
+		ret, loss := &{{$.To.FQObjectType}}{}, &ConversionLoss{}
+		{{converter $.To.ObjectGoType .ObjectGoType}}(ret, o, loss, "")
+		return ret, loss, nil
+{{- end}}
+	}
+	return nil, nil, fmt.Errorf("{{.Object}}To{{.To.VersionTitle}}: unsupported type %T", obj)
+}
+`
+	tmpl := template.Must(template.New("converters").Funcs(template.FuncMap{"converter": conv.converter}).Parse(text))
+	for _, versions := range objectVersions() {
+		o := versions[0].Object
+		for _, to := range versions {
+			data := struct {
+				Object string
+				To     *meta.ServiceInfo
+				From   []*meta.ServiceInfo
+			}{Object: o, To: to}
+			for _, from := range versions {
+				if from != to {
+					data.From = append(data.From, from)
+				}
 			}
-			if !reflect.DeepEqual(got, want) {
-				t.Errorf("{{.Service}}().List(); got %+v, want %+v", got, want)
+			if err := tmpl.Execute(wr, data); err != nil {
+				panic(err)
 			}
 		}
 	}
-{{- end}}{{- end}}
+}
 
-	// Delete across versions.
-{{- if .HasAlpha}}{{- if .Alpha.GenerateDelete}}
-	if err := mock.Alpha{{.Service}}().Delete(ctx, keyAlpha); err != nil {
-		t.Errorf("Alpha{{.Service}}().Delete(%v, %v) = %v; want nil", ctx, keyAlpha, err)
-	}
-{{- end}}{{- end}}
-{{- if .HasBeta}}{{- if .Beta.GenerateDelete}}
-	if err := mock.Beta{{.Service}}().Delete(ctx, keyBeta); err != nil {
-		t.Errorf("Beta{{.Service}}().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+// goPackages are the names of the packages of the types in the generated
+// code, by import path.
+var goPackages = map[string]string{
+	alphaComputePackage: "alpha",
+	betaComputePackage:  "beta",
+	gaComputePackage:    "ga",
+	googleAPIPackage:    "googleapi",
+	"net/http":          "http",
+}
+
+// goTypeName returns the name of the type t in the generated code, e.g.
+// "*ga.Backend" or "map[string]string".
+func goTypeName(t reflect.Type) string {
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name()
+		}
+		pkg, ok := goPackages[t.PkgPath()]
+		if !ok {
+			panic(fmt.Sprintf("goTypeName: unknown package %q of %s", t.PkgPath(), t))
+		}
+		return pkg + "." + t.Name()
 	}
-{{- end}}{{- end}}
-{{- if .HasGA}}{{- if .GA.GenerateDelete}}
-	if err := mock.{{.Service}}().Delete(ctx, keyGA); err != nil {
-		t.Errorf("{{.Service}}().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + goTypeName(t.Elem())
+	case reflect.Slice:
+		return "[]" + goTypeName(t.Elem())
+	case reflect.Map:
+		return "map[" + goTypeName(t.Key()) + "]" + goTypeName(t.Elem())
 	}
-{{- end}}{{- end}}
+	panic(fmt.Sprintf("goTypeName: unsupported type %s", t))
+}
 
-	// Delete not found.
-{{- if .HasAlpha}}{{- if .Alpha.GenerateDelete}}
-	if err := mock.Alpha{{.Service}}().Delete(ctx, keyAlpha); err == nil {
-		t.Errorf("Alpha{{.Service}}().Delete(%v, %v) = nil; want error", ctx, keyAlpha)
+// versionConverters generates the functions that copy the structs of the
+// compute API field by field, either between API versions (e.g. alpha to ga)
+// or within a version for the DeepCopy functions. Fields are matched by name.
+// The conversions add the fields that are not preserved to a
+// ConversionLoss.
+type versionConverters struct {
+	names map[[2]reflect.Type]string
+	queue [][2]reflect.Type
+}
+
+func newVersionConverters() *versionConverters {
+	return &versionConverters{names: map[[2]reflect.Type]string{}}
+}
+
+// converter returns the name of the function copying the struct type src to
+// the struct type dest. The function is generated by gen(). If the types are
+// the same, the function is a deep copy:
+//
+//	func(dest, src *T)
+//
+// Otherwise, it is a conversion, with path the JSON path of src in the
+// converted object:
+//
+//	func(dest *D, src *S, loss *ConversionLoss, path string)
+func (c *versionConverters) converter(dest, src reflect.Type) string {
+	k := [2]reflect.Type{dest, src}
+	if name, ok := c.names[k]; ok {
+		return name
 	}
-{{- end}}{{- end}}
-{{- if .HasBeta}}{{- if .Beta.GenerateDelete}}
-	if err := mock.Beta{{.Service}}().Delete(ctx, keyBeta); err == nil {
-		t.Errorf("Beta{{.Service}}().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	typeName := func(t reflect.Type) string {
+		prefix, ok := map[string]string{
+			alphaComputePackage: "Alpha",
+			betaComputePackage:  "Beta",
+			gaComputePackage:    "GA",
+			googleAPIPackage:    "GoogleAPI",
+		}[t.PkgPath()]
+		if !ok || t.Kind() != reflect.Struct {
+			panic(fmt.Sprintf("converter: unsupported type %s", t))
+		}
+		return prefix + t.Name()
 	}
-{{- end}}{{- end}}
-{{- if .HasGA}}{{- if .GA.GenerateDelete}}
-	if err := mock.{{.Service}}().Delete(ctx, keyGA); err == nil {
-		t.Errorf("{{.Service}}().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	name := "copy" + typeName(src)
+	if dest != src {
+		name = "convert" + typeName(src) + "To" + typeName(dest)
 	}
-{{- end}}{{- end}}
+	c.names[k] = name
+	c.queue = append(c.queue, k)
+	return name
 }
-`
-	tmpl := template.Must(template.New("unittest").Parse(text))
-	for _, sg := range meta.SortedServicesGroups {
-		if err := tmpl.Execute(wr, sg); err != nil {
-			panic(err)
-		}
+
+// gen writes the functions for all of the types returned by converter().
+func (c *versionConverters) gen(wr io.Writer) {
+	for i := 0; i < len(c.queue); i++ {
+		c.genStruct(wr, c.queue[i][0], c.queue[i][1])
 	}
 }
 
-func genUnitTestResourceIDConversion(wr io.Writer) {
-	const text = `
-func TestResourceIDConversion(t *testing.T) {
-	t.Parallel()
+func (c *versionConverters) genStruct(wr io.Writer, dest, src reflect.Type) {
+	conv := dest != src
+	name := c.converter(dest, src)
 
-	for _, id := range []*ResourceID{
-		{{- range .Groups}}
-		{{- with .ServiceInfo}}
-		{{- if .KeyIsProject}}
-		New{{.Service}}ResourceID("my-{{.Resource}}-resource"),
-		{{- else}}
-		{{- if .KeyIsGlobal}}
-		New{{.Service}}ResourceID("some-project", "my-{{.Resource}}-resource"),
-		{{- end}}
-		{{- if .KeyIsRegional}}
-		New{{.Service}}ResourceID("some-project", "us-central1", "my-{{.Resource}}-resource"),
-		{{- end}}
-		{{- if .KeyIsZonal}}
-		New{{.Service}}ResourceID("some-project", "us-east1-b", "my-{{.Resource}}-resource"),
-		{{- end -}}
-		{{end -}}
-		{{end -}}
-		{{end}}
-	} {
-		t.Run(id.Resource, func(t *testing.T) {
-			// Test conversion to and from full URL.
-			fullURL := id.SelfLink(meta.VersionGA)
-			parsedID, err := ParseResourceURL(fullURL)
-			if err != nil {
-				t.Errorf("ParseResourceURL(%s) = _, %v, want nil", fullURL, err)
-			}
-			if !reflect.DeepEqual(id, parsedID) {
-				t.Errorf("SelfLink(%+v) -> ParseResourceURL(%s) = %+v, want original ID", id, fullURL, parsedID)
+	fields := func(t reflect.Type) map[string]bool {
+		ret := map[string]bool{}
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				ret[t.Field(i).Name] = true
 			}
+		}
+		return ret
+	}
+	destFields, srcFields := fields(dest), fields(src)
+	isMetaField := func(name string) bool {
+		return name == "ForceSendFields" || name == "NullFields"
+	}
+	// ForceSendFields and NullFields drop the entries of the fields that
+	// are not in dest.
+	var dropped []string
+	for i := 0; i < src.NumField(); i++ {
+		if f := src.Field(i); f.IsExported() && !destFields[f.Name] {
+			dropped = append(dropped, fmt.Sprintf("%q", f.Name))
+		}
+	}
 
-			// Test conversion to and from relative resource name.
-			relativeName := id.RelativeResourceName()
-			parsedID, err = ParseResourceURL(relativeName)
-			if err != nil {
-				t.Errorf("ParseResourceURL(%s) = _, %v, want nil", relativeName, err)
+	var body bytes.Buffer
+	for i := 0; i < src.NumField(); i++ {
+		sf := src.Field(i)
+		if !sf.IsExported() || (!destFields[sf.Name] && isMetaField(sf.Name)) {
+			continue
+		}
+		jsonName := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if jsonName == "" {
+			jsonName = sf.Name
+		}
+		df, ok := dest.FieldByName(sf.Name)
+		if !ok && jsonName == "-" {
+			// Not part of the resource (e.g. the ServerResponse).
+			continue
+		}
+		path := fmt.Sprintf("joinPath(path, %q)", jsonName)
+		switch {
+		case ok && isMetaField(sf.Name):
+			fmt.Fprintf(&body, "\tdest.%v = metaFields(%v)\n", df.Name, strings.Join(append([]string{"src." + sf.Name}, dropped...), ", "))
+			continue
+		case ok && c.genValue(&body, df.Type, sf.Type, "dest."+df.Name, "src."+sf.Name, path, 1):
+			continue
+		case !conv:
+			panic(fmt.Sprintf("versionConverters: cannot copy %s.%s", src, sf.Name))
+		}
+		fmt.Fprintf(&body, "\tif %v {\n\t\tloss.addDropped(%v)\n\t}\n", nonZero("src."+sf.Name, sf.Type), path)
+	}
+	if conv {
+		for i := 0; i < dest.NumField(); i++ {
+			df := dest.Field(i)
+			if !df.IsExported() || srcFields[df.Name] || isMetaField(df.Name) {
+				continue
 			}
-			if !reflect.DeepEqual(id, parsedID) {
-				t.Errorf("RelativeResourceName(%+v) -> ParseResourceURL(%s) = %+v, want original ID", id, relativeName, parsedID)
+			jsonName := strings.Split(df.Tag.Get("json"), ",")[0]
+			if jsonName == "" || jsonName == "-" {
+				jsonName = df.Name
 			}
+			fmt.Fprintf(&body, "\tloss.addDefaulted(joinPath(path, %q))\n", jsonName)
+		}
+		fmt.Fprintf(wr, `
+// %[1]v converts src to dest.
+func %[1]v(dest *%[2]v, src *%[3]v, loss *ConversionLoss, path string) {
+%[4]v}
+`, name, goTypeName(dest), goTypeName(src), body.String())
+		return
+	}
+	fmt.Fprintf(wr, `
+// %[1]v copies src to dest.
+func %[1]v(dest, src *%[2]v) {
+%[3]v}
+`, name, goTypeName(src), body.String())
+}
 
-			// Do not test ResourcePath for projects.
-			if id.Resource == "projects" {
-				return
-			}
+// genValue writes the statements copying the expression src of type st to the
+// expression dest of type dt, at the given indentation depth. path is the
+// expression of the JSON path of src for the conversions. It returns false
+// if the types cannot be converted, in which case nothing is written.
+func (c *versionConverters) genValue(wr io.Writer, dt, st reflect.Type, dest, src, path string, depth int) bool {
+	indent := strings.Repeat("\t", depth)
+	// Variables of nested loops are suffixed by their depth.
+	v := func(name string) string {
+		if depth == 1 {
+			return name
+		}
+		return fmt.Sprintf("%v%v", name, depth)
+	}
+	call := func(dt, st reflect.Type, dest, src string) string {
+		if dt == st {
+			return fmt.Sprintf("%v(%v, %v)", c.converter(dt, st), dest, src)
+		}
+		return fmt.Sprintf("%v(%v, %v, loss, %v)", c.converter(dt, st), dest, src, path)
+	}
+
+	var b bytes.Buffer
+	switch {
+	case isBasicKind(dt.Kind()) && isBasicKind(st.Kind()):
+		if dt.Kind() != st.Kind() {
+			return false
+		}
+		if dt != st {
+			src = fmt.Sprintf("%v(%v)", goTypeName(dt), src)
+		}
+		fmt.Fprintf(&b, "%v%v = %v\n", indent, dest, src)
+
+	case dt.Kind() == reflect.Ptr && st.Kind() == reflect.Ptr:
+		de, se := dt.Elem(), st.Elem()
+		switch {
+		case de.Kind() == reflect.Struct && se.Kind() == reflect.Struct:
+			fmt.Fprintf(&b, "%[1]vif %[2]v != nil {\n%[1]v\t%[3]v = &%[4]v{}\n%[1]v\t%[5]v\n%[1]v}\n", indent, src, dest, goTypeName(de), call(de, se, dest, src))
+		case isBasicKind(de.Kind()) && de.Kind() == se.Kind():
+			val := "*" + src
+			if de != se {
+				val = fmt.Sprintf("%v(%v)", goTypeName(de), val)
+			}
+			fmt.Fprintf(&b, "%[1]vif %[2]v != nil {\n%[1]v\t%[3]v := %[4]v\n%[1]v\t%[5]v = &%[3]v\n%[1]v}\n", indent, src, v("v"), val, dest)
+		default:
+			return false
+		}
+
+	case dt.Kind() == reflect.Slice && st.Kind() == reflect.Slice:
+		fmt.Fprintf(&b, "%[1]vif %[2]v != nil {\n%[1]v\t%[3]v = make(%[4]v, len(%[2]v))\n", indent, src, dest, goTypeName(dt))
+		if dt.Elem() == st.Elem() && isBasicKind(st.Elem().Kind()) {
+			fmt.Fprintf(&b, "%[1]v\tcopy(%[2]v, %[3]v)\n", indent, dest, src)
+		} else {
+			i := v("i")
+			fmt.Fprintf(&b, "%[1]v\tfor %[2]v := range %[3]v {\n", indent, i, src)
+			if !c.genValue(&b, dt.Elem(), st.Elem(), dest+"["+i+"]", src+"["+i+"]", path, depth+2) {
+				return false
+			}
+			fmt.Fprintf(&b, "%v\t}\n", indent)
+		}
+		fmt.Fprintf(&b, "%v}\n", indent)
+
+	case dt.Kind() == reflect.Map && st.Kind() == reflect.Map:
+		dk, sk := dt.Key(), st.Key()
+		if !isBasicKind(dk.Kind()) || dk.Kind() != sk.Kind() {
+			return false
+		}
+		k, val := v("k"), v("v")
+		key := k
+		if dk != sk {
+			key = fmt.Sprintf("%v(%v)", goTypeName(dk), k)
+		}
+		fmt.Fprintf(&b, "%[1]vif %[2]v != nil {\n%[1]v\t%[3]v = make(%[4]v, len(%[2]v))\n%[1]v\tfor %[5]v, %[6]v := range %[2]v {\n", indent, src, dest, goTypeName(dt), k, val)
+		if dt.Elem() == st.Elem() && isBasicKind(st.Elem().Kind()) {
+			fmt.Fprintf(&b, "%[1]v\t\t%[2]v[%[3]v] = %[4]v\n", indent, dest, key, val)
+		} else {
+			d := v("d")
+			fmt.Fprintf(&b, "%[1]v\t\tvar %[2]v %[3]v\n", indent, d, goTypeName(dt.Elem()))
+			if !c.genValue(&b, dt.Elem(), st.Elem(), d, val, path, depth+2) {
+				return false
+			}
+			fmt.Fprintf(&b, "%[1]v\t\t%[2]v[%[3]v] = %[4]v\n", indent, dest, key, d)
+		}
+		fmt.Fprintf(&b, "%[1]v\t}\n%[1]v}\n", indent)
+
+	case dt.Kind() == reflect.Struct && st.Kind() == reflect.Struct:
+		fmt.Fprintf(&b, "%v%v\n", indent, call(dt, st, "&"+dest, "&"+src))
+
+	default:
+		return false
+	}
+	wr.Write(b.Bytes())
+	return true
+}
+
+// nonZero returns the condition that the expression v of type t is not the
+// zero value.
+func nonZero(v string, t reflect.Type) string {
+	switch k := t.Kind(); {
+	case k == reflect.Bool:
+		return v
+	case k == reflect.String:
+		return v + ` != ""`
+	case isBasicKind(k):
+		return v + " != 0"
+	case k == reflect.Ptr || k == reflect.Slice || k == reflect.Map || k == reflect.Interface:
+		return v + " != nil"
+	}
+	panic(fmt.Sprintf("nonZero: unsupported type %s", t))
+}
+
+// isBasicKind is true for the kinds that are copied by value.
+func isBasicKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool,
+		reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// unorderedListFields are the lists that the generated Diff functions compare
+// as sets, by object type. Paths do not contain list indices.
+var unorderedListFields = map[string][]string{
+	"Address":           {"users"},
+	"BackendService":    {"backends", "healthChecks", "customRequestHeaders", "customResponseHeaders", "securitySettings.subjectAltNames"},
+	"Firewall":          {"allowed", "allowed.ports", "denied", "denied.ports", "destinationRanges", "sourceRanges", "sourceServiceAccounts", "sourceTags", "targetServiceAccounts", "targetTags"},
+	"ForwardingRule":    {"ports", "sourceIpRanges"},
+	"InstanceGroup":     {"namedPorts"},
+	"Router":            {"nats", "nats.natIps", "nats.drainNatIps", "nats.subnetworks", "nats.subnetworks.secondaryIpRangeNames", "nats.subnetworks.sourceIpRangesToNat", "bgpPeers", "interfaces"},
+	"ServiceAttachment": {"consumerAcceptLists", "consumerRejectLists", "natSubnets", "domainNames"},
+	"SslCertificate":    {"managed.domains", "subjectAlternativeNames"},
+	"SslPolicy":         {"customFeatures"},
+	"Subnetwork":        {"secondaryIpRanges"},
+	"TargetHttpsProxy":  {"sslCertificates"},
+	"TargetPool":        {"healthChecks", "instances"},
+	"TargetSslProxy":    {"sslCertificates"},
+	"UrlMap":            {"hostRules", "hostRules.hosts", "pathMatchers", "pathMatchers.pathRules.paths", "tests"},
+}
+
+// discoverySchemas caches the schemas of the Discovery document of each
+// compute API version.
+var discoverySchemas = map[meta.Version]map[string]*discoverySchema{}
+
+// discoverySchema is the subset of a Discovery document schema used by the
+// generator.
+type discoverySchema struct {
+	Ref         string                      `json:"$ref"`
+	Type        string                      `json:"type"`
+	Description string                      `json:"description"`
+	Default     string                      `json:"default"`
+	Properties  map[string]*discoverySchema `json:"properties"`
+	Items       *discoverySchema            `json:"items"`
+}
+
+// loadDiscoverySchemas reads the schemas from the Discovery document
+// (compute-api.json) that is shipped with the compute client package.
+func loadDiscoverySchemas(v meta.Version) map[string]*discoverySchema {
+	if s, ok := discoverySchemas[v]; ok {
+		return s
+	}
+	pkg := map[meta.Version]string{
+		meta.VersionGA:    gaComputePackage,
+		meta.VersionAlpha: alphaComputePackage,
+		meta.VersionBeta:  betaComputePackage,
+	}[v]
+	p, err := build.Import(pkg, ".", build.FindOnly)
+	if err != nil {
+		panic(err)
+	}
+	b, err := os.ReadFile(filepath.Join(p.Dir, "compute-api.json"))
+	if err != nil {
+		panic(err)
+	}
+	var doc struct {
+		Schemas map[string]*discoverySchema `json:"schemas"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		panic(err)
+	}
+	discoverySchemas[v] = doc.Schemas
+	return doc.Schemas
+}
+
+// outputOnlyFields returns the paths of the fields of the object that are
+// documented as "[Output Only]" in the Discovery document.
+func outputOnlyFields(v meta.Version, object string) []string {
+	schemas := loadDiscoverySchemas(v)
+	var ret []string
+	var walk func(name, path string, seen map[string]bool)
+	walk = func(name, path string, seen map[string]bool) {
+		s, ok := schemas[name]
+		if !ok || seen[name] {
+			return
+		}
+		seen[name] = true
+		defer delete(seen, name)
+		for p, prop := range s.Properties {
+			if strings.HasPrefix(prop.Description, "[Output Only]") {
+				ret = append(ret, path+p)
+				continue
+			}
+			ref := prop.Ref
+			if prop.Items != nil {
+				ref = prop.Items.Ref
+			}
+			if ref != "" {
+				walk(ref, path+p+".", seen)
+			}
+		}
+	}
+	walk(object, "", map[string]bool{})
+	sort.Strings(ret)
+	return ret
+}
+
+// hasDiscoveryField returns true if path is a field of the object.
+func hasDiscoveryField(v meta.Version, object, path string) bool {
+	schemas := loadDiscoverySchemas(v)
+	s := schemas[object]
+	for _, name := range strings.Split(path, ".") {
+		if s == nil {
+			return false
+		}
+		if s.Ref != "" {
+			s = schemas[s.Ref]
+		}
+		if s != nil && s.Items != nil {
+			s = s.Items
+			if s.Ref != "" {
+				s = schemas[s.Ref]
+			}
+		}
+		if s == nil {
+			return false
+		}
+		s = s.Properties[name]
+	}
+	return s != nil
+}
+
+// genDiff generates the Diff functions for the objects.
+func genDiff(wr io.Writer) {
+	const text = `
+// Diff{{.VersionPrefix}}{{.Object}} returns the differences between a and b.
+// Output only and server populated fields (e.g. selfLink, fingerprints) are
+// ignored.
+{{- if .Unordered}}
+// Lists in diffOptions{{.VersionPrefix}}{{.Object}}.unordered are compared
+// regardless of order.
+{{- end}}
+func Diff{{.VersionPrefix}}{{.Object}}(a, b *{{.FQObjectType}}) []FieldDiff {
+	return diffObjects(a, b, diffOptions{{.VersionPrefix}}{{.Object}})
+}
+
+var diffOptions{{.VersionPrefix}}{{.Object}} = &diffOptions{
+	ignore: map[string]bool{
+{{- range .Ignore}}
+		"{{.}}": true,
+{{- end}}
+	},
+{{- if .Unordered}}
+	unordered: map[string]bool{
+{{- range .Unordered}}
+		"{{.}}": true,
+{{- end}}
+	},
+{{- end}}
+}
+`
+	tmpl := template.Must(template.New("diff").Parse(text))
+	for _, versions := range objectVersions() {
+		for _, s := range versions {
+			data := struct {
+				*meta.ServiceInfo
+				Ignore    []string
+				Unordered []string
+			}{ServiceInfo: s, Ignore: outputOnlyFields(s.Version(), s.Object)}
+			for _, f := range unorderedListFields[s.Object] {
+				if hasDiscoveryField(s.Version(), s.Object, f) {
+					data.Unordered = append(data.Unordered, f)
+				}
+			}
+			if err := tmpl.Execute(wr, data); err != nil {
+				panic(err)
+			}
+		}
+	}
+	// Catch typos in unorderedListFields.
+	for object, fields := range unorderedListFields {
+		for _, f := range fields {
+			if !hasDiscoveryField(meta.VersionAlpha, object, f) {
+				panic(fmt.Sprintf("unorderedListFields: %s has no field %q", object, f))
+			}
+		}
+	}
+}
+
+func genUnitTestHeader(wr io.Writer) {
+	const text = `/*
+Copyright {{.Year}} The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file was generated by "go run gen/main.go -mode test > gen_test.go". Do not edit
+// directly.
+
+package cloud
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	alpha "{{.AlphaComputePackage}}"
+	beta "{{.BetaComputePackage}}"
+	ga "{{.GaComputePackage}}"
+
+	"{{.FilterPackage}}"
+	"{{.MetaPackage}}"
+)
+
+const location = "location"
+`
+	tmpl := template.Must(template.New("header").Parse(text))
+	values := map[string]string{
+		"Year":                fmt.Sprintf("%v", time.Now().Year()),
+		"FilterPackage":       filterPackage,
+		"MetaPackage":         metaPackage,
+		"AlphaComputePackage": alphaComputePackage,
+		"BetaComputePackage":  betaComputePackage,
+		"GaComputePackage":    gaComputePackage,
+	}
+	if err := tmpl.Execute(wr, values); err != nil {
+		panic(err)
+	}
+}
+
+func genUnitTestServices(wr io.Writer) {
+	const text = `
+func Test{{.Service}}Group(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+{{- if .HasAlpha}}
+	keyAlpha := meta.{{.Alpha.MakeKey "key-alpha" "location"}}
+	key = keyAlpha
+{{- end}}
+{{- if .HasBeta}}
+	keyBeta := meta.{{.Beta.MakeKey "key-beta" "location"}}
+	key = keyBeta
+{{- end}}
+{{- if .HasGA}}
+	keyGA := meta.{{.GA.MakeKey "key-ga" "location"}}
+	key = keyGA
+{{- end}}
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+{{- if .HasAlpha}}{{- if .Alpha.GenerateGet}}
+	if _, err := mock.Alpha{{.Service}}().Get(ctx, key); err == nil {
+		t.Errorf("Alpha{{.Service}}().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+{{- end}}{{- end}}
+{{- if .HasBeta}}{{- if .Beta.GenerateGet}}
+	if _, err := mock.Beta{{.Service}}().Get(ctx, key); err == nil {
+		t.Errorf("Beta{{.Service}}().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+{{- end}}{{- end}}
+{{- if .HasGA}}{{- if .GA.GenerateGet}}
+	if _, err := mock.{{.Service}}().Get(ctx, key); err == nil {
+		t.Errorf("{{.Service}}().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+{{- end}}{{- end}}
+
+	// Insert.
+{{- if .HasAlpha}}{{- if .Alpha.GenerateInsert}}
+	{
+		obj := &alpha.{{.Alpha.Object}}{}
+		if err := mock.Alpha{{.Service}}().Insert(ctx, keyAlpha, obj); err != nil {
+			t.Errorf("Alpha{{.Service}}().Insert(%v, %v, %v) = %v; want nil", ctx, keyAlpha, obj, err)
+		}
+	}
+{{- end}}{{- end}}
+{{- if .HasBeta}}{{- if .Beta.GenerateInsert}}
+	{
+		obj := &beta.{{.Beta.Object}}{}
+		if err := mock.Beta{{.Service}}().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("Beta{{.Service}}().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+{{- end}}{{- end}}
+{{- if .HasGA}}{{- if .GA.GenerateInsert}}
+	{
+		obj := &ga.{{.GA.Object}}{}
+		if err := mock.{{.Service}}().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("{{.Service}}().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+{{- end}}{{- end}}
+
+	// Get across versions.
+{{- if .HasAlpha}}{{- if .Alpha.GenerateInsert}}
+	if obj, err := mock.Alpha{{.Service}}().Get(ctx, key); err != nil {
+		t.Errorf("Alpha{{.Service}}().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+{{- end}}{{- end}}
+{{- if .HasBeta}}{{- if .Beta.GenerateInsert}}
+	if obj, err := mock.Beta{{.Service}}().Get(ctx, key); err != nil {
+		t.Errorf("Beta{{.Service}}().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+{{- end}}{{- end}}
+{{- if .HasGA}}{{- if .GA.GenerateInsert}}
+	if obj, err := mock.{{.Service}}().Get(ctx, key); err != nil {
+		t.Errorf("{{.Service}}().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+{{- end}}{{- end}}
+
+	// List.
+{{- if .HasAlpha}}
+	mock.MockAlpha{{.Service}}.Objects[*keyAlpha] =  mock.MockAlpha{{.Service}}.Obj(&alpha.{{.Alpha.Object}}{Name: keyAlpha.Name})
+{{- end}}
+{{- if .HasBeta}}
+	mock.MockBeta{{.Service}}.Objects[*keyBeta] =  mock.MockBeta{{.Service}}.Obj(&beta.{{.Beta.Object}}{Name: keyBeta.Name})
+{{- end}}
+{{- if .HasGA}}
+	mock.Mock{{.Service}}.Objects[*keyGA] =  mock.Mock{{.Service}}.Obj(&ga.{{.GA.Object}}{Name: keyGA.Name})
+{{- end}}
+	want := map[string]bool{
+{{- if .HasAlpha}}
+		"key-alpha": true,
+{{- end}}
+{{- if .HasBeta}}
+		"key-beta": true,
+{{- end}}
+{{- if .HasGA}}
+		"key-ga": true,
+{{- end}}
+	}
+	_ = want // ignore unused variables.
+
+{{- if .HasAlpha}}{{- if .Alpha.GenerateList}}
+	{
+	{{- if .Alpha.KeyIsGlobal }}
+		objs, err := mock.Alpha{{.Service}}().List(ctx, filter.None)
+	{{- else}}
+		objs, err := mock.Alpha{{.Service}}().List(ctx, location, filter.None)
+	{{- end}}
+		if err != nil {
+			t.Errorf("Alpha{{.Service}}().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Alpha{{.Service}}().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+{{- end}}{{- end}}
+{{- if .HasBeta}}{{- if .Beta.GenerateList}}
+	{
+	{{- if .Beta.KeyIsGlobal }}
+		objs, err := mock.Beta{{.Service}}().List(ctx, filter.None)
+	{{- else}}
+		objs, err := mock.Beta{{.Service}}().List(ctx, location, filter.None)
+	{{- end}}
+		if err != nil {
+			t.Errorf("Beta{{.Service}}().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Beta{{.Service}}().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+{{- end}}{{- end}}
+{{- if .HasGA}}{{- if .GA.GenerateList}}
+	{
+	{{- if .GA.KeyIsGlobal }}
+		objs, err := mock.{{.Service}}().List(ctx, filter.None)
+	{{- else}}
+		objs, err := mock.{{.Service}}().List(ctx, location, filter.None)
+	{{- end}}
+		if err != nil {
+			t.Errorf("{{.Service}}().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("{{.Service}}().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+{{- end}}{{- end}}
+
+	// Delete across versions.
+{{- if .HasAlpha}}{{- if .Alpha.GenerateDelete}}
+	if err := mock.Alpha{{.Service}}().Delete(ctx, keyAlpha); err != nil {
+		t.Errorf("Alpha{{.Service}}().Delete(%v, %v) = %v; want nil", ctx, keyAlpha, err)
+	}
+{{- end}}{{- end}}
+{{- if .HasBeta}}{{- if .Beta.GenerateDelete}}
+	if err := mock.Beta{{.Service}}().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("Beta{{.Service}}().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
+{{- end}}{{- end}}
+{{- if .HasGA}}{{- if .GA.GenerateDelete}}
+	if err := mock.{{.Service}}().Delete(ctx, keyGA); err != nil {
+		t.Errorf("{{.Service}}().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+{{- end}}{{- end}}
+
+	// Delete not found.
+{{- if .HasAlpha}}{{- if .Alpha.GenerateDelete}}
+	if err := mock.Alpha{{.Service}}().Delete(ctx, keyAlpha); err == nil {
+		t.Errorf("Alpha{{.Service}}().Delete(%v, %v) = nil; want error", ctx, keyAlpha)
+	}
+{{- end}}{{- end}}
+{{- if .HasBeta}}{{- if .Beta.GenerateDelete}}
+	if err := mock.Beta{{.Service}}().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("Beta{{.Service}}().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
+{{- end}}{{- end}}
+{{- if .HasGA}}{{- if .GA.GenerateDelete}}
+	if err := mock.{{.Service}}().Delete(ctx, keyGA); err == nil {
+		t.Errorf("{{.Service}}().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+{{- end}}{{- end}}
+}
+`
+	tmpl := template.Must(template.New("unittest").Parse(text))
+	for _, sg := range meta.SortedServicesGroups {
+		if err := tmpl.Execute(wr, sg); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func genUnitTestResourceIDConversion(wr io.Writer) {
+	const text = `
+func TestResourceIDConversion(t *testing.T) {
+	t.Parallel()
+
+	for _, id := range []*ResourceID{
+		{{- range .Groups}}
+		{{- with .ServiceInfo}}
+		{{- if .KeyIsProject}}
+		New{{.Service}}ResourceID("my-{{.Resource}}-resource"),
+		{{- else}}
+		{{- if .KeyIsGlobal}}
+		New{{.Service}}ResourceID("some-project", "my-{{.Resource}}-resource"),
+		{{- end}}
+		{{- if .KeyIsRegional}}
+		New{{.Service}}ResourceID("some-project", "us-central1", "my-{{.Resource}}-resource"),
+		{{- end}}
+		{{- if .KeyIsZonal}}
+		New{{.Service}}ResourceID("some-project", "us-east1-b", "my-{{.Resource}}-resource"),
+		{{- end -}}
+		{{end -}}
+		{{end -}}
+		{{end}}
+	} {
+		t.Run(id.Resource, func(t *testing.T) {
+			// Test conversion to and from full URL.
+			fullURL := id.SelfLink(meta.VersionGA)
+			parsedID, err := ParseResourceURL(fullURL)
+			if err != nil {
+				t.Errorf("ParseResourceURL(%s) = _, %v, want nil", fullURL, err)
+			}
+			if !reflect.DeepEqual(id, parsedID) {
+				t.Errorf("SelfLink(%+v) -> ParseResourceURL(%s) = %+v, want original ID", id, fullURL, parsedID)
+			}
+
+			// Test conversion to and from relative resource name.
+			relativeName := id.RelativeResourceName()
+			parsedID, err = ParseResourceURL(relativeName)
+			if err != nil {
+				t.Errorf("ParseResourceURL(%s) = _, %v, want nil", relativeName, err)
+			}
+			if !reflect.DeepEqual(id, parsedID) {
+				t.Errorf("RelativeResourceName(%+v) -> ParseResourceURL(%s) = %+v, want original ID", id, relativeName, parsedID)
+			}
+
+			// Do not test ResourcePath for projects.
+			if id.Resource == "projects" {
+				return
+			}
 
 			// Test conversion to and from resource path.
 			resourcePath := id.ResourcePath()
@@ -1519,16 +2883,1326 @@ func TestResourceIDConversion(t *testing.T) {
 }
 `
 	data := struct {
-		Groups []*meta.ServiceGroup
-	}{meta.SortedServicesGroups}
-	tmpl := template.Must(template.New("unittest-resourceIDs").Parse(text))
+		Groups []*meta.ServiceGroup
+	}{meta.SortedServicesGroups}
+	tmpl := template.Must(template.New("unittest-resourceIDs").Parse(text))
+	if err := tmpl.Execute(wr, data); err != nil {
+		panic(err)
+	}
+}
+
+// grpcServiceInfo holds the names of the compute/apiv1 (protobuf) types used
+// to implement a GA service. The names are looked up in the proto registry
+// so that services and methods that do not exist in apiv1 are skipped.
+type grpcServiceInfo struct {
+	*meta.ServiceInfo
+
+	// ProtoObject is the name of the resource message (e.g. "Address").
+	ProtoObject string
+	// ToProto and FromProto are the converters of the object.
+	ToProto   string
+	FromProto string
+
+	GetRequest    string
+	GetNameField  string
+	ListRequest   string
+	InsertRequest string
+	// InsertObjectField is the field of InsertRequest holding the resource
+	// (e.g. "AddressResource").
+	InsertObjectField string
+	DeleteRequest     string
+	DeleteNameField   string
+
+	AggregatedListRequest string
+	// AggregatedListField is the field of the scoped list holding the
+	// objects (e.g. "Addresses").
+	AggregatedListField string
+	ListUsableRequest   string
+	ListUsableFromProto string
+
+	// Methods are the additional methods of the service that have an
+	// equivalent in apiv1.
+	Methods []*grpcMethod
+	// Unsupported are the methods of the service that cannot be implemented
+	// with the apiv1 client.
+	Unsupported []grpcUnsupported
+}
+
+// grpcMethod is an additional method (see meta.Method) implemented with the
+// apiv1 client.
+type grpcMethod struct {
+	*meta.Method
+	// GRPC is the service of the method.
+	GRPC *grpcServiceInfo
+
+	// Request is the name of the request message.
+	Request string
+	// NameField is the field of Request holding the name of the resource.
+	NameField string
+	// ArgField is the field of Request set to ArgExpr, the conversion of
+	// arg0. It is empty if the method does not take an argument.
+	ArgField  string
+	ArgExpr   string
+	RequestID bool
+	// ResultFromProto is the converter of the result (IsGet) or of the
+	// items (IsPaged).
+	ResultFromProto string
+}
+
+// grpcUnsupported is a method of the service that is not implemented by
+// the apiv1 client.
+type grpcUnsupported struct {
+	Service      string
+	GRPCWrapType string
+	Name         string
+	// Signature is the declaration of the method, without "func".
+	Signature string
+	// HasValue is true if the method returns a value before the error.
+	HasValue bool
+}
+
+// GRPCWrapType is the name of the apiv1 backed wrapper type.
+func (g *grpcServiceInfo) GRPCWrapType() string {
+	return "GRPC" + g.WrapType()
+}
+
+// LocationField is the name of the request field for the location of the
+// key.
+func (g *grpcServiceInfo) LocationField() string {
+	switch {
+	case g.KeyIsRegional():
+		return "Region"
+	case g.KeyIsZonal():
+		return "Zone"
+	}
+	return ""
+}
+
+// goCamelCase converts a proto field name to the Go field name generated
+// by protoc-gen-go (e.g. "address_resource" => "AddressResource").
+func goCamelCase(s string) string {
+	var parts []string
+	for _, p := range strings.Split(s, "_") {
+		if p == "" {
+			continue
+		}
+		parts = append(parts, strings.ToUpper(p[:1])+p[1:])
+	}
+	return strings.Join(parts, "")
+}
+
+// grpcRequest returns the Go name of the request message for the method and
+// the Go name of its field holding the resource name or resource message.
+// ok is false if the method does not exist or does not have the expected
+// fields for the key type of the service.
+func grpcRequest(sd protoreflect.ServiceDescriptor, s *meta.ServiceInfo, method string, wantMessage bool) (req, field string, ok bool) {
+	md := sd.Methods().ByName(protoreflect.Name(method))
+	if md == nil {
+		return "", "", false
+	}
+	locations := map[string]bool{"project": true}
+	switch {
+	case s.KeyIsRegional():
+		locations["region"] = true
+	case s.KeyIsZonal():
+		locations["zone"] = true
+	}
+	fields := md.Input().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		f := fields.Get(i)
+		name := string(f.Name())
+		switch {
+		case locations[name]:
+			delete(locations, name)
+		case (name == "region" || name == "zone") && s.Resource != name+"s":
+			// Location does not match the key type.
+			return "", "", false
+		case f.HasPresence() && f.Kind() != protoreflect.MessageKind:
+			// Optional parameter, e.g. request_id or filter.
+		case wantMessage && f.Kind() == protoreflect.MessageKind:
+			field = goCamelCase(name)
+		case !wantMessage && f.Kind() == protoreflect.StringKind && field == "":
+			field = goCamelCase(name)
+		default:
+			return "", "", false
+		}
+	}
+	if len(locations) != 0 {
+		return "", "", false
+	}
+	if method == "List" {
+		return string(md.Input().Name()), "", true
+	}
+	return string(md.Input().Name()), field, field != ""
+}
+
+// grpcListRequest returns the request message of the project level list
+// method (AggregatedList, ListUsable) and the items of the result. ok is
+// false if the method does not have this form.
+func grpcListRequest(sd protoreflect.ServiceDescriptor, method string) (req string, items protoreflect.FieldDescriptor, ok bool) {
+	md := sd.Methods().ByName(protoreflect.Name(method))
+	if md == nil {
+		return "", nil, false
+	}
+	fields := md.Input().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		f := fields.Get(i)
+		if f.Name() != "project" && !(f.HasPresence() && f.Kind() != protoreflect.MessageKind) {
+			return "", nil, false
+		}
+	}
+	items = pagedItems(md)
+	if items == nil {
+		return "", nil, false
+	}
+	return string(md.Input().Name()), items, true
+}
+
+// pagedItems returns the field of the result of md that holds the items if
+// the apiv1 client returns an iterator for the method. This is the case if
+// the request has a page_token and the response a next_page_token and
+// either an "items" field or a single repeated message field.
+func pagedItems(md protoreflect.MethodDescriptor) protoreflect.FieldDescriptor {
+	if md.Input().Fields().ByName("page_token") == nil || md.Output().Fields().ByName("next_page_token") == nil {
+		return nil
+	}
+	if f := md.Output().Fields().ByName("items"); f != nil && (f.IsList() || f.IsMap()) {
+		return f
+	}
+	var ret protoreflect.FieldDescriptor
+	fields := md.Output().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if f := fields.Get(i); f.IsList() && f.Kind() == protoreflect.MessageKind {
+			if ret != nil {
+				return nil
+			}
+			ret = f
+		}
+	}
+	return ret
+}
+
+// newGRPCMethod returns the apiv1 implementation of the method m. ok is false
+// if the method cannot be implemented with the apiv1 client.
+func newGRPCMethod(g *grpcServiceInfo, sd protoreflect.ServiceDescriptor, m *meta.Method, conv *protoConverters) (*grpcMethod, bool) {
+	md := sd.Methods().ByName(protoreflect.Name(m.Name()))
+	if md == nil {
+		return nil, false
+	}
+	ret := &grpcMethod{Method: m, GRPC: g, Request: string(md.Input().Name())}
+
+	locations := map[string]bool{"project": true}
+	if l := g.LocationField(); l != "" {
+		locations[strings.ToLower(l)] = true
+	}
+	args := m.ArgTypes()
+	if len(args) > 1 {
+		return nil, false
+	}
+	// Required strings and numbers other than the project and location: the
+	// name of the resource and possibly arg0.
+	var params []protoreflect.FieldDescriptor
+	fields := md.Input().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		f := fields.Get(i)
+		name := string(f.Name())
+		switch {
+		case locations[name]:
+			delete(locations, name)
+		case (name == "region" || name == "zone") && g.Resource != name+"s":
+			return nil, false
+		case name == "request_id":
+			ret.RequestID = true
+		case f.Kind() == protoreflect.MessageKind:
+			if len(args) != 1 || ret.ArgField != "" || args[0].Kind() != reflect.Ptr {
+				return nil, false
+			}
+			toProto, _, ok := conv.converters(args[0].Elem(), protoGoType(f.Message().FullName()))
+			if !ok {
+				return nil, false
+			}
+			ret.ArgField, ret.ArgExpr = goCamelCase(name), toProto+"(arg0)"
+		case f.HasPresence():
+			// Optional parameter, e.g. filter.
+		case f.Cardinality() == protoreflect.Repeated:
+			return nil, false
+		default:
+			params = append(params, f)
+		}
+	}
+	if len(locations) != 0 {
+		return nil, false
+	}
+
+	// The resource is named by the same field as in Get, by "resource"
+	// (e.g. SetLabels) or by the only parameter (e.g. "family" in
+	// GetFromFamily).
+	for i, f := range params {
+		if name := goCamelCase(string(f.Name())); f.Kind() == protoreflect.StringKind && (name == g.GetNameField || name == "Resource") {
+			ret.NameField = name
+			params = append(params[:i], params[i+1:]...)
+			break
+		}
+	}
+	if ret.NameField == "" && len(params) == len(args)+1 && params[0].Kind() == protoreflect.StringKind {
+		ret.NameField = goCamelCase(string(params[0].Name()))
+		params = params[1:]
+	}
+	if ret.NameField == "" {
+		return nil, false
+	}
+	switch {
+	case len(args) == 0 || ret.ArgField != "":
+		if len(params) != 0 {
+			return nil, false
+		}
+	case len(params) == 1:
+		// arg0 is a string or a number.
+		name := goCamelCase(string(params[0].Name()))
+		f, ok := protoGoType(md.Input().FullName()).FieldByName(name)
+		if !ok {
+			return nil, false
+		}
+		v, ok := scalarConv("arg0", args[0].Kind(), f.Type.Kind())
+		if !ok {
+			return nil, false
+		}
+		ret.ArgField, ret.ArgExpr = name, v
+	default:
+		return nil, false
+	}
+
+	items := pagedItems(md)
+	switch {
+	case m.IsOperation():
+		if md.Output().Name() != "Operation" {
+			return nil, false
+		}
+	case m.IsGet():
+		if items != nil {
+			return nil, false
+		}
+		_, fromProto, ok := conv.converters(m.ResultGoType(), protoGoType(md.Output().FullName()))
+		if !ok {
+			return nil, false
+		}
+		ret.ResultFromProto = fromProto
+	case m.IsPaged():
+		if items == nil || !items.IsList() || items.Kind() != protoreflect.MessageKind || md.Input().Fields().ByName("filter") == nil {
+			return nil, false
+		}
+		_, fromProto, ok := conv.converters(m.ResultGoType(), protoGoType(items.Message().FullName()))
+		if !ok {
+			return nil, false
+		}
+		ret.ResultFromProto = fromProto
+	}
+	return ret, true
+}
+
+// protoGoType returns the Go struct type of the message name. It returns
+// nil if the message is not registered.
+func protoGoType(name protoreflect.FullName) reflect.Type {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(name)
+	if err != nil {
+		return nil
+	}
+	return reflect.TypeOf(mt.Zero().Interface()).Elem()
+}
+
+// grpcServices returns the GA services that can be implemented with the
+// compute/apiv1 clients.
+func grpcServices(conv *protoConverters) []*grpcServiceInfo {
+	var ret []*grpcServiceInfo
+	for _, s := range meta.AllServices {
+		if s.Version() != meta.VersionGA || s.KeyIsProject() {
+			continue
+		}
+		d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(computeProtoPackage + "." + s.Service))
+		if err != nil {
+			continue
+		}
+		sd, ok := d.(protoreflect.ServiceDescriptor)
+		if !ok {
+			continue
+		}
+		g := &grpcServiceInfo{ServiceInfo: s}
+		md := sd.Methods().ByName("Get")
+		if md == nil || s.ObjectGoType() == nil {
+			continue
+		}
+		g.ProtoObject = string(md.Output().Name())
+		if g.ToProto, g.FromProto, ok = conv.converters(s.ObjectGoType(), protoGoType(md.Output().FullName())); !ok {
+			continue
+		}
+		if s.GenerateGet() {
+			g.GetRequest, g.GetNameField, _ = grpcRequest(sd, s, "Get", false)
+		}
+		if s.GenerateList() {
+			g.ListRequest, _, _ = grpcRequest(sd, s, "List", false)
+		}
+		if s.GenerateInsert() {
+			g.InsertRequest, g.InsertObjectField, _ = grpcRequest(sd, s, "Insert", true)
+		}
+		if s.GenerateDelete() {
+			g.DeleteRequest, g.DeleteNameField, _ = grpcRequest(sd, s, "Delete", false)
+		}
+		if s.AggregatedList() {
+			if req, items, ok := grpcListRequest(sd, "AggregatedList"); ok && items.IsMap() && items.MapValue().Kind() == protoreflect.MessageKind {
+				if f := items.MapValue().Message().Fields().ByJSONName(lowerFirst(s.AggregatedListField())); f != nil && f.IsList() && f.Message() != nil && f.Message().Name() == md.Output().Name() {
+					g.AggregatedListRequest, g.AggregatedListField = req, goCamelCase(string(f.Name()))
+				}
+			}
+		}
+		if s.ListUsable() {
+			if req, items, ok := grpcListRequest(sd, "ListUsable"); ok && items.IsList() && items.Kind() == protoreflect.MessageKind && s.ListUsableGoType() != nil {
+				if _, fromProto, ok := conv.converters(s.ListUsableGoType(), protoGoType(items.Message().FullName())); ok {
+					g.ListUsableRequest, g.ListUsableFromProto = req, fromProto
+				}
+			}
+		}
+		if g.GetRequest == "" && g.ListRequest == "" && g.InsertRequest == "" && g.DeleteRequest == "" {
+			continue
+		}
+
+		// Verbs of the service interface that cannot be implemented with
+		// apiv1 return an error.
+		unsupported := func(name, signature string, hasValue bool) {
+			g.Unsupported = append(g.Unsupported, grpcUnsupported{
+				Service:      s.Service,
+				GRPCWrapType: g.GRPCWrapType(),
+				Name:         name,
+				Signature:    signature,
+				HasValue:     hasValue,
+			})
+		}
+		if s.GenerateGet() && g.GetRequest == "" {
+			unsupported("Get", fmt.Sprintf("Get(ctx context.Context, key *meta.Key) (*%v, error)", s.FQObjectType()), true)
+		}
+		if s.GenerateList() && g.ListRequest == "" {
+			switch {
+			case s.KeyIsGlobal():
+				unsupported("List", fmt.Sprintf("List(ctx context.Context, fl *filter.F) ([]*%v, error)", s.FQObjectType()), true)
+			case s.KeyIsRegional():
+				unsupported("List", fmt.Sprintf("List(ctx context.Context, region string, fl *filter.F) ([]*%v, error)", s.FQObjectType()), true)
+			case s.KeyIsZonal():
+				unsupported("List", fmt.Sprintf("List(ctx context.Context, zone string, fl *filter.F) ([]*%v, error)", s.FQObjectType()), true)
+			}
+		}
+		if s.GenerateInsert() && g.InsertRequest == "" {
+			unsupported("Insert", fmt.Sprintf("Insert(ctx context.Context, key *meta.Key, obj *%v) error", s.FQObjectType()), false)
+		}
+		if s.GenerateDelete() && g.DeleteRequest == "" {
+			unsupported("Delete", "Delete(ctx context.Context, key *meta.Key) error", false)
+		}
+		if s.AggregatedList() && g.AggregatedListRequest == "" {
+			unsupported("AggregatedList", fmt.Sprintf("AggregatedList(ctx context.Context, fl *filter.F) (map[string][]*%v, error)", s.FQObjectType()), true)
+		}
+		if s.ListUsable() && g.ListUsableRequest == "" {
+			unsupported("ListUsable", fmt.Sprintf("ListUsable(ctx context.Context, fl *filter.F) ([]*%v, error)", s.FQListUsableObjectType()), true)
+		}
+		for _, m := range s.Methods() {
+			if gm, ok := newGRPCMethod(g, sd, m, conv); ok {
+				g.Methods = append(g.Methods, gm)
+				continue
+			}
+			unsupported(m.Name(), m.FcnArgs(), !m.IsOperation())
+		}
+		ret = append(ret, g)
+	}
+	return ret
+}
+
+// lowerFirst lower cases the first letter of s.
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// protoConverters generates the functions that convert between the
+// compute/v1 structs and the compute/apiv1 (computepb) messages. Fields are
+// matched by their JSON name. Fields that are only in one of the types or
+// whose types do not correspond are not converted.
+type protoConverters struct {
+	names map[[2]reflect.Type]string
+	used  map[string]bool
+	queue [][2]reflect.Type
+}
+
+func newProtoConverters() *protoConverters {
+	return &protoConverters{names: map[[2]reflect.Type]string{}, used: map[string]bool{}}
+}
+
+// converters returns the names of the functions converting the compute/v1
+// struct type obj to the computepb message type pb and back. The functions
+// are generated by gen(). ok is false if the types cannot be converted.
+func (c *protoConverters) converters(obj, pb reflect.Type) (toProto, fromProto string, ok bool) {
+	if obj == nil || pb == nil || obj.Kind() != reflect.Struct || pb.Kind() != reflect.Struct || obj.PkgPath() != gaComputePackage {
+		return "", "", false
+	}
+	if _, ok := reflect.New(pb).Interface().(protoreflect.ProtoMessage); !ok {
+		return "", "", false
+	}
+	k := [2]reflect.Type{obj, pb}
+	name, ok := c.names[k]
+	if !ok {
+		name = lowerFirst(obj.Name())
+		if c.used[name] {
+			name += pb.Name()
+		}
+		c.names[k] = name
+		c.used[name] = true
+		c.queue = append(c.queue, k)
+	}
+	return name + "ToProto", name + "FromProto", true
+}
+
+// gen writes the functions for all of the types returned by converters().
+func (c *protoConverters) gen(wr io.Writer) {
+	for i := 0; i < len(c.queue); i++ {
+		c.genPair(wr, c.queue[i][0], c.queue[i][1])
+	}
+}
+
+func (c *protoConverters) genPair(wr io.Writer, obj, pb reflect.Type) {
+	toProto, fromProto, _ := c.converters(obj, pb)
+
+	// Go fields of the message by JSON name.
+	md := reflect.New(pb).Interface().(protoreflect.ProtoMessage).ProtoReflect().Descriptor()
+	pbFields := map[string]reflect.StructField{}
+	for i := 0; i < pb.NumField(); i++ {
+		f := pb.Field(i)
+		for _, opt := range strings.Split(f.Tag.Get("protobuf"), ",") {
+			if name := strings.TrimPrefix(opt, "name="); name != opt {
+				if fd := md.Fields().ByName(protoreflect.Name(name)); fd != nil {
+					pbFields[fd.JSONName()] = f
+				}
+			}
+		}
+	}
+	_, force := obj.FieldByName("ForceSendFields")
+
+	var to, from bytes.Buffer
+	for i := 0; i < obj.NumField(); i++ {
+		f := obj.Field(i)
+		jsonName := strings.Split(f.Tag.Get("json"), ",")[0]
+		if jsonName == "" || jsonName == "-" {
+			continue
+		}
+		pf, ok := pbFields[jsonName]
+		if !ok {
+			continue
+		}
+		c.genField(&to, &from, f, pf, force)
+	}
+
+	fmt.Fprintf(wr, `
+// %[1]v converts the compute/v1 %[3]v to computepb.
+func %[1]v(src *ga.%[3]v) *computepb.%[4]v {
+	if src == nil {
+		return nil
+	}
+	dst := &computepb.%[4]v{}
+%[5]v	return dst
+}
+
+// %[2]v converts the computepb %[4]v to compute/v1.
+func %[2]v(src *computepb.%[4]v) *ga.%[3]v {
+	if src == nil {
+		return nil
+	}
+	dst := &ga.%[3]v{}
+%[6]v	return dst
+}
+`, toProto, fromProto, obj.Name(), pb.Name(), to.String(), from.String())
+}
+
+// protoScalar are the names of the functions of the proto package that
+// return a pointer to a value of the kind.
+var protoScalar = map[reflect.Kind]string{
+	reflect.Bool:    "Bool",
+	reflect.Int32:   "Int32",
+	reflect.Int64:   "Int64",
+	reflect.Uint32:  "Uint32",
+	reflect.Uint64:  "Uint64",
+	reflect.Float32: "Float32",
+	reflect.Float64: "Float64",
+	reflect.String:  "String",
+}
+
+// scalarConv returns the conversion of the expression v from the kind from to
+// the kind to. ok is false if the kinds are not compatible.
+func scalarConv(v string, from, to reflect.Kind) (string, bool) {
+	isNumber := func(k reflect.Kind) bool {
+		return k >= reflect.Int && k <= reflect.Float64
+	}
+	switch {
+	case from == to:
+		return v, true
+	case isNumber(from) && isNumber(to):
+		return fmt.Sprintf("%v(%v)", to, v), true
+	}
+	return "", false
+}
+
+// genField writes the statements converting the field f of the compute/v1
+// struct to the field pf of the message to to, and back to from. force is
+// true if the struct has ForceSendFields. Fields of types that do not
+// correspond are skipped.
+func (c *protoConverters) genField(to, from io.Writer, f, pf reflect.StructField, force bool) {
+	ft, pt := f.Type, pf.Type
+	switch {
+	case pt.Kind() == reflect.Ptr && pt.Elem().Kind() == reflect.Struct:
+		// Message.
+		if ft.Kind() != reflect.Ptr {
+			return
+		}
+		toProto, fromProto, ok := c.converters(ft.Elem(), pt.Elem())
+		if !ok {
+			return
+		}
+		fmt.Fprintf(to, "\tdst.%v = %v(src.%v)\n", pf.Name, toProto, f.Name)
+		fmt.Fprintf(from, "\tdst.%v = %v(src.%v)\n", f.Name, fromProto, pf.Name)
+
+	case pt.Kind() == reflect.Ptr:
+		// Optional scalar.
+		toV, ok := scalarConv("src."+f.Name, ft.Kind(), pt.Elem().Kind())
+		if !ok || protoScalar[pt.Elem().Kind()] == "" {
+			return
+		}
+		fromV, _ := scalarConv("*src."+pf.Name, pt.Elem().Kind(), ft.Kind())
+		cond := "src." + f.Name
+		switch ft.Kind() {
+		case reflect.String:
+			cond += ` != ""`
+		case reflect.Bool:
+		default:
+			cond += " != 0"
+		}
+		if force {
+			cond += fmt.Sprintf(" || forceSendField(src.ForceSendFields, %q)", f.Name)
+		}
+		fmt.Fprintf(to, "\tif %v {\n\t\tdst.%v = proto.%v(%v)\n\t}\n", cond, pf.Name, protoScalar[pt.Elem().Kind()], toV)
+		fmt.Fprintf(from, "\tif src.%v != nil {\n\t\tdst.%v = %v\n\t}\n", pf.Name, f.Name, fromV)
+
+	case pt.Kind() == reflect.Slice && pt.Elem().Kind() == reflect.Ptr:
+		// Repeated message.
+		if ft.Kind() != reflect.Slice || ft.Elem().Kind() != reflect.Ptr {
+			return
+		}
+		toProto, fromProto, ok := c.converters(ft.Elem().Elem(), pt.Elem().Elem())
+		if !ok {
+			return
+		}
+		fmt.Fprintf(to, "\tfor _, v := range src.%v {\n\t\tdst.%v = append(dst.%v, %v(v))\n\t}\n", f.Name, pf.Name, pf.Name, toProto)
+		fmt.Fprintf(from, "\tfor _, v := range src.%v {\n\t\tdst.%v = append(dst.%v, %v(v))\n\t}\n", pf.Name, f.Name, f.Name, fromProto)
+
+	case pt.Kind() == reflect.Slice:
+		// Repeated scalar.
+		if ft.Kind() != reflect.Slice {
+			return
+		}
+		toV, ok := scalarConv("v", ft.Elem().Kind(), pt.Elem().Kind())
+		if !ok {
+			return
+		}
+		if ft.Elem().Kind() == pt.Elem().Kind() {
+			fmt.Fprintf(to, "\tdst.%v = append(dst.%v, src.%v...)\n", pf.Name, pf.Name, f.Name)
+			fmt.Fprintf(from, "\tdst.%v = append(dst.%v, src.%v...)\n", f.Name, f.Name, pf.Name)
+			return
+		}
+		fromV, _ := scalarConv("v", pt.Elem().Kind(), ft.Elem().Kind())
+		fmt.Fprintf(to, "\tfor _, v := range src.%v {\n\t\tdst.%v = append(dst.%v, %v)\n\t}\n", f.Name, pf.Name, pf.Name, toV)
+		fmt.Fprintf(from, "\tfor _, v := range src.%v {\n\t\tdst.%v = append(dst.%v, %v)\n\t}\n", pf.Name, f.Name, f.Name, fromV)
+
+	case pt.Kind() == reflect.Map && pt.Elem().Kind() == reflect.Ptr:
+		// Map of messages. The compute/v1 values may be structs or
+		// pointers to structs.
+		if ft.Kind() != reflect.Map || ft.Key().Kind() != reflect.String || pt.Key().Kind() != reflect.String {
+			return
+		}
+		vt := ft.Elem()
+		if vt.Kind() == reflect.Ptr {
+			vt = vt.Elem()
+		}
+		toProto, fromProto, ok := c.converters(vt, pt.Elem().Elem())
+		if !ok {
+			return
+		}
+		fmt.Fprintf(to, "\tif src.%v != nil {\n\t\tdst.%v = map[string]*computepb.%v{}\n\t}\n", f.Name, pf.Name, pt.Elem().Elem().Name())
+		fmt.Fprintf(from, "\tif src.%v != nil {\n\t\tdst.%v = map[string]%vga.%v{}\n\t}\n", pf.Name, f.Name, map[bool]string{true: "*"}[ft.Elem().Kind() == reflect.Ptr], vt.Name())
+		if ft.Elem().Kind() == reflect.Ptr {
+			fmt.Fprintf(to, "\tfor k, v := range src.%v {\n\t\tdst.%v[k] = %v(v)\n\t}\n", f.Name, pf.Name, toProto)
+			fmt.Fprintf(from, "\tfor k, v := range src.%v {\n\t\tdst.%v[k] = %v(v)\n\t}\n", pf.Name, f.Name, fromProto)
+		} else {
+			fmt.Fprintf(to, "\tfor k, v := range src.%v {\n\t\tv := v\n\t\tdst.%v[k] = %v(&v)\n\t}\n", f.Name, pf.Name, toProto)
+			fmt.Fprintf(from, "\tfor k, v := range src.%v {\n\t\tif v := %v(v); v != nil {\n\t\t\tdst.%v[k] = *v\n\t\t}\n\t}\n", pf.Name, fromProto, f.Name)
+		}
+
+	case pt.Kind() == reflect.Map:
+		// Map of scalars.
+		if ft.Kind() != reflect.Map || ft.Key().Kind() != reflect.String || pt.Key().Kind() != reflect.String {
+			return
+		}
+		toV, ok := scalarConv("v", ft.Elem().Kind(), pt.Elem().Kind())
+		if !ok {
+			return
+		}
+		fromV, _ := scalarConv("v", pt.Elem().Kind(), ft.Elem().Kind())
+		fmt.Fprintf(to, "\tif src.%v != nil {\n\t\tdst.%v = map[string]%v{}\n\t}\n", f.Name, pf.Name, pt.Elem().Kind())
+		fmt.Fprintf(to, "\tfor k, v := range src.%v {\n\t\tdst.%v[k] = %v\n\t}\n", f.Name, pf.Name, toV)
+		fmt.Fprintf(from, "\tif src.%v != nil {\n\t\tdst.%v = map[string]%v{}\n\t}\n", pf.Name, f.Name, ft.Elem().Kind())
+		fmt.Fprintf(from, "\tfor k, v := range src.%v {\n\t\tdst.%v[k] = %v\n\t}\n", pf.Name, f.Name, fromV)
+
+	default:
+		// Required scalar.
+		toV, ok := scalarConv("src."+f.Name, ft.Kind(), pt.Kind())
+		if !ok || protoScalar[pt.Kind()] == "" {
+			return
+		}
+		fromV, _ := scalarConv("src."+pf.Name, pt.Kind(), ft.Kind())
+		fmt.Fprintf(to, "\tdst.%v = %v\n", pf.Name, toV)
+		fmt.Fprintf(from, "\tdst.%v = %v\n", f.Name, fromV)
+	}
+}
+
+// genGRPC generates the compute/apiv1 backed implementation of Cloud.
+func genGRPC(wr io.Writer) {
+	conv := newProtoConverters()
+	// The Operation is converted for the errors of the operations (see
+	// waitGRPCOperation).
+	conv.converters(reflect.TypeOf(ga.Operation{}), protoGoType(computeProtoPackage+".Operation"))
+	const text = `/*
+Copyright {{.Year}} Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file was generated by "go run gen/main.go -mode grpc > gen_grpc.go". Do not edit
+// directly.
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"cloud.google.com/go/compute/apiv1/computepb"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/klog/v2"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+{{- range .Services}}
+// {{.GRPCWrapType}} implements {{.Service}} using compute.{{.Service}}Client.
+type {{.GRPCWrapType}} struct {
+	s *Service
+	c *compute.{{.Service}}Client
+}
+
+{{- if .GetRequest}}
+
+// Get the {{.Object}} named by key.
+func (g *{{.GRPCWrapType}}) Get(ctx context.Context, key *meta.Key) (*{{.FQObjectType}}, error) {
+	key = normalizeKey(key)
+	return intercept(ctx, g.s, newCallInfo("{{.Service}}", "Get", meta.Version("{{.Version}}"), key), func(ctx context.Context) (*{{.FQObjectType}}, error) {
+	klog.V(5).Infof("{{.GRPCWrapType}}.Get(%v, %v): called", ctx, key)
+
+	if !key.Valid() {
+		klog.V(2).Infof("{{.GRPCWrapType}}.Get(%v, %v): key is invalid (%#v)", ctx, key, key)
+		return nil, fmt.Errorf("invalid GCE key (%#v)", key)
+	}
+	projectID := g.s.ProjectRouter.ProjectID(ctx, "{{.Version}}", "{{.Service}}")
+	ck := &CallContextKey{
+		ProjectID: projectID,
+		Operation: "Get",
+		Version:   meta.Version("{{.Version}}"),
+		Service:   "{{.Service}}",
+	}
+
+	klog.V(5).Infof("{{.GRPCWrapType}}.Get(%v, %v): projectID = %v, ck = %+v", ctx, key, projectID, ck)
+	ctx = g.s.callStart(ctx, ck, key)
+	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		klog.V(4).Infof("{{.GRPCWrapType}}.Get(%v, %v): RateLimiter error: %v", ctx, key, err)
+		callEnd(ctx, ck, err)
+		return nil, err
+	}
+	callSend(ctx, nil)
+
+	req := &computepb.{{.GetRequest}}{
+		Project: projectID,
+{{- if .LocationField}}
+		{{.LocationField}}: key.{{.LocationField}},
+{{- end}}
+		{{.GetNameField}}: key.Name,
+	}
+	pb, err := g.c.Get(ctx, req)
+	v := {{.FromProto}}(pb)
+	klog.V(4).Infof("{{.GRPCWrapType}}.Get(%v, %v) = %+v, %v", ctx, key, v, err)
+
+	callEnd(ctx, ck, err)
+	g.s.RateLimiter.Observe(ctx, err, ck)
+
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+	})
+}
+{{- end}}
+
+{{- if .ListRequest}}
+
+// List all {{.Object}} objects.
+{{- if .KeyIsGlobal}}
+func (g *{{.GRPCWrapType}}) List(ctx context.Context, fl *filter.F) ([]*{{.FQObjectType}}, error) {
+	return intercept(ctx, g.s, newCallInfo("{{.Service}}", "List", meta.Version("{{.Version}}"), nil, fl), func(ctx context.Context) ([]*{{.FQObjectType}}, error) {
+	klog.V(5).Infof("{{.GRPCWrapType}}.List(%v, %v) called", ctx, fl)
+{{- end -}}
+{{- if .KeyIsRegional}}
+func (g *{{.GRPCWrapType}}) List(ctx context.Context, region string, fl *filter.F) ([]*{{.FQObjectType}}, error) {
+	region = meta.NormalizeLocation(region)
+	return intercept(ctx, g.s, newCallInfo("{{.Service}}", "List", meta.Version("{{.Version}}"), nil, region, fl), func(ctx context.Context) ([]*{{.FQObjectType}}, error) {
+	klog.V(5).Infof("{{.GRPCWrapType}}.List(%v, %v, %v) called", ctx, region, fl)
+{{- end -}}
+{{- if .KeyIsZonal}}
+func (g *{{.GRPCWrapType}}) List(ctx context.Context, zone string, fl *filter.F) ([]*{{.FQObjectType}}, error) {
+	zone = meta.NormalizeLocation(zone)
+	return intercept(ctx, g.s, newCallInfo("{{.Service}}", "List", meta.Version("{{.Version}}"), nil, zone, fl), func(ctx context.Context) ([]*{{.FQObjectType}}, error) {
+	klog.V(5).Infof("{{.GRPCWrapType}}.List(%v, %v, %v) called", ctx, zone, fl)
+{{- end}}
+	projectID := g.s.ProjectRouter.ProjectID(ctx, "{{.Version}}", "{{.Service}}")
+	ck := &CallContextKey{
+		ProjectID: projectID,
+		Operation: "List",
+		Version:   meta.Version("{{.Version}}"),
+		Service:   "{{.Service}}",
+	}
+
+	ctx = g.s.callStart(ctx, ck, nil)
+	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		callEnd(ctx, ck, err)
+		return nil, err
+	}
+	callSend(ctx, nil)
+
+	req := &computepb.{{.ListRequest}}{
+		Project: projectID,
+{{- if .KeyIsRegional}}
+		Region: region,
+{{- end}}
+{{- if .KeyIsZonal}}
+		Zone: zone,
+{{- end}}
+	}
+	if fl != filter.None {
+		req.Filter = proto.String(fl.String())
+	}
+	var all []*{{.FQObjectType}}
+	it := g.c.List(ctx, req)
+	for {
+		pb, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			callEnd(ctx, ck, err)
+			g.s.RateLimiter.Observe(ctx, err, ck)
+
+			klog.V(4).Infof("{{.GRPCWrapType}}.List(%v, ..., %v) = %v, %v", ctx, fl, nil, err)
+			return nil, err
+		}
+		all = append(all, {{.FromProto}}(pb))
+	}
+
+	callEnd(ctx, ck, nil)
+	g.s.RateLimiter.Observe(ctx, nil, ck)
+
+	klog.V(4).Infof("{{.GRPCWrapType}}.List(%v, ..., %v) = [%v items], %v", ctx, fl, len(all), nil)
+	return all, nil
+	})
+}
+{{- end}}
+
+{{- if .InsertRequest}}
+
+// Insert {{.Object}} with key of value obj.
+func (g *{{.GRPCWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.FQObjectType}}) error {
+	key = normalizeKey(key)
+	return interceptErr(ctx, g.s, newCallInfo("{{.Service}}", "Insert", meta.Version("{{.Version}}"), key, obj), func(ctx context.Context) error {
+	klog.V(5).Infof("{{.GRPCWrapType}}.Insert(%v, %v, %+v): called", ctx, key, obj)
+	if !key.Valid() {
+		klog.V(2).Infof("{{.GRPCWrapType}}.Insert(%v, %v, ...): key is invalid (%#v)", ctx, key, key)
+		return fmt.Errorf("invalid GCE key (%+v)", key)
+	}
+	projectID := g.s.ProjectRouter.ProjectID(ctx, "{{.Version}}", "{{.Service}}")
+	ck := &CallContextKey{
+		ProjectID: projectID,
+		Operation: "Insert",
+		Version:   meta.Version("{{.Version}}"),
+		Service:   "{{.Service}}",
+	}
+
+	klog.V(5).Infof("{{.GRPCWrapType}}.Insert(%v, %v, ...): projectID = %v, ck = %+v", ctx, key, projectID, ck)
+	ctx = g.s.callStart(ctx, ck, key)
+	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		klog.V(4).Infof("{{.GRPCWrapType}}.Insert(%v, %v, ...): RateLimiter error: %v", ctx, key, err)
+		callEnd(ctx, ck, err)
+		return err
+	}
+	callSend(ctx, nil)
+	obj.Name = key.Name
+
+	req := &computepb.{{.InsertRequest}}{
+		Project: projectID,
+{{- if .LocationField}}
+		{{.LocationField}}: key.{{.LocationField}},
+{{- end}}
+		{{.InsertObjectField}}: {{.ToProto}}(obj),
+		RequestId: proto.String(callRequestID(ctx)),
+	}
+	op, err := g.c.Insert(ctx, req)
+	callSetOperation(ctx, op)
+
+	callEnd(ctx, ck, err)
+	g.s.RateLimiter.Observe(ctx, err, ck)
+
+	if err != nil {
+		klog.V(4).Infof("{{.GRPCWrapType}}.Insert(%v, %v, ...) = %+v", ctx, key, err)
+		return err
+	}
+
+	err = waitGRPCOperation(ctx, op)
+	klog.V(4).Infof("{{.GRPCWrapType}}.Insert(%v, %v, %+v) = %+v", ctx, key, obj, err)
+	return err
+	})
+}
+{{- end}}
+
+{{- if .DeleteRequest}}
+
+// Delete the {{.Object}} referenced by key.
+func (g *{{.GRPCWrapType}}) Delete(ctx context.Context, key *meta.Key) error {
+	key = normalizeKey(key)
+	return interceptErr(ctx, g.s, newCallInfo("{{.Service}}", "Delete", meta.Version("{{.Version}}"), key), func(ctx context.Context) error {
+	klog.V(5).Infof("{{.GRPCWrapType}}.Delete(%v, %v): called", ctx, key)
+	if !key.Valid() {
+		klog.V(2).Infof("{{.GRPCWrapType}}.Delete(%v, %v): key is invalid (%#v)", ctx, key, key)
+		return fmt.Errorf("invalid GCE key (%+v)", key)
+	}
+	projectID := g.s.ProjectRouter.ProjectID(ctx, "{{.Version}}", "{{.Service}}")
+	ck := &CallContextKey{
+		ProjectID: projectID,
+		Operation: "Delete",
+		Version:   meta.Version("{{.Version}}"),
+		Service:   "{{.Service}}",
+	}
+	klog.V(5).Infof("{{.GRPCWrapType}}.Delete(%v, %v): projectID = %v, ck = %+v", ctx, key, projectID, ck)
+	ctx = g.s.callStart(ctx, ck, key)
+	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		klog.V(4).Infof("{{.GRPCWrapType}}.Delete(%v, %v): RateLimiter error: %v", ctx, key, err)
+		callEnd(ctx, ck, err)
+		return err
+	}
+	callSend(ctx, nil)
+
+	req := &computepb.{{.DeleteRequest}}{
+		Project: projectID,
+{{- if .LocationField}}
+		{{.LocationField}}: key.{{.LocationField}},
+{{- end}}
+		{{.DeleteNameField}}: key.Name,
+		RequestId: proto.String(callRequestID(ctx)),
+	}
+	op, err := g.c.Delete(ctx, req)
+	callSetOperation(ctx, op)
+
+	callEnd(ctx, ck, err)
+	g.s.RateLimiter.Observe(ctx, err, ck)
+
+	if err != nil {
+		klog.V(4).Infof("{{.GRPCWrapType}}.Delete(%v, %v) = %v", ctx, key, err)
+		return err
+	}
+
+	err = waitGRPCOperation(ctx, op)
+	klog.V(4).Infof("{{.GRPCWrapType}}.Delete(%v, %v) = %v", ctx, key, err)
+	return err
+	})
+}
+{{- end}}
+
+{{- if .AggregatedListRequest}}
+
+// AggregatedList lists all {{.Object}} objects across all scopes.
+func (g *{{.GRPCWrapType}}) AggregatedList(ctx context.Context, fl *filter.F) (map[string][]*{{.FQObjectType}}, error) {
+	return intercept(ctx, g.s, newCallInfo("{{.Service}}", "AggregatedList", meta.Version("{{.Version}}"), nil, fl), func(ctx context.Context) (map[string][]*{{.FQObjectType}}, error) {
+	klog.V(5).Infof("{{.GRPCWrapType}}.AggregatedList(%v, %v) called", ctx, fl)
+
+	projectID := g.s.ProjectRouter.ProjectID(ctx, "{{.Version}}", "{{.Service}}")
+	ck := &CallContextKey{
+		ProjectID: projectID,
+		Operation: "AggregatedList",
+		Version:   meta.Version("{{.Version}}"),
+		Service:   "{{.Service}}",
+	}
+
+	ctx = g.s.callStart(ctx, ck, nil)
+	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		callEnd(ctx, ck, err)
+		return nil, err
+	}
+	callSend(ctx, nil)
+
+	req := &computepb.{{.AggregatedListRequest}}{
+		Project: projectID,
+	}
+	if fl != filter.None {
+		req.Filter = proto.String(fl.String())
+	}
+	all := map[string][]*{{.FQObjectType}}{}
+	it := g.c.AggregatedList(ctx, req)
+	for {
+		pair, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			callEnd(ctx, ck, err)
+			g.s.RateLimiter.Observe(ctx, err, ck)
+
+			klog.V(4).Infof("{{.GRPCWrapType}}.AggregatedList(%v, %v) = %v, %v", ctx, fl, nil, err)
+			return nil, err
+		}
+		// Scopes without any objects have a NO_RESULTS_ON_PAGE warning.
+		// Other warnings (e.g. an unreachable scope) mean that the
+		// result may be incomplete.
+		if w := pair.Value.GetWarning(); w != nil && w.GetCode() != "NO_RESULTS_ON_PAGE" {
+			klog.V(2).Infof("{{.GRPCWrapType}}.AggregatedList(%v, %v): warning for scope %v: %v: %v", ctx, fl, pair.Key, w.GetCode(), w.GetMessage())
+		}
+		for _, pb := range pair.Value.Get{{.AggregatedListField}}() {
+			all[pair.Key] = append(all[pair.Key], {{.FromProto}}(pb))
+		}
+	}
+
+	callEnd(ctx, ck, nil)
+	g.s.RateLimiter.Observe(ctx, nil, ck)
+
+	klog.V(4).Infof("{{.GRPCWrapType}}.AggregatedList(%v, %v) = [%v items], %v", ctx, fl, len(all), nil)
+	return all, nil
+	})
+}
+{{- end}}
+
+{{- if .ListUsableRequest}}
+
+// ListUsable lists the usable {{.Object}} objects.
+func (g *{{.GRPCWrapType}}) ListUsable(ctx context.Context, fl *filter.F) ([]*{{.FQListUsableObjectType}}, error) {
+	return intercept(ctx, g.s, newCallInfo("{{.Service}}", "ListUsable", meta.Version("{{.Version}}"), nil, fl), func(ctx context.Context) ([]*{{.FQListUsableObjectType}}, error) {
+	klog.V(5).Infof("{{.GRPCWrapType}}.ListUsable(%v, %v) called", ctx, fl)
+
+	projectID := g.s.ProjectRouter.ProjectID(ctx, "{{.Version}}", "{{.Service}}")
+	ck := &CallContextKey{
+		ProjectID: projectID,
+		Operation: "ListUsable",
+		Version:   meta.Version("{{.Version}}"),
+		Service:   "{{.Service}}",
+	}
+
+	ctx = g.s.callStart(ctx, ck, nil)
+	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		callEnd(ctx, ck, err)
+		return nil, err
+	}
+	callSend(ctx, nil)
+
+	req := &computepb.{{.ListUsableRequest}}{
+		Project: projectID,
+	}
+	if fl != filter.None {
+		req.Filter = proto.String(fl.String())
+	}
+	var all []*{{.FQListUsableObjectType}}
+	it := g.c.ListUsable(ctx, req)
+	for {
+		pb, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			callEnd(ctx, ck, err)
+			g.s.RateLimiter.Observe(ctx, err, ck)
+
+			klog.V(4).Infof("{{.GRPCWrapType}}.ListUsable(%v, %v) = %v, %v", ctx, fl, nil, err)
+			return nil, err
+		}
+		all = append(all, {{.ListUsableFromProto}}(pb))
+	}
+
+	callEnd(ctx, ck, nil)
+	g.s.RateLimiter.Observe(ctx, nil, ck)
+
+	klog.V(4).Infof("{{.GRPCWrapType}}.ListUsable(%v, %v) = [%v items], %v", ctx, fl, len(all), nil)
+	return all, nil
+	})
+}
+{{- end}}
+
+{{- range .Methods}}
+
+// {{.Name}} is a method on {{.GRPC.GRPCWrapType}}.
+func (g *{{.GRPC.GRPCWrapType}}) {{.FcnArgs}} {
+	key = normalizeKey(key)
+{{- if .IsOperation}}
+	return interceptErr(ctx, g.s, newCallInfo("{{.Service}}", "{{.Name}}", meta.Version("{{.Version}}"), key{{.CallArgs}}{{if .IsPatch}}, fieldMask{{end}}), func(ctx context.Context) error {
+{{- else if .IsGet}}
+	return intercept(ctx, g.s, newCallInfo("{{.Service}}", "{{.Name}}", meta.Version("{{.Version}}"), key{{.CallArgs}}), func(ctx context.Context) (*{{.Version}}.{{.ReturnType}}, error) {
+{{- else if .IsPaged}}
+	return intercept(ctx, g.s, newCallInfo("{{.Service}}", "{{.Name}}", meta.Version("{{.Version}}"), key{{.CallArgs}}, fl), func(ctx context.Context) ([]*{{.Version}}.{{.ItemType}}, error) {
+{{- end}}
+	klog.V(5).Infof("{{.GRPC.GRPCWrapType}}.{{.Name}}(%v, %v, ...): called", ctx, key)
+
+	if !key.Valid() {
+		klog.V(2).Infof("{{.GRPC.GRPCWrapType}}.{{.Name}}(%v, %v, ...): key is invalid (%#v)", ctx, key, key)
+{{- if .IsOperation}}
+		return fmt.Errorf("invalid GCE key (%+v)", key)
+{{- else}}
+		return nil, fmt.Errorf("invalid GCE key (%+v)", key)
+{{- end}}
+	}
+{{- if .IsPatch}}
+	arg0, err := applyFieldMask(arg0, fieldMask)
+	if err != nil {
+		klog.V(2).Infof("{{.GRPC.GRPCWrapType}}.{{.Name}}(%v, %v, ...): invalid field mask %v: %v", ctx, key, fieldMask, err)
+		return err
+	}
+	if arg0 != nil && len(arg0.NullFields) > 0 {
+		// Messages cannot clear a field in a PATCH.
+		return fmt.Errorf("{{.GRPC.GRPCWrapType}}.{{.Name}}: clearing fields %v: %w", arg0.NullFields, ErrGRPCUnsupported)
+	}
+{{- end}}
+	projectID := g.s.ProjectRouter.ProjectID(ctx, "{{.Version}}", "{{.Service}}")
+	ck := &CallContextKey{
+		ProjectID: projectID,
+		Operation: "{{.Name}}",
+		Version:   meta.Version("{{.Version}}"),
+		Service:   "{{.Service}}",
+	}
+	klog.V(5).Infof("{{.GRPC.GRPCWrapType}}.{{.Name}}(%v, %v, ...): projectID = %v, ck = %+v", ctx, key, projectID, ck)
+	ctx = g.s.callStart(ctx, ck, key)
+	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
+		klog.V(4).Infof("{{.GRPC.GRPCWrapType}}.{{.Name}}(%v, %v, ...): RateLimiter error: %v", ctx, key, err)
+		callEnd(ctx, ck, err)
+{{- if .IsOperation}}
+		return err
+{{- else}}
+		return nil, err
+{{- end}}
+	}
+	callSend(ctx, nil)
+
+	req := &computepb.{{.Request}}{
+		Project: projectID,
+{{- if .GRPC.LocationField}}
+		{{.GRPC.LocationField}}: key.{{.GRPC.LocationField}},
+{{- end}}
+		{{.NameField}}: key.Name,
+{{- if .ArgField}}
+		{{.ArgField}}: {{.ArgExpr}},
+{{- end}}
+{{- if .RequestID}}
+		RequestId: proto.String(callRequestID(ctx)),
+{{- end}}
+	}
+{{- if .IsOperation}}
+	op, err := g.c.{{.Name}}(ctx, req)
+	callSetOperation(ctx, op)
+
+	callEnd(ctx, ck, err)
+	g.s.RateLimiter.Observe(ctx, err, ck)
+
+	if err != nil {
+		klog.V(4).Infof("{{.GRPC.GRPCWrapType}}.{{.Name}}(%v, %v, ...) = %+v", ctx, key, err)
+		return err
+	}
+
+	err = waitGRPCOperation(ctx, op)
+	klog.V(4).Infof("{{.GRPC.GRPCWrapType}}.{{.Name}}(%v, %v, ...) = %+v", ctx, key, err)
+	return err
+{{- else if .IsGet}}
+	pb, err := g.c.{{.Name}}(ctx, req)
+	v := {{.ResultFromProto}}(pb)
+
+	callEnd(ctx, ck, err)
+	g.s.RateLimiter.Observe(ctx, err, ck)
+
+	klog.V(4).Infof("{{.GRPC.GRPCWrapType}}.{{.Name}}(%v, %v, ...) = %+v, %v", ctx, key, v, err)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+{{- else if .IsPaged}}
+	if fl != filter.None {
+		req.Filter = proto.String(fl.String())
+	}
+	var all []*{{.Version}}.{{.ItemType}}
+	it := g.c.{{.Name}}(ctx, req)
+	for {
+		pb, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			callEnd(ctx, ck, err)
+			g.s.RateLimiter.Observe(ctx, err, ck)
+
+			klog.V(4).Infof("{{.GRPC.GRPCWrapType}}.{{.Name}}(%v, %v, ...) = %v, %v", ctx, key, nil, err)
+			return nil, err
+		}
+		all = append(all, {{.ResultFromProto}}(pb))
+	}
+
+	callEnd(ctx, ck, nil)
+	g.s.RateLimiter.Observe(ctx, nil, ck)
+
+	klog.V(4).Infof("{{.GRPC.GRPCWrapType}}.{{.Name}}(%v, %v, ...) = [%v items], %v", ctx, key, len(all), nil)
+	return all, nil
+{{- end}}
+	})
+}
+{{- end}}
+
+{{- range .Unsupported}}
+
+// {{.Name}} is not available in compute.{{.Service}}Client. It returns an
+// error wrapping ErrGRPCUnsupported.
+func (g *{{.GRPCWrapType}}) {{.Signature}} {
+	return {{if .HasValue}}nil, {{end}}fmt.Errorf("{{.GRPCWrapType}}.{{.Name}}: %w", ErrGRPCUnsupported)
+}
+{{- end}}
+{{end}}
+`
+	data := struct {
+		Year     string
+		Services []*grpcServiceInfo
+	}{
+		Year:     fmt.Sprintf("%v", time.Now().Year()),
+		Services: grpcServices(conv),
+	}
+	tmpl := template.Must(template.New("grpc").Parse(text))
 	if err := tmpl.Execute(wr, data); err != nil {
 		panic(err)
 	}
+	conv.gen(wr)
+}
+
+// objectDefaults are the values that the API uses for the top level fields
+// of the objects if they are not set. The constructors set them so that the
+// desired state of an object matches what is read back. Fields that are not
+// in a version of the object are skipped.
+var objectDefaults = map[string]map[string]string{
+	"Address":              {"addressType": `"EXTERNAL"`},
+	"BackendService":       {"loadBalancingScheme": `"EXTERNAL"`, "protocol": `"HTTP"`, "sessionAffinity": `"NONE"`, "timeoutSec": "30"},
+	"Firewall":             {"direction": `"INGRESS"`, "priority": "1000"},
+	"ForwardingRule":       {"IPProtocol": `"TCP"`, "loadBalancingScheme": `"EXTERNAL"`},
+	"HealthCheck":          {"checkIntervalSec": "5", "healthyThreshold": "2", "timeoutSec": "5", "unhealthyThreshold": "2"},
+	"HttpHealthCheck":      {"checkIntervalSec": "5", "healthyThreshold": "2", "port": "80", "requestPath": `"/"`, "timeoutSec": "5", "unhealthyThreshold": "2"},
+	"HttpsHealthCheck":     {"checkIntervalSec": "5", "healthyThreshold": "2", "port": "443", "requestPath": `"/"`, "timeoutSec": "5", "unhealthyThreshold": "2"},
+	"NetworkEndpointGroup": {"networkEndpointType": `"GCE_VM_IP_PORT"`},
+	"Route":                {"priority": "1000"},
+	"SslPolicy":            {"minTlsVersion": `"TLS_1_0"`, "profile": `"COMPATIBLE"`},
+	"TargetPool":           {"sessionAffinity": `"NONE"`},
+}
+
+// genConstructors generates the New<Object> functions that return objects
+// with the Kind and the defaults in objectDefaults set.
+func genConstructors(wr io.Writer) {
+	const text = `
+// New{{.VersionPrefix}}{{.Object}} returns a {{.Object}} ({{.Version}} API) named
+// name with Kind and the default values of the API set. opts are applied to
+// the object in order.
+func New{{.VersionPrefix}}{{.Object}}(name string, opts ...func(*{{.FQObjectType}})) *{{.FQObjectType}} {
+	obj := &{{.FQObjectType}}{
+		Name: name,
+{{- if .Kind}}
+		Kind: "{{.Kind}}",
+{{- end}}
+{{- range .Defaults}}
+		{{.Field}}: {{.Value}},
+{{- end}}
+	}
+	for _, opt := range opts {
+		opt(obj)
+	}
+	return obj
+}
+`
+	type fieldDefault struct {
+		Field string
+		Value string
+	}
+	tmpl := template.Must(template.New("constructors").Parse(text))
+	for _, versions := range objectVersions() {
+		for _, s := range versions {
+			if !hasDiscoveryField(s.Version(), s.Object, "name") {
+				continue
+			}
+			data := struct {
+				*meta.ServiceInfo
+				Kind     string
+				Defaults []fieldDefault
+			}{ServiceInfo: s}
+			if kind := loadDiscoverySchemas(s.Version())[s.Object].Properties["kind"]; kind != nil {
+				data.Kind = kind.Default
+			}
+			var fields []string
+			for f := range objectDefaults[s.Object] {
+				fields = append(fields, f)
+			}
+			sort.Strings(fields)
+			for _, f := range fields {
+				if hasDiscoveryField(s.Version(), s.Object, f) {
+					data.Defaults = append(data.Defaults, fieldDefault{
+						Field: strings.ToUpper(f[:1]) + f[1:],
+						Value: objectDefaults[s.Object][f],
+					})
+				}
+			}
+			if err := tmpl.Execute(wr, data); err != nil {
+				panic(err)
+			}
+		}
+	}
+	// Catch typos in objectDefaults.
+	for object, fields := range objectDefaults {
+		for f := range fields {
+			if !hasDiscoveryField(meta.VersionAlpha, object, f) {
+				panic(fmt.Sprintf("objectDefaults: %s has no field %q", object, f))
+			}
+		}
+	}
 }
 
 func main() {
 	flag.Parse()
+	selectServices()
 
 	out := &bytes.Buffer{}
 
@@ -1536,12 +4210,24 @@ func main() {
 	case "src":
 		genHeader(out)
 		genStubs(out)
-		genTypes(out)
+		conv := newVersionConverters()
+		genTypes(out, conv)
+		genLocationServices(out)
+		genUpdateWithRetry(out)
 		genResourceIDs(out)
+		genConverters(out, conv)
+		genDeepCopy(out, conv)
+		conv.gen(out)
+		genDiff(out)
+		genConstructors(out)
 	case "test":
 		genUnitTestHeader(out)
 		genUnitTestServices(out)
 		genUnitTestResourceIDConversion(out)
+	case "grpc":
+		genGRPC(out)
+	case "cloud":
+		genCloud(out)
 	default:
 		log.Fatalf("Invalid -mode: %q", flags.mode)
 	}
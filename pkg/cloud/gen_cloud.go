@@ -0,0 +1,1874 @@
+//go:build !gce_subset
+
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file was generated by "go run gen/main.go -mode cloud > gen_cloud.go". Do not edit
+// directly.
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"google.golang.org/api/option"
+)
+
+// Cloud is an interface for the GCE compute API.
+type Cloud interface {
+	Addresses() Addresses
+	AlphaAddresses() AlphaAddresses
+	BetaAddresses() BetaAddresses
+	AlphaGlobalAddresses() AlphaGlobalAddresses
+	BetaGlobalAddresses() BetaGlobalAddresses
+	GlobalAddresses() GlobalAddresses
+	BackendServices() BackendServices
+	BetaBackendServices() BetaBackendServices
+	AlphaBackendServices() AlphaBackendServices
+	RegionBackendServices() RegionBackendServices
+	AlphaRegionBackendServices() AlphaRegionBackendServices
+	BetaRegionBackendServices() BetaRegionBackendServices
+	Disks() Disks
+	RegionDisks() RegionDisks
+	AlphaFirewalls() AlphaFirewalls
+	BetaFirewalls() BetaFirewalls
+	Firewalls() Firewalls
+	AlphaNetworkFirewallPolicies() AlphaNetworkFirewallPolicies
+	AlphaRegionNetworkFirewallPolicies() AlphaRegionNetworkFirewallPolicies
+	ForwardingRules() ForwardingRules
+	AlphaForwardingRules() AlphaForwardingRules
+	BetaForwardingRules() BetaForwardingRules
+	AlphaGlobalForwardingRules() AlphaGlobalForwardingRules
+	BetaGlobalForwardingRules() BetaGlobalForwardingRules
+	GlobalForwardingRules() GlobalForwardingRules
+	AlphaFutureReservations() AlphaFutureReservations
+	HealthChecks() HealthChecks
+	AlphaHealthChecks() AlphaHealthChecks
+	BetaHealthChecks() BetaHealthChecks
+	AlphaRegionHealthChecks() AlphaRegionHealthChecks
+	BetaRegionHealthChecks() BetaRegionHealthChecks
+	RegionHealthChecks() RegionHealthChecks
+	HttpHealthChecks() HttpHealthChecks
+	HttpsHealthChecks() HttpsHealthChecks
+	InstanceGroups() InstanceGroups
+	Instances() Instances
+	BetaInstances() BetaInstances
+	AlphaInstances() AlphaInstances
+	InstanceGroupManagers() InstanceGroupManagers
+	InstanceTemplates() InstanceTemplates
+	Interconnects() Interconnects
+	InterconnectAttachments() InterconnectAttachments
+	Images() Images
+	BetaImages() BetaImages
+	AlphaImages() AlphaImages
+	AlphaNetworks() AlphaNetworks
+	BetaNetworks() BetaNetworks
+	Networks() Networks
+	AlphaNetworkEndpointGroups() AlphaNetworkEndpointGroups
+	BetaNetworkEndpointGroups() BetaNetworkEndpointGroups
+	NetworkEndpointGroups() NetworkEndpointGroups
+	AlphaGlobalNetworkEndpointGroups() AlphaGlobalNetworkEndpointGroups
+	BetaGlobalNetworkEndpointGroups() BetaGlobalNetworkEndpointGroups
+	GlobalNetworkEndpointGroups() GlobalNetworkEndpointGroups
+	AlphaRegionNetworkEndpointGroups() AlphaRegionNetworkEndpointGroups
+	BetaRegionNetworkEndpointGroups() BetaRegionNetworkEndpointGroups
+	RegionNetworkEndpointGroups() RegionNetworkEndpointGroups
+	Projects() Projects
+	AlphaPublicAdvertisedPrefixes() AlphaPublicAdvertisedPrefixes
+	BetaPublicAdvertisedPrefixes() BetaPublicAdvertisedPrefixes
+	PublicAdvertisedPrefixes() PublicAdvertisedPrefixes
+	AlphaPublicDelegatedPrefixes() AlphaPublicDelegatedPrefixes
+	BetaPublicDelegatedPrefixes() BetaPublicDelegatedPrefixes
+	PublicDelegatedPrefixes() PublicDelegatedPrefixes
+	AlphaGlobalPublicDelegatedPrefixes() AlphaGlobalPublicDelegatedPrefixes
+	BetaGlobalPublicDelegatedPrefixes() BetaGlobalPublicDelegatedPrefixes
+	GlobalPublicDelegatedPrefixes() GlobalPublicDelegatedPrefixes
+	Regions() Regions
+	Reservations() Reservations
+	AlphaReservations() AlphaReservations
+	BetaReservations() BetaReservations
+	AlphaRouters() AlphaRouters
+	BetaRouters() BetaRouters
+	Routers() Routers
+	Routes() Routes
+	SecurityPolicies() SecurityPolicies
+	BetaSecurityPolicies() BetaSecurityPolicies
+	ServiceAttachments() ServiceAttachments
+	BetaServiceAttachments() BetaServiceAttachments
+	AlphaServiceAttachments() AlphaServiceAttachments
+	SslCertificates() SslCertificates
+	BetaSslCertificates() BetaSslCertificates
+	AlphaSslCertificates() AlphaSslCertificates
+	AlphaRegionSslCertificates() AlphaRegionSslCertificates
+	BetaRegionSslCertificates() BetaRegionSslCertificates
+	RegionSslCertificates() RegionSslCertificates
+	SslPolicies() SslPolicies
+	AlphaRegionSslPolicies() AlphaRegionSslPolicies
+	BetaRegionSslPolicies() BetaRegionSslPolicies
+	RegionSslPolicies() RegionSslPolicies
+	AlphaSubnetworks() AlphaSubnetworks
+	BetaSubnetworks() BetaSubnetworks
+	Subnetworks() Subnetworks
+	AlphaTargetHttpProxies() AlphaTargetHttpProxies
+	BetaTargetHttpProxies() BetaTargetHttpProxies
+	TargetHttpProxies() TargetHttpProxies
+	AlphaRegionTargetHttpProxies() AlphaRegionTargetHttpProxies
+	BetaRegionTargetHttpProxies() BetaRegionTargetHttpProxies
+	RegionTargetHttpProxies() RegionTargetHttpProxies
+	TargetHttpsProxies() TargetHttpsProxies
+	AlphaTargetHttpsProxies() AlphaTargetHttpsProxies
+	BetaTargetHttpsProxies() BetaTargetHttpsProxies
+	AlphaRegionTargetHttpsProxies() AlphaRegionTargetHttpsProxies
+	BetaRegionTargetHttpsProxies() BetaRegionTargetHttpsProxies
+	RegionTargetHttpsProxies() RegionTargetHttpsProxies
+	TargetPools() TargetPools
+	AlphaTargetTcpProxies() AlphaTargetTcpProxies
+	BetaTargetTcpProxies() BetaTargetTcpProxies
+	TargetTcpProxies() TargetTcpProxies
+	AlphaUrlMaps() AlphaUrlMaps
+	BetaUrlMaps() BetaUrlMaps
+	UrlMaps() UrlMaps
+	AlphaRegionUrlMaps() AlphaRegionUrlMaps
+	BetaRegionUrlMaps() BetaRegionUrlMaps
+	RegionUrlMaps() RegionUrlMaps
+	Zones() Zones
+	NetworkServices() NetworkServices
+	NetworkConnectivity() NetworkConnectivity
+}
+
+// NewGCE returns a GCE.
+func NewGCE(s *Service) *GCE {
+	g := &GCE{
+		gceAddresses:                          &GCEAddresses{s},
+		gceAlphaAddresses:                     &GCEAlphaAddresses{s},
+		gceBetaAddresses:                      &GCEBetaAddresses{s},
+		gceAlphaGlobalAddresses:               &GCEAlphaGlobalAddresses{s},
+		gceBetaGlobalAddresses:                &GCEBetaGlobalAddresses{s},
+		gceGlobalAddresses:                    &GCEGlobalAddresses{s},
+		gceBackendServices:                    &GCEBackendServices{s},
+		gceBetaBackendServices:                &GCEBetaBackendServices{s},
+		gceAlphaBackendServices:               &GCEAlphaBackendServices{s},
+		gceRegionBackendServices:              &GCERegionBackendServices{s},
+		gceAlphaRegionBackendServices:         &GCEAlphaRegionBackendServices{s},
+		gceBetaRegionBackendServices:          &GCEBetaRegionBackendServices{s},
+		gceDisks:                              &GCEDisks{s},
+		gceRegionDisks:                        &GCERegionDisks{s},
+		gceAlphaFirewalls:                     &GCEAlphaFirewalls{s},
+		gceBetaFirewalls:                      &GCEBetaFirewalls{s},
+		gceFirewalls:                          &GCEFirewalls{s},
+		gceAlphaNetworkFirewallPolicies:       &GCEAlphaNetworkFirewallPolicies{s},
+		gceAlphaRegionNetworkFirewallPolicies: &GCEAlphaRegionNetworkFirewallPolicies{s},
+		gceForwardingRules:                    &GCEForwardingRules{s},
+		gceAlphaForwardingRules:               &GCEAlphaForwardingRules{s},
+		gceBetaForwardingRules:                &GCEBetaForwardingRules{s},
+		gceAlphaGlobalForwardingRules:         &GCEAlphaGlobalForwardingRules{s},
+		gceBetaGlobalForwardingRules:          &GCEBetaGlobalForwardingRules{s},
+		gceGlobalForwardingRules:              &GCEGlobalForwardingRules{s},
+		gceAlphaFutureReservations:            &GCEAlphaFutureReservations{s},
+		gceHealthChecks:                       &GCEHealthChecks{s},
+		gceAlphaHealthChecks:                  &GCEAlphaHealthChecks{s},
+		gceBetaHealthChecks:                   &GCEBetaHealthChecks{s},
+		gceAlphaRegionHealthChecks:            &GCEAlphaRegionHealthChecks{s},
+		gceBetaRegionHealthChecks:             &GCEBetaRegionHealthChecks{s},
+		gceRegionHealthChecks:                 &GCERegionHealthChecks{s},
+		gceHttpHealthChecks:                   &GCEHttpHealthChecks{s},
+		gceHttpsHealthChecks:                  &GCEHttpsHealthChecks{s},
+		gceInstanceGroups:                     &GCEInstanceGroups{s},
+		gceInstances:                          &GCEInstances{s},
+		gceBetaInstances:                      &GCEBetaInstances{s},
+		gceAlphaInstances:                     &GCEAlphaInstances{s},
+		gceInstanceGroupManagers:              &GCEInstanceGroupManagers{s},
+		gceInstanceTemplates:                  &GCEInstanceTemplates{s},
+		gceInterconnects:                      &GCEInterconnects{s},
+		gceInterconnectAttachments:            &GCEInterconnectAttachments{s},
+		gceImages:                             &GCEImages{s},
+		gceBetaImages:                         &GCEBetaImages{s},
+		gceAlphaImages:                        &GCEAlphaImages{s},
+		gceAlphaNetworks:                      &GCEAlphaNetworks{s},
+		gceBetaNetworks:                       &GCEBetaNetworks{s},
+		gceNetworks:                           &GCENetworks{s},
+		gceAlphaNetworkEndpointGroups:         &GCEAlphaNetworkEndpointGroups{s},
+		gceBetaNetworkEndpointGroups:          &GCEBetaNetworkEndpointGroups{s},
+		gceNetworkEndpointGroups:              &GCENetworkEndpointGroups{s},
+		gceAlphaGlobalNetworkEndpointGroups:   &GCEAlphaGlobalNetworkEndpointGroups{s},
+		gceBetaGlobalNetworkEndpointGroups:    &GCEBetaGlobalNetworkEndpointGroups{s},
+		gceGlobalNetworkEndpointGroups:        &GCEGlobalNetworkEndpointGroups{s},
+		gceAlphaRegionNetworkEndpointGroups:   &GCEAlphaRegionNetworkEndpointGroups{s},
+		gceBetaRegionNetworkEndpointGroups:    &GCEBetaRegionNetworkEndpointGroups{s},
+		gceRegionNetworkEndpointGroups:        &GCERegionNetworkEndpointGroups{s},
+		gceProjects:                           &GCEProjects{s},
+		gceAlphaPublicAdvertisedPrefixes:      &GCEAlphaPublicAdvertisedPrefixes{s},
+		gceBetaPublicAdvertisedPrefixes:       &GCEBetaPublicAdvertisedPrefixes{s},
+		gcePublicAdvertisedPrefixes:           &GCEPublicAdvertisedPrefixes{s},
+		gceAlphaPublicDelegatedPrefixes:       &GCEAlphaPublicDelegatedPrefixes{s},
+		gceBetaPublicDelegatedPrefixes:        &GCEBetaPublicDelegatedPrefixes{s},
+		gcePublicDelegatedPrefixes:            &GCEPublicDelegatedPrefixes{s},
+		gceAlphaGlobalPublicDelegatedPrefixes: &GCEAlphaGlobalPublicDelegatedPrefixes{s},
+		gceBetaGlobalPublicDelegatedPrefixes:  &GCEBetaGlobalPublicDelegatedPrefixes{s},
+		gceGlobalPublicDelegatedPrefixes:      &GCEGlobalPublicDelegatedPrefixes{s},
+		gceRegions:                            &GCERegions{s},
+		gceReservations:                       &GCEReservations{s},
+		gceAlphaReservations:                  &GCEAlphaReservations{s},
+		gceBetaReservations:                   &GCEBetaReservations{s},
+		gceAlphaRouters:                       &GCEAlphaRouters{s},
+		gceBetaRouters:                        &GCEBetaRouters{s},
+		gceRouters:                            &GCERouters{s},
+		gceRoutes:                             &GCERoutes{s},
+		gceSecurityPolicies:                   &GCESecurityPolicies{s},
+		gceBetaSecurityPolicies:               &GCEBetaSecurityPolicies{s},
+		gceServiceAttachments:                 &GCEServiceAttachments{s},
+		gceBetaServiceAttachments:             &GCEBetaServiceAttachments{s},
+		gceAlphaServiceAttachments:            &GCEAlphaServiceAttachments{s},
+		gceSslCertificates:                    &GCESslCertificates{s},
+		gceBetaSslCertificates:                &GCEBetaSslCertificates{s},
+		gceAlphaSslCertificates:               &GCEAlphaSslCertificates{s},
+		gceAlphaRegionSslCertificates:         &GCEAlphaRegionSslCertificates{s},
+		gceBetaRegionSslCertificates:          &GCEBetaRegionSslCertificates{s},
+		gceRegionSslCertificates:              &GCERegionSslCertificates{s},
+		gceSslPolicies:                        &GCESslPolicies{s},
+		gceAlphaRegionSslPolicies:             &GCEAlphaRegionSslPolicies{s},
+		gceBetaRegionSslPolicies:              &GCEBetaRegionSslPolicies{s},
+		gceRegionSslPolicies:                  &GCERegionSslPolicies{s},
+		gceAlphaSubnetworks:                   &GCEAlphaSubnetworks{s},
+		gceBetaSubnetworks:                    &GCEBetaSubnetworks{s},
+		gceSubnetworks:                        &GCESubnetworks{s},
+		gceAlphaTargetHttpProxies:             &GCEAlphaTargetHttpProxies{s},
+		gceBetaTargetHttpProxies:              &GCEBetaTargetHttpProxies{s},
+		gceTargetHttpProxies:                  &GCETargetHttpProxies{s},
+		gceAlphaRegionTargetHttpProxies:       &GCEAlphaRegionTargetHttpProxies{s},
+		gceBetaRegionTargetHttpProxies:        &GCEBetaRegionTargetHttpProxies{s},
+		gceRegionTargetHttpProxies:            &GCERegionTargetHttpProxies{s},
+		gceTargetHttpsProxies:                 &GCETargetHttpsProxies{s},
+		gceAlphaTargetHttpsProxies:            &GCEAlphaTargetHttpsProxies{s},
+		gceBetaTargetHttpsProxies:             &GCEBetaTargetHttpsProxies{s},
+		gceAlphaRegionTargetHttpsProxies:      &GCEAlphaRegionTargetHttpsProxies{s},
+		gceBetaRegionTargetHttpsProxies:       &GCEBetaRegionTargetHttpsProxies{s},
+		gceRegionTargetHttpsProxies:           &GCERegionTargetHttpsProxies{s},
+		gceTargetPools:                        &GCETargetPools{s},
+		gceAlphaTargetTcpProxies:              &GCEAlphaTargetTcpProxies{s},
+		gceBetaTargetTcpProxies:               &GCEBetaTargetTcpProxies{s},
+		gceTargetTcpProxies:                   &GCETargetTcpProxies{s},
+		gceAlphaUrlMaps:                       &GCEAlphaUrlMaps{s},
+		gceBetaUrlMaps:                        &GCEBetaUrlMaps{s},
+		gceUrlMaps:                            &GCEUrlMaps{s},
+		gceAlphaRegionUrlMaps:                 &GCEAlphaRegionUrlMaps{s},
+		gceBetaRegionUrlMaps:                  &GCEBetaRegionUrlMaps{s},
+		gceRegionUrlMaps:                      &GCERegionUrlMaps{s},
+		gceZones:                              &GCEZones{s},
+		gceNetworkServices:                    NewGCENetworkServices(s),
+		gceNetworkConnectivity:                NewGCENetworkConnectivity(s),
+	}
+	return g
+}
+
+// GCE implements Cloud.
+var _ Cloud = (*GCE)(nil)
+
+// GCE is the golang adapter for the compute APIs.
+type GCE struct {
+	gceAddresses                          *GCEAddresses
+	gceAlphaAddresses                     *GCEAlphaAddresses
+	gceBetaAddresses                      *GCEBetaAddresses
+	gceAlphaGlobalAddresses               *GCEAlphaGlobalAddresses
+	gceBetaGlobalAddresses                *GCEBetaGlobalAddresses
+	gceGlobalAddresses                    *GCEGlobalAddresses
+	gceBackendServices                    *GCEBackendServices
+	gceBetaBackendServices                *GCEBetaBackendServices
+	gceAlphaBackendServices               *GCEAlphaBackendServices
+	gceRegionBackendServices              *GCERegionBackendServices
+	gceAlphaRegionBackendServices         *GCEAlphaRegionBackendServices
+	gceBetaRegionBackendServices          *GCEBetaRegionBackendServices
+	gceDisks                              *GCEDisks
+	gceRegionDisks                        *GCERegionDisks
+	gceAlphaFirewalls                     *GCEAlphaFirewalls
+	gceBetaFirewalls                      *GCEBetaFirewalls
+	gceFirewalls                          *GCEFirewalls
+	gceAlphaNetworkFirewallPolicies       *GCEAlphaNetworkFirewallPolicies
+	gceAlphaRegionNetworkFirewallPolicies *GCEAlphaRegionNetworkFirewallPolicies
+	gceForwardingRules                    *GCEForwardingRules
+	gceAlphaForwardingRules               *GCEAlphaForwardingRules
+	gceBetaForwardingRules                *GCEBetaForwardingRules
+	gceAlphaGlobalForwardingRules         *GCEAlphaGlobalForwardingRules
+	gceBetaGlobalForwardingRules          *GCEBetaGlobalForwardingRules
+	gceGlobalForwardingRules              *GCEGlobalForwardingRules
+	gceAlphaFutureReservations            *GCEAlphaFutureReservations
+	gceHealthChecks                       *GCEHealthChecks
+	gceAlphaHealthChecks                  *GCEAlphaHealthChecks
+	gceBetaHealthChecks                   *GCEBetaHealthChecks
+	gceAlphaRegionHealthChecks            *GCEAlphaRegionHealthChecks
+	gceBetaRegionHealthChecks             *GCEBetaRegionHealthChecks
+	gceRegionHealthChecks                 *GCERegionHealthChecks
+	gceHttpHealthChecks                   *GCEHttpHealthChecks
+	gceHttpsHealthChecks                  *GCEHttpsHealthChecks
+	gceInstanceGroups                     *GCEInstanceGroups
+	gceInstances                          *GCEInstances
+	gceBetaInstances                      *GCEBetaInstances
+	gceAlphaInstances                     *GCEAlphaInstances
+	gceInstanceGroupManagers              *GCEInstanceGroupManagers
+	gceInstanceTemplates                  *GCEInstanceTemplates
+	gceInterconnects                      *GCEInterconnects
+	gceInterconnectAttachments            *GCEInterconnectAttachments
+	gceImages                             *GCEImages
+	gceBetaImages                         *GCEBetaImages
+	gceAlphaImages                        *GCEAlphaImages
+	gceAlphaNetworks                      *GCEAlphaNetworks
+	gceBetaNetworks                       *GCEBetaNetworks
+	gceNetworks                           *GCENetworks
+	gceAlphaNetworkEndpointGroups         *GCEAlphaNetworkEndpointGroups
+	gceBetaNetworkEndpointGroups          *GCEBetaNetworkEndpointGroups
+	gceNetworkEndpointGroups              *GCENetworkEndpointGroups
+	gceAlphaGlobalNetworkEndpointGroups   *GCEAlphaGlobalNetworkEndpointGroups
+	gceBetaGlobalNetworkEndpointGroups    *GCEBetaGlobalNetworkEndpointGroups
+	gceGlobalNetworkEndpointGroups        *GCEGlobalNetworkEndpointGroups
+	gceAlphaRegionNetworkEndpointGroups   *GCEAlphaRegionNetworkEndpointGroups
+	gceBetaRegionNetworkEndpointGroups    *GCEBetaRegionNetworkEndpointGroups
+	gceRegionNetworkEndpointGroups        *GCERegionNetworkEndpointGroups
+	gceProjects                           *GCEProjects
+	gceAlphaPublicAdvertisedPrefixes      *GCEAlphaPublicAdvertisedPrefixes
+	gceBetaPublicAdvertisedPrefixes       *GCEBetaPublicAdvertisedPrefixes
+	gcePublicAdvertisedPrefixes           *GCEPublicAdvertisedPrefixes
+	gceAlphaPublicDelegatedPrefixes       *GCEAlphaPublicDelegatedPrefixes
+	gceBetaPublicDelegatedPrefixes        *GCEBetaPublicDelegatedPrefixes
+	gcePublicDelegatedPrefixes            *GCEPublicDelegatedPrefixes
+	gceAlphaGlobalPublicDelegatedPrefixes *GCEAlphaGlobalPublicDelegatedPrefixes
+	gceBetaGlobalPublicDelegatedPrefixes  *GCEBetaGlobalPublicDelegatedPrefixes
+	gceGlobalPublicDelegatedPrefixes      *GCEGlobalPublicDelegatedPrefixes
+	gceRegions                            *GCERegions
+	gceReservations                       *GCEReservations
+	gceAlphaReservations                  *GCEAlphaReservations
+	gceBetaReservations                   *GCEBetaReservations
+	gceAlphaRouters                       *GCEAlphaRouters
+	gceBetaRouters                        *GCEBetaRouters
+	gceRouters                            *GCERouters
+	gceRoutes                             *GCERoutes
+	gceSecurityPolicies                   *GCESecurityPolicies
+	gceBetaSecurityPolicies               *GCEBetaSecurityPolicies
+	gceServiceAttachments                 *GCEServiceAttachments
+	gceBetaServiceAttachments             *GCEBetaServiceAttachments
+	gceAlphaServiceAttachments            *GCEAlphaServiceAttachments
+	gceSslCertificates                    *GCESslCertificates
+	gceBetaSslCertificates                *GCEBetaSslCertificates
+	gceAlphaSslCertificates               *GCEAlphaSslCertificates
+	gceAlphaRegionSslCertificates         *GCEAlphaRegionSslCertificates
+	gceBetaRegionSslCertificates          *GCEBetaRegionSslCertificates
+	gceRegionSslCertificates              *GCERegionSslCertificates
+	gceSslPolicies                        *GCESslPolicies
+	gceAlphaRegionSslPolicies             *GCEAlphaRegionSslPolicies
+	gceBetaRegionSslPolicies              *GCEBetaRegionSslPolicies
+	gceRegionSslPolicies                  *GCERegionSslPolicies
+	gceAlphaSubnetworks                   *GCEAlphaSubnetworks
+	gceBetaSubnetworks                    *GCEBetaSubnetworks
+	gceSubnetworks                        *GCESubnetworks
+	gceAlphaTargetHttpProxies             *GCEAlphaTargetHttpProxies
+	gceBetaTargetHttpProxies              *GCEBetaTargetHttpProxies
+	gceTargetHttpProxies                  *GCETargetHttpProxies
+	gceAlphaRegionTargetHttpProxies       *GCEAlphaRegionTargetHttpProxies
+	gceBetaRegionTargetHttpProxies        *GCEBetaRegionTargetHttpProxies
+	gceRegionTargetHttpProxies            *GCERegionTargetHttpProxies
+	gceTargetHttpsProxies                 *GCETargetHttpsProxies
+	gceAlphaTargetHttpsProxies            *GCEAlphaTargetHttpsProxies
+	gceBetaTargetHttpsProxies             *GCEBetaTargetHttpsProxies
+	gceAlphaRegionTargetHttpsProxies      *GCEAlphaRegionTargetHttpsProxies
+	gceBetaRegionTargetHttpsProxies       *GCEBetaRegionTargetHttpsProxies
+	gceRegionTargetHttpsProxies           *GCERegionTargetHttpsProxies
+	gceTargetPools                        *GCETargetPools
+	gceAlphaTargetTcpProxies              *GCEAlphaTargetTcpProxies
+	gceBetaTargetTcpProxies               *GCEBetaTargetTcpProxies
+	gceTargetTcpProxies                   *GCETargetTcpProxies
+	gceAlphaUrlMaps                       *GCEAlphaUrlMaps
+	gceBetaUrlMaps                        *GCEBetaUrlMaps
+	gceUrlMaps                            *GCEUrlMaps
+	gceAlphaRegionUrlMaps                 *GCEAlphaRegionUrlMaps
+	gceBetaRegionUrlMaps                  *GCEBetaRegionUrlMaps
+	gceRegionUrlMaps                      *GCERegionUrlMaps
+	gceZones                              *GCEZones
+	gceNetworkServices                    *GCENetworkServices
+	gceNetworkConnectivity                *GCENetworkConnectivity
+}
+
+// Addresses returns the interface for the ga Addresses.
+func (gce *GCE) Addresses() Addresses {
+	return gce.gceAddresses
+}
+
+// AlphaAddresses returns the interface for the alpha Addresses.
+func (gce *GCE) AlphaAddresses() AlphaAddresses {
+	return gce.gceAlphaAddresses
+}
+
+// BetaAddresses returns the interface for the beta Addresses.
+func (gce *GCE) BetaAddresses() BetaAddresses {
+	return gce.gceBetaAddresses
+}
+
+// AlphaGlobalAddresses returns the interface for the alpha GlobalAddresses.
+func (gce *GCE) AlphaGlobalAddresses() AlphaGlobalAddresses {
+	return gce.gceAlphaGlobalAddresses
+}
+
+// BetaGlobalAddresses returns the interface for the beta GlobalAddresses.
+func (gce *GCE) BetaGlobalAddresses() BetaGlobalAddresses {
+	return gce.gceBetaGlobalAddresses
+}
+
+// GlobalAddresses returns the interface for the ga GlobalAddresses.
+func (gce *GCE) GlobalAddresses() GlobalAddresses {
+	return gce.gceGlobalAddresses
+}
+
+// BackendServices returns the interface for the ga BackendServices.
+func (gce *GCE) BackendServices() BackendServices {
+	return gce.gceBackendServices
+}
+
+// BetaBackendServices returns the interface for the beta BackendServices.
+func (gce *GCE) BetaBackendServices() BetaBackendServices {
+	return gce.gceBetaBackendServices
+}
+
+// AlphaBackendServices returns the interface for the alpha BackendServices.
+func (gce *GCE) AlphaBackendServices() AlphaBackendServices {
+	return gce.gceAlphaBackendServices
+}
+
+// RegionBackendServices returns the interface for the ga RegionBackendServices.
+func (gce *GCE) RegionBackendServices() RegionBackendServices {
+	return gce.gceRegionBackendServices
+}
+
+// AlphaRegionBackendServices returns the interface for the alpha RegionBackendServices.
+func (gce *GCE) AlphaRegionBackendServices() AlphaRegionBackendServices {
+	return gce.gceAlphaRegionBackendServices
+}
+
+// BetaRegionBackendServices returns the interface for the beta RegionBackendServices.
+func (gce *GCE) BetaRegionBackendServices() BetaRegionBackendServices {
+	return gce.gceBetaRegionBackendServices
+}
+
+// Disks returns the interface for the ga Disks.
+func (gce *GCE) Disks() Disks {
+	return gce.gceDisks
+}
+
+// RegionDisks returns the interface for the ga RegionDisks.
+func (gce *GCE) RegionDisks() RegionDisks {
+	return gce.gceRegionDisks
+}
+
+// AlphaFirewalls returns the interface for the alpha Firewalls.
+func (gce *GCE) AlphaFirewalls() AlphaFirewalls {
+	return gce.gceAlphaFirewalls
+}
+
+// BetaFirewalls returns the interface for the beta Firewalls.
+func (gce *GCE) BetaFirewalls() BetaFirewalls {
+	return gce.gceBetaFirewalls
+}
+
+// Firewalls returns the interface for the ga Firewalls.
+func (gce *GCE) Firewalls() Firewalls {
+	return gce.gceFirewalls
+}
+
+// AlphaNetworkFirewallPolicies returns the interface for the alpha NetworkFirewallPolicies.
+func (gce *GCE) AlphaNetworkFirewallPolicies() AlphaNetworkFirewallPolicies {
+	return gce.gceAlphaNetworkFirewallPolicies
+}
+
+// AlphaRegionNetworkFirewallPolicies returns the interface for the alpha RegionNetworkFirewallPolicies.
+func (gce *GCE) AlphaRegionNetworkFirewallPolicies() AlphaRegionNetworkFirewallPolicies {
+	return gce.gceAlphaRegionNetworkFirewallPolicies
+}
+
+// ForwardingRules returns the interface for the ga ForwardingRules.
+func (gce *GCE) ForwardingRules() ForwardingRules {
+	return gce.gceForwardingRules
+}
+
+// AlphaForwardingRules returns the interface for the alpha ForwardingRules.
+func (gce *GCE) AlphaForwardingRules() AlphaForwardingRules {
+	return gce.gceAlphaForwardingRules
+}
+
+// BetaForwardingRules returns the interface for the beta ForwardingRules.
+func (gce *GCE) BetaForwardingRules() BetaForwardingRules {
+	return gce.gceBetaForwardingRules
+}
+
+// AlphaGlobalForwardingRules returns the interface for the alpha GlobalForwardingRules.
+func (gce *GCE) AlphaGlobalForwardingRules() AlphaGlobalForwardingRules {
+	return gce.gceAlphaGlobalForwardingRules
+}
+
+// BetaGlobalForwardingRules returns the interface for the beta GlobalForwardingRules.
+func (gce *GCE) BetaGlobalForwardingRules() BetaGlobalForwardingRules {
+	return gce.gceBetaGlobalForwardingRules
+}
+
+// GlobalForwardingRules returns the interface for the ga GlobalForwardingRules.
+func (gce *GCE) GlobalForwardingRules() GlobalForwardingRules {
+	return gce.gceGlobalForwardingRules
+}
+
+// AlphaFutureReservations returns the interface for the alpha FutureReservations.
+func (gce *GCE) AlphaFutureReservations() AlphaFutureReservations {
+	return gce.gceAlphaFutureReservations
+}
+
+// HealthChecks returns the interface for the ga HealthChecks.
+func (gce *GCE) HealthChecks() HealthChecks {
+	return gce.gceHealthChecks
+}
+
+// AlphaHealthChecks returns the interface for the alpha HealthChecks.
+func (gce *GCE) AlphaHealthChecks() AlphaHealthChecks {
+	return gce.gceAlphaHealthChecks
+}
+
+// BetaHealthChecks returns the interface for the beta HealthChecks.
+func (gce *GCE) BetaHealthChecks() BetaHealthChecks {
+	return gce.gceBetaHealthChecks
+}
+
+// AlphaRegionHealthChecks returns the interface for the alpha RegionHealthChecks.
+func (gce *GCE) AlphaRegionHealthChecks() AlphaRegionHealthChecks {
+	return gce.gceAlphaRegionHealthChecks
+}
+
+// BetaRegionHealthChecks returns the interface for the beta RegionHealthChecks.
+func (gce *GCE) BetaRegionHealthChecks() BetaRegionHealthChecks {
+	return gce.gceBetaRegionHealthChecks
+}
+
+// RegionHealthChecks returns the interface for the ga RegionHealthChecks.
+func (gce *GCE) RegionHealthChecks() RegionHealthChecks {
+	return gce.gceRegionHealthChecks
+}
+
+// HttpHealthChecks returns the interface for the ga HttpHealthChecks.
+func (gce *GCE) HttpHealthChecks() HttpHealthChecks {
+	return gce.gceHttpHealthChecks
+}
+
+// HttpsHealthChecks returns the interface for the ga HttpsHealthChecks.
+func (gce *GCE) HttpsHealthChecks() HttpsHealthChecks {
+	return gce.gceHttpsHealthChecks
+}
+
+// InstanceGroups returns the interface for the ga InstanceGroups.
+func (gce *GCE) InstanceGroups() InstanceGroups {
+	return gce.gceInstanceGroups
+}
+
+// Instances returns the interface for the ga Instances.
+func (gce *GCE) Instances() Instances {
+	return gce.gceInstances
+}
+
+// BetaInstances returns the interface for the beta Instances.
+func (gce *GCE) BetaInstances() BetaInstances {
+	return gce.gceBetaInstances
+}
+
+// AlphaInstances returns the interface for the alpha Instances.
+func (gce *GCE) AlphaInstances() AlphaInstances {
+	return gce.gceAlphaInstances
+}
+
+// InstanceGroupManagers returns the interface for the ga InstanceGroupManagers.
+func (gce *GCE) InstanceGroupManagers() InstanceGroupManagers {
+	return gce.gceInstanceGroupManagers
+}
+
+// InstanceTemplates returns the interface for the ga InstanceTemplates.
+func (gce *GCE) InstanceTemplates() InstanceTemplates {
+	return gce.gceInstanceTemplates
+}
+
+// Interconnects returns the interface for the ga Interconnects.
+func (gce *GCE) Interconnects() Interconnects {
+	return gce.gceInterconnects
+}
+
+// InterconnectAttachments returns the interface for the ga InterconnectAttachments.
+func (gce *GCE) InterconnectAttachments() InterconnectAttachments {
+	return gce.gceInterconnectAttachments
+}
+
+// Images returns the interface for the ga Images.
+func (gce *GCE) Images() Images {
+	return gce.gceImages
+}
+
+// BetaImages returns the interface for the beta Images.
+func (gce *GCE) BetaImages() BetaImages {
+	return gce.gceBetaImages
+}
+
+// AlphaImages returns the interface for the alpha Images.
+func (gce *GCE) AlphaImages() AlphaImages {
+	return gce.gceAlphaImages
+}
+
+// AlphaNetworks returns the interface for the alpha Networks.
+func (gce *GCE) AlphaNetworks() AlphaNetworks {
+	return gce.gceAlphaNetworks
+}
+
+// BetaNetworks returns the interface for the beta Networks.
+func (gce *GCE) BetaNetworks() BetaNetworks {
+	return gce.gceBetaNetworks
+}
+
+// Networks returns the interface for the ga Networks.
+func (gce *GCE) Networks() Networks {
+	return gce.gceNetworks
+}
+
+// AlphaNetworkEndpointGroups returns the interface for the alpha NetworkEndpointGroups.
+func (gce *GCE) AlphaNetworkEndpointGroups() AlphaNetworkEndpointGroups {
+	return gce.gceAlphaNetworkEndpointGroups
+}
+
+// BetaNetworkEndpointGroups returns the interface for the beta NetworkEndpointGroups.
+func (gce *GCE) BetaNetworkEndpointGroups() BetaNetworkEndpointGroups {
+	return gce.gceBetaNetworkEndpointGroups
+}
+
+// NetworkEndpointGroups returns the interface for the ga NetworkEndpointGroups.
+func (gce *GCE) NetworkEndpointGroups() NetworkEndpointGroups {
+	return gce.gceNetworkEndpointGroups
+}
+
+// AlphaGlobalNetworkEndpointGroups returns the interface for the alpha GlobalNetworkEndpointGroups.
+func (gce *GCE) AlphaGlobalNetworkEndpointGroups() AlphaGlobalNetworkEndpointGroups {
+	return gce.gceAlphaGlobalNetworkEndpointGroups
+}
+
+// BetaGlobalNetworkEndpointGroups returns the interface for the beta GlobalNetworkEndpointGroups.
+func (gce *GCE) BetaGlobalNetworkEndpointGroups() BetaGlobalNetworkEndpointGroups {
+	return gce.gceBetaGlobalNetworkEndpointGroups
+}
+
+// GlobalNetworkEndpointGroups returns the interface for the ga GlobalNetworkEndpointGroups.
+func (gce *GCE) GlobalNetworkEndpointGroups() GlobalNetworkEndpointGroups {
+	return gce.gceGlobalNetworkEndpointGroups
+}
+
+// AlphaRegionNetworkEndpointGroups returns the interface for the alpha RegionNetworkEndpointGroups.
+func (gce *GCE) AlphaRegionNetworkEndpointGroups() AlphaRegionNetworkEndpointGroups {
+	return gce.gceAlphaRegionNetworkEndpointGroups
+}
+
+// BetaRegionNetworkEndpointGroups returns the interface for the beta RegionNetworkEndpointGroups.
+func (gce *GCE) BetaRegionNetworkEndpointGroups() BetaRegionNetworkEndpointGroups {
+	return gce.gceBetaRegionNetworkEndpointGroups
+}
+
+// RegionNetworkEndpointGroups returns the interface for the ga RegionNetworkEndpointGroups.
+func (gce *GCE) RegionNetworkEndpointGroups() RegionNetworkEndpointGroups {
+	return gce.gceRegionNetworkEndpointGroups
+}
+
+// Projects returns the interface for the ga Projects.
+func (gce *GCE) Projects() Projects {
+	return gce.gceProjects
+}
+
+// AlphaPublicAdvertisedPrefixes returns the interface for the alpha PublicAdvertisedPrefixes.
+func (gce *GCE) AlphaPublicAdvertisedPrefixes() AlphaPublicAdvertisedPrefixes {
+	return gce.gceAlphaPublicAdvertisedPrefixes
+}
+
+// BetaPublicAdvertisedPrefixes returns the interface for the beta PublicAdvertisedPrefixes.
+func (gce *GCE) BetaPublicAdvertisedPrefixes() BetaPublicAdvertisedPrefixes {
+	return gce.gceBetaPublicAdvertisedPrefixes
+}
+
+// PublicAdvertisedPrefixes returns the interface for the ga PublicAdvertisedPrefixes.
+func (gce *GCE) PublicAdvertisedPrefixes() PublicAdvertisedPrefixes {
+	return gce.gcePublicAdvertisedPrefixes
+}
+
+// AlphaPublicDelegatedPrefixes returns the interface for the alpha PublicDelegatedPrefixes.
+func (gce *GCE) AlphaPublicDelegatedPrefixes() AlphaPublicDelegatedPrefixes {
+	return gce.gceAlphaPublicDelegatedPrefixes
+}
+
+// BetaPublicDelegatedPrefixes returns the interface for the beta PublicDelegatedPrefixes.
+func (gce *GCE) BetaPublicDelegatedPrefixes() BetaPublicDelegatedPrefixes {
+	return gce.gceBetaPublicDelegatedPrefixes
+}
+
+// PublicDelegatedPrefixes returns the interface for the ga PublicDelegatedPrefixes.
+func (gce *GCE) PublicDelegatedPrefixes() PublicDelegatedPrefixes {
+	return gce.gcePublicDelegatedPrefixes
+}
+
+// AlphaGlobalPublicDelegatedPrefixes returns the interface for the alpha GlobalPublicDelegatedPrefixes.
+func (gce *GCE) AlphaGlobalPublicDelegatedPrefixes() AlphaGlobalPublicDelegatedPrefixes {
+	return gce.gceAlphaGlobalPublicDelegatedPrefixes
+}
+
+// BetaGlobalPublicDelegatedPrefixes returns the interface for the beta GlobalPublicDelegatedPrefixes.
+func (gce *GCE) BetaGlobalPublicDelegatedPrefixes() BetaGlobalPublicDelegatedPrefixes {
+	return gce.gceBetaGlobalPublicDelegatedPrefixes
+}
+
+// GlobalPublicDelegatedPrefixes returns the interface for the ga GlobalPublicDelegatedPrefixes.
+func (gce *GCE) GlobalPublicDelegatedPrefixes() GlobalPublicDelegatedPrefixes {
+	return gce.gceGlobalPublicDelegatedPrefixes
+}
+
+// Regions returns the interface for the ga Regions.
+func (gce *GCE) Regions() Regions {
+	return gce.gceRegions
+}
+
+// Reservations returns the interface for the ga Reservations.
+func (gce *GCE) Reservations() Reservations {
+	return gce.gceReservations
+}
+
+// AlphaReservations returns the interface for the alpha Reservations.
+func (gce *GCE) AlphaReservations() AlphaReservations {
+	return gce.gceAlphaReservations
+}
+
+// BetaReservations returns the interface for the beta Reservations.
+func (gce *GCE) BetaReservations() BetaReservations {
+	return gce.gceBetaReservations
+}
+
+// AlphaRouters returns the interface for the alpha Routers.
+func (gce *GCE) AlphaRouters() AlphaRouters {
+	return gce.gceAlphaRouters
+}
+
+// BetaRouters returns the interface for the beta Routers.
+func (gce *GCE) BetaRouters() BetaRouters {
+	return gce.gceBetaRouters
+}
+
+// Routers returns the interface for the ga Routers.
+func (gce *GCE) Routers() Routers {
+	return gce.gceRouters
+}
+
+// Routes returns the interface for the ga Routes.
+func (gce *GCE) Routes() Routes {
+	return gce.gceRoutes
+}
+
+// SecurityPolicies returns the interface for the ga SecurityPolicies.
+func (gce *GCE) SecurityPolicies() SecurityPolicies {
+	return gce.gceSecurityPolicies
+}
+
+// BetaSecurityPolicies returns the interface for the beta SecurityPolicies.
+func (gce *GCE) BetaSecurityPolicies() BetaSecurityPolicies {
+	return gce.gceBetaSecurityPolicies
+}
+
+// ServiceAttachments returns the interface for the ga ServiceAttachments.
+func (gce *GCE) ServiceAttachments() ServiceAttachments {
+	return gce.gceServiceAttachments
+}
+
+// BetaServiceAttachments returns the interface for the beta ServiceAttachments.
+func (gce *GCE) BetaServiceAttachments() BetaServiceAttachments {
+	return gce.gceBetaServiceAttachments
+}
+
+// AlphaServiceAttachments returns the interface for the alpha ServiceAttachments.
+func (gce *GCE) AlphaServiceAttachments() AlphaServiceAttachments {
+	return gce.gceAlphaServiceAttachments
+}
+
+// SslCertificates returns the interface for the ga SslCertificates.
+func (gce *GCE) SslCertificates() SslCertificates {
+	return gce.gceSslCertificates
+}
+
+// BetaSslCertificates returns the interface for the beta SslCertificates.
+func (gce *GCE) BetaSslCertificates() BetaSslCertificates {
+	return gce.gceBetaSslCertificates
+}
+
+// AlphaSslCertificates returns the interface for the alpha SslCertificates.
+func (gce *GCE) AlphaSslCertificates() AlphaSslCertificates {
+	return gce.gceAlphaSslCertificates
+}
+
+// AlphaRegionSslCertificates returns the interface for the alpha RegionSslCertificates.
+func (gce *GCE) AlphaRegionSslCertificates() AlphaRegionSslCertificates {
+	return gce.gceAlphaRegionSslCertificates
+}
+
+// BetaRegionSslCertificates returns the interface for the beta RegionSslCertificates.
+func (gce *GCE) BetaRegionSslCertificates() BetaRegionSslCertificates {
+	return gce.gceBetaRegionSslCertificates
+}
+
+// RegionSslCertificates returns the interface for the ga RegionSslCertificates.
+func (gce *GCE) RegionSslCertificates() RegionSslCertificates {
+	return gce.gceRegionSslCertificates
+}
+
+// SslPolicies returns the interface for the ga SslPolicies.
+func (gce *GCE) SslPolicies() SslPolicies {
+	return gce.gceSslPolicies
+}
+
+// AlphaRegionSslPolicies returns the interface for the alpha RegionSslPolicies.
+func (gce *GCE) AlphaRegionSslPolicies() AlphaRegionSslPolicies {
+	return gce.gceAlphaRegionSslPolicies
+}
+
+// BetaRegionSslPolicies returns the interface for the beta RegionSslPolicies.
+func (gce *GCE) BetaRegionSslPolicies() BetaRegionSslPolicies {
+	return gce.gceBetaRegionSslPolicies
+}
+
+// RegionSslPolicies returns the interface for the ga RegionSslPolicies.
+func (gce *GCE) RegionSslPolicies() RegionSslPolicies {
+	return gce.gceRegionSslPolicies
+}
+
+// AlphaSubnetworks returns the interface for the alpha Subnetworks.
+func (gce *GCE) AlphaSubnetworks() AlphaSubnetworks {
+	return gce.gceAlphaSubnetworks
+}
+
+// BetaSubnetworks returns the interface for the beta Subnetworks.
+func (gce *GCE) BetaSubnetworks() BetaSubnetworks {
+	return gce.gceBetaSubnetworks
+}
+
+// Subnetworks returns the interface for the ga Subnetworks.
+func (gce *GCE) Subnetworks() Subnetworks {
+	return gce.gceSubnetworks
+}
+
+// AlphaTargetHttpProxies returns the interface for the alpha TargetHttpProxies.
+func (gce *GCE) AlphaTargetHttpProxies() AlphaTargetHttpProxies {
+	return gce.gceAlphaTargetHttpProxies
+}
+
+// BetaTargetHttpProxies returns the interface for the beta TargetHttpProxies.
+func (gce *GCE) BetaTargetHttpProxies() BetaTargetHttpProxies {
+	return gce.gceBetaTargetHttpProxies
+}
+
+// TargetHttpProxies returns the interface for the ga TargetHttpProxies.
+func (gce *GCE) TargetHttpProxies() TargetHttpProxies {
+	return gce.gceTargetHttpProxies
+}
+
+// AlphaRegionTargetHttpProxies returns the interface for the alpha RegionTargetHttpProxies.
+func (gce *GCE) AlphaRegionTargetHttpProxies() AlphaRegionTargetHttpProxies {
+	return gce.gceAlphaRegionTargetHttpProxies
+}
+
+// BetaRegionTargetHttpProxies returns the interface for the beta RegionTargetHttpProxies.
+func (gce *GCE) BetaRegionTargetHttpProxies() BetaRegionTargetHttpProxies {
+	return gce.gceBetaRegionTargetHttpProxies
+}
+
+// RegionTargetHttpProxies returns the interface for the ga RegionTargetHttpProxies.
+func (gce *GCE) RegionTargetHttpProxies() RegionTargetHttpProxies {
+	return gce.gceRegionTargetHttpProxies
+}
+
+// TargetHttpsProxies returns the interface for the ga TargetHttpsProxies.
+func (gce *GCE) TargetHttpsProxies() TargetHttpsProxies {
+	return gce.gceTargetHttpsProxies
+}
+
+// AlphaTargetHttpsProxies returns the interface for the alpha TargetHttpsProxies.
+func (gce *GCE) AlphaTargetHttpsProxies() AlphaTargetHttpsProxies {
+	return gce.gceAlphaTargetHttpsProxies
+}
+
+// BetaTargetHttpsProxies returns the interface for the beta TargetHttpsProxies.
+func (gce *GCE) BetaTargetHttpsProxies() BetaTargetHttpsProxies {
+	return gce.gceBetaTargetHttpsProxies
+}
+
+// AlphaRegionTargetHttpsProxies returns the interface for the alpha RegionTargetHttpsProxies.
+func (gce *GCE) AlphaRegionTargetHttpsProxies() AlphaRegionTargetHttpsProxies {
+	return gce.gceAlphaRegionTargetHttpsProxies
+}
+
+// BetaRegionTargetHttpsProxies returns the interface for the beta RegionTargetHttpsProxies.
+func (gce *GCE) BetaRegionTargetHttpsProxies() BetaRegionTargetHttpsProxies {
+	return gce.gceBetaRegionTargetHttpsProxies
+}
+
+// RegionTargetHttpsProxies returns the interface for the ga RegionTargetHttpsProxies.
+func (gce *GCE) RegionTargetHttpsProxies() RegionTargetHttpsProxies {
+	return gce.gceRegionTargetHttpsProxies
+}
+
+// TargetPools returns the interface for the ga TargetPools.
+func (gce *GCE) TargetPools() TargetPools {
+	return gce.gceTargetPools
+}
+
+// AlphaTargetTcpProxies returns the interface for the alpha TargetTcpProxies.
+func (gce *GCE) AlphaTargetTcpProxies() AlphaTargetTcpProxies {
+	return gce.gceAlphaTargetTcpProxies
+}
+
+// BetaTargetTcpProxies returns the interface for the beta TargetTcpProxies.
+func (gce *GCE) BetaTargetTcpProxies() BetaTargetTcpProxies {
+	return gce.gceBetaTargetTcpProxies
+}
+
+// TargetTcpProxies returns the interface for the ga TargetTcpProxies.
+func (gce *GCE) TargetTcpProxies() TargetTcpProxies {
+	return gce.gceTargetTcpProxies
+}
+
+// AlphaUrlMaps returns the interface for the alpha UrlMaps.
+func (gce *GCE) AlphaUrlMaps() AlphaUrlMaps {
+	return gce.gceAlphaUrlMaps
+}
+
+// BetaUrlMaps returns the interface for the beta UrlMaps.
+func (gce *GCE) BetaUrlMaps() BetaUrlMaps {
+	return gce.gceBetaUrlMaps
+}
+
+// UrlMaps returns the interface for the ga UrlMaps.
+func (gce *GCE) UrlMaps() UrlMaps {
+	return gce.gceUrlMaps
+}
+
+// AlphaRegionUrlMaps returns the interface for the alpha RegionUrlMaps.
+func (gce *GCE) AlphaRegionUrlMaps() AlphaRegionUrlMaps {
+	return gce.gceAlphaRegionUrlMaps
+}
+
+// BetaRegionUrlMaps returns the interface for the beta RegionUrlMaps.
+func (gce *GCE) BetaRegionUrlMaps() BetaRegionUrlMaps {
+	return gce.gceBetaRegionUrlMaps
+}
+
+// RegionUrlMaps returns the interface for the ga RegionUrlMaps.
+func (gce *GCE) RegionUrlMaps() RegionUrlMaps {
+	return gce.gceRegionUrlMaps
+}
+
+// Zones returns the interface for the ga Zones.
+func (gce *GCE) Zones() Zones {
+	return gce.gceZones
+}
+
+// NetworkServices returns the interface for the networkservices API.
+func (gce *GCE) NetworkServices() NetworkServices {
+	return gce.gceNetworkServices
+}
+
+// NetworkConnectivity returns the interface for the networkconnectivity API.
+func (gce *GCE) NetworkConnectivity() NetworkConnectivity {
+	return gce.gceNetworkConnectivity
+}
+
+// GRPCGCE implements Cloud using the protobuf based compute clients
+// (cloud.google.com/go/compute/apiv1) for the GA services that have one.
+// Objects are converted field by field between the compute/v1 structs and
+// the computepb messages. The methods of these services that have no
+// equivalent in apiv1 return an error wrapping ErrGRPCUnsupported. The
+// services without an apiv1 client (and all alpha and beta services) are
+// served by the Discovery based client of the embedded GCE.
+type GRPCGCE struct {
+	*GCE
+	grpcAddresses                     *GRPCAddresses
+	grpcGlobalAddresses               *GRPCGlobalAddresses
+	grpcBackendServices               *GRPCBackendServices
+	grpcRegionBackendServices         *GRPCRegionBackendServices
+	grpcDisks                         *GRPCDisks
+	grpcRegionDisks                   *GRPCRegionDisks
+	grpcFirewalls                     *GRPCFirewalls
+	grpcForwardingRules               *GRPCForwardingRules
+	grpcGlobalForwardingRules         *GRPCGlobalForwardingRules
+	grpcHealthChecks                  *GRPCHealthChecks
+	grpcRegionHealthChecks            *GRPCRegionHealthChecks
+	grpcInstanceGroups                *GRPCInstanceGroups
+	grpcInstances                     *GRPCInstances
+	grpcInstanceGroupManagers         *GRPCInstanceGroupManagers
+	grpcInstanceTemplates             *GRPCInstanceTemplates
+	grpcInterconnects                 *GRPCInterconnects
+	grpcInterconnectAttachments       *GRPCInterconnectAttachments
+	grpcImages                        *GRPCImages
+	grpcNetworks                      *GRPCNetworks
+	grpcNetworkEndpointGroups         *GRPCNetworkEndpointGroups
+	grpcGlobalNetworkEndpointGroups   *GRPCGlobalNetworkEndpointGroups
+	grpcRegionNetworkEndpointGroups   *GRPCRegionNetworkEndpointGroups
+	grpcPublicAdvertisedPrefixes      *GRPCPublicAdvertisedPrefixes
+	grpcPublicDelegatedPrefixes       *GRPCPublicDelegatedPrefixes
+	grpcGlobalPublicDelegatedPrefixes *GRPCGlobalPublicDelegatedPrefixes
+	grpcRegions                       *GRPCRegions
+	grpcReservations                  *GRPCReservations
+	grpcRouters                       *GRPCRouters
+	grpcRoutes                        *GRPCRoutes
+	grpcSecurityPolicies              *GRPCSecurityPolicies
+	grpcServiceAttachments            *GRPCServiceAttachments
+	grpcSslCertificates               *GRPCSslCertificates
+	grpcRegionSslCertificates         *GRPCRegionSslCertificates
+	grpcSslPolicies                   *GRPCSslPolicies
+	grpcRegionSslPolicies             *GRPCRegionSslPolicies
+	grpcSubnetworks                   *GRPCSubnetworks
+	grpcTargetHttpProxies             *GRPCTargetHttpProxies
+	grpcRegionTargetHttpProxies       *GRPCRegionTargetHttpProxies
+	grpcTargetHttpsProxies            *GRPCTargetHttpsProxies
+	grpcRegionTargetHttpsProxies      *GRPCRegionTargetHttpsProxies
+	grpcTargetPools                   *GRPCTargetPools
+	grpcTargetTcpProxies              *GRPCTargetTcpProxies
+	grpcUrlMaps                       *GRPCUrlMaps
+	grpcRegionUrlMaps                 *GRPCRegionUrlMaps
+	grpcZones                         *GRPCZones
+}
+
+// NewGRPCGCE returns a new GRPCGCE. opts are passed to each of the compute
+// clients. Close() must be called to release the clients.
+func NewGRPCGCE(ctx context.Context, s *Service, opts ...option.ClientOption) (*GRPCGCE, error) {
+	g := &GRPCGCE{GCE: NewGCE(s)}
+	{
+		c, err := compute.NewAddressesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewAddressesRESTClient: %w", err)
+		}
+		g.grpcAddresses = &GRPCAddresses{s: s, c: c}
+	}
+	{
+		c, err := compute.NewGlobalAddressesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewGlobalAddressesRESTClient: %w", err)
+		}
+		g.grpcGlobalAddresses = &GRPCGlobalAddresses{s: s, c: c}
+	}
+	{
+		c, err := compute.NewBackendServicesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewBackendServicesRESTClient: %w", err)
+		}
+		g.grpcBackendServices = &GRPCBackendServices{s: s, c: c}
+	}
+	{
+		c, err := compute.NewRegionBackendServicesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewRegionBackendServicesRESTClient: %w", err)
+		}
+		g.grpcRegionBackendServices = &GRPCRegionBackendServices{s: s, c: c}
+	}
+	{
+		c, err := compute.NewDisksRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewDisksRESTClient: %w", err)
+		}
+		g.grpcDisks = &GRPCDisks{s: s, c: c}
+	}
+	{
+		c, err := compute.NewRegionDisksRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewRegionDisksRESTClient: %w", err)
+		}
+		g.grpcRegionDisks = &GRPCRegionDisks{s: s, c: c}
+	}
+	{
+		c, err := compute.NewFirewallsRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewFirewallsRESTClient: %w", err)
+		}
+		g.grpcFirewalls = &GRPCFirewalls{s: s, c: c}
+	}
+	{
+		c, err := compute.NewForwardingRulesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewForwardingRulesRESTClient: %w", err)
+		}
+		g.grpcForwardingRules = &GRPCForwardingRules{s: s, c: c}
+	}
+	{
+		c, err := compute.NewGlobalForwardingRulesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewGlobalForwardingRulesRESTClient: %w", err)
+		}
+		g.grpcGlobalForwardingRules = &GRPCGlobalForwardingRules{s: s, c: c}
+	}
+	{
+		c, err := compute.NewHealthChecksRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewHealthChecksRESTClient: %w", err)
+		}
+		g.grpcHealthChecks = &GRPCHealthChecks{s: s, c: c}
+	}
+	{
+		c, err := compute.NewRegionHealthChecksRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewRegionHealthChecksRESTClient: %w", err)
+		}
+		g.grpcRegionHealthChecks = &GRPCRegionHealthChecks{s: s, c: c}
+	}
+	{
+		c, err := compute.NewInstanceGroupsRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewInstanceGroupsRESTClient: %w", err)
+		}
+		g.grpcInstanceGroups = &GRPCInstanceGroups{s: s, c: c}
+	}
+	{
+		c, err := compute.NewInstancesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewInstancesRESTClient: %w", err)
+		}
+		g.grpcInstances = &GRPCInstances{s: s, c: c}
+	}
+	{
+		c, err := compute.NewInstanceGroupManagersRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewInstanceGroupManagersRESTClient: %w", err)
+		}
+		g.grpcInstanceGroupManagers = &GRPCInstanceGroupManagers{s: s, c: c}
+	}
+	{
+		c, err := compute.NewInstanceTemplatesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewInstanceTemplatesRESTClient: %w", err)
+		}
+		g.grpcInstanceTemplates = &GRPCInstanceTemplates{s: s, c: c}
+	}
+	{
+		c, err := compute.NewInterconnectsRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewInterconnectsRESTClient: %w", err)
+		}
+		g.grpcInterconnects = &GRPCInterconnects{s: s, c: c}
+	}
+	{
+		c, err := compute.NewInterconnectAttachmentsRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewInterconnectAttachmentsRESTClient: %w", err)
+		}
+		g.grpcInterconnectAttachments = &GRPCInterconnectAttachments{s: s, c: c}
+	}
+	{
+		c, err := compute.NewImagesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewImagesRESTClient: %w", err)
+		}
+		g.grpcImages = &GRPCImages{s: s, c: c}
+	}
+	{
+		c, err := compute.NewNetworksRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewNetworksRESTClient: %w", err)
+		}
+		g.grpcNetworks = &GRPCNetworks{s: s, c: c}
+	}
+	{
+		c, err := compute.NewNetworkEndpointGroupsRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewNetworkEndpointGroupsRESTClient: %w", err)
+		}
+		g.grpcNetworkEndpointGroups = &GRPCNetworkEndpointGroups{s: s, c: c}
+	}
+	{
+		c, err := compute.NewGlobalNetworkEndpointGroupsRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewGlobalNetworkEndpointGroupsRESTClient: %w", err)
+		}
+		g.grpcGlobalNetworkEndpointGroups = &GRPCGlobalNetworkEndpointGroups{s: s, c: c}
+	}
+	{
+		c, err := compute.NewRegionNetworkEndpointGroupsRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewRegionNetworkEndpointGroupsRESTClient: %w", err)
+		}
+		g.grpcRegionNetworkEndpointGroups = &GRPCRegionNetworkEndpointGroups{s: s, c: c}
+	}
+	{
+		c, err := compute.NewPublicAdvertisedPrefixesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewPublicAdvertisedPrefixesRESTClient: %w", err)
+		}
+		g.grpcPublicAdvertisedPrefixes = &GRPCPublicAdvertisedPrefixes{s: s, c: c}
+	}
+	{
+		c, err := compute.NewPublicDelegatedPrefixesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewPublicDelegatedPrefixesRESTClient: %w", err)
+		}
+		g.grpcPublicDelegatedPrefixes = &GRPCPublicDelegatedPrefixes{s: s, c: c}
+	}
+	{
+		c, err := compute.NewGlobalPublicDelegatedPrefixesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewGlobalPublicDelegatedPrefixesRESTClient: %w", err)
+		}
+		g.grpcGlobalPublicDelegatedPrefixes = &GRPCGlobalPublicDelegatedPrefixes{s: s, c: c}
+	}
+	{
+		c, err := compute.NewRegionsRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewRegionsRESTClient: %w", err)
+		}
+		g.grpcRegions = &GRPCRegions{s: s, c: c}
+	}
+	{
+		c, err := compute.NewReservationsRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewReservationsRESTClient: %w", err)
+		}
+		g.grpcReservations = &GRPCReservations{s: s, c: c}
+	}
+	{
+		c, err := compute.NewRoutersRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewRoutersRESTClient: %w", err)
+		}
+		g.grpcRouters = &GRPCRouters{s: s, c: c}
+	}
+	{
+		c, err := compute.NewRoutesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewRoutesRESTClient: %w", err)
+		}
+		g.grpcRoutes = &GRPCRoutes{s: s, c: c}
+	}
+	{
+		c, err := compute.NewSecurityPoliciesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewSecurityPoliciesRESTClient: %w", err)
+		}
+		g.grpcSecurityPolicies = &GRPCSecurityPolicies{s: s, c: c}
+	}
+	{
+		c, err := compute.NewServiceAttachmentsRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewServiceAttachmentsRESTClient: %w", err)
+		}
+		g.grpcServiceAttachments = &GRPCServiceAttachments{s: s, c: c}
+	}
+	{
+		c, err := compute.NewSslCertificatesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewSslCertificatesRESTClient: %w", err)
+		}
+		g.grpcSslCertificates = &GRPCSslCertificates{s: s, c: c}
+	}
+	{
+		c, err := compute.NewRegionSslCertificatesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewRegionSslCertificatesRESTClient: %w", err)
+		}
+		g.grpcRegionSslCertificates = &GRPCRegionSslCertificates{s: s, c: c}
+	}
+	{
+		c, err := compute.NewSslPoliciesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewSslPoliciesRESTClient: %w", err)
+		}
+		g.grpcSslPolicies = &GRPCSslPolicies{s: s, c: c}
+	}
+	{
+		c, err := compute.NewRegionSslPoliciesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewRegionSslPoliciesRESTClient: %w", err)
+		}
+		g.grpcRegionSslPolicies = &GRPCRegionSslPolicies{s: s, c: c}
+	}
+	{
+		c, err := compute.NewSubnetworksRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewSubnetworksRESTClient: %w", err)
+		}
+		g.grpcSubnetworks = &GRPCSubnetworks{s: s, c: c}
+	}
+	{
+		c, err := compute.NewTargetHttpProxiesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewTargetHttpProxiesRESTClient: %w", err)
+		}
+		g.grpcTargetHttpProxies = &GRPCTargetHttpProxies{s: s, c: c}
+	}
+	{
+		c, err := compute.NewRegionTargetHttpProxiesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewRegionTargetHttpProxiesRESTClient: %w", err)
+		}
+		g.grpcRegionTargetHttpProxies = &GRPCRegionTargetHttpProxies{s: s, c: c}
+	}
+	{
+		c, err := compute.NewTargetHttpsProxiesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewTargetHttpsProxiesRESTClient: %w", err)
+		}
+		g.grpcTargetHttpsProxies = &GRPCTargetHttpsProxies{s: s, c: c}
+	}
+	{
+		c, err := compute.NewRegionTargetHttpsProxiesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewRegionTargetHttpsProxiesRESTClient: %w", err)
+		}
+		g.grpcRegionTargetHttpsProxies = &GRPCRegionTargetHttpsProxies{s: s, c: c}
+	}
+	{
+		c, err := compute.NewTargetPoolsRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewTargetPoolsRESTClient: %w", err)
+		}
+		g.grpcTargetPools = &GRPCTargetPools{s: s, c: c}
+	}
+	{
+		c, err := compute.NewTargetTcpProxiesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewTargetTcpProxiesRESTClient: %w", err)
+		}
+		g.grpcTargetTcpProxies = &GRPCTargetTcpProxies{s: s, c: c}
+	}
+	{
+		c, err := compute.NewUrlMapsRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewUrlMapsRESTClient: %w", err)
+		}
+		g.grpcUrlMaps = &GRPCUrlMaps{s: s, c: c}
+	}
+	{
+		c, err := compute.NewRegionUrlMapsRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewRegionUrlMapsRESTClient: %w", err)
+		}
+		g.grpcRegionUrlMaps = &GRPCRegionUrlMaps{s: s, c: c}
+	}
+	{
+		c, err := compute.NewZonesRESTClient(ctx, opts...)
+		if err != nil {
+			g.Close()
+			return nil, fmt.Errorf("compute.NewZonesRESTClient: %w", err)
+		}
+		g.grpcZones = &GRPCZones{s: s, c: c}
+	}
+	return g, nil
+}
+
+// Close releases the compute clients.
+func (g *GRPCGCE) Close() error {
+	var errs []error
+	if g.grpcAddresses != nil {
+		if err := g.grpcAddresses.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcGlobalAddresses != nil {
+		if err := g.grpcGlobalAddresses.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcBackendServices != nil {
+		if err := g.grpcBackendServices.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcRegionBackendServices != nil {
+		if err := g.grpcRegionBackendServices.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcDisks != nil {
+		if err := g.grpcDisks.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcRegionDisks != nil {
+		if err := g.grpcRegionDisks.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcFirewalls != nil {
+		if err := g.grpcFirewalls.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcForwardingRules != nil {
+		if err := g.grpcForwardingRules.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcGlobalForwardingRules != nil {
+		if err := g.grpcGlobalForwardingRules.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcHealthChecks != nil {
+		if err := g.grpcHealthChecks.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcRegionHealthChecks != nil {
+		if err := g.grpcRegionHealthChecks.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcInstanceGroups != nil {
+		if err := g.grpcInstanceGroups.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcInstances != nil {
+		if err := g.grpcInstances.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcInstanceGroupManagers != nil {
+		if err := g.grpcInstanceGroupManagers.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcInstanceTemplates != nil {
+		if err := g.grpcInstanceTemplates.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcInterconnects != nil {
+		if err := g.grpcInterconnects.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcInterconnectAttachments != nil {
+		if err := g.grpcInterconnectAttachments.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcImages != nil {
+		if err := g.grpcImages.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcNetworks != nil {
+		if err := g.grpcNetworks.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcNetworkEndpointGroups != nil {
+		if err := g.grpcNetworkEndpointGroups.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcGlobalNetworkEndpointGroups != nil {
+		if err := g.grpcGlobalNetworkEndpointGroups.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcRegionNetworkEndpointGroups != nil {
+		if err := g.grpcRegionNetworkEndpointGroups.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcPublicAdvertisedPrefixes != nil {
+		if err := g.grpcPublicAdvertisedPrefixes.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcPublicDelegatedPrefixes != nil {
+		if err := g.grpcPublicDelegatedPrefixes.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcGlobalPublicDelegatedPrefixes != nil {
+		if err := g.grpcGlobalPublicDelegatedPrefixes.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcRegions != nil {
+		if err := g.grpcRegions.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcReservations != nil {
+		if err := g.grpcReservations.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcRouters != nil {
+		if err := g.grpcRouters.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcRoutes != nil {
+		if err := g.grpcRoutes.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcSecurityPolicies != nil {
+		if err := g.grpcSecurityPolicies.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcServiceAttachments != nil {
+		if err := g.grpcServiceAttachments.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcSslCertificates != nil {
+		if err := g.grpcSslCertificates.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcRegionSslCertificates != nil {
+		if err := g.grpcRegionSslCertificates.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcSslPolicies != nil {
+		if err := g.grpcSslPolicies.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcRegionSslPolicies != nil {
+		if err := g.grpcRegionSslPolicies.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcSubnetworks != nil {
+		if err := g.grpcSubnetworks.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcTargetHttpProxies != nil {
+		if err := g.grpcTargetHttpProxies.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcRegionTargetHttpProxies != nil {
+		if err := g.grpcRegionTargetHttpProxies.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcTargetHttpsProxies != nil {
+		if err := g.grpcTargetHttpsProxies.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcRegionTargetHttpsProxies != nil {
+		if err := g.grpcRegionTargetHttpsProxies.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcTargetPools != nil {
+		if err := g.grpcTargetPools.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcTargetTcpProxies != nil {
+		if err := g.grpcTargetTcpProxies.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcUrlMaps != nil {
+		if err := g.grpcUrlMaps.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcRegionUrlMaps != nil {
+		if err := g.grpcRegionUrlMaps.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if g.grpcZones != nil {
+		if err := g.grpcZones.c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("GRPCGCE.Close(): %v", errs)
+	}
+	return nil
+}
+
+// Addresses returns the interface for the ga Addresses.
+func (g *GRPCGCE) Addresses() Addresses {
+	return g.grpcAddresses
+}
+
+// GlobalAddresses returns the interface for the ga GlobalAddresses.
+func (g *GRPCGCE) GlobalAddresses() GlobalAddresses {
+	return g.grpcGlobalAddresses
+}
+
+// BackendServices returns the interface for the ga BackendServices.
+func (g *GRPCGCE) BackendServices() BackendServices {
+	return g.grpcBackendServices
+}
+
+// RegionBackendServices returns the interface for the ga RegionBackendServices.
+func (g *GRPCGCE) RegionBackendServices() RegionBackendServices {
+	return g.grpcRegionBackendServices
+}
+
+// Disks returns the interface for the ga Disks.
+func (g *GRPCGCE) Disks() Disks {
+	return g.grpcDisks
+}
+
+// RegionDisks returns the interface for the ga RegionDisks.
+func (g *GRPCGCE) RegionDisks() RegionDisks {
+	return g.grpcRegionDisks
+}
+
+// Firewalls returns the interface for the ga Firewalls.
+func (g *GRPCGCE) Firewalls() Firewalls {
+	return g.grpcFirewalls
+}
+
+// ForwardingRules returns the interface for the ga ForwardingRules.
+func (g *GRPCGCE) ForwardingRules() ForwardingRules {
+	return g.grpcForwardingRules
+}
+
+// GlobalForwardingRules returns the interface for the ga GlobalForwardingRules.
+func (g *GRPCGCE) GlobalForwardingRules() GlobalForwardingRules {
+	return g.grpcGlobalForwardingRules
+}
+
+// HealthChecks returns the interface for the ga HealthChecks.
+func (g *GRPCGCE) HealthChecks() HealthChecks {
+	return g.grpcHealthChecks
+}
+
+// RegionHealthChecks returns the interface for the ga RegionHealthChecks.
+func (g *GRPCGCE) RegionHealthChecks() RegionHealthChecks {
+	return g.grpcRegionHealthChecks
+}
+
+// InstanceGroups returns the interface for the ga InstanceGroups.
+func (g *GRPCGCE) InstanceGroups() InstanceGroups {
+	return g.grpcInstanceGroups
+}
+
+// Instances returns the interface for the ga Instances.
+func (g *GRPCGCE) Instances() Instances {
+	return g.grpcInstances
+}
+
+// InstanceGroupManagers returns the interface for the ga InstanceGroupManagers.
+func (g *GRPCGCE) InstanceGroupManagers() InstanceGroupManagers {
+	return g.grpcInstanceGroupManagers
+}
+
+// InstanceTemplates returns the interface for the ga InstanceTemplates.
+func (g *GRPCGCE) InstanceTemplates() InstanceTemplates {
+	return g.grpcInstanceTemplates
+}
+
+// Interconnects returns the interface for the ga Interconnects.
+func (g *GRPCGCE) Interconnects() Interconnects {
+	return g.grpcInterconnects
+}
+
+// InterconnectAttachments returns the interface for the ga InterconnectAttachments.
+func (g *GRPCGCE) InterconnectAttachments() InterconnectAttachments {
+	return g.grpcInterconnectAttachments
+}
+
+// Images returns the interface for the ga Images.
+func (g *GRPCGCE) Images() Images {
+	return g.grpcImages
+}
+
+// Networks returns the interface for the ga Networks.
+func (g *GRPCGCE) Networks() Networks {
+	return g.grpcNetworks
+}
+
+// NetworkEndpointGroups returns the interface for the ga NetworkEndpointGroups.
+func (g *GRPCGCE) NetworkEndpointGroups() NetworkEndpointGroups {
+	return g.grpcNetworkEndpointGroups
+}
+
+// GlobalNetworkEndpointGroups returns the interface for the ga GlobalNetworkEndpointGroups.
+func (g *GRPCGCE) GlobalNetworkEndpointGroups() GlobalNetworkEndpointGroups {
+	return g.grpcGlobalNetworkEndpointGroups
+}
+
+// RegionNetworkEndpointGroups returns the interface for the ga RegionNetworkEndpointGroups.
+func (g *GRPCGCE) RegionNetworkEndpointGroups() RegionNetworkEndpointGroups {
+	return g.grpcRegionNetworkEndpointGroups
+}
+
+// PublicAdvertisedPrefixes returns the interface for the ga PublicAdvertisedPrefixes.
+func (g *GRPCGCE) PublicAdvertisedPrefixes() PublicAdvertisedPrefixes {
+	return g.grpcPublicAdvertisedPrefixes
+}
+
+// PublicDelegatedPrefixes returns the interface for the ga PublicDelegatedPrefixes.
+func (g *GRPCGCE) PublicDelegatedPrefixes() PublicDelegatedPrefixes {
+	return g.grpcPublicDelegatedPrefixes
+}
+
+// GlobalPublicDelegatedPrefixes returns the interface for the ga GlobalPublicDelegatedPrefixes.
+func (g *GRPCGCE) GlobalPublicDelegatedPrefixes() GlobalPublicDelegatedPrefixes {
+	return g.grpcGlobalPublicDelegatedPrefixes
+}
+
+// Regions returns the interface for the ga Regions.
+func (g *GRPCGCE) Regions() Regions {
+	return g.grpcRegions
+}
+
+// Reservations returns the interface for the ga Reservations.
+func (g *GRPCGCE) Reservations() Reservations {
+	return g.grpcReservations
+}
+
+// Routers returns the interface for the ga Routers.
+func (g *GRPCGCE) Routers() Routers {
+	return g.grpcRouters
+}
+
+// Routes returns the interface for the ga Routes.
+func (g *GRPCGCE) Routes() Routes {
+	return g.grpcRoutes
+}
+
+// SecurityPolicies returns the interface for the ga SecurityPolicies.
+func (g *GRPCGCE) SecurityPolicies() SecurityPolicies {
+	return g.grpcSecurityPolicies
+}
+
+// ServiceAttachments returns the interface for the ga ServiceAttachments.
+func (g *GRPCGCE) ServiceAttachments() ServiceAttachments {
+	return g.grpcServiceAttachments
+}
+
+// SslCertificates returns the interface for the ga SslCertificates.
+func (g *GRPCGCE) SslCertificates() SslCertificates {
+	return g.grpcSslCertificates
+}
+
+// RegionSslCertificates returns the interface for the ga RegionSslCertificates.
+func (g *GRPCGCE) RegionSslCertificates() RegionSslCertificates {
+	return g.grpcRegionSslCertificates
+}
+
+// SslPolicies returns the interface for the ga SslPolicies.
+func (g *GRPCGCE) SslPolicies() SslPolicies {
+	return g.grpcSslPolicies
+}
+
+// RegionSslPolicies returns the interface for the ga RegionSslPolicies.
+func (g *GRPCGCE) RegionSslPolicies() RegionSslPolicies {
+	return g.grpcRegionSslPolicies
+}
+
+// Subnetworks returns the interface for the ga Subnetworks.
+func (g *GRPCGCE) Subnetworks() Subnetworks {
+	return g.grpcSubnetworks
+}
+
+// TargetHttpProxies returns the interface for the ga TargetHttpProxies.
+func (g *GRPCGCE) TargetHttpProxies() TargetHttpProxies {
+	return g.grpcTargetHttpProxies
+}
+
+// RegionTargetHttpProxies returns the interface for the ga RegionTargetHttpProxies.
+func (g *GRPCGCE) RegionTargetHttpProxies() RegionTargetHttpProxies {
+	return g.grpcRegionTargetHttpProxies
+}
+
+// TargetHttpsProxies returns the interface for the ga TargetHttpsProxies.
+func (g *GRPCGCE) TargetHttpsProxies() TargetHttpsProxies {
+	return g.grpcTargetHttpsProxies
+}
+
+// RegionTargetHttpsProxies returns the interface for the ga RegionTargetHttpsProxies.
+func (g *GRPCGCE) RegionTargetHttpsProxies() RegionTargetHttpsProxies {
+	return g.grpcRegionTargetHttpsProxies
+}
+
+// TargetPools returns the interface for the ga TargetPools.
+func (g *GRPCGCE) TargetPools() TargetPools {
+	return g.grpcTargetPools
+}
+
+// TargetTcpProxies returns the interface for the ga TargetTcpProxies.
+func (g *GRPCGCE) TargetTcpProxies() TargetTcpProxies {
+	return g.grpcTargetTcpProxies
+}
+
+// UrlMaps returns the interface for the ga UrlMaps.
+func (g *GRPCGCE) UrlMaps() UrlMaps {
+	return g.grpcUrlMaps
+}
+
+// RegionUrlMaps returns the interface for the ga RegionUrlMaps.
+func (g *GRPCGCE) RegionUrlMaps() RegionUrlMaps {
+	return g.grpcRegionUrlMaps
+}
+
+// Zones returns the interface for the ga Zones.
+func (g *GRPCGCE) Zones() Zones {
+	return g.grpcZones
+}
@@ -637,6 +637,69 @@ func TestForwardingRulesGroup(t *testing.T) {
 	}
 }
 
+func TestFutureReservationsGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyAlpha := meta.ZonalKey("key-alpha", "location")
+	key = keyAlpha
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.AlphaFutureReservations().Get(ctx, key); err == nil {
+		t.Errorf("AlphaFutureReservations().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &alpha.FutureReservation{}
+		if err := mock.AlphaFutureReservations().Insert(ctx, keyAlpha, obj); err != nil {
+			t.Errorf("AlphaFutureReservations().Insert(%v, %v, %v) = %v; want nil", ctx, keyAlpha, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.AlphaFutureReservations().Get(ctx, key); err != nil {
+		t.Errorf("AlphaFutureReservations().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockAlphaFutureReservations.Objects[*keyAlpha] = mock.MockAlphaFutureReservations.Obj(&alpha.FutureReservation{Name: keyAlpha.Name})
+	want := map[string]bool{
+		"key-alpha": true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.AlphaFutureReservations().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("AlphaFutureReservations().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("AlphaFutureReservations().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.AlphaFutureReservations().Delete(ctx, keyAlpha); err != nil {
+		t.Errorf("AlphaFutureReservations().Delete(%v, %v) = %v; want nil", ctx, keyAlpha, err)
+	}
+
+	// Delete not found.
+	if err := mock.AlphaFutureReservations().Delete(ctx, keyAlpha); err == nil {
+		t.Errorf("AlphaFutureReservations().Delete(%v, %v) = nil; want error", ctx, keyAlpha)
+	}
+}
+
 func TestGlobalAddressesGroup(t *testing.T) {
 	t.Parallel()
 
@@ -907,6 +970,276 @@ func TestGlobalForwardingRulesGroup(t *testing.T) {
 	}
 }
 
+func TestGlobalNetworkEndpointGroupsGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyAlpha := meta.GlobalKey("key-alpha")
+	key = keyAlpha
+	keyBeta := meta.GlobalKey("key-beta")
+	key = keyBeta
+	keyGA := meta.GlobalKey("key-ga")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.AlphaGlobalNetworkEndpointGroups().Get(ctx, key); err == nil {
+		t.Errorf("AlphaGlobalNetworkEndpointGroups().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.BetaGlobalNetworkEndpointGroups().Get(ctx, key); err == nil {
+		t.Errorf("BetaGlobalNetworkEndpointGroups().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.GlobalNetworkEndpointGroups().Get(ctx, key); err == nil {
+		t.Errorf("GlobalNetworkEndpointGroups().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &alpha.NetworkEndpointGroup{}
+		if err := mock.AlphaGlobalNetworkEndpointGroups().Insert(ctx, keyAlpha, obj); err != nil {
+			t.Errorf("AlphaGlobalNetworkEndpointGroups().Insert(%v, %v, %v) = %v; want nil", ctx, keyAlpha, obj, err)
+		}
+	}
+	{
+		obj := &beta.NetworkEndpointGroup{}
+		if err := mock.BetaGlobalNetworkEndpointGroups().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaGlobalNetworkEndpointGroups().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+	{
+		obj := &ga.NetworkEndpointGroup{}
+		if err := mock.GlobalNetworkEndpointGroups().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("GlobalNetworkEndpointGroups().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.AlphaGlobalNetworkEndpointGroups().Get(ctx, key); err != nil {
+		t.Errorf("AlphaGlobalNetworkEndpointGroups().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.BetaGlobalNetworkEndpointGroups().Get(ctx, key); err != nil {
+		t.Errorf("BetaGlobalNetworkEndpointGroups().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.GlobalNetworkEndpointGroups().Get(ctx, key); err != nil {
+		t.Errorf("GlobalNetworkEndpointGroups().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockAlphaGlobalNetworkEndpointGroups.Objects[*keyAlpha] = mock.MockAlphaGlobalNetworkEndpointGroups.Obj(&alpha.NetworkEndpointGroup{Name: keyAlpha.Name})
+	mock.MockBetaGlobalNetworkEndpointGroups.Objects[*keyBeta] = mock.MockBetaGlobalNetworkEndpointGroups.Obj(&beta.NetworkEndpointGroup{Name: keyBeta.Name})
+	mock.MockGlobalNetworkEndpointGroups.Objects[*keyGA] = mock.MockGlobalNetworkEndpointGroups.Obj(&ga.NetworkEndpointGroup{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-alpha": true,
+		"key-beta":  true,
+		"key-ga":    true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.AlphaGlobalNetworkEndpointGroups().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("AlphaGlobalNetworkEndpointGroups().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("AlphaGlobalNetworkEndpointGroups().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.BetaGlobalNetworkEndpointGroups().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("BetaGlobalNetworkEndpointGroups().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BetaGlobalNetworkEndpointGroups().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.GlobalNetworkEndpointGroups().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("GlobalNetworkEndpointGroups().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("GlobalNetworkEndpointGroups().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.AlphaGlobalNetworkEndpointGroups().Delete(ctx, keyAlpha); err != nil {
+		t.Errorf("AlphaGlobalNetworkEndpointGroups().Delete(%v, %v) = %v; want nil", ctx, keyAlpha, err)
+	}
+	if err := mock.BetaGlobalNetworkEndpointGroups().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaGlobalNetworkEndpointGroups().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
+	if err := mock.GlobalNetworkEndpointGroups().Delete(ctx, keyGA); err != nil {
+		t.Errorf("GlobalNetworkEndpointGroups().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.AlphaGlobalNetworkEndpointGroups().Delete(ctx, keyAlpha); err == nil {
+		t.Errorf("AlphaGlobalNetworkEndpointGroups().Delete(%v, %v) = nil; want error", ctx, keyAlpha)
+	}
+	if err := mock.BetaGlobalNetworkEndpointGroups().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaGlobalNetworkEndpointGroups().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
+	if err := mock.GlobalNetworkEndpointGroups().Delete(ctx, keyGA); err == nil {
+		t.Errorf("GlobalNetworkEndpointGroups().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
+func TestGlobalPublicDelegatedPrefixesGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyAlpha := meta.GlobalKey("key-alpha")
+	key = keyAlpha
+	keyBeta := meta.GlobalKey("key-beta")
+	key = keyBeta
+	keyGA := meta.GlobalKey("key-ga")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.AlphaGlobalPublicDelegatedPrefixes().Get(ctx, key); err == nil {
+		t.Errorf("AlphaGlobalPublicDelegatedPrefixes().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.BetaGlobalPublicDelegatedPrefixes().Get(ctx, key); err == nil {
+		t.Errorf("BetaGlobalPublicDelegatedPrefixes().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.GlobalPublicDelegatedPrefixes().Get(ctx, key); err == nil {
+		t.Errorf("GlobalPublicDelegatedPrefixes().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &alpha.PublicDelegatedPrefix{}
+		if err := mock.AlphaGlobalPublicDelegatedPrefixes().Insert(ctx, keyAlpha, obj); err != nil {
+			t.Errorf("AlphaGlobalPublicDelegatedPrefixes().Insert(%v, %v, %v) = %v; want nil", ctx, keyAlpha, obj, err)
+		}
+	}
+	{
+		obj := &beta.PublicDelegatedPrefix{}
+		if err := mock.BetaGlobalPublicDelegatedPrefixes().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaGlobalPublicDelegatedPrefixes().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+	{
+		obj := &ga.PublicDelegatedPrefix{}
+		if err := mock.GlobalPublicDelegatedPrefixes().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("GlobalPublicDelegatedPrefixes().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.AlphaGlobalPublicDelegatedPrefixes().Get(ctx, key); err != nil {
+		t.Errorf("AlphaGlobalPublicDelegatedPrefixes().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.BetaGlobalPublicDelegatedPrefixes().Get(ctx, key); err != nil {
+		t.Errorf("BetaGlobalPublicDelegatedPrefixes().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.GlobalPublicDelegatedPrefixes().Get(ctx, key); err != nil {
+		t.Errorf("GlobalPublicDelegatedPrefixes().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockAlphaGlobalPublicDelegatedPrefixes.Objects[*keyAlpha] = mock.MockAlphaGlobalPublicDelegatedPrefixes.Obj(&alpha.PublicDelegatedPrefix{Name: keyAlpha.Name})
+	mock.MockBetaGlobalPublicDelegatedPrefixes.Objects[*keyBeta] = mock.MockBetaGlobalPublicDelegatedPrefixes.Obj(&beta.PublicDelegatedPrefix{Name: keyBeta.Name})
+	mock.MockGlobalPublicDelegatedPrefixes.Objects[*keyGA] = mock.MockGlobalPublicDelegatedPrefixes.Obj(&ga.PublicDelegatedPrefix{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-alpha": true,
+		"key-beta":  true,
+		"key-ga":    true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.AlphaGlobalPublicDelegatedPrefixes().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("AlphaGlobalPublicDelegatedPrefixes().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("AlphaGlobalPublicDelegatedPrefixes().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.BetaGlobalPublicDelegatedPrefixes().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("BetaGlobalPublicDelegatedPrefixes().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BetaGlobalPublicDelegatedPrefixes().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.GlobalPublicDelegatedPrefixes().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("GlobalPublicDelegatedPrefixes().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("GlobalPublicDelegatedPrefixes().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.AlphaGlobalPublicDelegatedPrefixes().Delete(ctx, keyAlpha); err != nil {
+		t.Errorf("AlphaGlobalPublicDelegatedPrefixes().Delete(%v, %v) = %v; want nil", ctx, keyAlpha, err)
+	}
+	if err := mock.BetaGlobalPublicDelegatedPrefixes().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaGlobalPublicDelegatedPrefixes().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
+	if err := mock.GlobalPublicDelegatedPrefixes().Delete(ctx, keyGA); err != nil {
+		t.Errorf("GlobalPublicDelegatedPrefixes().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.AlphaGlobalPublicDelegatedPrefixes().Delete(ctx, keyAlpha); err == nil {
+		t.Errorf("AlphaGlobalPublicDelegatedPrefixes().Delete(%v, %v) = nil; want error", ctx, keyAlpha)
+	}
+	if err := mock.BetaGlobalPublicDelegatedPrefixes().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaGlobalPublicDelegatedPrefixes().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
+	if err := mock.GlobalPublicDelegatedPrefixes().Delete(ctx, keyGA); err == nil {
+		t.Errorf("GlobalPublicDelegatedPrefixes().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
 func TestHealthChecksGroup(t *testing.T) {
 	t.Parallel()
 
@@ -1627,7 +1960,7 @@ func TestInstancesGroup(t *testing.T) {
 	}
 }
 
-func TestNetworkEndpointGroupsGroup(t *testing.T) {
+func TestInterconnectAttachmentsGroup(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
@@ -1635,42 +1968,168 @@ func TestNetworkEndpointGroupsGroup(t *testing.T) {
 	mock := NewMockGCE(pr)
 
 	var key *meta.Key
-	keyAlpha := meta.ZonalKey("key-alpha", "location")
-	key = keyAlpha
-	keyBeta := meta.ZonalKey("key-beta", "location")
-	key = keyBeta
-	keyGA := meta.ZonalKey("key-ga", "location")
+	keyGA := meta.RegionalKey("key-ga", "location")
 	key = keyGA
 	// Ignore unused variables.
 	_, _, _ = ctx, mock, key
 
 	// Get not found.
-	if _, err := mock.AlphaNetworkEndpointGroups().Get(ctx, key); err == nil {
-		t.Errorf("AlphaNetworkEndpointGroups().Get(%v, %v) = _, nil; want error", ctx, key)
-	}
-	if _, err := mock.BetaNetworkEndpointGroups().Get(ctx, key); err == nil {
-		t.Errorf("BetaNetworkEndpointGroups().Get(%v, %v) = _, nil; want error", ctx, key)
-	}
-	if _, err := mock.NetworkEndpointGroups().Get(ctx, key); err == nil {
-		t.Errorf("NetworkEndpointGroups().Get(%v, %v) = _, nil; want error", ctx, key)
+	if _, err := mock.InterconnectAttachments().Get(ctx, key); err == nil {
+		t.Errorf("InterconnectAttachments().Get(%v, %v) = _, nil; want error", ctx, key)
 	}
 
 	// Insert.
 	{
-		obj := &alpha.NetworkEndpointGroup{}
-		if err := mock.AlphaNetworkEndpointGroups().Insert(ctx, keyAlpha, obj); err != nil {
-			t.Errorf("AlphaNetworkEndpointGroups().Insert(%v, %v, %v) = %v; want nil", ctx, keyAlpha, obj, err)
+		obj := &ga.InterconnectAttachment{}
+		if err := mock.InterconnectAttachments().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("InterconnectAttachments().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
 		}
 	}
-	{
-		obj := &beta.NetworkEndpointGroup{}
-		if err := mock.BetaNetworkEndpointGroups().Insert(ctx, keyBeta, obj); err != nil {
-			t.Errorf("BetaNetworkEndpointGroups().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
-		}
+
+	// Get across versions.
+	if obj, err := mock.InterconnectAttachments().Get(ctx, key); err != nil {
+		t.Errorf("InterconnectAttachments().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
 	}
-	{
-		obj := &ga.NetworkEndpointGroup{}
-		if err := mock.NetworkEndpointGroups().Insert(ctx, keyGA, obj); err != nil {
+
+	// List.
+	mock.MockInterconnectAttachments.Objects[*keyGA] = mock.MockInterconnectAttachments.Obj(&ga.InterconnectAttachment{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-ga": true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.InterconnectAttachments().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("InterconnectAttachments().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("InterconnectAttachments().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.InterconnectAttachments().Delete(ctx, keyGA); err != nil {
+		t.Errorf("InterconnectAttachments().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.InterconnectAttachments().Delete(ctx, keyGA); err == nil {
+		t.Errorf("InterconnectAttachments().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
+func TestInterconnectsGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyGA := meta.GlobalKey("key-ga")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.Interconnects().Get(ctx, key); err == nil {
+		t.Errorf("Interconnects().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &ga.Interconnect{}
+		if err := mock.Interconnects().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("Interconnects().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.Interconnects().Get(ctx, key); err != nil {
+		t.Errorf("Interconnects().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockInterconnects.Objects[*keyGA] = mock.MockInterconnects.Obj(&ga.Interconnect{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-ga": true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.Interconnects().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("Interconnects().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Interconnects().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.Interconnects().Delete(ctx, keyGA); err != nil {
+		t.Errorf("Interconnects().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.Interconnects().Delete(ctx, keyGA); err == nil {
+		t.Errorf("Interconnects().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
+func TestNetworkEndpointGroupsGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyAlpha := meta.ZonalKey("key-alpha", "location")
+	key = keyAlpha
+	keyBeta := meta.ZonalKey("key-beta", "location")
+	key = keyBeta
+	keyGA := meta.ZonalKey("key-ga", "location")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.AlphaNetworkEndpointGroups().Get(ctx, key); err == nil {
+		t.Errorf("AlphaNetworkEndpointGroups().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.BetaNetworkEndpointGroups().Get(ctx, key); err == nil {
+		t.Errorf("BetaNetworkEndpointGroups().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.NetworkEndpointGroups().Get(ctx, key); err == nil {
+		t.Errorf("NetworkEndpointGroups().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &alpha.NetworkEndpointGroup{}
+		if err := mock.AlphaNetworkEndpointGroups().Insert(ctx, keyAlpha, obj); err != nil {
+			t.Errorf("AlphaNetworkEndpointGroups().Insert(%v, %v, %v) = %v; want nil", ctx, keyAlpha, obj, err)
+		}
+	}
+	{
+		obj := &beta.NetworkEndpointGroup{}
+		if err := mock.BetaNetworkEndpointGroups().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaNetworkEndpointGroups().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+	{
+		obj := &ga.NetworkEndpointGroup{}
+		if err := mock.NetworkEndpointGroups().Insert(ctx, keyGA, obj); err != nil {
 			t.Errorf("NetworkEndpointGroups().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
 		}
 	}
@@ -1991,6 +2450,276 @@ func TestProjectsGroup(t *testing.T) {
 	// Delete not found.
 }
 
+func TestPublicAdvertisedPrefixesGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyAlpha := meta.GlobalKey("key-alpha")
+	key = keyAlpha
+	keyBeta := meta.GlobalKey("key-beta")
+	key = keyBeta
+	keyGA := meta.GlobalKey("key-ga")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.AlphaPublicAdvertisedPrefixes().Get(ctx, key); err == nil {
+		t.Errorf("AlphaPublicAdvertisedPrefixes().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.BetaPublicAdvertisedPrefixes().Get(ctx, key); err == nil {
+		t.Errorf("BetaPublicAdvertisedPrefixes().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.PublicAdvertisedPrefixes().Get(ctx, key); err == nil {
+		t.Errorf("PublicAdvertisedPrefixes().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &alpha.PublicAdvertisedPrefix{}
+		if err := mock.AlphaPublicAdvertisedPrefixes().Insert(ctx, keyAlpha, obj); err != nil {
+			t.Errorf("AlphaPublicAdvertisedPrefixes().Insert(%v, %v, %v) = %v; want nil", ctx, keyAlpha, obj, err)
+		}
+	}
+	{
+		obj := &beta.PublicAdvertisedPrefix{}
+		if err := mock.BetaPublicAdvertisedPrefixes().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaPublicAdvertisedPrefixes().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+	{
+		obj := &ga.PublicAdvertisedPrefix{}
+		if err := mock.PublicAdvertisedPrefixes().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("PublicAdvertisedPrefixes().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.AlphaPublicAdvertisedPrefixes().Get(ctx, key); err != nil {
+		t.Errorf("AlphaPublicAdvertisedPrefixes().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.BetaPublicAdvertisedPrefixes().Get(ctx, key); err != nil {
+		t.Errorf("BetaPublicAdvertisedPrefixes().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.PublicAdvertisedPrefixes().Get(ctx, key); err != nil {
+		t.Errorf("PublicAdvertisedPrefixes().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockAlphaPublicAdvertisedPrefixes.Objects[*keyAlpha] = mock.MockAlphaPublicAdvertisedPrefixes.Obj(&alpha.PublicAdvertisedPrefix{Name: keyAlpha.Name})
+	mock.MockBetaPublicAdvertisedPrefixes.Objects[*keyBeta] = mock.MockBetaPublicAdvertisedPrefixes.Obj(&beta.PublicAdvertisedPrefix{Name: keyBeta.Name})
+	mock.MockPublicAdvertisedPrefixes.Objects[*keyGA] = mock.MockPublicAdvertisedPrefixes.Obj(&ga.PublicAdvertisedPrefix{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-alpha": true,
+		"key-beta":  true,
+		"key-ga":    true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.AlphaPublicAdvertisedPrefixes().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("AlphaPublicAdvertisedPrefixes().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("AlphaPublicAdvertisedPrefixes().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.BetaPublicAdvertisedPrefixes().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("BetaPublicAdvertisedPrefixes().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BetaPublicAdvertisedPrefixes().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.PublicAdvertisedPrefixes().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("PublicAdvertisedPrefixes().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("PublicAdvertisedPrefixes().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.AlphaPublicAdvertisedPrefixes().Delete(ctx, keyAlpha); err != nil {
+		t.Errorf("AlphaPublicAdvertisedPrefixes().Delete(%v, %v) = %v; want nil", ctx, keyAlpha, err)
+	}
+	if err := mock.BetaPublicAdvertisedPrefixes().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaPublicAdvertisedPrefixes().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
+	if err := mock.PublicAdvertisedPrefixes().Delete(ctx, keyGA); err != nil {
+		t.Errorf("PublicAdvertisedPrefixes().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.AlphaPublicAdvertisedPrefixes().Delete(ctx, keyAlpha); err == nil {
+		t.Errorf("AlphaPublicAdvertisedPrefixes().Delete(%v, %v) = nil; want error", ctx, keyAlpha)
+	}
+	if err := mock.BetaPublicAdvertisedPrefixes().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaPublicAdvertisedPrefixes().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
+	if err := mock.PublicAdvertisedPrefixes().Delete(ctx, keyGA); err == nil {
+		t.Errorf("PublicAdvertisedPrefixes().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
+func TestPublicDelegatedPrefixesGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyAlpha := meta.RegionalKey("key-alpha", "location")
+	key = keyAlpha
+	keyBeta := meta.RegionalKey("key-beta", "location")
+	key = keyBeta
+	keyGA := meta.RegionalKey("key-ga", "location")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.AlphaPublicDelegatedPrefixes().Get(ctx, key); err == nil {
+		t.Errorf("AlphaPublicDelegatedPrefixes().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.BetaPublicDelegatedPrefixes().Get(ctx, key); err == nil {
+		t.Errorf("BetaPublicDelegatedPrefixes().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.PublicDelegatedPrefixes().Get(ctx, key); err == nil {
+		t.Errorf("PublicDelegatedPrefixes().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &alpha.PublicDelegatedPrefix{}
+		if err := mock.AlphaPublicDelegatedPrefixes().Insert(ctx, keyAlpha, obj); err != nil {
+			t.Errorf("AlphaPublicDelegatedPrefixes().Insert(%v, %v, %v) = %v; want nil", ctx, keyAlpha, obj, err)
+		}
+	}
+	{
+		obj := &beta.PublicDelegatedPrefix{}
+		if err := mock.BetaPublicDelegatedPrefixes().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaPublicDelegatedPrefixes().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+	{
+		obj := &ga.PublicDelegatedPrefix{}
+		if err := mock.PublicDelegatedPrefixes().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("PublicDelegatedPrefixes().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.AlphaPublicDelegatedPrefixes().Get(ctx, key); err != nil {
+		t.Errorf("AlphaPublicDelegatedPrefixes().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.BetaPublicDelegatedPrefixes().Get(ctx, key); err != nil {
+		t.Errorf("BetaPublicDelegatedPrefixes().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.PublicDelegatedPrefixes().Get(ctx, key); err != nil {
+		t.Errorf("PublicDelegatedPrefixes().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockAlphaPublicDelegatedPrefixes.Objects[*keyAlpha] = mock.MockAlphaPublicDelegatedPrefixes.Obj(&alpha.PublicDelegatedPrefix{Name: keyAlpha.Name})
+	mock.MockBetaPublicDelegatedPrefixes.Objects[*keyBeta] = mock.MockBetaPublicDelegatedPrefixes.Obj(&beta.PublicDelegatedPrefix{Name: keyBeta.Name})
+	mock.MockPublicDelegatedPrefixes.Objects[*keyGA] = mock.MockPublicDelegatedPrefixes.Obj(&ga.PublicDelegatedPrefix{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-alpha": true,
+		"key-beta":  true,
+		"key-ga":    true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.AlphaPublicDelegatedPrefixes().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("AlphaPublicDelegatedPrefixes().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("AlphaPublicDelegatedPrefixes().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.BetaPublicDelegatedPrefixes().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("BetaPublicDelegatedPrefixes().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BetaPublicDelegatedPrefixes().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.PublicDelegatedPrefixes().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("PublicDelegatedPrefixes().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("PublicDelegatedPrefixes().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.AlphaPublicDelegatedPrefixes().Delete(ctx, keyAlpha); err != nil {
+		t.Errorf("AlphaPublicDelegatedPrefixes().Delete(%v, %v) = %v; want nil", ctx, keyAlpha, err)
+	}
+	if err := mock.BetaPublicDelegatedPrefixes().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaPublicDelegatedPrefixes().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
+	if err := mock.PublicDelegatedPrefixes().Delete(ctx, keyGA); err != nil {
+		t.Errorf("PublicDelegatedPrefixes().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.AlphaPublicDelegatedPrefixes().Delete(ctx, keyAlpha); err == nil {
+		t.Errorf("AlphaPublicDelegatedPrefixes().Delete(%v, %v) = nil; want error", ctx, keyAlpha)
+	}
+	if err := mock.BetaPublicDelegatedPrefixes().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaPublicDelegatedPrefixes().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
+	if err := mock.PublicDelegatedPrefixes().Delete(ctx, keyGA); err == nil {
+		t.Errorf("PublicDelegatedPrefixes().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
 func TestRegionBackendServicesGroup(t *testing.T) {
 	t.Parallel()
 
@@ -2182,14 +2911,149 @@ func TestRegionDisksGroup(t *testing.T) {
 	if err := mock.RegionDisks().Delete(ctx, keyGA); err != nil {
 		t.Errorf("RegionDisks().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
 	}
-
-	// Delete not found.
-	if err := mock.RegionDisks().Delete(ctx, keyGA); err == nil {
-		t.Errorf("RegionDisks().Delete(%v, %v) = nil; want error", ctx, keyGA)
+
+	// Delete not found.
+	if err := mock.RegionDisks().Delete(ctx, keyGA); err == nil {
+		t.Errorf("RegionDisks().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
+func TestRegionHealthChecksGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyAlpha := meta.RegionalKey("key-alpha", "location")
+	key = keyAlpha
+	keyBeta := meta.RegionalKey("key-beta", "location")
+	key = keyBeta
+	keyGA := meta.RegionalKey("key-ga", "location")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.AlphaRegionHealthChecks().Get(ctx, key); err == nil {
+		t.Errorf("AlphaRegionHealthChecks().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.BetaRegionHealthChecks().Get(ctx, key); err == nil {
+		t.Errorf("BetaRegionHealthChecks().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.RegionHealthChecks().Get(ctx, key); err == nil {
+		t.Errorf("RegionHealthChecks().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &alpha.HealthCheck{}
+		if err := mock.AlphaRegionHealthChecks().Insert(ctx, keyAlpha, obj); err != nil {
+			t.Errorf("AlphaRegionHealthChecks().Insert(%v, %v, %v) = %v; want nil", ctx, keyAlpha, obj, err)
+		}
+	}
+	{
+		obj := &beta.HealthCheck{}
+		if err := mock.BetaRegionHealthChecks().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaRegionHealthChecks().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+	{
+		obj := &ga.HealthCheck{}
+		if err := mock.RegionHealthChecks().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("RegionHealthChecks().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.AlphaRegionHealthChecks().Get(ctx, key); err != nil {
+		t.Errorf("AlphaRegionHealthChecks().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.BetaRegionHealthChecks().Get(ctx, key); err != nil {
+		t.Errorf("BetaRegionHealthChecks().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.RegionHealthChecks().Get(ctx, key); err != nil {
+		t.Errorf("RegionHealthChecks().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockAlphaRegionHealthChecks.Objects[*keyAlpha] = mock.MockAlphaRegionHealthChecks.Obj(&alpha.HealthCheck{Name: keyAlpha.Name})
+	mock.MockBetaRegionHealthChecks.Objects[*keyBeta] = mock.MockBetaRegionHealthChecks.Obj(&beta.HealthCheck{Name: keyBeta.Name})
+	mock.MockRegionHealthChecks.Objects[*keyGA] = mock.MockRegionHealthChecks.Obj(&ga.HealthCheck{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-alpha": true,
+		"key-beta":  true,
+		"key-ga":    true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.AlphaRegionHealthChecks().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("AlphaRegionHealthChecks().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("AlphaRegionHealthChecks().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.BetaRegionHealthChecks().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("BetaRegionHealthChecks().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BetaRegionHealthChecks().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.RegionHealthChecks().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("RegionHealthChecks().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("RegionHealthChecks().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.AlphaRegionHealthChecks().Delete(ctx, keyAlpha); err != nil {
+		t.Errorf("AlphaRegionHealthChecks().Delete(%v, %v) = %v; want nil", ctx, keyAlpha, err)
+	}
+	if err := mock.BetaRegionHealthChecks().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaRegionHealthChecks().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
+	if err := mock.RegionHealthChecks().Delete(ctx, keyGA); err != nil {
+		t.Errorf("RegionHealthChecks().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.AlphaRegionHealthChecks().Delete(ctx, keyAlpha); err == nil {
+		t.Errorf("AlphaRegionHealthChecks().Delete(%v, %v) = nil; want error", ctx, keyAlpha)
+	}
+	if err := mock.BetaRegionHealthChecks().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaRegionHealthChecks().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
+	if err := mock.RegionHealthChecks().Delete(ctx, keyGA); err == nil {
+		t.Errorf("RegionHealthChecks().Delete(%v, %v) = nil; want error", ctx, keyGA)
 	}
 }
 
-func TestRegionHealthChecksGroup(t *testing.T) {
+func TestRegionNetworkEndpointGroupsGroup(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
@@ -2207,51 +3071,51 @@ func TestRegionHealthChecksGroup(t *testing.T) {
 	_, _, _ = ctx, mock, key
 
 	// Get not found.
-	if _, err := mock.AlphaRegionHealthChecks().Get(ctx, key); err == nil {
-		t.Errorf("AlphaRegionHealthChecks().Get(%v, %v) = _, nil; want error", ctx, key)
+	if _, err := mock.AlphaRegionNetworkEndpointGroups().Get(ctx, key); err == nil {
+		t.Errorf("AlphaRegionNetworkEndpointGroups().Get(%v, %v) = _, nil; want error", ctx, key)
 	}
-	if _, err := mock.BetaRegionHealthChecks().Get(ctx, key); err == nil {
-		t.Errorf("BetaRegionHealthChecks().Get(%v, %v) = _, nil; want error", ctx, key)
+	if _, err := mock.BetaRegionNetworkEndpointGroups().Get(ctx, key); err == nil {
+		t.Errorf("BetaRegionNetworkEndpointGroups().Get(%v, %v) = _, nil; want error", ctx, key)
 	}
-	if _, err := mock.RegionHealthChecks().Get(ctx, key); err == nil {
-		t.Errorf("RegionHealthChecks().Get(%v, %v) = _, nil; want error", ctx, key)
+	if _, err := mock.RegionNetworkEndpointGroups().Get(ctx, key); err == nil {
+		t.Errorf("RegionNetworkEndpointGroups().Get(%v, %v) = _, nil; want error", ctx, key)
 	}
 
 	// Insert.
 	{
-		obj := &alpha.HealthCheck{}
-		if err := mock.AlphaRegionHealthChecks().Insert(ctx, keyAlpha, obj); err != nil {
-			t.Errorf("AlphaRegionHealthChecks().Insert(%v, %v, %v) = %v; want nil", ctx, keyAlpha, obj, err)
+		obj := &alpha.NetworkEndpointGroup{}
+		if err := mock.AlphaRegionNetworkEndpointGroups().Insert(ctx, keyAlpha, obj); err != nil {
+			t.Errorf("AlphaRegionNetworkEndpointGroups().Insert(%v, %v, %v) = %v; want nil", ctx, keyAlpha, obj, err)
 		}
 	}
 	{
-		obj := &beta.HealthCheck{}
-		if err := mock.BetaRegionHealthChecks().Insert(ctx, keyBeta, obj); err != nil {
-			t.Errorf("BetaRegionHealthChecks().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		obj := &beta.NetworkEndpointGroup{}
+		if err := mock.BetaRegionNetworkEndpointGroups().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaRegionNetworkEndpointGroups().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
 		}
 	}
 	{
-		obj := &ga.HealthCheck{}
-		if err := mock.RegionHealthChecks().Insert(ctx, keyGA, obj); err != nil {
-			t.Errorf("RegionHealthChecks().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		obj := &ga.NetworkEndpointGroup{}
+		if err := mock.RegionNetworkEndpointGroups().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("RegionNetworkEndpointGroups().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
 		}
 	}
 
 	// Get across versions.
-	if obj, err := mock.AlphaRegionHealthChecks().Get(ctx, key); err != nil {
-		t.Errorf("AlphaRegionHealthChecks().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	if obj, err := mock.AlphaRegionNetworkEndpointGroups().Get(ctx, key); err != nil {
+		t.Errorf("AlphaRegionNetworkEndpointGroups().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
 	}
-	if obj, err := mock.BetaRegionHealthChecks().Get(ctx, key); err != nil {
-		t.Errorf("BetaRegionHealthChecks().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	if obj, err := mock.BetaRegionNetworkEndpointGroups().Get(ctx, key); err != nil {
+		t.Errorf("BetaRegionNetworkEndpointGroups().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
 	}
-	if obj, err := mock.RegionHealthChecks().Get(ctx, key); err != nil {
-		t.Errorf("RegionHealthChecks().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	if obj, err := mock.RegionNetworkEndpointGroups().Get(ctx, key); err != nil {
+		t.Errorf("RegionNetworkEndpointGroups().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
 	}
 
 	// List.
-	mock.MockAlphaRegionHealthChecks.Objects[*keyAlpha] = mock.MockAlphaRegionHealthChecks.Obj(&alpha.HealthCheck{Name: keyAlpha.Name})
-	mock.MockBetaRegionHealthChecks.Objects[*keyBeta] = mock.MockBetaRegionHealthChecks.Obj(&beta.HealthCheck{Name: keyBeta.Name})
-	mock.MockRegionHealthChecks.Objects[*keyGA] = mock.MockRegionHealthChecks.Obj(&ga.HealthCheck{Name: keyGA.Name})
+	mock.MockAlphaRegionNetworkEndpointGroups.Objects[*keyAlpha] = mock.MockAlphaRegionNetworkEndpointGroups.Obj(&alpha.NetworkEndpointGroup{Name: keyAlpha.Name})
+	mock.MockBetaRegionNetworkEndpointGroups.Objects[*keyBeta] = mock.MockBetaRegionNetworkEndpointGroups.Obj(&beta.NetworkEndpointGroup{Name: keyBeta.Name})
+	mock.MockRegionNetworkEndpointGroups.Objects[*keyGA] = mock.MockRegionNetworkEndpointGroups.Obj(&ga.NetworkEndpointGroup{Name: keyGA.Name})
 	want := map[string]bool{
 		"key-alpha": true,
 		"key-beta":  true,
@@ -2259,68 +3123,68 @@ func TestRegionHealthChecksGroup(t *testing.T) {
 	}
 	_ = want // ignore unused variables.
 	{
-		objs, err := mock.AlphaRegionHealthChecks().List(ctx, location, filter.None)
+		objs, err := mock.AlphaRegionNetworkEndpointGroups().List(ctx, location, filter.None)
 		if err != nil {
-			t.Errorf("AlphaRegionHealthChecks().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+			t.Errorf("AlphaRegionNetworkEndpointGroups().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
 		} else {
 			got := map[string]bool{}
 			for _, obj := range objs {
 				got[obj.Name] = true
 			}
 			if !reflect.DeepEqual(got, want) {
-				t.Errorf("AlphaRegionHealthChecks().List(); got %+v, want %+v", got, want)
+				t.Errorf("AlphaRegionNetworkEndpointGroups().List(); got %+v, want %+v", got, want)
 			}
 		}
 	}
 	{
-		objs, err := mock.BetaRegionHealthChecks().List(ctx, location, filter.None)
+		objs, err := mock.BetaRegionNetworkEndpointGroups().List(ctx, location, filter.None)
 		if err != nil {
-			t.Errorf("BetaRegionHealthChecks().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+			t.Errorf("BetaRegionNetworkEndpointGroups().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
 		} else {
 			got := map[string]bool{}
 			for _, obj := range objs {
 				got[obj.Name] = true
 			}
 			if !reflect.DeepEqual(got, want) {
-				t.Errorf("BetaRegionHealthChecks().List(); got %+v, want %+v", got, want)
+				t.Errorf("BetaRegionNetworkEndpointGroups().List(); got %+v, want %+v", got, want)
 			}
 		}
 	}
 	{
-		objs, err := mock.RegionHealthChecks().List(ctx, location, filter.None)
+		objs, err := mock.RegionNetworkEndpointGroups().List(ctx, location, filter.None)
 		if err != nil {
-			t.Errorf("RegionHealthChecks().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+			t.Errorf("RegionNetworkEndpointGroups().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
 		} else {
 			got := map[string]bool{}
 			for _, obj := range objs {
 				got[obj.Name] = true
 			}
 			if !reflect.DeepEqual(got, want) {
-				t.Errorf("RegionHealthChecks().List(); got %+v, want %+v", got, want)
+				t.Errorf("RegionNetworkEndpointGroups().List(); got %+v, want %+v", got, want)
 			}
 		}
 	}
 
 	// Delete across versions.
-	if err := mock.AlphaRegionHealthChecks().Delete(ctx, keyAlpha); err != nil {
-		t.Errorf("AlphaRegionHealthChecks().Delete(%v, %v) = %v; want nil", ctx, keyAlpha, err)
+	if err := mock.AlphaRegionNetworkEndpointGroups().Delete(ctx, keyAlpha); err != nil {
+		t.Errorf("AlphaRegionNetworkEndpointGroups().Delete(%v, %v) = %v; want nil", ctx, keyAlpha, err)
 	}
-	if err := mock.BetaRegionHealthChecks().Delete(ctx, keyBeta); err != nil {
-		t.Errorf("BetaRegionHealthChecks().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	if err := mock.BetaRegionNetworkEndpointGroups().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaRegionNetworkEndpointGroups().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
 	}
-	if err := mock.RegionHealthChecks().Delete(ctx, keyGA); err != nil {
-		t.Errorf("RegionHealthChecks().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	if err := mock.RegionNetworkEndpointGroups().Delete(ctx, keyGA); err != nil {
+		t.Errorf("RegionNetworkEndpointGroups().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
 	}
 
 	// Delete not found.
-	if err := mock.AlphaRegionHealthChecks().Delete(ctx, keyAlpha); err == nil {
-		t.Errorf("AlphaRegionHealthChecks().Delete(%v, %v) = nil; want error", ctx, keyAlpha)
+	if err := mock.AlphaRegionNetworkEndpointGroups().Delete(ctx, keyAlpha); err == nil {
+		t.Errorf("AlphaRegionNetworkEndpointGroups().Delete(%v, %v) = nil; want error", ctx, keyAlpha)
 	}
-	if err := mock.BetaRegionHealthChecks().Delete(ctx, keyBeta); err == nil {
-		t.Errorf("BetaRegionHealthChecks().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	if err := mock.BetaRegionNetworkEndpointGroups().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaRegionNetworkEndpointGroups().Delete(%v, %v) = nil; want error", ctx, keyBeta)
 	}
-	if err := mock.RegionHealthChecks().Delete(ctx, keyGA); err == nil {
-		t.Errorf("RegionHealthChecks().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	if err := mock.RegionNetworkEndpointGroups().Delete(ctx, keyGA); err == nil {
+		t.Errorf("RegionNetworkEndpointGroups().Delete(%v, %v) = nil; want error", ctx, keyGA)
 	}
 }
 
@@ -2522,6 +3386,141 @@ func TestRegionSslCertificatesGroup(t *testing.T) {
 	}
 }
 
+func TestRegionSslPoliciesGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyAlpha := meta.RegionalKey("key-alpha", "location")
+	key = keyAlpha
+	keyBeta := meta.RegionalKey("key-beta", "location")
+	key = keyBeta
+	keyGA := meta.RegionalKey("key-ga", "location")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.AlphaRegionSslPolicies().Get(ctx, key); err == nil {
+		t.Errorf("AlphaRegionSslPolicies().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.BetaRegionSslPolicies().Get(ctx, key); err == nil {
+		t.Errorf("BetaRegionSslPolicies().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.RegionSslPolicies().Get(ctx, key); err == nil {
+		t.Errorf("RegionSslPolicies().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &alpha.SslPolicy{}
+		if err := mock.AlphaRegionSslPolicies().Insert(ctx, keyAlpha, obj); err != nil {
+			t.Errorf("AlphaRegionSslPolicies().Insert(%v, %v, %v) = %v; want nil", ctx, keyAlpha, obj, err)
+		}
+	}
+	{
+		obj := &beta.SslPolicy{}
+		if err := mock.BetaRegionSslPolicies().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaRegionSslPolicies().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+	{
+		obj := &ga.SslPolicy{}
+		if err := mock.RegionSslPolicies().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("RegionSslPolicies().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.AlphaRegionSslPolicies().Get(ctx, key); err != nil {
+		t.Errorf("AlphaRegionSslPolicies().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.BetaRegionSslPolicies().Get(ctx, key); err != nil {
+		t.Errorf("BetaRegionSslPolicies().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.RegionSslPolicies().Get(ctx, key); err != nil {
+		t.Errorf("RegionSslPolicies().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockAlphaRegionSslPolicies.Objects[*keyAlpha] = mock.MockAlphaRegionSslPolicies.Obj(&alpha.SslPolicy{Name: keyAlpha.Name})
+	mock.MockBetaRegionSslPolicies.Objects[*keyBeta] = mock.MockBetaRegionSslPolicies.Obj(&beta.SslPolicy{Name: keyBeta.Name})
+	mock.MockRegionSslPolicies.Objects[*keyGA] = mock.MockRegionSslPolicies.Obj(&ga.SslPolicy{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-alpha": true,
+		"key-beta":  true,
+		"key-ga":    true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.AlphaRegionSslPolicies().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("AlphaRegionSslPolicies().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("AlphaRegionSslPolicies().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.BetaRegionSslPolicies().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("BetaRegionSslPolicies().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BetaRegionSslPolicies().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.RegionSslPolicies().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("RegionSslPolicies().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("RegionSslPolicies().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.AlphaRegionSslPolicies().Delete(ctx, keyAlpha); err != nil {
+		t.Errorf("AlphaRegionSslPolicies().Delete(%v, %v) = %v; want nil", ctx, keyAlpha, err)
+	}
+	if err := mock.BetaRegionSslPolicies().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaRegionSslPolicies().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
+	if err := mock.RegionSslPolicies().Delete(ctx, keyGA); err != nil {
+		t.Errorf("RegionSslPolicies().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.AlphaRegionSslPolicies().Delete(ctx, keyAlpha); err == nil {
+		t.Errorf("AlphaRegionSslPolicies().Delete(%v, %v) = nil; want error", ctx, keyAlpha)
+	}
+	if err := mock.BetaRegionSslPolicies().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaRegionSslPolicies().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
+	if err := mock.RegionSslPolicies().Delete(ctx, keyGA); err == nil {
+		t.Errorf("RegionSslPolicies().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
 func TestRegionTargetHttpProxiesGroup(t *testing.T) {
 	t.Parallel()
 
@@ -2975,6 +3974,141 @@ func TestRegionsGroup(t *testing.T) {
 	// Delete not found.
 }
 
+func TestReservationsGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyAlpha := meta.ZonalKey("key-alpha", "location")
+	key = keyAlpha
+	keyBeta := meta.ZonalKey("key-beta", "location")
+	key = keyBeta
+	keyGA := meta.ZonalKey("key-ga", "location")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.AlphaReservations().Get(ctx, key); err == nil {
+		t.Errorf("AlphaReservations().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.BetaReservations().Get(ctx, key); err == nil {
+		t.Errorf("BetaReservations().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.Reservations().Get(ctx, key); err == nil {
+		t.Errorf("Reservations().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &alpha.Reservation{}
+		if err := mock.AlphaReservations().Insert(ctx, keyAlpha, obj); err != nil {
+			t.Errorf("AlphaReservations().Insert(%v, %v, %v) = %v; want nil", ctx, keyAlpha, obj, err)
+		}
+	}
+	{
+		obj := &beta.Reservation{}
+		if err := mock.BetaReservations().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaReservations().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+	{
+		obj := &ga.Reservation{}
+		if err := mock.Reservations().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("Reservations().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.AlphaReservations().Get(ctx, key); err != nil {
+		t.Errorf("AlphaReservations().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.BetaReservations().Get(ctx, key); err != nil {
+		t.Errorf("BetaReservations().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.Reservations().Get(ctx, key); err != nil {
+		t.Errorf("Reservations().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockAlphaReservations.Objects[*keyAlpha] = mock.MockAlphaReservations.Obj(&alpha.Reservation{Name: keyAlpha.Name})
+	mock.MockBetaReservations.Objects[*keyBeta] = mock.MockBetaReservations.Obj(&beta.Reservation{Name: keyBeta.Name})
+	mock.MockReservations.Objects[*keyGA] = mock.MockReservations.Obj(&ga.Reservation{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-alpha": true,
+		"key-beta":  true,
+		"key-ga":    true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.AlphaReservations().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("AlphaReservations().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("AlphaReservations().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.BetaReservations().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("BetaReservations().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BetaReservations().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.Reservations().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("Reservations().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Reservations().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.AlphaReservations().Delete(ctx, keyAlpha); err != nil {
+		t.Errorf("AlphaReservations().Delete(%v, %v) = %v; want nil", ctx, keyAlpha, err)
+	}
+	if err := mock.BetaReservations().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaReservations().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
+	if err := mock.Reservations().Delete(ctx, keyGA); err != nil {
+		t.Errorf("Reservations().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.AlphaReservations().Delete(ctx, keyAlpha); err == nil {
+		t.Errorf("AlphaReservations().Delete(%v, %v) = nil; want error", ctx, keyAlpha)
+	}
+	if err := mock.BetaReservations().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaReservations().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
+	if err := mock.Reservations().Delete(ctx, keyGA); err == nil {
+		t.Errorf("Reservations().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
 func TestRoutersGroup(t *testing.T) {
 	t.Parallel()
 
@@ -3183,6 +4317,8 @@ func TestSecurityPoliciesGroup(t *testing.T) {
 	var key *meta.Key
 	keyBeta := meta.GlobalKey("key-beta")
 	key = keyBeta
+	keyGA := meta.GlobalKey("key-ga")
+	key = keyGA
 	// Ignore unused variables.
 	_, _, _ = ctx, mock, key
 
@@ -3190,6 +4326,9 @@ func TestSecurityPoliciesGroup(t *testing.T) {
 	if _, err := mock.BetaSecurityPolicies().Get(ctx, key); err == nil {
 		t.Errorf("BetaSecurityPolicies().Get(%v, %v) = _, nil; want error", ctx, key)
 	}
+	if _, err := mock.SecurityPolicies().Get(ctx, key); err == nil {
+		t.Errorf("SecurityPolicies().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
 
 	// Insert.
 	{
@@ -3198,16 +4337,27 @@ func TestSecurityPoliciesGroup(t *testing.T) {
 			t.Errorf("BetaSecurityPolicies().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
 		}
 	}
+	{
+		obj := &ga.SecurityPolicy{}
+		if err := mock.SecurityPolicies().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("SecurityPolicies().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
 
 	// Get across versions.
 	if obj, err := mock.BetaSecurityPolicies().Get(ctx, key); err != nil {
 		t.Errorf("BetaSecurityPolicies().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
 	}
+	if obj, err := mock.SecurityPolicies().Get(ctx, key); err != nil {
+		t.Errorf("SecurityPolicies().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
 
 	// List.
 	mock.MockBetaSecurityPolicies.Objects[*keyBeta] = mock.MockBetaSecurityPolicies.Obj(&beta.SecurityPolicy{Name: keyBeta.Name})
+	mock.MockSecurityPolicies.Objects[*keyGA] = mock.MockSecurityPolicies.Obj(&ga.SecurityPolicy{Name: keyGA.Name})
 	want := map[string]bool{
 		"key-beta": true,
+		"key-ga":   true,
 	}
 	_ = want // ignore unused variables.
 	{
@@ -3224,16 +4374,36 @@ func TestSecurityPoliciesGroup(t *testing.T) {
 			}
 		}
 	}
+	{
+		objs, err := mock.SecurityPolicies().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("SecurityPolicies().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("SecurityPolicies().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
 
 	// Delete across versions.
 	if err := mock.BetaSecurityPolicies().Delete(ctx, keyBeta); err != nil {
 		t.Errorf("BetaSecurityPolicies().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
 	}
+	if err := mock.SecurityPolicies().Delete(ctx, keyGA); err != nil {
+		t.Errorf("SecurityPolicies().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
 
 	// Delete not found.
 	if err := mock.BetaSecurityPolicies().Delete(ctx, keyBeta); err == nil {
 		t.Errorf("BetaSecurityPolicies().Delete(%v, %v) = nil; want error", ctx, keyBeta)
 	}
+	if err := mock.SecurityPolicies().Delete(ctx, keyGA); err == nil {
+		t.Errorf("SecurityPolicies().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
 }
 
 func TestServiceAttachmentsGroup(t *testing.T) {
@@ -4350,8 +5520,11 @@ func TestResourceIDConversion(t *testing.T) {
 		NewDisksResourceID("some-project", "us-east1-b", "my-disks-resource"),
 		NewFirewallsResourceID("some-project", "my-firewalls-resource"),
 		NewForwardingRulesResourceID("some-project", "us-central1", "my-forwardingRules-resource"),
+		NewFutureReservationsResourceID("some-project", "us-east1-b", "my-futureReservations-resource"),
 		NewGlobalAddressesResourceID("some-project", "my-addresses-resource"),
 		NewGlobalForwardingRulesResourceID("some-project", "my-forwardingRules-resource"),
+		NewGlobalNetworkEndpointGroupsResourceID("some-project", "my-networkEndpointGroups-resource"),
+		NewGlobalPublicDelegatedPrefixesResourceID("some-project", "my-publicDelegatedPrefixes-resource"),
 		NewHealthChecksResourceID("some-project", "my-healthChecks-resource"),
 		NewHttpHealthChecksResourceID("some-project", "my-httpHealthChecks-resource"),
 		NewHttpsHealthChecksResourceID("some-project", "my-httpsHealthChecks-resource"),
@@ -4360,19 +5533,26 @@ func TestResourceIDConversion(t *testing.T) {
 		NewInstanceGroupsResourceID("some-project", "us-east1-b", "my-instanceGroups-resource"),
 		NewInstanceTemplatesResourceID("some-project", "my-instanceTemplates-resource"),
 		NewInstancesResourceID("some-project", "us-east1-b", "my-instances-resource"),
+		NewInterconnectAttachmentsResourceID("some-project", "us-central1", "my-interconnectAttachments-resource"),
+		NewInterconnectsResourceID("some-project", "my-interconnects-resource"),
 		NewNetworkEndpointGroupsResourceID("some-project", "us-east1-b", "my-networkEndpointGroups-resource"),
 		NewNetworkFirewallPoliciesResourceID("some-project", "my-networkFirewallPolicies-resource"),
 		NewNetworksResourceID("some-project", "my-networks-resource"),
 		NewProjectsResourceID("my-projects-resource"),
+		NewPublicAdvertisedPrefixesResourceID("some-project", "my-publicAdvertisedPrefixes-resource"),
+		NewPublicDelegatedPrefixesResourceID("some-project", "us-central1", "my-publicDelegatedPrefixes-resource"),
 		NewRegionBackendServicesResourceID("some-project", "us-central1", "my-backendServices-resource"),
 		NewRegionDisksResourceID("some-project", "us-central1", "my-disks-resource"),
 		NewRegionHealthChecksResourceID("some-project", "us-central1", "my-healthChecks-resource"),
+		NewRegionNetworkEndpointGroupsResourceID("some-project", "us-central1", "my-networkEndpointGroups-resource"),
 		NewRegionNetworkFirewallPoliciesResourceID("some-project", "us-central1", "my-regionNetworkFirewallPolicies-resource"),
 		NewRegionSslCertificatesResourceID("some-project", "us-central1", "my-sslCertificates-resource"),
+		NewRegionSslPoliciesResourceID("some-project", "us-central1", "my-sslPolicies-resource"),
 		NewRegionTargetHttpProxiesResourceID("some-project", "us-central1", "my-targetHttpProxies-resource"),
 		NewRegionTargetHttpsProxiesResourceID("some-project", "us-central1", "my-targetHttpsProxies-resource"),
 		NewRegionUrlMapsResourceID("some-project", "us-central1", "my-urlMaps-resource"),
 		NewRegionsResourceID("some-project", "my-regions-resource"),
+		NewReservationsResourceID("some-project", "us-east1-b", "my-reservations-resource"),
 		NewRoutersResourceID("some-project", "us-central1", "my-routers-resource"),
 		NewRoutesResourceID("some-project", "my-routes-resource"),
 		NewSecurityPoliciesResourceID("some-project", "my-securityPolicies-resource"),
@@ -0,0 +1,58 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"google.golang.org/api/googleapi"
+)
+
+// ErrGRPCUnsupported is returned by the methods of GRPCGCE that have no
+// equivalent in the compute/apiv1 clients.
+var ErrGRPCUnsupported = errors.New("not supported by the compute/apiv1 client")
+
+// forceSendField returns true if name is in the ForceSendFields of an
+// object. The generated converters use it to set the zero values that are
+// sent to the API.
+func forceSendField(forceSendFields []string, name string) bool {
+	for _, f := range forceSendFields {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// waitGRPCOperation waits for op to complete. Like WaitForCompletion, an
+// error is returned if the operation failed.
+func waitGRPCOperation(ctx context.Context, op *compute.Operation) (err error) {
+	defer func() { callOperationDone(ctx, err) }()
+
+	if err := op.Wait(ctx); err != nil {
+		return err
+	}
+	o := operationFromProto(op.Proto())
+	if o.Error != nil && len(o.Error.Errors) > 0 && o.Error.Errors[0] != nil {
+		e := o.Error.Errors[0]
+		return &googleapi.Error{Code: int(o.HttpErrorStatusCode), Message: fmt.Sprintf("%v - %v", e.Code, e.Message), Details: operationErrorDetails(e.ErrorDetails)}
+	}
+	return nil
+}
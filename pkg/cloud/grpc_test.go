@@ -0,0 +1,229 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/gcerrors"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+var _ Cloud = (*GRPCGCE)(nil)
+
+func TestProtoConversion(t *testing.T) {
+	t.Parallel()
+
+	bs := &ga.BackendService{
+		Name:            "bs",
+		Id:              1234567890123,
+		TimeoutSec:      30,
+		Backends:        []*ga.Backend{{Group: "ig", MaxUtilization: 0.5}},
+		CdnPolicy:       &ga.BackendServiceCdnPolicy{CacheMode: "CACHE_ALL_STATIC"},
+		HealthChecks:    []string{"hc"},
+		ForceSendFields: []string{"EnableCDN"},
+	}
+	pb := backendServiceToProto(bs)
+	if pb.GetName() != "bs" || pb.GetId() != 1234567890123 || pb.GetTimeoutSec() != 30 {
+		t.Errorf("backendServiceToProto() = %v; want fields copied from %+v", pb, bs)
+	}
+	if len(pb.GetBackends()) != 1 || pb.GetBackends()[0].GetGroup() != "ig" || pb.GetBackends()[0].GetMaxUtilization() != 0.5 {
+		t.Errorf("backendServiceToProto().Backends = %v, want [{ig 0.5}]", pb.GetBackends())
+	}
+	if pb.GetCdnPolicy().GetCacheMode() != "CACHE_ALL_STATIC" || len(pb.GetHealthChecks()) != 1 {
+		t.Errorf("backendServiceToProto() = %v; want CdnPolicy and HealthChecks copied", pb)
+	}
+	// Zero values are only set if they are in ForceSendFields.
+	if pb.EnableCDN == nil || pb.GetEnableCDN() || pb.Description != nil {
+		t.Errorf("backendServiceToProto(): EnableCDN = %v, Description = %v; want false, nil", pb.EnableCDN, pb.Description)
+	}
+
+	got := backendServiceFromProto(pb)
+	got.ForceSendFields = bs.ForceSendFields
+	if diff := cmp.Diff(bs, got); diff != "" {
+		t.Errorf("backendServiceFromProto(backendServiceToProto()) diff -want +got: %v", diff)
+	}
+	if backendServiceToProto(nil) != nil || backendServiceFromProto(nil) != nil {
+		t.Errorf("conversion of nil != nil")
+	}
+}
+
+func TestGRPCGCE(t *testing.T) {
+	t.Parallel()
+
+	var inserted map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/compute/v1/projects/proj/regions/us-central1/addresses/addr", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "addr", "address": "10.0.0.1", "id": "42"})
+	})
+	mux.HandleFunc("/compute/v1/projects/proj/regions/us-central1/addresses/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, `{"error": {"code": 404, "message": "not found"}}`)
+	})
+	mux.HandleFunc("/compute/v1/projects/proj/regions/us-central1/addresses", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			if got, want := r.URL.Query().Get("filter"), filter.Regexp("name", "addr").String(); got != want {
+				t.Errorf("List filter = %q, want %q", got, want)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"items": []interface{}{map[string]interface{}{"name": "addr"}},
+			})
+		case http.MethodPost:
+			json.NewDecoder(r.Body).Decode(&inserted)
+			json.NewEncoder(w).Encode(map[string]interface{}{"name": "op-1", "status": "RUNNING"})
+		}
+	})
+	mux.HandleFunc("/compute/v1/projects/proj/regions/us-central1/operations/op-1", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "op-1", "status": "DONE"})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	rl := &countingRateLimiter{}
+	svc := &Service{
+		ProjectRouter: &SingleProjectRouter{"proj"},
+		RateLimiter:   rl,
+	}
+	g, err := NewGRPCGCE(ctx, svc, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewGRPCGCE() = _, %v, want nil", err)
+	}
+	defer g.Close()
+
+	key := meta.RegionalKey("addr", "us-central1")
+	addr, err := g.Addresses().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Addresses().Get(%v) = _, %v, want nil", key, err)
+	}
+	if addr.Name != "addr" || addr.Address != "10.0.0.1" || addr.Id != 42 {
+		t.Errorf("Addresses().Get(%v) = %+v, want name=addr, address=10.0.0.1, id=42", key, addr)
+	}
+
+	var gerr *googleapi.Error
+	if _, err := g.Addresses().Get(ctx, meta.RegionalKey("missing", "us-central1")); !errors.As(err, &gerr) || gerr.Code != http.StatusNotFound {
+		t.Errorf("Addresses().Get(missing) = _, %v; want 404", err)
+	}
+
+	list, err := g.Addresses().List(ctx, "us-central1", filter.Regexp("name", "addr"))
+	if err != nil || len(list) != 1 || list[0].Name != "addr" {
+		t.Errorf("Addresses().List() = %+v, %v; want [addr], nil", list, err)
+	}
+
+	if err := g.Addresses().Insert(ctx, meta.RegionalKey("new", "us-central1"), &ga.Address{AddressType: "INTERNAL"}); err != nil {
+		t.Errorf("Addresses().Insert() = %v, want nil", err)
+	}
+	if inserted["name"] != "new" || inserted["addressType"] != "INTERNAL" {
+		t.Errorf("inserted = %v, want name=new, addressType=INTERNAL", inserted)
+	}
+
+	if rl.accept != 4 || rl.observe != 4 {
+		t.Errorf("RateLimiter Accept() = %d, Observe() = %d calls; want 4, 4", rl.accept, rl.observe)
+	}
+}
+
+func TestGRPCGCEMethods(t *testing.T) {
+	t.Parallel()
+
+	var labels map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/compute/v1/projects/proj/regions/us-central1/addresses/addr/setLabels", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&labels)
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "op-1", "status": "RUNNING"})
+	})
+	mux.HandleFunc("/compute/v1/projects/proj/regions/us-central1/operations/op-1", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "op-1", "status": "DONE"})
+	})
+	mux.HandleFunc("/compute/v1/projects/proj/regions/us-central1/forwardingRules", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "op-2", "status": "RUNNING"})
+	})
+	mux.HandleFunc("/compute/v1/projects/proj/regions/us-central1/operations/op-2", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":                "op-2",
+			"status":              "DONE",
+			"httpErrorStatusCode": 403,
+			"error": map[string]interface{}{
+				"errors": []interface{}{map[string]interface{}{
+					"code":    "QUOTA_EXCEEDED",
+					"message": "Quota 'FORWARDING_RULES' exceeded.",
+					"errorDetails": []interface{}{map[string]interface{}{
+						"quotaInfo": map[string]interface{}{"metricName": "compute.googleapis.com/forwarding_rules", "limit": 75},
+					}},
+				}},
+			},
+		})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	svc := &Service{
+		ProjectRouter: &SingleProjectRouter{"proj"},
+		RateLimiter:   &NopRateLimiter{},
+	}
+	g, err := NewGRPCGCE(ctx, svc, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewGRPCGCE() = _, %v, want nil", err)
+	}
+	defer g.Close()
+
+	key := meta.RegionalKey("addr", "us-central1")
+	req := &ga.RegionSetLabelsRequest{Labels: map[string]string{"a": "b"}, LabelFingerprint: "fp"}
+	if err := g.Addresses().SetLabels(ctx, key, req); err != nil {
+		t.Errorf("Addresses().SetLabels() = %v, want nil", err)
+	}
+	if labels["labelFingerprint"] != "fp" || !cmp.Equal(labels["labels"], map[string]interface{}{"a": "b"}) {
+		t.Errorf("SetLabels request = %v, want labels a=b, labelFingerprint=fp", labels)
+	}
+
+	err = g.ForwardingRules().Insert(ctx, meta.RegionalKey("fr", "us-central1"), &ga.ForwardingRule{})
+	q, ok := gcerrors.Quota(err)
+	if !ok || q.Metric != "compute.googleapis.com/forwarding_rules" || q.Limit != 75 {
+		t.Errorf("gcerrors.Quota(%v) = %+v, %t; want the quota from the operation", err, q, ok)
+	}
+
+	// Disks.Update is not in compute/apiv1.
+	if err := g.Disks().Update(ctx, meta.ZonalKey("disk", "us-central1-b"), &ga.Disk{}); !errors.Is(err, ErrGRPCUnsupported) {
+		t.Errorf("Disks().Update() = %v, want %v", err, ErrGRPCUnsupported)
+	}
+}
+
+type countingRateLimiter struct {
+	accept, observe int
+}
+
+func (rl *countingRateLimiter) Accept(context.Context, *RateLimitKey) error {
+	rl.accept++
+	return nil
+}
+
+func (rl *countingRateLimiter) Observe(context.Context, error, *RateLimitKey) {
+	rl.observe++
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// HeaderRequestParams is the routing header with the project and
+	// location of the call.
+	HeaderRequestParams = "X-Goog-Request-Params"
+	// HeaderRequestReason identifies the component and the unit of work
+	// making the call. It is recorded in the Cloud Audit Logs.
+	HeaderRequestReason = "X-Goog-Request-Reason"
+)
+
+// CallHeaders configures the headers sent with each call made through
+// Cloud. The User-Agent is set on the clients, as the clients replace the
+// User-Agent of each request:
+//
+//	h := &cloud.CallHeaders{Component: "l7-controller", Version: version.Version}
+//	client, err := ga.NewService(ctx, option.WithUserAgent(h.UserAgent()))
+//	svc := &cloud.Service{
+//		GA:      client,
+//		Headers: h,
+//		...
+//	}
+//
+// Each call then has the HeaderRequestParams and a HeaderRequestReason of
+// the form "l7-controller/1.2.3 caller=l7-controller key=default/ing
+// reconcile=8e3c5a" with the Caller from the context.
+type CallHeaders struct {
+	// Component making the calls, e.g. "l7-controller".
+	Component string
+	// Version of the Component.
+	Version string
+}
+
+// UserAgent for the clients of the Service.
+func (h *CallHeaders) UserAgent() string {
+	return fmt.Sprintf("%s k8s-cloud-provider", h.product())
+}
+
+func (h *CallHeaders) product() string {
+	if h.Version == "" {
+		return h.Component
+	}
+	return h.Component + "/" + h.Version
+}
+
+// reason returns the HeaderRequestReason of the call with ctx.
+func (h *CallHeaders) reason(ctx context.Context) string {
+	parts := []string{h.product()}
+	if c, ok := CallerFromContext(ctx); ok {
+		if c.Name != "" {
+			parts = append(parts, "caller="+c.Name)
+		}
+		if c.Key != "" {
+			parts = append(parts, "key="+c.Key)
+		}
+		if c.ReconcileID != "" {
+			parts = append(parts, "reconcile="+c.ReconcileID)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// requestParams returns the HeaderRequestParams for a call in projectID on
+// the resource key. key may be nil.
+func requestParams(projectID string, key *meta.Key) string {
+	v := url.Values{}
+	v.Set("project", projectID)
+	if key != nil {
+		switch key.Type() {
+		case meta.Regional:
+			v.Set("region", key.Region)
+		case meta.Zonal:
+			v.Set("zone", key.Zone)
+		}
+	}
+	return v.Encode()
+}
+
+// start computes the headers of the call in state and adds them to
+// the outgoing gRPC metadata of ctx.
+func (h *CallHeaders) start(ctx context.Context, state *callState) context.Context {
+	if h == nil {
+		return ctx
+	}
+	state.headers = http.Header{}
+	state.headers.Set(HeaderRequestParams, requestParams(state.ck.ProjectID, state.key))
+	state.headers.Set(HeaderRequestReason, h.reason(ctx))
+	var kv []string
+	for k, vs := range state.headers {
+		for _, v := range vs {
+			kv = append(kv, strings.ToLower(k), v)
+		}
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestCallHeaders(t *testing.T) {
+	t.Parallel()
+
+	h := &CallHeaders{Component: "l7-controller", Version: "1.2.3"}
+	if got, want := h.UserAgent(), "l7-controller/1.2.3 k8s-cloud-provider"; got != want {
+		t.Errorf("UserAgent() = %q, want %q", got, want)
+	}
+
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "a1"})
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication(), option.WithUserAgent(h.UserAgent()))
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+	svc := &Service{
+		GA:            client,
+		ProjectRouter: &SingleProjectRouter{"proj"},
+		RateLimiter:   &NopRateLimiter{},
+		Headers:       h,
+	}
+	c := NewGCE(svc)
+
+	ctx = WithCaller(ctx, Caller{Name: "l7-controller", Key: "default/ing", ReconcileID: "r1"})
+	key := meta.RegionalKey("a1", "us-central1")
+	if _, err := c.Addresses().Get(ctx, key); err != nil {
+		t.Fatalf("Get(%v) = _, %v, want nil", key, err)
+	}
+
+	for k, want := range map[string]string{
+		HeaderRequestParams: "project=proj&region=us-central1",
+		HeaderRequestReason: "l7-controller/1.2.3 caller=l7-controller key=default/ing reconcile=r1",
+	} {
+		if got := got.Get(k); got != want {
+			t.Errorf("header %s = %q, want %q", k, got, want)
+		}
+	}
+	if ua := got.Get("User-Agent"); !strings.Contains(ua, "l7-controller/1.2.3 k8s-cloud-provider") {
+		t.Errorf("User-Agent = %q, want l7-controller/1.2.3", ua)
+	}
+
+	// gRPC calls send the headers in the metadata.
+	ck := &CallContextKey{ProjectID: "proj", Service: "Addresses", Operation: "Get"}
+	gctx := svc.callStart(ctx, ck, meta.ZonalKey("a1", "us-central1-b"))
+	md, _ := metadata.FromOutgoingContext(gctx)
+	if got, want := md.Get("x-goog-request-params"), []string{"project=proj&zone=us-central1-b"}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("metadata x-goog-request-params = %v, want %v", got, want)
+	}
+	callEnd(gctx, ck, nil)
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// CallInfo describes a call made through Cloud to an Interceptor.
+type CallInfo struct {
+	CallContextKey
+	// Key of the resource. This is nil for calls that are not on a single
+	// resource (e.g. List).
+	Key *meta.Key
+	// Args are the remaining arguments of the call after the context and
+	// the key, e.g. the object for Insert or the filter for List.
+	Args []interface{}
+}
+
+// CallHandler makes the call. The result is the value returned by the
+// call (e.g. *ga.BackendService for Get) or nil for calls that only return
+// an error.
+type CallHandler func(ctx context.Context) (interface{}, error)
+
+// Interceptor wraps a call made through Cloud. An Interceptor may change
+// the context, return early without calling next (e.g. to serve the result
+// from a cache or inject a fault) or change the result returned by next.
+// The result returned must be of the same type as the one returned by next.
+//
+//	func logCalls(ctx context.Context, info *cloud.CallInfo, next cloud.CallHandler) (interface{}, error) {
+//		start := time.Now()
+//		res, err := next(ctx)
+//		log.Printf("%s.%s(%v) = %v (%v)", info.Service, info.Operation, info.Key, err, time.Since(start))
+//		return res, err
+//	}
+//
+//	svc := &cloud.Service{
+//		...
+//		Interceptors: []cloud.Interceptor{logCalls},
+//	}
+//
+// Interceptors run before the rate limiter, so a call served by an
+// Interceptor does not count against the rate limits.
+type Interceptor func(ctx context.Context, info *CallInfo, next CallHandler) (interface{}, error)
+
+// newCallInfo returns the CallInfo for the call. ProjectID is filled in by
+// intercept.
+func newCallInfo(service, operation string, version meta.Version, key *meta.Key, args ...interface{}) *CallInfo {
+	return &CallInfo{
+		CallContextKey: CallContextKey{
+			Operation: operation,
+			Version:   version,
+			Service:   service,
+		},
+		Key:  key,
+		Args: args,
+	}
+}
+
+// intercept makes the call with the Interceptors and the RetryPolicy of
+// s. The error returned is a *CallError, translated by s.TranslateError.
+func intercept[T any](ctx context.Context, s *Service, info *CallInfo, call func(context.Context) (T, error)) (T, error) {
+	var requestID string
+	ctx = context.WithValue(ctx, sentRequestIDContextKey, &requestID)
+	if IsMutation(info.Operation) {
+		ctx = withCallRequestID(ctx)
+	}
+	ret, err := interceptors(ctx, s, info, withRetry(s, info, call))
+	if err != nil {
+		err = s.callError(ctx, info, err)
+		if cerr, ok := err.(*CallError); ok && s.TranslateError != nil {
+			if terr := s.TranslateError(ctx, cerr); terr != nil {
+				err = terr
+			}
+		}
+	}
+	return ret, err
+}
+
+func interceptors[T any](ctx context.Context, s *Service, info *CallInfo, call func(context.Context) (T, error)) (T, error) {
+	if len(s.Interceptors) == 0 {
+		return call(ctx)
+	}
+	info.ProjectID = s.ProjectRouter.ProjectID(ctx, info.Version, info.Service)
+
+	next := func(ctx context.Context) (interface{}, error) {
+		return call(ctx)
+	}
+	for i := len(s.Interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := s.Interceptors[i], next
+		next = func(ctx context.Context) (interface{}, error) {
+			return interceptor(ctx, info, inner)
+		}
+	}
+
+	var zero T
+	res, err := next(ctx)
+	if res == nil {
+		return zero, err
+	}
+	ret, ok := res.(T)
+	if !ok {
+		return zero, fmt.Errorf("interceptor returned %T for %s.%s, want %T", res, info.Service, info.Operation, zero)
+	}
+	return ret, err
+}
+
+// interceptErr is intercept for calls that only return an error.
+func interceptErr(ctx context.Context, s *Service, info *CallInfo, call func(context.Context) error) error {
+	_, err := intercept(ctx, s, info, func(ctx context.Context) (interface{}, error) {
+		return nil, call(ctx)
+	})
+	return err
+}
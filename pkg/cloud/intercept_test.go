@@ -0,0 +1,266 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/gcerrors"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+	ga "google.golang.org/api/compute/v1"
+	networkconnectivity "google.golang.org/api/networkconnectivity/v1"
+	"google.golang.org/api/option"
+)
+
+func TestInterceptors(t *testing.T) {
+	t.Parallel()
+
+	var requests int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/proj/regions/us-central1/addresses/a1", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "a1"})
+	})
+	mux.HandleFunc("/projects/proj/regions/us-central1/addresses", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"items": []interface{}{map[string]interface{}{"name": "a1"}},
+		})
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+
+	key := meta.RegionalKey("a1", "us-central1")
+	errInjected := errors.New("injected")
+
+	for _, tc := range []struct {
+		desc string
+		// interceptors are passed the trace to append to.
+		interceptors func(trace *[]string) []Interceptor
+		call         func(Cloud) (interface{}, error)
+		want         interface{}
+		wantTrace    []string
+		wantRequests int32
+		wantErr      error
+	}{
+		{
+			desc: "no interceptors",
+			interceptors: func(*[]string) []Interceptor {
+				return nil
+			},
+			call: func(c Cloud) (interface{}, error) {
+				a, err := c.Addresses().Get(ctx, key)
+				return a.Name, err
+			},
+			want:         "a1",
+			wantRequests: 1,
+		},
+		{
+			desc: "order",
+			interceptors: func(trace *[]string) []Interceptor {
+				mk := func(name string) Interceptor {
+					return func(ctx context.Context, info *CallInfo, next CallHandler) (interface{}, error) {
+						*trace = append(*trace, name+" "+info.ProjectID+" "+info.Service+"."+info.Operation)
+						res, err := next(ctx)
+						*trace = append(*trace, name+" done")
+						return res, err
+					}
+				}
+				return []Interceptor{mk("outer"), mk("inner")}
+			},
+			call: func(c Cloud) (interface{}, error) {
+				a, err := c.Addresses().Get(ctx, key)
+				return a.Name, err
+			},
+			want:         "a1",
+			wantTrace:    []string{"outer proj Addresses.Get", "inner proj Addresses.Get", "inner done", "outer done"},
+			wantRequests: 1,
+		},
+		{
+			desc: "short circuit",
+			interceptors: func(*[]string) []Interceptor {
+				return []Interceptor{func(ctx context.Context, info *CallInfo, next CallHandler) (interface{}, error) {
+					return &ga.Address{Name: "cached"}, nil
+				}}
+			},
+			call: func(c Cloud) (interface{}, error) {
+				a, err := c.Addresses().Get(ctx, key)
+				return a.Name, err
+			},
+			want: "cached",
+		},
+		{
+			desc: "inject error",
+			interceptors: func(*[]string) []Interceptor {
+				return []Interceptor{func(ctx context.Context, info *CallInfo, next CallHandler) (interface{}, error) {
+					return nil, errInjected
+				}}
+			},
+			call: func(c Cloud) (interface{}, error) {
+				return nil, c.Addresses().Insert(ctx, key, &ga.Address{})
+			},
+			wantErr: errInjected,
+		},
+		{
+			desc: "args",
+			interceptors: func(trace *[]string) []Interceptor {
+				return []Interceptor{func(ctx context.Context, info *CallInfo, next CallHandler) (interface{}, error) {
+					if info.Key != nil || len(info.Args) != 2 || info.Args[0] != "us-central1" || info.Args[1] != filter.None {
+						*trace = append(*trace, "bad info")
+					}
+					return next(ctx)
+				}}
+			},
+			call: func(c Cloud) (interface{}, error) {
+				l, err := c.Addresses().List(ctx, "us-central1", filter.None)
+				return len(l), err
+			},
+			want:         1,
+			wantRequests: 1,
+		},
+		{
+			desc: "wrong type",
+			interceptors: func(*[]string) []Interceptor {
+				return []Interceptor{func(ctx context.Context, info *CallInfo, next CallHandler) (interface{}, error) {
+					return "not an address", nil
+				}}
+			},
+			call: func(c Cloud) (interface{}, error) {
+				_, err := c.Addresses().Get(ctx, key)
+				return nil, err
+			},
+			wantErr: errAny,
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			atomic.StoreInt32(&requests, 0)
+			var trace []string
+			c := NewGCE(&Service{
+				GA:            client,
+				ProjectRouter: &SingleProjectRouter{"proj"},
+				RateLimiter:   &NopRateLimiter{},
+				Interceptors:  tc.interceptors(&trace),
+			})
+			got, err := tc.call(c)
+			switch {
+			case tc.wantErr == errAny && err == nil:
+				t.Fatalf("call() = _, nil, want error")
+			case tc.wantErr != errAny && !errors.Is(err, tc.wantErr):
+				t.Fatalf("call() = _, %v, want %v", err, tc.wantErr)
+			}
+			if tc.wantErr != nil {
+				return
+			}
+			if got != tc.want {
+				t.Errorf("call() = %v, want %v", got, tc.want)
+			}
+			if diff := cmp.Diff(tc.wantTrace, trace); diff != "" {
+				t.Errorf("trace: -want +got: %s", diff)
+			}
+			if n := atomic.LoadInt32(&requests); n != tc.wantRequests {
+				t.Errorf("requests = %d, want %d", n, tc.wantRequests)
+			}
+		})
+	}
+}
+
+// errAny matches any non-nil error in TestInterceptors.
+var errAny = errors.New("any error")
+
+// hintError is an error of the caller with a remediation hint.
+type hintError struct {
+	hint string
+	err  error
+}
+
+func (e *hintError) Error() string { return e.err.Error() + " (" + e.hint + ")" }
+func (e *hintError) Unwrap() error { return e.err }
+
+func TestTranslateError(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": {"code": 404, "message": "not found"}}`, http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+	ncClient, err := networkconnectivity.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("networkconnectivity.NewService() = _, %v, want nil", err)
+	}
+	var translated []string
+	c := NewGCE(&Service{
+		GA:                  client,
+		NetworkConnectivity: ncClient,
+		ProjectRouter:       &SingleProjectRouter{"proj"},
+		RateLimiter:         &NopRateLimiter{},
+		TranslateError: func(ctx context.Context, e *CallError) error {
+			translated = append(translated, e.CallContextKey.Service+"."+e.CallContextKey.Operation)
+			if e.CallContextKey.Service == "Firewalls" {
+				return nil
+			}
+			return &hintError{hint: "check the name", err: e}
+		},
+	})
+
+	_, err = c.Addresses().Get(ctx, meta.RegionalKey("a1", "us-central1"))
+	var herr *hintError
+	if !errors.As(err, &herr) || herr.hint != "check the name" {
+		t.Errorf("Get() = _, %v, want a *hintError", err)
+	}
+	var cerr *CallError
+	if !errors.As(err, &cerr) || !gcerrors.IsNotFound(err) {
+		t.Errorf("Get() = _, %v, want a wrapped *CallError of a 404", err)
+	}
+
+	// A nil error returns the CallError.
+	_, err = c.Firewalls().Get(ctx, meta.GlobalKey("fw"))
+	if _, ok := err.(*CallError); !ok {
+		t.Errorf("Get() = _, %v (%T), want a *CallError", err, err)
+	}
+	// The calls of the other APIs are translated too.
+	_, err = c.NetworkConnectivity().Hubs().Get(ctx, meta.GlobalKey("hub"))
+	if !errors.As(err, &herr) || !gcerrors.IsNotFound(err) {
+		t.Errorf("Hubs().Get() = _, %v, want a *hintError of a 404", err)
+	}
+	if diff := cmp.Diff([]string{"Addresses.Get", "Firewalls.Get", "Hubs.Get"}, translated); diff != "" {
+		t.Errorf("TranslateError: -want +got: %s", diff)
+	}
+}
@@ -0,0 +1,408 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objutil
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Copier copies a value into another one of a similar type, e.g. the same
+// API object in a different version. Struct fields are matched by name.
+// Values of src that have no place in dest are reported to Missing. The
+// result does not share any memory with src.
+type Copier struct {
+	// Lax drops the values that cannot be copied (e.g. a field with a
+	// different kind in dest) and reports them to Missing instead of
+	// returning an error.
+	Lax bool
+	// Skip, if set, returns true for the struct fields that are not copied.
+	Skip func(p Path) bool
+	// Missing, if set, is called for the non-zero values of src that are
+	// not copied to dest and for the metafield entries naming a field that
+	// doesn't exist in dest.
+	Missing func(p Path, v any)
+	// Defaulted, if set, is called for the fields of dest that don't exist
+	// in src.
+	Defaulted func(p Path)
+	// LogS is an optional structured log function, matching the signature
+	// from klog/v2.
+	LogS func(msg string, kv ...any)
+}
+
+// Copy src into dest.
+func (c *Copier) Copy(dest, src reflect.Value) error {
+	return c.doValues(Path{}, dest, src)
+}
+
+func (c *Copier) logS(msg string, kv ...any) {
+	if c.LogS == nil {
+		return
+	}
+	c.LogS(msg, kv...)
+}
+
+func (c *Copier) missing(p Path, v any) {
+	if c.Missing == nil {
+		return
+	}
+	c.Missing(p, v)
+}
+
+// incompatible returns err for a src that cannot be copied, or reports src
+// as missing if c is Lax.
+func (c *Copier) incompatible(p Path, src reflect.Value, err error) error {
+	if !c.Lax {
+		return err
+	}
+	if src.IsValid() && !src.IsZero() {
+		c.missing(p, src.Interface())
+		c.logS("copy dropped", "path", p, "err", err)
+	}
+	return nil
+}
+
+func (c *Copier) doValues(p Path, dest, src reflect.Value) error {
+	switch {
+	case isBasic(dest.Type()) && isBasic(src.Type()):
+		return c.doBasic(p, dest, src)
+	case src.Type().Kind() == reflect.Pointer && dest.Type().Kind() == reflect.Pointer:
+		return c.doPointer(p, dest, src)
+	case src.Type().Kind() == reflect.Slice && dest.Type().Kind() == reflect.Slice:
+		return c.doSlice(p, dest, src)
+	case src.Type().Kind() == reflect.Struct && dest.Type().Kind() == reflect.Struct:
+		return c.doStruct(p, dest, src)
+	case src.Type().Kind() == reflect.Map && dest.Type().Kind() == reflect.Map:
+		return c.doMap(p, dest, src)
+	case src.Type().Kind() == reflect.Interface && dest.Type().Kind() == reflect.Interface:
+		return c.doInterface(p, dest, src)
+	}
+	return c.incompatible(p, src, fmt.Errorf("copyValues: incompatible types: src %s, dest %s", src.Type(), dest.Type()))
+}
+
+func (c *Copier) doBasic(p Path, dest, src reflect.Value) error {
+	if !isBasic(dest.Type()) || !isBasic(src.Type()) || dest.Type().Kind() != src.Type().Kind() {
+		return c.incompatible(p, src, fmt.Errorf("copyBasic: mismatched types: src %s, dest %s", src.Type(), dest.Type()))
+	}
+	if !dest.CanSet() {
+		return fmt.Errorf("cannot set dest (%s)", p)
+	}
+	dest.Set(src.Convert(dest.Type()))
+	c.logS("copyBasic", "path", p, "value", dest.Interface())
+	return nil
+}
+
+func (c *Copier) doPointer(p Path, dest, src reflect.Value) error {
+	if dest.Type().Kind() != reflect.Pointer || src.Type().Kind() != reflect.Pointer {
+		return c.incompatible(p, src, fmt.Errorf("copyPointer: invalid types: src %s, dest %s", src.Type(), dest.Type()))
+	}
+	if src.IsZero() {
+		c.logS("copyPointer zero", "path", p)
+		if !dest.CanSet() {
+			return fmt.Errorf("cannot set dest (%s)", p)
+		}
+		dest.Set(reflect.Zero(dest.Type()))
+		return nil
+	}
+	if dest.IsZero() {
+		if !dest.CanSet() {
+			return fmt.Errorf("copyPointer: dest is nil and not settable: src %s, dest %s", src.Type(), dest.Type())
+		}
+		dest.Set(reflect.New(dest.Type().Elem()))
+		c.logS("copyPointer", "path", p, "value", dest.Interface())
+	}
+	return c.doValues(p.Pointer(), dest.Elem(), src.Elem())
+}
+
+func (c *Copier) doSlice(p Path, dest, src reflect.Value) error {
+	if dest.Type().Kind() != reflect.Slice || src.Type().Kind() != reflect.Slice {
+		return c.incompatible(p, src, fmt.Errorf("copySlice: invalid type (dest: %s, src: %s)", dest.Type(), src.Type()))
+	}
+	if !dest.CanSet() {
+		return fmt.Errorf("cannot set dest (%s)", p)
+	}
+	if src.IsZero() {
+		dest.Set(reflect.Zero(dest.Type()))
+		c.logS("copySlice zero", "path", p)
+		return nil
+	}
+
+	newSlice := reflect.MakeSlice(dest.Type(), src.Len(), src.Len())
+	for i := 0; i < src.Len(); i++ {
+		if err := c.doValues(p.Index(i), newSlice.Index(i), src.Index(i)); err != nil {
+			return err
+		}
+	}
+	c.logS("copySlice", "path", p, "value", newSlice)
+	dest.Set(newSlice)
+
+	return nil
+}
+
+func (c *Copier) doStruct(p Path, dest, src reflect.Value) error {
+	if dest.Kind() != reflect.Struct || src.Kind() != reflect.Struct {
+		return c.incompatible(p, src, fmt.Errorf("copyStruct: invalid type (dest: %s, src: %s)", dest.Type(), src.Type()))
+	}
+	// Copy over fields that are present in both src and dest. Fields in dest
+	// that don't exist in src are left alone.
+	plan := structPlanFor(src.Type(), dest.Type())
+	for _, f := range plan.fields {
+		fp := p.Field(f.name)
+		srcField := src.Field(f.src)
+
+		if f.dest == nil {
+			// Only non-zero fields are counted towards
+			// the missing fields. Fields explicitly named
+			// in NullFields or ForceSendFields are
+			// handled by doMetaFields() below.
+			if !srcField.IsZero() {
+				c.missing(fp, srcField.Interface())
+				c.logS("copyStruct missing field", "path", p, "fieldName", f.name)
+			}
+			continue
+		}
+		if c.Skip != nil && c.Skip(fp) {
+			continue
+		}
+
+		destField := dest.FieldByIndex(f.dest)
+		if f.meta {
+			if err := c.doMetaFields(fp, destField, srcField, dest, src); err != nil {
+				return err
+			}
+			continue
+		}
+
+		c.logS("copyStruct", "path", p, "fieldName", f.name)
+		if err := c.doValues(fp, destField, srcField); err != nil {
+			return err
+		}
+	}
+	if c.Defaulted != nil {
+		for _, name := range plan.defaulted {
+			c.Defaulted(p.Field(name))
+		}
+	}
+	return nil
+}
+
+func (c *Copier) doMap(p Path, dest, src reflect.Value) error {
+	if dest.Type().Kind() != reflect.Map || src.Type().Kind() != reflect.Map {
+		return c.incompatible(p, src, fmt.Errorf("copyMap: invalid type (dest: %s, src: %s)", dest.Type(), src.Type()))
+	}
+	if !dest.CanSet() {
+		return fmt.Errorf("cannot set dest (%s)", p)
+	}
+	if src.IsZero() {
+		dest.Set(reflect.Zero(dest.Type()))
+		c.logS("copyMap zero", "path", p)
+		return nil
+	}
+
+	dkt := dest.Type().Key()
+	dvt := dest.Type().Elem()
+	skt := src.Type().Key()
+	svt := src.Type().Elem()
+
+	switch {
+	case !isBasic(dkt) || !isBasic(skt):
+		return c.incompatible(p, src, fmt.Errorf("copyMap: keys are not basic types (dest: %s, src: %s)", dest.Type(), src.Type()))
+	case dkt.Kind() != skt.Kind():
+		return c.incompatible(p, src, fmt.Errorf("copyMap: keys do not match (dest: %s, src: %s)", dest.Type(), src.Type()))
+	case dvt.Kind() != svt.Kind():
+		return c.incompatible(p, src, fmt.Errorf("copyMap: values type must match (dest: %s, src: %s)", dest.Type(), src.Type()))
+	case src.Len() > 0 && !isBasic(svt) && svt.Kind() != reflect.Struct && svt.Kind() != reflect.Slice:
+		return c.incompatible(p, src, fmt.Errorf("copyMap: unsupported map types (dest: %s, src: %s)", dest.Type(), src.Type()))
+	}
+
+	newMap := reflect.MakeMapWithSize(dest.Type(), src.Len())
+	iter := src.MapRange()
+	for iter.Next() {
+		sk, sv := iter.Key(), iter.Value()
+		dv := reflect.New(dvt).Elem()
+		if err := c.doValues(p.MapIndex(sk.Interface()), dv, sv); err != nil {
+			return err
+		}
+		newMap.SetMapIndex(sk.Convert(dkt), dv)
+	}
+	c.logS("copyMap", "path", p, "value", newMap)
+	dest.Set(newMap)
+
+	return nil
+}
+
+func (c *Copier) doInterface(p Path, dest, src reflect.Value) error {
+	if !dest.CanSet() {
+		return fmt.Errorf("cannot set dest (%s)", p)
+	}
+	if src.IsNil() {
+		dest.Set(reflect.Zero(dest.Type()))
+		return nil
+	}
+	if !src.Elem().Type().AssignableTo(dest.Type()) {
+		return c.incompatible(p, src, fmt.Errorf("copyInterface: invalid type (dest: %s, src: %s)", dest.Type(), src.Elem().Type()))
+	}
+	// Copy the dynamic value, so that dest does not share memory with src.
+	v := reflect.New(src.Elem().Type()).Elem()
+	if err := c.doValues(p, v, src.Elem()); err != nil {
+		return err
+	}
+	dest.Set(v)
+	return nil
+}
+
+// doMetaFields copies over the contents of metafields such as
+// "ForceSendFields". This may not be a straightforward copy when
+// there are references in version-specific API fields. For example:
+//
+//	type Obj struct { Field int }
+//	type ObjBeta struct { Field int; BetaField int }
+//
+// The user may do the following:
+//
+//	EditBeta(func(x *ObjBeta) { x.ForceSendFields = []string{"BetaField"} }
+//	Edit(func(x *Obj) { x.ForceSendFields = []string{"Field"} }
+//
+// We want to preserve the presence of "BetaField" for the beta
+// version of Obj, even though the field does not exist in the GA
+// version of the API. In the above example, we will end up with:
+//
+//	// BetaField is marked as "missing" if the user uses ToGA().
+//	Obj.ForceSendFields == []string{"Field"}
+//	ObjBeta.ForceSendFields == []string{"Field", "BetaField"}
+//
+// The entries of src come first, in order, followed by the entries that were
+// only in dest. An entry may name a map key (e.g. "Labels.app"), in which case
+// the field is the part before the ".".
+func (c *Copier) doMetaFields(p Path, destField, srcField, destStruct, srcStruct reflect.Value) error {
+	stringsT := reflect.TypeOf([]string(nil))
+	if destField.Type() != stringsT || srcField.Type() != stringsT {
+		return c.incompatible(p, srcField, fmt.Errorf("copyMetaFields: invalid type (destField: %s, srcField: %s)", destField.Type(), srcField.Type()))
+	}
+	if !destField.CanSet() {
+		return fmt.Errorf("cannot set destField (%s)", p)
+	}
+
+	srcMetaFields := srcField.Interface().([]string)
+	oldDestMetaFields := destField.Interface().([]string)
+	if srcMetaFields == nil && oldDestMetaFields == nil {
+		return nil
+	}
+
+	destMetaFields := oldDestMetaFields[:0:0]
+	added := map[string]bool{}
+	for _, fn := range srcMetaFields {
+		field := strings.SplitN(fn, ".", 2)[0]
+		if _, ok := srcStruct.Type().FieldByName(field); !ok && !c.Lax {
+			return fmt.Errorf("copyMetaFields: %s refers to field %q that doesn't exist (type %s)", p, fn, srcStruct.Type())
+		}
+		if _, ok := destStruct.Type().FieldByName(field); !ok {
+			// Record that the metafield referenced a
+			// field that didn't exist on the dest
+			// version.
+			c.missing(p.Field(fn), srcMetaFields)
+			c.logS("copyMetaFields missing field", "path", p, "fieldName", fn)
+			continue
+		}
+		if !added[fn] {
+			destMetaFields = append(destMetaFields, fn)
+			added[fn] = true
+			c.logS("copyMetaFields add", "path", p, "fieldName", fn)
+		}
+	}
+	// Fields only in the dest version (e.g. "BetaField" above) are kept.
+	for _, fn := range oldDestMetaFields {
+		field := strings.SplitN(fn, ".", 2)[0]
+		if _, ok := srcStruct.Type().FieldByName(field); !ok && !added[fn] {
+			destMetaFields = append(destMetaFields, fn)
+			added[fn] = true
+		}
+	}
+	destField.Set(reflect.ValueOf(destMetaFields))
+
+	return nil
+}
+
+// structPlan is how to copy a struct type to another one. It is computed once
+// for each pair of types, as looking up the fields by name for each object
+// dominates the cost of the copies (e.g. of the objects of the mocks).
+type structPlan struct {
+	fields []fieldPlan
+	// defaulted are the fields of dest that are not in src.
+	defaulted []string
+}
+
+// fieldPlan copies the field src of the source to the field dest of the
+// destination, or drops it if dest is nil.
+type fieldPlan struct {
+	name string
+	src  int
+	dest []int
+	// meta is true for NullFields and ForceSendFields.
+	meta bool
+}
+
+var structPlans sync.Map // map[[2]reflect.Type]*structPlan
+
+func structPlanFor(st, dt reflect.Type) *structPlan {
+	k := [2]reflect.Type{st, dt}
+	if p, ok := structPlans.Load(k); ok {
+		return p.(*structPlan)
+	}
+	p := &structPlan{}
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		f := fieldPlan{name: sf.Name, src: i, meta: isMetaField(sf.Name)}
+		if df, ok := dt.FieldByName(sf.Name); ok && df.IsExported() {
+			f.dest = df.Index
+		}
+		p.fields = append(p.fields, f)
+	}
+	for i := 0; i < dt.NumField(); i++ {
+		df := dt.Field(i)
+		if !df.IsExported() || isMetaField(df.Name) {
+			continue
+		}
+		if _, ok := st.FieldByName(df.Name); !ok {
+			p.defaulted = append(p.defaulted, df.Name)
+		}
+	}
+	structPlans.Store(k, p)
+	return p
+}
+
+func isMetaField(name string) bool {
+	return name == "NullFields" || name == "ForceSendFields"
+}
+
+// isBasic is true for the types that are copied and compared by value.
+func isBasic(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objutil
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// DiffState gives details on a DiffItem.
+type DiffState string
+
+const (
+	// DiffDifferent means the element at the Path differs between A and B.
+	DiffDifferent DiffState = "Different"
+	// DiffOnlyInA means the element at the Path only exists in A.
+	DiffOnlyInA DiffState = "OnlyInA"
+	// DiffOnlyInB means the element at the Path only exists in B.
+	DiffOnlyInB DiffState = "OnlyInB"
+)
+
+// DiffItem is an element that is different.
+type DiffItem struct {
+	State DiffState
+	Path  Path
+	A     any
+	B     any
+}
+
+// Differ compares two values of the same type.
+type Differ struct {
+	// Ignore, if set, returns true for the struct fields that are not
+	// compared.
+	Ignore func(p Path) bool
+	// Unordered, if set, returns true for the slices that are compared as
+	// sets. They are reported as a whole.
+	Unordered func(p Path) bool
+	// NilIsZero compares a nil pointer as the zero value and nil slices and
+	// maps as empty ones. Otherwise, a nil value is only in A or B.
+	NilIsZero bool
+}
+
+// Diff returns the elements that differ between a and b.
+func (d *Differ) Diff(a, b reflect.Value) ([]DiffItem, error) {
+	var items []DiffItem
+	if err := d.do(Path{}, a, b, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func add(items *[]DiffItem, state DiffState, p Path, a, b reflect.Value) {
+	di := DiffItem{
+		State: state,
+		Path:  p,
+	}
+	if a.IsValid() {
+		di.A = a.Interface()
+	}
+	if b.IsValid() {
+		di.B = b.Interface()
+	}
+	*items = append(*items, di)
+}
+
+func (d *Differ) do(p Path, av, bv reflect.Value, items *[]DiffItem) error {
+	// cmpNil applies to pointer, slice and map values. Returns true if no
+	// further diff'ing is required for the values.
+	cmpNil := func() bool {
+		switch {
+		case av.IsNil() && bv.IsNil():
+			return true
+		case !av.IsNil() && bv.IsNil():
+			add(items, DiffOnlyInA, p, av, bv)
+			return true
+		case av.IsNil() && !bv.IsNil():
+			add(items, DiffOnlyInB, p, av, bv)
+			return true
+		}
+		return false
+	}
+
+	switch {
+	case isBasic(av.Type()):
+		if !av.Equal(bv) {
+			add(items, DiffDifferent, p, av, bv)
+		}
+		return nil
+
+	case av.Type().Kind() == reflect.Pointer:
+		if d.NilIsZero {
+			if av.IsNil() && bv.IsNil() {
+				return nil
+			}
+			if av.IsNil() {
+				av = reflect.New(av.Type().Elem())
+			}
+			if bv.IsNil() {
+				bv = reflect.New(bv.Type().Elem())
+			}
+		} else if cmpNil() {
+			return nil
+		}
+		return d.do(p.Pointer(), av.Elem(), bv.Elem(), items)
+
+	case av.Type().Kind() == reflect.Struct:
+		for i := 0; i < av.NumField(); i++ {
+			aft := av.Type().Field(i)
+			if !aft.IsExported() {
+				continue
+			}
+			fp := p.Field(aft.Name)
+			if d.Ignore != nil && d.Ignore(fp) {
+				continue
+			}
+			if err := d.do(fp, av.Field(i), bv.Field(i), items); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case av.Type().Kind() == reflect.Slice:
+		if d.NilIsZero {
+			if av.Len() == 0 && bv.Len() == 0 {
+				return nil
+			}
+		} else if cmpNil() {
+			return nil
+		}
+		if d.Unordered != nil && d.Unordered(p) {
+			same, err := d.sameElements(p, av, bv)
+			if err != nil {
+				return err
+			}
+			if !same {
+				add(items, DiffDifferent, p, av, bv)
+			}
+			return nil
+		}
+		// If we find the list lengths are difference, don't recurse into a list
+		// to compare item by item. There isn't a use case for a more fine grain
+		// diff within a slice at the moment.
+		if av.Len() != bv.Len() {
+			add(items, DiffDifferent, p, av, bv)
+			return nil
+		}
+		for i := 0; i < av.Len(); i++ {
+			if err := d.do(p.Index(i), av.Index(i), bv.Index(i), items); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case av.Type().Kind() == reflect.Map:
+		if !d.NilIsZero {
+			if cmpNil() {
+				return nil
+			}
+			if av.Len() != bv.Len() {
+				add(items, DiffDifferent, p, av, bv)
+				return nil
+			}
+		}
+		// Compare the union of the keys, in a stable order.
+		keys := map[string]reflect.Value{}
+		for _, k := range av.MapKeys() {
+			keys[fmt.Sprint(k.Interface())] = k
+		}
+		for _, k := range bv.MapKeys() {
+			keys[fmt.Sprint(k.Interface())] = k
+		}
+		var names []string
+		for k := range keys {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			mp := p.MapIndex(name)
+			amv, bmv := av.MapIndex(keys[name]), bv.MapIndex(keys[name])
+			if !d.NilIsZero {
+				if !bmv.IsValid() {
+					add(items, DiffOnlyInA, mp, amv, bmv)
+					continue
+				}
+				if !amv.IsValid() {
+					add(items, DiffOnlyInB, mp, amv, bmv)
+					continue
+				}
+			}
+			if !amv.IsValid() {
+				amv = reflect.Zero(av.Type().Elem())
+			}
+			if !bmv.IsValid() {
+				bmv = reflect.Zero(bv.Type().Elem())
+			}
+			if err := d.do(mp, amv, bmv, items); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case av.Type().Kind() == reflect.Interface:
+		if !reflect.DeepEqual(av.Interface(), bv.Interface()) {
+			add(items, DiffDifferent, p, av, bv)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("differ: invalid type %s at %s", av.Type(), p)
+}
+
+// sameElements returns true if the slices a and b have the same elements,
+// regardless of the order.
+func (d *Differ) sameElements(p Path, a, b reflect.Value) (bool, error) {
+	if a.Len() != b.Len() {
+		return false, nil
+	}
+	matched := make([]bool, b.Len())
+	for i := 0; i < a.Len(); i++ {
+		found := false
+		for j := 0; j < b.Len(); j++ {
+			if matched[j] {
+				continue
+			}
+			var items []DiffItem
+			if err := d.do(p.Index(i), a.Index(i), b.Index(j), &items); err != nil {
+				return false, err
+			}
+			if len(items) == 0 {
+				matched[j] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiffer(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		I int
+	}
+	type st struct {
+		I   int
+		PSt *sti
+		LS  []string
+		L   []sti
+		M   map[string]string
+	}
+
+	for _, tc := range []struct {
+		name string
+		d    Differ
+		a, b st
+		want []string
+	}{
+		{
+			name: "equal",
+			a:    st{I: 1, LS: []string{"a"}, M: map[string]string{"a": "b"}},
+			b:    st{I: 1, LS: []string{"a"}, M: map[string]string{"a": "b"}},
+		},
+		{
+			name: "nil is only in A or B",
+			a:    st{LS: []string{}},
+			b:    st{PSt: &sti{}},
+			want: []string{"OnlyInB *.PSt", "OnlyInA *.LS"},
+		},
+		{
+			name: "NilIsZero",
+			a:    st{LS: []string{}, M: map[string]string{"a": ""}},
+			b:    st{PSt: &sti{}},
+			d:    Differ{NilIsZero: true},
+		},
+		{
+			name: "map keys",
+			a:    st{M: map[string]string{"a": "1", "b": "2"}},
+			b:    st{M: map[string]string{"b": "3", "c": "4"}},
+			want: []string{"OnlyInA *.M:a", "Different *.M:b", "OnlyInB *.M:c"},
+		},
+		{
+			name: "unordered",
+			a:    st{L: []sti{{I: 1}, {I: 2}}, LS: []string{"a", "b"}},
+			b:    st{L: []sti{{I: 2}, {I: 1}}, LS: []string{"b", "a"}},
+			d:    Differ{Unordered: func(p Path) bool { return p.Equal(Path{}.Pointer().Field("L")) }},
+			want: []string{"Different *.LS!0", "Different *.LS!1"},
+		},
+		{
+			name: "ignore",
+			a:    st{I: 1, PSt: &sti{I: 1}},
+			b:    st{I: 2, PSt: &sti{I: 2}},
+			d:    Differ{Ignore: func(p Path) bool { return p.Equal(Path{}.Pointer().Field("I")) }},
+			want: []string{"Different *.PSt*.I"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			items, err := tc.d.Diff(reflect.ValueOf(&tc.a), reflect.ValueOf(&tc.b))
+			if err != nil {
+				t.Fatalf("Diff() = %v, want nil", err)
+			}
+			var got []string
+			for _, item := range items {
+				got = append(got, string(item.State)+" "+item.Path.String())
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Diff(): -want +got: %s", diff)
+			}
+		})
+	}
+}
+
+func TestDifferInvalidType(t *testing.T) {
+	t.Parallel()
+
+	type invalidSt struct {
+		C chan int
+	}
+	d := &Differ{}
+	if _, err := d.Diff(reflect.ValueOf(&invalidSt{}), reflect.ValueOf(&invalidSt{})); err == nil {
+		t.Error("Diff() = nil, want err")
+	}
+}
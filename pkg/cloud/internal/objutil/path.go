@@ -0,0 +1,217 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package objutil copies and compares the API objects, e.g. between the
+// versions of a resource. It is shared by the cloud and api packages.
+package objutil
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Path specifies a field in nested object. The type of the reference
+// is given by the first character:
+//
+// - "." is a field reference
+// - "!" is a slice index
+// - ":" is a map key
+// - "*" is a pointer deref.
+type Path []string
+
+// The types of the elements of a Path.
+const (
+	PathField      = '.'
+	PathSliceIndex = '!'
+	PathMapIndex   = ':'
+	PathPointer    = '*'
+)
+
+// Field returns the path extended with a struct field reference.
+func (p Path) Field(name string) Path {
+	return append(p[:len(p):len(p)], string(PathField)+name)
+}
+
+// Index returns the path extended with a slice dereference.
+func (p Path) Index(i int) Path {
+	return append(p[:len(p):len(p)], fmt.Sprintf("%c%d", PathSliceIndex, i))
+}
+
+// MapIndex returns the path extended with a map index.
+func (p Path) MapIndex(k any) Path {
+	return append(p[:len(p):len(p)], fmt.Sprintf("%c%v", PathMapIndex, k))
+}
+
+// Pointer returns the path extended with a pointer dereference.
+func (p Path) Pointer() Path {
+	return append(p[:len(p):len(p)], string(PathPointer))
+}
+
+// Equal returns true if other is the same path.
+func (p Path) Equal(other Path) bool {
+	if len(p) != len(other) {
+		return false
+	}
+	for i := range p {
+		if p[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// HasPrefix returns true if prefix is the prefix of this path.
+func (p Path) HasPrefix(prefix Path) bool {
+	if len(prefix) == 0 {
+		return true
+	}
+	if len(prefix) > len(p) {
+		return false
+	}
+
+	var i int
+	for i = range prefix {
+		if p[i] != prefix[i] {
+			return false
+		}
+	}
+	if i != len(prefix)-1 {
+		return false
+	}
+	return true
+}
+
+// String implements Stringer.
+func (p Path) String() string {
+	return strings.Join(p, "")
+}
+
+// ResolveType will attempt to traverse the type with the Path and return the
+// type of the field.
+func (p Path) ResolveType(t reflect.Type) (reflect.Type, error) {
+	for i, x := range p {
+		switch x[0] {
+		case PathField:
+			if t.Kind() != reflect.Struct {
+				return nil, fmt.Errorf("at %s element %d, expected struct, got %s", p, i, t)
+			}
+			fieldName := x[1:]
+			sf, ok := t.FieldByName(fieldName)
+			if !ok {
+				return nil, fmt.Errorf("at %s element %d, no field named %q", p, i, fieldName)
+			}
+			t = sf.Type
+		case PathSliceIndex:
+			if t.Kind() != reflect.Slice {
+				return nil, fmt.Errorf("at %s element %d, expected slice, got %s", p, i, t)
+			}
+			t = t.Elem()
+		case PathMapIndex:
+			if t.Kind() != reflect.Map {
+				return nil, fmt.Errorf("at %s element %d, expected map, got %s", p, i, t)
+			}
+			t = t.Elem()
+		case PathPointer:
+			if t.Kind() != reflect.Pointer {
+				return nil, fmt.Errorf("at %s element %d, expected pointer, got %s", p, i, t)
+			}
+			t = t.Elem()
+		default:
+			return nil, fmt.Errorf("at %s element %d, invalid path type %q", p, i, x[0])
+		}
+	}
+	return t, nil
+}
+
+// JSONPath returns the path as the JSON names of the fields of t, e.g.
+// "pathMatchers[0].defaultService" or "labels.app". Pointer dereferences are
+// omitted.
+func (p Path) JSONPath(t reflect.Type) string {
+	return p.jsonPath(t, true)
+}
+
+// JSONFields is like JSONPath, but only contains the field names, e.g.
+// "pathMatchers.defaultService" or "labels".
+func (p Path) JSONFields(t reflect.Type) string {
+	return p.jsonPath(t, false)
+}
+
+func (p Path) jsonPath(t reflect.Type, indices bool) string {
+	var b strings.Builder
+	// elem traverses t, which is nil once the path no longer matches the
+	// type.
+	elem := func(kinds ...reflect.Kind) {
+		if t == nil {
+			return
+		}
+		for _, k := range kinds {
+			if t.Kind() == k {
+				t = t.Elem()
+				return
+			}
+		}
+		t = nil
+	}
+	for _, x := range p {
+		if x == "" {
+			continue
+		}
+		switch x[0] {
+		case PathField:
+			name := x[1:]
+			if t != nil && t.Kind() == reflect.Struct {
+				if sf, ok := t.FieldByName(name); ok {
+					name, t = JSONName(sf), sf.Type
+				} else {
+					t = nil
+				}
+			} else {
+				t = nil
+			}
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(name)
+		case PathSliceIndex:
+			elem(reflect.Slice, reflect.Array)
+			if indices {
+				fmt.Fprintf(&b, "[%s]", x[1:])
+			}
+		case PathMapIndex:
+			elem(reflect.Map)
+			if indices {
+				if b.Len() > 0 {
+					b.WriteByte('.')
+				}
+				b.WriteString(x[1:])
+			}
+		case PathPointer:
+			elem(reflect.Pointer)
+		}
+	}
+	return b.String()
+}
+
+// JSONName returns the JSON name of f, which is the Go name of the field if
+// it does not have a JSON tag.
+func JSONName(f reflect.StructField) string {
+	name := strings.Split(f.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		name = f.Name
+	}
+	return name
+}
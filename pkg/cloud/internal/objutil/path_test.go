@@ -0,0 +1,67 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPathJSON(t *testing.T) {
+	t.Parallel()
+
+	type inner struct {
+		Service string `json:"defaultService,omitempty"`
+	}
+	type st struct {
+		Matchers []*inner         `json:"pathMatchers,omitempty"`
+		Labels   map[string]inner `json:"labels,omitempty"`
+		NoTag    string
+	}
+	typ := reflect.TypeOf(&st{})
+
+	for _, tc := range []struct {
+		path       Path
+		wantPath   string
+		wantFields string
+	}{
+		{path: Path{}, wantPath: "", wantFields: ""},
+		{path: Path{}.Pointer().Field("NoTag"), wantPath: "NoTag", wantFields: "NoTag"},
+		{
+			path:       Path{}.Pointer().Field("Matchers").Index(2).Pointer().Field("Service"),
+			wantPath:   "pathMatchers[2].defaultService",
+			wantFields: "pathMatchers.defaultService",
+		},
+		{
+			path:       Path{}.Pointer().Field("Labels").MapIndex("app").Field("Service"),
+			wantPath:   "labels.app.defaultService",
+			wantFields: "labels.defaultService",
+		},
+		{
+			path:       Path{}.Pointer().Field("Unknown").Field("X"),
+			wantPath:   "Unknown.X",
+			wantFields: "Unknown.X",
+		},
+	} {
+		if got := tc.path.JSONPath(typ); got != tc.wantPath {
+			t.Errorf("%s.JSONPath() = %q, want %q", tc.path, got, tc.wantPath)
+		}
+		if got := tc.path.JSONFields(typ); got != tc.wantFields {
+			t.Errorf("%s.JSONFields() = %q, want %q", tc.path, got, tc.wantFields)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/go-logr/logr"
+)
+
+// Verbosity of the structured logs written to Service.Logger and the mock
+// Logger.
+const (
+	// LogLevelError is used for failed calls and operations.
+	LogLevelError = 2
+	// LogLevelCall is used for each call to the API and mock.
+	LogLevelCall = 4
+	// LogLevelPoll is used for each poll of a long running operation.
+	LogLevelPoll = 5
+)
+
+// logCall logs the result of a call to the API with the fields
+// "project", "resource", "verb", "version", "key", "requestId", "latency",
+// "errorClass" and "err".
+func logCall(log logr.Logger, ck *CallContextKey, key *meta.Key, requestID string, latency time.Duration, err error) {
+	kv := []interface{}{
+		"project", ck.ProjectID,
+		"resource", ck.Service,
+		"verb", ck.Operation,
+		"version", ck.Version,
+		"latency", latency,
+	}
+	if key != nil {
+		kv = append(kv, "key", key.String())
+	}
+	if requestID != "" {
+		kv = append(kv, "requestId", requestID)
+	}
+	if err != nil {
+		log.V(LogLevelError).Info("API call failed", append(kv, "errorClass", ClassifyError(err), "err", err)...)
+		return
+	}
+	log.V(LogLevelCall).Info("API call", kv...)
+}
+
+// mockLog logs a call to a mock with the fields "resource", "verb", "key"
+// and "err". The zero value of log discards the logs.
+func mockLog(log logr.Logger, resource, verb string, key *meta.Key, err error) {
+	kv := []interface{}{
+		"resource", resource,
+		"verb", verb,
+	}
+	if key != nil {
+		kv = append(kv, "key", key.String())
+	}
+	if err != nil {
+		kv = append(kv, "err", err)
+	}
+	log.V(LogLevelCall).Info("mock call", kv...)
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/go-logr/logr"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+)
+
+type logEntry struct {
+	level int
+	msg   string
+	kv    map[string]interface{}
+}
+
+// fakeLogger is a logr.LogSink recording every entry regardless of the
+// level.
+type fakeLogger struct {
+	lock    *sync.Mutex
+	entries *[]logEntry
+}
+
+func newFakeLogger() *fakeLogger {
+	return &fakeLogger{lock: &sync.Mutex{}, entries: &[]logEntry{}}
+}
+
+// logger returns a logr.Logger writing to l.
+func (l *fakeLogger) logger() logr.Logger { return logr.New(l) }
+
+func (l *fakeLogger) Init(info logr.RuntimeInfo) {}
+
+func (l *fakeLogger) Enabled(level int) bool { return true }
+
+func (l *fakeLogger) Info(level int, msg string, keysAndValues ...interface{}) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	e := logEntry{level: level, msg: msg, kv: map[string]interface{}{}}
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		e.kv[keysAndValues[i].(string)] = keysAndValues[i+1]
+	}
+	*l.entries = append(*l.entries, e)
+}
+
+func (l *fakeLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.Info(0, msg, append(keysAndValues, "err", err)...)
+}
+
+func (l *fakeLogger) WithValues(keysAndValues ...interface{}) logr.LogSink { return l }
+func (l *fakeLogger) WithName(name string) logr.LogSink                    { return l }
+
+func (l *fakeLogger) find(msg string) []logEntry {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	var ret []logEntry
+	for _, e := range *l.entries {
+		if e.msg == msg {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+func TestServiceLogger(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/proj/global/addresses/a1", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "a1"}`))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+	log := newFakeLogger()
+	c := NewGCE(&Service{
+		GA:            client,
+		ProjectRouter: &SingleProjectRouter{"proj"},
+		RateLimiter:   &NopRateLimiter{},
+		Logger:        log.logger(),
+	})
+
+	if _, err := c.GlobalAddresses().Get(ctx, meta.GlobalKey("a1")); err != nil {
+		t.Fatalf("Get(a1) = _, %v, want nil", err)
+	}
+	if _, err := c.GlobalAddresses().Get(ctx, meta.GlobalKey("a2")); err == nil {
+		t.Fatalf("Get(a2) = _, nil, want error")
+	}
+
+	calls := log.find("API call")
+	if len(calls) != 1 {
+		t.Fatalf("got %d \"API call\" entries, want 1", len(calls))
+	}
+	for k, want := range map[string]interface{}{
+		"project":  "proj",
+		"resource": "GlobalAddresses",
+		"verb":     "Get",
+		"version":  meta.VersionGA,
+		"key":      meta.GlobalKey("a1").String(),
+	} {
+		if got := calls[0].kv[k]; got != want {
+			t.Errorf("entry[%q] = %v, want %v", k, got, want)
+		}
+	}
+	if calls[0].level != LogLevelCall {
+		t.Errorf("level = %d, want %d", calls[0].level, LogLevelCall)
+	}
+	if _, ok := calls[0].kv["latency"]; !ok {
+		t.Errorf("entry has no latency: %v", calls[0].kv)
+	}
+
+	failed := log.find("API call failed")
+	if len(failed) != 1 {
+		t.Fatalf("got %d \"API call failed\" entries, want 1", len(failed))
+	}
+	var gerr *googleapi.Error
+	if err, ok := failed[0].kv["err"].(error); !ok || !errors.As(err, &gerr) || gerr.Code != http.StatusNotFound {
+		t.Errorf("entry[\"err\"] = %v, want a not found error", failed[0].kv["err"])
+	}
+	if got := failed[0].kv["errorClass"]; got != ErrorClassClient {
+		t.Errorf("entry[\"errorClass\"] = %v, want %v", got, ErrorClassClient)
+	}
+	if failed[0].level != LogLevelError {
+		t.Errorf("level = %d, want %d", failed[0].level, LogLevelError)
+	}
+}
+
+func TestMockLogger(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	log := newFakeLogger()
+	mock := NewMockGCE(&SingleProjectRouter{"proj"})
+	mock.SetLogger(log.logger())
+
+	key := meta.GlobalKey("a1")
+	if err := mock.GlobalAddresses().Insert(ctx, key, &ga.Address{}); err != nil {
+		t.Fatalf("Insert(%v) = %v, want nil", key, err)
+	}
+	if err := mock.GlobalAddresses().Insert(ctx, key, &ga.Address{}); err == nil {
+		t.Fatalf("Insert(%v) = nil, want error", key)
+	}
+	if err := mock.GlobalAddresses().Delete(ctx, key); err != nil {
+		t.Fatalf("Delete(%v) = %v, want nil", key, err)
+	}
+
+	entries := log.find("mock call")
+	if len(entries) != 3 {
+		t.Fatalf("got %d \"mock call\" entries, want 3", len(entries))
+	}
+	for i, want := range []struct {
+		verb    string
+		wantErr bool
+	}{
+		{"Insert", false},
+		{"Insert", true},
+		{"Delete", false},
+	} {
+		e := entries[i]
+		_, hasErr := e.kv["err"]
+		if e.kv["resource"] != "GlobalAddresses" || e.kv["verb"] != want.verb || e.kv["key"] != key.String() || hasErr != want.wantErr {
+			t.Errorf("entries[%d] = %v, want resource GlobalAddresses, verb %s, key %v, err %t", i, e.kv, want.verb, key, want.wantErr)
+		}
+	}
+}
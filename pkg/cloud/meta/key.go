@@ -19,6 +19,7 @@ package meta
 import (
 	"fmt"
 	"regexp"
+	"strings"
 )
 
 // Key for a GCP resource.
@@ -98,6 +99,42 @@ func (k *Key) Valid() bool {
 	return true
 }
 
+// Normalize returns a copy of the key with the Zone and Region canonicalized
+// with NormalizeLocation. Keys built from values taken from different sources
+// (e.g. a zone URL from one object and a short zone name from another) will
+// compare equal after normalization.
+func (k *Key) Normalize() *Key {
+	return &Key{
+		Name:   k.Name,
+		Zone:   NormalizeLocation(k.Zone),
+		Region: NormalizeLocation(k.Region),
+	}
+}
+
+// Equal returns true if the two keys refer to the same resource. Locations
+// are compared after normalization.
+func (k *Key) Equal(other *Key) bool {
+	switch {
+	case k == nil && other == nil:
+		return true
+	case k == nil || other == nil:
+		return false
+	}
+	return *k.Normalize() == *other.Normalize()
+}
+
+// NormalizeLocation canonicalizes a zone or region value. The value may be a
+// full resource URL, a partial path ("zones/us-central1-b",
+// "projects/p/regions/us-central1") or a short name in any case. The short,
+// lowercase name is returned, e.g. "us-central1-b".
+func NormalizeLocation(loc string) string {
+	loc = strings.TrimSuffix(strings.TrimSpace(loc), "/")
+	if i := strings.LastIndex(loc, "/"); i >= 0 {
+		loc = loc[i+1:]
+	}
+	return strings.ToLower(loc)
+}
+
 // KeysToMap creates a map[Key]bool from a list of keys.
 func KeysToMap(keys ...Key) map[Key]bool {
 	ret := map[Key]bool{}
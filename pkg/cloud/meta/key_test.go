@@ -74,3 +74,48 @@ func TestKeyValid(t *testing.T) {
 		}
 	}
 }
+
+func TestNormalizeLocation(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"us-central1", "us-central1"},
+		{"US-Central1-B", "us-central1-b"},
+		{" us-central1 ", "us-central1"},
+		{"zones/us-central1-b", "us-central1-b"},
+		{"regions/us-central1/", "us-central1"},
+		{"projects/my-proj/regions/us-central1", "us-central1"},
+		{"https://www.googleapis.com/compute/v1/projects/my-proj/zones/us-central1-b", "us-central1-b"},
+	} {
+		if got := NormalizeLocation(tc.in); got != tc.want {
+			t.Errorf("NormalizeLocation(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestKeyEqual(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		a, b *Key
+		want bool
+	}{
+		{nil, nil, true},
+		{GlobalKey("abc"), nil, false},
+		{GlobalKey("abc"), GlobalKey("abc"), true},
+		{GlobalKey("abc"), GlobalKey("def"), false},
+		{RegionalKey("abc", "us-central1"), RegionalKey("abc", "US-CENTRAL1"), true},
+		{RegionalKey("abc", "us-central1"), RegionalKey("abc", "projects/p/regions/us-central1"), true},
+		{ZonalKey("abc", "us-central1-b"), ZonalKey("abc", "https://www.googleapis.com/compute/v1/projects/p/zones/us-central1-b"), true},
+		{ZonalKey("abc", "us-central1-b"), ZonalKey("abc", "us-central1-c"), false},
+		{ZonalKey("abc", "us-central1"), RegionalKey("abc", "us-central1"), false},
+	} {
+		if got := tc.a.Equal(tc.b); got != tc.want {
+			t.Errorf("%v.Equal(%v) = %t, want %t", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
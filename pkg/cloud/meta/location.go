@@ -0,0 +1,214 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package meta
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	networkconnectivity "google.golang.org/api/networkconnectivity/v1"
+	networkservices "google.golang.org/api/networkservices/v1"
+)
+
+// LocationServiceInfo defines the entry for a resource of an API other
+// than compute that code will be generated for. The resources are named by
+// location, e.g. "projects/p/locations/global/gateways/gw", and are all
+// GA.
+type LocationServiceInfo struct {
+	// API is the Go name of the interface of the API in Cloud, e.g.
+	// "NetworkServices".
+	API string
+	// Object is the Go name of the object type. Example: "HttpRoute".
+	Object string
+	// Service is the Go name of the method of the API interface that
+	// returns the resource. Example: "HTTPRoutes".
+	Service string
+	// Resource is the plural noun of the resource in the resource name
+	// (e.g. "httpRoutes").
+	Resource string
+	// GlobalOnly is true if the resource only exists in the "global"
+	// location.
+	GlobalOnly bool
+	// collection is the path of the service of the resource from the
+	// ProjectsLocationsService of the API, e.g. "Global.Hubs".
+	collection  string
+	serviceType reflect.Type
+}
+
+// AllLocationServices are the resources of the APIs other than compute to
+// generate code for. Keep this list grouped by API and in the order of the
+// methods of the API interface.
+var AllLocationServices = []*LocationServiceInfo{
+	{
+		API:         "NetworkServices",
+		Object:      "Gateway",
+		Service:     "Gateways",
+		Resource:    "gateways",
+		collection:  "Gateways",
+		serviceType: reflect.TypeOf(&networkservices.ProjectsLocationsGatewaysService{}),
+	},
+	{
+		API:         "NetworkServices",
+		Object:      "HttpRoute",
+		Service:     "HTTPRoutes",
+		Resource:    "httpRoutes",
+		collection:  "HttpRoutes",
+		serviceType: reflect.TypeOf(&networkservices.ProjectsLocationsHttpRoutesService{}),
+	},
+	{
+		API:         "NetworkServices",
+		Object:      "TcpRoute",
+		Service:     "TCPRoutes",
+		Resource:    "tcpRoutes",
+		collection:  "TcpRoutes",
+		serviceType: reflect.TypeOf(&networkservices.ProjectsLocationsTcpRoutesService{}),
+	},
+	{
+		API:         "NetworkServices",
+		Object:      "TlsRoute",
+		Service:     "TLSRoutes",
+		Resource:    "tlsRoutes",
+		collection:  "TlsRoutes",
+		serviceType: reflect.TypeOf(&networkservices.ProjectsLocationsTlsRoutesService{}),
+	},
+	{
+		API:         "NetworkServices",
+		Object:      "EndpointPolicy",
+		Service:     "EndpointPolicies",
+		Resource:    "endpointPolicies",
+		collection:  "EndpointPolicies",
+		serviceType: reflect.TypeOf(&networkservices.ProjectsLocationsEndpointPoliciesService{}),
+	},
+	{
+		API:         "NetworkConnectivity",
+		Object:      "Hub",
+		Service:     "Hubs",
+		Resource:    "hubs",
+		GlobalOnly:  true,
+		collection:  "Global.Hubs",
+		serviceType: reflect.TypeOf(&networkconnectivity.ProjectsLocationsGlobalHubsService{}),
+	},
+	{
+		API:         "NetworkConnectivity",
+		Object:      "Spoke",
+		Service:     "Spokes",
+		Resource:    "spokes",
+		collection:  "Spokes",
+		serviceType: reflect.TypeOf(&networkconnectivity.ProjectsLocationsSpokesService{}),
+	},
+}
+
+// Package is the name of the Go package of the API, e.g.
+// "networkservices".
+func (i *LocationServiceInfo) Package() string {
+	return strings.ToLower(i.API)
+}
+
+// GoPackage is the import path of the Go package of the API.
+func (i *LocationServiceInfo) GoPackage() string {
+	return i.serviceType.Elem().PkgPath()
+}
+
+// FQObjectType is the fully qualified name of the object (e.g.
+// networkservices.Gateway).
+func (i *LocationServiceInfo) FQObjectType() string {
+	return fmt.Sprintf("%v.%v", i.Package(), i.Object)
+}
+
+// GCEWrapType is the name of the GCE wrapper type.
+func (i *LocationServiceInfo) GCEWrapType() string {
+	return "GCE" + i.Service
+}
+
+// Field is the name of the field in the GCE struct of the API.
+func (i *LocationServiceInfo) Field() string {
+	return "gce" + i.Service
+}
+
+// Collection is the expression for the service of the resource from the
+// ProjectsLocationsService of the API, e.g. "Global.Hubs".
+func (i *LocationServiceInfo) Collection() string {
+	return i.collection
+}
+
+// apiMethod is the method of the API for the verb used in Cloud.
+func apiMethod(verb string) string {
+	if verb == "Insert" {
+		return "Create"
+	}
+	return verb
+}
+
+// call returns the type of the call returned by the API method for verb
+// (e.g. "Insert") or panics if the API has no such method.
+func (i *LocationServiceInfo) call(verb string) reflect.Type {
+	m, ok := i.serviceType.MethodByName(apiMethod(verb))
+	if !ok || m.Type.NumOut() != 1 {
+		panic(fmt.Errorf("method %q was not found in service %q", apiMethod(verb), i.Service))
+	}
+	return m.Type.Out(0)
+}
+
+// IDMethod is the method of the Create call that sets the name of the new
+// resource, e.g. "GatewayId".
+func (i *LocationServiceInfo) IDMethod() string {
+	name := i.Object + "Id"
+	if _, ok := i.call("Insert").MethodByName(name); !ok {
+		panic(fmt.Errorf("method %q was not found in the Create call of service %q", name, i.Service))
+	}
+	return name
+}
+
+// ListResponseType is the fully qualified name of the response of the List
+// call, e.g. networkservices.ListGatewaysResponse.
+func (i *LocationServiceInfo) ListResponseType() string {
+	t := doResultType(i.serviceType, "List")
+	if t == nil {
+		panic(fmt.Errorf("method %q was not found in service %q", "List", i.Service))
+	}
+	return fmt.Sprintf("%v.%v", i.Package(), t.Name())
+}
+
+// ListItemsField is the field of the response of the List call with the
+// objects, e.g. "Gateways".
+func (i *LocationServiceInfo) ListItemsField() string {
+	t := doResultType(i.serviceType, "List")
+	want := reflect.PtrTo(doResultType(i.serviceType, "Get"))
+	for j := 0; t != nil && j < t.NumField(); j++ {
+		if f := t.Field(j); f.Type.Kind() == reflect.Slice && f.Type.Elem() == want {
+			return f.Name
+		}
+	}
+	panic(fmt.Errorf("no field of %v in the List response of service %q", want, i.Service))
+}
+
+// SupportsRequestID is true if the call of the given verb (e.g. "Insert")
+// has a requestId parameter.
+func (i *LocationServiceInfo) SupportsRequestID(verb string) bool {
+	m, ok := i.serviceType.MethodByName(apiMethod(verb))
+	if !ok {
+		return false
+	}
+	_, ok = m.Type.Out(0).MethodByName("RequestId")
+	return ok
+}
+
+// Idempotency of the given verb (e.g. "Insert") of the service.
+func (i *LocationServiceInfo) Idempotency(verb string) Idempotency {
+	return VerbIdempotency(verb, i.SupportsRequestID(verb))
+}
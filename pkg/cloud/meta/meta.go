@@ -73,7 +73,10 @@ var AllServices = []*ServiceInfo{
 		Resource:    "addresses",
 		keyType:     Regional,
 		serviceType: reflect.TypeOf(&ga.AddressesService{}),
-		options:     AggregatedList,
+		additionalMethods: []string{
+			"SetLabels",
+		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "Address",
@@ -82,7 +85,10 @@ var AllServices = []*ServiceInfo{
 		version:     VersionAlpha,
 		keyType:     Regional,
 		serviceType: reflect.TypeOf(&alpha.AddressesService{}),
-		options:     AggregatedList,
+		additionalMethods: []string{
+			"SetLabels",
+		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "Address",
@@ -91,7 +97,10 @@ var AllServices = []*ServiceInfo{
 		version:     VersionBeta,
 		keyType:     Regional,
 		serviceType: reflect.TypeOf(&beta.AddressesService{}),
-		options:     AggregatedList,
+		additionalMethods: []string{
+			"SetLabels",
+		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "Address",
@@ -100,6 +109,9 @@ var AllServices = []*ServiceInfo{
 		version:     VersionAlpha,
 		keyType:     Global,
 		serviceType: reflect.TypeOf(&alpha.GlobalAddressesService{}),
+		additionalMethods: []string{
+			"SetLabels",
+		},
 	},
 	{
 		Object:      "Address",
@@ -108,6 +120,9 @@ var AllServices = []*ServiceInfo{
 		version:     VersionBeta,
 		keyType:     Global,
 		serviceType: reflect.TypeOf(&beta.GlobalAddressesService{}),
+		additionalMethods: []string{
+			"SetLabels",
+		},
 	},
 	{
 		Object:      "Address",
@@ -115,6 +130,9 @@ var AllServices = []*ServiceInfo{
 		Resource:    "addresses",
 		keyType:     Global,
 		serviceType: reflect.TypeOf(&ga.GlobalAddressesService{}),
+		additionalMethods: []string{
+			"SetLabels",
+		},
 	},
 	{
 		Object:      "BackendService",
@@ -126,9 +144,27 @@ var AllServices = []*ServiceInfo{
 			"GetHealth",
 			"Patch",
 			"Update",
-			"SetSecurityPolicy",
-			"AddSignedUrlKey",
-			"DeleteSignedUrlKey",
+		},
+		verbs: []Verb{
+			{
+				Name:     "SetSecurityPolicy",
+				Request:  reflect.TypeOf(&ga.SecurityPolicyReference{}),
+				Response: reflect.TypeOf(&ga.Operation{}),
+			},
+			{
+				Name:     "SetEdgeSecurityPolicy",
+				Request:  reflect.TypeOf(&ga.SecurityPolicyReference{}),
+				Response: reflect.TypeOf(&ga.Operation{}),
+			},
+			{
+				Name:     "AddSignedUrlKey",
+				Request:  reflect.TypeOf(&ga.SignedUrlKey{}),
+				Response: reflect.TypeOf(&ga.Operation{}),
+			},
+			{
+				Name:     "DeleteSignedUrlKey",
+				Response: reflect.TypeOf(&ga.Operation{}),
+			},
 		},
 		options: AggregatedList,
 	},
@@ -142,9 +178,27 @@ var AllServices = []*ServiceInfo{
 		additionalMethods: []string{
 			"Patch",
 			"Update",
-			"SetSecurityPolicy",
-			"AddSignedUrlKey",
-			"DeleteSignedUrlKey",
+		},
+		verbs: []Verb{
+			{
+				Name:     "SetSecurityPolicy",
+				Request:  reflect.TypeOf(&beta.SecurityPolicyReference{}),
+				Response: reflect.TypeOf(&beta.Operation{}),
+			},
+			{
+				Name:     "SetEdgeSecurityPolicy",
+				Request:  reflect.TypeOf(&beta.SecurityPolicyReference{}),
+				Response: reflect.TypeOf(&beta.Operation{}),
+			},
+			{
+				Name:     "AddSignedUrlKey",
+				Request:  reflect.TypeOf(&beta.SignedUrlKey{}),
+				Response: reflect.TypeOf(&beta.Operation{}),
+			},
+			{
+				Name:     "DeleteSignedUrlKey",
+				Response: reflect.TypeOf(&beta.Operation{}),
+			},
 		},
 		options: AggregatedList,
 	},
@@ -158,9 +212,27 @@ var AllServices = []*ServiceInfo{
 		additionalMethods: []string{
 			"Patch",
 			"Update",
-			"SetSecurityPolicy",
-			"AddSignedUrlKey",
-			"DeleteSignedUrlKey",
+		},
+		verbs: []Verb{
+			{
+				Name:     "SetSecurityPolicy",
+				Request:  reflect.TypeOf(&alpha.SecurityPolicyReference{}),
+				Response: reflect.TypeOf(&alpha.Operation{}),
+			},
+			{
+				Name:     "SetEdgeSecurityPolicy",
+				Request:  reflect.TypeOf(&alpha.SecurityPolicyReference{}),
+				Response: reflect.TypeOf(&alpha.Operation{}),
+			},
+			{
+				Name:     "AddSignedUrlKey",
+				Request:  reflect.TypeOf(&alpha.SignedUrlKey{}),
+				Response: reflect.TypeOf(&alpha.Operation{}),
+			},
+			{
+				Name:     "DeleteSignedUrlKey",
+				Response: reflect.TypeOf(&alpha.Operation{}),
+			},
 		},
 		options: AggregatedList,
 	},
@@ -189,6 +261,13 @@ var AllServices = []*ServiceInfo{
 			"Patch",
 			"Update",
 		},
+		verbs: []Verb{
+			{
+				Name:     "SetSecurityPolicy",
+				Request:  reflect.TypeOf(&alpha.SecurityPolicyReference{}),
+				Response: reflect.TypeOf(&alpha.Operation{}),
+			},
+		},
 	},
 	{
 		Object:      "BackendService",
@@ -211,7 +290,10 @@ var AllServices = []*ServiceInfo{
 		serviceType: reflect.TypeOf(&ga.DisksService{}),
 		additionalMethods: []string{
 			"Resize",
+			"Update",
+			"SetLabels",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "Disk",
@@ -222,6 +304,8 @@ var AllServices = []*ServiceInfo{
 		serviceType: reflect.TypeOf(&ga.RegionDisksService{}),
 		additionalMethods: []string{
 			"Resize",
+			"Update",
+			"SetLabels",
 		},
 	},
 	{
@@ -313,6 +397,7 @@ var AllServices = []*ServiceInfo{
 			"SetTarget",
 			"SetLabels",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "ForwardingRule",
@@ -325,6 +410,7 @@ var AllServices = []*ServiceInfo{
 			"SetTarget",
 			"SetLabels",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "ForwardingRule",
@@ -337,6 +423,7 @@ var AllServices = []*ServiceInfo{
 			"SetTarget",
 			"SetLabels",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "ForwardingRule",
@@ -373,6 +460,25 @@ var AllServices = []*ServiceInfo{
 			"SetLabels",
 		},
 	},
+	{
+		Object:      "FutureReservation",
+		Service:     "FutureReservations",
+		Resource:    "futureReservations",
+		version:     VersionAlpha,
+		keyType:     Zonal,
+		serviceType: reflect.TypeOf(&alpha.FutureReservationsService{}),
+		listType:    "FutureReservationsListResponse",
+		additionalMethods: []string{
+			"Update",
+		},
+		verbs: []Verb{
+			{
+				Name:     "Cancel",
+				Response: reflect.TypeOf(&alpha.Operation{}),
+			},
+		},
+		options: AggregatedList,
+	},
 	{
 		Object:      "HealthCheck",
 		Service:     "HealthChecks",
@@ -382,6 +488,7 @@ var AllServices = []*ServiceInfo{
 		additionalMethods: []string{
 			"Update",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "HealthCheck",
@@ -393,6 +500,7 @@ var AllServices = []*ServiceInfo{
 		additionalMethods: []string{
 			"Update",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "HealthCheck",
@@ -404,6 +512,7 @@ var AllServices = []*ServiceInfo{
 		additionalMethods: []string{
 			"Update",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "HealthCheck",
@@ -470,6 +579,7 @@ var AllServices = []*ServiceInfo{
 			"RemoveInstances",
 			"SetNamedPorts",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "Instance",
@@ -478,9 +588,46 @@ var AllServices = []*ServiceInfo{
 		keyType:     Zonal,
 		serviceType: reflect.TypeOf(&ga.InstancesService{}),
 		additionalMethods: []string{
-			"AttachDisk",
-			"DetachDisk",
+			"Update",
+			"SetLabels",
 		},
+		verbs: []Verb{
+			{
+				Name:     "AttachDisk",
+				Request:  reflect.TypeOf(&ga.AttachedDisk{}),
+				Response: reflect.TypeOf(&ga.Operation{}),
+			},
+			{
+				Name:     "DetachDisk",
+				Response: reflect.TypeOf(&ga.Operation{}),
+			},
+			{
+				Name:     "Start",
+				Response: reflect.TypeOf(&ga.Operation{}),
+			},
+			{
+				Name:     "Stop",
+				Response: reflect.TypeOf(&ga.Operation{}),
+			},
+			{
+				Name:     "Reset",
+				Response: reflect.TypeOf(&ga.Operation{}),
+			},
+			{
+				Name:     "Suspend",
+				Response: reflect.TypeOf(&ga.Operation{}),
+			},
+			{
+				Name:     "Resume",
+				Response: reflect.TypeOf(&ga.Operation{}),
+			},
+			{
+				Name:     "SetMachineType",
+				Request:  reflect.TypeOf(&ga.InstancesSetMachineTypeRequest{}),
+				Response: reflect.TypeOf(&ga.Operation{}),
+			},
+		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "Instance",
@@ -490,10 +637,48 @@ var AllServices = []*ServiceInfo{
 		keyType:     Zonal,
 		serviceType: reflect.TypeOf(&beta.InstancesService{}),
 		additionalMethods: []string{
-			"AttachDisk",
-			"DetachDisk",
 			"UpdateNetworkInterface",
+			"Update",
+			"SetLabels",
 		},
+		verbs: []Verb{
+			{
+				Name:     "AttachDisk",
+				Request:  reflect.TypeOf(&beta.AttachedDisk{}),
+				Response: reflect.TypeOf(&beta.Operation{}),
+			},
+			{
+				Name:     "DetachDisk",
+				Response: reflect.TypeOf(&beta.Operation{}),
+			},
+			{
+				Name:     "Start",
+				Response: reflect.TypeOf(&beta.Operation{}),
+			},
+			{
+				Name:     "Stop",
+				Response: reflect.TypeOf(&beta.Operation{}),
+			},
+			{
+				Name:     "Reset",
+				Response: reflect.TypeOf(&beta.Operation{}),
+			},
+			{
+				Name:     "Suspend",
+				Response: reflect.TypeOf(&beta.Operation{}),
+			},
+			{
+				Name:     "Resume",
+				Request:  reflect.TypeOf(&beta.InstancesResumeRequest{}),
+				Response: reflect.TypeOf(&beta.Operation{}),
+			},
+			{
+				Name:     "SetMachineType",
+				Request:  reflect.TypeOf(&beta.InstancesSetMachineTypeRequest{}),
+				Response: reflect.TypeOf(&beta.Operation{}),
+			},
+		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "Instance",
@@ -503,10 +688,48 @@ var AllServices = []*ServiceInfo{
 		keyType:     Zonal,
 		serviceType: reflect.TypeOf(&alpha.InstancesService{}),
 		additionalMethods: []string{
-			"AttachDisk",
-			"DetachDisk",
 			"UpdateNetworkInterface",
+			"Update",
+			"SetLabels",
+		},
+		verbs: []Verb{
+			{
+				Name:     "AttachDisk",
+				Request:  reflect.TypeOf(&alpha.AttachedDisk{}),
+				Response: reflect.TypeOf(&alpha.Operation{}),
+			},
+			{
+				Name:     "DetachDisk",
+				Response: reflect.TypeOf(&alpha.Operation{}),
+			},
+			{
+				Name:     "Start",
+				Response: reflect.TypeOf(&alpha.Operation{}),
+			},
+			{
+				Name:     "Stop",
+				Response: reflect.TypeOf(&alpha.Operation{}),
+			},
+			{
+				Name:     "Reset",
+				Response: reflect.TypeOf(&alpha.Operation{}),
+			},
+			{
+				Name:     "Suspend",
+				Response: reflect.TypeOf(&alpha.Operation{}),
+			},
+			{
+				Name:     "Resume",
+				Request:  reflect.TypeOf(&alpha.InstancesResumeRequest{}),
+				Response: reflect.TypeOf(&alpha.Operation{}),
+			},
+			{
+				Name:     "SetMachineType",
+				Request:  reflect.TypeOf(&alpha.InstancesSetMachineTypeRequest{}),
+				Response: reflect.TypeOf(&alpha.Operation{}),
+			},
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "InstanceGroupManager",
@@ -520,6 +743,18 @@ var AllServices = []*ServiceInfo{
 			"Resize",
 			"SetInstanceTemplate",
 		},
+		verbs: []Verb{
+			{
+				Name:     "RecreateInstances",
+				Request:  reflect.TypeOf(&ga.InstanceGroupManagersRecreateInstancesRequest{}),
+				Response: reflect.TypeOf(&ga.Operation{}),
+			},
+			{
+				Name:     "ListManagedInstances",
+				Response: reflect.TypeOf(&ga.InstanceGroupManagersListManagedInstancesResponse{}),
+			},
+		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "InstanceTemplate",
@@ -527,6 +762,36 @@ var AllServices = []*ServiceInfo{
 		Resource:    "instanceTemplates",
 		keyType:     Global,
 		serviceType: reflect.TypeOf(&ga.InstanceTemplatesService{}),
+		options:     AggregatedList,
+	},
+	{
+		Object:      "Interconnect",
+		Service:     "Interconnects",
+		Resource:    "interconnects",
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&ga.InterconnectsService{}),
+		additionalMethods: []string{
+			"Patch",
+			"SetLabels",
+		},
+		verbs: []Verb{
+			{
+				Name:     "GetDiagnostics",
+				Response: reflect.TypeOf(&ga.InterconnectsGetDiagnosticsResponse{}),
+			},
+		},
+	},
+	{
+		Object:      "InterconnectAttachment",
+		Service:     "InterconnectAttachments",
+		Resource:    "interconnectAttachments",
+		keyType:     Regional,
+		serviceType: reflect.TypeOf(&ga.InterconnectAttachmentsService{}),
+		additionalMethods: []string{
+			"Patch",
+			"SetLabels",
+		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "Image",
@@ -589,7 +854,7 @@ var AllServices = []*ServiceInfo{
 		Resource:    "networks",
 		version:     VersionBeta,
 		keyType:     Global,
-		serviceType: reflect.TypeOf(&alpha.NetworksService{}),
+		serviceType: reflect.TypeOf(&beta.NetworksService{}),
 	},
 	{
 		Object:      "Network",
@@ -597,7 +862,7 @@ var AllServices = []*ServiceInfo{
 		Resource:    "networks",
 		version:     VersionGA,
 		keyType:     Global,
-		serviceType: reflect.TypeOf(&alpha.NetworksService{}),
+		serviceType: reflect.TypeOf(&ga.NetworksService{}),
 	},
 	{
 		Object:      "NetworkEndpointGroup",
@@ -641,6 +906,74 @@ var AllServices = []*ServiceInfo{
 		},
 		options: AggregatedList,
 	},
+	{
+		Object:      "NetworkEndpointGroup",
+		Service:     "GlobalNetworkEndpointGroups",
+		Resource:    "networkEndpointGroups",
+		version:     VersionAlpha,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&alpha.GlobalNetworkEndpointGroupsService{}),
+		additionalMethods: []string{
+			"AttachNetworkEndpoints",
+			"DetachNetworkEndpoints",
+			"ListNetworkEndpoints",
+		},
+	},
+	{
+		Object:      "NetworkEndpointGroup",
+		Service:     "GlobalNetworkEndpointGroups",
+		Resource:    "networkEndpointGroups",
+		version:     VersionBeta,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&beta.GlobalNetworkEndpointGroupsService{}),
+		additionalMethods: []string{
+			"AttachNetworkEndpoints",
+			"DetachNetworkEndpoints",
+			"ListNetworkEndpoints",
+		},
+	},
+	{
+		Object:      "NetworkEndpointGroup",
+		Service:     "GlobalNetworkEndpointGroups",
+		Resource:    "networkEndpointGroups",
+		version:     VersionGA,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&ga.GlobalNetworkEndpointGroupsService{}),
+		additionalMethods: []string{
+			"AttachNetworkEndpoints",
+			"DetachNetworkEndpoints",
+			"ListNetworkEndpoints",
+		},
+	},
+	{
+		Object:      "NetworkEndpointGroup",
+		Service:     "RegionNetworkEndpointGroups",
+		Resource:    "networkEndpointGroups",
+		version:     VersionAlpha,
+		keyType:     Regional,
+		serviceType: reflect.TypeOf(&alpha.RegionNetworkEndpointGroupsService{}),
+		additionalMethods: []string{
+			"AttachNetworkEndpoints",
+			"DetachNetworkEndpoints",
+			"ListNetworkEndpoints",
+		},
+	},
+	{
+		Object:      "NetworkEndpointGroup",
+		Service:     "RegionNetworkEndpointGroups",
+		Resource:    "networkEndpointGroups",
+		version:     VersionBeta,
+		keyType:     Regional,
+		serviceType: reflect.TypeOf(&beta.RegionNetworkEndpointGroupsService{}),
+	},
+	{
+		Object:      "NetworkEndpointGroup",
+		Service:     "RegionNetworkEndpointGroups",
+		Resource:    "networkEndpointGroups",
+		version:     VersionGA,
+		keyType:     Regional,
+		serviceType: reflect.TypeOf(&ga.RegionNetworkEndpointGroupsService{}),
+	},
 	{
 		Object:   "Project",
 		Service:  "Projects",
@@ -650,6 +983,112 @@ var AllServices = []*ServiceInfo{
 		options:     NoGet | NoList | NoInsert | NoDelete | CustomOps,
 		serviceType: reflect.TypeOf(&ga.ProjectsService{}),
 	},
+	{
+		Object:      "PublicAdvertisedPrefix",
+		Service:     "PublicAdvertisedPrefixes",
+		Resource:    "publicAdvertisedPrefixes",
+		version:     VersionAlpha,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&alpha.PublicAdvertisedPrefixesService{}),
+		additionalMethods: []string{
+			"Announce",
+			"Patch",
+			"Withdraw",
+		},
+	},
+	{
+		Object:      "PublicAdvertisedPrefix",
+		Service:     "PublicAdvertisedPrefixes",
+		Resource:    "publicAdvertisedPrefixes",
+		version:     VersionBeta,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&beta.PublicAdvertisedPrefixesService{}),
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "PublicAdvertisedPrefix",
+		Service:     "PublicAdvertisedPrefixes",
+		Resource:    "publicAdvertisedPrefixes",
+		version:     VersionGA,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&ga.PublicAdvertisedPrefixesService{}),
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "PublicDelegatedPrefix",
+		Service:     "PublicDelegatedPrefixes",
+		Resource:    "publicDelegatedPrefixes",
+		version:     VersionAlpha,
+		keyType:     Regional,
+		serviceType: reflect.TypeOf(&alpha.PublicDelegatedPrefixesService{}),
+		options:     AggregatedList,
+		additionalMethods: []string{
+			"Announce",
+			"Patch",
+			"Withdraw",
+		},
+	},
+	{
+		Object:      "PublicDelegatedPrefix",
+		Service:     "PublicDelegatedPrefixes",
+		Resource:    "publicDelegatedPrefixes",
+		version:     VersionBeta,
+		keyType:     Regional,
+		serviceType: reflect.TypeOf(&beta.PublicDelegatedPrefixesService{}),
+		options:     AggregatedList,
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "PublicDelegatedPrefix",
+		Service:     "PublicDelegatedPrefixes",
+		Resource:    "publicDelegatedPrefixes",
+		version:     VersionGA,
+		keyType:     Regional,
+		serviceType: reflect.TypeOf(&ga.PublicDelegatedPrefixesService{}),
+		options:     AggregatedList,
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "PublicDelegatedPrefix",
+		Service:     "GlobalPublicDelegatedPrefixes",
+		Resource:    "publicDelegatedPrefixes",
+		version:     VersionAlpha,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&alpha.GlobalPublicDelegatedPrefixesService{}),
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "PublicDelegatedPrefix",
+		Service:     "GlobalPublicDelegatedPrefixes",
+		Resource:    "publicDelegatedPrefixes",
+		version:     VersionBeta,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&beta.GlobalPublicDelegatedPrefixesService{}),
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "PublicDelegatedPrefix",
+		Service:     "GlobalPublicDelegatedPrefixes",
+		Resource:    "publicDelegatedPrefixes",
+		version:     VersionGA,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&ga.GlobalPublicDelegatedPrefixesService{}),
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
 	{
 		Object:      "Region",
 		Service:     "Regions",
@@ -658,6 +1097,62 @@ var AllServices = []*ServiceInfo{
 		options:     ReadOnly,
 		serviceType: reflect.TypeOf(&ga.RegionsService{}),
 	},
+	{
+		Object:      "Reservation",
+		Service:     "Reservations",
+		Resource:    "reservations",
+		keyType:     Zonal,
+		serviceType: reflect.TypeOf(&ga.ReservationsService{}),
+		additionalMethods: []string{
+			"Update",
+		},
+		verbs: []Verb{
+			{
+				Name:     "Resize",
+				Request:  reflect.TypeOf(&ga.ReservationsResizeRequest{}),
+				Response: reflect.TypeOf(&ga.Operation{}),
+			},
+		},
+		options: AggregatedList,
+	},
+	{
+		Object:      "Reservation",
+		Service:     "Reservations",
+		Resource:    "reservations",
+		version:     VersionAlpha,
+		keyType:     Zonal,
+		serviceType: reflect.TypeOf(&alpha.ReservationsService{}),
+		additionalMethods: []string{
+			"Update",
+		},
+		verbs: []Verb{
+			{
+				Name:     "Resize",
+				Request:  reflect.TypeOf(&alpha.ReservationsResizeRequest{}),
+				Response: reflect.TypeOf(&alpha.Operation{}),
+			},
+		},
+		options: AggregatedList,
+	},
+	{
+		Object:      "Reservation",
+		Service:     "Reservations",
+		Resource:    "reservations",
+		version:     VersionBeta,
+		keyType:     Zonal,
+		serviceType: reflect.TypeOf(&beta.ReservationsService{}),
+		additionalMethods: []string{
+			"Update",
+		},
+		verbs: []Verb{
+			{
+				Name:     "Resize",
+				Request:  reflect.TypeOf(&beta.ReservationsResizeRequest{}),
+				Response: reflect.TypeOf(&beta.Operation{}),
+			},
+		},
+		options: AggregatedList,
+	},
 	{
 		Object:      "Router",
 		Service:     "Routers",
@@ -668,6 +1163,7 @@ var AllServices = []*ServiceInfo{
 		serviceType: reflect.TypeOf(&alpha.RoutersService{}),
 		additionalMethods: []string{
 			"Patch",
+			"Update",
 			"Preview",
 			"GetRouterStatus",
 			"TestIamPermissions",
@@ -683,6 +1179,7 @@ var AllServices = []*ServiceInfo{
 		serviceType: reflect.TypeOf(&beta.RoutersService{}),
 		additionalMethods: []string{
 			"Patch",
+			"Update",
 			"Preview",
 			"GetRouterStatus",
 			"TestIamPermissions",
@@ -698,6 +1195,7 @@ var AllServices = []*ServiceInfo{
 		serviceType: reflect.TypeOf(&ga.RoutersService{}),
 		additionalMethods: []string{
 			"Patch",
+			"Update",
 			"Preview",
 			"GetRouterStatus",
 		},
@@ -709,6 +1207,22 @@ var AllServices = []*ServiceInfo{
 		keyType:     Global,
 		serviceType: reflect.TypeOf(&ga.RoutesService{}),
 	},
+	{
+		Object:      "SecurityPolicy",
+		Service:     "SecurityPolicies",
+		Resource:    "securityPolicies",
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&ga.SecurityPoliciesService{}),
+		additionalMethods: []string{
+			"AddRule",
+			"GetRule",
+			"Patch",
+			"PatchRule",
+			"RemoveRule",
+			"SetLabels",
+		},
+		options: AggregatedList,
+	},
 	{
 		Object:      "SecurityPolicy",
 		Service:     "SecurityPolicies",
@@ -722,7 +1236,9 @@ var AllServices = []*ServiceInfo{
 			"Patch",
 			"PatchRule",
 			"RemoveRule",
+			"SetLabels",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "ServiceAttachment",
@@ -734,6 +1250,7 @@ var AllServices = []*ServiceInfo{
 		additionalMethods: []string{
 			"Patch",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "ServiceAttachment",
@@ -745,6 +1262,7 @@ var AllServices = []*ServiceInfo{
 		additionalMethods: []string{
 			"Patch",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "ServiceAttachment",
@@ -756,6 +1274,7 @@ var AllServices = []*ServiceInfo{
 		additionalMethods: []string{
 			"Patch",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "SslCertificate",
@@ -763,6 +1282,7 @@ var AllServices = []*ServiceInfo{
 		Resource:    "sslCertificates",
 		keyType:     Global,
 		serviceType: reflect.TypeOf(&ga.SslCertificatesService{}),
+		options:     AggregatedList,
 	},
 	{
 		Object:      "SslCertificate",
@@ -771,6 +1291,7 @@ var AllServices = []*ServiceInfo{
 		version:     VersionBeta,
 		keyType:     Global,
 		serviceType: reflect.TypeOf(&beta.SslCertificatesService{}),
+		options:     AggregatedList,
 	},
 	{
 		Object:      "SslCertificate",
@@ -779,6 +1300,7 @@ var AllServices = []*ServiceInfo{
 		version:     VersionAlpha,
 		keyType:     Global,
 		serviceType: reflect.TypeOf(&alpha.SslCertificatesService{}),
+		options:     AggregatedList,
 	},
 	{
 		Object:      "SslCertificate",
@@ -810,7 +1332,43 @@ var AllServices = []*ServiceInfo{
 		Resource:    "sslPolicies",
 		keyType:     Global,
 		serviceType: reflect.TypeOf(&ga.SslPoliciesService{}),
-		options:     NoList, // List() naming convention is different in GCE API for this resource
+		options:     AggregatedList | NoList, // List() naming convention is different in GCE API for this resource
+	},
+	{
+		Object:      "SslPolicy",
+		Service:     "RegionSslPolicies",
+		Resource:    "sslPolicies",
+		version:     VersionAlpha,
+		keyType:     Regional,
+		serviceType: reflect.TypeOf(&alpha.RegionSslPoliciesService{}),
+		listType:    "SslPoliciesList",
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "SslPolicy",
+		Service:     "RegionSslPolicies",
+		Resource:    "sslPolicies",
+		version:     VersionBeta,
+		keyType:     Regional,
+		serviceType: reflect.TypeOf(&beta.RegionSslPoliciesService{}),
+		listType:    "SslPoliciesList",
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "SslPolicy",
+		Service:     "RegionSslPolicies",
+		Resource:    "sslPolicies",
+		version:     VersionGA,
+		keyType:     Regional,
+		serviceType: reflect.TypeOf(&ga.RegionSslPoliciesService{}),
+		listType:    "SslPoliciesList",
+		additionalMethods: []string{
+			"Patch",
+		},
 	},
 	{
 		Object:      "Subnetwork",
@@ -819,7 +1377,7 @@ var AllServices = []*ServiceInfo{
 		version:     VersionAlpha,
 		keyType:     Regional,
 		serviceType: reflect.TypeOf(&alpha.SubnetworksService{}),
-		options:     ListUsable,
+		options:     AggregatedList | ListUsable,
 		additionalMethods: []string{
 			"Patch",
 		},
@@ -831,7 +1389,7 @@ var AllServices = []*ServiceInfo{
 		version:     VersionBeta,
 		keyType:     Regional,
 		serviceType: reflect.TypeOf(&beta.SubnetworksService{}),
-		options:     ListUsable,
+		options:     AggregatedList | ListUsable,
 		additionalMethods: []string{
 			"Patch",
 		},
@@ -843,7 +1401,7 @@ var AllServices = []*ServiceInfo{
 		version:     VersionGA,
 		keyType:     Regional,
 		serviceType: reflect.TypeOf(&ga.SubnetworksService{}),
-		options:     ListUsable,
+		options:     AggregatedList | ListUsable,
 		additionalMethods: []string{
 			"Patch",
 		},
@@ -858,6 +1416,7 @@ var AllServices = []*ServiceInfo{
 		additionalMethods: []string{
 			"SetUrlMap",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "TargetHttpProxy",
@@ -869,6 +1428,7 @@ var AllServices = []*ServiceInfo{
 		additionalMethods: []string{
 			"SetUrlMap",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "TargetHttpProxy",
@@ -879,6 +1439,7 @@ var AllServices = []*ServiceInfo{
 		additionalMethods: []string{
 			"SetUrlMap",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "TargetHttpProxy",
@@ -925,6 +1486,7 @@ var AllServices = []*ServiceInfo{
 			"SetSslPolicy",
 			"SetUrlMap",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "TargetHttpsProxy",
@@ -939,6 +1501,7 @@ var AllServices = []*ServiceInfo{
 			"SetSslPolicy",
 			"SetUrlMap",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "TargetHttpsProxy",
@@ -953,6 +1516,7 @@ var AllServices = []*ServiceInfo{
 			"SetSslPolicy",
 			"SetUrlMap",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "TargetHttpsProxy",
@@ -962,6 +1526,7 @@ var AllServices = []*ServiceInfo{
 		keyType:     Regional,
 		serviceType: reflect.TypeOf(&alpha.RegionTargetHttpsProxiesService{}),
 		additionalMethods: []string{
+			"Patch",
 			"SetSslCertificates",
 			"SetUrlMap",
 		},
@@ -974,6 +1539,7 @@ var AllServices = []*ServiceInfo{
 		keyType:     Regional,
 		serviceType: reflect.TypeOf(&beta.RegionTargetHttpsProxiesService{}),
 		additionalMethods: []string{
+			"Patch",
 			"SetSslCertificates",
 			"SetUrlMap",
 		},
@@ -986,6 +1552,7 @@ var AllServices = []*ServiceInfo{
 		keyType:     Regional,
 		serviceType: reflect.TypeOf(&ga.RegionTargetHttpsProxiesService{}),
 		additionalMethods: []string{
+			"Patch",
 			"SetSslCertificates",
 			"SetUrlMap",
 		},
@@ -1000,6 +1567,14 @@ var AllServices = []*ServiceInfo{
 			"AddInstance",
 			"RemoveInstance",
 		},
+		verbs: []Verb{
+			{
+				Name:     "SetBackup",
+				Request:  reflect.TypeOf(&ga.TargetReference{}),
+				Response: reflect.TypeOf(&ga.Operation{}),
+			},
+		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "TargetTcpProxy",
@@ -1011,6 +1586,7 @@ var AllServices = []*ServiceInfo{
 		additionalMethods: []string{
 			"SetBackendService",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "TargetTcpProxy",
@@ -1022,6 +1598,7 @@ var AllServices = []*ServiceInfo{
 		additionalMethods: []string{
 			"SetBackendService",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "TargetTcpProxy",
@@ -1033,6 +1610,7 @@ var AllServices = []*ServiceInfo{
 		additionalMethods: []string{
 			"SetBackendService",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "UrlMap",
@@ -1044,6 +1622,7 @@ var AllServices = []*ServiceInfo{
 		additionalMethods: []string{
 			"Update",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "UrlMap",
@@ -1055,6 +1634,7 @@ var AllServices = []*ServiceInfo{
 		additionalMethods: []string{
 			"Update",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "UrlMap",
@@ -1065,6 +1645,7 @@ var AllServices = []*ServiceInfo{
 		additionalMethods: []string{
 			"Update",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "UrlMap",
@@ -82,6 +82,41 @@ func (a *arg) String() string {
 	return ret
 }
 
+// Verb declares a custom (non-CRUD) method of a service, e.g. "SetBackup",
+// together with the types it takes and returns. The generator creates the
+// wrapper, rate limiter key, mock hook and mock error for the method in the
+// same way as for additionalMethods. The types are checked against the API so
+// that changes in the API are caught when generating the code.
+type Verb struct {
+	// Name of the method in the API service (e.g. "AttachDisk").
+	Name string
+	// Request is the type of the request body (e.g.
+	// reflect.TypeOf(&ga.AttachedDisk{})). nil if the method does not take
+	// a request body.
+	Request reflect.Type
+	// Response is the type returned by the method (e.g.
+	// reflect.TypeOf(&ga.Operation{}) for long running methods).
+	Response reflect.Type
+}
+
+// check panics if the verb does not match the signature of method m.
+func (v *Verb) check(m *Method) {
+	fType := m.m.Func.Type()
+	var req reflect.Type
+	for i := m.argsSkip(); i < fType.NumIn(); i++ {
+		if fType.In(i).Kind() == reflect.Ptr && fType.In(i).Elem().Kind() == reflect.Struct {
+			req = fType.In(i)
+		}
+	}
+	if req != v.Request {
+		panic(fmt.Errorf("method %q.%q: request type is %v, declared %v", m.Service, m.Name(), req, v.Request))
+	}
+	do, _ := fType.Out(0).MethodByName("Do")
+	if resp := do.Type.Out(0); resp != v.Response {
+		panic(fmt.Errorf("method %q.%q: response type is %v, declared %v", m.Service, m.Name(), resp, v.Response))
+	}
+}
+
 // newMethod returns a newly initialized method.
 func newMethod(s *ServiceInfo, m reflect.Method) *Method {
 	ret := &Method{
@@ -106,6 +141,42 @@ const (
 	MethodPaged MethodKind = iota
 )
 
+// Idempotency tells if a call can be repeated safely, e.g. after an error
+// that may have happened after the server applied the call.
+type Idempotency int
+
+const (
+	// NotIdempotent calls may be applied twice if they are repeated, e.g.
+	// AddInstances without a requestId.
+	NotIdempotent Idempotency = iota
+	// IdempotentWithRequestID calls are idempotent if they are repeated
+	// with the same requestId, e.g. Insert.
+	IdempotentWithRequestID
+	// Idempotent calls can always be repeated, e.g. Get and List. Delete
+	// is idempotent: repeating a Delete that succeeded fails with 404
+	// without side effects. Update, Patch and the Set* methods set the
+	// resource to the same state.
+	Idempotent
+)
+
+// VerbIdempotency returns the Idempotency of the method verb (e.g.
+// "Insert"). requestID is true if the method takes a requestId.
+func VerbIdempotency(verb string, requestID bool) Idempotency {
+	for _, prefix := range []string{"Get", "List", "Test", "Set"} {
+		if strings.HasPrefix(verb, prefix) {
+			return Idempotent
+		}
+	}
+	switch verb {
+	case "AggregatedList", "Preview", "Delete", "Update", "Patch":
+		return Idempotent
+	}
+	if requestID {
+		return IdempotentWithRequestID
+	}
+	return NotIdempotent
+}
+
 // Method is used to generate the calling code for non-standard methods.
 type Method struct {
 	*ServiceInfo
@@ -117,6 +188,9 @@ type Method struct {
 	// ItemType is the type of the individual elements returns from a
 	// Pages() call. This is only applicable for MethodPaged kind.
 	ItemType string
+	// ItemsField is the field of the page that contains the elements
+	// (usually "Items"). This is only applicable for MethodPaged kind.
+	ItemsField string
 }
 
 // IsOperation is true if the method is an Operation.
@@ -134,6 +208,82 @@ func (m *Method) IsGet() bool {
 	return m.kind == MethodGet
 }
 
+// IsPatch is true if the method is a PATCH of the resource. Patch methods take
+// an additional field mask argument that selects the fields that are sent to
+// the server.
+func (m *Method) IsPatch() bool {
+	fType := m.m.Func.Type()
+	return m.kind == MethodOperation && m.m.Name == "Patch" && fType.NumIn()-m.argsSkip() == 1 &&
+		fType.In(m.argsSkip()).Kind() == reflect.Ptr && fType.In(m.argsSkip()).Elem().Kind() == reflect.Struct
+}
+
+// IsUpdate is true if the method is an Update that replaces the resource with
+// the given object.
+func (m *Method) IsUpdate() bool {
+	fType := m.m.Func.Type()
+	return m.kind == MethodOperation && m.m.Name == "Update" && fType.NumIn()-m.argsSkip() == 1 &&
+		fType.In(m.argsSkip()).Kind() == reflect.Ptr && fType.In(m.argsSkip()).Elem().Name() == m.Object
+}
+
+// IsSetLabels is true if the method is a SetLabels that takes a request with
+// Labels and LabelFingerprint.
+func (m *Method) IsSetLabels() bool {
+	fType := m.m.Func.Type()
+	if m.kind != MethodOperation || m.m.Name != "SetLabels" || fType.NumIn()-m.argsSkip() != 1 {
+		return false
+	}
+	req := fType.In(m.argsSkip())
+	if req.Kind() != reflect.Ptr || req.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	_, hasLabels := req.Elem().FieldByName("Labels")
+	_, hasFingerprint := req.Elem().FieldByName("LabelFingerprint")
+	if !hasLabels || !hasFingerprint {
+		return false
+	}
+	// The object itself must also have the fields for the mock to maintain
+	// them (e.g. GA Address does not).
+	obj := m.objectType()
+	if obj == nil {
+		return false
+	}
+	_, hasLabels = obj.FieldByName("Labels")
+	_, hasFingerprint = obj.FieldByName("LabelFingerprint")
+	return hasLabels && hasFingerprint
+}
+
+// objectType returns the type of the object for the service by looking at the
+// return value of Get(). Returns nil if this cannot be determined.
+func (m *Method) objectType() reflect.Type {
+	return m.ObjectGoType()
+}
+
+// ArgTypes are the types of the arguments of the method that follow the key
+// (arg0, arg1, ...).
+func (m *Method) ArgTypes() []reflect.Type {
+	var ret []reflect.Type
+	fType := m.m.Func.Type()
+	for i := m.argsSkip(); i < fType.NumIn(); i++ {
+		ret = append(ret, fType.In(i))
+	}
+	return ret
+}
+
+// ResultGoType is the type of the object returned by a MethodGet or of the
+// items returned by a MethodPaged. It is nil for a MethodOperation.
+func (m *Method) ResultGoType() reflect.Type {
+	do, _ := m.m.Type.Out(0).MethodByName("Do")
+	t := do.Type.Out(0).Elem()
+	switch m.kind {
+	case MethodGet:
+		return t
+	case MethodPaged:
+		f, _ := t.FieldByName(m.ItemsField)
+		return f.Type.Elem().Elem()
+	}
+	return nil
+}
+
 // argsSkip is the number of arguments to skip when generating the
 // synthesized method.
 func (m *Method) argsSkip() int {
@@ -215,12 +365,18 @@ func (m *Method) init() {
 		case hasPages:
 			m.kind = MethodPaged
 			// Pages() returns a xxxList that has the actual list
-			// of objects in the xxxList.Items field.
+			// of objects in the xxxList.Items field. Some methods use a
+			// different name for the field (e.g. ManagedInstances), in
+			// which case the only list of objects in the page is used.
 			listType := out0.Elem()
 			itemsField, ok := listType.FieldByName("Items")
+			if !ok {
+				itemsField, ok = pageItemsField(listType)
+			}
 			if !ok {
 				panic(fmt.Errorf("method %q.%q: paged return type %q does not have a .Items field", m.Service, m.Name(), listType.Name()))
 			}
+			m.ItemsField = itemsField.Name
 			// itemsField will be a []*ItemType. Dereference to
 			// extract the ItemType.
 			itemsType := itemsField.Type
@@ -243,6 +399,22 @@ func (m *Method) init() {
 	}
 }
 
+// pageItemsField returns the field of the page type t that is a list of
+// objects, if there is exactly one such field.
+func pageItemsField(t reflect.Type) (reflect.StructField, bool) {
+	var ret []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() == reflect.Ptr && f.Type.Elem().Elem().Kind() == reflect.Struct {
+			ret = append(ret, f)
+		}
+	}
+	if len(ret) != 1 {
+		return reflect.StructField{}, false
+	}
+	return ret[0], true
+}
+
 // Name is the name of the method.
 func (m *Method) Name() string {
 	return m.m.Name
@@ -262,6 +434,11 @@ func (m *Method) CallArgs() string {
 	return fmt.Sprintf(", %s", strings.Join(args, ", "))
 }
 
+// MockErrorName is the name of the error map in the mock.
+func (m *Method) MockErrorName() string {
+	return m.m.Name + "Error"
+}
+
 // MockHookName is the name of the hook function in the mock.
 func (m *Method) MockHookName() string {
 	return m.m.Name + "Hook"
@@ -300,6 +477,9 @@ func (m *Method) FcnArgs() string {
 	if m.kind == MethodPaged {
 		args = append(args, "fl *filter.F")
 	}
+	if m.IsPatch() {
+		args = append(args, "fieldMask ...string")
+	}
 
 	switch m.kind {
 	case MethodOperation:
@@ -323,6 +503,9 @@ func (m *Method) InterfaceFunc() string {
 	if m.kind == MethodPaged {
 		args = append(args, "*filter.F")
 	}
+	if m.IsPatch() {
+		args = append(args, "...string")
+	}
 
 	switch m.kind {
 	case MethodOperation:
@@ -39,9 +39,16 @@ type ServiceInfo struct {
 	keyType     KeyType
 	serviceType reflect.Type
 
-	additionalMethods   []string
+	additionalMethods []string
+	// verbs are custom methods declared with their request and response
+	// types. Unlike additionalMethods, the generator checks the declared
+	// types against the API.
+	verbs               []Verb
 	options             int
 	aggregatedListField string
+	// listType overrides the name of the List type if it does not follow
+	// the "<Object>List" naming convention (e.g. "SslPoliciesList").
+	listType string
 }
 
 // Version returns the version of the Service, defaulting to GA if APIVersion
@@ -79,6 +86,15 @@ func (i *ServiceInfo) WrapType() string {
 	return "Invalid"
 }
 
+// VersionPrefix is the prefix used for names of the version of the object
+// ("" for GA, "Alpha" or "Beta").
+func (i *ServiceInfo) VersionPrefix() string {
+	if i.Version() == VersionGA {
+		return ""
+	}
+	return i.VersionTitle()
+}
+
 // WrapTypeOps is the name of the additional operations type.
 func (i *ServiceInfo) WrapTypeOps() string {
 	return i.WrapType() + "Ops"
@@ -91,11 +107,21 @@ func (i *ServiceInfo) FQObjectType() string {
 
 // ObjectListType is the compute List type for the object (contains Items field).
 func (i *ServiceInfo) ObjectListType() string {
+	if i.listType != "" {
+		return fmt.Sprintf("%v.%v", i.Version(), i.listType)
+	}
 	return fmt.Sprintf("%v.%vList", i.Version(), i.Object)
 }
 
 // ObjectAggregatedListType is the compute List type for the object (contains Items field).
+// The name is taken from the AggregatedList call of the service as it is not
+// consistent across the API (e.g. HealthChecksAggregatedList).
 func (i *ServiceInfo) ObjectAggregatedListType() string {
+	if m, ok := i.serviceType.MethodByName("AggregatedList"); ok {
+		if do, ok := m.Type.Out(0).MethodByName("Do"); ok {
+			return fmt.Sprintf("%v.%v", i.Version(), do.Type.Out(0).Elem().Name())
+		}
+	}
 	return fmt.Sprintf("%v.%vAggregatedList", i.Version(), i.Object)
 }
 
@@ -109,6 +135,40 @@ func (i *ServiceInfo) FQListUsableObjectType() string {
 	return fmt.Sprintf("%v.Usable%v", i.Version(), i.Object)
 }
 
+// ObjectGoType is the type of the object returned by the Get call of the
+// service. It is nil if the service does not have a Get call.
+func (i *ServiceInfo) ObjectGoType() reflect.Type {
+	return doResultType(i.serviceType, "Get")
+}
+
+// ListUsableGoType is the type of the items returned by the ListUsable call
+// of the service. It is nil if the service does not have a ListUsable call.
+func (i *ServiceInfo) ListUsableGoType() reflect.Type {
+	l := doResultType(i.serviceType, "ListUsable")
+	if l == nil {
+		return nil
+	}
+	f, ok := l.FieldByName("Items")
+	if !ok || f.Type.Kind() != reflect.Slice || f.Type.Elem().Kind() != reflect.Ptr {
+		return nil
+	}
+	return f.Type.Elem().Elem()
+}
+
+// doResultType returns the type pointed to by the result of Do() of the
+// call returned by method of the service type t.
+func doResultType(t reflect.Type, method string) reflect.Type {
+	m, ok := t.MethodByName(method)
+	if !ok || m.Type.NumOut() != 1 {
+		return nil
+	}
+	do, ok := m.Type.Out(0).MethodByName("Do")
+	if !ok || do.Type.NumOut() != 2 || do.Type.Out(0).Kind() != reflect.Ptr {
+		return nil
+	}
+	return do.Type.Out(0).Elem()
+}
+
 // MockWrapType is the name of the concrete mock for this type.
 func (i *ServiceInfo) MockWrapType() string {
 	return "Mock" + i.WrapType()
@@ -135,6 +195,15 @@ func (i *ServiceInfo) Methods() []*Method {
 	for _, m := range i.additionalMethods {
 		methods[m] = true
 	}
+	verbs := map[string]*Verb{}
+	for j := range i.verbs {
+		v := &i.verbs[j]
+		if methods[v.Name] || verbs[v.Name] != nil {
+			panic(fmt.Errorf("method %q is declared more than once in service %q", v.Name, i.Service))
+		}
+		methods[v.Name] = true
+		verbs[v.Name] = v
+	}
 
 	var ret []*Method
 	for j := 0; j < i.serviceType.NumMethod(); j++ {
@@ -142,7 +211,11 @@ func (i *ServiceInfo) Methods() []*Method {
 		if _, ok := methods[m.Name]; !ok {
 			continue
 		}
-		ret = append(ret, newMethod(i, m))
+		method := newMethod(i, m)
+		if v := verbs[m.Name]; v != nil {
+			v.check(method)
+		}
+		ret = append(ret, method)
 		methods[m.Name] = false
 	}
 
@@ -155,6 +228,26 @@ func (i *ServiceInfo) Methods() []*Method {
 	return ret
 }
 
+// UpdateMethod is the method that writes back a whole object read by Get,
+// for read-modify-write helpers: "Update" if the service has an Update of
+// the object, "Patch" otherwise. It is empty if the service has neither or
+// no Get.
+func (i *ServiceInfo) UpdateMethod() string {
+	if !i.GenerateGet() || i.KeyIsProject() {
+		return ""
+	}
+	var ret string
+	for _, m := range i.Methods() {
+		switch {
+		case m.IsUpdate():
+			return "Update"
+		case m.IsPatch():
+			ret = "Patch"
+		}
+	}
+	return ret
+}
+
 // KeyIsGlobal is true if the key is global.
 func (i *ServiceInfo) KeyIsGlobal() bool {
 	return i.keyType == Global
@@ -210,6 +303,22 @@ func (i *ServiceInfo) GenerateInsert() bool {
 	return i.options&NoInsert == 0
 }
 
+// SupportsRequestID is true if the call of the given method (e.g. "Insert")
+// has a requestId parameter.
+func (i *ServiceInfo) SupportsRequestID(method string) bool {
+	m, ok := i.serviceType.MethodByName(method)
+	if !ok {
+		return false
+	}
+	_, ok = m.Type.Out(0).MethodByName("RequestId")
+	return ok
+}
+
+// Idempotency of the given method (e.g. "Insert") of the service.
+func (i *ServiceInfo) Idempotency(method string) Idempotency {
+	return VerbIdempotency(method, i.SupportsRequestID(method))
+}
+
 // GenerateCustomOps is true if we should generated a xxxOps interface for
 // adding additional methods to the generated interface.
 func (i *ServiceInfo) GenerateCustomOps() bool {
@@ -304,8 +413,13 @@ var AllServicesByGroup map[string]*ServiceGroup
 var SortedServicesGroups []*ServiceGroup
 
 func init() {
+	initServiceGroups()
+}
+
+func initServiceGroups() {
 	AllServicesByGroup = groupServices(AllServices)
 
+	SortedServicesGroups = nil
 	for _, sg := range AllServicesByGroup {
 		SortedServicesGroups = append(SortedServicesGroups, sg)
 	}
@@ -313,3 +427,17 @@ func init() {
 		return SortedServicesGroups[i].Service() < SortedServicesGroups[j].Service()
 	})
 }
+
+// SelectServices restricts AllServices, AllServicesByGroup and
+// SortedServicesGroups to the services for which keep returns true. This is
+// used by the code generator to generate a subset of the services.
+func SelectServices(keep func(*ServiceInfo) bool) {
+	var all []*ServiceInfo
+	for _, s := range AllServices {
+		if keep(s) {
+			all = append(all, s)
+		}
+	}
+	AllServices = all
+	initServiceGroups()
+}
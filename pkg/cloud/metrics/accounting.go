@@ -0,0 +1,137 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+)
+
+// UnknownComponent is the component of the calls made with a context
+// without a cloud.Caller.
+const UnknownComponent = "unknown"
+
+// AccountingRow is the cumulative count of the calls made by a component
+// in a project.
+type AccountingRow struct {
+	// Project of the calls.
+	Project string `json:"project"`
+	// Component is the cloud.Caller Name from the context of the calls.
+	Component string `json:"component"`
+	// Calls is the number of calls.
+	Calls int64 `json:"calls"`
+	// Errors is the number of calls that returned an error.
+	Errors int64 `json:"errors"`
+	// QuotaErrors is the number of calls that returned a
+	// cloud.ErrorClassQuota error.
+	QuotaErrors int64 `json:"quotaErrors"`
+}
+
+type accountingKey struct {
+	project   string
+	component string
+}
+
+// Accounting is a cloud.CallObserver that counts the calls by project and
+// by the component from the cloud.Caller of the context, to attribute the
+// quota of a shared project to the controllers using it.
+//
+//	acct := metrics.NewAccounting()
+//	ctx = cloud.WithCallObserver(cloud.WithCaller(ctx, cloud.Caller{Name: "l4-controller"}), acct)
+//	...
+//	acct.WriteCSV(os.Stdout)
+type Accounting struct {
+	lock sync.Mutex
+	rows map[accountingKey]*AccountingRow
+}
+
+// NewAccounting returns an Accounting with no calls.
+func NewAccounting() *Accounting {
+	return &Accounting{rows: map[accountingKey]*AccountingRow{}}
+}
+
+// Accounting implements cloud.CallObserver.
+var _ cloud.CallObserver = (*Accounting)(nil)
+
+// Start implements cloud.CallObserver.
+func (a *Accounting) Start(ctx context.Context, key *cloud.CallContextKey) {}
+
+// End implements cloud.CallObserver.
+func (a *Accounting) End(ctx context.Context, key *cloud.CallContextKey, err error) {
+	component := UnknownComponent
+	if c, ok := cloud.CallerFromContext(ctx); ok && c.Name != "" {
+		component = c.Name
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	k := accountingKey{project: key.ProjectID, component: component}
+	row, ok := a.rows[k]
+	if !ok {
+		row = &AccountingRow{Project: key.ProjectID, Component: component}
+		a.rows[k] = row
+	}
+	row.Calls++
+	if err != nil {
+		row.Errors++
+		if cloud.ClassifyError(err) == cloud.ErrorClassQuota {
+			row.QuotaErrors++
+		}
+	}
+}
+
+// Report returns the counts sorted by project and component.
+func (a *Accounting) Report() []AccountingRow {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	var ret []AccountingRow
+	for _, row := range a.rows {
+		ret = append(ret, *row)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Project != ret[j].Project {
+			return ret[i].Project < ret[j].Project
+		}
+		return ret[i].Component < ret[j].Component
+	})
+	return ret
+}
+
+// WriteCSV writes the Report as CSV with a header line to w.
+func (a *Accounting) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"project", "component", "calls", "errors", "quota_errors"})
+	for _, row := range a.Report() {
+		cw.Write([]string{
+			row.Project,
+			row.Component,
+			strconv.FormatInt(row.Calls, 10),
+			strconv.FormatInt(row.Errors, 10),
+			strconv.FormatInt(row.QuotaErrors, 10),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/googleapi"
+)
+
+func TestAccounting(t *testing.T) {
+	t.Parallel()
+
+	acct := NewAccounting()
+	l4 := cloud.WithCaller(context.Background(), cloud.Caller{Name: "l4-controller"})
+	l7 := cloud.WithCaller(context.Background(), cloud.Caller{Name: "l7-controller"})
+	quota := &googleapi.Error{Code: http.StatusTooManyRequests}
+
+	for _, c := range []struct {
+		ctx     context.Context
+		project string
+		err     error
+	}{
+		{l4, "p1", nil},
+		{l4, "p1", quota},
+		{l7, "p1", errors.New("injected")},
+		{l7, "p2", nil},
+		{context.Background(), "p1", nil},
+	} {
+		ck := &cloud.CallContextKey{ProjectID: c.project, Service: "Addresses", Operation: "Get"}
+		acct.Start(c.ctx, ck)
+		acct.End(c.ctx, ck, c.err)
+	}
+
+	want := []AccountingRow{
+		{Project: "p1", Component: "l4-controller", Calls: 2, Errors: 1, QuotaErrors: 1},
+		{Project: "p1", Component: "l7-controller", Calls: 1, Errors: 1},
+		{Project: "p1", Component: UnknownComponent, Calls: 1},
+		{Project: "p2", Component: "l7-controller", Calls: 1},
+	}
+	if diff := cmp.Diff(want, acct.Report()); diff != "" {
+		t.Errorf("Report(): -want +got: %s", diff)
+	}
+
+	var b strings.Builder
+	if err := acct.WriteCSV(&b); err != nil {
+		t.Fatalf("WriteCSV() = %v, want nil", err)
+	}
+	wantCSV := `project,component,calls,errors,quota_errors
+p1,l4-controller,2,1,1
+p1,l7-controller,1,1,0
+p1,unknown,1,0,0
+p2,l7-controller,1,0,0
+`
+	if got := b.String(); got != wantCSV {
+		t.Errorf("WriteCSV() = %q, want %q", got, wantCSV)
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics records the count, latency and errors of calls to the API
+// through the cloud.CallObserver.
+//
+//	obs := metrics.NewObserver(recorder)
+//	ctx = cloud.WithCallObserver(ctx, obs)
+//	c.Addresses().Get(ctx, key)
+//
+// This package does not depend on a metrics library. The Recorder is
+// implemented on top of the library used by the caller, e.g. the prometheus
+// sub-package registers the metrics with the Prometheus client library.
+//
+// The call count is the count of the latency histogram.
+//
+// Calls exceeding the cloud.Watchdog threshold are counted with
+// OnSlowCall and failed calls by cloud.ErrorClass with OnError.
+//
+// LiveStats keeps rolling statistics of the calls in memory for inspecting
+// a running process without a metrics backend. Accounting counts the calls
+// by project and calling component.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"google.golang.org/api/googleapi"
+)
+
+// Status classes of a call.
+const (
+	// StatusClassOK is a call that did not return an error.
+	StatusClassOK = "2xx"
+	// StatusClassCanceled is a call that ended because the context was
+	// canceled or its deadline exceeded.
+	StatusClassCanceled = "canceled"
+	// StatusClassOther is an error that is not from the API (e.g. a
+	// network error).
+	StatusClassOther = "other"
+	// StatusClassInProgress is a call that has not ended.
+	StatusClassInProgress = "in_progress"
+)
+
+// LabelNames are the names of the labels in the order returned by
+// Labels.Values().
+var LabelNames = []string{"resource", "verb", "version", "status_class"}
+
+// Labels of a call to the API.
+type Labels struct {
+	// Resource is the service of the call, e.g. "Addresses".
+	Resource string
+	// Verb is the operation of the call, e.g. "Get".
+	Verb string
+	// Version is the API version, e.g. "ga".
+	Version string
+	// StatusClass is the HTTP status class (e.g. "2xx", "4xx") or
+	// StatusClassCanceled or StatusClassOther.
+	StatusClass string
+}
+
+// Values of the labels in the order of LabelNames.
+func (l Labels) Values() []string {
+	return []string{l.Resource, l.Verb, l.Version, l.StatusClass}
+}
+
+// Recorder stores the metrics of calls.
+type Recorder interface {
+	// Record a call with the given labels that took latency.
+	Record(l Labels, latency time.Duration)
+}
+
+// Observer is a cloud.CallObserver that records each call to the
+// Recorder.
+type Observer struct {
+	recorder Recorder
+	// now is replaced in unit tests.
+	now func() time.Time
+
+	lock  sync.Mutex
+	start map[*cloud.CallContextKey]time.Time
+}
+
+// NewObserver returns a new Observer that records to r.
+func NewObserver(r Recorder) *Observer {
+	return &Observer{
+		recorder: r,
+		now:      time.Now,
+		start:    map[*cloud.CallContextKey]time.Time{},
+	}
+}
+
+// Observer implements cloud.CallObserver.
+var _ cloud.CallObserver = (*Observer)(nil)
+
+// Start implements cloud.CallObserver.
+func (o *Observer) Start(ctx context.Context, key *cloud.CallContextKey) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.start[key] = o.now()
+}
+
+// End implements cloud.CallObserver.
+func (o *Observer) End(ctx context.Context, key *cloud.CallContextKey, err error) {
+	o.lock.Lock()
+	start, ok := o.start[key]
+	delete(o.start, key)
+	o.lock.Unlock()
+
+	if !ok {
+		return
+	}
+	o.recorder.Record(Labels{
+		Resource:    key.Service,
+		Verb:        key.Operation,
+		Version:     string(key.Version),
+		StatusClass: StatusClass(err),
+	}, o.now().Sub(start))
+}
+
+// SlowCallRecorder stores the count of calls reported by the
+// cloud.Watchdog.
+type SlowCallRecorder interface {
+	// RecordSlowCall records a call with the given labels that is still
+	// in progress after elapsed. StatusClass is StatusClassInProgress.
+	RecordSlowCall(l Labels, elapsed time.Duration)
+}
+
+// OnSlowCall returns a function for cloud.Watchdog.OnSlowCall that records
+// the slow calls to r.
+func OnSlowCall(r SlowCallRecorder) func(context.Context, *cloud.SlowCall) {
+	return func(ctx context.Context, c *cloud.SlowCall) {
+		r.RecordSlowCall(Labels{
+			Resource:    c.CallContextKey.Service,
+			Verb:        c.CallContextKey.Operation,
+			Version:     string(c.CallContextKey.Version),
+			StatusClass: StatusClassInProgress,
+		}, c.Elapsed)
+	}
+}
+
+// ErrorRecorder stores the count of failed calls by cloud.ErrorClass.
+type ErrorRecorder interface {
+	// RecordError records a failed call with the given labels and class.
+	RecordError(l Labels, class cloud.ErrorClass)
+}
+
+// OnError returns a function for cloud.Service.OnError that records the
+// failed calls to r.
+func OnError(r ErrorRecorder) func(context.Context, *cloud.CallError) {
+	return func(ctx context.Context, e *cloud.CallError) {
+		r.RecordError(Labels{
+			Resource:    e.CallContextKey.Service,
+			Verb:        e.CallContextKey.Operation,
+			Version:     string(e.CallContextKey.Version),
+			StatusClass: StatusClass(e.Err),
+		}, e.Class)
+	}
+}
+
+// StatusClass returns the status class of the result of a call.
+func StatusClass(err error) string {
+	if err == nil {
+		return StatusClassOK
+	}
+	var gerr *googleapi.Error
+	switch {
+	case errors.As(err, &gerr):
+		return fmt.Sprintf("%dxx", gerr.Code/100)
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return StatusClassCanceled
+	}
+	return StatusClassOther
+}
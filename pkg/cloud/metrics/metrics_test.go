@@ -0,0 +1,161 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/googleapi"
+)
+
+type record struct {
+	labels  Labels
+	latency time.Duration
+}
+
+type fakeRecorder struct {
+	records []record
+}
+
+func (r *fakeRecorder) Record(l Labels, latency time.Duration) {
+	r.records = append(r.records, record{l, latency})
+}
+
+func TestStatusClass(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		err  error
+		want string
+	}{
+		{err: nil, want: "2xx"},
+		{err: &googleapi.Error{Code: http.StatusNotFound}, want: "4xx"},
+		{err: fmt.Errorf("wrapped: %w", &googleapi.Error{Code: http.StatusServiceUnavailable}), want: "5xx"},
+		{err: context.Canceled, want: StatusClassCanceled},
+		{err: context.DeadlineExceeded, want: StatusClassCanceled},
+		{err: errors.New("connection reset"), want: StatusClassOther},
+	} {
+		if got := StatusClass(tc.err); got != tc.want {
+			t.Errorf("StatusClass(%v) = %q, want %q", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestObserver(t *testing.T) {
+	t.Parallel()
+
+	r := &fakeRecorder{}
+	obs := NewObserver(r)
+	now := time.Unix(0, 0)
+	obs.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		ck  *cloud.CallContextKey
+		err error
+	}{
+		{ck: &cloud.CallContextKey{Service: "Addresses", Operation: "Get", Version: meta.VersionGA}},
+		{ck: &cloud.CallContextKey{Service: "Addresses", Operation: "Insert", Version: meta.VersionBeta}, err: &googleapi.Error{Code: http.StatusConflict}},
+	} {
+		obs.Start(ctx, tc.ck)
+		obs.End(ctx, tc.ck, tc.err)
+	}
+	// End without Start is ignored.
+	obs.End(ctx, &cloud.CallContextKey{}, nil)
+
+	want := []record{
+		{Labels{Resource: "Addresses", Verb: "Get", Version: "ga", StatusClass: "2xx"}, time.Second},
+		{Labels{Resource: "Addresses", Verb: "Insert", Version: "beta", StatusClass: "4xx"}, time.Second},
+	}
+	if diff := cmp.Diff(want, r.records, cmp.AllowUnexported(record{})); diff != "" {
+		t.Errorf("records: -want +got: %s", diff)
+	}
+	if len(obs.start) != 0 {
+		t.Errorf("len(obs.start) = %d, want 0", len(obs.start))
+	}
+}
+
+type fakeSlowCallRecorder struct {
+	labels  []Labels
+	elapsed []time.Duration
+}
+
+func (r *fakeSlowCallRecorder) RecordSlowCall(l Labels, elapsed time.Duration) {
+	r.labels = append(r.labels, l)
+	r.elapsed = append(r.elapsed, elapsed)
+}
+
+func TestOnSlowCall(t *testing.T) {
+	t.Parallel()
+
+	r := &fakeSlowCallRecorder{}
+	f := OnSlowCall(r)
+	f(context.Background(), &cloud.SlowCall{
+		CallContextKey: &cloud.CallContextKey{Service: "Instances", Operation: "Insert", Version: meta.VersionGA},
+		Elapsed:        time.Minute,
+	})
+
+	want := []Labels{{Resource: "Instances", Verb: "Insert", Version: "ga", StatusClass: StatusClassInProgress}}
+	if diff := cmp.Diff(want, r.labels); diff != "" {
+		t.Errorf("labels: -want +got: %s", diff)
+	}
+	if len(r.elapsed) != 1 || r.elapsed[0] != time.Minute {
+		t.Errorf("elapsed = %v, want [%v]", r.elapsed, time.Minute)
+	}
+}
+
+type fakeErrorRecorder struct {
+	labels  []Labels
+	classes []cloud.ErrorClass
+}
+
+func (r *fakeErrorRecorder) RecordError(l Labels, class cloud.ErrorClass) {
+	r.labels = append(r.labels, l)
+	r.classes = append(r.classes, class)
+}
+
+func TestOnError(t *testing.T) {
+	t.Parallel()
+
+	r := &fakeErrorRecorder{}
+	f := OnError(r)
+	err := &googleapi.Error{Code: http.StatusTooManyRequests}
+	f(context.Background(), &cloud.CallError{
+		CallContextKey: &cloud.CallContextKey{Service: "Instances", Operation: "Insert", Version: meta.VersionGA},
+		Class:          cloud.ClassifyError(err),
+		Err:            err,
+	})
+
+	want := []Labels{{Resource: "Instances", Verb: "Insert", Version: "ga", StatusClass: "4xx"}}
+	if diff := cmp.Diff(want, r.labels); diff != "" {
+		t.Errorf("labels: -want +got: %s", diff)
+	}
+	if diff := cmp.Diff([]cloud.ErrorClass{cloud.ErrorClassQuota}, r.classes); diff != "" {
+		t.Errorf("classes: -want +got: %s", diff)
+	}
+}
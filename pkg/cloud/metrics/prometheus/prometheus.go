@@ -0,0 +1,123 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package prometheus exports the metrics of the calls to the API to
+// Prometheus.
+//
+//	r, err := prometheus.NewRecorder(prom.DefaultRegisterer)
+//	obs := metrics.NewObserver(r)
+//	ctx = cloud.WithCallObserver(ctx, obs)
+//	svc.OnError = metrics.OnError(r)
+//	watchdog.OnSlowCall = metrics.OnSlowCall(r)
+//
+// All the metrics have the labels metrics.LabelNames. The count of the calls
+// is the count of the latency histogram.
+package prometheus
+
+import (
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/metrics"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// Names of the metrics registered by NewRecorder.
+const (
+	// CallLatencyName is the histogram of the latency of the calls in
+	// seconds.
+	CallLatencyName = "gce_api_call_duration_seconds"
+	// CallErrorsName is the count of the failed calls.
+	CallErrorsName = "gce_api_call_errors_total"
+	// SlowCallsName is the count of the calls reported by the
+	// cloud.Watchdog.
+	SlowCallsName = "gce_api_slow_calls_total"
+	// ErrorClassesName is the count of the failed calls by cloud.ErrorClass,
+	// in the label ErrorClassLabel.
+	ErrorClassesName = "gce_api_call_error_classes_total"
+	// ErrorClassLabel is the label of the cloud.ErrorClass.
+	ErrorClassLabel = "error_class"
+)
+
+// LatencyBuckets are the buckets of the latency histogram, from 10ms to
+// about 3 minutes, as operations (e.g. Insert) take much longer than reads.
+var LatencyBuckets = prom.ExponentialBuckets(0.01, 2, 15)
+
+// Recorder records the calls to Prometheus. It implements
+// metrics.Recorder, metrics.SlowCallRecorder and metrics.ErrorRecorder.
+type Recorder struct {
+	latency      *prom.HistogramVec
+	errors       *prom.CounterVec
+	slowCalls    *prom.CounterVec
+	errorClasses *prom.CounterVec
+}
+
+var (
+	_ metrics.Recorder         = (*Recorder)(nil)
+	_ metrics.SlowCallRecorder = (*Recorder)(nil)
+	_ metrics.ErrorRecorder    = (*Recorder)(nil)
+)
+
+// NewRecorder returns a Recorder with its metrics registered to reg. If reg
+// is nil, prometheus.DefaultRegisterer is used.
+func NewRecorder(reg prom.Registerer) (*Recorder, error) {
+	if reg == nil {
+		reg = prom.DefaultRegisterer
+	}
+	r := &Recorder{
+		latency: prom.NewHistogramVec(prom.HistogramOpts{
+			Name:    CallLatencyName,
+			Help:    "Latency of the calls to the API in seconds.",
+			Buckets: LatencyBuckets,
+		}, metrics.LabelNames),
+		errors: prom.NewCounterVec(prom.CounterOpts{
+			Name: CallErrorsName,
+			Help: "Number of the calls to the API that returned an error.",
+		}, metrics.LabelNames),
+		slowCalls: prom.NewCounterVec(prom.CounterOpts{
+			Name: SlowCallsName,
+			Help: "Number of the calls to the API that exceeded the watchdog threshold.",
+		}, metrics.LabelNames),
+		errorClasses: prom.NewCounterVec(prom.CounterOpts{
+			Name: ErrorClassesName,
+			Help: "Number of the calls to the API that returned an error, by class of error.",
+		}, append(append([]string{}, metrics.LabelNames...), ErrorClassLabel)),
+	}
+	for _, c := range []prom.Collector{r.latency, r.errors, r.slowCalls, r.errorClasses} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Record implements metrics.Recorder.
+func (r *Recorder) Record(l metrics.Labels, latency time.Duration) {
+	r.latency.WithLabelValues(l.Values()...).Observe(latency.Seconds())
+	if l.StatusClass != metrics.StatusClassOK {
+		r.errors.WithLabelValues(l.Values()...).Inc()
+	}
+}
+
+// RecordSlowCall implements metrics.SlowCallRecorder.
+func (r *Recorder) RecordSlowCall(l metrics.Labels, elapsed time.Duration) {
+	r.slowCalls.WithLabelValues(l.Values()...).Inc()
+}
+
+// RecordError implements metrics.ErrorRecorder.
+func (r *Recorder) RecordError(l metrics.Labels, class cloud.ErrorClass) {
+	r.errorClasses.WithLabelValues(append(l.Values(), string(class))...).Inc()
+}
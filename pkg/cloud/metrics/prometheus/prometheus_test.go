@@ -0,0 +1,76 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prometheus
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/metrics"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	reg := prom.NewRegistry()
+	r, err := NewRecorder(reg)
+	if err != nil {
+		t.Fatalf("NewRecorder() = _, %v, want nil", err)
+	}
+
+	ok := metrics.Labels{Resource: "Addresses", Verb: "Get", Version: "ga", StatusClass: metrics.StatusClassOK}
+	failed := metrics.Labels{Resource: "Addresses", Verb: "Insert", Version: "ga", StatusClass: "4xx"}
+	r.Record(ok, time.Second)
+	r.Record(ok, 2*time.Second)
+	r.Record(failed, time.Second)
+	r.RecordError(failed, cloud.ErrorClassQuota)
+	r.RecordSlowCall(metrics.Labels{Resource: "Addresses", Verb: "Insert", Version: "ga", StatusClass: metrics.StatusClassInProgress}, time.Minute)
+
+	if got := testutil.CollectAndCount(r.latency); got != 2 {
+		t.Errorf("latency series = %d, want 2", got)
+	}
+	if got := testutil.ToFloat64(r.errors.WithLabelValues(failed.Values()...)); got != 1 {
+		t.Errorf("errors{%v} = %v, want 1", failed, got)
+	}
+	if got := testutil.CollectAndCount(r.errors); got != 1 {
+		t.Errorf("errors series = %d, want 1", got)
+	}
+	if got := testutil.ToFloat64(r.errorClasses.WithLabelValues(append(failed.Values(), string(cloud.ErrorClassQuota))...)); got != 1 {
+		t.Errorf("errorClasses = %v, want 1", got)
+	}
+	if got := testutil.CollectAndCount(r.slowCalls); got != 1 {
+		t.Errorf("slowCalls series = %d, want 1", got)
+	}
+
+	want := `
+# HELP gce_api_call_errors_total Number of the calls to the API that returned an error.
+# TYPE gce_api_call_errors_total counter
+gce_api_call_errors_total{resource="Addresses",status_class="4xx",verb="Insert",version="ga"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), CallErrorsName); err != nil {
+		t.Error(err)
+	}
+
+	// The metrics can only be registered once.
+	if _, err := NewRecorder(reg); err == nil {
+		t.Error("NewRecorder() again = _, nil, want error")
+	}
+}
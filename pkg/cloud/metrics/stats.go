@@ -0,0 +1,218 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+)
+
+// DefaultStatsWindow is the window of LiveStats if none is given.
+const DefaultStatsWindow = time.Minute
+
+// CallStats are the statistics of the calls in the window of LiveStats.
+// Durations are in nanoseconds in the JSON encoding.
+type CallStats struct {
+	// Calls is the number of calls that ended in the window.
+	Calls int `json:"calls"`
+	// QPS is Calls per second over the window.
+	QPS float64 `json:"qps"`
+	// InFlight is the number of calls that have started and not ended.
+	InFlight int `json:"inFlight"`
+	// Errors is the number of calls that ended with an error.
+	Errors int `json:"errors"`
+	// ErrorRate is Errors / Calls.
+	ErrorRate float64 `json:"errorRate"`
+	// P50 and P99 are the percentiles of the latency of the calls.
+	P50 time.Duration `json:"p50"`
+	P99 time.Duration `json:"p99"`
+}
+
+// StatsSnapshot is returned by LiveStats.Stats().
+type StatsSnapshot struct {
+	// Window over which the stats are computed.
+	Window time.Duration `json:"window"`
+	// Total are the stats of all calls.
+	Total CallStats `json:"total"`
+	// Resources are the stats by resource, e.g. "Addresses".
+	Resources map[string]CallStats `json:"resources"`
+}
+
+type statsSample struct {
+	end      time.Time
+	resource string
+	latency  time.Duration
+	err      bool
+}
+
+// LiveStats is a cloud.CallObserver that keeps rolling statistics of the
+// calls for inspecting the traffic of a running process without a metrics
+// backend. LiveStats is also an http.Handler that serves Stats() as JSON:
+//
+//	stats := metrics.NewLiveStats(0)
+//	ctx = cloud.WithCallObserver(ctx, stats)
+//	http.Handle("/debug/gce", stats)
+//
+// A sample is kept for each call in the window, so the memory used is
+// proportional to the QPS.
+type LiveStats struct {
+	window time.Duration
+	// now is replaced in unit tests.
+	now func() time.Time
+
+	lock     sync.Mutex
+	start    map[*cloud.CallContextKey]time.Time
+	inFlight map[string]int
+	samples  []statsSample
+}
+
+// NewLiveStats returns a LiveStats over the given window. If window is 0,
+// DefaultStatsWindow is used.
+func NewLiveStats(window time.Duration) *LiveStats {
+	if window <= 0 {
+		window = DefaultStatsWindow
+	}
+	return &LiveStats{
+		window:   window,
+		now:      time.Now,
+		start:    map[*cloud.CallContextKey]time.Time{},
+		inFlight: map[string]int{},
+	}
+}
+
+// LiveStats implements cloud.CallObserver.
+var _ cloud.CallObserver = (*LiveStats)(nil)
+
+// Start implements cloud.CallObserver.
+func (s *LiveStats) Start(ctx context.Context, key *cloud.CallContextKey) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.start[key] = s.now()
+	s.inFlight[key.Service]++
+}
+
+// End implements cloud.CallObserver.
+func (s *LiveStats) End(ctx context.Context, key *cloud.CallContextKey, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	start, ok := s.start[key]
+	if !ok {
+		return
+	}
+	delete(s.start, key)
+	if s.inFlight[key.Service]--; s.inFlight[key.Service] == 0 {
+		delete(s.inFlight, key.Service)
+	}
+	now := s.now()
+	s.samples = append(s.samples, statsSample{
+		end:      now,
+		resource: key.Service,
+		latency:  now.Sub(start),
+		err:      err != nil,
+	})
+	s.prune(now)
+}
+
+// prune removes the samples older than the window. s.lock must be held.
+func (s *LiveStats) prune(now time.Time) {
+	i := sort.Search(len(s.samples), func(i int) bool {
+		return now.Sub(s.samples[i].end) < s.window
+	})
+	if i > 0 {
+		s.samples = append(s.samples[:0], s.samples[i:]...)
+	}
+}
+
+// Stats returns the statistics of the calls in the window.
+func (s *LiveStats) Stats() *StatsSnapshot {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.prune(s.now())
+
+	var all []time.Duration
+	byResource := map[string][]time.Duration{}
+	total := CallStats{}
+	resources := map[string]CallStats{}
+	for _, sample := range s.samples {
+		all = append(all, sample.latency)
+		byResource[sample.resource] = append(byResource[sample.resource], sample.latency)
+		total.Calls++
+		rs := resources[sample.resource]
+		rs.Calls++
+		if sample.err {
+			total.Errors++
+			rs.Errors++
+		}
+		resources[sample.resource] = rs
+	}
+	for resource, n := range s.inFlight {
+		total.InFlight += n
+		rs := resources[resource]
+		rs.InFlight = n
+		resources[resource] = rs
+	}
+	s.finish(&total, all)
+	for resource, rs := range resources {
+		s.finish(&rs, byResource[resource])
+		resources[resource] = rs
+	}
+	return &StatsSnapshot{
+		Window:    s.window,
+		Total:     total,
+		Resources: resources,
+	}
+}
+
+// finish computes the rates and percentiles of cs from the latencies.
+func (s *LiveStats) finish(cs *CallStats, latencies []time.Duration) {
+	cs.QPS = float64(cs.Calls) / s.window.Seconds()
+	if cs.Calls > 0 {
+		cs.ErrorRate = float64(cs.Errors) / float64(cs.Calls)
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	cs.P50 = percentile(latencies, 50)
+	cs.P99 = percentile(latencies, 99)
+}
+
+// percentile p of the sorted latencies using the nearest rank.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// ServeHTTP implements http.Handler.
+func (s *LiveStats) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(s.Stats())
+}
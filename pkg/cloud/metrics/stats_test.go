@@ -0,0 +1,118 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLiveStats(t *testing.T) {
+	t.Parallel()
+
+	stats := NewLiveStats(10 * time.Second)
+	now := time.Unix(0, 0)
+	stats.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	call := func(service string, latency time.Duration, err error) {
+		ck := &cloud.CallContextKey{Service: service, Operation: "Get"}
+		stats.Start(ctx, ck)
+		now = now.Add(latency)
+		stats.End(ctx, ck, err)
+	}
+
+	// Falls out of the window.
+	call("Addresses", 100*time.Second, nil)
+	now = now.Add(20 * time.Second)
+	for i := 1; i <= 10; i++ {
+		call("Addresses", time.Duration(i)*100*time.Millisecond, nil)
+	}
+	call("Firewalls", time.Second, errors.New("injected"))
+	inFlight := &cloud.CallContextKey{Service: "Firewalls", Operation: "Insert"}
+	stats.Start(ctx, inFlight)
+
+	want := &StatsSnapshot{
+		Window: 10 * time.Second,
+		Total: CallStats{
+			Calls:     11,
+			QPS:       1.1,
+			InFlight:  1,
+			Errors:    1,
+			ErrorRate: 1.0 / 11,
+			P50:       600 * time.Millisecond,
+			P99:       time.Second,
+		},
+		Resources: map[string]CallStats{
+			"Addresses": {
+				Calls: 10,
+				QPS:   1,
+				P50:   500 * time.Millisecond,
+				P99:   time.Second,
+			},
+			"Firewalls": {
+				Calls:     1,
+				QPS:       0.1,
+				InFlight:  1,
+				Errors:    1,
+				ErrorRate: 1,
+				P50:       time.Second,
+				P99:       time.Second,
+			},
+		},
+	}
+	if diff := cmp.Diff(want, stats.Stats()); diff != "" {
+		t.Errorf("Stats(): -want +got: %s", diff)
+	}
+
+	stats.End(ctx, inFlight, nil)
+	now = now.Add(time.Hour)
+	want = &StatsSnapshot{
+		Window:    10 * time.Second,
+		Resources: map[string]CallStats{},
+	}
+	if diff := cmp.Diff(want, stats.Stats()); diff != "" {
+		t.Errorf("Stats() after window: -want +got: %s", diff)
+	}
+}
+
+func TestLiveStatsServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	stats := NewLiveStats(0)
+	ck := &cloud.CallContextKey{Service: "Addresses", Operation: "Get"}
+	stats.Start(context.Background(), ck)
+	stats.End(context.Background(), ck, nil)
+
+	rec := httptest.NewRecorder()
+	stats.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/gce", nil))
+
+	var got StatsSnapshot
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal(%q) = %v", rec.Body.String(), err)
+	}
+	if got.Window != DefaultStatsWindow || got.Total.Calls != 1 || got.Resources["Addresses"].Calls != 1 {
+		t.Errorf("ServeHTTP() = %+v, want 1 call to Addresses in %v", got, DefaultStatsWindow)
+	}
+}
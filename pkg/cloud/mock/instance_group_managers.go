@@ -0,0 +1,367 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// CreatedByMetadataKey is the Instance metadata key that references the
+// InstanceGroupManager that created the Instance.
+const CreatedByMetadataKey = "created-by"
+
+// The InstanceGroupManager hooks below create and delete the Instances of the
+// group in the mock, so they are constructed from the MockGCE:
+//
+//	m := mockGCE.MockInstanceGroupManagers
+//	m.InsertHook = mock.InsertInstanceGroupManagerHook(mockGCE)
+//	m.DeleteHook = mock.DeleteInstanceGroupManagerHook(mockGCE)
+//	m.ResizeHook = mock.ResizeInstanceGroupManagerHook(mockGCE)
+//	m.CreateInstancesHook = mock.CreateManagedInstancesHook(mockGCE)
+//	m.DeleteInstancesHook = mock.DeleteManagedInstancesHook(mockGCE)
+//	m.RecreateInstancesHook = mock.RecreateManagedInstancesHook(mockGCE)
+//	m.SetInstanceTemplateHook = mock.SetInstanceTemplateHook(mockGCE)
+//	m.ListManagedInstancesHook = mock.ListManagedInstancesHook(mockGCE)
+//
+// The Instances are created from the InstanceTemplate of the group and are
+// named "<baseInstanceName>-<NNNN>".
+
+// InsertInstanceGroupManagerHook returns a hook that checks the
+// InstanceTemplate of the InstanceGroupManager and creates TargetSize
+// Instances.
+func InsertInstanceGroupManagerHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *ga.InstanceGroupManager, *cloud.MockInstanceGroupManagers) (bool, error) {
+	return func(ctx context.Context, key *meta.Key, obj *ga.InstanceGroupManager, m *cloud.MockInstanceGroupManagers) (bool, error) {
+		if _, err := m.Get(ctx, key); err == nil {
+			return true, &googleapi.Error{
+				Code:    http.StatusConflict,
+				Message: fmt.Sprintf("InstanceGroupManager %v exists", key),
+			}
+		}
+		if _, err := instanceTemplate(ctx, mockGCE, obj.InstanceTemplate); err != nil {
+			return true, err
+		}
+		if obj.BaseInstanceName == "" {
+			obj.BaseInstanceName = key.Name
+		}
+		projectID := m.ProjectRouter.ProjectID(ctx, "ga", "instanceGroupManagers")
+		// Same as the SelfLink set by Insert().
+		igm := cloud.DeepCopyInstanceGroupManager(obj)
+		igm.SelfLink = cloud.SelfLink(meta.VersionGA, projectID, "instanceGroupManagers", key)
+		for i := int64(0); i < obj.TargetSize; i++ {
+			if err := createManagedInstance(ctx, mockGCE, key, igm, ""); err != nil {
+				return true, err
+			}
+		}
+		return false, nil
+	}
+}
+
+// DeleteInstanceGroupManagerHook returns a hook that deletes the Instances of
+// the InstanceGroupManager together with the group.
+func DeleteInstanceGroupManagerHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *cloud.MockInstanceGroupManagers) (bool, error) {
+	return func(ctx context.Context, key *meta.Key, m *cloud.MockInstanceGroupManagers) (bool, error) {
+		igm, err := m.Get(ctx, key)
+		if err != nil {
+			return true, err
+		}
+		instances, err := managedInstances(ctx, mockGCE, key, igm)
+		if err != nil {
+			return true, err
+		}
+		for _, instance := range instances {
+			if err := mockGCE.Instances().Delete(ctx, meta.ZonalKey(instance.Name, key.Zone)); err != nil {
+				return true, err
+			}
+		}
+		return false, nil
+	}
+}
+
+// ResizeInstanceGroupManagerHook returns a hook that creates or deletes
+// Instances to match the new size of the InstanceGroupManager.
+func ResizeInstanceGroupManagerHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, int64, *cloud.MockInstanceGroupManagers) error {
+	return func(ctx context.Context, key *meta.Key, size int64, m *cloud.MockInstanceGroupManagers) error {
+		if size < 0 {
+			return &googleapi.Error{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("InstanceGroupManager %v: invalid size %d", key, size),
+			}
+		}
+		igm, err := m.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		instances, err := managedInstances(ctx, mockGCE, key, igm)
+		if err != nil {
+			return err
+		}
+		for n := int64(len(instances)); n < size; n++ {
+			if err := createManagedInstance(ctx, mockGCE, key, igm, ""); err != nil {
+				return err
+			}
+		}
+		// Scale down removes the newest Instances first.
+		for n := int64(len(instances)); n > size; n-- {
+			if err := mockGCE.Instances().Delete(ctx, meta.ZonalKey(instances[n-1].Name, key.Zone)); err != nil {
+				return err
+			}
+		}
+		return updateInstanceGroupManager(key, m, func(igm *ga.InstanceGroupManager) {
+			igm.TargetSize = size
+		})
+	}
+}
+
+// CreateManagedInstancesHook returns a hook that creates the named Instances
+// in the InstanceGroupManager and increases the target size.
+func CreateManagedInstancesHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *ga.InstanceGroupManagersCreateInstancesRequest, *cloud.MockInstanceGroupManagers) error {
+	return func(ctx context.Context, key *meta.Key, req *ga.InstanceGroupManagersCreateInstancesRequest, m *cloud.MockInstanceGroupManagers) error {
+		igm, err := m.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		for _, config := range req.Instances {
+			if config.Name == "" {
+				return &googleapi.Error{
+					Code:    http.StatusBadRequest,
+					Message: fmt.Sprintf("InstanceGroupManager %v: instance name is required", key),
+				}
+			}
+			if err := createManagedInstance(ctx, mockGCE, key, igm, config.Name); err != nil {
+				return err
+			}
+		}
+		return updateInstanceGroupManager(key, m, func(igm *ga.InstanceGroupManager) {
+			igm.TargetSize += int64(len(req.Instances))
+		})
+	}
+}
+
+// DeleteManagedInstancesHook returns a hook that deletes the given Instances
+// of the InstanceGroupManager and decreases the target size.
+func DeleteManagedInstancesHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *ga.InstanceGroupManagersDeleteInstancesRequest, *cloud.MockInstanceGroupManagers) error {
+	return func(ctx context.Context, key *meta.Key, req *ga.InstanceGroupManagersDeleteInstancesRequest, m *cloud.MockInstanceGroupManagers) error {
+		igm, err := m.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		keys, err := memberKeys(ctx, mockGCE, key, igm, req.Instances)
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if err := mockGCE.Instances().Delete(ctx, k); err != nil {
+				return err
+			}
+		}
+		return updateInstanceGroupManager(key, m, func(igm *ga.InstanceGroupManager) {
+			igm.TargetSize -= int64(len(keys))
+		})
+	}
+}
+
+// RecreateManagedInstancesHook returns a hook that replaces the given
+// Instances of the InstanceGroupManager with Instances created from the
+// current InstanceTemplate.
+func RecreateManagedInstancesHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *ga.InstanceGroupManagersRecreateInstancesRequest, *cloud.MockInstanceGroupManagers) error {
+	return func(ctx context.Context, key *meta.Key, req *ga.InstanceGroupManagersRecreateInstancesRequest, m *cloud.MockInstanceGroupManagers) error {
+		igm, err := m.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		keys, err := memberKeys(ctx, mockGCE, key, igm, req.Instances)
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if err := mockGCE.Instances().Delete(ctx, k); err != nil {
+				return err
+			}
+			if err := createManagedInstance(ctx, mockGCE, key, igm, k.Name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// SetInstanceTemplateHook returns a hook that checks and sets the
+// InstanceTemplate of the InstanceGroupManager. Existing Instances are not
+// changed.
+func SetInstanceTemplateHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *ga.InstanceGroupManagersSetInstanceTemplateRequest, *cloud.MockInstanceGroupManagers) error {
+	return func(ctx context.Context, key *meta.Key, req *ga.InstanceGroupManagersSetInstanceTemplateRequest, m *cloud.MockInstanceGroupManagers) error {
+		if _, err := instanceTemplate(ctx, mockGCE, req.InstanceTemplate); err != nil {
+			return err
+		}
+		return updateInstanceGroupManager(key, m, func(igm *ga.InstanceGroupManager) {
+			igm.InstanceTemplate = req.InstanceTemplate
+		})
+	}
+}
+
+// ListManagedInstancesHook returns a hook that lists the Instances of the
+// InstanceGroupManager.
+func ListManagedInstancesHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *filter.F, *cloud.MockInstanceGroupManagers) ([]*ga.ManagedInstance, error) {
+	return func(ctx context.Context, key *meta.Key, fl *filter.F, m *cloud.MockInstanceGroupManagers) ([]*ga.ManagedInstance, error) {
+		igm, err := m.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		instances, err := managedInstances(ctx, mockGCE, key, igm)
+		if err != nil {
+			return nil, err
+		}
+		var ret []*ga.ManagedInstance
+		for _, instance := range instances {
+			status := instance.Status
+			if status == "" {
+				status = InstanceStatusRunning
+			}
+			mi := &ga.ManagedInstance{
+				Id:             instance.Id,
+				Instance:       instance.SelfLink,
+				InstanceStatus: status,
+				CurrentAction:  "NONE",
+			}
+			if fl.Match(mi) {
+				ret = append(ret, mi)
+			}
+		}
+		return ret, nil
+	}
+}
+
+// instanceTemplate returns the InstanceTemplate referenced by url.
+func instanceTemplate(ctx context.Context, mockGCE *cloud.MockGCE, url string) (*ga.InstanceTemplate, error) {
+	id, err := cloud.ParseResourceURL(url)
+	if err != nil || id.Resource != "instanceTemplates" || id.Key.Type() != meta.Global {
+		return nil, &googleapi.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("invalid instance template %q", url),
+		}
+	}
+	return mockGCE.InstanceTemplates().Get(ctx, id.Key)
+}
+
+// managedInstances returns the Instances created by the InstanceGroupManager,
+// sorted by name.
+func managedInstances(ctx context.Context, mockGCE *cloud.MockGCE, key *meta.Key, igm *ga.InstanceGroupManager) ([]*ga.Instance, error) {
+	all, err := mockGCE.Instances().List(ctx, key.Zone, filter.None)
+	if err != nil {
+		return nil, err
+	}
+	var ret []*ga.Instance
+	for _, instance := range all {
+		if instance.Metadata == nil {
+			continue
+		}
+		for _, item := range instance.Metadata.Items {
+			if item.Key == CreatedByMetadataKey && item.Value != nil && *item.Value == igm.SelfLink {
+				ret = append(ret, instance)
+			}
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret, nil
+}
+
+// memberKeys returns the keys of the Instances referenced by urls. All of the
+// Instances must be members of the InstanceGroupManager.
+func memberKeys(ctx context.Context, mockGCE *cloud.MockGCE, key *meta.Key, igm *ga.InstanceGroupManager, urls []string) ([]*meta.Key, error) {
+	instances, err := managedInstances(ctx, mockGCE, key, igm)
+	if err != nil {
+		return nil, err
+	}
+	members := map[string]bool{}
+	for _, instance := range instances {
+		members[instance.Name] = true
+	}
+	var ret []*meta.Key
+	for _, url := range urls {
+		id, err := cloud.ParseResourceURL(url)
+		if err != nil || id.Resource != "instances" || id.Key.Zone != key.Zone || !members[id.Key.Name] {
+			return nil, &googleapi.Error{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("InstanceGroupManager %v: %q is not a member of the group", key, url),
+			}
+		}
+		ret = append(ret, id.Key)
+	}
+	return ret, nil
+}
+
+// createManagedInstance creates an Instance of the InstanceGroupManager from
+// its InstanceTemplate. A name is generated from the BaseInstanceName if name
+// is empty.
+func createManagedInstance(ctx context.Context, mockGCE *cloud.MockGCE, key *meta.Key, igm *ga.InstanceGroupManager, name string) error {
+	template, err := instanceTemplate(ctx, mockGCE, igm.InstanceTemplate)
+	if err != nil {
+		return err
+	}
+	for i := 0; name == ""; i++ {
+		candidate := fmt.Sprintf("%s-%04d", igm.BaseInstanceName, i)
+		_, err := mockGCE.Instances().Get(ctx, meta.ZonalKey(candidate, key.Zone))
+		var gerr *googleapi.Error
+		if errors.As(err, &gerr) && gerr.Code == http.StatusNotFound {
+			name = candidate
+		}
+	}
+	createdBy := igm.SelfLink
+	instance := &ga.Instance{
+		Status: InstanceStatusRunning,
+		Metadata: &ga.Metadata{
+			Items: []*ga.MetadataItems{{Key: CreatedByMetadataKey, Value: &createdBy}},
+		},
+	}
+	if p := template.Properties; p != nil {
+		instance.MachineType = p.MachineType
+		instance.Labels = p.Labels
+		instance.Tags = p.Tags
+	}
+	return mockGCE.Instances().Insert(ctx, meta.ZonalKey(name, key.Zone), instance)
+}
+
+// updateInstanceGroupManager calls f on the InstanceGroupManager with the
+// given key and stores the result.
+func updateInstanceGroupManager(key *meta.Key, m *cloud.MockInstanceGroupManagers, f func(*ga.InstanceGroupManager)) error {
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	obj, ok := m.Objects[*key]
+	if !ok {
+		return &googleapi.Error{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("InstanceGroupManager %v not found", key),
+		}
+	}
+	igm := cloud.DeepCopyInstanceGroupManager(obj.ToGA())
+	f(igm)
+	m.Objects[*key] = m.Obj(igm)
+	return nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+func TestInstanceGroupManagerHooks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mockGCE := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "mock-project"})
+	m := mockGCE.MockInstanceGroupManagers
+	m.InsertHook = InsertInstanceGroupManagerHook(mockGCE)
+	m.DeleteHook = DeleteInstanceGroupManagerHook(mockGCE)
+	m.ResizeHook = ResizeInstanceGroupManagerHook(mockGCE)
+	m.CreateInstancesHook = CreateManagedInstancesHook(mockGCE)
+	m.DeleteInstancesHook = DeleteManagedInstancesHook(mockGCE)
+	m.RecreateInstancesHook = RecreateManagedInstancesHook(mockGCE)
+	m.SetInstanceTemplateHook = SetInstanceTemplateHook(mockGCE)
+	m.ListManagedInstancesHook = ListManagedInstancesHook(mockGCE)
+
+	for name, machineType := range map[string]string{"t1": "e2-small", "t2": "e2-large"} {
+		obj := &ga.InstanceTemplate{Properties: &ga.InstanceProperties{MachineType: machineType}}
+		if err := mockGCE.InstanceTemplates().Insert(ctx, meta.GlobalKey(name), obj); err != nil {
+			t.Fatalf("InstanceTemplates().Insert(%s) = %v, want nil", name, err)
+		}
+	}
+	t1 := cloud.SelfLink(meta.VersionGA, "mock-project", "instanceTemplates", meta.GlobalKey("t1"))
+	t2 := cloud.SelfLink(meta.VersionGA, "mock-project", "instanceTemplates", meta.GlobalKey("t2"))
+	zone := "us-central1-b"
+	key := meta.ZonalKey("mig", zone)
+	instanceURL := func(name string) string {
+		return cloud.SelfLink(meta.VersionGA, "mock-project", "instances", meta.ZonalKey(name, zone))
+	}
+
+	var gerr *googleapi.Error
+	missing := &ga.InstanceGroupManager{InstanceTemplate: cloud.SelfLink(meta.VersionGA, "mock-project", "instanceTemplates", meta.GlobalKey("missing")), TargetSize: 1}
+	if err := mockGCE.InstanceGroupManagers().Insert(ctx, key, missing); !errors.As(err, &gerr) || gerr.Code != http.StatusNotFound {
+		t.Errorf("Insert() with missing template = %v, want 404", err)
+	}
+	if err := mockGCE.InstanceGroupManagers().Insert(ctx, key, &ga.InstanceGroupManager{InstanceTemplate: t1, TargetSize: 2}); err != nil {
+		t.Fatalf("Insert(%v) = %v, want nil", key, err)
+	}
+
+	names := func() []string {
+		t.Helper()
+		mis, err := mockGCE.InstanceGroupManagers().ListManagedInstances(ctx, key, filter.None)
+		if err != nil {
+			t.Fatalf("ListManagedInstances(%v) = _, %v, want nil", key, err)
+		}
+		var ret []string
+		for _, mi := range mis {
+			id, _ := cloud.ParseResourceURL(mi.Instance)
+			ret = append(ret, id.Key.Name)
+		}
+		return ret
+	}
+	targetSize := func() int64 {
+		igm, _ := mockGCE.InstanceGroupManagers().Get(ctx, key)
+		return igm.TargetSize
+	}
+	machineType := func(name string) string {
+		instance, err := mockGCE.Instances().Get(ctx, meta.ZonalKey(name, zone))
+		if err != nil {
+			return ""
+		}
+		return instance.MachineType
+	}
+
+	if got, want := names(), []string{"mig-0000", "mig-0001"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after Insert: instances = %v, want %v", got, want)
+	}
+	if err := mockGCE.InstanceGroupManagers().Resize(ctx, key, 3); err != nil {
+		t.Fatalf("Resize(3) = %v, want nil", err)
+	}
+	if got, want := names(), []string{"mig-0000", "mig-0001", "mig-0002"}; !reflect.DeepEqual(got, want) || targetSize() != 3 {
+		t.Errorf("after Resize(3): instances = %v, target size = %d; want %v, 3", got, targetSize(), want)
+	}
+
+	if err := mockGCE.InstanceGroupManagers().SetInstanceTemplate(ctx, key, &ga.InstanceGroupManagersSetInstanceTemplateRequest{InstanceTemplate: t2}); err != nil {
+		t.Fatalf("SetInstanceTemplate(t2) = %v, want nil", err)
+	}
+	if got := machineType("mig-0000"); got != "e2-small" {
+		t.Errorf("after SetInstanceTemplate: MachineType = %q, want e2-small (unchanged)", got)
+	}
+	if err := mockGCE.InstanceGroupManagers().RecreateInstances(ctx, key, &ga.InstanceGroupManagersRecreateInstancesRequest{Instances: []string{instanceURL("mig-0000")}}); err != nil {
+		t.Fatalf("RecreateInstances() = %v, want nil", err)
+	}
+	if got := machineType("mig-0000"); got != "e2-large" {
+		t.Errorf("after RecreateInstances: MachineType = %q, want e2-large", got)
+	}
+
+	if err := mockGCE.InstanceGroupManagers().DeleteInstances(ctx, key, &ga.InstanceGroupManagersDeleteInstancesRequest{Instances: []string{instanceURL("other")}}); !errors.As(err, &gerr) || gerr.Code != http.StatusBadRequest {
+		t.Errorf("DeleteInstances(other) = %v, want 400", err)
+	}
+	if err := mockGCE.InstanceGroupManagers().DeleteInstances(ctx, key, &ga.InstanceGroupManagersDeleteInstancesRequest{Instances: []string{instanceURL("mig-0001")}}); err != nil {
+		t.Fatalf("DeleteInstances(mig-0001) = %v, want nil", err)
+	}
+	if err := mockGCE.InstanceGroupManagers().CreateInstances(ctx, key, &ga.InstanceGroupManagersCreateInstancesRequest{Instances: []*ga.PerInstanceConfig{{Name: "named"}}}); err != nil {
+		t.Fatalf("CreateInstances(named) = %v, want nil", err)
+	}
+	if got, want := names(), []string{"mig-0000", "mig-0002", "named"}; !reflect.DeepEqual(got, want) || targetSize() != 3 {
+		t.Errorf("after DeleteInstances, CreateInstances: instances = %v, target size = %d; want %v, 3", got, targetSize(), want)
+	}
+
+	if err := mockGCE.InstanceGroupManagers().Resize(ctx, key, 1); err != nil {
+		t.Fatalf("Resize(1) = %v, want nil", err)
+	}
+	if got, want := names(), []string{"mig-0000"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after Resize(1): instances = %v, want %v", got, want)
+	}
+	if err := mockGCE.InstanceGroupManagers().Delete(ctx, key); err != nil {
+		t.Fatalf("Delete(%v) = %v, want nil", key, err)
+	}
+	if all, _ := mockGCE.Instances().List(ctx, zone, filter.None); len(all) != 0 {
+		t.Errorf("after Delete: %d instances, want 0", len(all))
+	}
+}
@@ -0,0 +1,411 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// Instance statuses maintained by the Instance lifecycle hooks. An Instance
+// without a status is RUNNING.
+const (
+	InstanceStatusRunning    = "RUNNING"
+	InstanceStatusTerminated = "TERMINATED"
+	InstanceStatusSuspended  = "SUSPENDED"
+)
+
+// The Instance lifecycle hooks below change the status of the Instance in the
+// same way as the API:
+//
+//	mockGCE.MockInstances.StartHook = mock.StartInstanceHook
+//	mockGCE.MockInstances.StopHook = mock.StopInstanceHook
+//	mockGCE.MockInstances.ResetHook = mock.ResetInstanceHook
+//	mockGCE.MockInstances.SuspendHook = mock.SuspendInstanceHook
+//	mockGCE.MockInstances.ResumeHook = mock.ResumeInstanceHook
+
+// instanceTransition is the statuses a lifecycle method can be called in and
+// the resulting status.
+type instanceTransition struct {
+	from []string
+	to   string
+}
+
+var instanceTransitions = map[string]instanceTransition{
+	"Start":   {from: []string{InstanceStatusRunning, InstanceStatusTerminated}, to: InstanceStatusRunning},
+	"Stop":    {from: []string{InstanceStatusRunning, InstanceStatusSuspended, InstanceStatusTerminated}, to: InstanceStatusTerminated},
+	"Reset":   {from: []string{InstanceStatusRunning}, to: InstanceStatusRunning},
+	"Suspend": {from: []string{InstanceStatusRunning, InstanceStatusSuspended}, to: InstanceStatusSuspended},
+	"Resume":  {from: []string{InstanceStatusSuspended}, to: InstanceStatusRunning},
+}
+
+// updateInstance calls f on the Instance with the given key and stores the
+// result if f does not return an error. Instances of all versions share
+// objects, the lock must be the lock of the mock that owns the objects.
+func updateInstance(key *meta.Key, lock *sync.Mutex, objects map[meta.Key]*cloud.MockInstancesObj, f func(*alpha.Instance) error) error {
+	key = key.Normalize()
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	obj, ok := objects[*key]
+	if !ok {
+		return &googleapi.Error{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("Instance %v not found", key),
+		}
+	}
+	// Alpha has all of the fields of the other versions, so the object is
+	// stored without losing any fields. The object is copied so that it is
+	// unchanged if f fails.
+	instance := cloud.DeepCopyAlphaInstance(obj.ToAlpha())
+	if err := f(instance); err != nil {
+		return err
+	}
+	objects[*key] = &cloud.MockInstancesObj{Obj: instance}
+	return nil
+}
+
+// transitionInstance applies the lifecycle method to the status of the
+// Instance with the given key.
+func transitionInstance(key *meta.Key, method string, lock *sync.Mutex, objects map[meta.Key]*cloud.MockInstancesObj) error {
+	return updateInstance(key, lock, objects, func(instance *alpha.Instance) error {
+		status := instance.Status
+		if status == "" {
+			status = InstanceStatusRunning
+		}
+		t := instanceTransitions[method]
+		for _, from := range t.from {
+			if status == from {
+				instance.Status = t.to
+				return nil
+			}
+		}
+		return &googleapi.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Instance %v: cannot %s an instance that is %s", key, method, status),
+		}
+	})
+}
+
+// StartInstanceHook mocks starting a TERMINATED Instance.
+func StartInstanceHook(ctx context.Context, key *meta.Key, m *cloud.MockInstances) error {
+	return transitionInstance(key, "Start", &m.Lock, m.Objects)
+}
+
+// StopInstanceHook mocks stopping an Instance.
+func StopInstanceHook(ctx context.Context, key *meta.Key, m *cloud.MockInstances) error {
+	return transitionInstance(key, "Stop", &m.Lock, m.Objects)
+}
+
+// ResetInstanceHook mocks resetting a RUNNING Instance.
+func ResetInstanceHook(ctx context.Context, key *meta.Key, m *cloud.MockInstances) error {
+	return transitionInstance(key, "Reset", &m.Lock, m.Objects)
+}
+
+// SuspendInstanceHook mocks suspending a RUNNING Instance.
+func SuspendInstanceHook(ctx context.Context, key *meta.Key, m *cloud.MockInstances) error {
+	return transitionInstance(key, "Suspend", &m.Lock, m.Objects)
+}
+
+// ResumeInstanceHook mocks resuming a SUSPENDED Instance.
+func ResumeInstanceHook(ctx context.Context, key *meta.Key, m *cloud.MockInstances) error {
+	return transitionInstance(key, "Resume", &m.Lock, m.Objects)
+}
+
+// StartAlphaInstanceHook mocks starting a TERMINATED Instance.
+func StartAlphaInstanceHook(ctx context.Context, key *meta.Key, m *cloud.MockAlphaInstances) error {
+	return transitionInstance(key, "Start", &m.Lock, m.Objects)
+}
+
+// StopAlphaInstanceHook mocks stopping an Instance.
+func StopAlphaInstanceHook(ctx context.Context, key *meta.Key, m *cloud.MockAlphaInstances) error {
+	return transitionInstance(key, "Stop", &m.Lock, m.Objects)
+}
+
+// ResetAlphaInstanceHook mocks resetting a RUNNING Instance.
+func ResetAlphaInstanceHook(ctx context.Context, key *meta.Key, m *cloud.MockAlphaInstances) error {
+	return transitionInstance(key, "Reset", &m.Lock, m.Objects)
+}
+
+// SuspendAlphaInstanceHook mocks suspending a RUNNING Instance.
+func SuspendAlphaInstanceHook(ctx context.Context, key *meta.Key, m *cloud.MockAlphaInstances) error {
+	return transitionInstance(key, "Suspend", &m.Lock, m.Objects)
+}
+
+// ResumeAlphaInstanceHook mocks resuming a SUSPENDED Instance. The encryption
+// keys in the request are not checked.
+func ResumeAlphaInstanceHook(ctx context.Context, key *meta.Key, req *alpha.InstancesResumeRequest, m *cloud.MockAlphaInstances) error {
+	return transitionInstance(key, "Resume", &m.Lock, m.Objects)
+}
+
+// StartBetaInstanceHook mocks starting a TERMINATED Instance.
+func StartBetaInstanceHook(ctx context.Context, key *meta.Key, m *cloud.MockBetaInstances) error {
+	return transitionInstance(key, "Start", &m.Lock, m.Objects)
+}
+
+// StopBetaInstanceHook mocks stopping an Instance.
+func StopBetaInstanceHook(ctx context.Context, key *meta.Key, m *cloud.MockBetaInstances) error {
+	return transitionInstance(key, "Stop", &m.Lock, m.Objects)
+}
+
+// ResetBetaInstanceHook mocks resetting a RUNNING Instance.
+func ResetBetaInstanceHook(ctx context.Context, key *meta.Key, m *cloud.MockBetaInstances) error {
+	return transitionInstance(key, "Reset", &m.Lock, m.Objects)
+}
+
+// SuspendBetaInstanceHook mocks suspending a RUNNING Instance.
+func SuspendBetaInstanceHook(ctx context.Context, key *meta.Key, m *cloud.MockBetaInstances) error {
+	return transitionInstance(key, "Suspend", &m.Lock, m.Objects)
+}
+
+// ResumeBetaInstanceHook mocks resuming a SUSPENDED Instance. The encryption
+// keys in the request are not checked.
+func ResumeBetaInstanceHook(ctx context.Context, key *meta.Key, req *beta.InstancesResumeRequest, m *cloud.MockBetaInstances) error {
+	return transitionInstance(key, "Resume", &m.Lock, m.Objects)
+}
+
+// The disk hooks need access to the Disks in the mock to check that the disk
+// exists and to maintain Disk.Users, so they are constructed from the
+// MockGCE:
+//
+//	mockGCE.MockInstances.AttachDiskHook = mock.AttachDiskInstanceHook(mockGCE)
+//	mockGCE.MockInstances.DetachDiskHook = mock.DetachDiskInstanceHook(mockGCE)
+//	mockGCE.MockInstances.SetMachineTypeHook = mock.SetMachineTypeInstanceHook
+
+// AttachDiskInstanceHook returns a hook that attaches an existing Disk or
+// RegionDisk to the Instance.
+func AttachDiskInstanceHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *ga.AttachedDisk, *cloud.MockInstances) error {
+	return func(ctx context.Context, key *meta.Key, req *ga.AttachedDisk, m *cloud.MockInstances) error {
+		disk := &alpha.AttachedDisk{}
+		if _, err := cloud.Convert(disk, req); err != nil {
+			return err
+		}
+		return attachDisk(ctx, mockGCE, key, disk, &m.Lock, m.Objects)
+	}
+}
+
+// DetachDiskInstanceHook returns a hook that detaches the disk with the given
+// device name from the Instance.
+func DetachDiskInstanceHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, string, *cloud.MockInstances) error {
+	return func(ctx context.Context, key *meta.Key, deviceName string, m *cloud.MockInstances) error {
+		return detachDisk(ctx, mockGCE, key, deviceName, &m.Lock, m.Objects)
+	}
+}
+
+// SetMachineTypeInstanceHook mocks changing the machine type of a TERMINATED
+// Instance.
+func SetMachineTypeInstanceHook(ctx context.Context, key *meta.Key, req *ga.InstancesSetMachineTypeRequest, m *cloud.MockInstances) error {
+	return setMachineType(key, req.MachineType, &m.Lock, m.Objects)
+}
+
+// AttachDiskAlphaInstanceHook returns a hook that attaches an existing Disk
+// or RegionDisk to the Instance.
+func AttachDiskAlphaInstanceHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *alpha.AttachedDisk, *cloud.MockAlphaInstances) error {
+	return func(ctx context.Context, key *meta.Key, req *alpha.AttachedDisk, m *cloud.MockAlphaInstances) error {
+		disk := &alpha.AttachedDisk{}
+		if _, err := cloud.Convert(disk, req); err != nil {
+			return err
+		}
+		return attachDisk(ctx, mockGCE, key, disk, &m.Lock, m.Objects)
+	}
+}
+
+// DetachDiskAlphaInstanceHook returns a hook that detaches the disk with the
+// given device name from the Instance.
+func DetachDiskAlphaInstanceHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, string, *cloud.MockAlphaInstances) error {
+	return func(ctx context.Context, key *meta.Key, deviceName string, m *cloud.MockAlphaInstances) error {
+		return detachDisk(ctx, mockGCE, key, deviceName, &m.Lock, m.Objects)
+	}
+}
+
+// SetMachineTypeAlphaInstanceHook mocks changing the machine type of a
+// TERMINATED Instance.
+func SetMachineTypeAlphaInstanceHook(ctx context.Context, key *meta.Key, req *alpha.InstancesSetMachineTypeRequest, m *cloud.MockAlphaInstances) error {
+	return setMachineType(key, req.MachineType, &m.Lock, m.Objects)
+}
+
+// AttachDiskBetaInstanceHook returns a hook that attaches an existing Disk or
+// RegionDisk to the Instance.
+func AttachDiskBetaInstanceHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *beta.AttachedDisk, *cloud.MockBetaInstances) error {
+	return func(ctx context.Context, key *meta.Key, req *beta.AttachedDisk, m *cloud.MockBetaInstances) error {
+		disk := &alpha.AttachedDisk{}
+		if _, err := cloud.Convert(disk, req); err != nil {
+			return err
+		}
+		return attachDisk(ctx, mockGCE, key, disk, &m.Lock, m.Objects)
+	}
+}
+
+// DetachDiskBetaInstanceHook returns a hook that detaches the disk with the
+// given device name from the Instance.
+func DetachDiskBetaInstanceHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, string, *cloud.MockBetaInstances) error {
+	return func(ctx context.Context, key *meta.Key, deviceName string, m *cloud.MockBetaInstances) error {
+		return detachDisk(ctx, mockGCE, key, deviceName, &m.Lock, m.Objects)
+	}
+}
+
+// SetMachineTypeBetaInstanceHook mocks changing the machine type of a
+// TERMINATED Instance.
+func SetMachineTypeBetaInstanceHook(ctx context.Context, key *meta.Key, req *beta.InstancesSetMachineTypeRequest, m *cloud.MockBetaInstances) error {
+	return setMachineType(key, req.MachineType, &m.Lock, m.Objects)
+}
+
+// attachedDiskKey returns the key of the Disk or RegionDisk referenced by
+// source.
+func attachedDiskKey(source string) (*meta.Key, error) {
+	id, err := cloud.ParseResourceURL(source)
+	if err != nil || id.Resource != "disks" {
+		return nil, &googleapi.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("invalid disk source %q", source),
+		}
+	}
+	return id.Key, nil
+}
+
+func attachDisk(ctx context.Context, mockGCE *cloud.MockGCE, key *meta.Key, disk *alpha.AttachedDisk, lock *sync.Mutex, objects map[meta.Key]*cloud.MockInstancesObj) error {
+	diskKey, err := attachedDiskKey(disk.Source)
+	if err != nil {
+		return err
+	}
+	switch diskKey.Type() {
+	case meta.Zonal:
+		if diskKey.Zone != key.Zone {
+			return &googleapi.Error{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("Disk %v is not in the zone of Instance %v", diskKey, key),
+			}
+		}
+		_, err = mockGCE.Disks().Get(ctx, diskKey)
+	case meta.Regional:
+		_, err = mockGCE.RegionDisks().Get(ctx, diskKey)
+	default:
+		err = &googleapi.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("invalid disk source %q", disk.Source),
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if disk.DeviceName == "" {
+		disk.DeviceName = diskKey.Name
+	}
+
+	var selfLink string
+	err = updateInstance(key, lock, objects, func(instance *alpha.Instance) error {
+		for _, d := range instance.Disks {
+			if d.DeviceName == disk.DeviceName || d.Source == disk.Source {
+				return &googleapi.Error{
+					Code:    http.StatusBadRequest,
+					Message: fmt.Sprintf("Instance %v: disk %q (device %q) is already attached", key, disk.Source, disk.DeviceName),
+				}
+			}
+		}
+		instance.Disks = append(instance.Disks, disk)
+		selfLink = instance.SelfLink
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	updateDiskUsers(mockGCE, diskKey, func(users []string) []string {
+		return append(users, selfLink)
+	})
+	return nil
+}
+
+func detachDisk(ctx context.Context, mockGCE *cloud.MockGCE, key *meta.Key, deviceName string, lock *sync.Mutex, objects map[meta.Key]*cloud.MockInstancesObj) error {
+	var source, selfLink string
+	err := updateInstance(key, lock, objects, func(instance *alpha.Instance) error {
+		for i, d := range instance.Disks {
+			if d.DeviceName == deviceName {
+				source = d.Source
+				selfLink = instance.SelfLink
+				instance.Disks = append(instance.Disks[:i], instance.Disks[i+1:]...)
+				return nil
+			}
+		}
+		return &googleapi.Error{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("Disk: %s was not found in Instance %s", deviceName, key.String()),
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if diskKey, err := attachedDiskKey(source); err == nil {
+		updateDiskUsers(mockGCE, diskKey, func(users []string) []string {
+			var ret []string
+			for _, u := range users {
+				if u != selfLink {
+					ret = append(ret, u)
+				}
+			}
+			return ret
+		})
+	}
+	return nil
+}
+
+// updateDiskUsers sets the Users of the Disk or RegionDisk with the given key
+// to f(Users). Missing disks are ignored.
+func updateDiskUsers(mockGCE *cloud.MockGCE, diskKey *meta.Key, f func([]string) []string) {
+	switch diskKey.Type() {
+	case meta.Zonal:
+		m := mockGCE.MockDisks
+		m.Lock.Lock()
+		defer m.Lock.Unlock()
+		if obj, ok := m.Objects[*diskKey]; ok {
+			disk := obj.ToGA()
+			disk.Users = f(disk.Users)
+			m.Objects[*diskKey] = m.Obj(disk)
+		}
+	case meta.Regional:
+		m := mockGCE.MockRegionDisks
+		m.Lock.Lock()
+		defer m.Lock.Unlock()
+		if obj, ok := m.Objects[*diskKey]; ok {
+			disk := obj.ToGA()
+			disk.Users = f(disk.Users)
+			m.Objects[*diskKey] = m.Obj(disk)
+		}
+	}
+}
+
+func setMachineType(key *meta.Key, machineType string, lock *sync.Mutex, objects map[meta.Key]*cloud.MockInstancesObj) error {
+	return updateInstance(key, lock, objects, func(instance *alpha.Instance) error {
+		if instance.Status != InstanceStatusTerminated {
+			return &googleapi.Error{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("Instance %v: the machine type can only be changed when the instance is %s", key, InstanceStatusTerminated),
+			}
+		}
+		instance.MachineType = machineType
+		return nil
+	})
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+func TestInstanceLifecycleHooks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mockGCE := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "mock-project"})
+	mockGCE.MockInstances.StartHook = StartInstanceHook
+	mockGCE.MockInstances.StopHook = StopInstanceHook
+	mockGCE.MockInstances.ResetHook = ResetInstanceHook
+	mockGCE.MockInstances.SuspendHook = SuspendInstanceHook
+	mockGCE.MockInstances.ResumeHook = ResumeInstanceHook
+	mockGCE.MockAlphaInstances.ResumeHook = ResumeAlphaInstanceHook
+
+	key := meta.ZonalKey("vm", "us-central1-b")
+	var gerr *googleapi.Error
+	if err := mockGCE.Instances().Start(ctx, key); !errors.As(err, &gerr) || gerr.Code != http.StatusNotFound {
+		t.Errorf("Start(%v) = %v, want 404", key, err)
+	}
+	if err := mockGCE.Instances().Insert(ctx, key, &ga.Instance{}); err != nil {
+		t.Fatalf("Insert(%v) = %v, want nil", key, err)
+	}
+
+	for _, tc := range []struct {
+		desc       string
+		op         func() error
+		wantCode   int
+		wantStatus string
+	}{
+		{
+			desc:       "reset running",
+			op:         func() error { return mockGCE.Instances().Reset(ctx, key) },
+			wantStatus: InstanceStatusRunning,
+		},
+		{
+			desc:       "resume running",
+			op:         func() error { return mockGCE.Instances().Resume(ctx, key) },
+			wantCode:   http.StatusBadRequest,
+			wantStatus: InstanceStatusRunning,
+		},
+		{
+			desc:       "suspend",
+			op:         func() error { return mockGCE.Instances().Suspend(ctx, key) },
+			wantStatus: InstanceStatusSuspended,
+		},
+		{
+			desc:       "start suspended",
+			op:         func() error { return mockGCE.Instances().Start(ctx, key) },
+			wantCode:   http.StatusBadRequest,
+			wantStatus: InstanceStatusSuspended,
+		},
+		{
+			desc:       "alpha resume",
+			op:         func() error { return mockGCE.AlphaInstances().Resume(ctx, key, &alpha.InstancesResumeRequest{}) },
+			wantStatus: InstanceStatusRunning,
+		},
+		{
+			desc:       "stop",
+			op:         func() error { return mockGCE.Instances().Stop(ctx, key) },
+			wantStatus: InstanceStatusTerminated,
+		},
+		{
+			desc:       "reset terminated",
+			op:         func() error { return mockGCE.Instances().Reset(ctx, key) },
+			wantCode:   http.StatusBadRequest,
+			wantStatus: InstanceStatusTerminated,
+		},
+		{
+			desc:       "start",
+			op:         func() error { return mockGCE.Instances().Start(ctx, key) },
+			wantStatus: InstanceStatusRunning,
+		},
+	} {
+		err := tc.op()
+		if tc.wantCode == 0 && err != nil {
+			t.Errorf("%s: err = %v, want nil", tc.desc, err)
+		}
+		if tc.wantCode != 0 && (!errors.As(err, &gerr) || gerr.Code != tc.wantCode) {
+			t.Errorf("%s: err = %v, want %d", tc.desc, err, tc.wantCode)
+		}
+		vm, err := mockGCE.Instances().Get(ctx, key)
+		if err != nil {
+			t.Fatalf("%s: Get(%v) = _, %v, want nil", tc.desc, key, err)
+		}
+		if vm.Status != tc.wantStatus {
+			t.Errorf("%s: Status = %q, want %q", tc.desc, vm.Status, tc.wantStatus)
+		}
+	}
+}
+
+func TestInstanceDiskHooks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mockGCE := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "mock-project"})
+	mockGCE.MockInstances.AttachDiskHook = AttachDiskInstanceHook(mockGCE)
+	mockGCE.MockInstances.DetachDiskHook = DetachDiskInstanceHook(mockGCE)
+	mockGCE.MockInstances.StopHook = StopInstanceHook
+	mockGCE.MockInstances.SetMachineTypeHook = SetMachineTypeInstanceHook
+	mockGCE.MockBetaInstances.AttachDiskHook = AttachDiskBetaInstanceHook(mockGCE)
+
+	key := meta.ZonalKey("vm", "us-central1-b")
+	diskKey := meta.ZonalKey("disk", "us-central1-b")
+	otherZoneKey := meta.ZonalKey("other", "us-central1-c")
+	rdKey := meta.RegionalKey("rd", "us-central1")
+	source := cloud.SelfLink(meta.VersionGA, "mock-project", "disks", diskKey)
+	if err := mockGCE.Instances().Insert(ctx, key, &ga.Instance{}); err != nil {
+		t.Fatalf("Insert(%v) = %v, want nil", key, err)
+	}
+	for _, k := range []*meta.Key{diskKey, otherZoneKey} {
+		if err := mockGCE.Disks().Insert(ctx, k, &ga.Disk{}); err != nil {
+			t.Fatalf("Disks().Insert(%v) = %v, want nil", k, err)
+		}
+	}
+	if err := mockGCE.RegionDisks().Insert(ctx, rdKey, &ga.Disk{}); err != nil {
+		t.Fatalf("RegionDisks().Insert(%v) = %v, want nil", rdKey, err)
+	}
+
+	var gerr *googleapi.Error
+	for _, tc := range []struct {
+		desc     string
+		op       func() error
+		wantCode int
+	}{
+		{
+			desc: "attach missing disk",
+			op: func() error {
+				return mockGCE.Instances().AttachDisk(ctx, key, &ga.AttachedDisk{Source: cloud.SelfLink(meta.VersionGA, "mock-project", "disks", meta.ZonalKey("missing", "us-central1-b"))})
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			desc: "attach disk in other zone",
+			op: func() error {
+				return mockGCE.Instances().AttachDisk(ctx, key, &ga.AttachedDisk{Source: cloud.SelfLink(meta.VersionGA, "mock-project", "disks", otherZoneKey)})
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			desc: "attach disk",
+			op:   func() error { return mockGCE.Instances().AttachDisk(ctx, key, &ga.AttachedDisk{Source: source}) },
+		},
+		{
+			desc: "attach disk twice",
+			op: func() error {
+				return mockGCE.Instances().AttachDisk(ctx, key, &ga.AttachedDisk{Source: source, DeviceName: "other"})
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			desc: "beta attach regional disk",
+			op: func() error {
+				return mockGCE.BetaInstances().AttachDisk(ctx, key, &beta.AttachedDisk{Source: cloud.SelfLink(meta.VersionGA, "mock-project", "disks", rdKey), DeviceName: "rd-dev"})
+			},
+		},
+		{
+			desc: "set machine type running",
+			op: func() error {
+				return mockGCE.Instances().SetMachineType(ctx, key, &ga.InstancesSetMachineTypeRequest{MachineType: "n2-standard-2"})
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			desc:     "detach missing device",
+			op:       func() error { return mockGCE.Instances().DetachDisk(ctx, key, "missing") },
+			wantCode: http.StatusNotFound,
+		},
+	} {
+		err := tc.op()
+		if tc.wantCode == 0 && err != nil {
+			t.Errorf("%s: err = %v, want nil", tc.desc, err)
+		}
+		if tc.wantCode != 0 && (!errors.As(err, &gerr) || gerr.Code != tc.wantCode) {
+			t.Errorf("%s: err = %v, want %d", tc.desc, err, tc.wantCode)
+		}
+	}
+
+	vm, _ := mockGCE.Instances().Get(ctx, key)
+	var devices []string
+	for _, d := range vm.Disks {
+		devices = append(devices, d.DeviceName)
+	}
+	if want := []string{"disk", "rd-dev"}; !reflect.DeepEqual(devices, want) {
+		t.Errorf("Disks = %v, want devices %v", devices, want)
+	}
+	disk, _ := mockGCE.Disks().Get(ctx, diskKey)
+	if want := []string{vm.SelfLink}; !reflect.DeepEqual(disk.Users, want) {
+		t.Errorf("Disk.Users = %v, want %v", disk.Users, want)
+	}
+
+	if err := mockGCE.Instances().DetachDisk(ctx, key, "disk"); err != nil {
+		t.Errorf("DetachDisk(disk) = %v, want nil", err)
+	}
+	disk, _ = mockGCE.Disks().Get(ctx, diskKey)
+	if len(disk.Users) != 0 {
+		t.Errorf("Disk.Users = %v, want none", disk.Users)
+	}
+
+	if err := mockGCE.Instances().Stop(ctx, key); err != nil {
+		t.Fatalf("Stop(%v) = %v, want nil", key, err)
+	}
+	if err := mockGCE.Instances().SetMachineType(ctx, key, &ga.InstancesSetMachineTypeRequest{MachineType: "n2-standard-2"}); err != nil {
+		t.Errorf("SetMachineType() = %v, want nil", err)
+	}
+	if vm, _ := mockGCE.Instances().Get(ctx, key); vm.MachineType != "n2-standard-2" || len(vm.Disks) != 1 {
+		t.Errorf("Get() = %+v, want MachineType n2-standard-2 and 1 disk", vm)
+	}
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// Operational status of Interconnects and InterconnectAttachments.
+const (
+	InterconnectOperationalStatusActive        = "OS_ACTIVE"
+	InterconnectOperationalStatusUnprovisioned = "OS_UNPROVISIONED"
+)
+
+// State of InterconnectAttachments.
+const (
+	InterconnectAttachmentStateActive         = "ACTIVE"
+	InterconnectAttachmentStatePendingPartner = "PENDING_PARTNER"
+)
+
+// The InterconnectAttachment hooks below validate the Interconnect and Router
+// references and keep Interconnect.InterconnectAttachments up to date, so
+// they are constructed from the MockGCE:
+//
+//	mockGCE.MockInterconnects.InsertHook = mock.InsertInterconnectHook
+//	mockGCE.MockInterconnects.DeleteHook = mock.DeleteInterconnectHook
+//	mockGCE.MockInterconnects.GetDiagnosticsHook = mock.GetDiagnosticsInterconnectHook
+//	mockGCE.MockInterconnectAttachments.InsertHook = mock.InsertInterconnectAttachmentHook(mockGCE)
+//	mockGCE.MockInterconnectAttachments.DeleteHook = mock.DeleteInterconnectAttachmentHook(mockGCE)
+
+// InsertInterconnectHook marks the Interconnect as provisioned
+// (OperationalStatus OS_ACTIVE) unless the status is set by the caller.
+func InsertInterconnectHook(ctx context.Context, key *meta.Key, obj *ga.Interconnect, m *cloud.MockInterconnects) (bool, error) {
+	if obj.OperationalStatus == "" {
+		obj.OperationalStatus = InterconnectOperationalStatusActive
+	}
+	if obj.State == "" {
+		obj.State = "ACTIVE"
+	}
+	return false, nil
+}
+
+// DeleteInterconnectHook fails with 400 if InterconnectAttachments still
+// reference the Interconnect.
+func DeleteInterconnectHook(ctx context.Context, key *meta.Key, m *cloud.MockInterconnects) (bool, error) {
+	obj, err := m.Get(ctx, key)
+	if err != nil {
+		return true, err
+	}
+	if len(obj.InterconnectAttachments) > 0 {
+		return true, &googleapi.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Interconnect %v is in use by %v", key, obj.InterconnectAttachments),
+		}
+	}
+	return false, nil
+}
+
+// GetDiagnosticsInterconnectHook reports one link per circuit of the
+// Interconnect. The links are up if the Interconnect is OS_ACTIVE.
+func GetDiagnosticsInterconnectHook(ctx context.Context, key *meta.Key, m *cloud.MockInterconnects) (*ga.InterconnectsGetDiagnosticsResponse, error) {
+	obj, err := m.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	bundleStatus, linkStatus := "BUNDLE_OPERATIONAL_STATUS_DOWN", "LINK_OPERATIONAL_STATUS_DOWN"
+	if obj.OperationalStatus == InterconnectOperationalStatusActive {
+		bundleStatus, linkStatus = "BUNDLE_OPERATIONAL_STATUS_UP", "LINK_OPERATIONAL_STATUS_UP"
+	}
+	result := &ga.InterconnectDiagnostics{BundleOperationalStatus: bundleStatus}
+	for _, c := range obj.CircuitInfos {
+		result.Links = append(result.Links, &ga.InterconnectDiagnosticsLinkStatus{
+			CircuitId:         c.GoogleCircuitId,
+			GoogleDemarc:      c.GoogleDemarcId,
+			OperationalStatus: linkStatus,
+		})
+	}
+	return &ga.InterconnectsGetDiagnosticsResponse{Result: result}, nil
+}
+
+// InsertInterconnectAttachmentHook returns a hook that validates the
+// InterconnectAttachment before inserting it. DEDICATED attachments must
+// reference an existing Interconnect and become ACTIVE with the operational
+// status of the Interconnect; PARTNER attachments are PENDING_PARTNER.
+func InsertInterconnectAttachmentHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *ga.InterconnectAttachment, *cloud.MockInterconnectAttachments) (bool, error) {
+	return func(ctx context.Context, key *meta.Key, obj *ga.InterconnectAttachment, m *cloud.MockInterconnectAttachments) (bool, error) {
+		if _, err := m.Get(ctx, key); err == nil {
+			return true, &googleapi.Error{
+				Code:    http.StatusConflict,
+				Message: fmt.Sprintf("InterconnectAttachment %v exists", key),
+			}
+		}
+		if err := validateInterconnectAttachment(ctx, mockGCE, key, obj); err != nil {
+			return true, err
+		}
+
+		if obj.Type == "PARTNER" {
+			obj.State = InterconnectAttachmentStatePendingPartner
+			obj.OperationalStatus = InterconnectOperationalStatusUnprovisioned
+			return false, nil
+		}
+		if obj.Type == "" {
+			obj.Type = "DEDICATED"
+		}
+		id, _ := cloud.ParseResourceURL(obj.Interconnect)
+		projectID := m.ProjectRouter.ProjectID(ctx, "ga", "interconnectAttachments")
+		// Same as the SelfLink set by Insert().
+		selfLink := cloud.SelfLink(meta.VersionGA, projectID, "interconnectAttachments", key)
+		var status string
+		if err := updateInterconnect(ctx, mockGCE, id.Key, func(ic *ga.Interconnect) {
+			ic.InterconnectAttachments = append(ic.InterconnectAttachments, selfLink)
+			status = ic.OperationalStatus
+		}); err != nil {
+			return true, err
+		}
+		obj.State = InterconnectAttachmentStateActive
+		obj.OperationalStatus = status
+		return false, nil
+	}
+}
+
+// DeleteInterconnectAttachmentHook returns a hook that removes the
+// InterconnectAttachment from the InterconnectAttachments of its
+// Interconnect.
+func DeleteInterconnectAttachmentHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *cloud.MockInterconnectAttachments) (bool, error) {
+	return func(ctx context.Context, key *meta.Key, m *cloud.MockInterconnectAttachments) (bool, error) {
+		obj, err := m.Get(ctx, key)
+		if err != nil {
+			return true, err
+		}
+		if obj.Interconnect == "" {
+			return false, nil
+		}
+		id, err := cloud.ParseResourceURL(obj.Interconnect)
+		if err != nil {
+			return false, nil
+		}
+		// The Interconnect may be gone if it was deleted without the hooks.
+		if err := updateInterconnect(ctx, mockGCE, id.Key, func(ic *ga.Interconnect) {
+			var attachments []string
+			for _, a := range ic.InterconnectAttachments {
+				if a != obj.SelfLink {
+					attachments = append(attachments, a)
+				}
+			}
+			ic.InterconnectAttachments = attachments
+		}); err != nil {
+			var gerr *googleapi.Error
+			if !errors.As(err, &gerr) || gerr.Code != http.StatusNotFound {
+				return true, err
+			}
+		}
+		return false, nil
+	}
+}
+
+func invalidInterconnectAttachmentError(key *meta.Key, format string, args ...interface{}) error {
+	return &googleapi.Error{
+		Code:    http.StatusBadRequest,
+		Message: fmt.Sprintf("Invalid value for InterconnectAttachment %s: %s", key, fmt.Sprintf(format, args...)),
+	}
+}
+
+// validateInterconnectAttachment checks the references of the
+// InterconnectAttachment: the Router must exist in the same region and
+// DEDICATED attachments must reference an existing Interconnect.
+func validateInterconnectAttachment(ctx context.Context, mockGCE *cloud.MockGCE, key *meta.Key, obj *ga.InterconnectAttachment) error {
+	key = key.Normalize()
+	if key.Type() != meta.Regional {
+		return invalidInterconnectAttachmentError(key, "key must be regional")
+	}
+
+	id, err := cloud.ParseResourceURL(obj.Router)
+	if err != nil || id.Resource != "routers" {
+		return invalidInterconnectAttachmentError(key, "router %q is not a valid router", obj.Router)
+	}
+	if meta.NormalizeLocation(id.Key.Region) != key.Region {
+		return invalidInterconnectAttachmentError(key, "router %q is not in region %s", obj.Router, key.Region)
+	}
+	if _, err := mockGCE.Routers().Get(ctx, id.Key); err != nil {
+		return invalidInterconnectAttachmentError(key, "router %q does not exist", obj.Router)
+	}
+
+	switch obj.Type {
+	case "PARTNER":
+		if obj.Interconnect != "" {
+			return invalidInterconnectAttachmentError(key, "interconnect must not be set for PARTNER")
+		}
+	case "", "DEDICATED":
+		id, err := cloud.ParseResourceURL(obj.Interconnect)
+		if err != nil || id.Resource != "interconnects" {
+			return invalidInterconnectAttachmentError(key, "interconnect %q is not a valid interconnect", obj.Interconnect)
+		}
+		if _, err := mockGCE.Interconnects().Get(ctx, id.Key); err != nil {
+			return invalidInterconnectAttachmentError(key, "interconnect %q does not exist", obj.Interconnect)
+		}
+	default:
+		return invalidInterconnectAttachmentError(key, "unsupported type %q", obj.Type)
+	}
+	return nil
+}
+
+// updateInterconnect applies f to the Interconnect stored for key.
+func updateInterconnect(ctx context.Context, mockGCE *cloud.MockGCE, key *meta.Key, f func(*ga.Interconnect)) error {
+	m := mockGCE.MockInterconnects
+	if _, err := m.Get(ctx, key); err != nil {
+		return err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	obj := m.Objects[*key].ToGA()
+	f(obj)
+	m.Objects[*key] = m.Obj(obj)
+	return nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+func TestInterconnectHooks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mockGCE := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "mock-project"})
+	mockGCE.MockInterconnects.InsertHook = InsertInterconnectHook
+	mockGCE.MockInterconnects.DeleteHook = DeleteInterconnectHook
+	mockGCE.MockInterconnects.GetDiagnosticsHook = GetDiagnosticsInterconnectHook
+	mockGCE.MockInterconnectAttachments.InsertHook = InsertInterconnectAttachmentHook(mockGCE)
+	mockGCE.MockInterconnectAttachments.DeleteHook = DeleteInterconnectAttachmentHook(mockGCE)
+
+	icKey := meta.GlobalKey("ic")
+	routerKey := meta.RegionalKey("router", "us-central1")
+	ic := cloud.SelfLink(meta.VersionGA, "mock-project", "interconnects", icKey)
+	router := cloud.SelfLink(meta.VersionGA, "mock-project", "routers", routerKey)
+	otherRouter := cloud.SelfLink(meta.VersionGA, "mock-project", "routers", meta.RegionalKey("router", "us-east1"))
+	if err := mockGCE.Interconnects().Insert(ctx, icKey, &ga.Interconnect{
+		CircuitInfos: []*ga.InterconnectCircuitInfo{{GoogleCircuitId: "c1"}, {GoogleCircuitId: "c2"}},
+	}); err != nil {
+		t.Fatalf("Interconnects().Insert(%v) = %v, want nil", icKey, err)
+	}
+	if err := mockGCE.Routers().Insert(ctx, routerKey, &ga.Router{}); err != nil {
+		t.Fatalf("Routers().Insert(%v) = %v, want nil", routerKey, err)
+	}
+
+	diag, err := mockGCE.Interconnects().GetDiagnostics(ctx, icKey)
+	if err != nil {
+		t.Fatalf("GetDiagnostics(%v) = _, %v, want nil", icKey, err)
+	}
+	if len(diag.Result.Links) != 2 || diag.Result.Links[0].OperationalStatus != "LINK_OPERATIONAL_STATUS_UP" {
+		t.Errorf("GetDiagnostics(%v) = %+v, want 2 links that are up", icKey, diag.Result)
+	}
+
+	var gerr *googleapi.Error
+	for _, tc := range []struct {
+		desc        string
+		name        string
+		obj         *ga.InterconnectAttachment
+		wantCode    int
+		wantState   string
+		wantOpState string
+	}{
+		{
+			desc:     "missing router",
+			name:     "a1",
+			obj:      &ga.InterconnectAttachment{Interconnect: ic},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			desc:     "router in other region",
+			name:     "a1",
+			obj:      &ga.InterconnectAttachment{Interconnect: ic, Router: otherRouter},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			desc:     "missing interconnect",
+			name:     "a1",
+			obj:      &ga.InterconnectAttachment{Interconnect: cloud.SelfLink(meta.VersionGA, "mock-project", "interconnects", meta.GlobalKey("missing")), Router: router},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			desc:     "partner with interconnect",
+			name:     "a1",
+			obj:      &ga.InterconnectAttachment{Type: "PARTNER", Interconnect: ic, Router: router},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			desc:        "dedicated",
+			name:        "a1",
+			obj:         &ga.InterconnectAttachment{Interconnect: ic, Router: router},
+			wantState:   InterconnectAttachmentStateActive,
+			wantOpState: InterconnectOperationalStatusActive,
+		},
+		{
+			desc:     "duplicate",
+			name:     "a1",
+			obj:      &ga.InterconnectAttachment{Interconnect: ic, Router: router},
+			wantCode: http.StatusConflict,
+		},
+		{
+			desc:        "partner",
+			name:        "a2",
+			obj:         &ga.InterconnectAttachment{Type: "PARTNER", Router: router},
+			wantState:   InterconnectAttachmentStatePendingPartner,
+			wantOpState: InterconnectOperationalStatusUnprovisioned,
+		},
+	} {
+		key := meta.RegionalKey(tc.name, "us-central1")
+		err := mockGCE.InterconnectAttachments().Insert(ctx, key, tc.obj)
+		if tc.wantCode != 0 {
+			if !errors.As(err, &gerr) || gerr.Code != tc.wantCode {
+				t.Errorf("%s: Insert() = %v, want %d", tc.desc, err, tc.wantCode)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: Insert() = %v, want nil", tc.desc, err)
+		}
+		got, _ := mockGCE.InterconnectAttachments().Get(ctx, key)
+		if got.State != tc.wantState || got.OperationalStatus != tc.wantOpState {
+			t.Errorf("%s: State, OperationalStatus = %q, %q; want %q, %q", tc.desc, got.State, got.OperationalStatus, tc.wantState, tc.wantOpState)
+		}
+	}
+
+	a1 := meta.RegionalKey("a1", "us-central1")
+	got, _ := mockGCE.Interconnects().Get(ctx, icKey)
+	if want := cloud.SelfLink(meta.VersionGA, "mock-project", "interconnectAttachments", a1); len(got.InterconnectAttachments) != 1 || got.InterconnectAttachments[0] != want {
+		t.Errorf("InterconnectAttachments = %v, want [%s]", got.InterconnectAttachments, want)
+	}
+	if err := mockGCE.Interconnects().Delete(ctx, icKey); !errors.As(err, &gerr) || gerr.Code != http.StatusBadRequest {
+		t.Errorf("Interconnects().Delete(%v) with attachments = %v, want 400", icKey, err)
+	}
+	if err := mockGCE.InterconnectAttachments().Delete(ctx, a1); err != nil {
+		t.Fatalf("InterconnectAttachments().Delete(%v) = %v, want nil", a1, err)
+	}
+	if err := mockGCE.Interconnects().Delete(ctx, icKey); err != nil {
+		t.Errorf("Interconnects().Delete(%v) = %v, want nil", icKey, err)
+	}
+}
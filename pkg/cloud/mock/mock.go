@@ -123,6 +123,7 @@ func RemoveInstanceHook(ctx context.Context, key *meta.Key, req *ga.TargetPoolsR
 }
 
 func convertAndInsertAlphaForwardingRule(key *meta.Key, obj gceObject, mRules map[meta.Key]*cloud.MockForwardingRulesObj, version meta.Version, projectID string) (bool, error) {
+	key = key.Normalize()
 	if !key.Valid() {
 		return true, fmt.Errorf("invalid GCE key (%+v)", key)
 	}
@@ -191,6 +192,7 @@ type AddressAttributes struct {
 }
 
 func convertAndInsertAlphaAddress(key *meta.Key, obj gceObject, mAddrs map[meta.Key]*cloud.MockAddressesObj, version meta.Version, projectID string, addressAttrs AddressAttributes) (bool, error) {
+	key = key.Normalize()
 	if !key.Valid() {
 		return true, fmt.Errorf("invalid GCE key (%+v)", key)
 	}
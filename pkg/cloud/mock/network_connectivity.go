@@ -0,0 +1,105 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/googleapi"
+	networkconnectivity "google.golang.org/api/networkconnectivity/v1"
+)
+
+// NetworkConnectivityStateActive is the State of Hubs and Spokes once they
+// are created.
+const NetworkConnectivityStateActive = "ACTIVE"
+
+// The Network Connectivity Center hooks below check the references between
+// Hubs and Spokes, so they are constructed from the MockGCE:
+//
+//	ncc := mockGCE.MockNetworkConnectivity
+//	ncc.MockHubs.InsertHook = mock.InsertHubHook
+//	ncc.MockHubs.DeleteHook = mock.DeleteHubHook(mockGCE)
+//	ncc.MockSpokes.InsertHook = mock.InsertSpokeHook(mockGCE)
+
+// InsertHubHook sets the State of the Hub to ACTIVE.
+func InsertHubHook(ctx context.Context, key *meta.Key, obj *networkconnectivity.Hub, m *cloud.MockNetworkConnectivityOps[networkconnectivity.Hub]) (bool, error) {
+	obj.State = NetworkConnectivityStateActive
+	return false, nil
+}
+
+// DeleteHubHook returns a hook that fails with 400 if Spokes are still
+// attached to the Hub.
+func DeleteHubHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *cloud.MockNetworkConnectivityOps[networkconnectivity.Hub]) (bool, error) {
+	return func(ctx context.Context, key *meta.Key, m *cloud.MockNetworkConnectivityOps[networkconnectivity.Hub]) (bool, error) {
+		hub, err := m.Get(ctx, key)
+		if err != nil {
+			return true, err
+		}
+		spokes := mockGCE.MockNetworkConnectivity.MockSpokes
+
+		spokes.Lock.Lock()
+		defer spokes.Lock.Unlock()
+
+		for k, spoke := range spokes.Objects {
+			if hubName(spoke.Hub) == hubName(hub.Name) {
+				return true, &googleapi.Error{
+					Code:    http.StatusBadRequest,
+					Message: fmt.Sprintf("Hub %v is in use by Spoke %v", key, k),
+				}
+			}
+		}
+		return false, nil
+	}
+}
+
+// InsertSpokeHook returns a hook that checks that the Hub of the Spoke exists
+// and sets the State of the Spoke to ACTIVE.
+func InsertSpokeHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *networkconnectivity.Spoke, *cloud.MockNetworkConnectivityOps[networkconnectivity.Spoke]) (bool, error) {
+	return func(ctx context.Context, key *meta.Key, obj *networkconnectivity.Spoke, m *cloud.MockNetworkConnectivityOps[networkconnectivity.Spoke]) (bool, error) {
+		name := hubName(obj.Hub)
+		if name == "" {
+			return true, &googleapi.Error{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("Spoke %v: hub %q is not a valid hub", key, obj.Hub),
+			}
+		}
+		if _, err := mockGCE.NetworkConnectivity().Hubs().Get(ctx, meta.GlobalKey(name)); err != nil {
+			return true, &googleapi.Error{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("Spoke %v: hub %q does not exist", key, obj.Hub),
+			}
+		}
+		obj.State = NetworkConnectivityStateActive
+		return false, nil
+	}
+}
+
+// hubName returns the name of the Hub referenced by s, which is either a
+// relative resource name ("projects/p/locations/global/hubs/hub") or a full
+// URL. An empty string is returned if s does not reference a Hub.
+func hubName(s string) string {
+	i := strings.LastIndex(s, "/hubs/")
+	if i < 0 {
+		return ""
+	}
+	return s[i+len("/hubs/"):]
+}
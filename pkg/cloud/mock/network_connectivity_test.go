@@ -0,0 +1,73 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/googleapi"
+	networkconnectivity "google.golang.org/api/networkconnectivity/v1"
+)
+
+func TestNetworkConnectivityHooks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mockGCE := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "mock-project"})
+	ncc := mockGCE.MockNetworkConnectivity
+	ncc.MockHubs.InsertHook = InsertHubHook
+	ncc.MockHubs.DeleteHook = DeleteHubHook(mockGCE)
+	ncc.MockSpokes.InsertHook = InsertSpokeHook(mockGCE)
+
+	hk := meta.GlobalKey("hub")
+	sk := meta.RegionalKey("spoke", "us-central1")
+	hubURL := "https://networkconnectivity.googleapis.com/v1/projects/mock-project/locations/global/hubs/hub"
+
+	var gerr *googleapi.Error
+	if err := ncc.Spokes().Insert(ctx, sk, &networkconnectivity.Spoke{Hub: hubURL}); !errors.As(err, &gerr) || gerr.Code != http.StatusBadRequest {
+		t.Errorf("Spokes().Insert() with missing hub = %v, want 400", err)
+	}
+	if err := ncc.Spokes().Insert(ctx, sk, &networkconnectivity.Spoke{Hub: "hub"}); !errors.As(err, &gerr) || gerr.Code != http.StatusBadRequest {
+		t.Errorf("Spokes().Insert() with invalid hub = %v, want 400", err)
+	}
+	if err := ncc.Hubs().Insert(ctx, hk, &networkconnectivity.Hub{}); err != nil {
+		t.Fatalf("Hubs().Insert(%v) = %v, want nil", hk, err)
+	}
+	if err := ncc.Spokes().Insert(ctx, sk, &networkconnectivity.Spoke{Hub: hubURL}); err != nil {
+		t.Fatalf("Spokes().Insert(%v) = %v, want nil", sk, err)
+	}
+	hub, _ := ncc.Hubs().Get(ctx, hk)
+	spoke, _ := ncc.Spokes().Get(ctx, sk)
+	if hub.State != NetworkConnectivityStateActive || spoke.State != NetworkConnectivityStateActive {
+		t.Errorf("State = %q, %q; want %s", hub.State, spoke.State, NetworkConnectivityStateActive)
+	}
+
+	if err := ncc.Hubs().Delete(ctx, hk); !errors.As(err, &gerr) || gerr.Code != http.StatusBadRequest {
+		t.Errorf("Hubs().Delete(%v) with spokes = %v, want 400", hk, err)
+	}
+	if err := ncc.Spokes().Delete(ctx, sk); err != nil {
+		t.Fatalf("Spokes().Delete(%v) = %v, want nil", sk, err)
+	}
+	if err := ncc.Hubs().Delete(ctx, hk); err != nil {
+		t.Errorf("Hubs().Delete(%v) = %v, want nil", hk, err)
+	}
+}
@@ -0,0 +1,493 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// NetworkEndpointAttributes maps from NetworkEndpointGroup key to the
+// endpoints attached to the group. It is stored in the X field of the
+// NetworkEndpointGroup mocks and is created on first use by the hooks below.
+//
+// Endpoints are stored in the alpha representation, which is a superset of
+// the other versions.
+type NetworkEndpointAttributes struct {
+	Endpoints map[meta.Key]map[string]*alpha.NetworkEndpoint
+}
+
+func networkEndpointAttributes(x *interface{}) *NetworkEndpointAttributes {
+	if *x == nil {
+		*x = &NetworkEndpointAttributes{Endpoints: map[meta.Key]map[string]*alpha.NetworkEndpoint{}}
+	}
+	return (*x).(*NetworkEndpointAttributes)
+}
+
+func networkEndpointID(ep *alpha.NetworkEndpoint) string {
+	return fmt.Sprintf("%s/%s/%s/%d", ep.Instance, ep.IpAddress, ep.Fqdn, ep.Port)
+}
+
+// attach the endpoints (a slice of any version of NetworkEndpoint) to the
+// group. Attaching an endpoint that is already a member is a no-op. Returns
+// the new size of the group.
+func (a *NetworkEndpointAttributes) attach(key *meta.Key, endpoints interface{}) (int64, error) {
+	var eps []*alpha.NetworkEndpoint
+	if _, err := cloud.Convert(&eps, endpoints); err != nil {
+		return 0, err
+	}
+	members, ok := a.Endpoints[*key]
+	if !ok {
+		members = map[string]*alpha.NetworkEndpoint{}
+		a.Endpoints[*key] = members
+	}
+	for _, ep := range eps {
+		members[networkEndpointID(ep)] = ep
+	}
+	return int64(len(members)), nil
+}
+
+// detach the endpoints from the group. It is an error to detach an endpoint
+// that is not a member; in that case the group is unchanged.
+func (a *NetworkEndpointAttributes) detach(key *meta.Key, endpoints interface{}) (int64, error) {
+	var eps []*alpha.NetworkEndpoint
+	if _, err := cloud.Convert(&eps, endpoints); err != nil {
+		return 0, err
+	}
+	members := a.Endpoints[*key]
+	for _, ep := range eps {
+		if _, ok := members[networkEndpointID(ep)]; !ok {
+			return int64(len(members)), &googleapi.Error{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("%s is not a member of %s", networkEndpointID(ep), key.String()),
+			}
+		}
+	}
+	for _, ep := range eps {
+		delete(members, networkEndpointID(ep))
+	}
+	return int64(len(members)), nil
+}
+
+// list the endpoints in the group, converted to ret, which must be a pointer
+// to a slice of any version of NetworkEndpointWithHealthStatus.
+func (a *NetworkEndpointAttributes) list(key *meta.Key, ret interface{}) error {
+	var ids []string
+	for id := range a.Endpoints[*key] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var eps []*alpha.NetworkEndpointWithHealthStatus
+	for _, id := range ids {
+		eps = append(eps, &alpha.NetworkEndpointWithHealthStatus{NetworkEndpoint: a.Endpoints[*key][id]})
+	}
+	_, err := cloud.Convert(ret, eps)
+	return err
+}
+
+// setNetworkEndpointGroupSize updates the Size field of the stored object.
+func setNetworkEndpointGroupSize(obj interface{}, size int64) {
+	switch o := obj.(type) {
+	case *ga.NetworkEndpointGroup:
+		o.Size = size
+	case *beta.NetworkEndpointGroup:
+		o.Size = size
+	case *alpha.NetworkEndpointGroup:
+		o.Size = size
+	}
+}
+
+// AttachNetworkEndpointsHook mocks attaching endpoints to a zonal NetworkEndpointGroup.
+func AttachNetworkEndpointsHook(ctx context.Context, key *meta.Key, req *ga.NetworkEndpointGroupsAttachEndpointsRequest, m *cloud.MockNetworkEndpointGroups) error {
+	if _, err := m.Get(ctx, key); err != nil {
+		return err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	size, err := networkEndpointAttributes(&m.X).attach(key, req.NetworkEndpoints)
+	if err != nil {
+		return err
+	}
+	setNetworkEndpointGroupSize(m.Objects[*key].Obj, size)
+	return nil
+}
+
+// DetachNetworkEndpointsHook mocks detaching endpoints from a zonal NetworkEndpointGroup.
+func DetachNetworkEndpointsHook(ctx context.Context, key *meta.Key, req *ga.NetworkEndpointGroupsDetachEndpointsRequest, m *cloud.MockNetworkEndpointGroups) error {
+	if _, err := m.Get(ctx, key); err != nil {
+		return err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	size, err := networkEndpointAttributes(&m.X).detach(key, req.NetworkEndpoints)
+	if err != nil {
+		return err
+	}
+	setNetworkEndpointGroupSize(m.Objects[*key].Obj, size)
+	return nil
+}
+
+// ListNetworkEndpointsHook mocks listing the endpoints of a zonal NetworkEndpointGroup.
+func ListNetworkEndpointsHook(ctx context.Context, key *meta.Key, req *ga.NetworkEndpointGroupsListEndpointsRequest, fl *filter.F, m *cloud.MockNetworkEndpointGroups) ([]*ga.NetworkEndpointWithHealthStatus, error) {
+	if _, err := m.Get(ctx, key); err != nil {
+		return nil, err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	var ret []*ga.NetworkEndpointWithHealthStatus
+	if err := networkEndpointAttributes(&m.X).list(key, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// AttachBetaNetworkEndpointsHook mocks attaching endpoints to a beta zonal NetworkEndpointGroup.
+func AttachBetaNetworkEndpointsHook(ctx context.Context, key *meta.Key, req *beta.NetworkEndpointGroupsAttachEndpointsRequest, m *cloud.MockBetaNetworkEndpointGroups) error {
+	if _, err := m.Get(ctx, key); err != nil {
+		return err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	size, err := networkEndpointAttributes(&m.X).attach(key, req.NetworkEndpoints)
+	if err != nil {
+		return err
+	}
+	setNetworkEndpointGroupSize(m.Objects[*key].Obj, size)
+	return nil
+}
+
+// DetachBetaNetworkEndpointsHook mocks detaching endpoints from a beta zonal NetworkEndpointGroup.
+func DetachBetaNetworkEndpointsHook(ctx context.Context, key *meta.Key, req *beta.NetworkEndpointGroupsDetachEndpointsRequest, m *cloud.MockBetaNetworkEndpointGroups) error {
+	if _, err := m.Get(ctx, key); err != nil {
+		return err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	size, err := networkEndpointAttributes(&m.X).detach(key, req.NetworkEndpoints)
+	if err != nil {
+		return err
+	}
+	setNetworkEndpointGroupSize(m.Objects[*key].Obj, size)
+	return nil
+}
+
+// ListBetaNetworkEndpointsHook mocks listing the endpoints of a beta zonal NetworkEndpointGroup.
+func ListBetaNetworkEndpointsHook(ctx context.Context, key *meta.Key, req *beta.NetworkEndpointGroupsListEndpointsRequest, fl *filter.F, m *cloud.MockBetaNetworkEndpointGroups) ([]*beta.NetworkEndpointWithHealthStatus, error) {
+	if _, err := m.Get(ctx, key); err != nil {
+		return nil, err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	var ret []*beta.NetworkEndpointWithHealthStatus
+	if err := networkEndpointAttributes(&m.X).list(key, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// AttachAlphaNetworkEndpointsHook mocks attaching endpoints to an alpha zonal NetworkEndpointGroup.
+func AttachAlphaNetworkEndpointsHook(ctx context.Context, key *meta.Key, req *alpha.NetworkEndpointGroupsAttachEndpointsRequest, m *cloud.MockAlphaNetworkEndpointGroups) error {
+	if _, err := m.Get(ctx, key); err != nil {
+		return err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	size, err := networkEndpointAttributes(&m.X).attach(key, req.NetworkEndpoints)
+	if err != nil {
+		return err
+	}
+	setNetworkEndpointGroupSize(m.Objects[*key].Obj, size)
+	return nil
+}
+
+// DetachAlphaNetworkEndpointsHook mocks detaching endpoints from an alpha zonal NetworkEndpointGroup.
+func DetachAlphaNetworkEndpointsHook(ctx context.Context, key *meta.Key, req *alpha.NetworkEndpointGroupsDetachEndpointsRequest, m *cloud.MockAlphaNetworkEndpointGroups) error {
+	if _, err := m.Get(ctx, key); err != nil {
+		return err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	size, err := networkEndpointAttributes(&m.X).detach(key, req.NetworkEndpoints)
+	if err != nil {
+		return err
+	}
+	setNetworkEndpointGroupSize(m.Objects[*key].Obj, size)
+	return nil
+}
+
+// ListAlphaNetworkEndpointsHook mocks listing the endpoints of an alpha zonal NetworkEndpointGroup.
+func ListAlphaNetworkEndpointsHook(ctx context.Context, key *meta.Key, req *alpha.NetworkEndpointGroupsListEndpointsRequest, fl *filter.F, m *cloud.MockAlphaNetworkEndpointGroups) ([]*alpha.NetworkEndpointWithHealthStatus, error) {
+	if _, err := m.Get(ctx, key); err != nil {
+		return nil, err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	var ret []*alpha.NetworkEndpointWithHealthStatus
+	if err := networkEndpointAttributes(&m.X).list(key, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// AttachGlobalNetworkEndpointsHook mocks attaching endpoints to a global NetworkEndpointGroup.
+func AttachGlobalNetworkEndpointsHook(ctx context.Context, key *meta.Key, req *ga.GlobalNetworkEndpointGroupsAttachEndpointsRequest, m *cloud.MockGlobalNetworkEndpointGroups) error {
+	if _, err := m.Get(ctx, key); err != nil {
+		return err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	size, err := networkEndpointAttributes(&m.X).attach(key, req.NetworkEndpoints)
+	if err != nil {
+		return err
+	}
+	setNetworkEndpointGroupSize(m.Objects[*key].Obj, size)
+	return nil
+}
+
+// DetachGlobalNetworkEndpointsHook mocks detaching endpoints from a global NetworkEndpointGroup.
+func DetachGlobalNetworkEndpointsHook(ctx context.Context, key *meta.Key, req *ga.GlobalNetworkEndpointGroupsDetachEndpointsRequest, m *cloud.MockGlobalNetworkEndpointGroups) error {
+	if _, err := m.Get(ctx, key); err != nil {
+		return err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	size, err := networkEndpointAttributes(&m.X).detach(key, req.NetworkEndpoints)
+	if err != nil {
+		return err
+	}
+	setNetworkEndpointGroupSize(m.Objects[*key].Obj, size)
+	return nil
+}
+
+// ListGlobalNetworkEndpointsHook mocks listing the endpoints of a global NetworkEndpointGroup.
+func ListGlobalNetworkEndpointsHook(ctx context.Context, key *meta.Key, fl *filter.F, m *cloud.MockGlobalNetworkEndpointGroups) ([]*ga.NetworkEndpointWithHealthStatus, error) {
+	if _, err := m.Get(ctx, key); err != nil {
+		return nil, err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	var ret []*ga.NetworkEndpointWithHealthStatus
+	if err := networkEndpointAttributes(&m.X).list(key, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// AttachBetaGlobalNetworkEndpointsHook mocks attaching endpoints to a beta global NetworkEndpointGroup.
+func AttachBetaGlobalNetworkEndpointsHook(ctx context.Context, key *meta.Key, req *beta.GlobalNetworkEndpointGroupsAttachEndpointsRequest, m *cloud.MockBetaGlobalNetworkEndpointGroups) error {
+	if _, err := m.Get(ctx, key); err != nil {
+		return err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	size, err := networkEndpointAttributes(&m.X).attach(key, req.NetworkEndpoints)
+	if err != nil {
+		return err
+	}
+	setNetworkEndpointGroupSize(m.Objects[*key].Obj, size)
+	return nil
+}
+
+// DetachBetaGlobalNetworkEndpointsHook mocks detaching endpoints from a beta global NetworkEndpointGroup.
+func DetachBetaGlobalNetworkEndpointsHook(ctx context.Context, key *meta.Key, req *beta.GlobalNetworkEndpointGroupsDetachEndpointsRequest, m *cloud.MockBetaGlobalNetworkEndpointGroups) error {
+	if _, err := m.Get(ctx, key); err != nil {
+		return err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	size, err := networkEndpointAttributes(&m.X).detach(key, req.NetworkEndpoints)
+	if err != nil {
+		return err
+	}
+	setNetworkEndpointGroupSize(m.Objects[*key].Obj, size)
+	return nil
+}
+
+// ListBetaGlobalNetworkEndpointsHook mocks listing the endpoints of a beta global NetworkEndpointGroup.
+func ListBetaGlobalNetworkEndpointsHook(ctx context.Context, key *meta.Key, fl *filter.F, m *cloud.MockBetaGlobalNetworkEndpointGroups) ([]*beta.NetworkEndpointWithHealthStatus, error) {
+	if _, err := m.Get(ctx, key); err != nil {
+		return nil, err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	var ret []*beta.NetworkEndpointWithHealthStatus
+	if err := networkEndpointAttributes(&m.X).list(key, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// AttachAlphaGlobalNetworkEndpointsHook mocks attaching endpoints to an alpha global NetworkEndpointGroup.
+func AttachAlphaGlobalNetworkEndpointsHook(ctx context.Context, key *meta.Key, req *alpha.GlobalNetworkEndpointGroupsAttachEndpointsRequest, m *cloud.MockAlphaGlobalNetworkEndpointGroups) error {
+	if _, err := m.Get(ctx, key); err != nil {
+		return err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	size, err := networkEndpointAttributes(&m.X).attach(key, req.NetworkEndpoints)
+	if err != nil {
+		return err
+	}
+	setNetworkEndpointGroupSize(m.Objects[*key].Obj, size)
+	return nil
+}
+
+// DetachAlphaGlobalNetworkEndpointsHook mocks detaching endpoints from an alpha global NetworkEndpointGroup.
+func DetachAlphaGlobalNetworkEndpointsHook(ctx context.Context, key *meta.Key, req *alpha.GlobalNetworkEndpointGroupsDetachEndpointsRequest, m *cloud.MockAlphaGlobalNetworkEndpointGroups) error {
+	if _, err := m.Get(ctx, key); err != nil {
+		return err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	size, err := networkEndpointAttributes(&m.X).detach(key, req.NetworkEndpoints)
+	if err != nil {
+		return err
+	}
+	setNetworkEndpointGroupSize(m.Objects[*key].Obj, size)
+	return nil
+}
+
+// ListAlphaGlobalNetworkEndpointsHook mocks listing the endpoints of an alpha global NetworkEndpointGroup.
+func ListAlphaGlobalNetworkEndpointsHook(ctx context.Context, key *meta.Key, fl *filter.F, m *cloud.MockAlphaGlobalNetworkEndpointGroups) ([]*alpha.NetworkEndpointWithHealthStatus, error) {
+	if _, err := m.Get(ctx, key); err != nil {
+		return nil, err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	var ret []*alpha.NetworkEndpointWithHealthStatus
+	if err := networkEndpointAttributes(&m.X).list(key, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// AttachAlphaRegionNetworkEndpointsHook mocks attaching endpoints to an alpha regional NetworkEndpointGroup.
+func AttachAlphaRegionNetworkEndpointsHook(ctx context.Context, key *meta.Key, req *alpha.RegionNetworkEndpointGroupsAttachEndpointsRequest, m *cloud.MockAlphaRegionNetworkEndpointGroups) error {
+	if _, err := m.Get(ctx, key); err != nil {
+		return err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	size, err := networkEndpointAttributes(&m.X).attach(key, req.NetworkEndpoints)
+	if err != nil {
+		return err
+	}
+	setNetworkEndpointGroupSize(m.Objects[*key].Obj, size)
+	return nil
+}
+
+// DetachAlphaRegionNetworkEndpointsHook mocks detaching endpoints from an alpha regional NetworkEndpointGroup.
+func DetachAlphaRegionNetworkEndpointsHook(ctx context.Context, key *meta.Key, req *alpha.RegionNetworkEndpointGroupsDetachEndpointsRequest, m *cloud.MockAlphaRegionNetworkEndpointGroups) error {
+	if _, err := m.Get(ctx, key); err != nil {
+		return err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	size, err := networkEndpointAttributes(&m.X).detach(key, req.NetworkEndpoints)
+	if err != nil {
+		return err
+	}
+	setNetworkEndpointGroupSize(m.Objects[*key].Obj, size)
+	return nil
+}
+
+// ListAlphaRegionNetworkEndpointsHook mocks listing the endpoints of an alpha regional NetworkEndpointGroup.
+func ListAlphaRegionNetworkEndpointsHook(ctx context.Context, key *meta.Key, fl *filter.F, m *cloud.MockAlphaRegionNetworkEndpointGroups) ([]*alpha.NetworkEndpointWithHealthStatus, error) {
+	if _, err := m.Get(ctx, key); err != nil {
+		return nil, err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	var ret []*alpha.NetworkEndpointWithHealthStatus
+	if err := networkEndpointAttributes(&m.X).list(key, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"testing"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestNetworkEndpointsHooks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "mock-project"})
+	mock.MockNetworkEndpointGroups.AttachNetworkEndpointsHook = AttachNetworkEndpointsHook
+	mock.MockNetworkEndpointGroups.DetachNetworkEndpointsHook = DetachNetworkEndpointsHook
+	mock.MockNetworkEndpointGroups.ListNetworkEndpointsHook = ListNetworkEndpointsHook
+
+	negs := mock.NetworkEndpointGroups()
+	key := meta.ZonalKey("neg", "us-central1-a")
+	ep1 := &ga.NetworkEndpoint{Instance: "vm-1", IpAddress: "10.0.0.1", Port: 80}
+	ep2 := &ga.NetworkEndpoint{Instance: "vm-2", IpAddress: "10.0.0.2", Port: 80}
+
+	attach := &ga.NetworkEndpointGroupsAttachEndpointsRequest{NetworkEndpoints: []*ga.NetworkEndpoint{ep1, ep2}}
+	if err := negs.AttachNetworkEndpoints(ctx, key, attach); err == nil {
+		t.Errorf("AttachNetworkEndpoints() = nil, want error for missing NEG")
+	}
+	if err := negs.Insert(ctx, key, &ga.NetworkEndpointGroup{}); err != nil {
+		t.Fatalf("Insert() = %v, want nil", err)
+	}
+	if err := negs.AttachNetworkEndpoints(ctx, key, attach); err != nil {
+		t.Fatalf("AttachNetworkEndpoints() = %v, want nil", err)
+	}
+	// Attaching the same endpoint again is a no-op.
+	if err := negs.AttachNetworkEndpoints(ctx, key, attach); err != nil {
+		t.Fatalf("AttachNetworkEndpoints() = %v, want nil", err)
+	}
+
+	checkEndpoints := func(want ...string) {
+		t.Helper()
+		got, err := negs.ListNetworkEndpoints(ctx, key, &ga.NetworkEndpointGroupsListEndpointsRequest{}, filter.None)
+		if err != nil {
+			t.Fatalf("ListNetworkEndpoints() = _, %v, want nil", err)
+		}
+		var gotInstances []string
+		for _, ep := range got {
+			gotInstances = append(gotInstances, ep.NetworkEndpoint.Instance)
+		}
+		if len(gotInstances) != len(want) {
+			t.Fatalf("ListNetworkEndpoints() = %v, want %v", gotInstances, want)
+		}
+		for i := range want {
+			if gotInstances[i] != want[i] {
+				t.Fatalf("ListNetworkEndpoints() = %v, want %v", gotInstances, want)
+			}
+		}
+		neg, err := negs.Get(ctx, key)
+		if err != nil || neg.Size != int64(len(want)) {
+			t.Errorf("Get() = %+v, %v; want Size = %d", neg, err, len(want))
+		}
+	}
+	checkEndpoints("vm-1", "vm-2")
+
+	detach := &ga.NetworkEndpointGroupsDetachEndpointsRequest{NetworkEndpoints: []*ga.NetworkEndpoint{ep1}}
+	if err := negs.DetachNetworkEndpoints(ctx, key, detach); err != nil {
+		t.Fatalf("DetachNetworkEndpoints() = %v, want nil", err)
+	}
+	checkEndpoints("vm-2")
+
+	if err := negs.DetachNetworkEndpoints(ctx, key, detach); err == nil {
+		t.Errorf("DetachNetworkEndpoints() = nil, want error for non-member")
+	}
+	checkEndpoints("vm-2")
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// Status of a PublicAdvertisedPrefix after announce/withdraw.
+const (
+	PublicAdvertisedPrefixStatusAnnounced       = "ANNOUNCED_TO_INTERNET"
+	PublicAdvertisedPrefixStatusReadyToAnnounce = "READY_TO_ANNOUNCE"
+)
+
+// Status of a PublicDelegatedPrefix after announce/withdraw.
+const (
+	PublicDelegatedPrefixStatusAnnounced       = "ANNOUNCED_TO_INTERNET"
+	PublicDelegatedPrefixStatusReadyToAnnounce = "READY_TO_ANNOUNCE"
+)
+
+// AnnounceAlphaPublicAdvertisedPrefixHook sets the Status of the prefix to
+// ANNOUNCED_TO_INTERNET.
+func AnnounceAlphaPublicAdvertisedPrefixHook(ctx context.Context, key *meta.Key, m *cloud.MockAlphaPublicAdvertisedPrefixes) error {
+	return setPublicAdvertisedPrefixStatus(ctx, key, m, PublicAdvertisedPrefixStatusAnnounced)
+}
+
+// WithdrawAlphaPublicAdvertisedPrefixHook sets the Status of the prefix to
+// READY_TO_ANNOUNCE.
+func WithdrawAlphaPublicAdvertisedPrefixHook(ctx context.Context, key *meta.Key, m *cloud.MockAlphaPublicAdvertisedPrefixes) error {
+	return setPublicAdvertisedPrefixStatus(ctx, key, m, PublicAdvertisedPrefixStatusReadyToAnnounce)
+}
+
+// AnnounceAlphaPublicDelegatedPrefixHook sets the Status of the prefix to
+// ANNOUNCED_TO_INTERNET.
+func AnnounceAlphaPublicDelegatedPrefixHook(ctx context.Context, key *meta.Key, m *cloud.MockAlphaPublicDelegatedPrefixes) error {
+	return setPublicDelegatedPrefixStatus(ctx, key, m, PublicDelegatedPrefixStatusAnnounced)
+}
+
+// WithdrawAlphaPublicDelegatedPrefixHook sets the Status of the prefix to
+// READY_TO_ANNOUNCE.
+func WithdrawAlphaPublicDelegatedPrefixHook(ctx context.Context, key *meta.Key, m *cloud.MockAlphaPublicDelegatedPrefixes) error {
+	return setPublicDelegatedPrefixStatus(ctx, key, m, PublicDelegatedPrefixStatusReadyToAnnounce)
+}
+
+func setPublicAdvertisedPrefixStatus(ctx context.Context, key *meta.Key, m *cloud.MockAlphaPublicAdvertisedPrefixes, status string) error {
+	if _, err := m.Get(ctx, key); err != nil {
+		return err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	obj := m.Objects[*key].ToAlpha()
+	obj.Status = status
+	m.Objects[*key] = m.Obj(obj)
+	return nil
+}
+
+func setPublicDelegatedPrefixStatus(ctx context.Context, key *meta.Key, m *cloud.MockAlphaPublicDelegatedPrefixes, status string) error {
+	if _, err := m.Get(ctx, key); err != nil {
+		return err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	obj := m.Objects[*key].ToAlpha()
+	obj.Status = status
+	m.Objects[*key] = m.Obj(obj)
+	return nil
+}
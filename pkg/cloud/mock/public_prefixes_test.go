@@ -0,0 +1,93 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"testing"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+)
+
+func TestPublicPrefixHooks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mockGCE := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "mock-project"})
+	mockGCE.MockAlphaPublicAdvertisedPrefixes.AnnounceHook = AnnounceAlphaPublicAdvertisedPrefixHook
+	mockGCE.MockAlphaPublicAdvertisedPrefixes.WithdrawHook = WithdrawAlphaPublicAdvertisedPrefixHook
+	mockGCE.MockAlphaPublicDelegatedPrefixes.AnnounceHook = AnnounceAlphaPublicDelegatedPrefixHook
+	mockGCE.MockAlphaPublicDelegatedPrefixes.WithdrawHook = WithdrawAlphaPublicDelegatedPrefixHook
+
+	papKey := meta.GlobalKey("pap")
+	pdpKey := meta.RegionalKey("pdp", "us-central1")
+
+	if err := mockGCE.AlphaPublicAdvertisedPrefixes().Announce(ctx, papKey); err == nil {
+		t.Errorf("Announce(%v) = nil, want error for missing prefix", papKey)
+	}
+	if err := mockGCE.AlphaPublicAdvertisedPrefixes().Insert(ctx, papKey, &alpha.PublicAdvertisedPrefix{IpCidrRange: "203.0.113.0/24"}); err != nil {
+		t.Fatalf("Insert(%v) = %v, want nil", papKey, err)
+	}
+	if err := mockGCE.AlphaPublicDelegatedPrefixes().Insert(ctx, pdpKey, &alpha.PublicDelegatedPrefix{IpCidrRange: "203.0.113.0/28"}); err != nil {
+		t.Fatalf("Insert(%v) = %v, want nil", pdpKey, err)
+	}
+
+	for _, tc := range []struct {
+		desc    string
+		op      func() error
+		wantPAP string
+		wantPDP string
+	}{
+		{
+			desc:    "announce PAP",
+			op:      func() error { return mockGCE.AlphaPublicAdvertisedPrefixes().Announce(ctx, papKey) },
+			wantPAP: PublicAdvertisedPrefixStatusAnnounced,
+		},
+		{
+			desc:    "announce PDP",
+			op:      func() error { return mockGCE.AlphaPublicDelegatedPrefixes().Announce(ctx, pdpKey) },
+			wantPAP: PublicAdvertisedPrefixStatusAnnounced,
+			wantPDP: PublicDelegatedPrefixStatusAnnounced,
+		},
+		{
+			desc:    "withdraw PDP",
+			op:      func() error { return mockGCE.AlphaPublicDelegatedPrefixes().Withdraw(ctx, pdpKey) },
+			wantPAP: PublicAdvertisedPrefixStatusAnnounced,
+			wantPDP: PublicDelegatedPrefixStatusReadyToAnnounce,
+		},
+		{
+			desc:    "withdraw PAP",
+			op:      func() error { return mockGCE.AlphaPublicAdvertisedPrefixes().Withdraw(ctx, papKey) },
+			wantPAP: PublicAdvertisedPrefixStatusReadyToAnnounce,
+			wantPDP: PublicDelegatedPrefixStatusReadyToAnnounce,
+		},
+	} {
+		if err := tc.op(); err != nil {
+			t.Fatalf("%s: got %v, want nil", tc.desc, err)
+		}
+		pap, err := mockGCE.AlphaPublicAdvertisedPrefixes().Get(ctx, papKey)
+		if err != nil || pap.Status != tc.wantPAP {
+			t.Errorf("%s: PublicAdvertisedPrefix = %+v, %v; want Status %q", tc.desc, pap, err, tc.wantPAP)
+		}
+		pdp, err := mockGCE.AlphaPublicDelegatedPrefixes().Get(ctx, pdpKey)
+		if err != nil || pdp.Status != tc.wantPDP {
+			t.Errorf("%s: PublicDelegatedPrefix = %+v, %v; want Status %q", tc.desc, pdp, err, tc.wantPDP)
+		}
+	}
+}
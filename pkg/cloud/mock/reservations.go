@@ -0,0 +1,124 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// Procurement status of a FutureReservation.
+const (
+	FutureReservationStatusCancelled = "CANCELLED"
+	FutureReservationStatusFulfilled = "FULFILLED"
+)
+
+// ResizeReservationHook sets the count of the specific SKU reservation. The
+// count cannot be lower than the number of Instances using the Reservation.
+func ResizeReservationHook(ctx context.Context, key *meta.Key, req *ga.ReservationsResizeRequest, m *cloud.MockReservations) error {
+	return resizeReservation(key, req.SpecificSkuCount, &m.Lock, m.Objects)
+}
+
+// ResizeAlphaReservationHook sets the count of the specific SKU reservation.
+// The count cannot be lower than the number of Instances using the
+// Reservation.
+func ResizeAlphaReservationHook(ctx context.Context, key *meta.Key, req *alpha.ReservationsResizeRequest, m *cloud.MockAlphaReservations) error {
+	return resizeReservation(key, req.SpecificSkuCount, &m.Lock, m.Objects)
+}
+
+// ResizeBetaReservationHook sets the count of the specific SKU reservation.
+// The count cannot be lower than the number of Instances using the
+// Reservation.
+func ResizeBetaReservationHook(ctx context.Context, key *meta.Key, req *beta.ReservationsResizeRequest, m *cloud.MockBetaReservations) error {
+	return resizeReservation(key, req.SpecificSkuCount, &m.Lock, m.Objects)
+}
+
+// CancelAlphaFutureReservationHook sets the procurement status of the
+// FutureReservation to CANCELLED. FutureReservations that are already
+// fulfilled or cancelled cannot be cancelled.
+func CancelAlphaFutureReservationHook(ctx context.Context, key *meta.Key, m *cloud.MockAlphaFutureReservations) error {
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	obj, ok := m.Objects[*key]
+	if !ok {
+		return &googleapi.Error{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("FutureReservation %v not found", key),
+		}
+	}
+	fr := cloud.DeepCopyAlphaFutureReservation(obj.ToAlpha())
+	if fr.Status == nil {
+		fr.Status = &alpha.FutureReservationStatus{}
+	}
+	switch fr.Status.ProcurementStatus {
+	case FutureReservationStatusCancelled, FutureReservationStatusFulfilled:
+		return &googleapi.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("FutureReservation %v is %s", key, fr.Status.ProcurementStatus),
+		}
+	}
+	fr.Status.ProcurementStatus = FutureReservationStatusCancelled
+	m.Objects[*key] = m.Obj(fr)
+	return nil
+}
+
+// resizeReservation sets the count of the Reservation with the given key.
+// Reservations of all versions share objects, the lock must be the lock of
+// the mock that owns the objects.
+func resizeReservation(key *meta.Key, count int64, lock *sync.Mutex, objects map[meta.Key]*cloud.MockReservationsObj) error {
+	key = key.Normalize()
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	obj, ok := objects[*key]
+	if !ok {
+		return &googleapi.Error{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("Reservation %v not found", key),
+		}
+	}
+	// Alpha has all of the fields of the other versions.
+	r := cloud.DeepCopyAlphaReservation(obj.ToAlpha())
+	if r.SpecificReservation == nil {
+		return &googleapi.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Reservation %v does not have a specific SKU reservation", key),
+		}
+	}
+	if count < r.SpecificReservation.InUseCount {
+		return &googleapi.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("Reservation %v: count %d is lower than the in use count %d", key, count, r.SpecificReservation.InUseCount),
+		}
+	}
+	r.SpecificReservation.Count = count
+	objects[*key] = &cloud.MockReservationsObj{Obj: r}
+	return nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+func TestReservationHooks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mockGCE := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "mock-project"})
+	mockGCE.MockReservations.ResizeHook = ResizeReservationHook
+	mockGCE.MockBetaReservations.ResizeHook = ResizeBetaReservationHook
+
+	key := meta.ZonalKey("r", "us-central1-b")
+	noSKUKey := meta.ZonalKey("no-sku", "us-central1-c")
+	if err := mockGCE.Reservations().Insert(ctx, key, &ga.Reservation{
+		SpecificReservation: &ga.AllocationSpecificSKUReservation{Count: 4, InUseCount: 2},
+	}); err != nil {
+		t.Fatalf("Insert(%v) = %v, want nil", key, err)
+	}
+	if err := mockGCE.Reservations().Insert(ctx, noSKUKey, &ga.Reservation{}); err != nil {
+		t.Fatalf("Insert(%v) = %v, want nil", noSKUKey, err)
+	}
+
+	var gerr *googleapi.Error
+	for _, tc := range []struct {
+		desc      string
+		op        func() error
+		wantCode  int
+		wantCount int64
+	}{
+		{
+			desc: "grow",
+			op: func() error {
+				return mockGCE.Reservations().Resize(ctx, key, &ga.ReservationsResizeRequest{SpecificSkuCount: 6})
+			},
+			wantCount: 6,
+		},
+		{
+			desc: "beta shrink",
+			op: func() error {
+				return mockGCE.BetaReservations().Resize(ctx, key, &beta.ReservationsResizeRequest{SpecificSkuCount: 2})
+			},
+			wantCount: 2,
+		},
+		{
+			desc: "below in use",
+			op: func() error {
+				return mockGCE.Reservations().Resize(ctx, key, &ga.ReservationsResizeRequest{SpecificSkuCount: 1})
+			},
+			wantCode:  http.StatusBadRequest,
+			wantCount: 2,
+		},
+		{
+			desc: "missing",
+			op: func() error {
+				return mockGCE.Reservations().Resize(ctx, meta.ZonalKey("missing", "us-central1-b"), &ga.ReservationsResizeRequest{SpecificSkuCount: 1})
+			},
+			wantCode:  http.StatusNotFound,
+			wantCount: 2,
+		},
+		{
+			desc: "no specific SKU",
+			op: func() error {
+				return mockGCE.Reservations().Resize(ctx, noSKUKey, &ga.ReservationsResizeRequest{SpecificSkuCount: 1})
+			},
+			wantCode:  http.StatusBadRequest,
+			wantCount: 2,
+		},
+	} {
+		err := tc.op()
+		if tc.wantCode == 0 && err != nil {
+			t.Errorf("%s: err = %v, want nil", tc.desc, err)
+		}
+		if tc.wantCode != 0 && (!errors.As(err, &gerr) || gerr.Code != tc.wantCode) {
+			t.Errorf("%s: err = %v, want %d", tc.desc, err, tc.wantCode)
+		}
+		r, err := mockGCE.Reservations().Get(ctx, key)
+		if err != nil {
+			t.Fatalf("%s: Get(%v) = _, %v, want nil", tc.desc, key, err)
+		}
+		if r.SpecificReservation.Count != tc.wantCount {
+			t.Errorf("%s: Count = %d, want %d", tc.desc, r.SpecificReservation.Count, tc.wantCount)
+		}
+	}
+
+	all, err := mockGCE.Reservations().AggregatedList(ctx, filter.None)
+	if err != nil {
+		t.Fatalf("AggregatedList() = _, %v, want nil", err)
+	}
+	if len(all["zones/us-central1-b"]) != 1 || len(all["zones/us-central1-c"]) != 1 {
+		t.Errorf("AggregatedList() = %v, want one Reservation in each zone", all)
+	}
+}
+
+func TestCancelAlphaFutureReservationHook(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mockGCE := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "mock-project"})
+	mockGCE.MockAlphaFutureReservations.CancelHook = CancelAlphaFutureReservationHook
+
+	key := meta.ZonalKey("fr", "us-central1-b")
+	var gerr *googleapi.Error
+	if err := mockGCE.AlphaFutureReservations().Cancel(ctx, key); !errors.As(err, &gerr) || gerr.Code != http.StatusNotFound {
+		t.Errorf("Cancel(%v) = %v, want 404", key, err)
+	}
+	if err := mockGCE.AlphaFutureReservations().Insert(ctx, key, &alpha.FutureReservation{}); err != nil {
+		t.Fatalf("Insert(%v) = %v, want nil", key, err)
+	}
+	if err := mockGCE.AlphaFutureReservations().Cancel(ctx, key); err != nil {
+		t.Fatalf("Cancel(%v) = %v, want nil", key, err)
+	}
+	fr, _ := mockGCE.AlphaFutureReservations().Get(ctx, key)
+	if fr.Status == nil || fr.Status.ProcurementStatus != FutureReservationStatusCancelled {
+		t.Errorf("Status = %+v, want ProcurementStatus %s", fr.Status, FutureReservationStatusCancelled)
+	}
+	if err := mockGCE.AlphaFutureReservations().Cancel(ctx, key); !errors.As(err, &gerr) || gerr.Code != http.StatusBadRequest {
+		t.Errorf("Cancel(%v) twice = %v, want 400", key, err)
+	}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// The Router hooks below need access to the other resources in the mock
+// (Networks, Subnetworks and Addresses) to validate references, so they are
+// constructed from the MockGCE:
+//
+//	mockGCE.MockRouters.InsertHook = mock.InsertRouterHook(mockGCE)
+//	mockGCE.MockRouters.PatchHook = mock.PatchRouterHook(mockGCE)
+//	mockGCE.MockRouters.UpdateHook = mock.UpdateRouterHook(mockGCE)
+//	mockGCE.MockRouters.GetRouterStatusHook = mock.GetRouterStatusHook(mockGCE)
+
+// InsertRouterHook returns a hook that validates the network and NAT
+// references of the Router before inserting it.
+func InsertRouterHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *ga.Router, *cloud.MockRouters) (bool, error) {
+	return func(ctx context.Context, key *meta.Key, obj *ga.Router, m *cloud.MockRouters) (bool, error) {
+		if err := validateRouter(ctx, mockGCE, key, obj); err != nil {
+			return true, err
+		}
+		return false, nil
+	}
+}
+
+// PatchRouterHook returns a hook that patches the Router and validates the
+// result. The Router is not changed if validation fails.
+func PatchRouterHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *ga.Router, *cloud.MockRouters) error {
+	return func(ctx context.Context, key *meta.Key, obj *ga.Router, m *cloud.MockRouters) error {
+		cur, err := m.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		// Patch a copy so the stored object is unchanged if validation fails.
+		patched := cloud.DeepCopyRouter(cur)
+		if err := cloud.MockPatch(patched, obj); err != nil {
+			return err
+		}
+		if err := validateRouter(ctx, mockGCE, key, patched); err != nil {
+			return err
+		}
+
+		m.Lock.Lock()
+		defer m.Lock.Unlock()
+		m.Objects[*key.Normalize()] = m.Obj(patched)
+		return nil
+	}
+}
+
+// UpdateRouterHook returns a hook that validates the Router and replaces the
+// object with the same key in the mock.
+func UpdateRouterHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *ga.Router, *cloud.MockRouters) error {
+	return func(ctx context.Context, key *meta.Key, obj *ga.Router, m *cloud.MockRouters) error {
+		cur, err := m.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if err := validateRouter(ctx, mockGCE, key, obj); err != nil {
+			return err
+		}
+		obj.Name = cur.Name
+		obj.SelfLink = cur.SelfLink
+
+		m.Lock.Lock()
+		defer m.Lock.Unlock()
+		m.Objects[*key.Normalize()] = m.Obj(obj)
+		return nil
+	}
+}
+
+// GetRouterStatusHook returns a hook that reports the status of the NATs
+// configured on the Router. User allocated NAT IPs are resolved using the
+// Addresses in the mock.
+func GetRouterStatusHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *cloud.MockRouters) (*ga.RouterStatusResponse, error) {
+	return func(ctx context.Context, key *meta.Key, m *cloud.MockRouters) (*ga.RouterStatusResponse, error) {
+		router, err := m.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		status := &ga.RouterStatus{Network: router.Network}
+		for _, nat := range router.Nats {
+			natStatus := &ga.RouterStatusNatStatus{Name: nat.Name}
+			for _, natIP := range nat.NatIps {
+				id, err := cloud.ParseResourceURL(natIP)
+				if err != nil {
+					continue
+				}
+				addr, err := mockGCE.Addresses().Get(ctx, id.Key)
+				if err != nil {
+					continue
+				}
+				natStatus.UserAllocatedNatIps = append(natStatus.UserAllocatedNatIps, addr.Address)
+				natStatus.UserAllocatedNatIpResources = append(natStatus.UserAllocatedNatIpResources, natIP)
+			}
+			status.NatStatus = append(status.NatStatus, natStatus)
+		}
+		return &ga.RouterStatusResponse{Kind: "compute#routerStatusResponse", Result: status}, nil
+	}
+}
+
+func invalidRouterError(key *meta.Key, format string, args ...interface{}) error {
+	return &googleapi.Error{
+		Code:    http.StatusBadRequest,
+		Message: fmt.Sprintf("Invalid value for Router %s: %s", key, fmt.Sprintf(format, args...)),
+	}
+}
+
+// validateRouter checks the references of the Router: the network must
+// exist, NAT subnetworks must exist in the same region and network and
+// manually allocated NAT IPs must be Addresses in the same region.
+func validateRouter(ctx context.Context, mockGCE *cloud.MockGCE, key *meta.Key, r *ga.Router) error {
+	key = key.Normalize()
+	if key.Type() != meta.Regional {
+		return invalidRouterError(key, "key must be regional")
+	}
+
+	var networkID *cloud.ResourceID
+	if r.Network != "" {
+		id, err := cloud.ParseResourceURL(r.Network)
+		if err != nil || id.Resource != "networks" {
+			return invalidRouterError(key, "network %q is not a valid network", r.Network)
+		}
+		if _, err := mockGCE.Networks().Get(ctx, id.Key); err != nil {
+			return invalidRouterError(key, "network %q does not exist", r.Network)
+		}
+		networkID = id
+	}
+
+	natNames := map[string]bool{}
+	for _, nat := range r.Nats {
+		if nat.Name == "" {
+			return invalidRouterError(key, "NAT name must be set")
+		}
+		if natNames[nat.Name] {
+			return invalidRouterError(key, "duplicate NAT name %q", nat.Name)
+		}
+		natNames[nat.Name] = true
+
+		switch nat.SourceSubnetworkIpRangesToNat {
+		case "LIST_OF_SUBNETWORKS":
+			if len(nat.Subnetworks) == 0 {
+				return invalidRouterError(key, "NAT %q: subnetworks must be set for LIST_OF_SUBNETWORKS", nat.Name)
+			}
+		default:
+			if len(nat.Subnetworks) != 0 {
+				return invalidRouterError(key, "NAT %q: subnetworks can only be set for LIST_OF_SUBNETWORKS", nat.Name)
+			}
+		}
+		for _, sn := range nat.Subnetworks {
+			id, err := cloud.ParseResourceURL(sn.Name)
+			if err != nil || id.Resource != "subnetworks" {
+				return invalidRouterError(key, "NAT %q: %q is not a valid subnetwork", nat.Name, sn.Name)
+			}
+			if meta.NormalizeLocation(id.Key.Region) != key.Region {
+				return invalidRouterError(key, "NAT %q: subnetwork %q is not in region %s", nat.Name, sn.Name, key.Region)
+			}
+			subnet, err := mockGCE.Subnetworks().Get(ctx, id.Key)
+			if err != nil {
+				return invalidRouterError(key, "NAT %q: subnetwork %q does not exist", nat.Name, sn.Name)
+			}
+			if networkID != nil && subnet.Network != "" {
+				if snNetwork, err := cloud.ParseResourceURL(subnet.Network); err == nil && !snNetwork.Equal(networkID) {
+					return invalidRouterError(key, "NAT %q: subnetwork %q is not in network %q", nat.Name, sn.Name, r.Network)
+				}
+			}
+		}
+
+		if nat.NatIpAllocateOption == "MANUAL_ONLY" && len(nat.NatIps) == 0 {
+			return invalidRouterError(key, "NAT %q: natIps must be set for MANUAL_ONLY", nat.Name)
+		}
+		for _, natIP := range nat.NatIps {
+			id, err := cloud.ParseResourceURL(natIP)
+			if err != nil || id.Resource != "addresses" || id.Key.Type() != meta.Regional {
+				return invalidRouterError(key, "NAT %q: %q is not a valid address", nat.Name, natIP)
+			}
+			if meta.NormalizeLocation(id.Key.Region) != key.Region {
+				return invalidRouterError(key, "NAT %q: address %q is not in region %s", nat.Name, natIP, key.Region)
+			}
+			if _, err := mockGCE.Addresses().Get(ctx, id.Key); err != nil {
+				return invalidRouterError(key, "NAT %q: address %q does not exist", nat.Name, natIP)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"testing"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestRouterHooks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mockGCE := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "mock-project"})
+	mockGCE.MockRouters.InsertHook = InsertRouterHook(mockGCE)
+	mockGCE.MockRouters.PatchHook = PatchRouterHook(mockGCE)
+	mockGCE.MockRouters.UpdateHook = UpdateRouterHook(mockGCE)
+	mockGCE.MockRouters.GetRouterStatusHook = GetRouterStatusHook(mockGCE)
+
+	const (
+		network  = "projects/mock-project/global/networks/net"
+		network2 = "projects/mock-project/global/networks/net2"
+		subnet   = "projects/mock-project/regions/us-central1/subnetworks/subnet"
+		subnet2  = "projects/mock-project/regions/us-central1/subnetworks/subnet2"
+		subnetEU = "projects/mock-project/regions/europe-west1/subnetworks/subnet"
+		natIP    = "projects/mock-project/regions/us-central1/addresses/nat-ip"
+	)
+	mustInsert := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("Insert() = %v, want nil", err)
+		}
+	}
+	mustInsert(mockGCE.Networks().Insert(ctx, meta.GlobalKey("net"), &ga.Network{}))
+	mustInsert(mockGCE.Networks().Insert(ctx, meta.GlobalKey("net2"), &ga.Network{}))
+	mustInsert(mockGCE.Subnetworks().Insert(ctx, meta.RegionalKey("subnet", "us-central1"), &ga.Subnetwork{Network: network}))
+	mustInsert(mockGCE.Subnetworks().Insert(ctx, meta.RegionalKey("subnet2", "us-central1"), &ga.Subnetwork{Network: network2}))
+	mustInsert(mockGCE.Subnetworks().Insert(ctx, meta.RegionalKey("subnet", "europe-west1"), &ga.Subnetwork{Network: network}))
+	mustInsert(mockGCE.Addresses().Insert(ctx, meta.RegionalKey("nat-ip", "us-central1"), &ga.Address{Address: "1.2.3.4"}))
+
+	nat := func(name string, subnets ...string) *ga.RouterNat {
+		n := &ga.RouterNat{
+			Name:                          name,
+			NatIpAllocateOption:           "AUTO_ONLY",
+			SourceSubnetworkIpRangesToNat: "ALL_SUBNETWORKS_ALL_IP_RANGES",
+		}
+		if len(subnets) > 0 {
+			n.SourceSubnetworkIpRangesToNat = "LIST_OF_SUBNETWORKS"
+		}
+		for _, s := range subnets {
+			n.Subnetworks = append(n.Subnetworks, &ga.RouterNatSubnetworkToNat{Name: s})
+		}
+		return n
+	}
+
+	for _, tc := range []struct {
+		desc    string
+		router  *ga.Router
+		wantErr bool
+	}{
+		{desc: "no NAT", router: &ga.Router{Network: network}},
+		{desc: "all subnetworks", router: &ga.Router{Network: network, Nats: []*ga.RouterNat{nat("nat")}}},
+		{desc: "list of subnetworks", router: &ga.Router{Network: network, Nats: []*ga.RouterNat{nat("nat", subnet)}}},
+		{desc: "missing network", router: &ga.Router{Network: "projects/mock-project/global/networks/missing"}, wantErr: true},
+		{desc: "unnamed NAT", router: &ga.Router{Network: network, Nats: []*ga.RouterNat{nat("")}}, wantErr: true},
+		{desc: "duplicate NAT", router: &ga.Router{Network: network, Nats: []*ga.RouterNat{nat("nat"), nat("nat")}}, wantErr: true},
+		{desc: "empty subnetwork list", router: &ga.Router{Network: network, Nats: []*ga.RouterNat{{Name: "nat", SourceSubnetworkIpRangesToNat: "LIST_OF_SUBNETWORKS"}}}, wantErr: true},
+		{desc: "missing subnetwork", router: &ga.Router{Network: network, Nats: []*ga.RouterNat{nat("nat", "projects/mock-project/regions/us-central1/subnetworks/missing")}}, wantErr: true},
+		{desc: "subnetwork in other region", router: &ga.Router{Network: network, Nats: []*ga.RouterNat{nat("nat", subnetEU)}}, wantErr: true},
+		{desc: "subnetwork in other network", router: &ga.Router{Network: network, Nats: []*ga.RouterNat{nat("nat", subnet2)}}, wantErr: true},
+		{desc: "manual without IPs", router: &ga.Router{Network: network, Nats: []*ga.RouterNat{{Name: "nat", NatIpAllocateOption: "MANUAL_ONLY"}}}, wantErr: true},
+		{desc: "manual with missing IP", router: &ga.Router{Network: network, Nats: []*ga.RouterNat{{Name: "nat", NatIpAllocateOption: "MANUAL_ONLY", NatIps: []string{"projects/mock-project/regions/us-central1/addresses/missing"}}}}, wantErr: true},
+		{desc: "manual", router: &ga.Router{Network: network, Nats: []*ga.RouterNat{{Name: "nat", NatIpAllocateOption: "MANUAL_ONLY", NatIps: []string{natIP}}}}},
+	} {
+		key := meta.RegionalKey("router", "us-central1")
+		err := mockGCE.Routers().Insert(ctx, key, tc.router)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("%s: Insert() = %v; gotErr = %t, want %t", tc.desc, err, gotErr, tc.wantErr)
+		}
+		if err == nil {
+			mockGCE.Routers().Delete(ctx, key)
+		}
+	}
+
+	key := meta.RegionalKey("router", "us-central1")
+	if err := mockGCE.Routers().Insert(ctx, key, &ga.Router{Network: network, Nats: []*ga.RouterNat{nat("nat", subnet)}}); err != nil {
+		t.Fatalf("Insert() = %v, want nil", err)
+	}
+
+	// An invalid patch leaves the Router unchanged.
+	if err := mockGCE.Routers().Patch(ctx, key, &ga.Router{Nats: []*ga.RouterNat{nat("nat", subnet2)}}); err == nil {
+		t.Errorf("Patch() = nil, want error for subnetwork in other network")
+	}
+	r, err := mockGCE.Routers().Get(ctx, key)
+	if err != nil || r.Nats[0].Subnetworks[0].Name != subnet {
+		t.Errorf("Get() = %+v, %v; want unchanged NAT subnetworks", r, err)
+	}
+
+	manual := &ga.RouterNat{Name: "nat", NatIpAllocateOption: "MANUAL_ONLY", SourceSubnetworkIpRangesToNat: "ALL_SUBNETWORKS_ALL_IP_RANGES", NatIps: []string{natIP}}
+	if err := mockGCE.Routers().Patch(ctx, key, &ga.Router{Nats: []*ga.RouterNat{manual}}); err != nil {
+		t.Fatalf("Patch() = %v, want nil", err)
+	}
+
+	status, err := mockGCE.Routers().GetRouterStatus(ctx, key)
+	if err != nil {
+		t.Fatalf("GetRouterStatus() = _, %v, want nil", err)
+	}
+	if status.Result.Network != network || len(status.Result.NatStatus) != 1 {
+		t.Fatalf("GetRouterStatus() = %+v, want network %q with one NAT", status.Result, network)
+	}
+	ns := status.Result.NatStatus[0]
+	if ns.Name != "nat" || len(ns.UserAllocatedNatIps) != 1 || ns.UserAllocatedNatIps[0] != "1.2.3.4" {
+		t.Errorf("NatStatus = %+v, want nat with UserAllocatedNatIps [1.2.3.4]", ns)
+	}
+
+	if err := mockGCE.Routers().Update(ctx, key, &ga.Router{Network: network2}); err != nil {
+		t.Errorf("Update() = %v, want nil", err)
+	}
+	if err := mockGCE.Routers().Update(ctx, key, &ga.Router{Network: network2, Nats: []*ga.RouterNat{nat("nat", subnet)}}); err == nil {
+		t.Errorf("Update() = nil, want error for subnetwork in other network")
+	}
+	if err := mockGCE.Routers().Update(ctx, meta.RegionalKey("missing", "us-central1"), &ga.Router{}); err == nil {
+		t.Errorf("Update(missing) = nil, want error")
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// Connection status of a ServiceAttachmentConnectedEndpoint.
+const (
+	ServiceAttachmentStatusAccepted = "ACCEPTED"
+	ServiceAttachmentStatusPending  = "PENDING"
+	ServiceAttachmentStatusRejected = "REJECTED"
+)
+
+// ConnectServiceAttachmentEndpoint simulates a consumer endpoint (a
+// forwarding rule in the consumer project) connecting to the
+// ServiceAttachment. The status of the connection is computed from the
+// connection preference and the consumer accept/reject lists.
+func ConnectServiceAttachmentEndpoint(ctx context.Context, key *meta.Key, endpoint string, pscConnectionID uint64, m *cloud.MockServiceAttachments) error {
+	if _, err := m.Get(ctx, key); err != nil {
+		return err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	sa := m.Objects[*key].ToGA()
+	for _, ep := range sa.ConnectedEndpoints {
+		if ep.Endpoint == endpoint {
+			return &googleapi.Error{
+				Code:    http.StatusConflict,
+				Message: fmt.Sprintf("endpoint %s is already connected to %s", endpoint, key),
+			}
+		}
+	}
+	sa.ConnectedEndpoints = append(sa.ConnectedEndpoints, &ga.ServiceAttachmentConnectedEndpoint{
+		Endpoint:        endpoint,
+		PscConnectionId: pscConnectionID,
+	})
+	updateServiceAttachmentConnections(sa)
+	m.Objects[*key] = m.Obj(sa)
+	return nil
+}
+
+// PatchServiceAttachmentHook patches the ServiceAttachment and re-evaluates
+// the status of the connected endpoints against the updated consumer
+// accept/reject lists.
+func PatchServiceAttachmentHook(ctx context.Context, key *meta.Key, obj *ga.ServiceAttachment, m *cloud.MockServiceAttachments) error {
+	if _, err := m.Get(ctx, key); err != nil {
+		return err
+	}
+	key = key.Normalize()
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	sa := m.Objects[*key].ToGA()
+	if err := cloud.MockPatch(sa, obj); err != nil {
+		return err
+	}
+	updateServiceAttachmentConnections(sa)
+	m.Objects[*key] = m.Obj(sa)
+	return nil
+}
+
+// updateServiceAttachmentConnections sets the Status of each connected
+// endpoint. Rejected consumers are always rejected. With ACCEPT_MANUAL, only
+// consumers in the accept list are accepted and other connections are left
+// pending.
+func updateServiceAttachmentConnections(sa *ga.ServiceAttachment) {
+	for _, ep := range sa.ConnectedEndpoints {
+		project := ""
+		if id, err := cloud.ParseResourceURL(ep.Endpoint); err == nil {
+			project = id.ProjectID
+		}
+		ep.Status = serviceAttachmentConnectionStatus(sa, project)
+	}
+}
+
+func serviceAttachmentConnectionStatus(sa *ga.ServiceAttachment, project string) string {
+	for _, p := range sa.ConsumerRejectLists {
+		if p == project {
+			return ServiceAttachmentStatusRejected
+		}
+	}
+	if sa.ConnectionPreference != "ACCEPT_MANUAL" {
+		return ServiceAttachmentStatusAccepted
+	}
+	for _, limit := range sa.ConsumerAcceptLists {
+		if limit.ProjectIdOrNum == project {
+			return ServiceAttachmentStatusAccepted
+		}
+	}
+	return ServiceAttachmentStatusPending
+}
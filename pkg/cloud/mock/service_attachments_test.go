@@ -0,0 +1,79 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"testing"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestServiceAttachmentConnections(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "producer"})
+	mock.MockServiceAttachments.PatchHook = PatchServiceAttachmentHook
+
+	key := meta.RegionalKey("sa", "us-central1")
+	if err := mock.ServiceAttachments().Insert(ctx, key, &ga.ServiceAttachment{ConnectionPreference: "ACCEPT_MANUAL"}); err != nil {
+		t.Fatalf("Insert() = %v, want nil", err)
+	}
+
+	const (
+		epA = "https://www.googleapis.com/compute/v1/projects/consumer-a/regions/us-central1/forwardingRules/fr"
+		epB = "https://www.googleapis.com/compute/v1/projects/consumer-b/regions/us-central1/forwardingRules/fr"
+	)
+	for i, ep := range []string{epA, epB} {
+		if err := ConnectServiceAttachmentEndpoint(ctx, key, ep, uint64(i), mock.MockServiceAttachments); err != nil {
+			t.Fatalf("ConnectServiceAttachmentEndpoint(%q) = %v, want nil", ep, err)
+		}
+	}
+	if err := ConnectServiceAttachmentEndpoint(ctx, key, epA, 10, mock.MockServiceAttachments); err == nil {
+		t.Errorf("ConnectServiceAttachmentEndpoint(%q) = nil, want error (duplicate)", epA)
+	}
+
+	checkStatus := func(want map[string]string) {
+		t.Helper()
+		sa, err := mock.ServiceAttachments().Get(ctx, key)
+		if err != nil {
+			t.Fatalf("Get() = _, %v, want nil", err)
+		}
+		got := map[string]string{}
+		for _, ep := range sa.ConnectedEndpoints {
+			got[ep.Endpoint] = ep.Status
+		}
+		for ep, status := range want {
+			if got[ep] != status {
+				t.Errorf("status of %q = %q, want %q", ep, got[ep], status)
+			}
+		}
+	}
+	checkStatus(map[string]string{epA: ServiceAttachmentStatusPending, epB: ServiceAttachmentStatusPending})
+
+	patch := &ga.ServiceAttachment{
+		ConsumerAcceptLists: []*ga.ServiceAttachmentConsumerProjectLimit{{ProjectIdOrNum: "consumer-a", ConnectionLimit: 10}},
+		ConsumerRejectLists: []string{"consumer-b"},
+	}
+	if err := mock.ServiceAttachments().Patch(ctx, key, patch, "consumerAcceptLists", "consumerRejectLists"); err != nil {
+		t.Fatalf("Patch() = %v, want nil", err)
+	}
+	checkStatus(map[string]string{epA: ServiceAttachmentStatusAccepted, epB: ServiceAttachmentStatusRejected})
+}
@@ -0,0 +1,275 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// The hooks below set the fields of BackendServices and target proxies that
+// reference other resources. Unlike SetURLMapTargetHTTPProxyHook and the
+// other hooks in mock.go, they check that the referenced resource exists and
+// is in the same scope (global or the same region), so they are constructed
+// from the MockGCE:
+//
+//	mockGCE.MockBackendServices.SetSecurityPolicyHook = mock.SetSecurityPolicyBackendServiceHook(mockGCE)
+//	mockGCE.MockBackendServices.SetEdgeSecurityPolicyHook = mock.SetEdgeSecurityPolicyBackendServiceHook(mockGCE)
+//	mockGCE.MockTargetHttpProxies.SetUrlMapHook = mock.ValidatingSetURLMapTargetHTTPProxyHook(mockGCE)
+//	mockGCE.MockRegionTargetHttpProxies.SetUrlMapHook = mock.ValidatingSetURLMapRegionTargetHTTPProxyHook(mockGCE)
+//	mockGCE.MockTargetHttpsProxies.SetUrlMapHook = mock.ValidatingSetURLMapTargetHTTPSProxyHook(mockGCE)
+//	mockGCE.MockTargetHttpsProxies.SetSslCertificatesHook = mock.ValidatingSetSslCertificatesTargetHTTPSProxyHook(mockGCE)
+//	mockGCE.MockTargetHttpsProxies.SetSslPolicyHook = mock.ValidatingSetSslPolicyTargetHTTPSProxyHook(mockGCE)
+//	mockGCE.MockRegionTargetHttpsProxies.SetUrlMapHook = mock.ValidatingSetURLMapRegionTargetHTTPSProxyHook(mockGCE)
+//	mockGCE.MockRegionTargetHttpsProxies.SetSslCertificatesHook = mock.ValidatingSetSslCertificatesRegionTargetHTTPSProxyHook(mockGCE)
+//
+// An empty reference clears the SecurityPolicy, EdgeSecurityPolicy and
+// SslPolicy fields.
+
+// SetSecurityPolicyBackendServiceHook returns a hook that sets the
+// SecurityPolicy of the BackendService.
+func SetSecurityPolicyBackendServiceHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *ga.SecurityPolicyReference, *cloud.MockBackendServices) error {
+	return func(ctx context.Context, key *meta.Key, ref *ga.SecurityPolicyReference, m *cloud.MockBackendServices) error {
+		if err := checkReference(ctx, mockGCE, key, ref.SecurityPolicy, "securityPolicies", true); err != nil {
+			return err
+		}
+		return updateBackendService(ctx, key, m, func(bs *ga.BackendService) {
+			bs.SecurityPolicy = ref.SecurityPolicy
+		})
+	}
+}
+
+// SetEdgeSecurityPolicyBackendServiceHook returns a hook that sets the
+// EdgeSecurityPolicy of the BackendService.
+func SetEdgeSecurityPolicyBackendServiceHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *ga.SecurityPolicyReference, *cloud.MockBackendServices) error {
+	return func(ctx context.Context, key *meta.Key, ref *ga.SecurityPolicyReference, m *cloud.MockBackendServices) error {
+		if err := checkReference(ctx, mockGCE, key, ref.SecurityPolicy, "securityPolicies", true); err != nil {
+			return err
+		}
+		return updateBackendService(ctx, key, m, func(bs *ga.BackendService) {
+			bs.EdgeSecurityPolicy = ref.SecurityPolicy
+		})
+	}
+}
+
+// ValidatingSetURLMapTargetHTTPProxyHook returns a hook that sets the UrlMap
+// of the TargetHttpProxy.
+func ValidatingSetURLMapTargetHTTPProxyHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *ga.UrlMapReference, *cloud.MockTargetHttpProxies) error {
+	return func(ctx context.Context, key *meta.Key, ref *ga.UrlMapReference, m *cloud.MockTargetHttpProxies) error {
+		if err := checkReference(ctx, mockGCE, key, ref.UrlMap, "urlMaps", false); err != nil {
+			return err
+		}
+		return updateObject(ctx, key, m.Get, cloud.DeepCopyTargetHttpProxy, func(tp *ga.TargetHttpProxy) {
+			tp.UrlMap = ref.UrlMap
+			m.Lock.Lock()
+			defer m.Lock.Unlock()
+			m.Objects[*key.Normalize()] = m.Obj(tp)
+		})
+	}
+}
+
+// ValidatingSetURLMapRegionTargetHTTPProxyHook returns a hook that sets the
+// UrlMap of the regional TargetHttpProxy.
+func ValidatingSetURLMapRegionTargetHTTPProxyHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *ga.UrlMapReference, *cloud.MockRegionTargetHttpProxies) error {
+	return func(ctx context.Context, key *meta.Key, ref *ga.UrlMapReference, m *cloud.MockRegionTargetHttpProxies) error {
+		if err := checkReference(ctx, mockGCE, key, ref.UrlMap, "urlMaps", false); err != nil {
+			return err
+		}
+		return updateObject(ctx, key, m.Get, cloud.DeepCopyTargetHttpProxy, func(tp *ga.TargetHttpProxy) {
+			tp.UrlMap = ref.UrlMap
+			m.Lock.Lock()
+			defer m.Lock.Unlock()
+			m.Objects[*key.Normalize()] = m.Obj(tp)
+		})
+	}
+}
+
+// ValidatingSetURLMapTargetHTTPSProxyHook returns a hook that sets the UrlMap
+// of the TargetHttpsProxy.
+func ValidatingSetURLMapTargetHTTPSProxyHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *ga.UrlMapReference, *cloud.MockTargetHttpsProxies) error {
+	return func(ctx context.Context, key *meta.Key, ref *ga.UrlMapReference, m *cloud.MockTargetHttpsProxies) error {
+		if err := checkReference(ctx, mockGCE, key, ref.UrlMap, "urlMaps", false); err != nil {
+			return err
+		}
+		return updateObject(ctx, key, m.Get, cloud.DeepCopyTargetHttpsProxy, func(tp *ga.TargetHttpsProxy) {
+			tp.UrlMap = ref.UrlMap
+			m.Lock.Lock()
+			defer m.Lock.Unlock()
+			m.Objects[*key.Normalize()] = m.Obj(tp)
+		})
+	}
+}
+
+// ValidatingSetURLMapRegionTargetHTTPSProxyHook returns a hook that sets the
+// UrlMap of the regional TargetHttpsProxy.
+func ValidatingSetURLMapRegionTargetHTTPSProxyHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *ga.UrlMapReference, *cloud.MockRegionTargetHttpsProxies) error {
+	return func(ctx context.Context, key *meta.Key, ref *ga.UrlMapReference, m *cloud.MockRegionTargetHttpsProxies) error {
+		if err := checkReference(ctx, mockGCE, key, ref.UrlMap, "urlMaps", false); err != nil {
+			return err
+		}
+		return updateObject(ctx, key, m.Get, cloud.DeepCopyTargetHttpsProxy, func(tp *ga.TargetHttpsProxy) {
+			tp.UrlMap = ref.UrlMap
+			m.Lock.Lock()
+			defer m.Lock.Unlock()
+			m.Objects[*key.Normalize()] = m.Obj(tp)
+		})
+	}
+}
+
+// ValidatingSetSslCertificatesTargetHTTPSProxyHook returns a hook that sets
+// the SslCertificates of the TargetHttpsProxy.
+func ValidatingSetSslCertificatesTargetHTTPSProxyHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *ga.TargetHttpsProxiesSetSslCertificatesRequest, *cloud.MockTargetHttpsProxies) error {
+	return func(ctx context.Context, key *meta.Key, req *ga.TargetHttpsProxiesSetSslCertificatesRequest, m *cloud.MockTargetHttpsProxies) error {
+		if err := checkReferences(ctx, mockGCE, key, req.SslCertificates, "sslCertificates"); err != nil {
+			return err
+		}
+		return updateObject(ctx, key, m.Get, cloud.DeepCopyTargetHttpsProxy, func(tp *ga.TargetHttpsProxy) {
+			tp.SslCertificates = req.SslCertificates
+			m.Lock.Lock()
+			defer m.Lock.Unlock()
+			m.Objects[*key.Normalize()] = m.Obj(tp)
+		})
+	}
+}
+
+// ValidatingSetSslCertificatesRegionTargetHTTPSProxyHook returns a hook that
+// sets the SslCertificates of the regional TargetHttpsProxy.
+func ValidatingSetSslCertificatesRegionTargetHTTPSProxyHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *ga.RegionTargetHttpsProxiesSetSslCertificatesRequest, *cloud.MockRegionTargetHttpsProxies) error {
+	return func(ctx context.Context, key *meta.Key, req *ga.RegionTargetHttpsProxiesSetSslCertificatesRequest, m *cloud.MockRegionTargetHttpsProxies) error {
+		if err := checkReferences(ctx, mockGCE, key, req.SslCertificates, "sslCertificates"); err != nil {
+			return err
+		}
+		return updateObject(ctx, key, m.Get, cloud.DeepCopyTargetHttpsProxy, func(tp *ga.TargetHttpsProxy) {
+			tp.SslCertificates = req.SslCertificates
+			m.Lock.Lock()
+			defer m.Lock.Unlock()
+			m.Objects[*key.Normalize()] = m.Obj(tp)
+		})
+	}
+}
+
+// ValidatingSetSslPolicyTargetHTTPSProxyHook returns a hook that sets the
+// SslPolicy of the TargetHttpsProxy.
+func ValidatingSetSslPolicyTargetHTTPSProxyHook(mockGCE *cloud.MockGCE) func(context.Context, *meta.Key, *ga.SslPolicyReference, *cloud.MockTargetHttpsProxies) error {
+	return func(ctx context.Context, key *meta.Key, ref *ga.SslPolicyReference, m *cloud.MockTargetHttpsProxies) error {
+		if err := checkReference(ctx, mockGCE, key, ref.SslPolicy, "sslPolicies", true); err != nil {
+			return err
+		}
+		return updateObject(ctx, key, m.Get, cloud.DeepCopyTargetHttpsProxy, func(tp *ga.TargetHttpsProxy) {
+			tp.SslPolicy = ref.SslPolicy
+			m.Lock.Lock()
+			defer m.Lock.Unlock()
+			m.Objects[*key.Normalize()] = m.Obj(tp)
+		})
+	}
+}
+
+func updateBackendService(ctx context.Context, key *meta.Key, m *cloud.MockBackendServices, f func(*ga.BackendService)) error {
+	return updateObject(ctx, key, m.Get, cloud.DeepCopyBackendService, func(bs *ga.BackendService) {
+		f(bs)
+		m.Lock.Lock()
+		defer m.Lock.Unlock()
+		m.Objects[*key.Normalize()] = m.Obj(bs)
+	})
+}
+
+// updateObject gets the object with get and calls store with a modified
+// copy. store must apply the modification and save the object.
+func updateObject[T any](ctx context.Context, key *meta.Key, get func(context.Context, *meta.Key) (*T, error), deepCopy func(*T) *T, store func(*T)) error {
+	obj, err := get(ctx, key)
+	if err != nil {
+		return err
+	}
+	store(deepCopy(obj))
+	return nil
+}
+
+// checkReferences calls checkReference for each of refs. The list must not
+// be empty.
+func checkReferences(ctx context.Context, mockGCE *cloud.MockGCE, key *meta.Key, refs []string, resource string) error {
+	if len(refs) == 0 {
+		return &googleapi.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("%v: at least one of %s is required", key, resource),
+		}
+	}
+	for _, ref := range refs {
+		if err := checkReference(ctx, mockGCE, key, ref, resource, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkReference returns an error if ref is not a URL of an existing
+// resource of the given type in the same scope as key. An empty ref is valid
+// if optional is true.
+func checkReference(ctx context.Context, mockGCE *cloud.MockGCE, key *meta.Key, ref, resource string, optional bool) error {
+	if ref == "" && optional {
+		return nil
+	}
+	id, err := cloud.ParseResourceURL(ref)
+	if err != nil || id.Resource != resource {
+		return &googleapi.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("%v: %q is not a valid reference to %s", key, ref, resource),
+		}
+	}
+	key = key.Normalize()
+	refKey := id.Key.Normalize()
+	if refKey.Type() != key.Type() || refKey.Region != key.Region {
+		return &googleapi.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("%v: %q must be in the same scope", key, ref),
+		}
+	}
+
+	switch {
+	case resource == "securityPolicies" && refKey.Type() == meta.Global:
+		_, err = mockGCE.SecurityPolicies().Get(ctx, refKey)
+	case resource == "urlMaps" && refKey.Type() == meta.Global:
+		_, err = mockGCE.UrlMaps().Get(ctx, refKey)
+	case resource == "urlMaps":
+		_, err = mockGCE.RegionUrlMaps().Get(ctx, refKey)
+	case resource == "sslCertificates" && refKey.Type() == meta.Global:
+		_, err = mockGCE.SslCertificates().Get(ctx, refKey)
+	case resource == "sslCertificates":
+		_, err = mockGCE.RegionSslCertificates().Get(ctx, refKey)
+	case resource == "sslPolicies" && refKey.Type() == meta.Global:
+		_, err = mockGCE.SslPolicies().Get(ctx, refKey)
+	case resource == "sslPolicies":
+		_, err = mockGCE.RegionSslPolicies().Get(ctx, refKey)
+	default:
+		return &googleapi.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("%v: references to %s are not supported", key, ref),
+		}
+	}
+	if err != nil {
+		return &googleapi.Error{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("The resource %q was not found", ref),
+		}
+	}
+	return nil
+}
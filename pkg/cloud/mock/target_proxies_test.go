@@ -0,0 +1,222 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+func TestSetSecurityPolicyBackendServiceHooks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mockGCE := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "mock-project"})
+	mockGCE.MockBackendServices.SetSecurityPolicyHook = SetSecurityPolicyBackendServiceHook(mockGCE)
+	mockGCE.MockBackendServices.SetEdgeSecurityPolicyHook = SetEdgeSecurityPolicyBackendServiceHook(mockGCE)
+
+	bsKey := meta.GlobalKey("bs")
+	spKey := meta.GlobalKey("sp")
+	sp := cloud.SelfLink(meta.VersionGA, "mock-project", "securityPolicies", spKey)
+	if err := mockGCE.BackendServices().Insert(ctx, bsKey, &ga.BackendService{}); err != nil {
+		t.Fatalf("BackendServices().Insert(%v) = %v, want nil", bsKey, err)
+	}
+	if err := mockGCE.SecurityPolicies().Insert(ctx, spKey, &ga.SecurityPolicy{}); err != nil {
+		t.Fatalf("SecurityPolicies().Insert(%v) = %v, want nil", spKey, err)
+	}
+
+	var gerr *googleapi.Error
+	for _, tc := range []struct {
+		desc     string
+		edge     bool
+		ref      string
+		wantCode int
+	}{
+		{desc: "set", ref: sp},
+		{desc: "set edge", edge: true, ref: sp},
+		{desc: "clear", ref: ""},
+		{
+			desc:     "missing policy",
+			ref:      cloud.SelfLink(meta.VersionGA, "mock-project", "securityPolicies", meta.GlobalKey("other")),
+			wantCode: http.StatusNotFound,
+		},
+		{
+			desc:     "wrong resource",
+			ref:      cloud.SelfLink(meta.VersionGA, "mock-project", "sslPolicies", spKey),
+			wantCode: http.StatusBadRequest,
+		},
+	} {
+		ref := &ga.SecurityPolicyReference{SecurityPolicy: tc.ref}
+		var err error
+		if tc.edge {
+			err = mockGCE.BackendServices().SetEdgeSecurityPolicy(ctx, bsKey, ref)
+		} else {
+			err = mockGCE.BackendServices().SetSecurityPolicy(ctx, bsKey, ref)
+		}
+		if tc.wantCode != 0 {
+			if !errors.As(err, &gerr) || gerr.Code != tc.wantCode {
+				t.Errorf("%s: err = %v, want code %d", tc.desc, err, tc.wantCode)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: err = %v, want nil", tc.desc, err)
+			continue
+		}
+		bs, err := mockGCE.BackendServices().Get(ctx, bsKey)
+		if err != nil {
+			t.Fatalf("BackendServices().Get(%v) = _, %v, want nil", bsKey, err)
+		}
+		got := bs.SecurityPolicy
+		if tc.edge {
+			got = bs.EdgeSecurityPolicy
+		}
+		if got != tc.ref {
+			t.Errorf("%s: policy = %q, want %q", tc.desc, got, tc.ref)
+		}
+	}
+}
+
+func TestValidatingTargetHTTPSProxyHooks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mockGCE := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "mock-project"})
+	mockGCE.MockTargetHttpsProxies.SetUrlMapHook = ValidatingSetURLMapTargetHTTPSProxyHook(mockGCE)
+	mockGCE.MockTargetHttpsProxies.SetSslCertificatesHook = ValidatingSetSslCertificatesTargetHTTPSProxyHook(mockGCE)
+	mockGCE.MockTargetHttpsProxies.SetSslPolicyHook = ValidatingSetSslPolicyTargetHTTPSProxyHook(mockGCE)
+	mockGCE.MockRegionTargetHttpsProxies.SetUrlMapHook = ValidatingSetURLMapRegionTargetHTTPSProxyHook(mockGCE)
+
+	tpKey := meta.GlobalKey("tp")
+	rtpKey := meta.RegionalKey("tp", "us-central1")
+	umKey := meta.GlobalKey("um")
+	rumKey := meta.RegionalKey("um", "us-central1")
+	certKey := meta.GlobalKey("cert")
+	policyKey := meta.GlobalKey("policy")
+	um := cloud.SelfLink(meta.VersionGA, "mock-project", "urlMaps", umKey)
+	rum := cloud.SelfLink(meta.VersionGA, "mock-project", "urlMaps", rumKey)
+	otherRum := cloud.SelfLink(meta.VersionGA, "mock-project", "urlMaps", meta.RegionalKey("um", "us-east1"))
+	cert := cloud.SelfLink(meta.VersionGA, "mock-project", "sslCertificates", certKey)
+	policy := cloud.SelfLink(meta.VersionGA, "mock-project", "sslPolicies", policyKey)
+
+	for _, err := range []error{
+		mockGCE.TargetHttpsProxies().Insert(ctx, tpKey, &ga.TargetHttpsProxy{}),
+		mockGCE.RegionTargetHttpsProxies().Insert(ctx, rtpKey, &ga.TargetHttpsProxy{}),
+		mockGCE.UrlMaps().Insert(ctx, umKey, &ga.UrlMap{}),
+		mockGCE.RegionUrlMaps().Insert(ctx, rumKey, &ga.UrlMap{}),
+		mockGCE.SslCertificates().Insert(ctx, certKey, &ga.SslCertificate{}),
+		mockGCE.SslPolicies().Insert(ctx, policyKey, &ga.SslPolicy{}),
+	} {
+		if err != nil {
+			t.Fatalf("Insert() = %v, want nil", err)
+		}
+	}
+
+	var gerr *googleapi.Error
+	for _, tc := range []struct {
+		desc     string
+		call     func() error
+		wantCode int
+	}{
+		{
+			desc: "url map",
+			call: func() error {
+				return mockGCE.TargetHttpsProxies().SetUrlMap(ctx, tpKey, &ga.UrlMapReference{UrlMap: um})
+			},
+		},
+		{
+			desc: "regional url map on global proxy",
+			call: func() error {
+				return mockGCE.TargetHttpsProxies().SetUrlMap(ctx, tpKey, &ga.UrlMapReference{UrlMap: rum})
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			desc: "regional url map",
+			call: func() error {
+				return mockGCE.RegionTargetHttpsProxies().SetUrlMap(ctx, rtpKey, &ga.UrlMapReference{UrlMap: rum})
+			},
+		},
+		{
+			desc: "url map in other region",
+			call: func() error {
+				return mockGCE.RegionTargetHttpsProxies().SetUrlMap(ctx, rtpKey, &ga.UrlMapReference{UrlMap: otherRum})
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			desc: "certificates",
+			call: func() error {
+				return mockGCE.TargetHttpsProxies().SetSslCertificates(ctx, tpKey, &ga.TargetHttpsProxiesSetSslCertificatesRequest{SslCertificates: []string{cert}})
+			},
+		},
+		{
+			desc: "no certificates",
+			call: func() error {
+				return mockGCE.TargetHttpsProxies().SetSslCertificates(ctx, tpKey, &ga.TargetHttpsProxiesSetSslCertificatesRequest{})
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			desc: "missing certificate",
+			call: func() error {
+				missing := cloud.SelfLink(meta.VersionGA, "mock-project", "sslCertificates", meta.GlobalKey("missing"))
+				return mockGCE.TargetHttpsProxies().SetSslCertificates(ctx, tpKey, &ga.TargetHttpsProxiesSetSslCertificatesRequest{SslCertificates: []string{cert, missing}})
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			desc: "ssl policy",
+			call: func() error {
+				return mockGCE.TargetHttpsProxies().SetSslPolicy(ctx, tpKey, &ga.SslPolicyReference{SslPolicy: policy})
+			},
+		},
+	} {
+		err := tc.call()
+		if tc.wantCode == 0 {
+			if err != nil {
+				t.Errorf("%s: err = %v, want nil", tc.desc, err)
+			}
+			continue
+		}
+		if !errors.As(err, &gerr) || gerr.Code != tc.wantCode {
+			t.Errorf("%s: err = %v, want code %d", tc.desc, err, tc.wantCode)
+		}
+	}
+
+	tp, err := mockGCE.TargetHttpsProxies().Get(ctx, tpKey)
+	if err != nil {
+		t.Fatalf("TargetHttpsProxies().Get(%v) = _, %v, want nil", tpKey, err)
+	}
+	if tp.UrlMap != um || tp.SslPolicy != policy || len(tp.SslCertificates) != 1 || tp.SslCertificates[0] != cert {
+		t.Errorf("TargetHttpsProxies().Get(%v) = %+v, want UrlMap %q, SslPolicy %q, SslCertificates [%q]", tpKey, tp, um, policy, cert)
+	}
+	rtp, err := mockGCE.RegionTargetHttpsProxies().Get(ctx, rtpKey)
+	if err != nil {
+		t.Fatalf("RegionTargetHttpsProxies().Get(%v) = _, %v, want nil", rtpKey, err)
+	}
+	if rtp.UrlMap != rum {
+		t.Errorf("RegionTargetHttpsProxies().Get(%v).UrlMap = %q, want %q", rtpKey, rtp.UrlMap, rum)
+	}
+}
@@ -0,0 +1,309 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"google.golang.org/api/googleapi"
+	networkconnectivity "google.golang.org/api/networkconnectivity/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// NewMockNetworkConnectivity returns a new mock for the networkconnectivity
+// API.
+func NewMockNetworkConnectivity(projectRouter ProjectRouter) *MockNetworkConnectivity {
+	hubs := NewMockNetworkConnectivityOps[networkconnectivity.Hub](projectRouter, "Hubs", "hubs")
+	hubs.Global = true
+	return &MockNetworkConnectivity{
+		MockHubs:   hubs,
+		MockSpokes: NewMockNetworkConnectivityOps[networkconnectivity.Spoke](projectRouter, "Spokes", "spokes"),
+	}
+}
+
+// MockNetworkConnectivity is the mock for the networkconnectivity API.
+type MockNetworkConnectivity struct {
+	MockHubs   *MockNetworkConnectivityOps[networkconnectivity.Hub]
+	MockSpokes *MockNetworkConnectivityOps[networkconnectivity.Spoke]
+}
+
+// MockNetworkConnectivity implements NetworkConnectivity.
+var _ NetworkConnectivity = (*MockNetworkConnectivity)(nil)
+
+// Hubs implements NetworkConnectivity.
+func (m *MockNetworkConnectivity) Hubs() NetworkConnectivityOps[networkconnectivity.Hub] {
+	return m.MockHubs
+}
+
+// Spokes implements NetworkConnectivity.
+func (m *MockNetworkConnectivity) Spokes() NetworkConnectivityOps[networkconnectivity.Spoke] {
+	return m.MockSpokes
+}
+
+// NewMockNetworkConnectivityOps returns a new mock for a networkconnectivity
+// resource.
+func NewMockNetworkConnectivityOps[T any](pr ProjectRouter, service, resource string) *MockNetworkConnectivityOps[T] {
+	return &MockNetworkConnectivityOps[T]{
+		ProjectRouter: pr,
+		Service:       service,
+		Resource:      resource,
+		Objects:       map[meta.Key]*T{},
+		GetError:      map[meta.Key]error{},
+		InsertError:   map[meta.Key]error{},
+		DeleteError:   map[meta.Key]error{},
+		PatchError:    map[meta.Key]error{},
+	}
+}
+
+// MockNetworkConnectivityOps is the mock for a networkconnectivity resource.
+type MockNetworkConnectivityOps[T any] struct {
+	Lock sync.Mutex
+
+	ProjectRouter ProjectRouter
+	// Service and Resource are the names used for the CallContextKey and
+	// the resource path respectively.
+	Service  string
+	Resource string
+	// Global is true if the resource only exists in the global location.
+	Global bool
+
+	// Objects maintained by the mock.
+	Objects map[meta.Key]*T
+
+	// If an entry exists for the given key and operation, then the error
+	// will be returned instead of the operation.
+	GetError    map[meta.Key]error
+	ListError   *error
+	InsertError map[meta.Key]error
+	DeleteError map[meta.Key]error
+	PatchError  map[meta.Key]error
+
+	// xxxHook allow you to intercept the standard processing of the mock in
+	// order to add your own logic. Return (true, _, _) to prevent the normal
+	// execution flow of the mock. Return (false, nil, nil) to continue with
+	// normal mock behavior/ after the hook function executes.
+	GetHook    func(ctx context.Context, key *meta.Key, m *MockNetworkConnectivityOps[T]) (bool, *T, error)
+	ListHook   func(ctx context.Context, location string, m *MockNetworkConnectivityOps[T]) (bool, []*T, error)
+	InsertHook func(ctx context.Context, key *meta.Key, obj *T, m *MockNetworkConnectivityOps[T]) (bool, error)
+	DeleteHook func(ctx context.Context, key *meta.Key, m *MockNetworkConnectivityOps[T]) (bool, error)
+	PatchHook  func(ctx context.Context, key *meta.Key, obj *T, updateMask []string, m *MockNetworkConnectivityOps[T]) (bool, error)
+
+	// X is extra state that can be used as part of the mock. Generated code
+	// will not use this field.
+	X interface{}
+}
+
+func (m *MockNetworkConnectivityOps[T]) notFound(key *meta.Key) error {
+	return &googleapi.Error{
+		Code:    http.StatusNotFound,
+		Message: fmt.Sprintf("MockNetworkConnectivity.%s %v not found", m.Service, key),
+	}
+}
+
+// checkKey returns an error if key is not valid for the resource.
+func (m *MockNetworkConnectivityOps[T]) checkKey(key *meta.Key) error {
+	_, _, err := locationResourceName("", m.Resource, m.Global, key)
+	return err
+}
+
+// Get implements NetworkConnectivityOps.
+func (m *MockNetworkConnectivityOps[T]) Get(ctx context.Context, key *meta.Key) (*T, error) {
+	if m.GetHook != nil {
+		if intercept, obj, err := m.GetHook(ctx, key, m); intercept {
+			klog.V(5).Infof("MockNetworkConnectivity.%s.Get(%v, %v) = %+v, %v", m.Service, ctx, key, obj, err)
+			return obj, err
+		}
+	}
+	key = key.Normalize()
+	if err := m.checkKey(key); err != nil {
+		return nil, err
+	}
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	if err, ok := m.GetError[*key]; ok {
+		klog.V(5).Infof("MockNetworkConnectivity.%s.Get(%v, %v) = nil, %v", m.Service, ctx, key, err)
+		return nil, err
+	}
+	obj, ok := m.Objects[*key]
+	if !ok {
+		err := m.notFound(key)
+		klog.V(5).Infof("MockNetworkConnectivity.%s.Get(%v, %v) = nil, %v", m.Service, ctx, key, err)
+		return nil, err
+	}
+	klog.V(5).Infof("MockNetworkConnectivity.%s.Get(%v, %v) = %+v, nil", m.Service, ctx, key, obj)
+	return obj, nil
+}
+
+// List implements NetworkConnectivityOps.
+func (m *MockNetworkConnectivityOps[T]) List(ctx context.Context, location string) ([]*T, error) {
+	if m.ListHook != nil {
+		if intercept, objs, err := m.ListHook(ctx, location, m); intercept {
+			klog.V(5).Infof("MockNetworkConnectivity.%s.List(%v, %v) = [%v items], %v", m.Service, ctx, location, len(objs), err)
+			return objs, err
+		}
+	}
+	location = meta.NormalizeLocation(location)
+	if m.Global && location != "global" {
+		return nil, fmt.Errorf("invalid networkconnectivity location for %s (%q), must be global", m.Resource, location)
+	}
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	if m.ListError != nil {
+		err := *m.ListError
+		klog.V(5).Infof("MockNetworkConnectivity.%s.List(%v, %v) = nil, %v", m.Service, ctx, location, err)
+		return nil, err
+	}
+
+	var objs []*T
+	for key, obj := range m.Objects {
+		if loc, _ := NetworkServicesLocation(&key); loc != location {
+			continue
+		}
+		objs = append(objs, obj)
+	}
+	klog.V(5).Infof("MockNetworkConnectivity.%s.List(%v, %v) = [%v items], nil", m.Service, ctx, location, len(objs))
+	return objs, nil
+}
+
+// Insert implements NetworkConnectivityOps.
+func (m *MockNetworkConnectivityOps[T]) Insert(ctx context.Context, key *meta.Key, obj *T) error {
+	if m.InsertHook != nil {
+		if intercept, err := m.InsertHook(ctx, key, obj, m); intercept {
+			klog.V(5).Infof("MockNetworkConnectivity.%s.Insert(%v, %v, %+v) = %v", m.Service, ctx, key, obj, err)
+			return err
+		}
+	}
+	key = key.Normalize()
+	projectID := m.ProjectRouter.ProjectID(ctx, meta.VersionGA, m.Service)
+	_, name, err := locationResourceName(projectID, m.Resource, m.Global, key)
+	if err != nil {
+		return err
+	}
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	if err, ok := m.InsertError[*key]; ok {
+		klog.V(5).Infof("MockNetworkConnectivity.%s.Insert(%v, %v, %+v) = %v", m.Service, ctx, key, obj, err)
+		return err
+	}
+	if _, ok := m.Objects[*key]; ok {
+		err := &googleapi.Error{
+			Code:    http.StatusConflict,
+			Message: fmt.Sprintf("MockNetworkConnectivity.%s %v exists", m.Service, key),
+		}
+		klog.V(5).Infof("MockNetworkConnectivity.%s.Insert(%v, %v, %+v) = %v", m.Service, ctx, key, obj, err)
+		return err
+	}
+
+	setStringField(obj, "Name", name)
+	m.Objects[*key] = obj
+	klog.V(5).Infof("MockNetworkConnectivity.%s.Insert(%v, %v, %+v) = nil", m.Service, ctx, key, obj)
+	return nil
+}
+
+// Delete implements NetworkConnectivityOps.
+func (m *MockNetworkConnectivityOps[T]) Delete(ctx context.Context, key *meta.Key) error {
+	if m.DeleteHook != nil {
+		if intercept, err := m.DeleteHook(ctx, key, m); intercept {
+			klog.V(5).Infof("MockNetworkConnectivity.%s.Delete(%v, %v) = %v", m.Service, ctx, key, err)
+			return err
+		}
+	}
+	key = key.Normalize()
+	if err := m.checkKey(key); err != nil {
+		return err
+	}
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	if err, ok := m.DeleteError[*key]; ok {
+		klog.V(5).Infof("MockNetworkConnectivity.%s.Delete(%v, %v) = %v", m.Service, ctx, key, err)
+		return err
+	}
+	if _, ok := m.Objects[*key]; !ok {
+		err := m.notFound(key)
+		klog.V(5).Infof("MockNetworkConnectivity.%s.Delete(%v, %v) = %v", m.Service, ctx, key, err)
+		return err
+	}
+
+	delete(m.Objects, *key)
+	klog.V(5).Infof("MockNetworkConnectivity.%s.Delete(%v, %v) = nil", m.Service, ctx, key)
+	return nil
+}
+
+// Patch implements NetworkConnectivityOps. Fields in updateMask are copied to
+// the stored object. An empty updateMask replaces the stored object.
+func (m *MockNetworkConnectivityOps[T]) Patch(ctx context.Context, key *meta.Key, obj *T, updateMask ...string) error {
+	if m.PatchHook != nil {
+		if intercept, err := m.PatchHook(ctx, key, obj, updateMask, m); intercept {
+			klog.V(5).Infof("MockNetworkConnectivity.%s.Patch(%v, %v, %+v, %v) = %v", m.Service, ctx, key, obj, updateMask, err)
+			return err
+		}
+	}
+	key = key.Normalize()
+	if err := m.checkKey(key); err != nil {
+		return err
+	}
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	if err, ok := m.PatchError[*key]; ok {
+		klog.V(5).Infof("MockNetworkConnectivity.%s.Patch(%v, %v, %+v, %v) = %v", m.Service, ctx, key, obj, updateMask, err)
+		return err
+	}
+	cur, ok := m.Objects[*key]
+	if !ok {
+		err := m.notFound(key)
+		klog.V(5).Infof("MockNetworkConnectivity.%s.Patch(%v, %v, %+v, %v) = %v", m.Service, ctx, key, obj, updateMask, err)
+		return err
+	}
+
+	updated := new(T)
+	if len(updateMask) == 0 {
+		*updated = *obj
+	} else {
+		*updated = *cur
+		dest, src := reflect.ValueOf(updated).Elem(), reflect.ValueOf(obj).Elem()
+		for _, path := range updateMask {
+			if err := maskField(dest, src, strings.Split(path, ".")); err != nil {
+				return &googleapi.Error{
+					Code:    http.StatusBadRequest,
+					Message: fmt.Sprintf("MockNetworkConnectivity.%s: invalid updateMask %q: %v", m.Service, path, err),
+				}
+			}
+		}
+	}
+	setStringField(updated, "Name", stringField(cur, "Name"))
+	m.Objects[*key] = updated
+
+	klog.V(5).Infof("MockNetworkConnectivity.%s.Patch(%v, %v, %+v, %v) = nil", m.Service, ctx, key, obj, updateMask)
+	return nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	networkconnectivity "google.golang.org/api/networkconnectivity/v1"
+	"google.golang.org/api/option"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+func TestMockNetworkConnectivity(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"mock-project"})
+	hubs := mock.NetworkConnectivity().Hubs()
+	spokes := mock.NetworkConnectivity().Spokes()
+
+	hk := meta.GlobalKey("hub")
+	if err := hubs.Insert(ctx, hk, &networkconnectivity.Hub{}); err != nil {
+		t.Fatalf("Hubs().Insert(%v) = %v, want nil", hk, err)
+	}
+	if err := hubs.Insert(ctx, meta.RegionalKey("hub", "us-central1"), &networkconnectivity.Hub{}); err == nil {
+		t.Errorf("Hubs().Insert(regional key) = nil, want error")
+	}
+	if _, err := hubs.List(ctx, "us-central1"); err == nil {
+		t.Errorf("Hubs().List(us-central1) = _, nil, want error")
+	}
+	hub, err := hubs.Get(ctx, hk)
+	if err != nil {
+		t.Fatalf("Hubs().Get(%v) = _, %v, want nil", hk, err)
+	}
+	if want := "projects/mock-project/locations/global/hubs/hub"; hub.Name != want {
+		t.Errorf("hub.Name = %q, want %q", hub.Name, want)
+	}
+
+	sk := meta.RegionalKey("spoke", "us-central1")
+	if err := spokes.Insert(ctx, sk, &networkconnectivity.Spoke{Hub: hub.Name}); err != nil {
+		t.Fatalf("Spokes().Insert(%v) = %v, want nil", sk, err)
+	}
+	for _, tc := range []struct {
+		location string
+		want     int
+	}{
+		{"us-central1", 1},
+		{"regions/us-central1", 1},
+		{"global", 0},
+	} {
+		objs, err := spokes.List(ctx, tc.location)
+		if err != nil || len(objs) != tc.want {
+			t.Errorf("Spokes().List(%q) = %d items, %v; want %d items, nil", tc.location, len(objs), err, tc.want)
+		}
+	}
+	if err := spokes.Patch(ctx, sk, &networkconnectivity.Spoke{Description: "desc"}, "description"); err != nil {
+		t.Fatalf("Spokes().Patch(%v) = %v, want nil", sk, err)
+	}
+	if spoke, _ := spokes.Get(ctx, sk); spoke.Description != "desc" || spoke.Hub != hub.Name {
+		t.Errorf("after Patch(description), got %+v", spoke)
+	}
+	if err := spokes.Delete(ctx, sk); err != nil {
+		t.Errorf("Spokes().Delete(%v) = %v, want nil", sk, err)
+	}
+	if err := spokes.Delete(ctx, sk); err == nil {
+		t.Errorf("Spokes().Delete(%v) = nil; want error", sk)
+	}
+}
+
+func TestGCENetworkConnectivityNotConfigured(t *testing.T) {
+	t.Parallel()
+
+	gce := NewGCE(&Service{
+		ProjectRouter: &SingleProjectRouter{"proj"},
+		RateLimiter:   &NopRateLimiter{},
+	})
+	if _, err := gce.NetworkConnectivity().Spokes().Get(context.Background(), meta.RegionalKey("s", "us-central1")); err != errNetworkConnectivityNotConfigured {
+		t.Errorf("Get() = _, %v; want %v", err, errNetworkConnectivityNotConfigured)
+	}
+}
+
+func TestGCENetworkConnectivityOperation(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/projects/proj/locations/global/hubs", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("hubId"); got != "hub" {
+			t.Errorf("hubId = %q, want hub", got)
+		}
+		if r.URL.Query().Get("requestId") == "" {
+			t.Errorf("requestId is not set")
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "projects/proj/locations/global/operations/op-1"})
+	})
+	mux.HandleFunc("/v1/projects/proj/locations/global/operations/op-1", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":  "projects/proj/locations/global/operations/op-1",
+			"done":  true,
+			"error": map[string]interface{}{"code": 6, "message": "already exists"},
+		})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := networkconnectivity.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("networkconnectivity.NewService() = _, %v, want nil", err)
+	}
+	gce := NewGCE(&Service{
+		ProjectRouter:       &SingleProjectRouter{"proj"},
+		RateLimiter:         &NopRateLimiter{},
+		NetworkConnectivity: client,
+	})
+
+	err = gce.NetworkConnectivity().Hubs().Insert(ctx, meta.GlobalKey("hub"), &networkconnectivity.Hub{})
+	var opErr *NetworkConnectivityOperationError
+	if !errors.As(err, &opErr) || opErr.Code != 6 {
+		t.Errorf("Insert() = %v, want NetworkConnectivityOperationError with code 6", err)
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	networkservices "google.golang.org/api/networkservices/v1"
+	"google.golang.org/api/option"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+func TestMockNetworkServices(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"mock-project"})
+	gateways := mock.NetworkServices().Gateways()
+
+	gk := meta.GlobalKey("gw")
+	rk := meta.RegionalKey("gw", "us-central1")
+
+	if err := gateways.Insert(ctx, gk, &networkservices.Gateway{Type: "OPEN_MESH", Ports: []int64{80}}); err != nil {
+		t.Fatalf("Insert(%v) = %v, want nil", gk, err)
+	}
+	if err := gateways.Insert(ctx, rk, &networkservices.Gateway{Type: "SECURE_WEB_GATEWAY"}); err != nil {
+		t.Fatalf("Insert(%v) = %v, want nil", rk, err)
+	}
+	if err := gateways.Insert(ctx, gk, &networkservices.Gateway{}); err == nil {
+		t.Errorf("Insert(%v) = nil, want error (exists)", gk)
+	}
+	if err := gateways.Insert(ctx, meta.ZonalKey("gw", "us-central1-a"), &networkservices.Gateway{}); err == nil {
+		t.Errorf("Insert(zonal key) = nil, want error")
+	}
+
+	gw, err := gateways.Get(ctx, gk)
+	if err != nil {
+		t.Fatalf("Get(%v) = _, %v, want nil", gk, err)
+	}
+	if want := "projects/mock-project/locations/global/gateways/gw"; gw.Name != want {
+		t.Errorf("gw.Name = %q, want %q", gw.Name, want)
+	}
+
+	for _, tc := range []struct {
+		location string
+		want     int
+	}{
+		{"global", 1},
+		{"us-central1", 1},
+		{"regions/us-central1", 1},
+		{"europe-west1", 0},
+	} {
+		objs, err := gateways.List(ctx, tc.location)
+		if err != nil || len(objs) != tc.want {
+			t.Errorf("List(%q) = %d items, %v; want %d items, nil", tc.location, len(objs), err, tc.want)
+		}
+	}
+
+	// Patch with a mask only changes the masked fields.
+	if err := gateways.Patch(ctx, gk, &networkservices.Gateway{Description: "desc", Type: "ignored"}, "description"); err != nil {
+		t.Fatalf("Patch(%v) = %v, want nil", gk, err)
+	}
+	gw, _ = gateways.Get(ctx, gk)
+	if gw.Description != "desc" || gw.Type != "OPEN_MESH" || len(gw.Ports) != 1 {
+		t.Errorf("after Patch(description), got %+v", gw)
+	}
+	if err := gateways.Patch(ctx, gk, &networkservices.Gateway{}, "noSuchField"); err == nil {
+		t.Errorf("Patch(_, _, _, noSuchField) = nil, want error")
+	}
+	if err := gateways.Patch(ctx, meta.GlobalKey("missing"), &networkservices.Gateway{}); err == nil {
+		t.Errorf("Patch(missing) = nil, want error")
+	}
+
+	if err := gateways.Delete(ctx, gk); err != nil {
+		t.Errorf("Delete(%v) = %v, want nil", gk, err)
+	}
+	if _, err := gateways.Get(ctx, gk); err == nil {
+		t.Errorf("Get(%v) = _, nil; want error after Delete()", gk)
+	}
+	if err := gateways.Delete(ctx, gk); err == nil {
+		t.Errorf("Delete(%v) = nil; want error", gk)
+	}
+}
+
+func TestGCENetworkServicesNotConfigured(t *testing.T) {
+	t.Parallel()
+
+	gce := NewGCE(&Service{
+		ProjectRouter: &SingleProjectRouter{"proj"},
+		RateLimiter:   &NopRateLimiter{},
+	})
+	if _, err := gce.NetworkServices().HTTPRoutes().Get(context.Background(), meta.GlobalKey("r")); err != errNetworkServicesNotConfigured {
+		t.Errorf("Get() = _, %v; want %v", err, errNetworkServicesNotConfigured)
+	}
+}
+
+func TestGCENetworkServicesIntercept(t *testing.T) {
+	t.Parallel()
+
+	// The gateway is only found on the second attempt.
+	var (
+		lock  sync.Mutex
+		calls int
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/projects/proj/locations/us-central1/gateways/gw", func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		calls++
+		if calls == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"name": "projects/proj/locations/us-central1/gateways/gw"}`))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := networkservices.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("networkservices.NewService() = _, %v, want nil", err)
+	}
+	var infos []*CallInfo
+	gce := NewGCE(&Service{
+		ProjectRouter:   &SingleProjectRouter{"proj"},
+		RateLimiter:     &NopRateLimiter{},
+		NetworkServices: client,
+		Retry:           &RetryPolicy{MaxAttempts: 2, InitialBackoff: 1},
+		Interceptors: []Interceptor{func(ctx context.Context, info *CallInfo, next CallHandler) (interface{}, error) {
+			infos = append(infos, info)
+			return next(ctx)
+		}},
+	})
+
+	gw, err := gce.NetworkServices().Gateways().Get(ctx, meta.RegionalKey("gw", "regions/US-Central1"))
+	if err != nil || gw.Name != "projects/proj/locations/us-central1/gateways/gw" {
+		t.Fatalf("Get() = %+v, %v; want gw, nil", gw, err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+	if len(infos) != 1 || infos[0].Service != "Gateways" || infos[0].Operation != "Get" || *infos[0].Key != *meta.RegionalKey("gw", "us-central1") {
+		t.Errorf("Interceptor calls = %+v, want one Gateways.Get with the normalized key", infos)
+	}
+
+	_, err = gce.NetworkServices().HTTPRoutes().Get(ctx, meta.GlobalKey("r"))
+	var cerr *CallError
+	if !errors.As(err, &cerr) || cerr.CallContextKey.Service != "HTTPRoutes" || cerr.Class != ErrorClassClient {
+		t.Errorf("Get() = _, %v; want a *CallError for HTTPRoutes.Get of class %v", err, ErrorClassClient)
+	}
+}
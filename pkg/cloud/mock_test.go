@@ -18,12 +18,18 @@ package cloud
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	alpha "google.golang.org/api/compute/v0.alpha"
 	beta "google.golang.org/api/compute/v0.beta"
 	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
@@ -149,3 +155,336 @@ func TestMocks(t *testing.T) {
 		t.Errorf("Addresses().Delete(%v, %v) = nil; want error", ctx, key)
 	}
 }
+
+func TestMockKeyNormalization(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"mock-project"})
+
+	urlKey := meta.RegionalKey("addr", "https://www.googleapis.com/compute/v1/projects/mock-project/regions/US-Central1")
+	shortKey := meta.RegionalKey("addr", "us-central1")
+
+	if err := mock.Addresses().Insert(ctx, urlKey, &ga.Address{}); err != nil {
+		t.Fatalf("Addresses().Insert(%v, %v, _) = %v; want nil", ctx, urlKey, err)
+	}
+	if _, err := mock.Addresses().Get(ctx, shortKey); err != nil {
+		t.Errorf("Addresses().Get(%v, %v) = _, %v; want nil", ctx, shortKey, err)
+	}
+	objs, err := mock.Addresses().List(ctx, "regions/us-central1", filter.None)
+	if err != nil || len(objs) != 1 {
+		t.Errorf("Addresses().List(%v, %q, _) = %d items, %v; want 1 item, nil", ctx, "regions/us-central1", len(objs), err)
+	}
+	if err := mock.Addresses().Delete(ctx, urlKey); err != nil {
+		t.Errorf("Addresses().Delete(%v, %v) = %v; want nil", ctx, urlKey, err)
+	}
+}
+
+func TestMockReadsReturnCopies(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"mock-project"})
+	key := meta.GlobalKey("bs")
+
+	if err := mock.BackendServices().Insert(ctx, key, &ga.BackendService{Backends: []*ga.Backend{{Group: "ig"}}}); err != nil {
+		t.Fatalf("BackendServices().Insert(%v, %v, _) = %v; want nil", ctx, key, err)
+	}
+	got, err := mock.BackendServices().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("BackendServices().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+	}
+	got.Description = "changed"
+	got.Backends[0].Group = "changed"
+	objs, err := mock.BackendServices().List(ctx, filter.None)
+	if err != nil || len(objs) != 1 {
+		t.Fatalf("BackendServices().List(%v, _) = %v, %v; want 1 item, nil", ctx, objs, err)
+	}
+	objs[0].TimeoutSec = 10
+	alphaObj, err := mock.AlphaBackendServices().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("AlphaBackendServices().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+	}
+	alphaObj.Backends[0].Group = "changed"
+	mock.MockBackendServices.Lock.Lock()
+	mock.MockBackendServices.Objects[*key].ToGA().Description = "changed"
+	mock.MockBackendServices.Lock.Unlock()
+
+	// Changing the returned objects does not change the mock.
+	bs, err := mock.BackendServices().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("BackendServices().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+	}
+	if bs.Description != "" || bs.TimeoutSec != 0 || bs.Backends[0].Group != "ig" {
+		t.Errorf("BackendServices().Get(%v, %v) = %+v; want the inserted object", ctx, key, bs)
+	}
+}
+
+func TestMockUpdate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"mock-project"})
+	key := meta.RegionalKey("router", "us-central1")
+
+	var gerr *googleapi.Error
+	if err := mock.Routers().Update(ctx, key, &ga.Router{Description: "new"}); !errors.As(err, &gerr) || gerr.Code != http.StatusNotFound {
+		t.Errorf("Routers().Update(%v, %v, _) = %v; want 404", ctx, key, err)
+	}
+	if err := mock.Routers().Insert(ctx, key, &ga.Router{Description: "old"}); err != nil {
+		t.Fatalf("Routers().Insert(%v, %v, _) = %v; want nil", ctx, key, err)
+	}
+	if err := mock.Routers().Update(ctx, key, &ga.Router{Description: "new"}); err != nil {
+		t.Fatalf("Routers().Update(%v, %v, _) = %v; want nil", ctx, key, err)
+	}
+	got, err := mock.Routers().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Routers().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+	}
+	if got.Description != "new" || got.Name != "router" {
+		t.Errorf("Routers().Get(%v, %v) = %+v; want Name=router, Description=new", ctx, key, got)
+	}
+}
+
+func TestMockSetLabels(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"mock-project"})
+	key := meta.ZonalKey("disk", "us-central1-a")
+
+	req := &ga.ZoneSetLabelsRequest{Labels: map[string]string{"owner": "a"}}
+	var gerr *googleapi.Error
+	if err := mock.Disks().SetLabels(ctx, key, req); !errors.As(err, &gerr) || gerr.Code != http.StatusNotFound {
+		t.Errorf("Disks().SetLabels(%v, %v, _) = %v; want 404", ctx, key, err)
+	}
+	if err := mock.Disks().Insert(ctx, key, &ga.Disk{}); err != nil {
+		t.Fatalf("Disks().Insert(%v, %v, _) = %v; want nil", ctx, key, err)
+	}
+	if err := mock.Disks().SetLabels(ctx, key, req); err != nil {
+		t.Fatalf("Disks().SetLabels(%v, %v, _) = %v; want nil", ctx, key, err)
+	}
+	disk, err := mock.Disks().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Disks().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+	}
+	if !reflect.DeepEqual(disk.Labels, req.Labels) || disk.LabelFingerprint == "" {
+		t.Errorf("Disks().Get(%v, %v) = %+v; want Labels = %v and a new LabelFingerprint", ctx, key, disk, req.Labels)
+	}
+
+	// Stale fingerprint is rejected.
+	stale := &ga.ZoneSetLabelsRequest{Labels: map[string]string{"owner": "b"}}
+	if err := mock.Disks().SetLabels(ctx, key, stale); !errors.As(err, &gerr) || gerr.Code != http.StatusPreconditionFailed {
+		t.Errorf("Disks().SetLabels(%v, %v, stale) = %v; want 412", ctx, key, err)
+	}
+	stale.LabelFingerprint = disk.LabelFingerprint
+	if err := mock.Disks().SetLabels(ctx, key, stale); err != nil {
+		t.Errorf("Disks().SetLabels(%v, %v, _) = %v; want nil", ctx, key, err)
+	}
+}
+
+func TestMockPatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"mock-project"})
+	key := meta.GlobalKey("bs")
+
+	if err := mock.BackendServices().Insert(ctx, key, &ga.BackendService{Description: "desc", TimeoutSec: 30}); err != nil {
+		t.Fatalf("BackendServices().Insert(%v, %v, _) = %v; want nil", ctx, key, err)
+	}
+	// Only the masked field is changed.
+	if err := mock.BackendServices().Patch(ctx, key, &ga.BackendService{Description: "ignored", TimeoutSec: 60}, "timeoutSec"); err != nil {
+		t.Fatalf("BackendServices().Patch(%v, %v, _) = %v; want nil", ctx, key, err)
+	}
+	bs, err := mock.BackendServices().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("BackendServices().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+	}
+	if bs.Description != "desc" || bs.TimeoutSec != 60 || bs.Fingerprint == "" {
+		t.Errorf("BackendServices().Get(%v, %v) = %+v; want Description=desc, TimeoutSec=60 and a Fingerprint", ctx, key, bs)
+	}
+
+	// A stale fingerprint is rejected.
+	var gerr *googleapi.Error
+	if err := mock.BackendServices().Patch(ctx, key, &ga.BackendService{Fingerprint: "stale", TimeoutSec: 10}); !errors.As(err, &gerr) || gerr.Code != http.StatusPreconditionFailed {
+		t.Errorf("BackendServices().Patch(%v, %v, stale) = %v; want 412", ctx, key, err)
+	}
+	if err := mock.BackendServices().Patch(ctx, meta.GlobalKey("missing"), &ga.BackendService{}); !errors.As(err, &gerr) || gerr.Code != http.StatusNotFound {
+		t.Errorf("BackendServices().Patch(missing) = %v; want 404", err)
+	}
+}
+
+func TestMockAggregatedList(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"mock-project"})
+
+	for _, key := range []*meta.Key{meta.GlobalKey("hc-1"), meta.GlobalKey("hc-2")} {
+		if err := mock.HealthChecks().Insert(ctx, key, &ga.HealthCheck{}); err != nil {
+			t.Fatalf("HealthChecks().Insert(%v, %v, _) = %v; want nil", ctx, key, err)
+		}
+	}
+	for _, key := range []*meta.Key{meta.ZonalKey("disk-1", "us-central1-a"), meta.ZonalKey("disk-2", "us-central1-b")} {
+		if err := mock.Disks().Insert(ctx, key, &ga.Disk{}); err != nil {
+			t.Fatalf("Disks().Insert(%v, %v, _) = %v; want nil", ctx, key, err)
+		}
+	}
+	key := meta.RegionalKey("fr", "us-central1")
+	if err := mock.ForwardingRules().Insert(ctx, key, &ga.ForwardingRule{}); err != nil {
+		t.Fatalf("ForwardingRules().Insert(%v, %v, _) = %v; want nil", ctx, key, err)
+	}
+
+	hcs, err := mock.HealthChecks().AggregatedList(ctx, filter.Regexp("name", "hc-1"))
+	if err != nil || len(hcs) != 1 || len(hcs["global"]) != 1 || hcs["global"][0].Name != "hc-1" {
+		t.Errorf("HealthChecks().AggregatedList(%v, name=hc-1) = %v, %v; want {global: [hc-1]}, nil", ctx, hcs, err)
+	}
+	disks, err := mock.Disks().AggregatedList(ctx, filter.None)
+	got := map[string][]string{}
+	for scope, objs := range disks {
+		for _, o := range objs {
+			got[scope] = append(got[scope], o.Name)
+		}
+	}
+	want := map[string][]string{"zones/us-central1-a": {"disk-1"}, "zones/us-central1-b": {"disk-2"}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Disks().AggregatedList(%v, _) = %v, %v; want %v, nil", ctx, got, err, want)
+	}
+	// Objects are visible in the aggregated list of all versions.
+	frs, err := mock.AlphaForwardingRules().AggregatedList(ctx, filter.None)
+	if err != nil || len(frs["regions/us-central1"]) != 1 {
+		t.Errorf("AlphaForwardingRules().AggregatedList(%v, _) = %v, %v; want {regions/us-central1: [fr]}, nil", ctx, frs, err)
+	}
+}
+
+func TestMockMethodError(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"mock-project"})
+	key := meta.RegionalKey("tp", "us-central1")
+	ref := &ga.TargetReference{Target: "backup"}
+
+	if err := mock.TargetPools().SetBackup(ctx, key, ref); err != nil {
+		t.Errorf("TargetPools().SetBackup(%v, %v, _) = %v; want nil", ctx, key, err)
+	}
+	injected := &googleapi.Error{Code: http.StatusBadRequest}
+	mock.MockTargetPools.SetBackupError[*key] = injected
+	if err := mock.TargetPools().SetBackup(ctx, key, ref); err != injected {
+		t.Errorf("TargetPools().SetBackup(%v, %v, _) = %v; want %v", ctx, key, err, injected)
+	}
+	// The hook takes precedence over the error.
+	mock.MockTargetPools.SetBackupHook = func(context.Context, *meta.Key, *ga.TargetReference, *MockTargetPools) error { return nil }
+	if err := mock.TargetPools().SetBackup(ctx, key, ref); err != nil {
+		t.Errorf("TargetPools().SetBackup(%v, %v, _) = %v; want nil", ctx, key, err)
+	}
+}
+
+func TestMockRegionSslPolicies(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"mock-project"})
+	policyKey := meta.RegionalKey("policy", "us-central1")
+	proxyKey := meta.RegionalKey("proxy", "us-central1")
+
+	if err := mock.RegionSslPolicies().Insert(ctx, policyKey, &ga.SslPolicy{Profile: "MODERN"}); err != nil {
+		t.Fatalf("RegionSslPolicies().Insert(%v, %v, _) = %v; want nil", ctx, policyKey, err)
+	}
+	policies, err := mock.RegionSslPolicies().List(ctx, "us-central1", filter.None)
+	if err != nil || len(policies) != 1 {
+		t.Errorf("RegionSslPolicies().List(%v, us-central1, _) = %d items, %v; want 1 item, nil", ctx, len(policies), err)
+	}
+	if err := mock.RegionSslPolicies().Patch(ctx, policyKey, &ga.SslPolicy{MinTlsVersion: "TLS_1_2"}); err != nil {
+		t.Fatalf("RegionSslPolicies().Patch(%v, %v, _) = %v; want nil", ctx, policyKey, err)
+	}
+	policy, err := mock.RegionSslPolicies().Get(ctx, policyKey)
+	if err != nil || policy.Profile != "MODERN" || policy.MinTlsVersion != "TLS_1_2" {
+		t.Errorf("RegionSslPolicies().Get(%v, %v) = %+v, %v; want Profile=MODERN, MinTlsVersion=TLS_1_2", ctx, policyKey, policy, err)
+	}
+
+	// Regional proxies have no setSslPolicy verb; the policy is set with Patch.
+	if err := mock.RegionTargetHttpsProxies().Insert(ctx, proxyKey, &ga.TargetHttpsProxy{}); err != nil {
+		t.Fatalf("RegionTargetHttpsProxies().Insert(%v, %v, _) = %v; want nil", ctx, proxyKey, err)
+	}
+	if err := mock.RegionTargetHttpsProxies().Patch(ctx, proxyKey, &ga.TargetHttpsProxy{SslPolicy: policy.SelfLink}, "sslPolicy"); err != nil {
+		t.Fatalf("RegionTargetHttpsProxies().Patch(%v, %v, _) = %v; want nil", ctx, proxyKey, err)
+	}
+	proxy, err := mock.RegionTargetHttpsProxies().Get(ctx, proxyKey)
+	if err != nil || proxy.SslPolicy != policy.SelfLink {
+		t.Errorf("RegionTargetHttpsProxies().Get(%v, %v) = %+v, %v; want SslPolicy=%q", ctx, proxyKey, proxy, err, policy.SelfLink)
+	}
+}
+
+// fakeAddresses implements only the methods used by the code under test.
+type fakeAddresses struct {
+	Addresses
+	got *meta.Key
+}
+
+func (f *fakeAddresses) Get(ctx context.Context, key *meta.Key) (*ga.Address, error) {
+	f.got = key
+	return &ga.Address{Name: key.Name}, nil
+}
+
+type fakeAddressesProvider struct{ f *fakeAddresses }
+
+func (p fakeAddressesProvider) Addresses() Addresses { return p.f }
+
+func TestResourceProviders(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"mock-project"})
+	var (
+		_ AddressesProvider            = mock
+		_ AlphaForwardingRulesProvider = mock
+		_ NetworkServicesProvider      = mock
+		_ NetworkConnectivityProvider  = mock
+	)
+	if got := NewAlphaForwardingRules(mock); got != mock.AlphaForwardingRules() {
+		t.Errorf("NewAlphaForwardingRules(mock) = %v, want %v", got, mock.AlphaForwardingRules())
+	}
+
+	// A consumer that depends on AddressesProvider can be given a tiny fake.
+	getAddress := func(p AddressesProvider, key *meta.Key) (*ga.Address, error) {
+		return NewAddresses(p).Get(ctx, key)
+	}
+	fake := &fakeAddresses{}
+	key := meta.RegionalKey("addr", "us-central1")
+	if addr, err := getAddress(fakeAddressesProvider{fake}, key); err != nil || addr.Name != "addr" || fake.got != key {
+		t.Errorf("getAddress(fake, %v) = %+v, %v; want addr, nil", key, addr, err)
+	}
+}
+
+func TestGCEKeyNormalization(t *testing.T) {
+	t.Parallel()
+
+	// The GCE wrappers accept the same keys as the mocks.
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`{"name": "addr"}`))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+	c := NewGCE(&Service{GA: client, ProjectRouter: &SingleProjectRouter{"proj"}, RateLimiter: &NopRateLimiter{}})
+
+	urlKey := meta.RegionalKey("addr", "https://www.googleapis.com/compute/v1/projects/proj/regions/US-Central1")
+	if _, err := c.Addresses().Get(ctx, urlKey); err != nil {
+		t.Errorf("Addresses().Get(%v) = _, %v; want nil", urlKey, err)
+	}
+	if _, err := c.Addresses().List(ctx, "regions/us-central1", filter.None); err != nil {
+		t.Errorf("Addresses().List(%q) = _, %v; want nil", "regions/us-central1", err)
+	}
+	want := []string{"/projects/proj/regions/us-central1/addresses/addr", "/projects/proj/regions/us-central1/addresses"}
+	if diff := cmp.Diff(want, paths); diff != "" {
+		t.Errorf("request paths: -want +got: %s", diff)
+	}
+}
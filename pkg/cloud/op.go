@@ -110,7 +110,7 @@ func (o *gaOperation) isDone(ctx context.Context) (bool, error) {
 
 	if op.Error != nil && len(op.Error.Errors) > 0 && op.Error.Errors[0] != nil {
 		e := op.Error.Errors[0]
-		o.err = &googleapi.Error{Code: int(op.HttpErrorStatusCode), Message: fmt.Sprintf("%v - %v", e.Code, e.Message)}
+		o.err = &googleapi.Error{Code: int(op.HttpErrorStatusCode), Message: fmt.Sprintf("%v - %v", e.Code, e.Message), Details: operationErrorDetails(e.ErrorDetails)}
 	}
 	return true, nil
 }
@@ -184,7 +184,7 @@ func (o *alphaOperation) isDone(ctx context.Context) (bool, error) {
 
 	if op.Error != nil && len(op.Error.Errors) > 0 && op.Error.Errors[0] != nil {
 		e := op.Error.Errors[0]
-		o.err = &googleapi.Error{Code: int(op.HttpErrorStatusCode), Message: fmt.Sprintf("%v - %v", e.Code, e.Message)}
+		o.err = &googleapi.Error{Code: int(op.HttpErrorStatusCode), Message: fmt.Sprintf("%v - %v", e.Code, e.Message), Details: operationErrorDetails(e.ErrorDetails)}
 	}
 	return true, nil
 }
@@ -257,7 +257,7 @@ func (o *betaOperation) isDone(ctx context.Context) (bool, error) {
 
 	if op.Error != nil && len(op.Error.Errors) > 0 && op.Error.Errors[0] != nil {
 		e := op.Error.Errors[0]
-		o.err = &googleapi.Error{Code: int(op.HttpErrorStatusCode), Message: fmt.Sprintf("%v - %v", e.Code, e.Message)}
+		o.err = &googleapi.Error{Code: int(op.HttpErrorStatusCode), Message: fmt.Sprintf("%v - %v", e.Code, e.Message), Details: operationErrorDetails(e.ErrorDetails)}
 	}
 	return true, nil
 }
@@ -274,3 +274,16 @@ func (o *betaOperation) rateLimitKey() *RateLimitKey {
 func (o *betaOperation) error() error {
 	return o.err
 }
+
+// operationErrorDetails returns the details of the error of an operation
+// (e.g. the QuotaExceededInfo) as the Details of a googleapi.Error, so that
+// gcerrors.From parses them.
+func operationErrorDetails[T any](details []*T) []interface{} {
+	var ret []interface{}
+	for _, d := range details {
+		if d != nil {
+			ret = append(ret, d)
+		}
+	}
+	return ret
+}
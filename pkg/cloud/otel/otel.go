@@ -0,0 +1,91 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package otel traces the calls to the API with OpenTelemetry.
+//
+//	svc := &cloud.Service{
+//		...
+//		Tracer: otel.NewTracer(tp),
+//	}
+//
+// Each call is a client Span with the cloud.Attribute* attributes. The
+// Span of a failed call has the error recorded and the status codes.Error.
+package otel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation name of the Tracer created by
+// NewTracer.
+const TracerName = "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+
+// NewTracer returns a cloud.Tracer creating the spans with the Tracer
+// TracerName of tp.
+func NewTracer(tp trace.TracerProvider) cloud.Tracer {
+	return &tracer{t: tp.Tracer(TracerName)}
+}
+
+type tracer struct {
+	t trace.Tracer
+}
+
+// Start implements cloud.Tracer.
+func (t *tracer) Start(ctx context.Context, name string, attrs ...cloud.Attribute) (context.Context, cloud.Span) {
+	ctx, s := t.t.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(keyValues(attrs)...))
+	return ctx, &span{s: s}
+}
+
+type span struct {
+	s trace.Span
+}
+
+// SetAttributes implements cloud.Span.
+func (s *span) SetAttributes(attrs ...cloud.Attribute) {
+	s.s.SetAttributes(keyValues(attrs)...)
+}
+
+// End implements cloud.Span.
+func (s *span) End(err error) {
+	if err != nil {
+		s.s.RecordError(err)
+		s.s.SetStatus(codes.Error, err.Error())
+	}
+	s.s.End()
+}
+
+// keyValues converts attrs to OpenTelemetry attributes. Values that are
+// neither a string nor an int are formatted with %v.
+func keyValues(attrs []cloud.Attribute) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		switch v := a.Value.(type) {
+		case string:
+			kvs = append(kvs, attribute.String(a.Key, v))
+		case int:
+			kvs = append(kvs, attribute.Int(a.Key, v))
+		default:
+			kvs = append(kvs, attribute.String(a.Key, fmt.Sprint(v)))
+		}
+	}
+	return kvs
+}
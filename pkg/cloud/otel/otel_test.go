@@ -0,0 +1,86 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracer(t *testing.T) {
+	t.Parallel()
+
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	tracer := NewTracer(tp)
+
+	ctx, span := tracer.Start(context.Background(), "Addresses.Get",
+		cloud.Attribute{Key: cloud.AttributeService, Value: "Addresses"},
+		cloud.Attribute{Key: cloud.AttributeHTTPStatus, Value: 404},
+	)
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		t.Errorf("Start() context has no span")
+	}
+	span.SetAttributes(cloud.Attribute{Key: cloud.AttributeOperationPolls, Value: 2})
+	span.End(errors.New("not found"))
+
+	_, span = tracer.Start(context.Background(), "Addresses.List")
+	span.End(nil)
+
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("len(spans) = %d, want 2", len(spans))
+	}
+	failed, ok := spans[0], spans[1]
+
+	if failed.Name() != "Addresses.Get" || failed.SpanKind() != trace.SpanKindClient {
+		t.Errorf("span = %q %v, want Addresses.Get %v", failed.Name(), failed.SpanKind(), trace.SpanKindClient)
+	}
+	if got := failed.InstrumentationScope().Name; got != TracerName {
+		t.Errorf("InstrumentationScope().Name = %q, want %q", got, TracerName)
+	}
+	wantAttrs := []attribute.KeyValue{
+		attribute.String(cloud.AttributeService, "Addresses"),
+		attribute.Int(cloud.AttributeHTTPStatus, 404),
+		attribute.Int(cloud.AttributeOperationPolls, 2),
+	}
+	if diff := cmp.Diff(wantAttrs, failed.Attributes(), cmp.Comparer(func(a, b attribute.KeyValue) bool { return a == b })); diff != "" {
+		t.Errorf("Attributes() diff -want +got: %s", diff)
+	}
+	if got := failed.Status(); got.Code != codes.Error || got.Description != "not found" {
+		t.Errorf("Status() = %+v, want Error not found", got)
+	}
+	if got := len(failed.Events()); got != 1 {
+		t.Errorf("len(Events()) = %d, want 1 for the recorded error", got)
+	}
+
+	if got := ok.Status().Code; got != codes.Unset {
+		t.Errorf("Status().Code = %v, want %v", got, codes.Unset)
+	}
+	if got := len(ok.Events()); got != 0 {
+		t.Errorf("len(Events()) = %d, want 0", got)
+	}
+}
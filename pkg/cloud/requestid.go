@@ -0,0 +1,97 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+)
+
+var (
+	requestIDContextKey = contextKey("request id")
+	// callRequestIDContextKey is the requestId of the call in progress,
+	// sent by all of its attempts. It is set by intercept.
+	callRequestIDContextKey = contextKey("call request id")
+	// sentRequestIDContextKey is the requestId sent by the last attempt of
+	// the call, for its CallError. It is set by intercept.
+	sentRequestIDContextKey = contextKey("sent request id")
+)
+
+// WithRequestID sets the requestId sent with the next mutating call made
+// with ctx. The server ignores a request with the same requestId as one it
+// has already completed, so a caller that retries a call with the same id
+// does not create the resource twice.
+//
+// The id is used by the first mutating call made with ctx only, including
+// all of its retries. Later calls with ctx generate a new id, as reusing it
+// would make the server drop them as duplicates of the first call.
+//
+// If no id is set, a new one is generated for each call.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, &pinnedRequestID{id: id})
+}
+
+// pinnedRequestID is the id set by WithRequestID.
+type pinnedRequestID struct {
+	id   string
+	used atomic.Bool
+}
+
+// withCallRequestID returns ctx with the requestId of a new mutating call:
+// the id set by WithRequestID if no call has used it yet, or a new one.
+func withCallRequestID(ctx context.Context) context.Context {
+	var id string
+	if p, ok := ctx.Value(requestIDContextKey).(*pinnedRequestID); ok && p.used.CompareAndSwap(false, true) {
+		id = p.id
+	}
+	if id == "" {
+		id = uuid.New().String()
+	}
+	return context.WithValue(ctx, callRequestIDContextKey, id)
+}
+
+// RequestID returns the requestId of the call in progress with ctx, e.g.
+// from a CallObserver. It returns "" if the call did not send a requestId.
+func RequestID(ctx context.Context) string {
+	if state, ok := ctx.Value(callStateContextKey).(*callState); ok {
+		return state.requestID
+	}
+	return ""
+}
+
+// callRequestID returns the requestId to send with the call in ctx,
+// generating one if the call has none (see withCallRequestID).
+func callRequestID(ctx context.Context) string {
+	id, _ := ctx.Value(callRequestIDContextKey).(string)
+	if id == "" {
+		id = uuid.New().String()
+	}
+	if sent, ok := ctx.Value(sentRequestIDContextKey).(*string); ok {
+		*sent = id
+	}
+	state, ok := ctx.Value(callStateContextKey).(*callState)
+	if !ok {
+		return id
+	}
+	state.requestID = id
+	if state.span != nil {
+		state.span.SetAttributes(Attribute{Key: AttributeRequestID, Value: id})
+	}
+	return id
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+)
+
+type requestIDObserver struct {
+	ids []string
+}
+
+func (o *requestIDObserver) Start(ctx context.Context, key *CallContextKey) {}
+func (o *requestIDObserver) End(ctx context.Context, key *CallContextKey, err error) {
+	o.ids = append(o.ids, RequestID(ctx))
+}
+
+func TestRequestID(t *testing.T) {
+	t.Parallel()
+
+	var lock sync.Mutex
+	var sent []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/proj/global/addresses", func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		sent = append(sent, r.URL.Query().Get("requestId"))
+		lock.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":     "op-1",
+			"status":   "DONE",
+			"selfLink": "https://www.googleapis.com/compute/v1/projects/proj/global/operations/op-1",
+		})
+	})
+	mux.HandleFunc("/projects/proj/global/addresses/a1", func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		sent = append(sent, r.URL.Query().Get("requestId"))
+		lock.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "a1"})
+	})
+	mux.HandleFunc("/projects/proj/global/operations/op-1/wait", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "op-1", "status": "DONE"})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+	c := NewGCE(&Service{
+		GA:            client,
+		ProjectRouter: &SingleProjectRouter{"proj"},
+		RateLimiter:   &NopRateLimiter{},
+	})
+	obs := &requestIDObserver{}
+	ctx = WithCallObserver(ctx, obs)
+	key := meta.GlobalKey("a1")
+
+	for i := 0; i < 2; i++ {
+		if err := c.GlobalAddresses().Insert(ctx, key, &ga.Address{}); err != nil {
+			t.Fatalf("Insert(%v) = %v, want nil", key, err)
+		}
+	}
+	pinned := WithRequestID(ctx, "4f5c0d52-0e6e-4f6b-8ef4-6d0b1c1c2b2a")
+	for i := 0; i < 2; i++ {
+		if err := c.GlobalAddresses().Insert(pinned, key, &ga.Address{}); err != nil {
+			t.Fatalf("Insert(%v) = %v, want nil", key, err)
+		}
+	}
+	if _, err := c.GlobalAddresses().Get(ctx, key); err != nil {
+		t.Fatalf("Get(%v) = _, %v, want nil", key, err)
+	}
+
+	if len(sent) != 5 {
+		t.Fatalf("got %d requests, want 5", len(sent))
+	}
+	if sent[0] == "" || sent[1] == "" || sent[0] == sent[1] {
+		t.Errorf("generated requestIds = %q, %q; want two different ids", sent[0], sent[1])
+	}
+	if sent[2] != "4f5c0d52-0e6e-4f6b-8ef4-6d0b1c1c2b2a" {
+		t.Errorf("requestId = %q, want the id from WithRequestID()", sent[2])
+	}
+	// The id is only used by the first call made with the context.
+	if sent[3] == "" || sent[3] == sent[2] {
+		t.Errorf("requestId of the second call = %q, want a new id", sent[3])
+	}
+	if sent[4] != "" {
+		t.Errorf("Get() sent requestId %q, want none", sent[4])
+	}
+	for i, id := range obs.ids {
+		if id != sent[i] {
+			t.Errorf("RequestID() in the CallObserver for call %d = %q, want %q", i, id, sent[i])
+		}
+	}
+}
@@ -0,0 +1,342 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/gcerrors"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/uuid"
+	"k8s.io/klog/v2"
+)
+
+// Default backoffs of a RetryPolicy.
+const (
+	DefaultRetryInitialBackoff  = time.Second
+	DefaultRetryMaxBackoff      = 30 * time.Second
+	DefaultRetryNotReadyBackoff = 5 * time.Second
+)
+
+// RetryPolicy retries the calls that fail with a transient error: a 5xx
+// error, a 429 or an error connecting to the API. Only the calls that are
+// safe to repeat are retried, see meta.Idempotency:
+//
+//   - Idempotent calls (e.g. Get, Delete) are repeated as is. A Delete
+//     that fails with 404 after an earlier attempt succeeds.
+//   - IdempotentWithRequestID calls (e.g. Insert) are repeated with the
+//     requestId of the first attempt, so the server applies them once.
+//   - NotIdempotent calls are not retried.
+//
+// The errors classified by an ErrorRule are retried (or not) as the rule
+// says, see RegisterErrorRule.
+//
+// Each attempt is a separate call: it is accepted by the RateLimiter,
+// traced and logged, so retries consume rate limit tokens like any other
+// call. The Interceptors see a single call.
+//
+// The policy of the calls is Service.Retry, or the one set with
+// WithRetryPolicy for the calls made with a context:
+//
+//	svc.Retry = &cloud.RetryPolicy{MaxAttempts: 4}
+type RetryPolicy struct {
+	// MaxAttempts of a call, including the first one. Values below 2
+	// disable retries.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry. The wait doubles
+	// after each retry, up to MaxBackoff. Zero values use
+	// DefaultRetryInitialBackoff and DefaultRetryMaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Jitter randomizes each wait by up to this fraction of it, e.g. 0.2
+	// waits between 80% and 120% of the backoff.
+	Jitter float64
+	// WaitForConflicts makes a mutation that fails with 409 because
+	// another operation on the resource is running wait for that
+	// operation, found by listing the running operations of the resource,
+	// and try again once. This is independent of MaxAttempts.
+	WaitForConflicts bool
+	// RetryNotReady retries, up to MaxAttempts, the calls that fail
+	// because a resource they depend on is not ready or because the
+	// resource is used by another one (see gcerrors.IsResourceNotReady and
+	// gcerrors.IsInUse). These calls are rejected, so they are retried
+	// whatever their Idempotency. They are retried after at least
+	// NotReadyBackoff (DefaultRetryNotReadyBackoff if zero), as the
+	// other resource takes a while to change.
+	RetryNotReady   bool
+	NotReadyBackoff time.Duration
+	// RefreshOnConflict retries, up to MaxAttempts, the mutations that fail
+	// with 412 Precondition Failed because the fingerprint of the object
+	// is stale. Before each retry, the refresh function of the context
+	// (see WithRefresh) updates the object of the call. The mutations made
+	// without one are not retried.
+	RefreshOnConflict bool
+}
+
+// backoff returns the wait before retry n, starting at 1.
+func (p *RetryPolicy) backoff(n int) time.Duration {
+	d, max := p.InitialBackoff, p.MaxBackoff
+	if d <= 0 {
+		d = DefaultRetryInitialBackoff
+	}
+	if max <= 0 {
+		max = DefaultRetryMaxBackoff
+	}
+	for i := 1; i < n && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	if p.Jitter > 0 {
+		d += time.Duration(p.Jitter * (2*rand.Float64() - 1) * float64(d))
+	}
+	return d
+}
+
+// isRetriable is true if err is a transient error.
+func isRetriable(err error) bool {
+	return gcerrors.IsServerError(err) ||
+		gcerrors.Code(err) == http.StatusTooManyRequests ||
+		ClassifyError(err) == ErrorClassNetwork
+}
+
+// isNotReady is true if err is retriable with RetryNotReady.
+func isNotReady(err error) bool {
+	return gcerrors.IsResourceNotReady(err) || gcerrors.IsInUse(err)
+}
+
+var (
+	idempotencyLock sync.Mutex
+	idempotencies   map[idempotencyKey]meta.Idempotency
+)
+
+type idempotencyKey struct {
+	service, operation string
+	version            meta.Version
+}
+
+// callIdempotency returns the Idempotency of the call from meta.
+func callIdempotency(info *CallInfo) meta.Idempotency {
+	k := idempotencyKey{service: info.Service, operation: info.Operation, version: info.Version}
+	idempotencyLock.Lock()
+	defer idempotencyLock.Unlock()
+
+	if idem, ok := idempotencies[k]; ok {
+		return idem
+	}
+	if idempotencies == nil {
+		idempotencies = map[idempotencyKey]meta.Idempotency{}
+	}
+	// Services that are not in meta (e.g. the ones called with gRPC) are
+	// classified by their verb alone.
+	idem := meta.VerbIdempotency(info.Operation, false)
+	for _, s := range meta.AllServices {
+		if s.Service == info.Service && s.Version() == info.Version {
+			idem = s.Idempotency(info.Operation)
+			break
+		}
+	}
+	for _, s := range meta.AllLocationServices {
+		if s.Service == info.Service && info.Version == meta.VersionGA {
+			idem = s.Idempotency(info.Operation)
+			break
+		}
+	}
+	idempotencies[k] = idem
+	return idem
+}
+
+// withRetry returns call retried with the RetryPolicy of the context (see
+// WithRetryPolicy) or else the one of s.
+func withRetry[T any](s *Service, info *CallInfo, call func(context.Context) (T, error)) func(context.Context) (T, error) {
+	return func(ctx context.Context) (T, error) {
+		p := s.Retry
+		if cp, ok := ctx.Value(retryPolicyContextKey).(*RetryPolicy); ok {
+			p = cp
+		}
+		if p == nil {
+			return call(ctx)
+		}
+		retry := p.MaxAttempts >= 2 && callIdempotency(info) != meta.NotIdempotent
+		notReady := p.MaxAttempts >= 2 && p.RetryNotReady
+		conflicts := p.WaitForConflicts && IsMutation(info.Operation) && info.Key != nil
+		refresh := p.MaxAttempts >= 2 && p.RefreshOnConflict && IsMutation(info.Operation)
+		if !retry && !notReady && !conflicts && !refresh {
+			return call(ctx)
+		}
+		// All the attempts of a mutation send the requestId set by
+		// intercept.
+		waited := false
+		for attempt := 1; ; {
+			v, err := call(ctx)
+			if attempt > 1 && info.Operation == "Delete" && gcerrors.IsNotFound(err) {
+				klog.V(2).Infof("%s.%s(%v): deleted by an earlier attempt", info.Service, info.Operation, info.Key)
+				return v, nil
+			}
+			// The call was rejected, so it is safe to repeat whatever its
+			// Idempotency.
+			if conflicts && !waited && isOperationConflict(err) {
+				waited = true
+				if werr := s.waitForConflictingOperation(ctx, info); werr != nil {
+					klog.V(2).Infof("%s.%s(%v): cannot wait for the conflicting operation: %v", info.Service, info.Operation, info.Key, werr)
+					return v, err
+				}
+				continue
+			}
+			if err == nil || attempt >= p.MaxAttempts {
+				return v, err
+			}
+			d := p.backoff(attempt)
+			var refreshFn func(context.Context) error
+			ruleRetriable, ruleOK := errorRule(err, &info.CallContextKey)
+			switch {
+			case ruleOK && !ruleRetriable:
+				return v, err
+			case ruleOK:
+				if !retry {
+					return v, err
+				}
+			case refresh && gcerrors.IsPreconditionFailed(err):
+				if refreshFn = refreshFromContext(ctx); refreshFn == nil {
+					return v, err
+				}
+			case retry && isRetriable(err):
+			case notReady && isNotReady(err):
+				min := p.NotReadyBackoff
+				if min <= 0 {
+					min = DefaultRetryNotReadyBackoff
+				}
+				if d < min {
+					d = min
+				}
+			default:
+				return v, err
+			}
+			if b := retryBudgetFromContext(ctx); b != nil && !b.take(d) {
+				klog.V(2).Infof("%s.%s(%v): retry budget exhausted after attempt %d: %v", info.Service, info.Operation, info.Key, attempt, err)
+				return v, err
+			}
+			klog.V(2).Infof("%s.%s(%v): attempt %d failed, retrying in %v: %v", info.Service, info.Operation, info.Key, attempt, d, err)
+			t := time.NewTimer(d)
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				t.Stop()
+				return v, err
+			}
+			if refreshFn != nil {
+				if rerr := refreshFn(ctx); rerr != nil {
+					klog.V(2).Infof("%s.%s(%v): cannot refresh the object: %v", info.Service, info.Operation, info.Key, rerr)
+					return v, err
+				}
+				// The object changed, so this is a new request.
+				ctx = context.WithValue(ctx, callRequestIDContextKey, uuid.New().String())
+			}
+			attempt++
+		}
+	}
+}
+
+var retryPolicyContextKey = contextKey("retry policy")
+
+// WithRetryPolicy makes the calls made with ctx use p instead of
+// Service.Retry, e.g. to not retry a best-effort cleanup or to retry a
+// call more than the others. A nil p disables the retries.
+//
+//	err := gce.Addresses().Delete(cloud.WithRetryPolicy(ctx, nil), key)
+func WithRetryPolicy(ctx context.Context, p *RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyContextKey, p)
+}
+
+var refreshContextKey = contextKey("refresh")
+
+// WithRefresh sets the function that updates the object of the mutations
+// made with ctx when they fail with a stale fingerprint, see
+// RetryPolicy.RefreshOnConflict. Refresh returns such a function from the
+// Get of the resource:
+//
+//	ctx = cloud.WithRefresh(ctx, cloud.Refresh(gce.BackendServices().Get, key, bs, mutate))
+//	err := gce.BackendServices().Update(ctx, key, bs)
+func WithRefresh(ctx context.Context, refresh func(context.Context) error) context.Context {
+	return context.WithValue(ctx, refreshContextKey, refresh)
+}
+
+func refreshFromContext(ctx context.Context) func(context.Context) error {
+	f, _ := ctx.Value(refreshContextKey).(func(context.Context) error)
+	return f
+}
+
+var retryBudgetContextKey = contextKey("retry budget")
+
+// RetryBudget bounds the retries of all the calls made with a context, e.g.
+// the calls of one reconcile, so that a resource that keeps failing does
+// not hold a worker for minutes of compounding backoffs. A call that
+// would exceed the budget returns its last error instead of retrying.
+//
+//	ctx = cloud.WithRetryBudget(ctx, cloud.NewRetryBudget(10, time.Minute))
+//	// All the calls made with ctx share 10 retries and 1 minute of backoff.
+type RetryBudget struct {
+	maxRetries int
+	maxWait    time.Duration
+
+	lock    sync.Mutex
+	retries int
+	wait    time.Duration
+}
+
+// NewRetryBudget returns a RetryBudget of retries retries and wait total
+// backoff. A zero value is not limited.
+func NewRetryBudget(retries int, wait time.Duration) *RetryBudget {
+	return &RetryBudget{maxRetries: retries, maxWait: wait}
+}
+
+// WithRetryBudget makes the calls made with ctx share b.
+func WithRetryBudget(ctx context.Context, b *RetryBudget) context.Context {
+	return context.WithValue(ctx, retryBudgetContextKey, b)
+}
+
+func retryBudgetFromContext(ctx context.Context) *RetryBudget {
+	b, _ := ctx.Value(retryBudgetContextKey).(*RetryBudget)
+	return b
+}
+
+// Used returns the retries and the total backoff used so far.
+func (b *RetryBudget) Used() (int, time.Duration) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.retries, b.wait
+}
+
+// take a retry after a backoff of d from the budget. It returns false if
+// there is not enough budget left.
+func (b *RetryBudget) take(d time.Duration) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if (b.maxRetries > 0 && b.retries >= b.maxRetries) || (b.maxWait > 0 && b.wait+d > b.maxWait) {
+		return false
+	}
+	b.retries++
+	b.wait += d
+	return true
+}
@@ -0,0 +1,542 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+)
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	// The server answers the calls on the resource with the statuses of
+	// the test case in turn, then with success, and records their
+	// requestIds.
+	var (
+		lock       sync.Mutex
+		statuses   []int
+		requestIDs []string
+	)
+	const op = `{"name": "op-1", "status": "DONE", "selfLink": "https://www.googleapis.com/compute/v1/projects/proj/global/operations/op-1"}`
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/proj/global/operations/op-1/wait", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(op))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		requestIDs = append(requestIDs, r.URL.Query().Get("requestId"))
+		if len(statuses) > 0 {
+			status := statuses[0]
+			statuses = statuses[1:]
+			http.Error(w, "failed", status)
+			return
+		}
+		w.Write([]byte(op))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+
+	key := meta.GlobalKey("bs")
+	for _, tc := range []struct {
+		desc     string
+		statuses []int
+		call     func(c Cloud) error
+		wantReqs int
+		// wantAccepts is the number of calls accepted by the
+		// RateLimiter, if it is not wantReqs.
+		wantAccepts int
+		wantErr     bool
+	}{
+		{
+			desc:     "get retried until success",
+			statuses: []int{http.StatusServiceUnavailable, http.StatusBadGateway},
+			call:     func(c Cloud) error { _, err := c.BackendServices().Get(ctx, key); return err },
+			wantReqs: 3,
+		},
+		{
+			desc:     "429 retried",
+			statuses: []int{http.StatusTooManyRequests},
+			call: func(c Cloud) error {
+				_, err := c.BackendServices().GetHealth(ctx, key, &ga.ResourceGroupReference{})
+				return err
+			},
+			wantReqs: 2,
+		},
+		{
+			desc:     "attempts exhausted",
+			statuses: []int{500, 500, 500, 500},
+			call:     func(c Cloud) error { _, err := c.BackendServices().Get(ctx, key); return err },
+			wantReqs: 3,
+			wantErr:  true,
+		},
+		{
+			desc:     "not found not retried",
+			statuses: []int{http.StatusNotFound},
+			call:     func(c Cloud) error { _, err := c.BackendServices().Get(ctx, key); return err },
+			wantReqs: 1,
+			wantErr:  true,
+		},
+		{
+			desc:     "insert retried",
+			statuses: []int{http.StatusServiceUnavailable},
+			call:     func(c Cloud) error { return c.BackendServices().Insert(ctx, key, &ga.BackendService{}) },
+			wantReqs: 2,
+			// The wait for the operation.
+			wantAccepts: 3,
+		},
+		{
+			desc:     "delete deleted by the first attempt",
+			statuses: []int{http.StatusServiceUnavailable, http.StatusNotFound},
+			call:     func(c Cloud) error { return c.BackendServices().Delete(ctx, key) },
+			wantReqs: 2,
+		},
+		{
+			desc:     "delete not found",
+			statuses: []int{http.StatusNotFound},
+			call:     func(c Cloud) error { return c.BackendServices().Delete(ctx, key) },
+			wantReqs: 1,
+			wantErr:  true,
+		},
+	} {
+		lock.Lock()
+		statuses, requestIDs = tc.statuses, nil
+		lock.Unlock()
+		rl := &countingRateLimiter{}
+		c := NewGCE(&Service{
+			GA:            client,
+			ProjectRouter: &SingleProjectRouter{"proj"},
+			RateLimiter:   rl,
+			Retry:         &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+		})
+		err := tc.call(c)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("%s: err = %v, want error %t", tc.desc, err, tc.wantErr)
+		}
+		lock.Lock()
+		if len(requestIDs) != tc.wantReqs {
+			t.Errorf("%s: got %d requests, want %d", tc.desc, len(requestIDs), tc.wantReqs)
+		}
+		for _, id := range requestIDs {
+			if id != requestIDs[0] {
+				t.Errorf("%s: requestIds = %v, want the same requestId for all attempts", tc.desc, requestIDs)
+				break
+			}
+		}
+		lock.Unlock()
+		wantAccepts := tc.wantAccepts
+		if wantAccepts == 0 {
+			wantAccepts = tc.wantReqs
+		}
+		if rl.accept != wantAccepts {
+			t.Errorf("%s: RateLimiter accepted %d calls, want %d", tc.desc, rl.accept, wantAccepts)
+		}
+	}
+}
+
+func TestCallIdempotency(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		service, operation string
+		version            meta.Version
+		want               meta.Idempotency
+	}{
+		{"BackendServices", "Get", meta.VersionGA, meta.Idempotent},
+		{"BackendServices", "AggregatedList", meta.VersionGA, meta.Idempotent},
+		{"BackendServices", "Delete", meta.VersionGA, meta.Idempotent},
+		{"BackendServices", "Patch", meta.VersionBeta, meta.Idempotent},
+		{"GlobalForwardingRules", "SetTarget", meta.VersionGA, meta.Idempotent},
+		{"BackendServices", "Insert", meta.VersionGA, meta.IdempotentWithRequestID},
+		{"InstanceGroups", "AddInstances", meta.VersionGA, meta.IdempotentWithRequestID},
+		{"TcpRoutes", "Insert", meta.VersionGA, meta.NotIdempotent},
+		{"TcpRoutes", "List", meta.VersionGA, meta.Idempotent},
+	} {
+		info := newCallInfo(tc.service, tc.operation, tc.version, nil)
+		if got := callIdempotency(info); got != tc.want {
+			t.Errorf("callIdempotency(%s.%s %s) = %v, want %v", tc.service, tc.operation, tc.version, got, tc.want)
+		}
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	t.Parallel()
+
+	p := &RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	for n, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 10: 5 * time.Second} {
+		if got := p.backoff(n); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", n, got, want)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := p.backoff(1); got < 500*time.Millisecond || got > 1500*time.Millisecond {
+			t.Fatalf("backoff(1) = %v, want in [500ms, 1.5s]", got)
+		}
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	t.Parallel()
+
+	var lock sync.Mutex
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests++
+		lock.Unlock()
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+	c := NewGCE(&Service{
+		GA:            client,
+		ProjectRouter: &SingleProjectRouter{"proj"},
+		RateLimiter:   &NopRateLimiter{},
+		Retry:         &RetryPolicy{MaxAttempts: 10, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+	})
+
+	b := NewRetryBudget(3, 0)
+	ctx = WithRetryBudget(ctx, b)
+	// The first call uses the whole budget, the second is not retried.
+	for i, want := range []int{4, 5} {
+		if _, err := c.BackendServices().Get(ctx, meta.GlobalKey("bs")); err == nil {
+			t.Fatalf("Get() = _, nil, want error")
+		}
+		lock.Lock()
+		if requests != want {
+			t.Errorf("after call %d: got %d requests, want %d", i, requests, want)
+		}
+		lock.Unlock()
+	}
+	if retries, wait := b.Used(); retries != 3 || wait != 3*time.Millisecond {
+		t.Errorf("Used() = %d, %v, want 3, 3ms", retries, wait)
+	}
+
+	b = NewRetryBudget(0, 10*time.Second)
+	for _, tc := range []struct {
+		d    time.Duration
+		want bool
+	}{
+		{6 * time.Second, true},
+		{5 * time.Second, false},
+		{4 * time.Second, true},
+	} {
+		if got := b.take(tc.d); got != tc.want {
+			t.Errorf("take(%v) = %t, want %t", tc.d, got, tc.want)
+		}
+	}
+}
+
+func TestRetryWaitForConflicts(t *testing.T) {
+	t.Parallel()
+
+	const (
+		opURL   = "https://www.googleapis.com/compute/v1/projects/proj/global/operations/"
+		running = `{"name": "op-other", "status": "RUNNING", "operationType": "patch", "selfLink": "` + opURL + `op-other"}`
+		done    = `{"name": "op-1", "status": "DONE", "selfLink": "` + opURL + `op-1"}`
+	)
+	var (
+		lock    sync.Mutex
+		calls   []string
+		filters []string
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/proj/global/backendServices/bs", func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		calls = append(calls, "delete")
+		if len(calls) == 1 {
+			http.Error(w, `{"error": {"code": 409, "message": "in progress", "errors": [{"reason": "resourceNotReady"}]}}`, http.StatusConflict)
+			return
+		}
+		w.Write([]byte(done))
+	})
+	mux.HandleFunc("/projects/proj/global/operations", func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		calls = append(calls, "list")
+		filters = append(filters, r.URL.Query().Get("filter"))
+		w.Write([]byte(`{"items": [` + running + `]}`))
+	})
+	mux.HandleFunc("/projects/proj/global/operations/op-other/wait", func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		calls = append(calls, "wait op-other")
+		w.Write([]byte(`{"name": "op-other", "status": "DONE"}`))
+	})
+	mux.HandleFunc("/projects/proj/global/operations/op-1/wait", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(done))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+	c := NewGCE(&Service{
+		GA:            client,
+		ProjectRouter: &SingleProjectRouter{"proj"},
+		RateLimiter:   &NopRateLimiter{},
+		Retry:         &RetryPolicy{WaitForConflicts: true},
+	})
+
+	if err := c.BackendServices().Delete(ctx, meta.GlobalKey("bs")); err != nil {
+		t.Fatalf("Delete() = %v, want nil", err)
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if diff := cmp.Diff([]string{"delete", "list", "wait op-other", "delete"}, calls); diff != "" {
+		t.Errorf("calls: -want +got: %s", diff)
+	}
+	const wantFilter = `(targetLink eq .*/projects/proj/global/backendServices/bs) (status ne DONE)`
+	if len(filters) != 1 || filters[0] != wantFilter {
+		t.Errorf("filters = %q, want [%q]", filters, wantFilter)
+	}
+}
+
+func TestRetryNotReady(t *testing.T) {
+	t.Parallel()
+
+	const op = `{"name": "op-1", "status": "DONE", "selfLink": "https://www.googleapis.com/compute/v1/projects/proj/global/operations/op-1"}`
+	var lock sync.Mutex
+	var requests int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/proj/global/healthChecks/hc", func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		requests++
+		if requests == 1 {
+			http.Error(w, `{"error": {"code": 400, "message": "The health_check resource 'hc' is already being used by 'bs'", "errors": [{"reason": "resourceInUseByAnotherResource"}]}}`, http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(op))
+	})
+	mux.HandleFunc("/projects/proj/global/operations/op-1/wait", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(op))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+
+	for _, tc := range []struct {
+		desc     string
+		policy   *RetryPolicy
+		wantReqs int
+		wantErr  bool
+	}{
+		{desc: "not retried", policy: &RetryPolicy{MaxAttempts: 3}, wantReqs: 1, wantErr: true},
+		{desc: "retried", policy: &RetryPolicy{MaxAttempts: 3, RetryNotReady: true, NotReadyBackoff: time.Millisecond}, wantReqs: 2},
+	} {
+		lock.Lock()
+		requests = 0
+		lock.Unlock()
+		c := NewGCE(&Service{
+			GA:            client,
+			ProjectRouter: &SingleProjectRouter{"proj"},
+			RateLimiter:   &NopRateLimiter{},
+			Retry:         tc.policy,
+		})
+		err := c.HealthChecks().Delete(ctx, meta.GlobalKey("hc"))
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("%s: Delete() = %v, want error %t", tc.desc, err, tc.wantErr)
+		}
+		lock.Lock()
+		if requests != tc.wantReqs {
+			t.Errorf("%s: got %d requests, want %d", tc.desc, requests, tc.wantReqs)
+		}
+		lock.Unlock()
+	}
+}
+
+func TestRetryRefreshOnConflict(t *testing.T) {
+	t.Parallel()
+
+	// The object on the server has the fingerprint "f2"; an Update with
+	// another fingerprint fails with 412.
+	const op = `{"name": "op-1", "status": "DONE", "selfLink": "https://www.googleapis.com/compute/v1/projects/proj/global/operations/op-1"}`
+	var (
+		lock    sync.Mutex
+		updates []ga.BackendService
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/proj/global/backendServices/bs", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"name": "bs", "fingerprint": "f2", "timeoutSec": 30}`))
+			return
+		}
+		var bs ga.BackendService
+		if err := json.NewDecoder(r.Body).Decode(&bs); err != nil {
+			t.Errorf("Decode() = %v, want nil", err)
+		}
+		lock.Lock()
+		updates = append(updates, bs)
+		lock.Unlock()
+		if bs.Fingerprint != "f2" {
+			http.Error(w, `{"error": {"code": 412, "message": "Invalid fingerprint", "errors": [{"reason": "conditionNotMet"}]}}`, http.StatusPreconditionFailed)
+			return
+		}
+		w.Write([]byte(op))
+	})
+	mux.HandleFunc("/projects/proj/global/operations/op-1/wait", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(op))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+	c := NewGCE(&Service{
+		GA:            client,
+		ProjectRouter: &SingleProjectRouter{"proj"},
+		RateLimiter:   &NopRateLimiter{},
+		Retry:         &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, RefreshOnConflict: true},
+	})
+	key := meta.GlobalKey("bs")
+	mutate := func(bs *ga.BackendService) error {
+		bs.TimeoutSec = 60
+		return nil
+	}
+
+	for _, tc := range []struct {
+		desc        string
+		withRefresh bool
+		want        []ga.BackendService
+		wantErr     bool
+	}{
+		{
+			desc:    "no refresh",
+			want:    []ga.BackendService{{Name: "bs", Fingerprint: "f1", TimeoutSec: 60}},
+			wantErr: true,
+		},
+		{
+			desc:        "refreshed",
+			withRefresh: true,
+			want: []ga.BackendService{
+				{Name: "bs", Fingerprint: "f1", TimeoutSec: 60},
+				{Name: "bs", Fingerprint: "f2", TimeoutSec: 60},
+			},
+		},
+	} {
+		lock.Lock()
+		updates = nil
+		lock.Unlock()
+
+		bs := &ga.BackendService{Name: "bs", Fingerprint: "f1", TimeoutSec: 60}
+		callCtx := ctx
+		if tc.withRefresh {
+			callCtx = WithRefresh(ctx, Refresh(c.BackendServices().Get, key, bs, mutate))
+		}
+		err := c.BackendServices().Update(callCtx, key, bs)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("%s: Update() = %v, want error %t", tc.desc, err, tc.wantErr)
+		}
+		lock.Lock()
+		if diff := cmp.Diff(tc.want, updates); diff != "" {
+			t.Errorf("%s: updates: -want +got: %s", tc.desc, diff)
+		}
+		lock.Unlock()
+	}
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	var (
+		lock     sync.Mutex
+		requests int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests++
+		lock.Unlock()
+		http.Error(w, `{"error": {"code": 503, "message": "unavailable"}}`, http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+	fast := func(n int) *RetryPolicy {
+		return &RetryPolicy{MaxAttempts: n, InitialBackoff: time.Millisecond}
+	}
+
+	for _, tc := range []struct {
+		desc     string
+		service  *RetryPolicy
+		ctx      context.Context
+		wantReqs int
+	}{
+		{desc: "service policy", service: fast(3), ctx: ctx, wantReqs: 3},
+		{desc: "disabled for the call", service: fast(3), ctx: WithRetryPolicy(ctx, nil), wantReqs: 1},
+		{desc: "extended for the call", service: fast(2), ctx: WithRetryPolicy(ctx, fast(4)), wantReqs: 4},
+		{desc: "only for the call", ctx: WithRetryPolicy(ctx, fast(2)), wantReqs: 2},
+	} {
+		lock.Lock()
+		requests = 0
+		lock.Unlock()
+		c := NewGCE(&Service{
+			GA:            client,
+			ProjectRouter: &SingleProjectRouter{"proj"},
+			RateLimiter:   &NopRateLimiter{},
+			Retry:         tc.service,
+		})
+		if _, err := c.BackendServices().Get(tc.ctx, meta.GlobalKey("bs")); err == nil {
+			t.Errorf("%s: Get() = _, nil, want error", tc.desc)
+		}
+		lock.Lock()
+		if requests != tc.wantReqs {
+			t.Errorf("%s: got %d requests, want %d", tc.desc, requests, tc.wantReqs)
+		}
+		lock.Unlock()
+	}
+}
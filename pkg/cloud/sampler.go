@@ -0,0 +1,196 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"math/rand"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// Sampler captures the CallDiagnostics of a fraction of the calls. The
+// calls that are not sampled only pay for a random number.
+//
+//	svc.Sampler = &cloud.Sampler{
+//		Fraction: 0.01,
+//		OnSample: func(ctx context.Context, d *cloud.CallDiagnostics) {
+//			if d.Total > 10*time.Second {
+//				logger.Info("slow call", "diagnostics", d)
+//			}
+//		},
+//	}
+type Sampler struct {
+	// Fraction of the calls that are sampled, between 0 and 1.
+	Fraction float64
+	// OnSample is called with the CallDiagnostics of each sampled call
+	// when it ends.
+	OnSample func(ctx context.Context, d *CallDiagnostics)
+
+	// rand is replaced in unit tests.
+	rand func() float64
+}
+
+func (s *Sampler) sample() bool {
+	if s == nil || s.OnSample == nil || s.Fraction <= 0 {
+		return false
+	}
+	r := rand.Float64
+	if s.rand != nil {
+		r = s.rand
+	}
+	return r() < s.Fraction
+}
+
+// CallDiagnostics is the timing breakdown of a sampled call. The phases
+// add up to Total.
+type CallDiagnostics struct {
+	CallContextKey *CallContextKey
+	// Key of the resource. This is nil if the call is not on a single
+	// resource.
+	Key *meta.Key
+	// Start of the call.
+	Start time.Time
+	// Total duration of the call.
+	Total time.Duration
+	// RateLimitWait is the time spent in the RateLimiter.
+	RateLimitWait time.Duration
+	// HTTP is the time from getting a connection to the first byte of
+	// the response, summed over the requests of the call (e.g. the pages
+	// of a List). This is zero for gRPC calls.
+	HTTP time.Duration
+	// Decode is the rest of the time until the call returned or returned
+	// its operation: encoding the request, reading and decoding the
+	// response.
+	Decode time.Duration
+	// OperationWait is the time spent waiting for the long running
+	// operation.
+	OperationWait time.Duration
+	// Requests is the number of HTTP requests of the call, not counting
+	// the polls of the operation.
+	Requests int
+	// Err is the result of the call.
+	Err error
+}
+
+// callDiag is the CallDiagnostics of a sampled call in progress.
+type callDiag struct {
+	sampler *Sampler
+
+	lock     sync.Mutex
+	sent     time.Time
+	reqStart time.Time
+	opStart  time.Time
+	http     time.Duration
+	requests int
+	done     bool
+}
+
+// startDiag samples the call in state. The returned context traces the
+// HTTP requests of sampled calls.
+func (s *Service) startDiag(ctx context.Context, state *callState) context.Context {
+	if !s.Sampler.sample() {
+		return ctx
+	}
+	d := &callDiag{sampler: s.Sampler}
+	state.diag = d
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GetConn: func(string) {
+			d.lock.Lock()
+			defer d.lock.Unlock()
+			if d.opStart.IsZero() {
+				d.reqStart = time.Now()
+			}
+		},
+		GotFirstResponseByte: func() {
+			d.lock.Lock()
+			defer d.lock.Unlock()
+			if d.opStart.IsZero() && !d.reqStart.IsZero() {
+				d.http += time.Since(d.reqStart)
+				d.requests++
+				d.reqStart = time.Time{}
+			}
+		},
+	})
+}
+
+// sending records that the rate limiter accepted the call.
+func (d *callDiag) sending() {
+	if d == nil {
+		return
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.sent.IsZero() {
+		d.sent = time.Now()
+	}
+}
+
+// waiting records that the call returned its operation.
+func (d *callDiag) waiting() {
+	if d == nil {
+		return
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.opStart = time.Now()
+}
+
+// end reports the CallDiagnostics of the call in state with the result
+// err. It is reported once.
+func (d *callDiag) end(ctx context.Context, state *callState, err error) {
+	if d == nil {
+		return
+	}
+	d.lock.Lock()
+	if d.done {
+		d.lock.Unlock()
+		return
+	}
+	d.done = true
+	now := time.Now()
+	diag := &CallDiagnostics{
+		CallContextKey: state.ck,
+		Key:            state.key,
+		Start:          state.start,
+		Total:          now.Sub(state.start),
+		HTTP:           d.http,
+		Requests:       d.requests,
+		Err:            err,
+	}
+	sent := d.sent
+	if sent.IsZero() {
+		// The call did not get past the RateLimiter.
+		sent = now
+	}
+	diag.RateLimitWait = sent.Sub(state.start)
+	returned := now
+	if !d.opStart.IsZero() {
+		returned = d.opStart
+		diag.OperationWait = now.Sub(d.opStart)
+	}
+	diag.Decode = returned.Sub(sent) - d.http
+	if diag.Decode < 0 {
+		diag.Decode = 0
+	}
+	d.lock.Unlock()
+
+	d.sampler.OnSample(ctx, diag)
+}
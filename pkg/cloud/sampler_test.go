@@ -0,0 +1,133 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+)
+
+type sleepRateLimiter struct{ d time.Duration }
+
+func (r *sleepRateLimiter) Accept(context.Context, *RateLimitKey) error {
+	time.Sleep(r.d)
+	return nil
+}
+func (r *sleepRateLimiter) Observe(context.Context, error, *RateLimitKey) {}
+
+func TestSampler(t *testing.T) {
+	t.Parallel()
+
+	const delay = 20 * time.Millisecond
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/proj/global/firewalls", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":     "op-1",
+			"status":   "RUNNING",
+			"selfLink": "https://www.googleapis.com/compute/v1/projects/proj/global/operations/op-1",
+		})
+	})
+	mux.HandleFunc("/projects/proj/global/operations/op-1/wait", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "op-1", "status": "DONE"})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+
+	for _, tc := range []struct {
+		desc        string
+		fraction    float64
+		rand        float64
+		wantSampled bool
+	}{
+		{desc: "sampled", fraction: 0.5, rand: 0.25, wantSampled: true},
+		{desc: "not sampled", fraction: 0.5, rand: 0.75},
+		{desc: "off", fraction: 0, rand: 0},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			var lock sync.Mutex
+			var got []*CallDiagnostics
+			c := NewGCE(&Service{
+				GA:            client,
+				ProjectRouter: &SingleProjectRouter{"proj"},
+				RateLimiter:   &sleepRateLimiter{delay},
+				Sampler: &Sampler{
+					Fraction: tc.fraction,
+					OnSample: func(ctx context.Context, d *CallDiagnostics) {
+						lock.Lock()
+						defer lock.Unlock()
+						got = append(got, d)
+					},
+					rand: func() float64 { return tc.rand },
+				},
+			})
+			key := meta.GlobalKey("fw")
+			if err := c.Firewalls().Insert(ctx, key, &ga.Firewall{}); err != nil {
+				t.Fatalf("Insert(%v) = %v, want nil", key, err)
+			}
+
+			if !tc.wantSampled {
+				if len(got) != 0 {
+					t.Fatalf("got %d samples, want 0", len(got))
+				}
+				return
+			}
+			if len(got) != 1 {
+				t.Fatalf("got %d samples, want 1", len(got))
+			}
+			d := got[0]
+			if d.CallContextKey.Operation != "Insert" || d.Key != key || d.Err != nil {
+				t.Errorf("sample = %+v, want Insert(%v) = nil", d, key)
+			}
+			// The operation is polled once, with the rate limiter.
+			for name, v := range map[string]time.Duration{
+				"RateLimitWait": d.RateLimitWait,
+				"HTTP":          d.HTTP,
+				"OperationWait": d.OperationWait,
+			} {
+				if v < delay {
+					t.Errorf("%s = %v, want >= %v", name, v, delay)
+				}
+			}
+			if d.OperationWait < 2*delay {
+				t.Errorf("OperationWait = %v, want >= %v", d.OperationWait, 2*delay)
+			}
+			if d.Requests != 1 {
+				t.Errorf("Requests = %d, want 1", d.Requests)
+			}
+			if sum := d.RateLimitWait + d.HTTP + d.Decode + d.OperationWait; sum != d.Total {
+				t.Errorf("phases add up to %v, want Total = %v", sum, d.Total)
+			}
+		})
+	}
+}
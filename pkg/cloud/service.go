@@ -21,9 +21,12 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/go-logr/logr"
 	alpha "google.golang.org/api/compute/v0.alpha"
 	beta "google.golang.org/api/compute/v0.beta"
 	ga "google.golang.org/api/compute/v1"
+	networkconnectivity "google.golang.org/api/networkconnectivity/v1"
+	networkservices "google.golang.org/api/networkservices/v1"
 	"k8s.io/klog/v2"
 )
 
@@ -35,6 +38,52 @@ type Service struct {
 	Beta          *beta.Service
 	ProjectRouter ProjectRouter
 	RateLimiter   RateLimiter
+	// Tracer creates a Span for each call to the API. This may be nil
+	// if tracing is not used.
+	Tracer Tracer
+	// Logger receives structured logs for each call to the API and each
+	// wait for an operation. The zero value discards the logs. See
+	// LogLevelError, LogLevelCall and LogLevelPoll for the verbosities.
+	Logger logr.Logger
+	// Auditor is called for each call that mutates a resource. This may
+	// be nil if auditing is not used.
+	Auditor Auditor
+	// Watchdog reports calls that take longer than a threshold. This may
+	// be nil.
+	Watchdog *Watchdog
+	// OnError is called with the class of the error of each call that
+	// fails, e.g. to set the conditions of a Kubernetes object
+	// consistently. This may be nil.
+	OnError func(ctx context.Context, e *CallError)
+	// Events receives an Event for each successful mutation. This may be
+	// nil.
+	Events *EventBus
+	// Headers sent with each call. This may be nil.
+	Headers *CallHeaders
+	// Sampler captures the CallDiagnostics of a fraction of the calls.
+	// This may be nil.
+	Sampler *Sampler
+	// Interceptors wrap each call made through Cloud. The first
+	// Interceptor is the outermost. See Interceptor.
+	Interceptors []Interceptor
+	// Retry the calls that fail with a transient error. This may be nil
+	// to not retry.
+	Retry *RetryPolicy
+	// TranslateError is called with the error of each call made through
+	// Cloud, including NetworkServices() and NetworkConnectivity(), before
+	// it is returned, e.g. to map it to the errors of the caller or to add
+	// a remediation hint. The error returned replaces e; it should wrap e
+	// so that the predicates of package gcerrors still work. A nil error
+	// returns e. This may be nil.
+	TranslateError func(ctx context.Context, e *CallError) error
+
+	// NetworkServices is the client for networkservices.googleapis.com.
+	// This may be nil if the NetworkServices() resources are not used.
+	NetworkServices *networkservices.Service
+	// NetworkConnectivity is the client for
+	// networkconnectivity.googleapis.com. This may be nil if the
+	// NetworkConnectivity() resources are not used.
+	NetworkConnectivity *networkconnectivity.Service
 }
 
 // wrapOperation wraps a GCE anyOP in a version generic operation type.
@@ -58,6 +107,14 @@ func (s *Service) wrapOperation(anyOp interface{}) (operation, error) {
 			return nil, err
 		}
 		return &betaOperation{s: s, projectID: r.ProjectID, key: r.Key}, nil
+	case *networkservices.Operation:
+		op := &networkServicesOperation{s: s, projectID: locationProjectID(o.Name)}
+		op.setOp(o)
+		return op, nil
+	case *networkconnectivity.GoogleLongrunningOperation:
+		op := &networkConnectivityOperation{s: s, projectID: locationProjectID(o.Name)}
+		op.setOp(o)
+		return op, nil
 	default:
 		return nil, fmt.Errorf("invalid type %T", anyOp)
 	}
@@ -65,15 +122,24 @@ func (s *Service) wrapOperation(anyOp interface{}) (operation, error) {
 
 // WaitForCompletion of a long running operation. This will poll the state of
 // GCE for the completion status of the given operation. genericOp can be one
-// of alpha, beta, ga Operation types.
+// of alpha, beta, ga Operation types or an operation of the networkservices
+// or networkconnectivity APIs.
 func (s *Service) WaitForCompletion(ctx context.Context, genericOp interface{}) error {
 	op, err := s.wrapOperation(genericOp)
 	if err != nil {
 		klog.Errorf("wrapOperation(%+v) error: %v", genericOp, err)
+		callOperationDone(ctx, err)
 		return err
 	}
 
-	return s.pollOperation(ctx, op)
+	wctx, w := s.waitStart(ctx, genericOp)
+	err = s.pollOperation(wctx, op)
+	w.end(err)
+	callOperationDone(ctx, err)
+	if err == nil {
+		s.publishOperation(ctx, genericOp)
+	}
+	return err
 }
 
 // pollOperation calls operations.isDone until the function comes back true or context is Done.
@@ -82,6 +148,8 @@ func (s *Service) WaitForCompletion(ctx context.Context, genericOp interface{})
 func (s *Service) pollOperation(ctx context.Context, op operation) error {
 	start := time.Now()
 	var pollCount int
+	w := opWaitFromContext(ctx)
+	defer func() { w.polls = pollCount }()
 	for {
 		// Check if context has been cancelled. Note that ctx.Done() must be checked before
 		// returning ctx.Err().
@@ -99,12 +167,26 @@ func (s *Service) pollOperation(ctx context.Context, op operation) error {
 		switch done, err := op.isDone(ctx); {
 		case err != nil:
 			klog.V(5).Infof("op.isDone(%v) error; op = %v, poll count = %d, err = %v, retrying (%v elapsed)", ctx, op, pollCount, err, time.Now().Sub(start))
+			s.logWait(op, w, pollCount, start, err)
 			s.RateLimiter.Observe(ctx, err, op.rateLimitKey())
 			return err
 		case done:
 			klog.V(5).Infof("op.isDone(%v) complete; op = %v, poll count = %d, op.err = %v (%v elapsed)", ctx, op, pollCount, op.error(), time.Now().Sub(start))
+			s.logWait(op, w, pollCount, start, op.error())
 			s.RateLimiter.Observe(ctx, op.error(), op.rateLimitKey())
 			return op.error()
 		}
+		s.Logger.V(LogLevelPoll).Info("operation not done", append([]interface{}{"op", op, "polls", pollCount, "latency", time.Since(start)}, w.kv...)...)
+	}
+}
+
+// logWait logs the end of pollOperation to the Logger with the fields of
+// the wait w.
+func (s *Service) logWait(op operation, w *opWait, pollCount int, start time.Time, err error) {
+	kv := append([]interface{}{"op", op, "polls", pollCount, "latency", time.Since(start)}, w.kv...)
+	if err != nil {
+		s.Logger.V(LogLevelError).Info("operation failed", append(kv, "err", err)...)
+		return
 	}
+	s.Logger.V(LogLevelCall).Info("operation done", kv...)
 }
@@ -0,0 +1,109 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// snapshotVersion is the version of the format of the snapshots.
+const snapshotVersion = 1
+
+// snapshot is the serialized form of a Store.
+type snapshot[T any] struct {
+	Version  int
+	Resource string
+	// LastSync is the time of the List of the Objects.
+	LastSync time.Time
+	Objects  []T
+}
+
+// Save writes a snapshot of the objects to w, with the time they were
+// listed. The snapshot is JSON.
+func (s *Store[T]) Save(w io.Writer) error {
+	s.lock.RLock()
+	snap := snapshot[T]{
+		Version:  snapshotVersion,
+		Resource: s.config.Resource,
+		LastSync: s.lastSync,
+	}
+	for _, obj := range s.objects {
+		snap.Objects = append(snap.Objects, obj)
+	}
+	s.lock.RUnlock()
+
+	return json.NewEncoder(w).Encode(&snap)
+}
+
+// Load replaces the objects with a snapshot written by Save. The Store
+// HasSynced after Load, and LastSync is the time of the List of the
+// snapshot, so callers can check its staleness.
+func (s *Store[T]) Load(r io.Reader) error {
+	var snap snapshot[T]
+	if err := json.NewDecoder(r).Decode(&snap); err != nil {
+		return err
+	}
+	if snap.Version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d", snap.Version)
+	}
+	if snap.Resource != s.config.Resource {
+		return fmt.Errorf("snapshot of %q, not %q", snap.Resource, s.config.Resource)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.replace(snap.Objects)
+	s.lastSync = snap.LastSync
+	return nil
+}
+
+// SaveFile saves the snapshot to the file path. The file is replaced
+// atomically, so a crash does not leave a partial snapshot.
+func (s *Store[T]) SaveFile(path string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := s.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// LoadFile loads the snapshot from the file path. The error wraps
+// fs.ErrNotExist if there is no snapshot.
+func (s *Store[T]) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return s.Load(f)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestStoreSaveLoad(t *testing.T) {
+	t.Parallel()
+
+	list := func(ctx context.Context) ([]*ga.ForwardingRule, error) {
+		return []*ga.ForwardingRule{{
+			Name:     "fr1",
+			SelfLink: prefix + "global/forwardingRules/fr1",
+			Labels:   map[string]string{"app": "a"},
+		}}, nil
+	}
+	s := New(Config[*ga.ForwardingRule]{Resource: "forwardingRules", List: list})
+	if err := s.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := s.Save(&buf); err != nil {
+		t.Fatalf("Save() = %v", err)
+	}
+
+	loaded := New(Config[*ga.ForwardingRule]{Resource: "forwardingRules"})
+	if err := loaded.Load(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	if !loaded.HasSynced() || !loaded.LastSync().Equal(s.LastSync()) {
+		t.Errorf("HasSynced(), LastSync() = %t, %v after Load; want true, %v", loaded.HasSynced(), loaded.LastSync(), s.LastSync())
+	}
+	if got := names(loaded.ByLabel("app", "a")); !equal(got, []string{"fr1"}) {
+		t.Errorf("ByLabel(app, a) = %v after Load, want [fr1]", got)
+	}
+
+	other := New(Config[*ga.ForwardingRule]{Resource: "backendServices"})
+	if err := other.Load(bytes.NewReader(buf.Bytes())); err == nil {
+		t.Errorf("Load() of a snapshot of another resource = nil, want error")
+	}
+}
+
+func TestStoreRunSnapshot(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "forwardingRules.json")
+	var lists int32
+	config := Config[*ga.ForwardingRule]{
+		Resource: "forwardingRules",
+		List: func(ctx context.Context) ([]*ga.ForwardingRule, error) {
+			atomic.AddInt32(&lists, 1)
+			return []*ga.ForwardingRule{{Name: "fr1", SelfLink: prefix + "global/forwardingRules/fr1"}}, nil
+		},
+		SnapshotPath: path,
+	}
+
+	// Without a snapshot, Run lists and saves the objects.
+	New(config).Run(context.Background())
+	if n := atomic.LoadInt32(&lists); n != 1 {
+		t.Fatalf("lists = %d, want 1", n)
+	}
+
+	// A recent snapshot is used until the next resync.
+	config.ResyncPeriod = time.Hour
+	s := New(config)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	deadline := time.Now().Add(10 * time.Second)
+	for !s.HasSynced() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+	if got := names(s.List()); !equal(got, []string{"fr1"}) {
+		t.Errorf("List() = %v, want [fr1] from the snapshot", got)
+	}
+	if n := atomic.LoadInt32(&lists); n != 1 {
+		t.Errorf("lists = %d, want 1", n)
+	}
+}
@@ -0,0 +1,560 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package store keeps an indexed in-memory copy of the resources of a
+// type in a project, like the informers of client-go.
+//
+// A Store is filled by listing all the resources, usually with
+// AggregatedList, and is resynced periodically. The Events of the
+// mutations made through a cloud.Service update the Store between
+// resyncs:
+//
+//	bs := store.New(store.Config[*ga.BackendService]{
+//		Resource: "backendServices",
+//		List: func(ctx context.Context) ([]*ga.BackendService, error) {
+//			all, err := gce.BackendServices().AggregatedList(ctx, filter.None)
+//			return store.Flatten(all), err
+//		},
+//		Get: func(ctx context.Context, id *cloud.ResourceID) (*ga.BackendService, error) {
+//			if id.Key.Type() == meta.Regional {
+//				return gce.RegionBackendServices().Get(ctx, id.Key)
+//			}
+//			return gce.BackendServices().Get(ctx, id.Key)
+//		},
+//		ResyncPeriod: 10 * time.Minute,
+//	})
+//	bus.Subscribe(bs.OnEvent)
+//	go bs.Run(ctx)
+//
+//	// Forwarding rules pointing at a backend service.
+//	frs := frStore.ByReference(bsSelfLink)
+//
+// The objects are indexed by label and by the resources they reference.
+// More indexes can be added with AddIndex, like the indexers of client-go.
+//
+// With Config.SnapshotPath, the objects are saved to a file after each
+// resync and loaded from it when Run starts, so a controller that restarts
+// does not list them again until the snapshot is older than the
+// ResyncPeriod.
+//
+// The objects returned by the Store are shared and must not be modified.
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"k8s.io/klog/v2"
+)
+
+// Config of a Store of objects of type T (e.g. *ga.BackendService).
+type Config[T any] struct {
+	// Resource is the name of the resource in the URL, e.g.
+	// "backendServices". The Events of other resources are ignored.
+	Resource string
+	// List all the objects in the project.
+	List func(ctx context.Context) ([]T, error)
+	// Get the object id after a mutation. This may be nil, in which
+	// case created and updated objects are seen at the next resync.
+	Get func(ctx context.Context, id *cloud.ResourceID) (T, error)
+	// ResyncPeriod is the period of the full List. Zero only lists
+	// once.
+	ResyncPeriod time.Duration
+	// SnapshotPath is a file where Run saves the objects after each
+	// Sync. Run loads the file when it starts and only lists the
+	// objects when the snapshot is older than ResyncPeriod. See Save.
+	SnapshotPath string
+}
+
+// Lister reads the objects of a Store.
+type Lister[T any] interface {
+	// Get the object with the ResourceID.
+	Get(id *cloud.ResourceID) (T, bool)
+	// List all the objects, ordered by their ResourceID.
+	List() []T
+	// ByLabel returns the objects with the label key set to value.
+	ByLabel(key, value string) []T
+	// ByReference returns the objects that have a field referencing the
+	// resource with the URL link (e.g. a selfLink).
+	ByReference(link string) []T
+	// ByIndex returns the objects with the value in the index name.
+	ByIndex(name, value string) ([]T, error)
+}
+
+// Names of the indexes of all Stores.
+const (
+	// IndexLabel has the labels of the objects as "key=value".
+	IndexLabel = "label"
+	// IndexReference has the relative resource names (e.g.
+	// "projects/p/global/backendServices/bs") of the resources that the
+	// objects reference.
+	IndexReference = "reference"
+)
+
+// IndexFunc returns the values of obj in an index.
+type IndexFunc[T any] func(obj T) []string
+
+// Store is an indexed copy of the objects of a resource type.
+type Store[T any] struct {
+	config Config[T]
+
+	lock   sync.RWMutex
+	synced bool
+	// lastSync is the time of the List of the objects, which may be
+	// before the Store was created if they were loaded from a snapshot.
+	lastSync time.Time
+	objects  map[cloud.ResourceMapKey]T
+	// indexFuncs return the values of an object for each index.
+	indexFuncs map[string]IndexFunc[T]
+	// indexes are the keys of the objects by index and value.
+	indexes map[string]map[string]map[cloud.ResourceMapKey]bool
+
+	// runCtx is the context of Run, used for refreshing the objects
+	// after Events.
+	runCtx context.Context
+	// eventSeq numbers the Events.
+	eventSeq uint64
+	// events are the keys with refreshes in progress.
+	events map[cloud.ResourceMapKey]*keyEvents
+}
+
+// keyEvents tracks the refreshes of a key. The result of a refresh is
+// dropped if a later Event was received for the key, e.g. a Get that
+// returns after the object was deleted.
+type keyEvents struct {
+	// seq of the last Event of the key.
+	seq uint64
+	// refreshes in progress.
+	refreshes int
+}
+
+// Store implements Lister.
+var _ Lister[interface{}] = (*Store[interface{}])(nil)
+
+// New returns an empty Store. The Store is filled by Run or Sync.
+func New[T any](config Config[T]) *Store[T] {
+	s := &Store[T]{
+		config:  config,
+		objects: map[cloud.ResourceMapKey]T{},
+		indexFuncs: map[string]IndexFunc[T]{
+			IndexLabel:     func(obj T) []string { return labelValues(obj) },
+			IndexReference: func(obj T) []string { return referenceValues(obj) },
+		},
+		indexes: map[string]map[string]map[cloud.ResourceMapKey]bool{},
+		runCtx:  context.Background(),
+		events:  map[cloud.ResourceMapKey]*keyEvents{},
+	}
+	for name := range s.indexFuncs {
+		s.indexes[name] = map[string]map[cloud.ResourceMapKey]bool{}
+	}
+	return s
+}
+
+// Run lists the objects every ResyncPeriod until ctx is done. Errors are
+// logged and the List is retried at the next period. If the Config has a
+// SnapshotPath, the objects are loaded from it first and saved to it after
+// each List.
+func (s *Store[T]) Run(ctx context.Context) {
+	s.lock.Lock()
+	s.runCtx = ctx
+	s.lock.Unlock()
+
+	var wait time.Duration
+	if s.config.SnapshotPath != "" {
+		if err := s.LoadFile(s.config.SnapshotPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			klog.Errorf("store.LoadFile(%s, %q) = %v", s.config.Resource, s.config.SnapshotPath, err)
+		}
+		if last := s.LastSync(); !last.IsZero() && s.config.ResyncPeriod > 0 {
+			wait = time.Until(last.Add(s.config.ResyncPeriod))
+		}
+	}
+
+	for {
+		if wait > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+		}
+		if err := s.Sync(ctx); err != nil {
+			klog.Errorf("store.Sync(%s) = %v", s.config.Resource, err)
+		} else if s.config.SnapshotPath != "" {
+			if err := s.SaveFile(s.config.SnapshotPath); err != nil {
+				klog.Errorf("store.SaveFile(%s, %q) = %v", s.config.Resource, s.config.SnapshotPath, err)
+			}
+		}
+		if s.config.ResyncPeriod <= 0 {
+			return
+		}
+		wait = s.config.ResyncPeriod
+	}
+}
+
+// Sync replaces the objects with the result of List.
+func (s *Store[T]) Sync(ctx context.Context) error {
+	start := time.Now()
+	objs, err := s.config.List(ctx)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.replace(objs)
+	s.lastSync = start
+	return nil
+}
+
+// replace the objects with objs. s.lock must be held.
+func (s *Store[T]) replace(objs []T) {
+	s.objects = map[cloud.ResourceMapKey]T{}
+	for name := range s.indexes {
+		s.indexes[name] = map[string]map[cloud.ResourceMapKey]bool{}
+	}
+	for _, obj := range objs {
+		id, err := objectID(obj)
+		if err != nil {
+			klog.Errorf("store(%s): %v", s.config.Resource, err)
+			continue
+		}
+		s.add(id.MapKey(), obj)
+	}
+	s.synced = true
+}
+
+// LastSync returns the time of the List of the objects in the Store, or
+// zero if they have not been listed.
+func (s *Store[T]) LastSync() time.Time {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.lastSync
+}
+
+// HasSynced returns true after the first successful Sync or Load.
+func (s *Store[T]) HasSynced() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.synced
+}
+
+// OnEvent updates the Store with the Event of a mutation. It is a
+// cloud.EventHandler. Deleted objects are removed. Created and updated
+// objects are fetched with Config.Get in the background. A fetched object
+// is dropped if a later Event of the same object was received meanwhile.
+func (s *Store[T]) OnEvent(ctx context.Context, e *cloud.Event) {
+	if e.ResourceID == nil || e.ResourceID.Key == nil || e.ResourceID.Resource != s.config.Resource {
+		return
+	}
+	id := e.ResourceID
+	k := id.MapKey()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.eventSeq++
+	ev := s.events[k]
+	if e.Type == cloud.EventDeleted {
+		s.remove(k)
+		if ev != nil {
+			ev.seq = s.eventSeq
+		}
+		return
+	}
+	if s.config.Get == nil {
+		return
+	}
+	if ev == nil {
+		ev = &keyEvents{}
+		s.events[k] = ev
+	}
+	ev.seq = s.eventSeq
+	ev.refreshes++
+	go s.refresh(s.runCtx, id, ev.seq)
+}
+
+// refresh fetches the object id after its Event seq.
+func (s *Store[T]) refresh(ctx context.Context, id *cloud.ResourceID, seq uint64) {
+	obj, err := s.config.Get(ctx, id)
+
+	k := id.MapKey()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	ev := s.events[k]
+	ev.refreshes--
+	if ev.refreshes == 0 {
+		delete(s.events, k)
+	}
+	if err != nil {
+		klog.Errorf("store.refresh(%s, %v) = %v", s.config.Resource, id, err)
+		return
+	}
+	if ev.seq != seq {
+		klog.V(4).Infof("store.refresh(%s, %v): dropping the object of an outdated Event", s.config.Resource, id)
+		return
+	}
+	s.add(k, obj)
+}
+
+// add obj to the objects and indexes. s.lock must be held.
+func (s *Store[T]) add(k cloud.ResourceMapKey, obj T) {
+	s.remove(k)
+	s.objects[k] = obj
+	for name, f := range s.indexFuncs {
+		for _, v := range f(obj) {
+			idx := s.indexes[name]
+			if idx[v] == nil {
+				idx[v] = map[cloud.ResourceMapKey]bool{}
+			}
+			idx[v][k] = true
+		}
+	}
+}
+
+// remove the object k from the objects and indexes. s.lock must be held.
+func (s *Store[T]) remove(k cloud.ResourceMapKey) {
+	obj, ok := s.objects[k]
+	if !ok {
+		return
+	}
+	delete(s.objects, k)
+	for name, f := range s.indexFuncs {
+		for _, v := range f(obj) {
+			idx := s.indexes[name]
+			delete(idx[v], k)
+			if len(idx[v]) == 0 {
+				delete(idx, v)
+			}
+		}
+	}
+}
+
+// Get implements Lister.
+func (s *Store[T]) Get(id *cloud.ResourceID) (T, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	obj, ok := s.objects[id.MapKey()]
+	return obj, ok
+}
+
+// List implements Lister.
+func (s *Store[T]) List() []T {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	keys := make([]cloud.ResourceMapKey, 0, len(s.objects))
+	for k := range s.objects {
+		keys = append(keys, k)
+	}
+	return s.sorted(keys)
+}
+
+// ByLabel implements Lister.
+func (s *Store[T]) ByLabel(key, value string) []T {
+	return s.byIndex(IndexLabel, key+"="+value)
+}
+
+// ByReference implements Lister.
+func (s *Store[T]) ByReference(link string) []T {
+	id, err := cloud.ParseResourceURL(link)
+	if err != nil || id.Key == nil {
+		return nil
+	}
+	return s.byIndex(IndexReference, referenceValue(id))
+}
+
+// AddIndex adds the index name with the values returned by f. The objects
+// already in the Store are indexed. It is an error to add an index twice.
+//
+//	// Backend services by the health checks they use.
+//	err := bs.AddIndex("healthCheck", func(obj *ga.BackendService) []string {
+//		return obj.HealthChecks
+//	})
+//	...
+//	objs, err := bs.ByIndex("healthCheck", hcSelfLink)
+func (s *Store[T]) AddIndex(name string, f IndexFunc[T]) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.indexFuncs[name]; ok {
+		return fmt.Errorf("index %q already exists", name)
+	}
+	s.indexFuncs[name] = f
+	idx := map[string]map[cloud.ResourceMapKey]bool{}
+	for k, obj := range s.objects {
+		for _, v := range f(obj) {
+			if idx[v] == nil {
+				idx[v] = map[cloud.ResourceMapKey]bool{}
+			}
+			idx[v][k] = true
+		}
+	}
+	s.indexes[name] = idx
+	return nil
+}
+
+// ByIndex implements Lister.
+func (s *Store[T]) ByIndex(name, value string) ([]T, error) {
+	s.lock.RLock()
+	_, ok := s.indexFuncs[name]
+	s.lock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("index %q does not exist", name)
+	}
+	return s.byIndex(name, value), nil
+}
+
+func (s *Store[T]) byIndex(name, value string) []T {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var keys []cloud.ResourceMapKey
+	for k := range s.indexes[name][value] {
+		keys = append(keys, k)
+	}
+	return s.sorted(keys)
+}
+
+// sorted returns the objects of keys sorted by key. s.lock must be held.
+func (s *Store[T]) sorted(keys []cloud.ResourceMapKey) []T {
+	type sortKey struct {
+		str string
+		k   cloud.ResourceMapKey
+	}
+	sks := make([]sortKey, 0, len(keys))
+	for _, k := range keys {
+		sks = append(sks, sortKey{fmt.Sprint(k), k})
+	}
+	sort.Slice(sks, func(i, j int) bool { return sks[i].str < sks[j].str })
+	ret := make([]T, 0, len(sks))
+	for _, sk := range sks {
+		ret = append(ret, s.objects[sk.k])
+	}
+	return ret
+}
+
+// objectID returns the ResourceID from the SelfLink of obj.
+func objectID(obj interface{}) (*cloud.ResourceID, error) {
+	v := reflect.Indirect(reflect.ValueOf(obj))
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("object %T is not a pointer to a struct", obj)
+	}
+	f := v.FieldByName("SelfLink")
+	if !f.IsValid() || f.Kind() != reflect.String {
+		return nil, fmt.Errorf("object %T has no SelfLink", obj)
+	}
+	id, err := cloud.ParseResourceURL(f.String())
+	if err != nil {
+		return nil, err
+	}
+	if id.Key == nil {
+		return nil, fmt.Errorf("object %T has no key in its SelfLink %q", obj, f.String())
+	}
+	return id, nil
+}
+
+// labelValues returns "key=value" for each label of obj.
+func labelValues(obj interface{}) []string {
+	v := reflect.Indirect(reflect.ValueOf(obj))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	f := v.FieldByName("Labels")
+	if !f.IsValid() {
+		return nil
+	}
+	labels, ok := f.Interface().(map[string]string)
+	if !ok {
+		return nil
+	}
+	var ret []string
+	for k, val := range labels {
+		ret = append(ret, k+"="+val)
+	}
+	return ret
+}
+
+// referenceValues returns the referenceValue of the resources referenced
+// by the fields of obj, other than its SelfLink.
+func referenceValues(obj interface{}) []string {
+	seen := map[string]bool{}
+	var visit func(v reflect.Value, selfLink bool)
+	visit = func(v reflect.Value, selfLink bool) {
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Interface:
+			if !v.IsNil() {
+				visit(v.Elem(), false)
+			}
+		case reflect.Struct:
+			for i := 0; i < v.NumField(); i++ {
+				if v.Type().Field(i).IsExported() {
+					visit(v.Field(i), v.Type().Field(i).Name == "SelfLink")
+				}
+			}
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < v.Len(); i++ {
+				visit(v.Index(i), false)
+			}
+		case reflect.Map:
+			iter := v.MapRange()
+			for iter.Next() {
+				visit(iter.Value(), false)
+			}
+		case reflect.String:
+			s := v.String()
+			if selfLink || !strings.Contains(s, "projects/") {
+				return
+			}
+			if id, err := cloud.ParseResourceURL(s); err == nil && id.Key != nil {
+				seen[referenceValue(id)] = true
+			}
+		}
+	}
+	visit(reflect.ValueOf(obj), false)
+
+	var ret []string
+	for r := range seen {
+		ret = append(ret, r)
+	}
+	return ret
+}
+
+// referenceValue is the value in the reference index of a resource. It
+// is the same for the URLs of all the API versions.
+func referenceValue(id *cloud.ResourceID) string {
+	return id.RelativeResourceName()
+}
+
+// Flatten the result of an AggregatedList.
+func Flatten[T any](all map[string][]T) []T {
+	var ret []T
+	for _, objs := range all {
+		ret = append(ret, objs...)
+	}
+	return ret
+}
@@ -0,0 +1,312 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+)
+
+const prefix = "https://www.googleapis.com/compute/v1/projects/proj/"
+
+func names(objs []*ga.ForwardingRule) []string {
+	var ret []string
+	for _, o := range objs {
+		ret = append(ret, o.Name)
+	}
+	return ret
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	var lock sync.Mutex
+	objs := []*ga.ForwardingRule{
+		{
+			Name:      "fr1",
+			SelfLink:  prefix + "global/forwardingRules/fr1",
+			Labels:    map[string]string{"app": "a"},
+			Target:    prefix + "global/targetHttpProxies/tp1",
+			IPAddress: "10.0.0.1",
+		},
+		{
+			Name:     "fr2",
+			SelfLink: prefix + "regions/us-central1/forwardingRules/fr2",
+			Labels:   map[string]string{"app": "b"},
+			// Same resource as the BackendService below, in another
+			// API version.
+			BackendService: "https://www.googleapis.com/compute/beta/projects/proj/regions/us-central1/backendServices/bs",
+		},
+	}
+	s := New(Config[*ga.ForwardingRule]{
+		Resource: "forwardingRules",
+		List: func(ctx context.Context) ([]*ga.ForwardingRule, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			return append([]*ga.ForwardingRule(nil), objs...), nil
+		},
+	})
+	if s.HasSynced() {
+		t.Fatalf("HasSynced() = true before Sync")
+	}
+	if err := s.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() = %v", err)
+	}
+	if !s.HasSynced() {
+		t.Fatalf("HasSynced() = false after Sync")
+	}
+
+	for _, tc := range []struct {
+		name string
+		got  []*ga.ForwardingRule
+		want []string
+	}{
+		{name: "List", got: s.List(), want: []string{"fr1", "fr2"}},
+		{name: "ByLabel", got: s.ByLabel("app", "a"), want: []string{"fr1"}},
+		{name: "ByLabel no match", got: s.ByLabel("app", "c"), want: nil},
+		{name: "ByReference", got: s.ByReference(prefix + "global/targetHttpProxies/tp1"), want: []string{"fr1"}},
+		{name: "ByReference other version", got: s.ByReference(prefix + "regions/us-central1/backendServices/bs"), want: []string{"fr2"}},
+		{name: "ByReference self", got: s.ByReference(prefix + "global/forwardingRules/fr1"), want: nil},
+	} {
+		if !equal(names(tc.got), tc.want) {
+			t.Errorf("%s = %v, want %v", tc.name, names(tc.got), tc.want)
+		}
+	}
+
+	id := &cloud.ResourceID{ProjectID: "proj", Resource: "forwardingRules", Key: meta.RegionalKey("fr2", "us-central1")}
+	if obj, ok := s.Get(id); !ok || obj.Name != "fr2" {
+		t.Errorf("Get(%v) = %v, %t, want fr2", id, obj, ok)
+	}
+
+	// Deleted objects are removed from the indexes.
+	s.OnEvent(context.Background(), &cloud.Event{Type: cloud.EventDeleted, ResourceID: id})
+	if _, ok := s.Get(id); ok {
+		t.Errorf("Get(%v) found the object after EventDeleted", id)
+	}
+	if got := s.ByLabel("app", "b"); len(got) != 0 {
+		t.Errorf("ByLabel(app, b) = %v after EventDeleted, want none", names(got))
+	}
+	// Events of other resources are ignored.
+	other := &cloud.ResourceID{ProjectID: "proj", Resource: "backendServices", Key: meta.GlobalKey("fr1")}
+	s.OnEvent(context.Background(), &cloud.Event{Type: cloud.EventDeleted, ResourceID: other})
+	if got := s.List(); !equal(names(got), []string{"fr1"}) {
+		t.Errorf("List() = %v, want [fr1]", names(got))
+	}
+
+	// Resync replaces everything.
+	lock.Lock()
+	objs = objs[1:]
+	lock.Unlock()
+	if err := s.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() = %v", err)
+	}
+	if got := s.List(); !equal(names(got), []string{"fr2"}) {
+		t.Errorf("List() = %v after resync, want [fr2]", names(got))
+	}
+	if got := s.ByLabel("app", "a"); len(got) != 0 {
+		t.Errorf("ByLabel(app, a) = %v after resync, want none", names(got))
+	}
+}
+
+func TestStoreWithoutLabels(t *testing.T) {
+	t.Parallel()
+
+	// ga.Network has no Labels field.
+	s := New(Config[*ga.Network]{
+		Resource: "networks",
+		List: func(ctx context.Context) ([]*ga.Network, error) {
+			return []*ga.Network{{Name: "net", SelfLink: prefix + "global/networks/net"}}, nil
+		},
+	})
+	if err := s.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() = %v", err)
+	}
+	if got := s.List(); len(got) != 1 || got[0].Name != "net" {
+		t.Errorf("List() = %v, want [net]", got)
+	}
+	if got := s.ByLabel("app", "a"); len(got) != 0 {
+		t.Errorf("ByLabel(app, a) = %v, want none", got)
+	}
+}
+
+func TestStoreRun(t *testing.T) {
+	t.Parallel()
+
+	lists := make(chan struct{}, 10)
+	gets := make(chan *cloud.ResourceID, 10)
+	s := New(Config[*ga.ForwardingRule]{
+		Resource: "forwardingRules",
+		List: func(ctx context.Context) ([]*ga.ForwardingRule, error) {
+			lists <- struct{}{}
+			return nil, nil
+		},
+		Get: func(ctx context.Context, id *cloud.ResourceID) (*ga.ForwardingRule, error) {
+			defer func() { gets <- id }()
+			return &ga.ForwardingRule{Name: id.Key.Name, SelfLink: id.SelfLink(meta.VersionGA)}, nil
+		},
+		ResyncPeriod: time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	// The initial List and at least one resync.
+	for i := 0; i < 2; i++ {
+		select {
+		case <-lists:
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timeout waiting for List %d", i)
+		}
+	}
+
+	id := &cloud.ResourceID{ProjectID: "proj", Resource: "forwardingRules", Key: meta.GlobalKey("fr")}
+	s.OnEvent(ctx, &cloud.Event{Type: cloud.EventCreated, ResourceID: id})
+	select {
+	case <-gets:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timeout waiting for Get(%v)", id)
+	}
+	cancel()
+	<-done
+}
+
+func TestStoreRefreshAfterDelete(t *testing.T) {
+	t.Parallel()
+
+	id := &cloud.ResourceID{ProjectID: "proj", Resource: "forwardingRules", Key: meta.GlobalKey("fr")}
+	obj := &ga.ForwardingRule{Name: "fr", SelfLink: id.SelfLink(meta.VersionGA)}
+	getting := make(chan struct{})
+	release := make(chan struct{})
+	s := New(Config[*ga.ForwardingRule]{
+		Resource: "forwardingRules",
+		List: func(ctx context.Context) ([]*ga.ForwardingRule, error) {
+			return []*ga.ForwardingRule{obj}, nil
+		},
+		Get: func(ctx context.Context, id *cloud.ResourceID) (*ga.ForwardingRule, error) {
+			getting <- struct{}{}
+			<-release
+			return obj, nil
+		},
+	})
+	ctx := context.Background()
+	if err := s.Sync(ctx); err != nil {
+		t.Fatalf("Sync() = %v, want nil", err)
+	}
+
+	// The Get of the update returns after the object was deleted.
+	s.OnEvent(ctx, &cloud.Event{Type: cloud.EventUpdated, ResourceID: id})
+	<-getting
+	s.OnEvent(ctx, &cloud.Event{Type: cloud.EventDeleted, ResourceID: id})
+	close(release)
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		s.lock.RLock()
+		pending := len(s.events)
+		s.lock.RUnlock()
+		if pending == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for the refresh of %v", id)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got, ok := s.Get(id); ok {
+		t.Errorf("Get(%v) = %v, want the deleted object to stay deleted", id, got)
+	}
+
+	// A later update is applied.
+	s.OnEvent(ctx, &cloud.Event{Type: cloud.EventUpdated, ResourceID: id})
+	<-getting
+	for {
+		if _, ok := s.Get(id); ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for %v to be added", id)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestStoreAddIndex(t *testing.T) {
+	t.Parallel()
+
+	objs := []*ga.ForwardingRule{
+		{Name: "fr1", SelfLink: prefix + "global/forwardingRules/fr1", IPProtocol: "TCP"},
+		{Name: "fr2", SelfLink: prefix + "global/forwardingRules/fr2", IPProtocol: "UDP"},
+	}
+	s := New(Config[*ga.ForwardingRule]{
+		Resource: "forwardingRules",
+		List: func(ctx context.Context) ([]*ga.ForwardingRule, error) {
+			return objs, nil
+		},
+	})
+	if err := s.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() = %v", err)
+	}
+	protocol := func(fr *ga.ForwardingRule) []string { return []string{fr.IPProtocol} }
+	if err := s.AddIndex("protocol", protocol); err != nil {
+		t.Fatalf("AddIndex(protocol) = %v", err)
+	}
+	if err := s.AddIndex("protocol", protocol); err == nil {
+		t.Errorf("AddIndex(protocol) twice = nil, want error")
+	}
+	if err := s.AddIndex(IndexLabel, protocol); err == nil {
+		t.Errorf("AddIndex(%s) = nil, want error", IndexLabel)
+	}
+
+	// The objects are indexed when added and removed.
+	if got, err := s.ByIndex("protocol", "UDP"); err != nil || !equal(names(got), []string{"fr2"}) {
+		t.Errorf("ByIndex(protocol, UDP) = %v, %v; want [fr2]", names(got), err)
+	}
+	objs = objs[:1]
+	if err := s.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() = %v", err)
+	}
+	if got, err := s.ByIndex("protocol", "UDP"); err != nil || len(got) != 0 {
+		t.Errorf("ByIndex(protocol, UDP) = %v, %v after resync; want none", names(got), err)
+	}
+	if got, err := s.ByIndex("protocol", "TCP"); err != nil || !equal(names(got), []string{"fr1"}) {
+		t.Errorf("ByIndex(protocol, TCP) = %v, %v; want [fr1]", names(got), err)
+	}
+	if _, err := s.ByIndex("unknown", "x"); err == nil {
+		t.Errorf("ByIndex(unknown) = nil error, want error")
+	}
+}
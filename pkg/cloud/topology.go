@@ -0,0 +1,150 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// Topology answers questions about the relationship between zones and
+// regions. Zone and region arguments may be given in any form accepted by
+// meta.NormalizeLocation.
+type Topology interface {
+	// RegionForZone returns the region that zone belongs to.
+	RegionForZone(ctx context.Context, zone string) (string, error)
+	// ZonesInRegion returns the zones in region, sorted by name.
+	ZonesInRegion(ctx context.Context, region string) ([]string, error)
+}
+
+// NewTopology returns a Topology that is populated by listing the Zones
+// using c. The list is fetched on first use and cached until Reset() is
+// called.
+func NewTopology(c Cloud) *CachedTopology {
+	return &CachedTopology{c: c}
+}
+
+// CachedTopology implements Topology using the Zones API.
+type CachedTopology struct {
+	c Cloud
+
+	lock  sync.Mutex
+	table *StaticTopology
+}
+
+// CachedTopology implements Topology.
+var _ Topology = (*CachedTopology)(nil)
+
+// RegionForZone implements Topology.
+func (t *CachedTopology) RegionForZone(ctx context.Context, zone string) (string, error) {
+	table, err := t.load(ctx)
+	if err != nil {
+		return "", err
+	}
+	return table.RegionForZone(ctx, zone)
+}
+
+// ZonesInRegion implements Topology.
+func (t *CachedTopology) ZonesInRegion(ctx context.Context, region string) ([]string, error) {
+	table, err := t.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return table.ZonesInRegion(ctx, region)
+}
+
+// Reset drops the cached topology. The next call will re-list the Zones.
+func (t *CachedTopology) Reset() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.table = nil
+}
+
+func (t *CachedTopology) load(ctx context.Context) (*StaticTopology, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.table != nil {
+		return t.table, nil
+	}
+	zones, err := t.c.Zones().List(ctx, filter.None)
+	if err != nil {
+		return nil, fmt.Errorf("CachedTopology: listing zones: %w", err)
+	}
+	regionZones := map[string][]string{}
+	for _, z := range zones {
+		region := meta.NormalizeLocation(z.Region)
+		regionZones[region] = append(regionZones[region], z.Name)
+	}
+	t.table = NewStaticTopology(regionZones)
+	return t.table, nil
+}
+
+// NewStaticTopology returns a Topology from a fixed table of region to the
+// zones in the region. This is intended for tests and for environments where
+// the topology is known in advance.
+func NewStaticTopology(regionZones map[string][]string) *StaticTopology {
+	ret := &StaticTopology{
+		zoneToRegion: map[string]string{},
+		regionZones:  map[string][]string{},
+	}
+	for region, zones := range regionZones {
+		region = meta.NormalizeLocation(region)
+		for _, zone := range zones {
+			zone = meta.NormalizeLocation(zone)
+			ret.zoneToRegion[zone] = region
+			ret.regionZones[region] = append(ret.regionZones[region], zone)
+		}
+	}
+	for _, zones := range ret.regionZones {
+		sort.Strings(zones)
+	}
+	return ret
+}
+
+// StaticTopology is a Topology backed by a fixed table.
+type StaticTopology struct {
+	zoneToRegion map[string]string
+	regionZones  map[string][]string
+}
+
+// StaticTopology implements Topology.
+var _ Topology = (*StaticTopology)(nil)
+
+// RegionForZone implements Topology.
+func (t *StaticTopology) RegionForZone(_ context.Context, zone string) (string, error) {
+	region, ok := t.zoneToRegion[meta.NormalizeLocation(zone)]
+	if !ok {
+		return "", fmt.Errorf("zone %q not found in topology", zone)
+	}
+	return region, nil
+}
+
+// ZonesInRegion implements Topology.
+func (t *StaticTopology) ZonesInRegion(_ context.Context, region string) ([]string, error) {
+	zones, ok := t.regionZones[meta.NormalizeLocation(region)]
+	if !ok {
+		return nil, fmt.Errorf("region %q not found in topology", region)
+	}
+	return append([]string{}, zones...), nil
+}
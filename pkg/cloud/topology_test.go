@@ -0,0 +1,123 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	ga "google.golang.org/api/compute/v1"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+func TestCachedTopology(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"mock-project"})
+	for _, z := range []struct{ zone, region string }{
+		{"us-central1-a", "us-central1"},
+		{"us-central1-b", "us-central1"},
+		{"europe-west1-b", "europe-west1"},
+	} {
+		key := meta.GlobalKey(z.zone)
+		mock.MockZones.Objects[*key] = mock.MockZones.Obj(&ga.Zone{
+			Name:   z.zone,
+			Region: SelfLink(meta.VersionGA, "mock-project", "regions", meta.GlobalKey(z.region)),
+		})
+	}
+	var listCalls int
+	mock.MockZones.ListHook = func(context.Context, *filter.F, *MockZones) (bool, []*ga.Zone, error) {
+		listCalls++
+		return false, nil, nil
+	}
+
+	topo := NewTopology(mock)
+
+	for _, tc := range []struct {
+		zone    string
+		want    string
+		wantErr bool
+	}{
+		{zone: "us-central1-a", want: "us-central1"},
+		{zone: "zones/US-CENTRAL1-B", want: "us-central1"},
+		{zone: "europe-west1-b", want: "europe-west1"},
+		{zone: "asia-east1-a", wantErr: true},
+	} {
+		got, err := topo.RegionForZone(ctx, tc.zone)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("RegionForZone(%q) = _, %v; gotErr = %t, want %t", tc.zone, err, gotErr, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("RegionForZone(%q) = %q, nil; want %q", tc.zone, got, tc.want)
+		}
+	}
+
+	got, err := topo.ZonesInRegion(ctx, "us-central1")
+	if want := []string{"us-central1-a", "us-central1-b"}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ZonesInRegion(us-central1) = %v, %v; want %v, nil", got, err, want)
+	}
+	if _, err := topo.ZonesInRegion(ctx, "asia-east1"); err == nil {
+		t.Errorf("ZonesInRegion(asia-east1) = _, nil; want error")
+	}
+	if listCalls != 1 {
+		t.Errorf("Zones().List() called %d times, want 1", listCalls)
+	}
+
+	topo.Reset()
+	if _, err := topo.RegionForZone(ctx, "us-central1-a"); err != nil {
+		t.Errorf("RegionForZone() = _, %v; want nil", err)
+	}
+	if listCalls != 2 {
+		t.Errorf("Zones().List() called %d times after Reset(), want 2", listCalls)
+	}
+}
+
+func TestCachedTopologyListError(t *testing.T) {
+	t.Parallel()
+
+	mock := NewMockGCE(&SingleProjectRouter{"mock-project"})
+	errInjected := errors.New("injected")
+	mock.MockZones.ListError = &errInjected
+
+	topo := NewTopology(mock)
+	if _, err := topo.RegionForZone(context.Background(), "us-central1-a"); !errors.Is(err, errInjected) {
+		t.Errorf("RegionForZone() = _, %v; want %v", err, errInjected)
+	}
+}
+
+func TestStaticTopology(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	topo := NewStaticTopology(map[string][]string{
+		"us-central1": {"us-central1-c", "us-central1-a"},
+	})
+
+	if got, err := topo.RegionForZone(ctx, "us-central1-c"); err != nil || got != "us-central1" {
+		t.Errorf("RegionForZone(us-central1-c) = %q, %v; want us-central1, nil", got, err)
+	}
+	got, err := topo.ZonesInRegion(ctx, "regions/us-central1")
+	if want := []string{"us-central1-a", "us-central1-c"}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ZonesInRegion(us-central1) = %v, %v; want %v, nil", got, err, want)
+	}
+}
@@ -0,0 +1,320 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/go-logr/logr"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	networkconnectivity "google.golang.org/api/networkconnectivity/v1"
+	networkservices "google.golang.org/api/networkservices/v1"
+)
+
+// Span attribute keys set on the spans for calls to the API.
+const (
+	AttributeProjectID     = "gcp.project_id"
+	AttributeService       = "gcp.compute.service"
+	AttributeOperation     = "gcp.compute.operation"
+	AttributeVersion       = "gcp.compute.api_version"
+	AttributeKey           = "gcp.compute.key"
+	AttributeOperationName = "gcp.compute.operation_name"
+	AttributeRequestID     = "gcp.compute.request_id"
+	// AttributeOperationInsertTime is the time the operation was created,
+	// for computing the end-to-end latency of waits that are resumed.
+	AttributeOperationInsertTime = "gcp.compute.operation_insert_time"
+	AttributeOperationPolls      = "gcp.compute.operation_polls"
+	// AttributeResumed is set on the wait Span if the wait is not part of
+	// the call that returned the operation.
+	AttributeResumed    = "gcp.compute.resumed"
+	AttributeHTTPStatus = "http.status_code"
+)
+
+// Attribute is a key-value pair attached to a Span.
+type Attribute struct {
+	Key string
+	// Value is a string or an int.
+	Value interface{}
+}
+
+// Tracer creates a Span for each call to the API. The interface follows
+// the shape of the OpenTelemetry trace API; package
+// github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/otel adapts
+// an OpenTelemetry TracerProvider:
+//
+//	svc := &cloud.Service{
+//		...
+//		Tracer: otel.NewTracer(tp),
+//	}
+type Tracer interface {
+	// Start a Span named name. The returned context is used for the
+	// call, so spans created by the underlying client are children of
+	// the Span.
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span is a single traced call to the API.
+type Span interface {
+	// SetAttributes adds attrs to the Span.
+	SetAttributes(attrs ...Attribute)
+	// End the Span. err is the result of the call and may be nil.
+	End(err error)
+}
+
+var callStateContextKey = contextKey("call state")
+
+// callState is the state of an in-flight call kept in its context between
+// callStart and callEnd.
+type callState struct {
+	ck    *CallContextKey
+	start time.Time
+	key   *meta.Key
+	span  Span
+	log   logr.Logger
+
+	auditor   Auditor
+	onError   func(context.Context, *CallError)
+	events    *EventBus
+	headers   http.Header
+	diag      *callDiag
+	requestID string
+	watch     *callWatch
+	// opPending is set when the call returned a long running operation.
+	// The call is audited and its Event published when the operation is
+	// done.
+	opPending bool
+	audited   bool
+}
+
+// callStart starts the CallObserver and the Span for the call identified by
+// ck. key may be nil for calls that are not on a single resource (e.g.
+// List). The returned context must be used for the rest of the call and
+// passed to callEnd.
+func (s *Service) callStart(ctx context.Context, ck *CallContextKey, key *meta.Key) context.Context {
+	callObserverStart(ctx, ck)
+	state := &callState{ck: ck, start: time.Now(), key: key, log: s.Logger, auditor: s.Auditor, onError: s.OnError, events: s.Events}
+	if s.Tracer != nil {
+		attrs := []Attribute{
+			{Key: AttributeProjectID, Value: ck.ProjectID},
+			{Key: AttributeService, Value: ck.Service},
+			{Key: AttributeOperation, Value: ck.Operation},
+			{Key: AttributeVersion, Value: string(ck.Version)},
+		}
+		if key != nil {
+			attrs = append(attrs, Attribute{Key: AttributeKey, Value: key.String()})
+		}
+		ctx, state.span = s.Tracer.Start(ctx, fmt.Sprintf("%s.%s", ck.Service, ck.Operation), attrs...)
+	}
+	ctx = s.Headers.start(ctx, state)
+	ctx = s.startDiag(ctx, state)
+	ctx = context.WithValue(ctx, callStateContextKey, state)
+	s.startWatch(ctx, state)
+	return ctx
+}
+
+// callEnd ends the CallObserver and the Span started by callStart, logs the
+// call, reports its error to OnError and audits it unless it is waiting for
+// an operation.
+func callEnd(ctx context.Context, ck *CallContextKey, err error) {
+	callObserverEnd(ctx, ck, err)
+	state, ok := ctx.Value(callStateContextKey).(*callState)
+	if !ok {
+		return
+	}
+	if err != nil || !state.opPending {
+		state.watch.stop()
+		state.audit(ctx, err)
+		state.diag.end(ctx, state, err)
+	}
+	if err != nil && state.onError != nil {
+		state.onError(ctx, &CallError{CallContextKey: ck, Key: state.key, Class: ClassifyError(err), RequestID: state.requestID, Err: err})
+	}
+	logCall(state.log, ck, state.key, state.requestID, time.Since(state.start), err)
+	if state.span == nil {
+		return
+	}
+	if code := httpStatus(err); code != 0 {
+		state.span.SetAttributes(Attribute{Key: AttributeHTTPStatus, Value: code})
+	}
+	state.span.End(err)
+}
+
+// callSend is called with the headers h of the request of the call in ctx
+// after the RateLimiter accepted the call. It sets the CallHeaders and the
+// preconditions of ctx on h. h is nil for gRPC calls, which get the
+// CallHeaders from the context.
+func callSend(ctx context.Context, h http.Header) {
+	if h != nil {
+		setPreconditions(ctx, h)
+	}
+	state, ok := ctx.Value(callStateContextKey).(*callState)
+	if !ok {
+		return
+	}
+	if h != nil {
+		for k, vs := range state.headers {
+			h[k] = vs
+		}
+	}
+	state.diag.sending()
+}
+
+// callSetOperation records that the call in ctx returned the long running
+// operation op and adds its name to the Span.
+func callSetOperation(ctx context.Context, op interface{}) {
+	state, ok := ctx.Value(callStateContextKey).(*callState)
+	if !ok {
+		return
+	}
+	name, _ := operationInfo(op)
+	if name == "" {
+		return
+	}
+	state.opPending = true
+	state.diag.waiting()
+	if state.watch != nil {
+		state.watch.waiting.Store(true)
+	}
+	if state.span != nil {
+		state.span.SetAttributes(Attribute{Key: AttributeOperationName, Value: name})
+	}
+}
+
+// operationInfo returns the name and the insert time of the long running
+// operation op. name is empty if op is nil or not an operation.
+func operationInfo(op interface{}) (name, insertTime string) {
+	switch o := op.(type) {
+	case *ga.Operation:
+		if o != nil {
+			return o.Name, o.InsertTime
+		}
+	case *alpha.Operation:
+		if o != nil {
+			return o.Name, o.InsertTime
+		}
+	case *beta.Operation:
+		if o != nil {
+			return o.Name, o.InsertTime
+		}
+	case *networkconnectivity.GoogleLongrunningOperation:
+		if o != nil {
+			return o.Name, ""
+		}
+	case *networkservices.Operation:
+		if o != nil {
+			return o.Name, ""
+		}
+	case *compute.Operation:
+		if o != nil {
+			return o.Name(), o.Proto().GetInsertTime()
+		}
+	}
+	return "", ""
+}
+
+var opWaitContextKey = contextKey("operation wait")
+
+// opWait is the trace of a wait for a long running operation.
+type opWait struct {
+	span Span
+	// kv are logged with each poll of the operation.
+	kv    []interface{}
+	polls int
+}
+
+// opWaitFromContext returns the opWait started in ctx by waitStart or an
+// empty opWait.
+func opWaitFromContext(ctx context.Context) *opWait {
+	if w, ok := ctx.Value(opWaitContextKey).(*opWait); ok {
+		return w
+	}
+	return &opWait{}
+}
+
+// waitStart starts the trace of the wait for op. The wait is correlated
+// with the call in ctx that returned op: the Span is a child of the Span
+// of the call and the logs have the fields of the call. A wait that is
+// resumed outside of the call (e.g. WaitForCompletion after a restart) has
+// the operation name, which is also on the Span of the call.
+func (s *Service) waitStart(ctx context.Context, op interface{}) (context.Context, *opWait) {
+	name, insertTime := operationInfo(op)
+	w := &opWait{kv: []interface{}{"opName", name}}
+	attrs := []Attribute{{Key: AttributeOperationName, Value: name}}
+	if insertTime != "" {
+		attrs = append(attrs, Attribute{Key: AttributeOperationInsertTime, Value: insertTime})
+	}
+	spanName := "Operation.Wait"
+	if state, ok := ctx.Value(callStateContextKey).(*callState); ok {
+		spanName = fmt.Sprintf("%s.%s.Wait", state.ck.Service, state.ck.Operation)
+		attrs = append(attrs,
+			Attribute{Key: AttributeProjectID, Value: state.ck.ProjectID},
+			Attribute{Key: AttributeService, Value: state.ck.Service},
+			Attribute{Key: AttributeOperation, Value: state.ck.Operation},
+			Attribute{Key: AttributeVersion, Value: string(state.ck.Version)},
+		)
+		w.kv = append(w.kv, "resource", state.ck.Service, "verb", state.ck.Operation)
+		if state.key != nil {
+			attrs = append(attrs, Attribute{Key: AttributeKey, Value: state.key.String()})
+			w.kv = append(w.kv, "key", state.key.String())
+		}
+		if state.requestID != "" {
+			attrs = append(attrs, Attribute{Key: AttributeRequestID, Value: state.requestID})
+			w.kv = append(w.kv, "requestId", state.requestID)
+		}
+	} else {
+		attrs = append(attrs, Attribute{Key: AttributeResumed, Value: "true"})
+		w.kv = append(w.kv, "resumed", true)
+	}
+	if s.Tracer != nil {
+		ctx, w.span = s.Tracer.Start(ctx, spanName, attrs...)
+	}
+	return context.WithValue(ctx, opWaitContextKey, w), w
+}
+
+// end the trace of the wait with the result err.
+func (w *opWait) end(err error) {
+	if w.span == nil {
+		return
+	}
+	w.span.SetAttributes(Attribute{Key: AttributeOperationPolls, Value: w.polls})
+	if code := httpStatus(err); code != 0 {
+		w.span.SetAttributes(Attribute{Key: AttributeHTTPStatus, Value: code})
+	}
+	w.span.End(err)
+}
+
+// httpStatus returns the HTTP status code for the result of a call or 0 if
+// it is not known.
+func httpStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		return gerr.Code
+	}
+	return 0
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+)
+
+type fakeSpan struct {
+	name  string
+	attrs map[string]interface{}
+	ended bool
+	err   error
+}
+
+func (s *fakeSpan) SetAttributes(attrs ...Attribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *fakeSpan) End(err error) {
+	s.ended = true
+	s.err = err
+}
+
+type fakeTracer struct {
+	lock  sync.Mutex
+	spans []*fakeSpan
+}
+
+func (t *fakeTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	s := &fakeSpan{name: name, attrs: map[string]interface{}{}}
+	s.SetAttributes(attrs...)
+	t.spans = append(t.spans, s)
+	return ctx, s
+}
+
+type errRateLimiter struct{ err error }
+
+func (r *errRateLimiter) Accept(context.Context, *RateLimitKey) error   { return r.err }
+func (r *errRateLimiter) Observe(context.Context, error, *RateLimitKey) {}
+
+func TestTracer(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/proj/regions/us-central1/addresses/a1", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "a1"})
+	})
+	mux.HandleFunc("/projects/proj/regions/us-central1/addresses", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":     "op-1",
+			"status":   "DONE",
+			"selfLink": "https://www.googleapis.com/compute/v1/projects/proj/regions/us-central1/operations/op-1",
+		})
+	})
+	mux.HandleFunc("/projects/proj/regions/us-central1/operations/op-1/wait", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "op-1", "status": "DONE"})
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+
+	errRateLimited := errors.New("rate limited")
+	for _, tc := range []struct {
+		desc      string
+		rl        RateLimiter
+		call      func(Cloud) error
+		wantName  string
+		wantAttrs map[string]interface{}
+		// wantRequestID is true if the span has a generated requestId.
+		wantRequestID bool
+		// wantWait are the attributes of the span of the wait for the
+		// operation, if any.
+		wantWait map[string]interface{}
+		wantErr  bool
+	}{
+		{
+			desc: "get",
+			rl:   &NopRateLimiter{},
+			call: func(c Cloud) error {
+				_, err := c.Addresses().Get(ctx, meta.RegionalKey("a1", "us-central1"))
+				return err
+			},
+			wantName: "Addresses.Get",
+			wantAttrs: map[string]interface{}{
+				AttributeProjectID:  "proj",
+				AttributeService:    "Addresses",
+				AttributeOperation:  "Get",
+				AttributeVersion:    "ga",
+				AttributeKey:        "Key{\"a1\", region: \"us-central1\"}",
+				AttributeHTTPStatus: http.StatusOK,
+			},
+		},
+		{
+			desc: "not found",
+			rl:   &NopRateLimiter{},
+			call: func(c Cloud) error {
+				_, err := c.Addresses().Get(ctx, meta.RegionalKey("a2", "us-central1"))
+				return err
+			},
+			wantName: "Addresses.Get",
+			wantAttrs: map[string]interface{}{
+				AttributeProjectID:  "proj",
+				AttributeService:    "Addresses",
+				AttributeOperation:  "Get",
+				AttributeVersion:    "ga",
+				AttributeKey:        "Key{\"a2\", region: \"us-central1\"}",
+				AttributeHTTPStatus: http.StatusNotFound,
+			},
+			wantErr: true,
+		},
+		{
+			desc: "insert",
+			rl:   &NopRateLimiter{},
+			call: func(c Cloud) error {
+				return c.Addresses().Insert(ctx, meta.RegionalKey("a1", "us-central1"), &ga.Address{})
+			},
+			wantName: "Addresses.Insert",
+			wantAttrs: map[string]interface{}{
+				AttributeProjectID:     "proj",
+				AttributeService:       "Addresses",
+				AttributeOperation:     "Insert",
+				AttributeVersion:       "ga",
+				AttributeKey:           "Key{\"a1\", region: \"us-central1\"}",
+				AttributeOperationName: "op-1",
+				AttributeHTTPStatus:    http.StatusOK,
+			},
+			wantRequestID: true,
+			wantWait: map[string]interface{}{
+				AttributeProjectID:      "proj",
+				AttributeService:        "Addresses",
+				AttributeOperation:      "Insert",
+				AttributeVersion:        "ga",
+				AttributeKey:            "Key{\"a1\", region: \"us-central1\"}",
+				AttributeOperationName:  "op-1",
+				AttributeOperationPolls: 1,
+				AttributeHTTPStatus:     http.StatusOK,
+			},
+		},
+		{
+			desc: "list",
+			rl:   &errRateLimiter{err: errRateLimited},
+			call: func(c Cloud) error {
+				_, err := c.Addresses().List(ctx, "us-central1", nil)
+				return err
+			},
+			wantName: "Addresses.List",
+			wantAttrs: map[string]interface{}{
+				AttributeProjectID: "proj",
+				AttributeService:   "Addresses",
+				AttributeOperation: "List",
+				AttributeVersion:   "ga",
+			},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			tracer := &fakeTracer{}
+			c := NewGCE(&Service{
+				GA:            client,
+				ProjectRouter: &SingleProjectRouter{"proj"},
+				RateLimiter:   tc.rl,
+				Tracer:        tracer,
+			})
+			err := tc.call(c)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("call() = %v, want error %t", err, tc.wantErr)
+			}
+			wantSpans := 1
+			if tc.wantWait != nil {
+				wantSpans++
+			}
+			if len(tracer.spans) != wantSpans {
+				t.Fatalf("got %d spans, want %d", len(tracer.spans), wantSpans)
+			}
+			if tc.wantWait != nil {
+				wait := tracer.spans[1]
+				if want := tc.wantName + ".Wait"; wait.name != want || !wait.ended {
+					t.Errorf("wait span = %q, ended %t; want %q, ended", wait.name, wait.ended, want)
+				}
+				if wait.attrs[AttributeRequestID] != tracer.spans[0].attrs[AttributeRequestID] {
+					t.Errorf("wait span requestId = %v, want %v", wait.attrs[AttributeRequestID], tracer.spans[0].attrs[AttributeRequestID])
+				}
+				delete(wait.attrs, AttributeRequestID)
+				if diff := cmp.Diff(tc.wantWait, wait.attrs); diff != "" {
+					t.Errorf("wait span attrs: -want +got: %s", diff)
+				}
+			}
+			span := tracer.spans[0]
+			if span.name != tc.wantName {
+				t.Errorf("span.name = %q, want %q", span.name, tc.wantName)
+			}
+			if id, ok := span.attrs[AttributeRequestID]; ok != tc.wantRequestID || (ok && id == "") {
+				t.Errorf("span.attrs[%q] = %v, want set: %t", AttributeRequestID, id, tc.wantRequestID)
+			}
+			delete(span.attrs, AttributeRequestID)
+			if diff := cmp.Diff(tc.wantAttrs, span.attrs); diff != "" {
+				t.Errorf("span.attrs: -want +got: %s", diff)
+			}
+			if !span.ended || (span.err != nil) != tc.wantErr {
+				t.Errorf("span.ended = %t, span.err = %v; want true, error %t", span.ended, span.err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestTracerResumedWait(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/proj/global/operations/op-1/wait", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "op-1", "status": "DONE"})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+	tracer := &fakeTracer{}
+	svc := &Service{
+		GA:            client,
+		ProjectRouter: &SingleProjectRouter{"proj"},
+		RateLimiter:   &NopRateLimiter{},
+		Tracer:        tracer,
+	}
+	// The operation returned by a call before a restart.
+	op := &ga.Operation{
+		Name:       "op-1",
+		InsertTime: "2023-01-02T03:04:05.000-07:00",
+		SelfLink:   "https://www.googleapis.com/compute/v1/projects/proj/global/operations/op-1",
+	}
+	if err := svc.WaitForCompletion(ctx, op); err != nil {
+		t.Fatalf("WaitForCompletion() = %v, want nil", err)
+	}
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(tracer.spans))
+	}
+	span := tracer.spans[0]
+	want := map[string]interface{}{
+		AttributeOperationName:       "op-1",
+		AttributeOperationInsertTime: "2023-01-02T03:04:05.000-07:00",
+		AttributeResumed:             "true",
+		AttributeOperationPolls:      1,
+		AttributeHTTPStatus:          http.StatusOK,
+	}
+	if span.name != "Operation.Wait" || !span.ended {
+		t.Errorf("span = %q, ended %t; want %q, ended", span.name, span.ended, "Operation.Wait")
+	}
+	if diff := cmp.Diff(want, span.attrs); diff != "" {
+		t.Errorf("span.attrs: -want +got: %s", diff)
+	}
+}
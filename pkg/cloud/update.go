@@ -0,0 +1,89 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/gcerrors"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"k8s.io/klog/v2"
+)
+
+// UpdateAttempts is the number of times UpdateWithRetry writes the object
+// before giving up on conflicts.
+const UpdateAttempts = 5
+
+// UpdateWithRetry does a read-modify-write of the object key: it gets the
+// latest object, applies mutate and writes it back with update. The
+// object carries the fingerprint returned by get, so the write fails with
+// 412 Precondition Failed if the object was changed in between; this is
+// retried up to UpdateAttempts times. The error of mutate is returned
+// without writing the object. Nothing is written if mutate does not change
+// the object.
+//
+// The generated Update<Resource>WithRetry functions use UpdateWithRetry with the
+// Get and the Update (or Patch) of the resource:
+//
+//	err := cloud.UpdateBackendServicesWithRetry(ctx, gce.BackendServices(), key, func(bs *ga.BackendService) error {
+//		bs.TimeoutSec = 60
+//		return nil
+//	})
+func UpdateWithRetry[T any](ctx context.Context, key *meta.Key, get func(context.Context, *meta.Key) (T, error), update func(context.Context, *meta.Key, T) error, mutate func(T) error) error {
+	var err error
+	for attempt := 1; attempt <= UpdateAttempts; attempt++ {
+		var obj T
+		obj, err = get(ctx, key)
+		if err != nil {
+			return err
+		}
+		orig := DeepCopy(obj)
+		if err := mutate(obj); err != nil {
+			return err
+		}
+		if reflect.DeepEqual(orig, obj) {
+			klog.V(4).Infof("UpdateWithRetry(%v): no change", key)
+			return nil
+		}
+		err = update(ctx, key, obj)
+		if !gcerrors.IsPreconditionFailed(err) {
+			return err
+		}
+		klog.V(2).Infof("UpdateWithRetry(%v): conflict on attempt %d: %v", key, attempt, err)
+	}
+	return err
+}
+
+// Refresh returns a function that gets the latest object key, applies
+// mutate to it and stores it in obj, so that the call that writes obj is
+// retried with the latest fingerprint. It is used with WithRefresh for the
+// calls retried by RetryPolicy.RefreshOnConflict; UpdateWithRetry does the
+// same without the retry layer.
+func Refresh[T any](get func(context.Context, *meta.Key) (*T, error), key *meta.Key, obj *T, mutate func(*T) error) func(context.Context) error {
+	return func(ctx context.Context) error {
+		latest, err := get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if err := mutate(latest); err != nil {
+			return err
+		}
+		*obj = *latest
+		return nil
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+func TestUpdateWithRetry(t *testing.T) {
+	t.Parallel()
+
+	conflict := &googleapi.Error{Code: http.StatusPreconditionFailed}
+	errMutate := errors.New("mutate")
+	key := meta.GlobalKey("bs")
+
+	for _, tc := range []struct {
+		name       string
+		conflicts  int
+		mutate     func(*ga.BackendService) error
+		wantErr    error
+		wantGets   int
+		wantWrites int
+	}{
+		{
+			name:       "no conflict",
+			mutate:     func(bs *ga.BackendService) error { bs.TimeoutSec = 60; return nil },
+			wantGets:   1,
+			wantWrites: 1,
+		},
+		{
+			name:       "conflicts",
+			conflicts:  2,
+			mutate:     func(bs *ga.BackendService) error { bs.TimeoutSec = 60; return nil },
+			wantGets:   3,
+			wantWrites: 3,
+		},
+		{
+			name:       "too many conflicts",
+			conflicts:  UpdateAttempts,
+			mutate:     func(bs *ga.BackendService) error { bs.TimeoutSec = 60; return nil },
+			wantErr:    conflict,
+			wantGets:   UpdateAttempts,
+			wantWrites: UpdateAttempts,
+		},
+		{
+			name:     "mutate error",
+			mutate:   func(bs *ga.BackendService) error { return errMutate },
+			wantErr:  errMutate,
+			wantGets: 1,
+		},
+		{
+			name:     "no change",
+			mutate:   func(bs *ga.BackendService) error { bs.TimeoutSec = 30; return nil },
+			wantGets: 1,
+		},
+	} {
+		var gets, writes int
+		get := func(ctx context.Context, key *meta.Key) (*ga.BackendService, error) {
+			gets++
+			return &ga.BackendService{Name: key.Name, TimeoutSec: 30}, nil
+		}
+		update := func(ctx context.Context, key *meta.Key, bs *ga.BackendService) error {
+			writes++
+			if writes <= tc.conflicts {
+				return conflict
+			}
+			return nil
+		}
+		err := UpdateWithRetry(context.Background(), key, get, update, tc.mutate)
+		if !errors.Is(err, tc.wantErr) || gets != tc.wantGets || writes != tc.wantWrites {
+			t.Errorf("%s: UpdateWithRetry() = %v after %d gets and %d writes; want %v after %d and %d",
+				tc.name, err, gets, writes, tc.wantErr, tc.wantGets, tc.wantWrites)
+		}
+	}
+}
+
+func TestUpdateBackendServicesWithRetry(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"mock-project"})
+	key := meta.GlobalKey("bs")
+	if err := mock.BackendServices().Insert(ctx, key, &ga.BackendService{TimeoutSec: 30}); err != nil {
+		t.Fatalf("BackendServices().Insert(%v, %v, _) = %v; want nil", ctx, key, err)
+	}
+
+	// The first Update races with another client.
+	var updates int
+	mock.MockBackendServices.UpdateHook = func(ctx context.Context, key *meta.Key, obj *ga.BackendService, m *MockBackendServices) error {
+		updates++
+		if updates == 1 {
+			m.Objects[*key] = &MockBackendServicesObj{&ga.BackendService{Name: key.Name, TimeoutSec: 30, Description: "other client"}}
+			return &googleapi.Error{Code: http.StatusPreconditionFailed}
+		}
+		m.Objects[*key] = &MockBackendServicesObj{obj}
+		return nil
+	}
+	err := UpdateBackendServicesWithRetry(ctx, mock.BackendServices(), key, func(bs *ga.BackendService) error {
+		bs.TimeoutSec = 60
+		return nil
+	})
+	if err != nil || updates != 2 {
+		t.Fatalf("UpdateBackendServicesWithRetry() = %v after %d updates; want nil after 2", err, updates)
+	}
+	bs, err := mock.BackendServices().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("BackendServices().Get(%v, %v) = _, %v; want nil", ctx, key, err)
+	}
+	if bs.TimeoutSec != 60 || bs.Description != "other client" {
+		t.Errorf("BackendServices().Get(%v, %v) = %+v; want TimeoutSec=60 and the Description of the other client", ctx, key, bs)
+	}
+}
@@ -17,8 +17,10 @@ limitations under the License.
 package cloud
 
 import (
-	"encoding/json"
+	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
@@ -54,12 +56,8 @@ func (r *ResourceID) Equal(other *ResourceID) bool {
 		return false
 	case r.ProjectID != other.ProjectID || r.Resource != other.Resource:
 		return false
-	case r.Key != nil && other.Key != nil:
-		return *r.Key == *other.Key
-	case r.Key == nil && other.Key == nil:
-		return true
 	default:
-		return false
+		return r.Key.Equal(other.Key)
 	}
 }
 
@@ -182,12 +180,30 @@ func ParseResourceURL(url string) (*ResourceID, error) {
 	return nil, errNotValid
 }
 
-func copyViaJSON(dest, src interface{}) error {
-	bytes, err := json.Marshal(src)
-	if err != nil {
-		return err
+// normalizeKey returns key with its locations normalized, see
+// meta.Key.Normalize. The calls accept the same keys as the mocks, e.g. a
+// region URL. key is returned as is if it is already normalized, so that
+// the Interceptors and OnError see the key of the caller.
+func normalizeKey(key *meta.Key) *meta.Key {
+	if nk := key.Normalize(); *nk != *key {
+		return nk
+	}
+	return key
+}
+
+// mockLabelFingerprint returns a fingerprint of the labels. The mocks use this
+// to emulate the labelFingerprint maintained by the API.
+func mockLabelFingerprint(labels map[string]string) string {
+	var keys []string
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%s\n", k, labels[k])
 	}
-	return json.Unmarshal(bytes, dest)
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)[:8])
 }
 
 // ResourcePath returns the path starting from the location.
@@ -0,0 +1,155 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// Watchdog reports calls that are still in progress after a threshold. A
+// call is in progress until it returns, including the wait for its long
+// running operation. A slow call is reported again every time the
+// threshold elapses until it ends.
+//
+//	svc.Watchdog = &cloud.Watchdog{
+//		Default: time.Minute,
+//		Thresholds: map[cloud.CallSelector]time.Duration{
+//			{Service: "Instances", Operation: "Insert"}: 5 * time.Minute,
+//		},
+//		OnSlowCall: metrics.OnSlowCall(recorder),
+//	}
+type Watchdog struct {
+	// Default threshold for the calls that do not match Thresholds. Zero
+	// disables the watchdog for these calls.
+	Default time.Duration
+	// Thresholds by resource and verb. The most specific match is used:
+	// {Service, Operation}, then {Service, ""}, then {"", Operation}.
+	Thresholds map[CallSelector]time.Duration
+	// OnSlowCall is called when a call is slow, in addition to the
+	// Service.Logger. This may be nil.
+	OnSlowCall func(ctx context.Context, c *SlowCall)
+}
+
+// SlowCall describes a call that exceeded its Watchdog threshold.
+type SlowCall struct {
+	// CallContextKey of the call.
+	CallContextKey *CallContextKey
+	// Key of the resource. This is nil if the call is not on a single
+	// resource.
+	Key *meta.Key
+	// Elapsed time since the start of the call.
+	Elapsed time.Duration
+	// Threshold that was exceeded.
+	Threshold time.Duration
+	// Waiting is true if the call is waiting for its long running
+	// operation.
+	Waiting bool
+}
+
+// threshold returns the threshold for the call or 0 if it is not watched.
+func (w *Watchdog) threshold(ck *CallContextKey) time.Duration {
+	for _, sel := range []CallSelector{
+		{Service: ck.Service, Operation: ck.Operation},
+		{Service: ck.Service},
+		{Operation: ck.Operation},
+	} {
+		if d, ok := w.Thresholds[sel]; ok {
+			return d
+		}
+	}
+	return w.Default
+}
+
+// callWatch is the Watchdog timer of a call.
+type callWatch struct {
+	waiting atomic.Bool
+
+	// mu guards the fields below. The timer fires concurrently with the
+	// call.
+	mu      sync.Mutex
+	timer   *time.Timer
+	stopped bool
+}
+
+// startWatch starts the Watchdog for the call in state.
+func (s *Service) startWatch(ctx context.Context, state *callState) {
+	if s.Watchdog == nil {
+		return
+	}
+	d := s.Watchdog.threshold(state.ck)
+	if d <= 0 {
+		return
+	}
+	w := &callWatch{}
+	var fire func()
+	fire = func() {
+		w.mu.Lock()
+		stopped := w.stopped
+		w.mu.Unlock()
+		if stopped {
+			return
+		}
+		c := &SlowCall{
+			CallContextKey: state.ck,
+			Key:            state.key,
+			Elapsed:        time.Since(state.start),
+			Threshold:      d,
+			Waiting:        w.waiting.Load(),
+		}
+		kv := []interface{}{
+			"project", c.CallContextKey.ProjectID,
+			"resource", c.CallContextKey.Service,
+			"verb", c.CallContextKey.Operation,
+			"elapsed", c.Elapsed,
+			"threshold", d,
+			"waiting", c.Waiting,
+		}
+		if c.Key != nil {
+			kv = append(kv, "key", c.Key.String())
+		}
+		s.Logger.Info("slow call", kv...)
+		if s.Watchdog.OnSlowCall != nil {
+			s.Watchdog.OnSlowCall(ctx, c)
+		}
+		// Report the call again after another threshold.
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if !w.stopped {
+			w.timer = time.AfterFunc(d, fire)
+		}
+	}
+	w.mu.Lock()
+	w.timer = time.AfterFunc(d, fire)
+	w.mu.Unlock()
+	state.watch = w
+}
+
+// stop the Watchdog timer. This is safe to call on a nil callWatch.
+func (w *callWatch) stop() {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stopped = true
+	w.timer.Stop()
+}
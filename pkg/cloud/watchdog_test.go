@@ -0,0 +1,122 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+)
+
+func TestWatchdogThreshold(t *testing.T) {
+	t.Parallel()
+
+	w := &Watchdog{
+		Default: time.Second,
+		Thresholds: map[CallSelector]time.Duration{
+			{Service: "Instances", Operation: "Insert"}: 5 * time.Second,
+			{Service: "Instances"}:                      2 * time.Second,
+			{Operation: "Delete"}:                       3 * time.Second,
+		},
+	}
+	for _, tc := range []struct {
+		service, op string
+		want        time.Duration
+	}{
+		{"Instances", "Insert", 5 * time.Second},
+		{"Instances", "Delete", 2 * time.Second},
+		{"Addresses", "Delete", 3 * time.Second},
+		{"Addresses", "Get", time.Second},
+	} {
+		ck := &CallContextKey{Service: tc.service, Operation: tc.op}
+		if got := w.threshold(ck); got != tc.want {
+			t.Errorf("threshold(%s.%s) = %v, want %v", tc.service, tc.op, got, tc.want)
+		}
+	}
+}
+
+func TestWatchdog(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/proj/global/addresses/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "slow"})
+	})
+	mux.HandleFunc("/projects/proj/global/addresses/fast", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "fast"})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := ga.NewService(ctx, option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("ga.NewService() = _, %v, want nil", err)
+	}
+	var lock sync.Mutex
+	var slow []*SlowCall
+	log := newFakeLogger()
+	c := NewGCE(&Service{
+		GA:            client,
+		ProjectRouter: &SingleProjectRouter{"proj"},
+		RateLimiter:   &NopRateLimiter{},
+		Logger:        log.logger(),
+		Watchdog: &Watchdog{
+			Default: 40 * time.Millisecond,
+			OnSlowCall: func(ctx context.Context, c *SlowCall) {
+				lock.Lock()
+				defer lock.Unlock()
+				slow = append(slow, c)
+			},
+		},
+	})
+
+	if _, err := c.GlobalAddresses().Get(ctx, meta.GlobalKey("fast")); err != nil {
+		t.Fatalf("Get(fast) = _, %v, want nil", err)
+	}
+	if _, err := c.GlobalAddresses().Get(ctx, meta.GlobalKey("slow")); err != nil {
+		t.Fatalf("Get(slow) = _, %v, want nil", err)
+	}
+	lock.Lock()
+	n := len(slow)
+	lock.Unlock()
+	// No reports after the call ended.
+	time.Sleep(100 * time.Millisecond)
+
+	lock.Lock()
+	defer lock.Unlock()
+	if n == 0 || len(slow) != n {
+		t.Fatalf("got %d slow calls, then %d after the call ended; want > 0 and no more", n, len(slow))
+	}
+	for i, c := range slow {
+		if c.Key.Name != "slow" || c.CallContextKey.Operation != "Get" || c.Threshold != 40*time.Millisecond || c.Elapsed < time.Duration(i+1)*40*time.Millisecond {
+			t.Errorf("slow[%d] = %+v, want Get of slow after %v", i, c, time.Duration(i+1)*40*time.Millisecond)
+		}
+	}
+	if got := len(log.find("slow call")); got != n {
+		t.Errorf("got %d \"slow call\" log entries, want %d", got, n)
+	}
+}